@@ -0,0 +1,109 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"flight-api/config"
+	"flight-api/internal/cache"
+	repo_airport "flight-api/internal/repository/airport"
+	repo_runway "flight-api/internal/repository/runway"
+	service_import "flight-api/internal/service/import"
+	"flight-api/pkg/database"
+	"flight-api/pkg/logger"
+	"flight-api/pkg/redis"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/sirupsen/logrus"
+)
+
+func main() {
+	logger := logger.NewLogger(logger.INFO_DEBUG_LEVEL)
+
+	// Define command-line flags for imports
+	nasrFlag := flag.String("nasr", "", "Import the FAA NASR airport data: a directory with APT_BASE.csv or APT.txt, or an APT.txt file")
+	ourAirportsFlag := flag.String("ourairports", "", "Import the OurAirports data: a directory with airports.csv, and runways.csv and countries.csv")
+	flag.Parse()
+
+	if *nasrFlag == "" && *ourAirportsFlag == "" {
+		logger.Info("Please specify the data to import: --nasr <path> and/or --ourairports <dir>")
+		logger.Info("Use --help for more information")
+		return
+	}
+
+	// Stop between facilities on interrupt; the ones stored so far stay
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Load application configuration
+	cfg, err := config.Load()
+	if err != nil {
+		logger.Fatalf("Failed to load configuration: %v", err)
+	}
+
+	logger.SetLogLevel(cfg.LogLevel)
+
+	// Connect to database
+	db, err := database.Connect(cfg.DatabaseURL)
+	if err != nil {
+		logger.Fatalw(logrus.Fields{
+			"error": err,
+		}, "Failed to connect to database")
+	}
+	defer db.Close()
+
+	// Drop imported airports from a shared redis cache; an in-memory cache
+	// lives in the server process and expires on its own
+	var appCache cache.ICache
+	if cfg.CacheBackend == cache.BackendRedis || (cfg.CacheBackend == "" && cfg.RedisEnable) {
+		redisClient, err := redis.NewRedisClient(true, cfg.RedisURL)
+		if err != nil {
+			logger.Warnw(logrus.Fields{
+				"error": err,
+			}, "Failed to connect to redis, cached airports expire on their own")
+		} else {
+			defer redisClient.Close()
+			appCache = cache.NewCache(logger, &cfg, redisClient)
+		}
+	}
+
+	importService, err := service_import.NewImportService(
+		logger,
+		&cfg,
+		db,
+		repo_airport.NewAirportRepository(logger),
+		repo_runway.NewRunwayRepository(logger),
+		cache.NewAirportCache(logger, &cfg, appCache),
+	)
+	if err != nil {
+		logger.Fatalf("Failed to create import service: %v", err)
+	}
+
+	// NASR first when both are given; the source priority decides the
+	// fields either way
+	if *nasrFlag != "" {
+		summary, err := importService.ImportNASR(ctx, *nasrFlag)
+		if err != nil {
+			logger.Fatalf("Failed to import NASR data: %v", err)
+		}
+		logSummary(logger, summary, "NASR")
+	}
+	if *ourAirportsFlag != "" {
+		summary, err := importService.ImportOurAirports(ctx, *ourAirportsFlag)
+		if err != nil {
+			logger.Fatalf("Failed to import OurAirports data: %v", err)
+		}
+		logSummary(logger, summary, "OurAirports")
+	}
+}
+
+func logSummary(logger *logger.Logger, summary service_import.ImportSummary, source string) {
+	logger.Infow(logrus.Fields{
+		"facilities": summary.Facilities,
+		"inserted":   summary.Inserted,
+		"updated":    summary.Updated,
+		"failed":     summary.Failed,
+		"runways":    summary.Runways,
+	}, "%s import finished", source)
+}
@@ -3,15 +3,24 @@ package main
 import (
 	"context"
 	"flight-api/config"
+	"flight-api/internal/cache"
 	"flight-api/internal/handler"
 	repo_airport "flight-api/internal/repository/airport"
+	repo_runway "flight-api/internal/repository/runway"
+	repo_sync_job "flight-api/internal/repository/sync_job"
+	repo_sync_run "flight-api/internal/repository/sync_run"
+	repo_sync_schedule "flight-api/internal/repository/sync_schedule"
 	service_airport "flight-api/internal/service/airport"
 	service_aviation "flight-api/internal/service/aviation"
+	service_import "flight-api/internal/service/import"
+	service_metar "flight-api/internal/service/metar"
+	service_status "flight-api/internal/service/status"
 	service_sync "flight-api/internal/service/sync"
 	service_weather "flight-api/internal/service/weather"
 	"flight-api/pkg/database"
 	"flight-api/pkg/httpserver"
 	"flight-api/pkg/logger"
+	"flight-api/pkg/redis"
 	"flight-api/util"
 	"os"
 	"os/signal"
@@ -48,22 +57,78 @@ func main() {
 	}
 	defer db.Close()
 
+	// Setup cache; the API keeps serving without one if it is unavailable
+	appCache, closeCache := newCache(logger, &cfg)
+	defer closeCache()
+
 	// Initialize validator
 	validate := util.NewValidator()
 
 	// Initialize repository
 	airportRepository := repo_airport.NewAirportRepository(logger)
+	runwayRepository := repo_runway.NewRunwayRepository(logger)
+	syncJobRepository := repo_sync_job.NewSyncJobRepository(logger)
+	syncScheduleRepository := repo_sync_schedule.NewSyncScheduleRepository(logger)
+	syncRunRepository := repo_sync_run.NewSyncRunRepository(logger)
+
+	// Initialize upstream clients; each has its own circuit breaker
+	weatherProviders, err := service_weather.NewWeatherProviders(logger, &cfg)
+	if err != nil {
+		logger.Fatalf("Failed to configure weather providers: %v", err)
+	}
+	aviationClient := service_aviation.NewAviationClient(logger, &cfg)
+	metarClient := service_metar.NewMetarClient(logger, &cfg)
+
+	// Syncs keep the fields an import source outranks the Aviation API on
+	sourcePriority, err := service_import.ParseSourcePriority(cfg.ImportSourcePriority)
+	if err != nil {
+		logger.Fatalf("Failed to configure import source priority: %v", err)
+	}
 
 	// Initialize service
-	weatherService := service_weather.NewWeatherService(logger, &cfg)
-	airportService := service_airport.NewAirportService(logger, validate, db, airportRepository, weatherService)
-	aviationService := service_aviation.NewAviationService(logger, &cfg)
-	syncService := service_sync.NewSyncService(logger, validate, db, airportRepository, aviationService)
+	cachedWeatherService := service_weather.NewCachedWeatherService(logger, &cfg, service_weather.NewWeatherService(logger, weatherProviders...), appCache)
+	weatherService := service_weather.NewCoalescingWeatherService(logger, &cfg, cachedWeatherService)
+	metarService := service_metar.NewMetarService(logger, &cfg)
+	airportCache := cache.NewAirportCache(logger, &cfg, appCache)
+	airportService := service_airport.NewAirportService(logger, &cfg, validate, db, airportRepository, weatherService, airportCache, metarService, runwayRepository)
+	aviationService := service_aviation.NewAviationService(logger, &cfg, aviationClient)
+	syncService := service_sync.NewSyncService(logger, validate, db, airportRepository, syncRunRepository, aviationService, airportCache, sourcePriority)
+	syncRunService := service_sync.NewSyncRunService(logger, db, syncRunRepository)
+	syncJobService := service_sync.NewSyncJobService(logger, &cfg, validate, db, syncService, syncJobRepository)
+	syncScheduleService, err := service_sync.NewSyncScheduleService(logger, &cfg, db, syncScheduleRepository, airportRepository, syncJobService)
+	if err != nil {
+		logger.Fatalf("Failed to configure sync schedules: %v", err)
+	}
+	upstreams := append(service_weather.ProviderClients(weatherProviders), aviationClient)
+	if cfg.MetarSourceURL != "" {
+		upstreams = append(upstreams, metarClient)
+	}
+	var caches []service_status.ICacheStats
+	if c, ok := cachedWeatherService.(service_status.ICacheStats); ok {
+		caches = append(caches, c)
+	}
+	statusService := service_status.NewStatusService(logger, db, caches, upstreams...)
+
+	// Pull METAR from the configured file-drop and HTTP sources in the background
+	metarIngester := service_metar.NewMetarIngester(logger, &cfg, metarService, service_metar.NewMetarSources(logger, &cfg, metarClient)...)
+	go metarIngester.Run(ctx)
+
+	// Sync the airports waiting in the sync_status state machine
+	syncWorker := service_sync.NewSyncWorker(logger, &cfg, db, airportRepository, aviationService, airportCache, sourcePriority)
+	go syncWorker.Run(ctx)
+
+	// Pick up the sync jobs left unfinished by a previous run
+	go syncJobService.Resume(ctx)
+
+	// Refresh every airport when a sync schedule comes due
+	go syncScheduleService.Run(ctx)
 
 	// Initialize Handlers
 	airportHandler := handler.NewAirportHandler(airportService, logger)
-	syncHandler := handler.NewSyncHandler(syncService, logger)
+	syncHandler := handler.NewSyncHandler(syncService, syncJobService, syncScheduleService, syncRunService, logger)
 	weatherHandler := handler.NewWeatherHandler(weatherService, logger)
+	statusHandler := handler.NewStatusHandler(statusService, logger)
+	metarHandler := handler.NewMetarHandler(metarService, logger)
 
 	// Setup router
 	logger.Info("Setup Router ...")
@@ -71,6 +136,8 @@ func main() {
 		airportHandler,
 		syncHandler,
 		weatherHandler,
+		statusHandler,
+		metarHandler,
 	)
 
 	// Start HTTP server
@@ -98,5 +165,45 @@ func main() {
 		logger.Errorw(logrus.Fields{"error": err}, "Error during server shutdown")
 	}
 
+	// Running sync jobs go back to the queue and resume on the next start
+	if err := syncJobService.Shutdown(shutdownCtx); err != nil {
+		logger.Errorw(logrus.Fields{"error": err}, "Error while stopping sync jobs")
+	}
+
 	logger.Info("Server stopped gracefully!")
 }
+
+// newCache builds the cache selected by CACHE_BACKEND. When it is unset the
+// Redis cache is used if REDIS_ENABLE is true. A nil cache disables caching.
+func newCache(log *logger.Logger, cfg *config.Config) (cache.ICache, func()) {
+	backend := cfg.CacheBackend
+	if backend == "" {
+		backend = cache.BackendNone
+		if cfg.RedisEnable {
+			backend = cache.BackendRedis
+		}
+	}
+
+	switch backend {
+	case cache.BackendMemory:
+		log.Infow(logrus.Fields{"max_entries": cfg.CacheMaxEntries}, "Using in-memory cache")
+		return cache.NewMemoryCache(log, cfg), func() {}
+	case cache.BackendRedis:
+		log.Info("Connecting to redis ...")
+		redisClient, err := redis.NewRedisClient(true, cfg.RedisURL)
+		if err != nil {
+			log.Warnw(logrus.Fields{
+				"error": err,
+			}, "Failed to connect to redis, caching disabled")
+			return nil, func() {}
+		}
+		log.Info("Succesfully connected to redis!")
+		return cache.NewCache(log, cfg, redisClient), func() { _ = redisClient.Close() }
+	case cache.BackendNone:
+		log.Info("Caching disabled")
+		return nil, func() {}
+	default:
+		log.Warnw(logrus.Fields{"backend": backend}, "Unknown cache backend, caching disabled")
+		return nil, func() {}
+	}
+}
@@ -13,17 +13,59 @@ import (
 )
 
 type Config struct {
-	ServiceName     string        `mapstructure:"SERVICE_NAME"`
-	HTTPPort        string        `mapstructure:"HTTP_PORT"`
-	LogLevel        string        `mapstructure:"LOG_LEVEL"`
-	AppEnv          string        `mapstructure:"APP_ENV"`
-	DatabaseURL     string        `mapstructure:"DATABASE_URL"`
-	RedisURL        string        `mapstructure:"REDIS_URL"`
-	RedisEnable     bool          `mapstructure:"REDIS_ENABLE"`
-	AviationURL     string        `mapstructure:"AVIATION_API_URL"`
-	WeatherURL      string        `mapstructure:"WEATHER_API_URL"`
-	WeatherAPIKey   string        `mapstructure:"WEATHER_API_KEY"`
-	ShutdownTimeout time.Duration `mapstructure:"SHUTDOWN_TIMEOUT"`
+	ServiceName           string        `mapstructure:"SERVICE_NAME"`
+	HTTPPort              string        `mapstructure:"HTTP_PORT"`
+	LogLevel              string        `mapstructure:"LOG_LEVEL"`
+	AppEnv                string        `mapstructure:"APP_ENV"`
+	DatabaseURL           string        `mapstructure:"DATABASE_URL"`
+	RedisURL              string        `mapstructure:"REDIS_URL"`
+	RedisEnable           bool          `mapstructure:"REDIS_ENABLE"`
+	CacheBackend          string        `mapstructure:"CACHE_BACKEND"`
+	CacheMaxEntries       int           `mapstructure:"CACHE_MAX_ENTRIES"`
+	CacheNegativeTTL      time.Duration `mapstructure:"CACHE_NEGATIVE_TTL"`
+	AirportCacheTTL       time.Duration `mapstructure:"AIRPORT_CACHE_TTL"`
+	WeatherFanoutWorkers  int           `mapstructure:"WEATHER_FANOUT_WORKERS"`
+	WeatherFanoutTimeout  time.Duration `mapstructure:"WEATHER_FANOUT_TIMEOUT"`
+	WeatherCategoryScan   int           `mapstructure:"WEATHER_CATEGORY_SCAN_LIMIT"`
+	WeatherFreshTTL       time.Duration `mapstructure:"WEATHER_FRESH_TTL"`
+	WeatherStaleGrace     time.Duration `mapstructure:"WEATHER_STALE_GRACE"`
+	WeatherStaleIfError   time.Duration `mapstructure:"WEATHER_STALE_IF_ERROR"`
+	WeatherCacheTTL       time.Duration `mapstructure:"WEATHER_CACHE_TTL"`
+	WeatherCacheMinTTL    time.Duration `mapstructure:"WEATHER_CACHE_MIN_TTL"`
+	ForecastCacheTTL      time.Duration `mapstructure:"WEATHER_FORECAST_CACHE_TTL"`
+	AviationURL           string        `mapstructure:"AVIATION_API_URL"`
+	WeatherURL            string        `mapstructure:"WEATHER_API_URL"`
+	WeatherAPIKey         string        `mapstructure:"WEATHER_API_KEY"`
+	WeatherProviders      string        `mapstructure:"WEATHER_PROVIDERS"`
+	OpenMeteoURL          string        `mapstructure:"OPEN_METEO_URL"`
+	OpenMeteoGeocodingURL string        `mapstructure:"OPEN_METEO_GEOCODING_URL"`
+	AviationTimeout       time.Duration `mapstructure:"AVIATION_API_TIMEOUT"`
+	WeatherTimeout        time.Duration `mapstructure:"WEATHER_API_TIMEOUT"`
+	HTTPMaxRetries        int           `mapstructure:"HTTP_CLIENT_MAX_RETRIES"`
+	HTTPRetryBase         time.Duration `mapstructure:"HTTP_CLIENT_RETRY_BASE_DELAY"`
+	HTTPRetryMax          time.Duration `mapstructure:"HTTP_CLIENT_RETRY_MAX_DELAY"`
+	BreakerThreshold      int           `mapstructure:"UPSTREAM_BREAKER_THRESHOLD"`
+	BreakerOpenTimeout    time.Duration `mapstructure:"UPSTREAM_BREAKER_OPEN_TIMEOUT"`
+	BreakerHalfOpenProbes int           `mapstructure:"UPSTREAM_BREAKER_HALF_OPEN_PROBES"`
+	MetarDropDir          string        `mapstructure:"METAR_DROP_DIR"`
+	MetarSourceURL        string        `mapstructure:"METAR_SOURCE_URL"`
+	MetarPollInterval     time.Duration `mapstructure:"METAR_POLL_INTERVAL"`
+	MetarMaxAge           time.Duration `mapstructure:"METAR_MAX_AGE"`
+	DensityAltWarnFt      int           `mapstructure:"DENSITY_ALTITUDE_WARN_FT"`
+	DensityAltExcessFt    int           `mapstructure:"DENSITY_ALTITUDE_EXCESS_FT"`
+	SyncJobStaleAfter     time.Duration `mapstructure:"SYNC_JOB_STALE_AFTER"`
+	SyncWorkerInterval    time.Duration `mapstructure:"SYNC_WORKER_INTERVAL"`
+	SyncWorkerBatchSize   int           `mapstructure:"SYNC_WORKER_BATCH_SIZE"`
+	SyncWorkerMaxAttempts int           `mapstructure:"SYNC_WORKER_MAX_ATTEMPTS"`
+	SyncWorkerRetryDelay  time.Duration `mapstructure:"SYNC_WORKER_RETRY_DELAY"`
+	SyncWorkerStaleAfter  time.Duration `mapstructure:"SYNC_WORKER_STALE_AFTER"`
+	AviationBatchSize     int           `mapstructure:"AVIATION_API_BATCH_SIZE"`
+	AviationConcurrency   int           `mapstructure:"AVIATION_API_CONCURRENCY"`
+	AviationRateLimit     float64       `mapstructure:"AVIATION_API_RATE_LIMIT"`
+	AviationRateBurst     int           `mapstructure:"AVIATION_API_RATE_BURST"`
+	SyncSchedules         string        `mapstructure:"SYNC_SCHEDULES"`
+	ImportSourcePriority  string        `mapstructure:"IMPORT_SOURCE_PRIORITY"`
+	ShutdownTimeout       time.Duration `mapstructure:"SHUTDOWN_TIMEOUT"`
 }
 
 func Load() (config Config, err error) {
@@ -68,9 +110,52 @@ func Load() (config Config, err error) {
 	viper.SetDefault("DATABASE_URL", "")
 	viper.SetDefault("REDIS_URL", "")
 	viper.SetDefault("REDIS_ENABLE", false)
+	viper.SetDefault("CACHE_BACKEND", "")
+	viper.SetDefault("CACHE_MAX_ENTRIES", 10000)
+	viper.SetDefault("CACHE_NEGATIVE_TTL", 0)
+	viper.SetDefault("AIRPORT_CACHE_TTL", 5*time.Minute)
+	viper.SetDefault("WEATHER_FANOUT_WORKERS", 8)
+	viper.SetDefault("WEATHER_FANOUT_TIMEOUT", 10*time.Second)
+	viper.SetDefault("WEATHER_CATEGORY_SCAN_LIMIT", 500)
+	viper.SetDefault("WEATHER_FRESH_TTL", time.Minute)
+	viper.SetDefault("WEATHER_STALE_GRACE", 5*time.Minute)
+	viper.SetDefault("WEATHER_STALE_IF_ERROR", time.Hour)
+	viper.SetDefault("WEATHER_CACHE_TTL", 15*time.Minute)
+	viper.SetDefault("WEATHER_CACHE_MIN_TTL", 30*time.Second)
+	viper.SetDefault("WEATHER_FORECAST_CACHE_TTL", time.Hour)
 	viper.SetDefault("AVIATION_API_URL", "")
 	viper.SetDefault("WEATHER_API_URL", "")
 	viper.SetDefault("WEATHER_API_KEY", "")
+	viper.SetDefault("WEATHER_PROVIDERS", "weatherapi")
+	viper.SetDefault("OPEN_METEO_URL", "https://api.open-meteo.com")
+	viper.SetDefault("OPEN_METEO_GEOCODING_URL", "https://geocoding-api.open-meteo.com")
+	viper.SetDefault("AVIATION_API_TIMEOUT", 30*time.Second)
+	viper.SetDefault("WEATHER_API_TIMEOUT", 10*time.Second)
+	viper.SetDefault("HTTP_CLIENT_MAX_RETRIES", 2)
+	viper.SetDefault("HTTP_CLIENT_RETRY_BASE_DELAY", 200*time.Millisecond)
+	viper.SetDefault("HTTP_CLIENT_RETRY_MAX_DELAY", 5*time.Second)
+	viper.SetDefault("UPSTREAM_BREAKER_THRESHOLD", 5)
+	viper.SetDefault("UPSTREAM_BREAKER_OPEN_TIMEOUT", 30*time.Second)
+	viper.SetDefault("UPSTREAM_BREAKER_HALF_OPEN_PROBES", 1)
+	viper.SetDefault("METAR_DROP_DIR", "")
+	viper.SetDefault("METAR_SOURCE_URL", "")
+	viper.SetDefault("METAR_POLL_INTERVAL", 5*time.Minute)
+	viper.SetDefault("METAR_MAX_AGE", 3*time.Hour)
+	viper.SetDefault("DENSITY_ALTITUDE_WARN_FT", 5000)
+	viper.SetDefault("DENSITY_ALTITUDE_EXCESS_FT", 2000)
+	viper.SetDefault("SYNC_JOB_STALE_AFTER", 5*time.Minute)
+	viper.SetDefault("SYNC_WORKER_INTERVAL", time.Minute)
+	viper.SetDefault("SYNC_WORKER_BATCH_SIZE", 25)
+	viper.SetDefault("SYNC_WORKER_MAX_ATTEMPTS", 3)
+	viper.SetDefault("SYNC_WORKER_RETRY_DELAY", 5*time.Minute)
+	viper.SetDefault("SYNC_WORKER_STALE_AFTER", 10*time.Minute)
+	viper.SetDefault("AVIATION_API_BATCH_SIZE", 25)
+	viper.SetDefault("AVIATION_API_CONCURRENCY", 4)
+	viper.SetDefault("AVIATION_API_RATE_LIMIT", 5)
+	viper.SetDefault("AVIATION_API_RATE_BURST", 5)
+	viper.SetDefault("SYNC_SCHEDULES", "airac=@airac")
+	// NASR reuses the FAA identifier as the IATA code; OurAirports has the real one
+	viper.SetDefault("IMPORT_SOURCE_PRIORITY", "nasr,aviationapi,ourairports;iata_id=ourairports,nasr,aviationapi")
 	viper.SetDefault("SHUTDOWN_TIMEOUT", 5*time.Second)
 
 	err = viper.Unmarshal(&config)
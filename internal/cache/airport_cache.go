@@ -0,0 +1,110 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"flight-api/config"
+	"flight-api/internal/model"
+	"flight-api/pkg/logger"
+	"time"
+)
+
+const defaultAirportCacheTTL = 5 * time.Minute
+
+// AirportCache keeps committed airports by ID and ICAO code so that hot
+// single-airport reads skip the database. Services must only store rows read
+// from, or invalidate rows written by, a committed transaction. All methods
+// are safe to call on a nil *AirportCache, which disables caching.
+type AirportCache struct {
+	logger *logger.Logger
+	cache  ICache
+	ttl    time.Duration
+}
+
+func NewAirportCache(logger *logger.Logger, cfg *config.Config, c ICache) *AirportCache {
+	if c == nil {
+		return nil
+	}
+
+	ttl := cfg.AirportCacheTTL
+	if ttl <= 0 {
+		ttl = defaultAirportCacheTTL
+	}
+
+	return &AirportCache{
+		logger: logger,
+		cache:  c,
+		ttl:    ttl,
+	}
+}
+
+func (a *AirportCache) FindByID(ctx context.Context, id string) (model.Airport, bool) {
+	if a == nil {
+		return model.Airport{}, false
+	}
+
+	airport, err := a.cache.FindAirportByID(ctx, id)
+	return a.result(airport, err)
+}
+
+func (a *AirportCache) FindByICAOID(ctx context.Context, icaoID string) (model.Airport, bool) {
+	if a == nil {
+		return model.Airport{}, false
+	}
+
+	airport, err := a.cache.FindAirportByICAOID(ctx, icaoID)
+	return a.result(airport, err)
+}
+
+// Store caches the airport under both its ID and its ICAO code.
+func (a *AirportCache) Store(ctx context.Context, airport model.Airport) {
+	if a == nil {
+		return
+	}
+
+	if airport.ID != nil {
+		if err := a.cache.CacheAirportByID(ctx, airport.ID.String(), &airport, a.ttl); err != nil {
+			a.logger.Warnf("[AirportCache] Failed to cache airport %s: %v", airport.ID, err)
+		}
+	}
+	if airport.ICAOID != nil {
+		if err := a.cache.CacheAirport(ctx, *airport.ICAOID, &airport, a.ttl); err != nil {
+			a.logger.Warnf("[AirportCache] Failed to cache airport %s: %v", *airport.ICAOID, err)
+		}
+	}
+}
+
+// Invalidate drops every key the given airports may be cached under.
+func (a *AirportCache) Invalidate(ctx context.Context, airports ...model.Airport) {
+	if a == nil {
+		return
+	}
+
+	for _, airport := range airports {
+		var id, icaoID string
+		if airport.ID != nil {
+			id = airport.ID.String()
+		}
+		if airport.ICAOID != nil {
+			icaoID = *airport.ICAOID
+		}
+
+		if err := a.cache.DeleteAirport(ctx, id, icaoID); err != nil {
+			a.logger.Warnf("[AirportCache] Failed to invalidate airport %s/%s: %v", id, icaoID, err)
+		}
+	}
+}
+
+func (a *AirportCache) result(airport *model.Airport, err error) (model.Airport, bool) {
+	if err != nil {
+		if !errors.Is(err, ErrCacheMiss) {
+			a.logger.Warnf("[AirportCache] Airport cache unavailable, bypassing: %v", err)
+		}
+		return model.Airport{}, false
+	}
+	if airport == nil {
+		return model.Airport{}, false
+	}
+
+	return *airport, true
+}
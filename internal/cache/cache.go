@@ -7,9 +7,30 @@ import (
 	"time"
 )
 
+// Supported values of CACHE_BACKEND.
+const (
+	BackendRedis  = "redis"
+	BackendMemory = "memory"
+	BackendNone   = "none"
+)
+
+// Stats is a snapshot of the hit, miss and error counters of a cache
+// consumer.
+type Stats struct {
+	Hits   uint64 `json:"hits"`
+	Misses uint64 `json:"misses"`
+	Errors uint64 `json:"errors"`
+}
+
 type ICache interface {
+	FindAirportByID(ctx context.Context, id string) (*model.Airport, error)
+	CacheAirportByID(ctx context.Context, id string, data *model.Airport, expiration time.Duration) error
 	FindAirportByICAOID(ctx context.Context, icaoID string) (*model.Airport, error)
 	CacheAirport(ctx context.Context, icaoID string, data *model.Airport, expiration time.Duration) error
+	DeleteAirport(ctx context.Context, id string, icaoID string) error
 	FindWeatherByLocation(ctx context.Context, location string) (*weather_dto.WeatherDto, error)
 	CacheWeather(ctx context.Context, location string, data *weather_dto.WeatherDto, expiration time.Duration) error
+	CacheWeatherNotFound(ctx context.Context, location string, expiration time.Duration) error
+	FindForecast(ctx context.Context, location string, days int) (*weather_dto.ForecastDto, error)
+	CacheForecast(ctx context.Context, location string, days int, data *weather_dto.ForecastDto, expiration time.Duration) error
 }
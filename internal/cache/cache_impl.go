@@ -2,17 +2,30 @@ package cache
 
 import (
 	"context"
+	"errors"
 	"flight-api/config"
 	weather_dto "flight-api/internal/dto/weather"
 	"flight-api/internal/model"
 	"flight-api/pkg/logger"
 	"flight-api/util"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/redis/go-redis/v9"
 )
 
+var (
+	// ErrCacheMiss is returned when the requested key is not in the cache.
+	ErrCacheMiss = errors.New("cache miss")
+	// ErrCachedNotFound is returned when the cache remembers that the
+	// upstream had no data for the key.
+	ErrCachedNotFound = errors.New("cached not found")
+)
+
+// notFoundMarker is stored in place of a payload for negative entries.
+const notFoundMarker = "__not_found__"
+
 type Cache struct {
 	logger      *logger.Logger
 	cfg         *config.Config
@@ -29,11 +42,75 @@ func NewCache(logger *logger.Logger, cfg *config.Config, redisClient *redis.Clie
 	}
 }
 
+// NormalizeKey lowercases a lookup value and collapses whitespace so that
+// "Jakarta", " jakarta " and "JAKARTA" share one cache entry.
+func NormalizeKey(value string) string {
+	return strings.Join(strings.Fields(strings.ToLower(value)), "_")
+}
+
+func airportKey(prefix, icaoID string) string {
+	return prefix + ":airport:" + NormalizeKey(icaoID)
+}
+
+func airportIDKey(prefix, id string) string {
+	return prefix + ":airport:id:" + NormalizeKey(id)
+}
+
+// airportKeys returns the keys an airport may be cached under; empty
+// identifiers are skipped.
+func airportKeys(prefix, id, icaoID string) []string {
+	keys := make([]string, 0, 2)
+	if NormalizeKey(id) != "" {
+		keys = append(keys, airportIDKey(prefix, id))
+	}
+	if NormalizeKey(icaoID) != "" {
+		keys = append(keys, airportKey(prefix, icaoID))
+	}
+	return keys
+}
+
+func weatherKey(prefix, location string) string {
+	return prefix + ":weather:" + NormalizeKey(location)
+}
+
+// forecastKey includes the number of days, since a longer forecast is a
+// different upstream answer.
+func forecastKey(prefix, location string, days int) string {
+	return prefix + ":forecast:" + strconv.Itoa(days) + ":" + NormalizeKey(location)
+}
+
+func (c *Cache) FindAirportByID(ctx context.Context, id string) (*model.Airport, error) {
+	return c.findAirport(ctx, airportIDKey(c.defaultKey, id))
+}
+
+func (c *Cache) CacheAirportByID(ctx context.Context, id string, data *model.Airport, expiration time.Duration) error {
+	return c.cacheAirport(ctx, airportIDKey(c.defaultKey, id), data, expiration)
+}
+
 func (c *Cache) FindAirportByICAOID(ctx context.Context, icaoID string) (*model.Airport, error) {
+	return c.findAirport(ctx, airportKey(c.defaultKey, icaoID))
+}
+
+func (c *Cache) CacheAirport(ctx context.Context, icaoID string, data *model.Airport, expiration time.Duration) error {
+	return c.cacheAirport(ctx, airportKey(c.defaultKey, icaoID), data, expiration)
+}
+
+func (c *Cache) DeleteAirport(ctx context.Context, id string, icaoID string) error {
+	keys := airportKeys(c.defaultKey, id, icaoID)
+	if len(keys) == 0 {
+		return nil
+	}
+
+	return c.redisClient.Del(ctx, keys...).Err()
+}
+
+func (c *Cache) findAirport(ctx context.Context, key string) (*model.Airport, error) {
 	var data model.Airport
 
-	key := c.defaultKey + ":airport:" + icaoID
 	cacheData, err := c.redisClient.Get(ctx, key).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil, ErrCacheMiss
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -46,12 +123,10 @@ func (c *Cache) FindAirportByICAOID(ctx context.Context, icaoID string) (*model.
 	return &data, nil
 }
 
-func (c *Cache) CacheAirport(ctx context.Context, icaoID string, data *model.Airport, expiration time.Duration) error {
-	key := c.defaultKey + ":airport:" + strings.ReplaceAll(strings.ToLower(icaoID), " ", "_")
-
+func (c *Cache) cacheAirport(ctx context.Context, key string, data *model.Airport, expiration time.Duration) error {
 	jsonData, err := util.ToJSON(data)
 	if err != nil {
-		c.logger.Errorf("[CacheWeather] Failed to marshal weather data to JSON: %v", err)
+		c.logger.Errorf("[CacheAirport] Failed to marshal airport data to JSON: %v", err)
 		return err
 	}
 
@@ -61,16 +136,20 @@ func (c *Cache) CacheAirport(ctx context.Context, icaoID string, data *model.Air
 func (c *Cache) FindWeatherByLocation(ctx context.Context, location string) (*weather_dto.WeatherDto, error) {
 	var data weather_dto.WeatherDto
 
-	key := c.defaultKey + ":weather:" + location
-	cacheData, err := c.redisClient.Get(ctx, key).Result()
-
+	cacheData, err := c.redisClient.Get(ctx, weatherKey(c.defaultKey, location)).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil, ErrCacheMiss
+	}
 	if err != nil {
 		return nil, err
 	}
 
-	c.logger.Debug("[GetWeatherCondition] Found cached weather data in Redis.")
-	err = util.ParseJSON([]byte(cacheData), &data)
+	if cacheData == notFoundMarker {
+		return nil, ErrCachedNotFound
+	}
 
+	c.logger.Debug("[FindWeatherByLocation] Found cached weather data in Redis.")
+	err = util.ParseJSON([]byte(cacheData), &data)
 	if err != nil {
 		return nil, err
 	}
@@ -79,13 +158,45 @@ func (c *Cache) FindWeatherByLocation(ctx context.Context, location string) (*we
 }
 
 func (c *Cache) CacheWeather(ctx context.Context, location string, data *weather_dto.WeatherDto, expiration time.Duration) error {
-	key := c.defaultKey + ":weather:" + location
-
 	jsonData, err := util.ToJSON(data)
 	if err != nil {
 		c.logger.Errorf("[CacheWeather] Failed to marshal weather data to JSON: %v", err)
 		return err
 	}
 
-	return c.redisClient.Set(ctx, key, jsonData, expiration).Err()
+	return c.redisClient.Set(ctx, weatherKey(c.defaultKey, location), jsonData, expiration).Err()
+}
+
+func (c *Cache) CacheWeatherNotFound(ctx context.Context, location string, expiration time.Duration) error {
+	return c.redisClient.Set(ctx, weatherKey(c.defaultKey, location), notFoundMarker, expiration).Err()
+}
+
+func (c *Cache) FindForecast(ctx context.Context, location string, days int) (*weather_dto.ForecastDto, error) {
+	var data weather_dto.ForecastDto
+
+	cacheData, err := c.redisClient.Get(ctx, forecastKey(c.defaultKey, location, days)).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil, ErrCacheMiss
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	c.logger.Debug("[FindForecast] Found cached forecast data in Redis.")
+	err = util.ParseJSON([]byte(cacheData), &data)
+	if err != nil {
+		return nil, err
+	}
+
+	return &data, nil
+}
+
+func (c *Cache) CacheForecast(ctx context.Context, location string, days int, data *weather_dto.ForecastDto, expiration time.Duration) error {
+	jsonData, err := util.ToJSON(data)
+	if err != nil {
+		c.logger.Errorf("[CacheForecast] Failed to marshal forecast data to JSON: %v", err)
+		return err
+	}
+
+	return c.redisClient.Set(ctx, forecastKey(c.defaultKey, location, days), jsonData, expiration).Err()
 }
@@ -13,18 +13,68 @@ type CacheMock struct {
 	Mock mock.Mock
 }
 
+func (c *CacheMock) FindAirportByID(ctx context.Context, id string) (*model.Airport, error) {
+	args := c.Mock.Called(ctx, id)
+	var out *model.Airport
+	if v, ok := args.Get(0).(*model.Airport); ok {
+		out = v
+	}
+	return out, args.Error(1)
+}
+
+func (c *CacheMock) CacheAirportByID(ctx context.Context, id string, data *model.Airport, expiration time.Duration) error {
+	args := c.Mock.Called(ctx, id, data, expiration)
+	return args.Error(0)
+}
+
+func (c *CacheMock) DeleteAirport(ctx context.Context, id string, icaoID string) error {
+	args := c.Mock.Called(ctx, id, icaoID)
+	return args.Error(0)
+}
+
 func (c *CacheMock) FindAirportByICAOID(ctx context.Context, icaoID string) (*model.Airport, error) {
-	return &model.Airport{}, nil
+	args := c.Mock.Called(ctx, icaoID)
+	var out *model.Airport
+	if v, ok := args.Get(0).(*model.Airport); ok {
+		out = v
+	}
+	return out, args.Error(1)
 }
 
 func (c *CacheMock) CacheAirport(ctx context.Context, icaoID string, data *model.Airport, expiration time.Duration) error {
-	return nil
+	args := c.Mock.Called(ctx, icaoID, data, expiration)
+	return args.Error(0)
 }
 
 func (c *CacheMock) FindWeatherByLocation(ctx context.Context, location string) (*weather_dto.WeatherDto, error) {
-	return nil, nil
+	args := c.Mock.Called(ctx, location)
+	var out *weather_dto.WeatherDto
+	if v, ok := args.Get(0).(*weather_dto.WeatherDto); ok {
+		out = v
+	}
+	return out, args.Error(1)
 }
 
 func (c *CacheMock) CacheWeather(ctx context.Context, location string, data *weather_dto.WeatherDto, expiration time.Duration) error {
-	return nil
+	args := c.Mock.Called(ctx, location, data, expiration)
+	return args.Error(0)
+}
+
+func (c *CacheMock) CacheWeatherNotFound(ctx context.Context, location string, expiration time.Duration) error {
+	args := c.Mock.Called(ctx, location, expiration)
+	return args.Error(0)
+}
+
+func (c *CacheMock) FindForecast(ctx context.Context, location string, days int) (*weather_dto.ForecastDto, error) {
+	args := c.Mock.Called(ctx, location, days)
+	var out *weather_dto.ForecastDto
+	if v, ok := args.Get(0).(*weather_dto.ForecastDto); ok {
+		out = v
+	}
+	return out, args.Error(1)
+}
+
+func (c *CacheMock) CacheForecast(ctx context.Context, location string, days int, data *weather_dto.ForecastDto, expiration time.Duration) error {
+	args := c.Mock.Called(ctx, location, days, data, expiration)
+	return args.Error(0)
 }
@@ -0,0 +1,212 @@
+package cache_test
+
+import (
+	"context"
+	"flight-api/config"
+	"flight-api/internal/cache"
+	weather_dto "flight-api/internal/dto/weather"
+	"flight-api/internal/model"
+	"flight-api/pkg/logger"
+	"flight-api/util"
+	"fmt"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var log = logger.NewLogger(logger.DEBUG_LEVEL)
+
+// runCacheSuite checks the behaviour every ICache implementation must share.
+// newCache must return an empty cache whose keys do not collide with other runs.
+func runCacheSuite(t *testing.T, newCache func(t *testing.T) cache.ICache) {
+	ctx := context.Background()
+
+	t.Run("miss on unknown key", func(t *testing.T) {
+		c := newCache(t)
+
+		_, err := c.FindWeatherByLocation(ctx, "jakarta")
+		assert.ErrorIs(t, err, cache.ErrCacheMiss)
+
+		_, err = c.FindAirportByICAOID(ctx, "WIII")
+		assert.ErrorIs(t, err, cache.ErrCacheMiss)
+	})
+
+	t.Run("weather round trip with normalized key", func(t *testing.T) {
+		c := newCache(t)
+		data := &weather_dto.WeatherDto{
+			Object:  util.Ptr("weather"),
+			Current: &weather_dto.CurrentWeatherDto{TempC: util.Ptr(25.2)},
+		}
+
+		require.NoError(t, c.CacheWeather(ctx, " Jakarta ", data, time.Minute))
+
+		got, err := c.FindWeatherByLocation(ctx, "JAKARTA")
+		require.NoError(t, err)
+		assert.Equal(t, data, got)
+	})
+
+	t.Run("forecast round trip keyed by days", func(t *testing.T) {
+		c := newCache(t)
+		data := &weather_dto.ForecastDto{
+			Object: util.Ptr("forecast"),
+			Forecast: &weather_dto.ForecastPeriodsDto{ForecastDay: []weather_dto.ForecastDayDto{
+				{Date: util.Ptr("2025-10-06"), Hour: []weather_dto.HourlyWeatherDto{{TempC: util.Ptr(24.1)}}},
+			}},
+		}
+
+		require.NoError(t, c.CacheForecast(ctx, "Jakarta", 3, data, time.Minute))
+
+		got, err := c.FindForecast(ctx, " jakarta", 3)
+		require.NoError(t, err)
+		assert.Equal(t, data, got)
+
+		_, err = c.FindForecast(ctx, "jakarta", 5)
+		assert.ErrorIs(t, err, cache.ErrCacheMiss)
+		_, err = c.FindWeatherByLocation(ctx, "jakarta")
+		assert.ErrorIs(t, err, cache.ErrCacheMiss)
+	})
+
+	t.Run("airport round trip with normalized key", func(t *testing.T) {
+		c := newCache(t)
+		data := &model.Airport{ID: util.Ptr(uuid.New()), ICAOID: util.Ptr("WIII")}
+
+		require.NoError(t, c.CacheAirport(ctx, "WIII", data, time.Minute))
+
+		got, err := c.FindAirportByICAOID(ctx, "wiii")
+		require.NoError(t, err)
+		assert.Equal(t, data.ID, got.ID)
+		assert.Equal(t, data.ICAOID, got.ICAOID)
+	})
+
+	t.Run("airport by id and invalidation", func(t *testing.T) {
+		c := newCache(t)
+		id := uuid.New()
+		data := &model.Airport{ID: &id, ICAOID: util.Ptr("KJFK")}
+
+		require.NoError(t, c.CacheAirportByID(ctx, id.String(), data, time.Minute))
+		require.NoError(t, c.CacheAirport(ctx, "KJFK", data, time.Minute))
+
+		got, err := c.FindAirportByID(ctx, id.String())
+		require.NoError(t, err)
+		assert.Equal(t, data.ICAOID, got.ICAOID)
+
+		require.NoError(t, c.DeleteAirport(ctx, id.String(), "kjfk"))
+
+		_, err = c.FindAirportByID(ctx, id.String())
+		assert.ErrorIs(t, err, cache.ErrCacheMiss)
+		_, err = c.FindAirportByICAOID(ctx, "KJFK")
+		assert.ErrorIs(t, err, cache.ErrCacheMiss)
+	})
+
+	t.Run("returned values are copies", func(t *testing.T) {
+		c := newCache(t)
+		data := &weather_dto.WeatherDto{Object: util.Ptr("weather")}
+		require.NoError(t, c.CacheWeather(ctx, "tokyo", data, time.Minute))
+
+		got, err := c.FindWeatherByLocation(ctx, "tokyo")
+		require.NoError(t, err)
+		*got.Object = "mutated"
+
+		again, err := c.FindWeatherByLocation(ctx, "tokyo")
+		require.NoError(t, err)
+		assert.Equal(t, "weather", *again.Object)
+	})
+
+	t.Run("entries expire", func(t *testing.T) {
+		c := newCache(t)
+		data := &weather_dto.WeatherDto{Object: util.Ptr("weather")}
+		require.NoError(t, c.CacheWeather(ctx, "jakarta", data, 50*time.Millisecond))
+
+		time.Sleep(100 * time.Millisecond)
+
+		_, err := c.FindWeatherByLocation(ctx, "jakarta")
+		assert.ErrorIs(t, err, cache.ErrCacheMiss)
+	})
+
+	t.Run("negative entries", func(t *testing.T) {
+		c := newCache(t)
+		require.NoError(t, c.CacheWeatherNotFound(ctx, "atlantis", time.Minute))
+
+		_, err := c.FindWeatherByLocation(ctx, "Atlantis")
+		assert.ErrorIs(t, err, cache.ErrCachedNotFound)
+
+		data := &weather_dto.WeatherDto{Object: util.Ptr("weather")}
+		require.NoError(t, c.CacheWeather(ctx, "atlantis", data, time.Minute))
+
+		got, err := c.FindWeatherByLocation(ctx, "atlantis")
+		require.NoError(t, err)
+		assert.Equal(t, data, got)
+	})
+
+	t.Run("concurrent access", func(t *testing.T) {
+		c := newCache(t)
+		data := &weather_dto.WeatherDto{Object: util.Ptr("weather")}
+
+		var wg sync.WaitGroup
+		for i := 0; i < 20; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				loc := fmt.Sprintf("city-%d", i%5)
+				assert.NoError(t, c.CacheWeather(ctx, loc, data, time.Minute))
+				_, err := c.FindWeatherByLocation(ctx, loc)
+				assert.NoError(t, err)
+			}(i)
+		}
+		wg.Wait()
+	})
+}
+
+func TestMemoryCache_Suite(t *testing.T) {
+	runCacheSuite(t, func(t *testing.T) cache.ICache {
+		return cache.NewMemoryCache(log, &config.Config{ServiceName: "flight-api-test"})
+	})
+}
+
+// TestRedisCache_Suite runs against a real server when REDIS_TEST_URL is set.
+func TestRedisCache_Suite(t *testing.T) {
+	redisURL := os.Getenv("REDIS_TEST_URL")
+	if redisURL == "" {
+		t.Skip("REDIS_TEST_URL not set")
+	}
+
+	opts, err := redis.ParseURL(redisURL)
+	require.NoError(t, err)
+	client := redis.NewClient(opts)
+	defer client.Close()
+	require.NoError(t, client.Ping(context.Background()).Err())
+
+	runCacheSuite(t, func(t *testing.T) cache.ICache {
+		// A unique prefix keeps runs isolated without flushing the database.
+		return cache.NewCache(log, &config.Config{ServiceName: "flight-api-test-" + uuid.NewString()}, client)
+	})
+}
+
+func TestMemoryCache_EvictsLeastRecentlyUsed(t *testing.T) {
+	ctx := context.Background()
+	c := cache.NewMemoryCache(log, &config.Config{CacheMaxEntries: 2})
+	data := &weather_dto.WeatherDto{Object: util.Ptr("weather")}
+
+	require.NoError(t, c.CacheWeather(ctx, "a", data, time.Minute))
+	require.NoError(t, c.CacheWeather(ctx, "b", data, time.Minute))
+
+	// Touch "a" so "b" becomes the least recently used entry.
+	_, err := c.FindWeatherByLocation(ctx, "a")
+	require.NoError(t, err)
+
+	require.NoError(t, c.CacheWeather(ctx, "c", data, time.Minute))
+
+	assert.Equal(t, 2, c.(*cache.MemoryCache).Len())
+	_, err = c.FindWeatherByLocation(ctx, "b")
+	assert.ErrorIs(t, err, cache.ErrCacheMiss)
+	_, err = c.FindWeatherByLocation(ctx, "a")
+	assert.NoError(t, err)
+	_, err = c.FindWeatherByLocation(ctx, "c")
+	assert.NoError(t, err)
+}
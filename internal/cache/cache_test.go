@@ -0,0 +1,22 @@
+package cache_test
+
+import (
+	"flight-api/internal/cache"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeKey(t *testing.T) {
+	tests := map[string]string{
+		"Jakarta":          "jakarta",
+		"  JAKARTA ":       "jakarta",
+		"New   York  City": "new_york_city",
+		"WIII":             "wiii",
+		"":                 "",
+	}
+
+	for input, expected := range tests {
+		assert.Equal(t, expected, cache.NormalizeKey(input), input)
+	}
+}
@@ -0,0 +1,234 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"flight-api/config"
+	weather_dto "flight-api/internal/dto/weather"
+	"flight-api/internal/model"
+	"flight-api/pkg/logger"
+	"flight-api/util"
+	"sync"
+	"time"
+)
+
+const defaultMemoryCacheMaxEntries = 10000
+
+type memoryEntry struct {
+	key       string
+	value     []byte
+	notFound  bool
+	expiresAt time.Time
+}
+
+// MemoryCache is an in-process, size-bounded LRU implementation of ICache
+// for deployments without Redis. Values are stored as JSON, like in Redis, so
+// callers never share mutable state with the cache.
+type MemoryCache struct {
+	logger     *logger.Logger
+	defaultKey string
+	maxEntries int
+	now        func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	order   *list.List
+}
+
+func NewMemoryCache(logger *logger.Logger, cfg *config.Config) ICache {
+	maxEntries := cfg.CacheMaxEntries
+	if maxEntries <= 0 {
+		maxEntries = defaultMemoryCacheMaxEntries
+	}
+
+	return &MemoryCache{
+		logger:     logger,
+		defaultKey: cfg.ServiceName,
+		maxEntries: maxEntries,
+		now:        time.Now,
+		entries:    make(map[string]*list.Element),
+		order:      list.New(),
+	}
+}
+
+func (c *MemoryCache) FindAirportByID(ctx context.Context, id string) (*model.Airport, error) {
+	return c.findAirport(airportIDKey(c.defaultKey, id))
+}
+
+func (c *MemoryCache) CacheAirportByID(ctx context.Context, id string, data *model.Airport, expiration time.Duration) error {
+	return c.cacheAirport(airportIDKey(c.defaultKey, id), data, expiration)
+}
+
+func (c *MemoryCache) FindAirportByICAOID(ctx context.Context, icaoID string) (*model.Airport, error) {
+	return c.findAirport(airportKey(c.defaultKey, icaoID))
+}
+
+func (c *MemoryCache) CacheAirport(ctx context.Context, icaoID string, data *model.Airport, expiration time.Duration) error {
+	return c.cacheAirport(airportKey(c.defaultKey, icaoID), data, expiration)
+}
+
+func (c *MemoryCache) DeleteAirport(ctx context.Context, id string, icaoID string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, key := range airportKeys(c.defaultKey, id, icaoID) {
+		if elem, ok := c.entries[key]; ok {
+			c.removeElement(elem)
+		}
+	}
+	return nil
+}
+
+func (c *MemoryCache) findAirport(key string) (*model.Airport, error) {
+	var data model.Airport
+
+	value, err := c.get(key)
+	if err != nil {
+		return nil, err
+	}
+
+	err = util.ParseJSON(value, &data)
+	if err != nil {
+		return nil, err
+	}
+
+	return &data, nil
+}
+
+func (c *MemoryCache) cacheAirport(key string, data *model.Airport, expiration time.Duration) error {
+	jsonData, err := util.ToJSON(data)
+	if err != nil {
+		c.logger.Errorf("[CacheAirport] Failed to marshal airport data to JSON: %v", err)
+		return err
+	}
+
+	c.set(key, jsonData, false, expiration)
+	return nil
+}
+
+func (c *MemoryCache) FindWeatherByLocation(ctx context.Context, location string) (*weather_dto.WeatherDto, error) {
+	var data weather_dto.WeatherDto
+
+	value, err := c.get(weatherKey(c.defaultKey, location))
+	if err != nil {
+		return nil, err
+	}
+
+	err = util.ParseJSON(value, &data)
+	if err != nil {
+		return nil, err
+	}
+
+	return &data, nil
+}
+
+func (c *MemoryCache) CacheWeather(ctx context.Context, location string, data *weather_dto.WeatherDto, expiration time.Duration) error {
+	jsonData, err := util.ToJSON(data)
+	if err != nil {
+		c.logger.Errorf("[CacheWeather] Failed to marshal weather data to JSON: %v", err)
+		return err
+	}
+
+	c.set(weatherKey(c.defaultKey, location), jsonData, false, expiration)
+	return nil
+}
+
+func (c *MemoryCache) CacheWeatherNotFound(ctx context.Context, location string, expiration time.Duration) error {
+	c.set(weatherKey(c.defaultKey, location), nil, true, expiration)
+	return nil
+}
+
+func (c *MemoryCache) FindForecast(ctx context.Context, location string, days int) (*weather_dto.ForecastDto, error) {
+	var data weather_dto.ForecastDto
+
+	value, err := c.get(forecastKey(c.defaultKey, location, days))
+	if err != nil {
+		return nil, err
+	}
+
+	err = util.ParseJSON(value, &data)
+	if err != nil {
+		return nil, err
+	}
+
+	return &data, nil
+}
+
+func (c *MemoryCache) CacheForecast(ctx context.Context, location string, days int, data *weather_dto.ForecastDto, expiration time.Duration) error {
+	jsonData, err := util.ToJSON(data)
+	if err != nil {
+		c.logger.Errorf("[CacheForecast] Failed to marshal forecast data to JSON: %v", err)
+		return err
+	}
+
+	c.set(forecastKey(c.defaultKey, location, days), jsonData, false, expiration)
+	return nil
+}
+
+// Len returns the number of entries currently held, including expired
+// entries that have not been evicted yet.
+func (c *MemoryCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+func (c *MemoryCache) get(key string) ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[key]
+	if !ok {
+		return nil, ErrCacheMiss
+	}
+
+	entry := elem.Value.(*memoryEntry)
+	if !entry.expiresAt.IsZero() && !c.now().Before(entry.expiresAt) {
+		c.removeElement(elem)
+		return nil, ErrCacheMiss
+	}
+
+	c.order.MoveToFront(elem)
+	if entry.notFound {
+		return nil, ErrCachedNotFound
+	}
+
+	return entry.value, nil
+}
+
+// set stores a value; a zero expiration keeps the entry until it is evicted,
+// matching Redis semantics.
+func (c *MemoryCache) set(key string, value []byte, notFound bool, expiration time.Duration) {
+	var expiresAt time.Time
+	if expiration > 0 {
+		expiresAt = c.now().Add(expiration)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[key]; ok {
+		entry := elem.Value.(*memoryEntry)
+		entry.value = value
+		entry.notFound = notFound
+		entry.expiresAt = expiresAt
+		c.order.MoveToFront(elem)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(&memoryEntry{
+		key:       key,
+		value:     value,
+		notFound:  notFound,
+		expiresAt: expiresAt,
+	})
+
+	for c.order.Len() > c.maxEntries {
+		c.removeElement(c.order.Back())
+	}
+}
+
+func (c *MemoryCache) removeElement(elem *list.Element) {
+	c.order.Remove(elem)
+	delete(c.entries, elem.Value.(*memoryEntry).key)
+}
@@ -32,6 +32,7 @@ type AirportDto struct {
 	Longitude     *string               `json:"longitude"`
 	LongitudeSec  *string               `json:"longitude_sec"`
 	Elevation     *int64                `json:"elevation"`
+	MagVariation  *string               `json:"magnetic_variation"`
 	ControlTower  *bool                 `json:"control_tower"`
 	Unicom        *string               `json:"unicom"`
 	CTAF          *string               `json:"ctaf"`
@@ -66,6 +67,7 @@ func ToAirportDto(m model.Airport) AirportDto {
 		Longitude:     m.Longitude,
 		LongitudeSec:  m.LongitudeSec,
 		Elevation:     m.Elevation,
+		MagVariation:  m.MagVariation,
 		ControlTower:  m.ControlTower,
 		Unicom:        m.Unicom,
 		CTAF:          m.CTAF,
@@ -28,6 +28,7 @@ type AirportRequestDto struct {
 	Longitude     *string               `json:"longitude" validate:"omitempty"`
 	LongitudeSec  *string               `json:"longitude_sec" validate:"omitempty"`
 	Elevation     *int64                `json:"elevation" validate:"omitempty"`
+	MagVariation  *string               `json:"magnetic_variation" validate:"omitempty"`
 	ControlTower  *bool                 `json:"control_tower" validate:"omitempty"`
 	Unicom        *string               `json:"unicom" validate:"omitempty"`
 	CTAF          *string               `json:"ctaf" validate:"omitempty"`
@@ -57,6 +58,7 @@ func AirportRequestToAirport(r AirportRequestDto) model.Airport {
 		Longitude:     r.Longitude,
 		LongitudeSec:  r.LongitudeSec,
 		Elevation:     r.Elevation,
+		MagVariation:  r.MagVariation,
 		ControlTower:  r.ControlTower,
 		Unicom:        r.Unicom,
 		CTAF:          r.CTAF,
@@ -1,10 +1,66 @@
 package airport_dto
 
-import weather_dto "flight-api/internal/dto/weather"
+import (
+	weather_dto "flight-api/internal/dto/weather"
+	"flight-api/pkg/metar"
+	"time"
+)
+
+// Values of AirportWeatherDto.WeatherStatus.
+const (
+	WeatherStatusOK          = "ok"
+	WeatherStatusStale       = "stale"
+	WeatherStatusUnavailable = "unavailable"
+	WeatherStatusError       = "error"
+	WeatherStatusTimeout     = "timeout"
+)
+
+// WeatherSourceMetar is the AirportWeatherDto.Source of weather built from an
+// ingested METAR.
+const WeatherSourceMetar = "metar"
+
+// Values of AirportWeatherDto.LookupBy, in order of preference.
+const (
+	WeatherLookupCoordinates = "coordinates"
+	WeatherLookupICAO        = "icao"
+	WeatherLookupCity        = "city"
+)
 
 type AirportWeatherDto struct {
-	Object  string                         `json:"object"`
-	Code    *string                        `json:"code"`
-	Airport *AirportDto                    `json:"airport"`
-	Weather *weather_dto.CurrentWeatherDto `json:"weather"`
+	Object        string                         `json:"object"`
+	Code          *string                        `json:"code"`
+	Airport       *AirportDto                    `json:"airport"`
+	Weather       *weather_dto.CurrentWeatherDto `json:"weather"`
+	WeatherStatus string                         `json:"weather_status"`
+	WeatherError  *string                        `json:"weather_error,omitempty"`
+	LookupBy      string                         `json:"lookup_by,omitempty"`
+	Source        *string                        `json:"source,omitempty"`
+	FetchedAt     *time.Time                     `json:"fetched_at,omitempty"`
+	Observation   *ObservationPointDto           `json:"observation,omitempty"`
+	Metar         *metar.Metar                   `json:"metar,omitempty"`
+	Category      *FlightCategoryDto             `json:"flight_category,omitempty"`
+	Performance   *PerformanceConditionsDto      `json:"performance,omitempty"`
+}
+
+// ObservationPointDto is where the reported weather applies, and how far it
+// is from the airport when both positions are known.
+type ObservationPointDto struct {
+	Name       *string  `json:"name"`
+	Lat        *float64 `json:"lat"`
+	Lon        *float64 `json:"lon"`
+	DistanceKm *float64 `json:"distance_km"`
+}
+
+// AirportForecastDto is the forecast at one airport. AtTime is the hourly
+// period covering the requested time, e.g. the estimated arrival.
+type AirportForecastDto struct {
+	Object      string                        `json:"object"`
+	Code        *string                       `json:"code"`
+	Airport     *AirportDto                   `json:"airport"`
+	LookupBy    string                        `json:"lookup_by"`
+	Source      *string                       `json:"source,omitempty"`
+	FetchedAt   *time.Time                    `json:"fetched_at,omitempty"`
+	Observation *ObservationPointDto          `json:"observation,omitempty"`
+	AtTime      *weather_dto.HourlyWeatherDto `json:"at_time,omitempty"`
+	Forecast    []weather_dto.ForecastDayDto  `json:"forecast"`
 }
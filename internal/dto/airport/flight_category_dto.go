@@ -0,0 +1,63 @@
+package airport_dto
+
+import (
+	weather_dto "flight-api/internal/dto/weather"
+	"flight-api/pkg/metar"
+	"math"
+)
+
+// Values of FlightCategoryDto.Source.
+const (
+	FlightCategorySourceMetar   = "metar"
+	FlightCategorySourceWeather = "weather"
+)
+
+// ceilingCloudCover is the cloud cover, in percent, above which consumer
+// weather may hide a ceiling: broken starts at 5/8 of the sky.
+const ceilingCloudCover = 50
+
+// FlightCategoryDto is the FAA flight category at an airport and the values
+// it was derived from. CeilingUnknown is set when consumer weather reports
+// enough cloud for a ceiling but not its height, so the category reflects
+// visibility only and may be better than the real one.
+type FlightCategoryDto struct {
+	Category        string   `json:"category"`
+	CeilingFt       *int     `json:"ceiling_ft"`
+	VisibilityMiles *float64 `json:"visibility_miles"`
+	CeilingUnknown  bool     `json:"ceiling_unknown,omitempty"`
+	Source          string   `json:"source"`
+}
+
+// ToFlightCategoryDto derives the flight category from a decoded METAR when
+// one is given, otherwise from the cloud and vis_miles of consumer weather.
+// It returns nil when neither holds enough to decide.
+func ToFlightCategoryDto(m *metar.Metar, current *weather_dto.CurrentWeatherDto) *FlightCategoryDto {
+	if m != nil {
+		category := m.FlightCategory()
+		if category == "" {
+			return nil
+		}
+		dto := &FlightCategoryDto{Category: category, CeilingFt: m.CeilingFt(), Source: FlightCategorySourceMetar}
+		switch {
+		case m.CAVOK:
+			dto.VisibilityMiles = ptr(10.0)
+		case m.Visibility != nil:
+			dto.VisibilityMiles = ptr(math.Round(m.Visibility.Miles()*100) / 100)
+		}
+		return dto
+	}
+
+	if current == nil || current.VisMiles == nil {
+		return nil
+	}
+	return &FlightCategoryDto{
+		Category:        metar.FlightCategory(nil, current.VisMiles),
+		VisibilityMiles: current.VisMiles,
+		CeilingUnknown:  current.Cloud == nil || *current.Cloud > ceilingCloudCover,
+		Source:          FlightCategorySourceWeather,
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
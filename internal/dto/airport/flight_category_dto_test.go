@@ -0,0 +1,58 @@
+package airport_dto_test
+
+import (
+	airport_dto "flight-api/internal/dto/airport"
+	weather_dto "flight-api/internal/dto/weather"
+	"flight-api/pkg/metar"
+	"flight-api/util"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToFlightCategoryDto_FromMetar(t *testing.T) {
+	m, err := metar.ParseAt("KBOS 061754Z 05009KT 1 1/2SM -RA BR OVC008 13/12 A3010", time.Date(2025, 10, 6, 18, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+
+	// The METAR wins over consumer weather when both are given.
+	got := airport_dto.ToFlightCategoryDto(m, &weather_dto.CurrentWeatherDto{VisMiles: util.Ptr(10.0), Cloud: util.Ptr(0)})
+
+	assert.Equal(t, &airport_dto.FlightCategoryDto{
+		Category:        metar.CategoryIFR,
+		CeilingFt:       util.Ptr(800),
+		VisibilityMiles: util.Ptr(1.5),
+		Source:          airport_dto.FlightCategorySourceMetar,
+	}, got)
+}
+
+func TestToFlightCategoryDto_FromWeather(t *testing.T) {
+	tests := []struct {
+		name           string
+		current        *weather_dto.CurrentWeatherDto
+		want           string
+		ceilingUnknown bool
+	}{
+		{"clear", &weather_dto.CurrentWeatherDto{VisMiles: util.Ptr(10.0), Cloud: util.Ptr(0)}, metar.CategoryVFR, false},
+		{"scattered", &weather_dto.CurrentWeatherDto{VisMiles: util.Ptr(4.0), Cloud: util.Ptr(50)}, metar.CategoryMVFR, false},
+		{"overcast", &weather_dto.CurrentWeatherDto{VisMiles: util.Ptr(2.0), Cloud: util.Ptr(100)}, metar.CategoryIFR, true},
+		{"cloud not reported", &weather_dto.CurrentWeatherDto{VisMiles: util.Ptr(0.5)}, metar.CategoryLIFR, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := airport_dto.ToFlightCategoryDto(nil, tt.current)
+			require.NotNil(t, got)
+			assert.Equal(t, tt.want, got.Category)
+			assert.Equal(t, tt.ceilingUnknown, got.CeilingUnknown)
+			assert.Nil(t, got.CeilingFt)
+			assert.Equal(t, airport_dto.FlightCategorySourceWeather, got.Source)
+		})
+	}
+}
+
+func TestToFlightCategoryDto_Unknown(t *testing.T) {
+	assert.Nil(t, airport_dto.ToFlightCategoryDto(nil, nil))
+	assert.Nil(t, airport_dto.ToFlightCategoryDto(nil, &weather_dto.CurrentWeatherDto{Cloud: util.Ptr(100)}))
+}
@@ -0,0 +1,123 @@
+package airport_dto
+
+import (
+	weather_dto "flight-api/internal/dto/weather"
+	"math"
+	"time"
+)
+
+// Values of PerformanceConditionsDto.Flags.
+const (
+	PerformanceFlagHighDensityAltitude = "high_density_altitude"
+	PerformanceFlagDensityAboveField   = "density_altitude_above_field"
+)
+
+// International Standard Atmosphere constants.
+const (
+	isaSeaLevelHpa     = 1013.25
+	isaSeaLevelTempC   = 15.0
+	isaLapseRatePerFt  = 0.0019812
+	isaSeaLevelDensity = 1.225
+	metersPerFoot      = 0.3048
+	gasConstantDryAir  = 287.05
+	gasConstantVapor   = 461.495
+)
+
+// PerformanceThresholds decides which PerformanceConditionsDto.Flags are
+// raised. A zero threshold never raises its flag.
+type PerformanceThresholds struct {
+	// HighDensityAltitudeFt raises high_density_altitude at or above it.
+	HighDensityAltitudeFt int
+	// AboveFieldFt raises density_altitude_above_field when the density
+	// altitude exceeds the field elevation by at least this much.
+	AboveFieldFt int
+}
+
+// PerformanceConditionsDto describes how the air at an airport performs
+// compared with the standard atmosphere. Density altitude includes the
+// humidity correction when a dewpoint is known.
+type PerformanceConditionsDto struct {
+	ElevationFt        int64    `json:"elevation_ft"`
+	AltimeterHpa       float64  `json:"altimeter_hpa"`
+	TempC              float64  `json:"temp_c"`
+	DewpointC          *float64 `json:"dewpoint_c"`
+	PressureAltitudeFt int      `json:"pressure_altitude_ft"`
+	DensityAltitudeFt  int      `json:"density_altitude_ft"`
+	IsaTempC           float64  `json:"isa_temp_c"`
+	IsaDeviationC      float64  `json:"isa_deviation_c"`
+	HumidityCorrected  bool     `json:"humidity_corrected"`
+	Flags              []string `json:"flags"`
+}
+
+// AirportPerformanceDto is the performance conditions at one airport with
+// the weather they were computed from.
+type AirportPerformanceDto struct {
+	Object        string                    `json:"object"`
+	Code          *string                   `json:"code"`
+	Airport       *AirportDto               `json:"airport"`
+	WeatherStatus string                    `json:"weather_status"`
+	Source        *string                   `json:"source,omitempty"`
+	FetchedAt     *time.Time                `json:"fetched_at,omitempty"`
+	Performance   *PerformanceConditionsDto `json:"performance"`
+}
+
+// ToPerformanceConditionsDto computes pressure and density altitude at a
+// field of elevationFt from the temperature, dewpoint and altimeter setting
+// in current. It returns nil when the elevation, temperature or pressure is
+// unknown.
+func ToPerformanceConditionsDto(elevationFt *int64, current *weather_dto.CurrentWeatherDto, thresholds PerformanceThresholds) *PerformanceConditionsDto {
+	if elevationFt == nil || current == nil || current.TempC == nil || current.PressureMb == nil || *current.PressureMb <= 0 {
+		return nil
+	}
+
+	elevation := float64(*elevationFt)
+	qnh := *current.PressureMb
+	tempC := *current.TempC
+
+	pressureAltitude := elevation + isaAltitudeFt(qnh)
+	isaTemp := isaSeaLevelTempC - isaLapseRatePerFt*pressureAltitude
+
+	// Station pressure is the altimeter setting reduced to field elevation
+	// through the standard atmosphere.
+	stationHpa := qnh * math.Pow(1-elevation*metersPerFoot*2.25577e-5, 5.25588)
+	vaporHpa := 0.0
+	if current.DewpointC != nil {
+		vaporHpa = saturationVaporPressure(*current.DewpointC)
+	}
+	tempK := tempC + 273.15
+	density := (stationHpa-vaporHpa)*100/(gasConstantDryAir*tempK) + vaporHpa*100/(gasConstantVapor*tempK)
+	densityAltitude := (44330.8 - 42266.5*math.Pow(density, 0.234969)) / metersPerFoot
+
+	dto := &PerformanceConditionsDto{
+		ElevationFt:        *elevationFt,
+		AltimeterHpa:       qnh,
+		TempC:              tempC,
+		DewpointC:          current.DewpointC,
+		PressureAltitudeFt: int(math.Round(pressureAltitude)),
+		DensityAltitudeFt:  int(math.Round(densityAltitude)),
+		IsaTempC:           math.Round(isaTemp*10) / 10,
+		IsaDeviationC:      math.Round((tempC-isaTemp)*10) / 10,
+		HumidityCorrected:  current.DewpointC != nil,
+		Flags:              []string{},
+	}
+
+	if thresholds.HighDensityAltitudeFt > 0 && dto.DensityAltitudeFt >= thresholds.HighDensityAltitudeFt {
+		dto.Flags = append(dto.Flags, PerformanceFlagHighDensityAltitude)
+	}
+	if thresholds.AboveFieldFt > 0 && int64(dto.DensityAltitudeFt)-dto.ElevationFt >= int64(thresholds.AboveFieldFt) {
+		dto.Flags = append(dto.Flags, PerformanceFlagDensityAboveField)
+	}
+
+	return dto
+}
+
+// isaAltitudeFt is the height in the standard atmosphere at which pressure
+// equals hpa, so the pressure altitude correction for an altimeter setting.
+func isaAltitudeFt(hpa float64) float64 {
+	return 145366.45 * (1 - math.Pow(hpa/isaSeaLevelHpa, 0.190284))
+}
+
+// saturationVaporPressure in hPa at tempC, by the Magnus formula.
+func saturationVaporPressure(tempC float64) float64 {
+	return 6.1078 * math.Pow(10, 7.5*tempC/(237.3+tempC))
+}
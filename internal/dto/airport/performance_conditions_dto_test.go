@@ -0,0 +1,57 @@
+package airport_dto
+
+import (
+	weather_dto "flight-api/internal/dto/weather"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testThresholds = PerformanceThresholds{HighDensityAltitudeFt: 5000, AboveFieldFt: 2000}
+
+func TestToPerformanceConditionsDto_StandardDay(t *testing.T) {
+	current := &weather_dto.CurrentWeatherDto{TempC: ptr(15.0), PressureMb: ptr(1013.25)}
+
+	dto := ToPerformanceConditionsDto(ptr(int64(0)), current, testThresholds)
+	require.NotNil(t, dto)
+	assert.Equal(t, 0, dto.PressureAltitudeFt)
+	assert.InDelta(t, 0, dto.DensityAltitudeFt, 1)
+	assert.Equal(t, 15.0, dto.IsaTempC)
+	assert.Equal(t, 0.0, dto.IsaDeviationC)
+	assert.False(t, dto.HumidityCorrected)
+	assert.Empty(t, dto.Flags)
+}
+
+func TestToPerformanceConditionsDto_HotHighField(t *testing.T) {
+	// A summer afternoon at a 5,000 ft field with a low altimeter setting.
+	current := &weather_dto.CurrentWeatherDto{TempC: ptr(32.0), DewpointC: ptr(10.0), PressureMb: ptr(1008.0)}
+
+	dto := ToPerformanceConditionsDto(ptr(int64(5000)), current, testThresholds)
+	require.NotNil(t, dto)
+	assert.InDelta(t, 5144, dto.PressureAltitudeFt, 5)
+	assert.InDelta(t, 8400, dto.DensityAltitudeFt, 100)
+	assert.Equal(t, 4.8, dto.IsaTempC)
+	assert.Equal(t, 27.2, dto.IsaDeviationC)
+	assert.True(t, dto.HumidityCorrected)
+	assert.Equal(t, []string{PerformanceFlagHighDensityAltitude, PerformanceFlagDensityAboveField}, dto.Flags)
+}
+
+func TestToPerformanceConditionsDto_HumidityRaisesDensityAltitude(t *testing.T) {
+	dry := &weather_dto.CurrentWeatherDto{TempC: ptr(30.0), PressureMb: ptr(1013.25)}
+	humid := &weather_dto.CurrentWeatherDto{TempC: ptr(30.0), DewpointC: ptr(26.0), PressureMb: ptr(1013.25)}
+
+	dryDto := ToPerformanceConditionsDto(ptr(int64(0)), dry, PerformanceThresholds{})
+	humidDto := ToPerformanceConditionsDto(ptr(int64(0)), humid, PerformanceThresholds{})
+	assert.Greater(t, humidDto.DensityAltitudeFt, dryDto.DensityAltitudeFt+300)
+	assert.Empty(t, humidDto.Flags, "zero thresholds raise no flags")
+}
+
+func TestToPerformanceConditionsDto_MissingInputs(t *testing.T) {
+	current := &weather_dto.CurrentWeatherDto{TempC: ptr(15.0), PressureMb: ptr(1013.25)}
+
+	assert.Nil(t, ToPerformanceConditionsDto(nil, current, testThresholds))
+	assert.Nil(t, ToPerformanceConditionsDto(ptr(int64(0)), nil, testThresholds))
+	assert.Nil(t, ToPerformanceConditionsDto(ptr(int64(0)), &weather_dto.CurrentWeatherDto{TempC: ptr(15.0)}, testThresholds))
+	assert.Nil(t, ToPerformanceConditionsDto(ptr(int64(0)), &weather_dto.CurrentWeatherDto{PressureMb: ptr(1013.25)}, testThresholds))
+}
@@ -0,0 +1,68 @@
+package airport_dto
+
+import "time"
+
+// Values of WindComponentsDto.RunwaySource.
+const (
+	RunwaySourceRequest = "request"
+	RunwaySourceStored  = "stored"
+)
+
+// Values of WindComponentDto.CrosswindFrom.
+const (
+	CrosswindFromLeft  = "left"
+	CrosswindFromRight = "right"
+)
+
+// WindComponentsDto is the current wind at an airport resolved along each
+// runway. Recommended is the runway with the most headwind, ties going to
+// the least crosswind; it is nil when the wind is calm or has no direction.
+type WindComponentsDto struct {
+	Object            string          `json:"object"`
+	Code              *string         `json:"code"`
+	Airport           *AirportDto     `json:"airport"`
+	Wind              WindDto         `json:"wind"`
+	MagneticVariation *float64        `json:"magnetic_variation"`
+	RunwaySource      string          `json:"runway_source"`
+	Runways           []RunwayWindDto `json:"runways"`
+	Recommended       *string         `json:"recommended_runway"`
+	WeatherStatus     string          `json:"weather_status"`
+	Source            *string         `json:"source,omitempty"`
+	FetchedAt         *time.Time      `json:"fetched_at,omitempty"`
+}
+
+// WindDto is the wind the components were computed from. Directions are
+// where the wind blows from; DirectionMagnetic is nil when the airport has
+// no usable magnetic variation.
+type WindDto struct {
+	DirectionTrue     *float64 `json:"direction_true"`
+	DirectionMagnetic *float64 `json:"direction_magnetic"`
+	SpeedKt           float64  `json:"speed_kt"`
+	SpeedKph          float64  `json:"speed_kph"`
+	GustKt            *float64 `json:"gust_kt"`
+	GustKph           *float64 `json:"gust_kph"`
+	Calm              bool     `json:"calm"`
+	Variable          bool     `json:"variable"`
+}
+
+// RunwayWindDto is the wind along one runway end. Steady is nil when the
+// wind has no direction; Gust is only set when gusts exceed the steady wind.
+type RunwayWindDto struct {
+	Runway          string            `json:"runway"`
+	HeadingMagnetic float64           `json:"heading_magnetic"`
+	HeadingTrue     float64           `json:"heading_true"`
+	Steady          *WindComponentDto `json:"steady"`
+	Gust            *WindComponentDto `json:"gust,omitempty"`
+}
+
+// WindComponentDto splits one wind speed along a runway. A negative
+// headwind is a tailwind. The crosswind is always positive, with the side
+// it blows from in CrosswindFrom.
+type WindComponentDto struct {
+	HeadwindKt    float64 `json:"headwind_kt"`
+	HeadwindKph   float64 `json:"headwind_kph"`
+	CrosswindKt   float64 `json:"crosswind_kt"`
+	CrosswindKph  float64 `json:"crosswind_kph"`
+	CrosswindFrom string  `json:"crosswind_from,omitempty"`
+	Tailwind      bool    `json:"tailwind"`
+}
@@ -63,6 +63,7 @@ func ToAirportRequestDto(source AviationAirportDto) airport_dto.AirportRequestDt
 		Longitude:     &source.Longitude,
 		LongitudeSec:  &source.LongitudeSec,
 		Elevation:     util.ParseInt64Ptr(source.Elevation),
+		MagVariation:  &source.MagneticVariation,
 		ControlTower:  util.Ptr(ToControlTower(source.ControlTower)),
 		Unicom:        &source.UNICOM,
 		CTAF:          &source.CTAF,
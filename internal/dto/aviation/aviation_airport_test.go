@@ -4,6 +4,7 @@ import (
 	airport_dto "flight-api/internal/dto/airport"
 	aviation_dto "flight-api/internal/dto/aviation"
 	"flight-api/internal/enum"
+	"flight-api/pkg/nasr"
 	"testing"
 	"time"
 
@@ -33,7 +34,7 @@ func TestToAirportRequestDto(t *testing.T) {
 		Longitude:               "",
 		LongitudeSec:            "",
 		Elevation:               "13",
-		MagneticVariation:       "",
+		MagneticVariation:       "13W",
 		TPA:                     "",
 		VFRSectional:            "",
 		NotamFacilityIdentifier: "",
@@ -72,6 +73,7 @@ func TestToAirportRequestDto(t *testing.T) {
 	assert.Equal(t, "", *result.Longitude)
 	assert.Equal(t, "", *result.LongitudeSec)
 	assert.Equal(t, int64(13), *result.Elevation)
+	assert.Equal(t, "13W", *result.MagVariation)
 	assert.Equal(t, true, *result.ControlTower)
 	assert.Equal(t, "A", *result.Unicom)
 	assert.Equal(t, "A", *result.CTAF)
@@ -277,3 +279,38 @@ func TestToAirportEffectiveDate(t *testing.T) {
 		})
 	}
 }
+
+func TestFromNASRAirport(t *testing.T) {
+	source := nasr.Airport{
+		SiteNumber:    "15793.*A",
+		Type:          "AIRPORT",
+		FAAID:         "JFK",
+		ICAOID:        "KJFK",
+		EffectiveDate: time.Date(2025, time.October, 2, 0, 0, 0, 0, time.UTC),
+		Name:          "JOHN F KENNEDY INTL",
+		Ownership:     "PU",
+		Use:           "PU",
+		Elevation:     "13.6",
+		MagVariation:  "13W",
+		Status:        "O",
+		ControlTower:  "Y",
+	}
+
+	result := aviation_dto.ToAirportRequestDto(aviation_dto.FromNASRAirport(source))
+
+	assert.Equal(t, "15793.*A", *result.SiteNumber)
+	assert.Equal(t, "KJFK", *result.ICAOID)
+	assert.Equal(t, "JFK", *result.FAAID)
+	assert.Equal(t, "airport", *result.Type)
+	assert.Equal(t, int64(14), *result.Elevation)
+	assert.Equal(t, true, *result.ControlTower)
+	assert.Equal(t, time.Date(2025, time.October, 2, 0, 0, 0, 0, time.UTC), *result.EffectiveDate)
+
+	// Without an ICAO identifier the FAA location identifier is the key
+	source.ICAOID = ""
+	source.FAAID = "6N5"
+	source.Type = "SEAPLANE BASE"
+	result = aviation_dto.ToAirportRequestDto(aviation_dto.FromNASRAirport(source))
+	assert.Equal(t, "6N5", *result.ICAOID)
+	assert.Nil(t, result.Type)
+}
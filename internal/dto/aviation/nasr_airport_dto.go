@@ -0,0 +1,55 @@
+package aviation_dto
+
+import (
+	"flight-api/pkg/nasr"
+	"math"
+	"strconv"
+)
+
+// FromNASRAirport maps a facility from the NASR subscription files onto the
+// Aviation API DTO, which publishes the same data, so both go through
+// ToAirportRequestDto. A facility without an ICAO identifier is keyed by its
+// FAA location identifier, as the Aviation API looks it up.
+func FromNASRAirport(source nasr.Airport) AviationAirportDto {
+	dto := AviationAirportDto{
+		SiteNumber:              source.SiteNumber,
+		Type:                    source.Type,
+		FacilityName:            source.Name,
+		FAAIdentifier:           source.FAAID,
+		ICAOIdentifier:          source.ICAOID,
+		Region:                  source.Region,
+		DistrictOffice:          source.DistrictOffice,
+		State:                   source.State,
+		StateFull:               source.StateFull,
+		County:                  source.County,
+		City:                    source.City,
+		Ownership:               source.Ownership,
+		Use:                     source.Use,
+		Manager:                 source.Manager,
+		ManagerPhone:            source.ManagerPhone,
+		Latitude:                source.Latitude,
+		LatitudeSec:             source.LatitudeSec,
+		Longitude:               source.Longitude,
+		LongitudeSec:            source.LongitudeSec,
+		Elevation:               source.Elevation,
+		MagneticVariation:       source.MagVariation,
+		TPA:                     source.TPA,
+		VFRSectional:            source.Sectional,
+		NotamFacilityIdentifier: source.NotamID,
+		Status:                  source.Status,
+		ControlTower:            source.ControlTower,
+		UNICOM:                  source.Unicom,
+		CTAF:                    source.CTAF,
+	}
+	if dto.ICAOIdentifier == "" {
+		dto.ICAOIdentifier = source.FAAID
+	}
+	// NASR publishes elevation to the tenth of a foot
+	if elevation, err := strconv.ParseFloat(source.Elevation, 64); err == nil {
+		dto.Elevation = strconv.FormatInt(int64(math.Round(elevation)), 10)
+	}
+	if !source.EffectiveDate.IsZero() {
+		dto.EffectiveDate = source.EffectiveDate.Format("02/01/2006")
+	}
+	return dto
+}
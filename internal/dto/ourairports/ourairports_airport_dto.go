@@ -0,0 +1,118 @@
+package ourairports_dto
+
+import (
+	airport_dto "flight-api/internal/dto/airport"
+	"flight-api/internal/enum"
+	"flight-api/pkg/ourairports"
+	"flight-api/util"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// maxTextLength is the width of the country and city columns; longer
+// OurAirports names are cut to fit.
+const maxTextLength = 64
+
+// ToAirportRequestDto maps an OurAirports airport onto the airport fields it
+// publishes. Fields it has no value for stay nil, so they keep what another
+// source stored.
+func ToAirportRequestDto(source ourairports.Airport) airport_dto.AirportRequestDto {
+	dto := airport_dto.AirportRequestDto{
+		ICAOID:    util.Ptr(ICAOIdentifier(source)),
+		IATAID:    optional(source.IATACode),
+		Name:      optional(source.Name),
+		Type:      ToFacilityType(source.Type),
+		Status:    util.Ptr(source.Type != "closed"),
+		Country:   optional(truncate(source.CountryName, maxTextLength)),
+		State:     optional(ToState(source.Region)),
+		City:      optional(truncate(source.Municipality, maxTextLength)),
+		Elevation: util.ParseInt64Ptr(source.ElevationFt),
+	}
+	if dto.Country == nil {
+		dto.Country = optional(source.Country)
+	}
+	if source.Country == "US" {
+		dto.FAAID = optional(source.LocalCode)
+	}
+
+	if latitude, err := strconv.ParseFloat(source.LatitudeDeg, 64); err == nil {
+		dto.Latitude, dto.LatitudeSec = ToCoordinate(latitude, "N", "S", 2)
+	}
+	if longitude, err := strconv.ParseFloat(source.LongitudeDeg, 64); err == nil {
+		dto.Longitude, dto.LongitudeSec = ToCoordinate(longitude, "E", "W", 3)
+	}
+
+	return dto
+}
+
+// ICAOIdentifier is the code an OurAirports airport is stored under: its
+// ICAO code, or for a US facility without one its FAA identifier, as the
+// NASR import and the Aviation API key it, or else its GPS code or ident.
+func ICAOIdentifier(source ourairports.Airport) string {
+	switch {
+	case source.ICAOCode != "":
+		return source.ICAOCode
+	case source.Country == "US" && source.LocalCode != "":
+		return source.LocalCode
+	case source.GPSCode != "":
+		return source.GPSCode
+	default:
+		return source.Ident
+	}
+}
+
+// ToFacilityType maps the OurAirports airport types onto the facility enum;
+// seaplane bases, balloonports and closed airports have no facility type.
+func ToFacilityType(airportType string) enum.FasilityTypeEnum {
+	switch airportType {
+	case "large_airport", "medium_airport", "small_airport":
+		return enum.AIRPORT
+	case "heliport":
+		return enum.HELIPORT
+	default:
+		return enum.NIL
+	}
+}
+
+// ToState drops the country from an ISO 3166-2 region code, so US-NY
+// becomes NY.
+func ToState(region string) string {
+	if _, subdivision, ok := strings.Cut(region, "-"); ok {
+		return subdivision
+	}
+	return ""
+}
+
+// ToCoordinate formats decimal degrees the way NASR publishes coordinates:
+// 40-38-23.7400N, and in seconds, 146303.7400N.
+func ToCoordinate(degrees float64, positive, negative string, degreeDigits int) (*string, *string) {
+	hemisphere := positive
+	if degrees < 0 {
+		hemisphere = negative
+	}
+
+	total := math.Round(math.Abs(degrees)*3600*1e4) / 1e4
+	deg := math.Floor(total / 3600)
+	minutes := math.Floor((total - deg*3600) / 60)
+	sec := total - deg*3600 - minutes*60
+
+	formatted := fmt.Sprintf("%0*d-%02d-%07.4f%s", degreeDigits, int(deg), int(minutes), sec, hemisphere)
+	seconds := fmt.Sprintf("%.4f%s", total, hemisphere)
+	return &formatted, &seconds
+}
+
+func optional(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+func truncate(s string, length int) string {
+	if runes := []rune(s); len(runes) > length {
+		return string(runes[:length])
+	}
+	return s
+}
@@ -0,0 +1,100 @@
+package ourairports_dto
+
+import (
+	"flight-api/internal/enum"
+	"flight-api/pkg/ourairports"
+	"flight-api/util"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToAirportRequestDto(t *testing.T) {
+	dto := ToAirportRequestDto(ourairports.Airport{
+		Ident:        "KJFK",
+		Type:         "large_airport",
+		Name:         "John F Kennedy International Airport",
+		LatitudeDeg:  "40.639447",
+		LongitudeDeg: "-73.779317",
+		ElevationFt:  "13",
+		Country:      "US",
+		CountryName:  "United States",
+		Region:       "US-NY",
+		Municipality: "New York",
+		ICAOCode:     "KJFK",
+		IATACode:     "JFK",
+		GPSCode:      "KJFK",
+		LocalCode:    "JFK",
+	})
+
+	assert.Equal(t, "KJFK", *dto.ICAOID)
+	assert.Equal(t, "JFK", *dto.FAAID)
+	assert.Equal(t, "JFK", *dto.IATAID)
+	assert.Equal(t, enum.AIRPORT, dto.Type)
+	assert.True(t, *dto.Status)
+	assert.Equal(t, "United States", *dto.Country)
+	assert.Equal(t, "NY", *dto.State)
+	assert.Equal(t, "New York", *dto.City)
+	assert.Equal(t, int64(13), *dto.Elevation)
+	assert.Equal(t, "40-38-22.0092N", *dto.Latitude)
+	assert.Equal(t, "146302.0092N", *dto.LatitudeSec)
+	assert.Equal(t, "073-46-45.5412W", *dto.Longitude)
+	assert.Equal(t, "265605.5412W", *dto.LongitudeSec)
+
+	// Fields OurAirports does not publish keep the stored value
+	assert.Nil(t, dto.SiteNumber)
+	assert.Nil(t, dto.Manager)
+	assert.Nil(t, dto.MagVariation)
+
+	latitude, err := util.ParseCoordinate(*dto.Latitude)
+	assert.NoError(t, err)
+	assert.InDelta(t, 40.639447, latitude, 1e-6)
+}
+
+func TestToAirportRequestDto_OutsideUS(t *testing.T) {
+	dto := ToAirportRequestDto(ourairports.Airport{
+		Ident:        "EGLL",
+		Type:         "large_airport",
+		LatitudeDeg:  "51.4706",
+		LongitudeDeg: "-0.461941",
+		Country:      "GB",
+		Region:       "GB-ENG",
+		ICAOCode:     "EGLL",
+		LocalCode:    "HEATHROW",
+	})
+
+	assert.Nil(t, dto.FAAID)
+	assert.Nil(t, dto.IATAID)
+	// Without countries.csv the ISO code stands in for the name
+	assert.Equal(t, "GB", *dto.Country)
+	assert.Equal(t, "ENG", *dto.State)
+	assert.Equal(t, "51-28-14.1600N", *dto.Latitude)
+	assert.Equal(t, "000-27-42.9876W", *dto.Longitude)
+	assert.Nil(t, dto.Elevation)
+}
+
+func TestICAOIdentifier(t *testing.T) {
+	cases := []struct {
+		name   string
+		source ourairports.Airport
+		want   string
+	}{
+		{"icao code", ourairports.Airport{Ident: "EGLL", ICAOCode: "EGLL", GPSCode: "EGLL"}, "EGLL"},
+		{"us faa identifier", ourairports.Airport{Ident: "00A", Country: "US", GPSCode: "K00A", LocalCode: "00A"}, "00A"},
+		{"gps code", ourairports.Airport{Ident: "CA-0042", Country: "CA", GPSCode: "CPZ4", LocalCode: "PZ4"}, "CPZ4"},
+		{"ident", ourairports.Airport{Ident: "AU-0022", Country: "AU"}, "AU-0022"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, ICAOIdentifier(tc.source))
+		})
+	}
+}
+
+func TestToFacilityType(t *testing.T) {
+	assert.Equal(t, enum.AIRPORT, ToFacilityType("medium_airport"))
+	assert.Equal(t, enum.AIRPORT, ToFacilityType("small_airport"))
+	assert.Equal(t, enum.HELIPORT, ToFacilityType("heliport"))
+	assert.Equal(t, enum.NIL, ToFacilityType("seaplane_base"))
+	assert.Equal(t, enum.NIL, ToFacilityType("closed"))
+}
@@ -4,6 +4,9 @@ type PaginationMetaDto struct {
 	Page  int  `json:"page"`
 	Limit int  `json:"limit"`
 	Next  bool `json:"next"`
+	// Truncated is set when only part of the matching rows were examined,
+	// so Total is a lower bound.
+	Truncated bool `json:"truncated,omitempty"`
 }
 
 type PaginationDto struct {
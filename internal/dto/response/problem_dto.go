@@ -0,0 +1,38 @@
+package response_dto
+
+import "encoding/json"
+
+// ProblemDto is an RFC 7807 problem details object (application/problem+json).
+type ProblemDto struct {
+	Type       string                 `json:"type"`
+	Title      string                 `json:"title"`
+	Status     int                    `json:"status"`
+	Detail     string                 `json:"detail,omitempty"`
+	Instance   string                 `json:"instance,omitempty"`
+	RequestID  string                 `json:"request_id,omitempty"`
+	Extensions map[string]interface{} `json:"-"`
+}
+
+// MarshalJSON flattens extension members into the top-level object as
+// required by RFC 7807. Standard members always win over extensions.
+func (p ProblemDto) MarshalJSON() ([]byte, error) {
+	out := make(map[string]interface{}, len(p.Extensions)+6)
+	for k, v := range p.Extensions {
+		out[k] = v
+	}
+
+	out["type"] = p.Type
+	out["title"] = p.Title
+	out["status"] = p.Status
+	if p.Detail != "" {
+		out["detail"] = p.Detail
+	}
+	if p.Instance != "" {
+		out["instance"] = p.Instance
+	}
+	if p.RequestID != "" {
+		out["request_id"] = p.RequestID
+	}
+
+	return json.Marshal(out)
+}
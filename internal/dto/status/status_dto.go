@@ -0,0 +1,83 @@
+package status_dto
+
+import (
+	"flight-api/internal/cache"
+	"flight-api/pkg/httpclient"
+	"time"
+)
+
+// Values of StatusDto.Status and DependencyDto.Status.
+const (
+	StatusUp       = "up"
+	StatusDegraded = "degraded"
+	StatusDown     = "down"
+)
+
+// Values of DependencyDto.Kind.
+const (
+	KindDatabase = "database"
+	KindUpstream = "upstream"
+)
+
+type StatusDto struct {
+	Object       string          `json:"object"`
+	Status       string          `json:"status"`
+	CheckedAt    time.Time       `json:"checked_at"`
+	Dependencies []DependencyDto `json:"dependencies"`
+	Caches       []CacheDto      `json:"caches"`
+}
+
+type CacheDto struct {
+	Name   string `json:"name"`
+	Hits   uint64 `json:"hits"`
+	Misses uint64 `json:"misses"`
+	Errors uint64 `json:"errors"`
+}
+
+type DependencyDto struct {
+	Name                string            `json:"name"`
+	Kind                string            `json:"kind"`
+	Status              string            `json:"status"`
+	BreakerState        *string           `json:"breaker_state,omitempty"`
+	ConsecutiveFailures int               `json:"consecutive_failures"`
+	LastError           *string           `json:"last_error"`
+	LastErrorAt         *time.Time        `json:"last_error_at"`
+	LastSuccessAt       *time.Time        `json:"last_success_at"`
+	OpenUntil           *time.Time        `json:"open_until,omitempty"`
+	Stats               *httpclient.Stats `json:"stats,omitempty"`
+}
+
+// ToUpstreamDependencyDto maps an upstream's breaker health and request
+// metrics. An open breaker is down and a half-open one is degraded.
+func ToUpstreamDependencyDto(health httpclient.Health, stats httpclient.Stats) DependencyDto {
+	status := StatusUp
+	switch health.State {
+	case httpclient.StateOpen:
+		status = StatusDown
+	case httpclient.StateHalfOpen:
+		status = StatusDegraded
+	}
+
+	state := string(health.State)
+	return DependencyDto{
+		Name:                health.Name,
+		Kind:                KindUpstream,
+		Status:              status,
+		BreakerState:        &state,
+		ConsecutiveFailures: health.ConsecutiveFailures,
+		LastError:           health.LastError,
+		LastErrorAt:         health.LastErrorAt,
+		LastSuccessAt:       health.LastSuccessAt,
+		OpenUntil:           health.OpenUntil,
+		Stats:               &stats,
+	}
+}
+
+func ToCacheDto(name string, stats cache.Stats) CacheDto {
+	return CacheDto{
+		Name:   name,
+		Hits:   stats.Hits,
+		Misses: stats.Misses,
+		Errors: stats.Errors,
+	}
+}
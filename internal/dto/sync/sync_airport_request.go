@@ -1,5 +1,21 @@
 package sync_dto
 
+import "github.com/google/uuid"
+
 type SyncAirportRequest struct {
 	ICAOCodes []string `json:"icao_codes" validate:"required,dive,required"`
+	// Mode is "insert" (the default) or "refresh".
+	Mode string `json:"mode" validate:"omitempty,oneof=insert refresh"`
+	// Fields limits a refresh to these fields, leaving the others as they
+	// are stored. Empty refreshes every field.
+	Fields []string `json:"fields" validate:"omitempty,dive,required"`
+	// DryRun reports what the sync would do and rolls back its writes.
+	DryRun bool `json:"dry_run"`
+	// Atomic stores every airport in one transaction and commits only when
+	// all of them sync. By default each airport commits on its own.
+	Atomic bool `json:"atomic"`
+	// Trigger and JobID record what called for the sync in its run history.
+	// They are set by the caller, never read from the request body.
+	Trigger string     `json:"-"`
+	JobID   *uuid.UUID `json:"-"`
 }
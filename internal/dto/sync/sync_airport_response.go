@@ -7,4 +7,13 @@ type SyncAirportResponse struct {
 	Airport  *airport_dto.AirportDto `json:"airport"`
 	Status   string                  `json:"status"`
 	Message  string                  `json:"message"`
+	Changes  []FieldChangeDto        `json:"changes,omitempty"`
+}
+
+// FieldChangeDto is a field a refresh changed, from the stored value to the
+// fetched one.
+type FieldChangeDto struct {
+	Field string      `json:"field"`
+	Old   interface{} `json:"old"`
+	New   interface{} `json:"new"`
 }
@@ -0,0 +1,94 @@
+package sync_dto
+
+import (
+	"encoding/json"
+	"flight-api/internal/model"
+	"time"
+)
+
+// SyncJobDto is an asynchronous airport sync and the per-ICAO results it
+// has produced so far.
+type SyncJobDto struct {
+	Object     string                `json:"object"`
+	ID         string                `json:"id"`
+	Status     string                `json:"status"`
+	ICAOCodes  []string              `json:"icao_codes"`
+	Mode       string                `json:"mode"`
+	Fields     []string              `json:"fields"`
+	Atomic     bool                  `json:"atomic"`
+	Trigger    string                `json:"trigger"`
+	Total      int64                 `json:"total"`
+	Processed  int64                 `json:"processed"`
+	Counts     map[string]int        `json:"counts"`
+	Results    []SyncAirportResponse `json:"results"`
+	Error      *string               `json:"error,omitempty"`
+	CreatedAt  *time.Time            `json:"created_at"`
+	StartedAt  *time.Time            `json:"started_at"`
+	FinishedAt *time.Time            `json:"finished_at"`
+}
+
+// DecodeSyncJobResults decodes the results stored on a job. Empty or
+// unreadable results decode to an empty list.
+func DecodeSyncJobResults(raw []byte) []SyncAirportResponse {
+	results := []SyncAirportResponse{}
+	if len(raw) == 0 {
+		return results
+	}
+	if err := json.Unmarshal(raw, &results); err != nil {
+		return []SyncAirportResponse{}
+	}
+	return results
+}
+
+func ToSyncJobDto(job model.SyncJob) SyncJobDto {
+	dto := SyncJobDto{
+		Object:     "sync_job",
+		ICAOCodes:  job.ICAOCodes,
+		Fields:     job.Fields,
+		Counts:     map[string]int{},
+		Results:    DecodeSyncJobResults(job.Results),
+		Error:      job.Error,
+		CreatedAt:  job.CreatedAt,
+		StartedAt:  job.StartedAt,
+		FinishedAt: job.FinishedAt,
+	}
+	if job.ID != nil {
+		dto.ID = job.ID.String()
+	}
+	if job.Status != nil {
+		dto.Status = *job.Status
+	}
+	if job.Mode != nil {
+		dto.Mode = *job.Mode
+	}
+	if job.Atomic != nil {
+		dto.Atomic = *job.Atomic
+	}
+	if job.Trigger != nil {
+		dto.Trigger = *job.Trigger
+	}
+	if job.Total != nil {
+		dto.Total = *job.Total
+	}
+	if job.Processed != nil {
+		dto.Processed = *job.Processed
+	}
+	if dto.ICAOCodes == nil {
+		dto.ICAOCodes = []string{}
+	}
+	if dto.Fields == nil {
+		dto.Fields = []string{}
+	}
+	for _, result := range dto.Results {
+		dto.Counts[result.Status]++
+	}
+	return dto
+}
+
+func ToSyncJobDtos(jobs []model.SyncJob) []SyncJobDto {
+	dtos := make([]SyncJobDto, 0, len(jobs))
+	for _, job := range jobs {
+		dtos = append(dtos, ToSyncJobDto(job))
+	}
+	return dtos
+}
@@ -0,0 +1,46 @@
+package sync_dto_test
+
+import (
+	sync_dto "flight-api/internal/dto/sync"
+	"flight-api/internal/model"
+	"flight-api/util"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToSyncJobDto(t *testing.T) {
+	id := uuid.New()
+	now := time.Now()
+	job := model.SyncJob{
+		ID:        &id,
+		Status:    util.Ptr("running"),
+		ICAOCodes: []string{"KJFK", "KSEA", "KXXX"},
+		Total:     util.Ptr(int64(3)),
+		Processed: util.Ptr(int64(2)),
+		Results:   []byte(`[{"icao_code":"KJFK","status":"Conflict"},{"icao_code":"KSEA","status":"Inserted"}]`),
+		CreatedAt: &now,
+		StartedAt: &now,
+	}
+
+	dto := sync_dto.ToSyncJobDto(job)
+	assert.Equal(t, "sync_job", dto.Object)
+	assert.Equal(t, id.String(), dto.ID)
+	assert.Equal(t, "running", dto.Status)
+	assert.Equal(t, int64(3), dto.Total)
+	assert.Equal(t, int64(2), dto.Processed)
+	assert.Len(t, dto.Results, 2)
+	assert.Equal(t, "KSEA", dto.Results[1].ICAOCode)
+	assert.Equal(t, map[string]int{"Conflict": 1, "Inserted": 1}, dto.Counts)
+	assert.Nil(t, dto.FinishedAt)
+}
+
+func TestToSyncJobDto_EmptyResults(t *testing.T) {
+	dto := sync_dto.ToSyncJobDto(model.SyncJob{Results: []byte(`not json`)})
+	assert.NotNil(t, dto.Results)
+	assert.Empty(t, dto.Results)
+	assert.NotNil(t, dto.ICAOCodes)
+	assert.Empty(t, dto.Counts)
+}
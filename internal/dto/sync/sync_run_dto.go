@@ -0,0 +1,176 @@
+package sync_dto
+
+import (
+	"encoding/json"
+	"flight-api/internal/enum"
+	"flight-api/internal/model"
+	"flight-api/util"
+	"time"
+)
+
+// SyncRunFilter narrows a list of sync runs to those with a code that ended
+// in Outcome, started by Trigger or run for the job JobID. Empty fields
+// match every run.
+type SyncRunFilter struct {
+	Outcome string
+	Trigger string
+	JobID   string
+}
+
+// SyncRunDto is one airport sync run: what called for it, the codes it was
+// asked for and how many ended in each outcome. Items holds the outcome of
+// each code, and is only filled in when a single run is looked up.
+type SyncRunDto struct {
+	Object     string           `json:"object"`
+	ID         string           `json:"id"`
+	Trigger    string           `json:"trigger"`
+	JobID      *string          `json:"job_id"`
+	ICAOCodes  []string         `json:"icao_codes"`
+	Mode       string           `json:"mode"`
+	Fields     []string         `json:"fields"`
+	DryRun     bool             `json:"dry_run"`
+	Atomic     bool             `json:"atomic"`
+	Total      int64            `json:"total"`
+	Failed     int64            `json:"failed"`
+	Counts     map[string]int   `json:"counts"`
+	StartedAt  *time.Time       `json:"started_at"`
+	FinishedAt *time.Time       `json:"finished_at"`
+	Items      []SyncRunItemDto `json:"items,omitempty"`
+}
+
+// SyncRunItemDto is the outcome of one ICAO code in a run.
+type SyncRunItemDto struct {
+	ICAOCode string           `json:"icao_code"`
+	Outcome  string           `json:"outcome"`
+	Status   string           `json:"status"`
+	Message  string           `json:"message"`
+	Changes  []FieldChangeDto `json:"changes,omitempty"`
+}
+
+// ToSyncRun turns the responses of a sync into the run and items recorded
+// for it, counting the codes per outcome.
+func ToSyncRun(req SyncAirportRequest, responses []SyncAirportResponse, startedAt, finishedAt time.Time) (model.SyncRun, []model.SyncRunItem) {
+	trigger := req.Trigger
+	if trigger == "" {
+		trigger = enum.SYNC_RUN_TRIGGER_API.String()
+	}
+	mode := req.Mode
+	if mode == "" {
+		mode = enum.SYNC_MODE_INSERT.String()
+	}
+
+	counts := map[string]int{}
+	var failed int64
+	items := make([]model.SyncRunItem, 0, len(responses))
+	for _, res := range responses {
+		outcome := enum.ToSyncRunOutcome(res.Status)
+		counts[outcome.String()]++
+		if outcome.IsFailure() {
+			failed++
+		}
+
+		item := model.SyncRunItem{
+			ICAOCode: &res.ICAOCode,
+			Outcome:  util.Ptr(outcome.String()),
+			Status:   &res.Status,
+			Message:  &res.Message,
+		}
+		if len(res.Changes) > 0 {
+			item.Changes, _ = json.Marshal(res.Changes)
+		}
+		items = append(items, item)
+	}
+	raw, _ := json.Marshal(counts)
+
+	return model.SyncRun{
+		Trigger:    &trigger,
+		JobID:      req.JobID,
+		ICAOCodes:  req.ICAOCodes,
+		Mode:       &mode,
+		Fields:     req.Fields,
+		DryRun:     &req.DryRun,
+		Atomic:     &req.Atomic,
+		Total:      util.Ptr(int64(len(responses))),
+		Failed:     &failed,
+		Counts:     raw,
+		StartedAt:  &startedAt,
+		FinishedAt: &finishedAt,
+	}, items
+}
+
+func ToSyncRunDto(run model.SyncRun) SyncRunDto {
+	dto := SyncRunDto{
+		Object:     "sync_run",
+		ICAOCodes:  run.ICAOCodes,
+		Fields:     run.Fields,
+		Counts:     map[string]int{},
+		StartedAt:  run.StartedAt,
+		FinishedAt: run.FinishedAt,
+	}
+	if run.ID != nil {
+		dto.ID = run.ID.String()
+	}
+	if run.Trigger != nil {
+		dto.Trigger = *run.Trigger
+	}
+	if run.JobID != nil {
+		dto.JobID = util.Ptr(run.JobID.String())
+	}
+	if run.Mode != nil {
+		dto.Mode = *run.Mode
+	}
+	if run.DryRun != nil {
+		dto.DryRun = *run.DryRun
+	}
+	if run.Atomic != nil {
+		dto.Atomic = *run.Atomic
+	}
+	if run.Total != nil {
+		dto.Total = *run.Total
+	}
+	if run.Failed != nil {
+		dto.Failed = *run.Failed
+	}
+	if dto.ICAOCodes == nil {
+		dto.ICAOCodes = []string{}
+	}
+	if dto.Fields == nil {
+		dto.Fields = []string{}
+	}
+	if len(run.Counts) > 0 {
+		_ = json.Unmarshal(run.Counts, &dto.Counts)
+	}
+	return dto
+}
+
+func ToSyncRunDtos(runs []model.SyncRun) []SyncRunDto {
+	dtos := make([]SyncRunDto, 0, len(runs))
+	for _, run := range runs {
+		dtos = append(dtos, ToSyncRunDto(run))
+	}
+	return dtos
+}
+
+func ToSyncRunItemDtos(items []model.SyncRunItem) []SyncRunItemDto {
+	dtos := make([]SyncRunItemDto, 0, len(items))
+	for _, item := range items {
+		dto := SyncRunItemDto{}
+		if item.ICAOCode != nil {
+			dto.ICAOCode = *item.ICAOCode
+		}
+		if item.Outcome != nil {
+			dto.Outcome = *item.Outcome
+		}
+		if item.Status != nil {
+			dto.Status = *item.Status
+		}
+		if item.Message != nil {
+			dto.Message = *item.Message
+		}
+		if len(item.Changes) > 0 {
+			_ = json.Unmarshal(item.Changes, &dto.Changes)
+		}
+		dtos = append(dtos, dto)
+	}
+	return dtos
+}
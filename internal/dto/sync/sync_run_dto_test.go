@@ -0,0 +1,56 @@
+package sync_dto_test
+
+import (
+	sync_dto "flight-api/internal/dto/sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToSyncRun(t *testing.T) {
+	jobID := uuid.New()
+	started := time.Now()
+	finished := started.Add(time.Second)
+	req := sync_dto.SyncAirportRequest{ICAOCodes: []string{"KJFK", "KSEA", "KXXX"}, Mode: "refresh", Trigger: "job", JobID: &jobID}
+
+	run, items := sync_dto.ToSyncRun(req, []sync_dto.SyncAirportResponse{
+		{ICAOCode: "KJFK", Status: "Updated", Message: "Airport data refreshed, 1 fields changed", Changes: []sync_dto.FieldChangeDto{{Field: "name", Old: "A", New: "B"}}},
+		{ICAOCode: "KSEA", Status: "Unchanged"},
+		{ICAOCode: "KXXX", Status: "Not Found", Message: "No data found from Aviation API."},
+	}, started, finished)
+
+	assert.Equal(t, "job", *run.Trigger)
+	assert.Equal(t, jobID, *run.JobID)
+	assert.Equal(t, "refresh", *run.Mode)
+	assert.Equal(t, int64(3), *run.Total)
+	assert.Equal(t, int64(1), *run.Failed)
+	assert.JSONEq(t, `{"updated":1,"unchanged":1,"not_found":1}`, string(run.Counts))
+	require.Len(t, items, 3)
+	assert.Equal(t, "not_found", *items[2].Outcome)
+	assert.JSONEq(t, `[{"field":"name","old":"A","new":"B"}]`, string(items[0].Changes))
+	assert.Nil(t, items[1].Changes)
+
+	dto := sync_dto.ToSyncRunDto(run)
+	assert.Equal(t, "sync_run", dto.Object)
+	assert.Equal(t, jobID.String(), *dto.JobID)
+	assert.Equal(t, map[string]int{"updated": 1, "unchanged": 1, "not_found": 1}, dto.Counts)
+	assert.Equal(t, []string{}, dto.Fields)
+
+	itemDtos := sync_dto.ToSyncRunItemDtos(items)
+	assert.Equal(t, "KJFK", itemDtos[0].ICAOCode)
+	assert.Equal(t, []sync_dto.FieldChangeDto{{Field: "name", Old: "A", New: "B"}}, itemDtos[0].Changes)
+}
+
+func TestToSyncRun_DefaultsToAPITrigger(t *testing.T) {
+	run, items := sync_dto.ToSyncRun(sync_dto.SyncAirportRequest{ICAOCodes: []string{"KJFK"}, DryRun: true}, nil, time.Now(), time.Now())
+
+	assert.Equal(t, "api", *run.Trigger)
+	assert.Equal(t, "insert", *run.Mode)
+	assert.True(t, *run.DryRun)
+	assert.Nil(t, run.JobID)
+	assert.Empty(t, items)
+	assert.JSONEq(t, `{}`, string(run.Counts))
+}
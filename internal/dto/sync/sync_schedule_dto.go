@@ -0,0 +1,39 @@
+package sync_dto
+
+import (
+	"flight-api/internal/model"
+	"time"
+)
+
+// SyncScheduleDto is a configured sync schedule, when it runs next and what
+// its last run did. AIRACCycle is the cycle the next run of an @airac
+// schedule refreshes for.
+type SyncScheduleDto struct {
+	Object        string     `json:"object"`
+	Name          string     `json:"name"`
+	Expression    string     `json:"expression"`
+	NextRunAt     *time.Time `json:"next_run_at"`
+	AIRACCycle    *string    `json:"airac_cycle,omitempty"`
+	LastRunAt     *time.Time `json:"last_run_at"`
+	LastOutcome   *string    `json:"last_outcome"`
+	LastMessage   *string    `json:"last_message"`
+	LastJobID     *string    `json:"last_job_id"`
+	LastJobStatus *string    `json:"last_job_status"`
+}
+
+// ToSyncScheduleDto fills in the last run of a schedule from its stored
+// state. The name, expression and next run come from the configuration.
+func ToSyncScheduleDto(schedule model.SyncSchedule) SyncScheduleDto {
+	dto := SyncScheduleDto{
+		Object:        "sync_schedule",
+		LastRunAt:     schedule.LastRunAt,
+		LastOutcome:   schedule.LastOutcome,
+		LastMessage:   schedule.LastMessage,
+		LastJobStatus: schedule.LastJobStatus,
+	}
+	if schedule.LastJobID != nil {
+		id := schedule.LastJobID.String()
+		dto.LastJobID = &id
+	}
+	return dto
+}
@@ -0,0 +1,116 @@
+package weather_dto
+
+import (
+	location_dto "flight-api/internal/dto/location"
+	"time"
+)
+
+// ForecastDto is a daily and hourly forecast. Its shape follows WeatherAPI's
+// forecast.json and reuses the condition and unit structures of the current
+// conditions.
+type ForecastDto struct {
+	Object     *string                   `json:"object"`
+	Location   *location_dto.LocationDto `json:"location"`
+	Current    *CurrentWeatherDto        `json:"current"`
+	Forecast   *ForecastPeriodsDto       `json:"forecast"`
+	Source     *string                   `json:"source"`
+	FetchedAt  *time.Time                `json:"fetched_at"`
+	Stale      bool                      `json:"stale,omitempty"`
+	AgeSeconds *int64                    `json:"age_seconds,omitempty"`
+}
+
+type ForecastPeriodsDto struct {
+	ForecastDay []ForecastDayDto `json:"forecastday"`
+}
+
+type ForecastDayDto struct {
+	Date      *string            `json:"date"`
+	DateEpoch *int               `json:"date_epoch"`
+	Day       *DayWeatherDto     `json:"day"`
+	Hour      []HourlyWeatherDto `json:"hour"`
+}
+
+// DayWeatherDto summarizes one forecast day.
+type DayWeatherDto struct {
+	MaxtempC          *float64      `json:"maxtemp_c"`
+	MaxtempF          *float64      `json:"maxtemp_f"`
+	MintempC          *float64      `json:"mintemp_c"`
+	MintempF          *float64      `json:"mintemp_f"`
+	AvgtempC          *float64      `json:"avgtemp_c"`
+	AvgtempF          *float64      `json:"avgtemp_f"`
+	MaxwindMph        *float64      `json:"maxwind_mph"`
+	MaxwindKph        *float64      `json:"maxwind_kph"`
+	TotalprecipMm     *float64      `json:"totalprecip_mm"`
+	TotalprecipIn     *float64      `json:"totalprecip_in"`
+	TotalsnowCm       *float64      `json:"totalsnow_cm"`
+	AvgvisKm          *float64      `json:"avgvis_km"`
+	AvgvisMiles       *float64      `json:"avgvis_miles"`
+	Avghumidity       *int          `json:"avghumidity"`
+	DailyWillItRain   *int          `json:"daily_will_it_rain"`
+	DailyChanceOfRain *int          `json:"daily_chance_of_rain"`
+	DailyWillItSnow   *int          `json:"daily_will_it_snow"`
+	DailyChanceOfSnow *int          `json:"daily_chance_of_snow"`
+	Condition         *ConditionDto `json:"condition"`
+	Uv                *float64      `json:"uv"`
+}
+
+// HourlyWeatherDto is the forecast for the hour starting at TimeEpoch.
+type HourlyWeatherDto struct {
+	TimeEpoch    *int          `json:"time_epoch"`
+	Time         *string       `json:"time"`
+	TempC        *float64      `json:"temp_c"`
+	TempF        *float64      `json:"temp_f"`
+	IsDay        *uint8        `json:"is_day"`
+	Condition    *ConditionDto `json:"condition"`
+	WindMph      *float64      `json:"wind_mph"`
+	WindKph      *float64      `json:"wind_kph"`
+	WindDegree   *int          `json:"wind_degree"`
+	WindDir      *string       `json:"wind_dir"`
+	PressureMb   *float64      `json:"pressure_mb"`
+	PressureIn   *float64      `json:"pressure_in"`
+	PrecipMm     *float64      `json:"precip_mm"`
+	PrecipIn     *float64      `json:"precip_in"`
+	SnowCm       *float64      `json:"snow_cm"`
+	Humidity     *int          `json:"humidity"`
+	Cloud        *int          `json:"cloud"`
+	FeelslikeC   *float64      `json:"feelslike_c"`
+	FeelslikeF   *float64      `json:"feelslike_f"`
+	WindchillC   *float64      `json:"windchill_c"`
+	WindchillF   *float64      `json:"windchill_f"`
+	HeatindexC   *float64      `json:"heatindex_c"`
+	HeatindexF   *float64      `json:"heatindex_f"`
+	DewpointC    *float64      `json:"dewpoint_c"`
+	DewpointF    *float64      `json:"dewpoint_f"`
+	WillItRain   *int          `json:"will_it_rain"`
+	ChanceOfRain *int          `json:"chance_of_rain"`
+	WillItSnow   *int          `json:"will_it_snow"`
+	ChanceOfSnow *int          `json:"chance_of_snow"`
+	VisKm        *float64      `json:"vis_km"`
+	VisMiles     *float64      `json:"vis_miles"`
+	GustMph      *float64      `json:"gust_mph"`
+	GustKph      *float64      `json:"gust_kph"`
+	Uv           *float64      `json:"uv"`
+}
+
+// HourAt returns the hourly period covering t, or nil when the forecast does
+// not reach t.
+func (f *ForecastDto) HourAt(t time.Time) *HourlyWeatherDto {
+	if f == nil || f.Forecast == nil {
+		return nil
+	}
+
+	unix := t.Unix()
+	for d := range f.Forecast.ForecastDay {
+		hours := f.Forecast.ForecastDay[d].Hour
+		for h := range hours {
+			if hours[h].TimeEpoch == nil {
+				continue
+			}
+			start := int64(*hours[h].TimeEpoch)
+			if unix >= start && unix < start+int64(time.Hour/time.Second) {
+				return &hours[h]
+			}
+		}
+	}
+	return nil
+}
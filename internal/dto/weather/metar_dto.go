@@ -0,0 +1,175 @@
+package weather_dto
+
+import (
+	"flight-api/pkg/metar"
+	"math"
+	"strings"
+)
+
+// MetarDecodeRequestDto is the body of POST /v1/weather/metar/decode.
+type MetarDecodeRequestDto struct {
+	Raw string `json:"raw" validate:"required"`
+}
+
+// MetarDecodeDto is a decoded report; exactly one of Metar and TAF is set,
+// matching Object.
+type MetarDecodeDto struct {
+	Object string       `json:"object"`
+	Metar  *metar.Metar `json:"metar,omitempty"`
+	TAF    *metar.TAF   `json:"taf,omitempty"`
+}
+
+// MetarIngestDto summarizes one batch of ingested reports.
+type MetarIngestDto struct {
+	Object   string                 `json:"object"`
+	Source   string                 `json:"source"`
+	Accepted int                    `json:"accepted"`
+	Stations []string               `json:"stations"`
+	Rejected []MetarRejectedLineDto `json:"rejected"`
+}
+
+// MetarRejectedLineDto is a report that could not be ingested.
+type MetarRejectedLineDto struct {
+	Raw   string `json:"raw"`
+	Error string `json:"error"`
+}
+
+const (
+	kphPerKnot = 1.852
+	mphPerKnot = 1.150779
+	kmPerMile  = 1.609344
+)
+
+// Cloud cover, in percent, reported for each METAR cover code.
+var metarCloudCover = map[string]int{
+	metar.CoverFew:       25,
+	metar.CoverScattered: 50,
+	metar.CoverBroken:    75,
+	metar.CoverOvercast:  100,
+}
+
+// ToCurrentWeatherDto converts a METAR into the current weather shape used by
+// the consumer providers, so airport responses keep one format whichever
+// source answered. Values the METAR does not report are left nil.
+func ToCurrentWeatherDto(m *metar.Metar) *CurrentWeatherDto {
+	current := &CurrentWeatherDto{
+		LastUpdatedEpoch: ptr(int(m.Time.Unix())),
+		LastUpdated:      ptr(m.Time.Format("2006-01-02 15:04")),
+		Condition:        &ConditionDto{Text: ptr(metarConditionText(m))},
+	}
+
+	temperature, dewpoint := m.Temperature()
+	if temperature != nil {
+		current.TempC = temperature
+		current.TempF = ptr(round1(*temperature*9/5 + 32))
+	}
+	if dewpoint != nil {
+		current.DewpointC = dewpoint
+		current.DewpointF = ptr(round1(*dewpoint*9/5 + 32))
+	}
+	if temperature != nil && dewpoint != nil {
+		current.Humidity = ptr(relativeHumidity(*temperature, *dewpoint))
+	}
+
+	if m.Wind != nil {
+		current.WindKph = ptr(round1(float64(m.Wind.SpeedKt) * kphPerKnot))
+		current.WindMph = ptr(round1(float64(m.Wind.SpeedKt) * mphPerKnot))
+		if m.Wind.DirectionDeg != nil && !m.Wind.Calm {
+			current.WindDegree = m.Wind.DirectionDeg
+			current.WindDir = ptr(compassPoint(*m.Wind.DirectionDeg))
+		}
+		if m.Wind.GustKt != nil {
+			current.GustKph = ptr(round1(float64(*m.Wind.GustKt) * kphPerKnot))
+			current.GustMph = ptr(round1(float64(*m.Wind.GustKt) * mphPerKnot))
+		}
+	}
+
+	if m.Altimeter != nil {
+		current.PressureMb = ptr(m.Altimeter.Hpa)
+		current.PressureIn = ptr(m.Altimeter.InHg)
+	}
+
+	switch {
+	case m.CAVOK:
+		current.VisKm = ptr(10.0)
+		current.VisMiles = ptr(round1(10 / kmPerMile))
+	case m.Visibility != nil:
+		miles := m.Visibility.Miles()
+		current.VisMiles = ptr(round1(miles))
+		current.VisKm = ptr(round1(miles * kmPerMile))
+	}
+
+	if cloud, ok := metarCloud(m); ok {
+		current.Cloud = ptr(cloud)
+	}
+
+	return current
+}
+
+// metarConditionText describes the present weather, or the sky when there
+// is none.
+func metarConditionText(m *metar.Metar) string {
+	if len(m.Weather) > 0 {
+		descriptions := make([]string, 0, len(m.Weather))
+		for _, w := range m.Weather {
+			descriptions = append(descriptions, w.Description)
+		}
+		text := strings.Join(descriptions, ", ")
+		return strings.ToUpper(text[:1]) + text[1:]
+	}
+
+	cloud, ok := metarCloud(m)
+	switch {
+	case !ok:
+		return "Unknown"
+	case m.VerticalVisibilityFt != nil:
+		return "Sky obscured"
+	case cloud == 0:
+		return "Clear"
+	case cloud <= 50:
+		return "Partly cloudy"
+	case cloud < 100:
+		return "Cloudy"
+	default:
+		return "Overcast"
+	}
+}
+
+// metarCloud returns the cover of the densest layer in percent.
+func metarCloud(m *metar.Metar) (int, bool) {
+	if m.CAVOK || m.SkyClear {
+		return 0, true
+	}
+	if m.VerticalVisibilityFt != nil {
+		return 100, true
+	}
+	if len(m.Clouds) == 0 {
+		return 0, false
+	}
+
+	cover := 0
+	for _, layer := range m.Clouds {
+		cover = max(cover, metarCloudCover[layer.Cover])
+	}
+	return cover, true
+}
+
+// relativeHumidity uses the Magnus approximation.
+func relativeHumidity(temperatureC, dewpointC float64) int {
+	const b, c = 17.625, 243.04
+	rh := 100 * math.Exp(b*dewpointC/(c+dewpointC)-b*temperatureC/(c+temperatureC))
+	return int(math.Round(math.Min(rh, 100)))
+}
+
+func compassPoint(degrees int) string {
+	points := []string{"N", "NNE", "NE", "ENE", "E", "ESE", "SE", "SSE", "S", "SSW", "SW", "WSW", "W", "WNW", "NW", "NNW"}
+	return points[int(math.Round(float64(degrees%360)/22.5))%len(points)]
+}
+
+func round1(v float64) float64 {
+	return math.Round(v*10) / 10
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
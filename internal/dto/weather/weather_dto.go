@@ -1,9 +1,16 @@
 package weather_dto
 
-import location_dto "flight-api/internal/dto/location"
+import (
+	location_dto "flight-api/internal/dto/location"
+	"time"
+)
 
 type WeatherDto struct {
-	Object   *string                   `json:"object"`
-	Location *location_dto.LocationDto `json:"location"`
-	Current  *CurrentWeatherDto        `json:"current"`
+	Object     *string                   `json:"object"`
+	Location   *location_dto.LocationDto `json:"location"`
+	Current    *CurrentWeatherDto        `json:"current"`
+	Source     *string                   `json:"source"`
+	FetchedAt  *time.Time                `json:"fetched_at"`
+	Stale      bool                      `json:"stale,omitempty"`
+	AgeSeconds *int64                    `json:"age_seconds,omitempty"`
 }
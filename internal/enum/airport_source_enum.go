@@ -0,0 +1,25 @@
+package enum
+
+// AirportSourceEnum names a provider of airport data. The importers record
+// it per field so the source priority can decide which provider wins.
+type AirportSourceEnum string
+
+const (
+	AIRPORT_SOURCE_NASR         AirportSourceEnum = "nasr"
+	AIRPORT_SOURCE_AVIATION_API AirportSourceEnum = "aviationapi"
+	AIRPORT_SOURCE_OURAIRPORTS  AirportSourceEnum = "ourairports"
+)
+
+func (s AirportSourceEnum) String() string {
+	return string(s)
+}
+
+// ToAirportSource reports the source named s, if it is one.
+func ToAirportSource(s string) (AirportSourceEnum, bool) {
+	switch source := AirportSourceEnum(s); source {
+	case AIRPORT_SOURCE_NASR, AIRPORT_SOURCE_AVIATION_API, AIRPORT_SOURCE_OURAIRPORTS:
+		return source, true
+	default:
+		return "", false
+	}
+}
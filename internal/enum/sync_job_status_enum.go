@@ -0,0 +1,27 @@
+package enum
+
+// SyncJobStatusEnum is the lifecycle of an asynchronous sync job. Queued and
+// running jobs are unfinished; the others are terminal.
+type SyncJobStatusEnum string
+
+const (
+	SYNC_JOB_QUEUED    SyncJobStatusEnum = "queued"
+	SYNC_JOB_RUNNING   SyncJobStatusEnum = "running"
+	SYNC_JOB_COMPLETED SyncJobStatusEnum = "completed"
+	SYNC_JOB_FAILED    SyncJobStatusEnum = "failed"
+	SYNC_JOB_CANCELED  SyncJobStatusEnum = "canceled"
+)
+
+// IsSyncJobStatus reports whether s names a job status.
+func IsSyncJobStatus(s string) bool {
+	switch SyncJobStatusEnum(s) {
+	case SYNC_JOB_QUEUED, SYNC_JOB_RUNNING, SYNC_JOB_COMPLETED, SYNC_JOB_FAILED, SYNC_JOB_CANCELED:
+		return true
+	default:
+		return false
+	}
+}
+
+func (s SyncJobStatusEnum) String() string {
+	return string(s)
+}
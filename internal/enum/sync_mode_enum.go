@@ -0,0 +1,15 @@
+package enum
+
+// SyncModeEnum selects what an airport sync does with ICAO codes that are
+// already stored: insert skips them as a conflict, refresh re-fetches them
+// and updates the fields that changed.
+type SyncModeEnum string
+
+const (
+	SYNC_MODE_INSERT  SyncModeEnum = "insert"
+	SYNC_MODE_REFRESH SyncModeEnum = "refresh"
+)
+
+func (m SyncModeEnum) String() string {
+	return string(m)
+}
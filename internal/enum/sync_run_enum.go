@@ -0,0 +1,108 @@
+package enum
+
+import "strings"
+
+// SyncRunTriggerEnum is what called for a sync run: a dry run requested
+// through the API, a sync job started through the API working through its
+// codes, or the job of a sync schedule.
+type SyncRunTriggerEnum string
+
+const (
+	SYNC_RUN_TRIGGER_API      SyncRunTriggerEnum = "api"
+	SYNC_RUN_TRIGGER_JOB      SyncRunTriggerEnum = "job"
+	SYNC_RUN_TRIGGER_SCHEDULE SyncRunTriggerEnum = "schedule"
+)
+
+func (t SyncRunTriggerEnum) String() string {
+	return string(t)
+}
+
+// IsSyncRunTrigger reports whether s is a trigger.
+func IsSyncRunTrigger(s string) bool {
+	switch SyncRunTriggerEnum(s) {
+	case SYNC_RUN_TRIGGER_API, SYNC_RUN_TRIGGER_JOB, SYNC_RUN_TRIGGER_SCHEDULE:
+		return true
+	default:
+		return false
+	}
+}
+
+// SyncRunOutcomeEnum is what a sync run did with one ICAO code, the status
+// of its sync response in snake case.
+type SyncRunOutcomeEnum string
+
+const (
+	SYNC_RUN_INSERTED     SyncRunOutcomeEnum = "inserted"
+	SYNC_RUN_UPDATED      SyncRunOutcomeEnum = "updated"
+	SYNC_RUN_UNCHANGED    SyncRunOutcomeEnum = "unchanged"
+	SYNC_RUN_WOULD_INSERT SyncRunOutcomeEnum = "would_insert"
+	SYNC_RUN_WOULD_UPDATE SyncRunOutcomeEnum = "would_update"
+	SYNC_RUN_CONFLICT     SyncRunOutcomeEnum = "conflict"
+	SYNC_RUN_ERROR        SyncRunOutcomeEnum = "error"
+	SYNC_RUN_INVALID      SyncRunOutcomeEnum = "invalid"
+	SYNC_RUN_NOT_FOUND    SyncRunOutcomeEnum = "not_found"
+	SYNC_RUN_SKIPPED      SyncRunOutcomeEnum = "skipped"
+	SYNC_RUN_ROLLED_BACK  SyncRunOutcomeEnum = "rolled_back"
+
+	// SYNC_RUN_FAILED filters on every outcome that left a code unsynced.
+	SYNC_RUN_FAILED SyncRunOutcomeEnum = "failed"
+)
+
+var syncRunOutcomes = []SyncRunOutcomeEnum{
+	SYNC_RUN_INSERTED, SYNC_RUN_UPDATED, SYNC_RUN_UNCHANGED, SYNC_RUN_WOULD_INSERT, SYNC_RUN_WOULD_UPDATE,
+	SYNC_RUN_CONFLICT, SYNC_RUN_ERROR, SYNC_RUN_INVALID, SYNC_RUN_NOT_FOUND, SYNC_RUN_SKIPPED, SYNC_RUN_ROLLED_BACK,
+}
+
+func (o SyncRunOutcomeEnum) String() string {
+	return string(o)
+}
+
+// IsFailure reports whether the code is worth syncing again: it failed,
+// or was skipped or rolled back because another code failed.
+func (o SyncRunOutcomeEnum) IsFailure() bool {
+	switch o {
+	case SYNC_RUN_ERROR, SYNC_RUN_INVALID, SYNC_RUN_NOT_FOUND, SYNC_RUN_SKIPPED, SYNC_RUN_ROLLED_BACK:
+		return true
+	default:
+		return false
+	}
+}
+
+// ToSyncRunOutcome turns a sync response status, such as "Not Found", into
+// its outcome.
+func ToSyncRunOutcome(status string) SyncRunOutcomeEnum {
+	return SyncRunOutcomeEnum(strings.ReplaceAll(strings.ToLower(strings.TrimSpace(status)), " ", "_"))
+}
+
+// SyncRunOutcomes lists every outcome a sync run records.
+func SyncRunOutcomes() []string {
+	outcomes := make([]string, 0, len(syncRunOutcomes))
+	for _, o := range syncRunOutcomes {
+		outcomes = append(outcomes, o.String())
+	}
+	return outcomes
+}
+
+// SyncRunFailureOutcomes lists the outcomes the failed filter covers.
+func SyncRunFailureOutcomes() []string {
+	outcomes := []string{}
+	for _, o := range syncRunOutcomes {
+		if o.IsFailure() {
+			outcomes = append(outcomes, o.String())
+		}
+	}
+	return outcomes
+}
+
+// IsSyncRunOutcome reports whether s is an outcome or the failed filter.
+func IsSyncRunOutcome(s string) bool {
+	if SyncRunOutcomeEnum(s) == SYNC_RUN_FAILED {
+		return true
+	}
+	for _, o := range syncRunOutcomes {
+		if o.String() == s {
+			return true
+		}
+	}
+	return false
+}
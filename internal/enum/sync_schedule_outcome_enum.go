@@ -0,0 +1,16 @@
+package enum
+
+// SyncScheduleOutcomeEnum is what a sync schedule did the last time it was
+// due: queued a sync job, skipped it with nothing to sync, or failed to
+// queue it. How the queued job went is its own status.
+type SyncScheduleOutcomeEnum string
+
+const (
+	SYNC_SCHEDULE_QUEUED  SyncScheduleOutcomeEnum = "queued"
+	SYNC_SCHEDULE_SKIPPED SyncScheduleOutcomeEnum = "skipped"
+	SYNC_SCHEDULE_FAILED  SyncScheduleOutcomeEnum = "failed"
+)
+
+func (o SyncScheduleOutcomeEnum) String() string {
+	return string(o)
+}
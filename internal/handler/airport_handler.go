@@ -14,4 +14,8 @@ type IAirportHandler interface {
 	Update(w http.ResponseWriter, r *http.Request)
 	Delete(w http.ResponseWriter, r *http.Request)
 	GetWeatherCondition(w http.ResponseWriter, r *http.Request)
+	GetAirportWeather(w http.ResponseWriter, r *http.Request)
+	GetAirportForecast(w http.ResponseWriter, r *http.Request)
+	GetWindComponents(w http.ResponseWriter, r *http.Request)
+	GetPerformanceConditions(w http.ResponseWriter, r *http.Request)
 }
@@ -9,6 +9,9 @@ import (
 	"flight-api/util"
 	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 )
@@ -35,7 +38,11 @@ func (h *AirportHandler) RegisterRouter(r chi.Router) {
 		r.Get("/{id}", h.FindByID)
 		r.Put("/{id}", h.Update)
 		r.Delete("/{id}", h.Delete)
-		// r.Get("/weathers", h.GetWeatherCondition)
+		r.Get("/{id}/weather", h.GetAirportWeather)
+		r.Get("/{id}/weather/forecast", h.GetAirportForecast)
+		r.Get("/{id}/wind-components", h.GetWindComponents)
+		r.Get("/{id}/performance-conditions", h.GetPerformanceConditions)
+		r.Get("/weathers", h.GetWeatherCondition)
 	}
 
 	// Airports Endpoints
@@ -45,12 +52,15 @@ func (h *AirportHandler) RegisterRouter(r chi.Router) {
 // Create Airport Data
 func (h *AirportHandler) Create(w http.ResponseWriter, r *http.Request) {
 	airportReq := airport_dto.AirportRequestDto{}
-	util.ReadFromRequestBody(r, &airportReq)
+	if err := util.ReadFromRequestBody(r, &airportReq); err != nil {
+		util.ErrorHandler(w, r, err)
+		return
+	}
 
 	airportResponse, err := h.airportService.Create(r.Context(), airportReq)
 	if err != nil {
 		h.logger.Errorf("[Create] Failed to create airport: %v", err)
-		util.ErrorHandler(w, err)
+		util.ErrorHandler(w, r, err)
 		return
 	}
 
@@ -71,7 +81,7 @@ func (h *AirportHandler) FindAll(w http.ResponseWriter, r *http.Request) {
 	airportResponses, err := h.airportService.FindAll(r.Context(), query)
 	if err != nil {
 		h.logger.Errorf("[FindAll] Failed to fetch airports: %v", err)
-		util.ErrorHandler(w, err)
+		util.ErrorHandler(w, r, err)
 		return
 	}
 
@@ -90,26 +100,8 @@ func (h *AirportHandler) FindByID(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 
 	airportResponse, err := h.airportService.FindByID(r.Context(), id)
-
 	if err != nil {
-		if err == util.ErrNotFound {
-			response := response_dto.ResponseDto{
-				Code:   http.StatusNotFound,
-				Status: "Not Found",
-				Data:   nil,
-			}
-			util.WriteToResponseBody(w, http.StatusNotFound, response)
-
-			return
-		}
-
-		response := response_dto.ResponseDto{
-			Code:   http.StatusInternalServerError,
-			Status: "Internal Server Error",
-			Data:   nil,
-		}
-		util.WriteToResponseBody(w, http.StatusInternalServerError, response)
-
+		util.ErrorHandler(w, r, err)
 		return
 	}
 
@@ -127,40 +119,15 @@ func (h *AirportHandler) Update(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 
 	airportUpdate := airport_dto.AirportUpdateDto{}
-	util.ReadFromRequestBody(r, &airportUpdate)
+	if err := util.ReadFromRequestBody(r, &airportUpdate); err != nil {
+		util.ErrorHandler(w, r, err)
+		return
+	}
 
 	airportResponse, err := h.airportService.Update(r.Context(), id, airportUpdate)
-
 	if err != nil {
-		switch {
-		case err == util.ErrBadRequest:
-			response := response_dto.ResponseDto{
-				Code:   http.StatusBadRequest,
-				Status: "Bad Request",
-				Data:   nil,
-			}
-			util.WriteToResponseBody(w, http.StatusBadRequest, response)
-
-			return
-		case err == util.ErrNotFound:
-			response := response_dto.ResponseDto{
-				Code:   http.StatusNotFound,
-				Status: "Not Found",
-				Data:   nil,
-			}
-			util.WriteToResponseBody(w, http.StatusNotFound, response)
-
-			return
-		default:
-			response := response_dto.ResponseDto{
-				Code:   http.StatusInternalServerError,
-				Status: "Internal Server Error",
-				Data:   nil,
-			}
-			util.WriteToResponseBody(w, http.StatusInternalServerError, response)
-
-			return
-		}
+		util.ErrorHandler(w, r, err)
+		return
 	}
 
 	response := response_dto.ResponseDto{
@@ -177,30 +144,9 @@ func (h *AirportHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 
 	err := h.airportService.Delete(r.Context(), id)
-
 	if err != nil {
-		switch {
-		case err == util.ErrNotFound:
-			response := response_dto.ResponseDto{
-				Code:    http.StatusNotFound,
-				Status:  "Not Found",
-				Data:    nil,
-				Message: fmt.Sprintf("Airport with ID %s not found", id),
-			}
-			util.WriteToResponseBody(w, http.StatusNotFound, response)
-
-			return
-		default:
-			response := response_dto.ResponseDto{
-				Code:    http.StatusInternalServerError,
-				Status:  "Internal Server Error",
-				Data:    nil,
-				Message: err.Error(),
-			}
-			util.WriteToResponseBody(w, http.StatusInternalServerError, response)
-
-			return
-		}
+		util.ErrorHandler(w, r, err)
+		return
 	}
 
 	h.logger.Debugf("Airport with ID %s deleted successfully", id)
@@ -218,7 +164,6 @@ func (h *AirportHandler) Delete(w http.ResponseWriter, r *http.Request) {
 func (h *AirportHandler) GetWeatherCondition(w http.ResponseWriter, r *http.Request) {
 	// Parse query parameter optional
 	var code, name string
-	var response response_dto.ResponseDto
 
 	query := queryparams.GetQueryParams(r)
 	code = r.URL.Query().Get("code")
@@ -226,53 +171,159 @@ func (h *AirportHandler) GetWeatherCondition(w http.ResponseWriter, r *http.Requ
 
 	if code == "" && name == "" {
 		// Should has value
-		response = response_dto.ResponseDto{
-			Code:    http.StatusBadRequest,
-			Status:  "Bad Request",
-			Data:    nil,
-			Message: "neither 'code' or 'name' query parameter is required",
-		}
-
-		util.WriteToResponseBody(w, http.StatusBadRequest, response)
+		util.ErrorHandler(w, r, util.NewAppError(util.ErrBadRequest, "Either 'code' or 'name' query parameter is required", nil))
 		return
 	} else if code != "" && name != "" {
 		// can't has value at the same time
-		response = response_dto.ResponseDto{
-			Code:    http.StatusBadRequest,
-			Status:  "Bad Request",
-			Data:    nil,
-			Message: "'code' and 'name' query parameter can't be used at the same time",
-		}
+		util.ErrorHandler(w, r, util.NewAppError(util.ErrBadRequest, "'code' and 'name' query parameter can't be used at the same time", nil))
+		return
+	}
 
-		util.WriteToResponseBody(w, http.StatusBadRequest, response)
+	// Optional flight category filter, e.g. category=IFR,LIFR
+	var categories []string
+	if category := r.URL.Query().Get("category"); category != "" {
+		categories = strings.Split(category, ",")
 	}
 
 	// Call service
-	data, err := h.airportService.GetWeatherCondition(r.Context(), code, name, query)
-
-	switch {
-	case err == nil:
-		response = response_dto.ResponseDto{
-			Code:    http.StatusOK,
-			Status:  "OK",
-			Data:    data,
-			Message: "Success",
-		}
-	case err == util.ErrNotFound:
-		response = response_dto.ResponseDto{
-			Code:    http.StatusNotFound,
-			Status:  "Not Found",
-			Data:    nil,
-			Message: "Airport and weather data not found",
+	data, err := h.airportService.GetWeatherCondition(r.Context(), code, name, query, categories)
+	if err != nil {
+		util.ErrorHandler(w, r, err)
+		return
+	}
+
+	response := response_dto.ResponseDto{
+		Code:    http.StatusOK,
+		Status:  "OK",
+		Data:    data,
+		Message: "Success",
+	}
+
+	util.WriteToResponseBody(w, http.StatusOK, response)
+}
+
+// GetAirportWeather returns the current weather at one airport
+func (h *AirportHandler) GetAirportWeather(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	data, err := h.airportService.GetAirportWeather(r.Context(), id)
+	if err != nil {
+		util.ErrorHandler(w, r, err)
+		return
+	}
+
+	response := response_dto.ResponseDto{
+		Code:    http.StatusOK,
+		Status:  "OK",
+		Data:    data,
+		Message: "Success",
+	}
+
+	util.WriteToResponseBody(w, http.StatusOK, response)
+}
+
+// GetAirportForecast returns the forecast at one airport, optionally with the
+// conditions at a given time (?at=RFC 3339 timestamp)
+func (h *AirportHandler) GetAirportForecast(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	days, err := forecastDays(r)
+	if err != nil {
+		util.ErrorHandler(w, r, err)
+		return
+	}
+
+	var at *time.Time
+	if value := r.URL.Query().Get("at"); value != "" {
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			util.ErrorHandler(w, r, util.NewAppError(util.ErrBadRequest, "'at' must be an RFC 3339 timestamp", err))
+			return
 		}
-	default:
-		response = response_dto.ResponseDto{
-			Code:    http.StatusInternalServerError,
-			Status:  "Internal Server Error",
-			Data:    nil,
-			Message: err.Error(),
+		at = &parsed
+	}
+
+	data, err := h.airportService.GetAirportForecast(r.Context(), id, days, at)
+	if err != nil {
+		util.ErrorHandler(w, r, err)
+		return
+	}
+
+	response := response_dto.ResponseDto{
+		Code:    http.StatusOK,
+		Status:  "OK",
+		Data:    data,
+		Message: "Success",
+	}
+
+	util.WriteToResponseBody(w, http.StatusOK, response)
+}
+
+// GetWindComponents returns the current wind at one airport resolved along
+// runways, given as ?runway_heading=040,220 or repeated parameters. Without
+// headings the airport's stored runways are used.
+func (h *AirportHandler) GetWindComponents(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	headings, err := runwayHeadings(r)
+	if err != nil {
+		util.ErrorHandler(w, r, err)
+		return
+	}
+
+	data, err := h.airportService.GetWindComponents(r.Context(), id, headings)
+	if err != nil {
+		util.ErrorHandler(w, r, err)
+		return
+	}
+
+	response := response_dto.ResponseDto{
+		Code:    http.StatusOK,
+		Status:  "OK",
+		Data:    data,
+		Message: "Success",
+	}
+
+	util.WriteToResponseBody(w, http.StatusOK, response)
+}
+
+// runwayHeadings reads every runway_heading value, splitting comma lists.
+func runwayHeadings(r *http.Request) ([]int, error) {
+	var headings []int
+	for _, value := range r.URL.Query()["runway_heading"] {
+		for _, part := range strings.Split(value, ",") {
+			part = strings.TrimSpace(part)
+			if part == "" {
+				continue
+			}
+			heading, err := strconv.Atoi(part)
+			if err != nil {
+				detail := fmt.Sprintf("'runway_heading' must be whole degrees, got %q", part)
+				return nil, util.NewAppError(util.ErrBadRequest, detail, err)
+			}
+			headings = append(headings, heading)
 		}
 	}
+	return headings, nil
+}
+
+// GetPerformanceConditions returns the pressure and density altitude at one
+// airport
+func (h *AirportHandler) GetPerformanceConditions(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	data, err := h.airportService.GetPerformanceConditions(r.Context(), id)
+	if err != nil {
+		util.ErrorHandler(w, r, err)
+		return
+	}
+
+	response := response_dto.ResponseDto{
+		Code:    http.StatusOK,
+		Status:  "OK",
+		Data:    data,
+		Message: "Success",
+	}
 
 	util.WriteToResponseBody(w, http.StatusOK, response)
 }
@@ -0,0 +1,14 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type IMetarHandler interface {
+	RegisterRouter(r chi.Router)
+	Decode(w http.ResponseWriter, r *http.Request)
+	Ingest(w http.ResponseWriter, r *http.Request)
+	GetLatest(w http.ResponseWriter, r *http.Request)
+}
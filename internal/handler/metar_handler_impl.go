@@ -0,0 +1,107 @@
+package handler
+
+import (
+	response_dto "flight-api/internal/dto/response"
+	weather_dto "flight-api/internal/dto/weather"
+	service_metar "flight-api/internal/service/metar"
+	"flight-api/pkg/logger"
+	"flight-api/util"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// maxIngestBytes bounds a pushed batch of reports.
+const maxIngestBytes = 1 << 20
+
+type MetarHandler struct {
+	service service_metar.IMetarService
+	logger  *logger.Logger
+}
+
+func NewMetarHandler(service service_metar.IMetarService, logger *logger.Logger) IMetarHandler {
+	return &MetarHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+func (h *MetarHandler) RegisterRouter(r chi.Router) {
+	routes := func(r chi.Router) {
+		r.Post("/decode", h.Decode)
+		r.Post("/ingest", h.Ingest)
+		r.Get("/{station}", h.GetLatest)
+	}
+
+	// METAR Endpoint
+	r.Route("/v1/weather/metar", routes)
+}
+
+// Decode decodes a raw METAR, SPECI or TAF without storing it
+func (h *MetarHandler) Decode(w http.ResponseWriter, r *http.Request) {
+	var req weather_dto.MetarDecodeRequestDto
+	if err := util.ReadFromRequestBody(r, &req); err != nil {
+		util.ErrorHandler(w, r, err)
+		return
+	}
+
+	data, err := h.service.Decode(r.Context(), req.Raw)
+	if err != nil {
+		util.ErrorHandler(w, r, err)
+		return
+	}
+
+	response := response_dto.ResponseDto{
+		Code:    http.StatusOK,
+		Status:  "OK",
+		Data:    data,
+		Message: "Success",
+	}
+
+	util.WriteToResponseBody(w, http.StatusOK, response)
+}
+
+// Ingest stores pushed METAR reports, sent as plain text with one report per
+// line. Reports that fail to decode are listed in the response.
+func (h *MetarHandler) Ingest(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxIngestBytes))
+	if err != nil {
+		util.ErrorHandler(w, r, util.NewAppError(util.ErrBadRequest, "Failed to read request body", err))
+		return
+	}
+	if strings.TrimSpace(string(body)) == "" {
+		util.ErrorHandler(w, r, util.NewAppError(util.ErrBadRequest, "Request body must contain at least one METAR report", nil))
+		return
+	}
+
+	data := h.service.Ingest(r.Context(), "push", string(body))
+
+	response := response_dto.ResponseDto{
+		Code:    http.StatusOK,
+		Status:  "OK",
+		Data:    data,
+		Message: "Success",
+	}
+
+	util.WriteToResponseBody(w, http.StatusOK, response)
+}
+
+// GetLatest returns the newest ingested METAR for a station
+func (h *MetarHandler) GetLatest(w http.ResponseWriter, r *http.Request) {
+	data, err := h.service.Latest(r.Context(), chi.URLParam(r, "station"))
+	if err != nil {
+		util.ErrorHandler(w, r, err)
+		return
+	}
+
+	response := response_dto.ResponseDto{
+		Code:    http.StatusOK,
+		Status:  "OK",
+		Data:    data,
+		Message: "Success",
+	}
+
+	util.WriteToResponseBody(w, http.StatusOK, response)
+}
@@ -0,0 +1,12 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type IStatusHandler interface {
+	RegisterRouter(r chi.Router)
+	GetStatus(w http.ResponseWriter, r *http.Request)
+}
@@ -0,0 +1,49 @@
+package handler
+
+import (
+	response_dto "flight-api/internal/dto/response"
+	status_dto "flight-api/internal/dto/status"
+	service_status "flight-api/internal/service/status"
+	"flight-api/pkg/logger"
+	"flight-api/util"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type StatusHandler struct {
+	service service_status.IStatusService
+	logger  *logger.Logger
+}
+
+func NewStatusHandler(service service_status.IStatusService, logger *logger.Logger) IStatusHandler {
+	return &StatusHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+func (h *StatusHandler) RegisterRouter(r chi.Router) {
+	// Status Endpoint
+	r.Get("/v1/status", h.GetStatus)
+}
+
+// GetStatus reports the state of every dependency. It answers 503 only when
+// the API cannot serve requests at all; a degraded upstream is still 200.
+func (h *StatusHandler) GetStatus(w http.ResponseWriter, r *http.Request) {
+	data := h.service.GetStatus(r.Context())
+
+	response := response_dto.ResponseDto{
+		Code:    http.StatusOK,
+		Status:  "OK",
+		Data:    data,
+		Message: "Success",
+	}
+	if data.Status == status_dto.StatusDown {
+		response.Code = http.StatusServiceUnavailable
+		response.Status = http.StatusText(http.StatusServiceUnavailable)
+		response.Message = "One or more required dependencies are down"
+	}
+
+	util.WriteToResponseBody(w, response.Code, response)
+}
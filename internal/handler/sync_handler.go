@@ -9,4 +9,10 @@ import (
 type ISyncHandler interface {
 	RegisterRouter(r chi.Router)
 	SyncAirport(w http.ResponseWriter, r *http.Request)
+	FindAllJobs(w http.ResponseWriter, r *http.Request)
+	FindJobByID(w http.ResponseWriter, r *http.Request)
+	CancelJob(w http.ResponseWriter, r *http.Request)
+	FindAllSchedules(w http.ResponseWriter, r *http.Request)
+	FindAllRuns(w http.ResponseWriter, r *http.Request)
+	FindRunByID(w http.ResponseWriter, r *http.Request)
 }
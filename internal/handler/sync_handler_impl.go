@@ -1,98 +1,281 @@
 package handler
 
 import (
+	queryparams "flight-api/internal/dto/query_params"
 	response_dto "flight-api/internal/dto/response"
 	sync_dto "flight-api/internal/dto/sync"
+	"flight-api/internal/enum"
 	service_sync "flight-api/internal/service/sync"
 	"flight-api/pkg/logger"
 	"flight-api/util"
+	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
 )
 
 type SyncHandler struct {
-	service service_sync.ISyncService
-	logger  *logger.Logger
+	service         service_sync.ISyncService
+	jobService      service_sync.ISyncJobService
+	scheduleService service_sync.ISyncScheduleService
+	runService      service_sync.ISyncRunService
+	logger          *logger.Logger
 }
 
-func NewSyncHandler(service service_sync.ISyncService, logger *logger.Logger) ISyncHandler {
+func NewSyncHandler(service service_sync.ISyncService, jobService service_sync.ISyncJobService, scheduleService service_sync.ISyncScheduleService, runService service_sync.ISyncRunService, logger *logger.Logger) ISyncHandler {
 	return &SyncHandler{
-		service: service,
-		logger:  logger,
+		service:         service,
+		jobService:      jobService,
+		scheduleService: scheduleService,
+		runService:      runService,
+		logger:          logger,
 	}
 }
 
 // RegisterRoutes
 func (h *SyncHandler) RegisterRouter(r chi.Router) {
 	routes := func(r chi.Router) {
-		// Sync Airport Data
-		// r.Post("/airports", h.SyncAirport)
+		// Sync Airport Data, run as a background job
+		r.Post("/airports", h.SyncAirport)
+		r.Get("/jobs", h.FindAllJobs)
+		r.Get("/jobs/{id}", h.FindJobByID)
+		r.Delete("/jobs/{id}", h.CancelJob)
+		r.Get("/schedules", h.FindAllSchedules)
+		r.Get("/runs", h.FindAllRuns)
+		r.Get("/runs/{id}", h.FindRunByID)
 	}
 
 	// Sync Endpoints
 	r.Route("/v1/sync", routes)
 }
 
+// SyncAirport queues a sync job and returns it without waiting for it to run.
+// A dry run (?dry_run=true or "dry_run" in the body) runs right away and
+// returns what the sync would do, without writing anything.
 func (h *SyncHandler) SyncAirport(w http.ResponseWriter, r *http.Request) {
 	// Parse body
 	var req sync_dto.SyncAirportRequest
-	util.ReadFromRequestBody(r, &req)
+	if err := util.ReadFromRequestBody(r, &req); err != nil {
+		util.ErrorHandler(w, r, err)
+		return
+	}
+
+	if value := r.URL.Query().Get("dry_run"); value != "" {
+		dryRun, err := strconv.ParseBool(value)
+		if err != nil {
+			util.ErrorHandler(w, r, util.NewAppError(util.ErrBadRequest, "'dry_run' must be true or false", err))
+			return
+		}
+		req.DryRun = dryRun
+	}
+
+	if req.DryRun {
+		h.previewSync(w, r, req)
+		return
+	}
 
 	// Call service
+	job, err := h.jobService.Start(r.Context(), req)
+	if err != nil {
+		h.logger.Error("Failed to start sync job: ", err)
+		util.ErrorHandler(w, r, err)
+		return
+	}
+
+	h.logger.Debugf("Sync job %s accepted", job.ID)
+
+	// Response (202 Accepted)
+	response := response_dto.ResponseDto{
+		Code:    http.StatusAccepted,
+		Status:  "Accepted",
+		Data:    job,
+		Message: "Sync job accepted",
+	}
+
+	w.Header().Set("Location", fmt.Sprintf("/v1/sync/jobs/%s", job.ID))
+	util.WriteToResponseBody(w, http.StatusAccepted, response)
+}
+
+func (h *SyncHandler) previewSync(w http.ResponseWriter, r *http.Request, req sync_dto.SyncAirportRequest) {
 	data, err := h.service.SyncAirports(r.Context(), req)
 	if err != nil {
-		h.logger.Error("Failed to sync airports: ", err)
-
-		switch err {
-		case util.ErrBadRequest:
-			response := response_dto.ResponseDto{
-				Code:    http.StatusBadRequest,
-				Status:  "Bad Request",
-				Data:    err.Error(),
-				Message: err.Error(),
-			}
-			util.WriteToResponseBody(w, http.StatusBadRequest, response)
-			return
-		case util.ErrGatewayTimeout:
-			response := response_dto.ResponseDto{
-				Code:    http.StatusGatewayTimeout,
-				Status:  "Gateway Timeout",
-				Data:    err.Error(),
-				Message: err.Error(),
-			}
-			util.WriteToResponseBody(w, http.StatusGatewayTimeout, response)
-			return
-		case util.ErrNotFound:
-			response := response_dto.ResponseDto{
-				Code:    http.StatusNotFound,
-				Status:  "Not Found",
-				Data:    err.Error(),
-				Message: err.Error(),
-			}
-			util.WriteToResponseBody(w, http.StatusNotFound, response)
-			return
-		default:
-			response := response_dto.ResponseDto{
-				Code:    http.StatusInternalServerError,
-				Status:  "Internal Server Error",
-				Data:    err.Error(),
-				Message: err.Error(),
-			}
-			util.WriteToResponseBody(w, http.StatusInternalServerError, response)
-			return
-		}
+		h.logger.Error("Failed to preview airport sync: ", err)
+		util.ErrorHandler(w, r, err)
+		return
+	}
+
+	response := response_dto.ResponseDto{
+		Code:    http.StatusOK,
+		Status:  "OK",
+		Data:    data,
+		Message: "Dry run, no changes were written",
+	}
+
+	util.WriteToResponseBody(w, http.StatusOK, response)
+}
+
+// FindAllJobs lists sync jobs, optionally filtered by ?status=
+func (h *SyncHandler) FindAllJobs(w http.ResponseWriter, r *http.Request) {
+	query := queryparams.GetQueryParams(r)
+
+	status := r.URL.Query().Get("status")
+	if status != "" && !enum.IsSyncJobStatus(status) {
+		detail := fmt.Sprintf("'status' must be one of queued, running, completed, failed or canceled, got %q", status)
+		util.ErrorHandler(w, r, util.NewAppError(util.ErrBadRequest, detail, nil))
+		return
+	}
+
+	data, err := h.jobService.FindAll(r.Context(), query, status)
+	if err != nil {
+		util.ErrorHandler(w, r, err)
+		return
+	}
+
+	response := response_dto.ResponseDto{
+		Code:   http.StatusOK,
+		Status: "OK",
+		Data:   data,
+	}
+
+	util.WriteToResponseBody(w, http.StatusOK, response)
+}
+
+// FindJobByID reports a sync job's progress and results
+func (h *SyncHandler) FindJobByID(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	data, err := h.jobService.FindByID(r.Context(), id)
+	if err != nil {
+		util.ErrorHandler(w, r, err)
+		return
+	}
+
+	response := response_dto.ResponseDto{
+		Code:   http.StatusOK,
+		Status: "OK",
+		Data:   data,
 	}
 
-	h.logger.Debug("Successfully synced airports")
+	util.WriteToResponseBody(w, http.StatusOK, response)
+}
+
+// CancelJob cancels a queued or running sync job
+func (h *SyncHandler) CancelJob(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	data, err := h.jobService.Cancel(r.Context(), id)
+	if err != nil {
+		util.ErrorHandler(w, r, err)
+		return
+	}
 
-	// Return response
 	response := response_dto.ResponseDto{
-		Code:    200,
+		Code:    http.StatusOK,
 		Status:  "OK",
 		Data:    data,
-		Message: "Successfully synced airports",
+		Message: fmt.Sprintf("Sync job with ID %s canceled", id),
+	}
+
+	util.WriteToResponseBody(w, http.StatusOK, response)
+}
+
+// FindAllSchedules lists the configured sync schedules with their next run
+// and the outcome of their last one
+func (h *SyncHandler) FindAllSchedules(w http.ResponseWriter, r *http.Request) {
+	data, err := h.scheduleService.FindAll(r.Context())
+	if err != nil {
+		util.ErrorHandler(w, r, err)
+		return
+	}
+
+	response := response_dto.ResponseDto{
+		Code:   http.StatusOK,
+		Status: "OK",
+		Data:   data,
+	}
+
+	util.WriteToResponseBody(w, http.StatusOK, response)
+}
+
+// FindAllRuns lists past sync runs, newest first. ?outcome= keeps the runs
+// with at least one airport in that outcome; "failed" matches any failure.
+// ?trigger= and ?job_id= keep the runs started one way or by one job.
+func (h *SyncHandler) FindAllRuns(w http.ResponseWriter, r *http.Request) {
+	query := queryparams.GetQueryParams(r)
+
+	outcome, ok := h.runOutcome(w, r)
+	if !ok {
+		return
+	}
+
+	filter := sync_dto.SyncRunFilter{
+		Outcome: outcome,
+		Trigger: r.URL.Query().Get("trigger"),
+		JobID:   r.URL.Query().Get("job_id"),
+	}
+	if filter.Trigger != "" && !enum.IsSyncRunTrigger(filter.Trigger) {
+		detail := fmt.Sprintf("'trigger' must be one of api, job or schedule, got %q", filter.Trigger)
+		util.ErrorHandler(w, r, util.NewAppError(util.ErrBadRequest, detail, nil))
+		return
+	}
+	if filter.JobID != "" {
+		if _, err := uuid.Parse(filter.JobID); err != nil {
+			detail := fmt.Sprintf("'job_id' must be a UUID, got %q", filter.JobID)
+			util.ErrorHandler(w, r, util.NewAppError(util.ErrBadRequest, detail, err))
+			return
+		}
+	}
+
+	data, err := h.runService.FindAll(r.Context(), query, filter)
+	if err != nil {
+		util.ErrorHandler(w, r, err)
+		return
+	}
+
+	response := response_dto.ResponseDto{
+		Code:   http.StatusOK,
+		Status: "OK",
+		Data:   data,
+	}
+
+	util.WriteToResponseBody(w, http.StatusOK, response)
+}
+
+// FindRunByID returns a sync run with its per-airport results, optionally
+// narrowed by ?outcome=
+func (h *SyncHandler) FindRunByID(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	outcome, ok := h.runOutcome(w, r)
+	if !ok {
+		return
+	}
+
+	data, err := h.runService.FindByID(r.Context(), id, outcome)
+	if err != nil {
+		util.ErrorHandler(w, r, err)
+		return
+	}
+
+	response := response_dto.ResponseDto{
+		Code:   http.StatusOK,
+		Status: "OK",
+		Data:   data,
 	}
 
 	util.WriteToResponseBody(w, http.StatusOK, response)
 }
+
+func (h *SyncHandler) runOutcome(w http.ResponseWriter, r *http.Request) (string, bool) {
+	outcome := r.URL.Query().Get("outcome")
+	if outcome != "" && !enum.IsSyncRunOutcome(outcome) {
+		detail := fmt.Sprintf("'outcome' must be one of %s or %s, got %q",
+			strings.Join(enum.SyncRunOutcomes(), ", "), enum.SYNC_RUN_FAILED, outcome)
+		util.ErrorHandler(w, r, util.NewAppError(util.ErrBadRequest, detail, nil))
+		return "", false
+	}
+	return outcome, true
+}
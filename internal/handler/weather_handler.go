@@ -9,4 +9,5 @@ import (
 type IWeatherHandler interface {
 	RegisterRouter(r chi.Router)
 	GetWeatherCondition(w http.ResponseWriter, r *http.Request)
+	GetWeatherForecast(w http.ResponseWriter, r *http.Request)
 }
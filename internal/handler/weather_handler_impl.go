@@ -5,7 +5,9 @@ import (
 	service_weather "flight-api/internal/service/weather"
 	"flight-api/pkg/logger"
 	"flight-api/util"
+	"fmt"
 	"net/http"
+	"strconv"
 
 	"github.com/go-chi/chi/v5"
 )
@@ -25,6 +27,7 @@ func NewWeatherHandler(service service_weather.IWeatherService, logger *logger.L
 func (h *WeatherHandler) RegisterRouter(r chi.Router) {
 	routes := func(r chi.Router) {
 		r.Get("/", h.GetWeatherCondition)
+		r.Get("/forecast", h.GetWeatherForecast)
 	}
 
 	// weather Endpoint
@@ -34,17 +37,10 @@ func (h *WeatherHandler) RegisterRouter(r chi.Router) {
 func (h *WeatherHandler) GetWeatherCondition(w http.ResponseWriter, r *http.Request) {
 	// Parse query parameter
 	var loc string
-	var response response_dto.ResponseDto
 	loc = r.URL.Query().Get("loc")
 
 	if loc == "" {
-		response = response_dto.ResponseDto{
-			Code:    http.StatusBadRequest,
-			Status:  "Bad Request",
-			Data:    nil,
-			Message: "'loc' query parameter is required",
-		}
-		util.WriteToResponseBody(w, http.StatusBadRequest, response)
+		util.ErrorHandler(w, r, util.NewAppError(util.ErrBadRequest, "'loc' query parameter is required", nil))
 		return
 	}
 
@@ -52,51 +48,12 @@ func (h *WeatherHandler) GetWeatherCondition(w http.ResponseWriter, r *http.Requ
 	data, err := h.service.GetWeatherCondition(r.Context(), &loc)
 	if err != nil {
 		h.logger.Errorf("Failed to get weather condition: %v", err)
-
-		switch err {
-		case util.ErrUnauthorized:
-			response = response_dto.ResponseDto{
-				Code:    http.StatusUnauthorized,
-				Status:  "Unauthorized",
-				Data:    nil,
-				Message: "Unauthorized",
-			}
-		case util.ErrNotFound:
-			response = response_dto.ResponseDto{
-				Code:    http.StatusNotFound,
-				Status:  "Not Found",
-				Data:    nil,
-				Message: "No matching location found",
-			}
-		case util.ErrBadRequest:
-			response = response_dto.ResponseDto{
-				Code:    http.StatusBadRequest,
-				Status:  "Bad Request",
-				Data:    nil,
-				Message: "Bad Request",
-			}
-		case util.ErrGatewayTimeout:
-			response = response_dto.ResponseDto{
-				Code:    http.StatusGatewayTimeout,
-				Status:  "Gateway Timeout",
-				Data:    nil,
-				Message: "Gateway Timeout",
-			}
-		default:
-			response = response_dto.ResponseDto{
-				Code:    http.StatusInternalServerError,
-				Status:  "Internal Server Error",
-				Data:    nil,
-				Message: err.Error(),
-			}
-		}
-
-		util.WriteToResponseBody(w, response.Code, response)
+		util.ErrorHandler(w, r, err)
 		return
 	}
 
 	// Return response
-	response = response_dto.ResponseDto{
+	response := response_dto.ResponseDto{
 		Code:    200,
 		Status:  "OK",
 		Data:    data,
@@ -105,3 +62,53 @@ func (h *WeatherHandler) GetWeatherCondition(w http.ResponseWriter, r *http.Requ
 
 	util.WriteToResponseBody(w, http.StatusOK, response)
 }
+
+// GetWeatherForecast returns the daily and hourly forecast for a location
+func (h *WeatherHandler) GetWeatherForecast(w http.ResponseWriter, r *http.Request) {
+	loc := r.URL.Query().Get("loc")
+	if loc == "" {
+		util.ErrorHandler(w, r, util.NewAppError(util.ErrBadRequest, "'loc' query parameter is required", nil))
+		return
+	}
+
+	days, err := forecastDays(r)
+	if err != nil {
+		util.ErrorHandler(w, r, err)
+		return
+	}
+	if days == 0 {
+		days = service_weather.DefaultForecastDays
+	}
+
+	data, err := h.service.GetWeatherForecast(r.Context(), &loc, days)
+	if err != nil {
+		h.logger.Errorf("[GetWeatherForecast] Failed to get weather forecast: %v", err)
+		util.ErrorHandler(w, r, err)
+		return
+	}
+
+	response := response_dto.ResponseDto{
+		Code:    http.StatusOK,
+		Status:  "OK",
+		Data:    data,
+		Message: "Success",
+	}
+
+	util.WriteToResponseBody(w, http.StatusOK, response)
+}
+
+// forecastDays reads the optional 'days' query parameter; zero means it was
+// not given.
+func forecastDays(r *http.Request) (int, error) {
+	value := r.URL.Query().Get("days")
+	if value == "" {
+		return 0, nil
+	}
+
+	days, err := strconv.Atoi(value)
+	if err != nil || days < service_weather.MinForecastDays || days > service_weather.MaxForecastDays {
+		detail := fmt.Sprintf("'days' must be an integer between %d and %d", service_weather.MinForecastDays, service_weather.MaxForecastDays)
+		return 0, util.NewAppError(util.ErrBadRequest, detail, nil)
+	}
+	return days, nil
+}
@@ -29,12 +29,14 @@ type Airport struct {
 	Longitude     *string    `db:"longitude"`
 	LongitudeSec  *string    `db:"longitude_sec"`
 	Elevation     *int64     `db:"elevation"`
+	MagVariation  *string    `db:"magnetic_variation"`
 	ControlTower  *bool      `db:"control_tower"`
 	Unicom        *string    `db:"unicom"`
 	CTAF          *string    `db:"ctaf"`
 	EffectiveDate *time.Time `db:"effective_date"`
 	SyncStatus    *int64     `db:"sync_status"`
 	SyncMessage   *string    `db:"sync_message"`
+	SyncAttempts  *int64     `db:"sync_attempts"`
 	CreatedAt     *time.Time `db:"created_at"`
 	UpdatedAt     *time.Time `db:"updated_at"`
 }
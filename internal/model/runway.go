@@ -0,0 +1,21 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Runway is one runway end; both ends of a strip are stored separately.
+type Runway struct {
+	ID              *uuid.UUID `db:"id"`
+	AirportID       *uuid.UUID `db:"airport_id"`
+	Ident           *string    `db:"ident"`
+	MagneticHeading *int64     `db:"magnetic_heading"`
+	TrueHeading     *int64     `db:"true_heading"`
+	LengthFt        *int64     `db:"length_ft"`
+	WidthFt         *int64     `db:"width_ft"`
+	Surface         *string    `db:"surface"`
+	CreatedAt       *time.Time `db:"created_at"`
+	UpdatedAt       *time.Time `db:"updated_at"`
+}
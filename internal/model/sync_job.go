@@ -0,0 +1,28 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SyncJob is an asynchronous airport sync. Results is the JSON encoded list
+// of per-ICAO outcomes processed so far. Trigger is what started the job,
+// the API or a sync schedule.
+type SyncJob struct {
+	ID         *uuid.UUID `db:"id"`
+	Status     *string    `db:"status"`
+	ICAOCodes  []string   `db:"icao_codes"`
+	Mode       *string    `db:"mode"`
+	Fields     []string   `db:"fields"`
+	Atomic     *bool      `db:"atomic"`
+	Trigger    *string    `db:"trigger"`
+	Total      *int64     `db:"total"`
+	Processed  *int64     `db:"processed"`
+	Results    []byte     `db:"results"`
+	Error      *string    `db:"error"`
+	CreatedAt  *time.Time `db:"created_at"`
+	StartedAt  *time.Time `db:"started_at"`
+	FinishedAt *time.Time `db:"finished_at"`
+	UpdatedAt  *time.Time `db:"updated_at"`
+}
@@ -0,0 +1,39 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SyncRun is one airport sync run. Counts is the JSON encoded number of
+// codes per outcome.
+type SyncRun struct {
+	ID         *uuid.UUID `db:"id"`
+	Trigger    *string    `db:"trigger"`
+	JobID      *uuid.UUID `db:"job_id"`
+	ICAOCodes  []string   `db:"icao_codes"`
+	Mode       *string    `db:"mode"`
+	Fields     []string   `db:"fields"`
+	DryRun     *bool      `db:"dry_run"`
+	Atomic     *bool      `db:"atomic"`
+	Total      *int64     `db:"total"`
+	Failed     *int64     `db:"failed"`
+	Counts     []byte     `db:"counts"`
+	StartedAt  *time.Time `db:"started_at"`
+	FinishedAt *time.Time `db:"finished_at"`
+	CreatedAt  *time.Time `db:"created_at"`
+}
+
+// SyncRunItem is the outcome of one ICAO code in a run. Changes is the
+// JSON encoded list of fields a refresh changed.
+type SyncRunItem struct {
+	ID       *uuid.UUID `db:"id"`
+	RunID    *uuid.UUID `db:"run_id"`
+	Position *int64     `db:"position"`
+	ICAOCode *string    `db:"icao_code"`
+	Outcome  *string    `db:"outcome"`
+	Status   *string    `db:"status"`
+	Message  *string    `db:"message"`
+	Changes  []byte     `db:"changes"`
+}
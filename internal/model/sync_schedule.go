@@ -0,0 +1,22 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SyncSchedule is the stored state of a configured sync schedule.
+// LastJobStatus is the current status of the job its last run started.
+type SyncSchedule struct {
+	Name          *string    `db:"name"`
+	Expression    *string    `db:"expression"`
+	LastRunAt     *time.Time `db:"last_run_at"`
+	NextRunAt     *time.Time `db:"next_run_at"`
+	LastOutcome   *string    `db:"last_outcome"`
+	LastMessage   *string    `db:"last_message"`
+	LastJobID     *uuid.UUID `db:"last_job_id"`
+	LastJobStatus *string    `db:"last_job_status"`
+	CreatedAt     *time.Time `db:"created_at"`
+	UpdatedAt     *time.Time `db:"updated_at"`
+}
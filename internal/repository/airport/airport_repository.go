@@ -3,17 +3,25 @@ package repository_airport
 import (
 	"context"
 	"database/sql"
+	"flight-api/internal/enum"
 	"flight-api/internal/model"
+	"time"
 )
 
 type IAirportRepository interface {
 	Insert(ctx context.Context, tx *sql.Tx, airport model.Airport) (model.Airport, error)
+	Upsert(ctx context.Context, tx *sql.Tx, airport model.Airport, sources map[string]string) (model.Airport, bool, error)
 	SyncAirport(ctx context.Context, tx *sql.Tx, airport model.Airport) (model.Airport, error)
 	FindAll(ctx context.Context, tx *sql.Tx, args map[string]interface{}) ([]model.Airport, int, error)
 	FindBySearchName(ctx context.Context, tx *sql.Tx, name string, args map[string]interface{}) ([]model.Airport, int, error)
 	FindByID(ctx context.Context, tx *sql.Tx, id string) (model.Airport, error)
 	FindExistsByICAOID(ctx context.Context, tx *sql.Tx, icaoId string) (bool, error)
 	FindByICAOID(ctx context.Context, tx *sql.Tx, icaoId string) (model.Airport, error)
+	FindFieldSources(ctx context.Context, tx *sql.Tx, icaoId string) (map[string]string, error)
+	UpdateFieldSources(ctx context.Context, tx *sql.Tx, icaoId string, sources map[string]string) error
+	FindAllICAOIDs(ctx context.Context, tx *sql.Tx) ([]string, error)
 	Update(ctx context.Context, tx *sql.Tx, id string, airport model.Airport) (model.Airport, error)
 	Delete(ctx context.Context, tx *sql.Tx, id string) error
+	ClaimForSync(ctx context.Context, tx *sql.Tx, limit int, maxAttempts int, retryBefore time.Time, staleBefore time.Time) ([]model.Airport, error)
+	UpdateSyncStatus(ctx context.Context, tx *sql.Tx, id string, status enum.SyncStatusEnum, message string) error
 }
@@ -3,11 +3,14 @@ package repository_airport
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"flight-api/internal/enum"
 	"flight-api/internal/model"
 	"flight-api/pkg/logger"
 	"flight-api/util"
+	"fmt"
 	"strings"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -29,15 +32,15 @@ func (r *AirportRepository) Insert(ctx context.Context, tx *sql.Tx, airport mode
 			type, status, country, state, state_full, 
 			county, city, ownership, "use", manager, 
 			manager_phone, latitude, latitude_sec, longitude, longitude_sec,
-			elevation, control_tower, unicom, ctaf, effective_date,
-			sync_status, sync_message
+			elevation, magnetic_variation, control_tower, unicom, ctaf,
+			effective_date, sync_status, sync_message
 		) VALUES (
 			$1, $2, $3, $4, $5,
 			$6, $7, $8, $9, $10, 
 			$11, $12, $13, $14, $15, 
 			$16, $17, $18, $19, $20,
 			$21, $22, $23, $24, $25,
-			$26, $27
+			$26, $27, $28
 		) 
 		RETURNING 
 			id,
@@ -45,8 +48,8 @@ func (r *AirportRepository) Insert(ctx context.Context, tx *sql.Tx, airport mode
 			type, status, country, state, state_full, 
 			county, city, ownership, "use", manager, 
 			manager_phone, latitude, latitude_sec, longitude, longitude_sec,
-			elevation, control_tower, unicom, ctaf, effective_date,
-			sync_status, sync_message, created_at, updated_at
+			elevation, magnetic_variation, control_tower, unicom, ctaf,
+			effective_date, sync_status, sync_message, created_at, updated_at
 	`
 
 	var result model.Airport
@@ -57,8 +60,8 @@ func (r *AirportRepository) Insert(ctx context.Context, tx *sql.Tx, airport mode
 		airport.Type, airport.Status, airport.Country, airport.State, airport.StateFull,
 		airport.County, airport.City, airport.Ownership, airport.Use, airport.Manager,
 		airport.ManagerPhone, airport.Latitude, airport.LatitudeSec, airport.Longitude, airport.LongitudeSec,
-		airport.Elevation, airport.ControlTower, airport.Unicom, airport.CTAF, airport.EffectiveDate,
-		enum.SYNC_SYNCED.Int(), enum.SYNC_SYNCED.String(),
+		airport.Elevation, airport.MagVariation, airport.ControlTower, airport.Unicom, airport.CTAF,
+		airport.EffectiveDate, enum.SYNC_SYNCED.Int(), enum.SYNC_SYNCED.String(),
 	)
 
 	err := row.Scan(
@@ -67,8 +70,8 @@ func (r *AirportRepository) Insert(ctx context.Context, tx *sql.Tx, airport mode
 		&result.Type, &result.Status, &result.Country, &result.State, &result.StateFull,
 		&result.County, &result.City, &result.Ownership, &result.Use, &result.Manager,
 		&result.ManagerPhone, &result.Latitude, &result.LatitudeSec, &result.Longitude, &result.LongitudeSec,
-		&result.Elevation, &result.ControlTower, &result.Unicom, &result.CTAF, &result.EffectiveDate,
-		&result.SyncStatus, &result.SyncMessage, &result.CreatedAt, &result.UpdatedAt,
+		&result.Elevation, &result.MagVariation, &result.ControlTower, &result.Unicom, &result.CTAF,
+		&result.EffectiveDate, &result.SyncStatus, &result.SyncMessage, &result.CreatedAt, &result.UpdatedAt,
 	)
 
 	if err != nil {
@@ -80,6 +83,88 @@ func (r *AirportRepository) Insert(ctx context.Context, tx *sql.Tx, airport mode
 	return result, nil
 }
 
+// Upsert inserts the airport or, when one with its ICAO code is stored,
+// updates it. Nil fields keep the stored value. sources records the import
+// source of the fields written, merged over the ones already recorded. It
+// reports whether the airport was inserted; the result holds only its ID
+// and ICAO code.
+func (r *AirportRepository) Upsert(ctx context.Context, tx *sql.Tx, airport model.Airport, sources map[string]string) (model.Airport, bool, error) {
+	if sources == nil {
+		sources = map[string]string{}
+	}
+	fieldSources, err := json.Marshal(sources)
+	if err != nil {
+		return model.Airport{}, false, err
+	}
+
+	SQL := `
+		INSERT INTO airports (
+			site_number, icao_id, faa_id, iata_id, name,
+			type, status, country, state, state_full,
+			county, city, ownership, "use", manager,
+			manager_phone, latitude, latitude_sec, longitude, longitude_sec,
+			elevation, magnetic_variation, control_tower, unicom, ctaf,
+			effective_date, sync_status, sync_message, field_sources
+		) VALUES (
+			$1, $2, $3, $4, $5,
+			$6, $7, $8, $9, $10,
+			$11, $12, $13, $14, $15,
+			$16, $17, $18, $19, $20,
+			$21, $22, $23, $24, $25,
+			$26, $27, $28, $29
+		)
+		ON CONFLICT (icao_id) DO UPDATE SET
+			site_number = COALESCE(EXCLUDED.site_number, airports.site_number),
+			faa_id = COALESCE(EXCLUDED.faa_id, airports.faa_id),
+			iata_id = COALESCE(EXCLUDED.iata_id, airports.iata_id),
+			name = COALESCE(EXCLUDED.name, airports.name),
+			type = COALESCE(EXCLUDED.type, airports.type),
+			status = COALESCE(EXCLUDED.status, airports.status),
+			country = COALESCE(EXCLUDED.country, airports.country),
+			state = COALESCE(EXCLUDED.state, airports.state),
+			state_full = COALESCE(EXCLUDED.state_full, airports.state_full),
+			county = COALESCE(EXCLUDED.county, airports.county),
+			city = COALESCE(EXCLUDED.city, airports.city),
+			ownership = COALESCE(EXCLUDED.ownership, airports.ownership),
+			"use" = COALESCE(EXCLUDED."use", airports."use"),
+			manager = COALESCE(EXCLUDED.manager, airports.manager),
+			manager_phone = COALESCE(EXCLUDED.manager_phone, airports.manager_phone),
+			latitude = COALESCE(EXCLUDED.latitude, airports.latitude),
+			latitude_sec = COALESCE(EXCLUDED.latitude_sec, airports.latitude_sec),
+			longitude = COALESCE(EXCLUDED.longitude, airports.longitude),
+			longitude_sec = COALESCE(EXCLUDED.longitude_sec, airports.longitude_sec),
+			elevation = COALESCE(EXCLUDED.elevation, airports.elevation),
+			magnetic_variation = COALESCE(EXCLUDED.magnetic_variation, airports.magnetic_variation),
+			control_tower = COALESCE(EXCLUDED.control_tower, airports.control_tower),
+			unicom = COALESCE(EXCLUDED.unicom, airports.unicom),
+			ctaf = COALESCE(EXCLUDED.ctaf, airports.ctaf),
+			effective_date = COALESCE(EXCLUDED.effective_date, airports.effective_date),
+			sync_status = EXCLUDED.sync_status,
+			sync_message = EXCLUDED.sync_message,
+			field_sources = airports.field_sources || EXCLUDED.field_sources
+		RETURNING id, icao_id, (xmax = 0) AS inserted
+	`
+
+	var result model.Airport
+	var inserted bool
+	err = tx.QueryRowContext(
+		ctx,
+		strings.TrimSpace(SQL),
+		airport.SiteNumber, airport.ICAOID, airport.FAAID, airport.IATAID, airport.Name,
+		airport.Type, airport.Status, airport.Country, airport.State, airport.StateFull,
+		airport.County, airport.City, airport.Ownership, airport.Use, airport.Manager,
+		airport.ManagerPhone, airport.Latitude, airport.LatitudeSec, airport.Longitude, airport.LongitudeSec,
+		airport.Elevation, airport.MagVariation, airport.ControlTower, airport.Unicom, airport.CTAF,
+		airport.EffectiveDate, enum.SYNC_SYNCED.Int(), enum.SYNC_SYNCED.String(), string(fieldSources),
+	).Scan(&result.ID, &result.ICAOID, &inserted)
+	if err != nil {
+		r.logger.Errorf("Failed to upsert airport %s: %v", util.DerefPtr(airport.ICAOID), err)
+		return model.Airport{}, false, err
+	}
+
+	return result, inserted, nil
+}
+
 func (r *AirportRepository) SyncAirport(ctx context.Context, tx *sql.Tx, airport model.Airport) (model.Airport, error) {
 	SQL := `
 		INSERT INTO airports (
@@ -88,14 +173,14 @@ func (r *AirportRepository) SyncAirport(ctx context.Context, tx *sql.Tx, airport
 			ownership, "use", manager, manager_phone,
 			latitude, latitude_sec, longitude, longitude_sec,
 			elevation, control_tower, unicom, ctaf, effective_date,
-			status
+			magnetic_variation
 		) VALUES (
 			$1, $2, $3, $4, $5, $6, $7,
 			$8, $9, $10, $11, $12,
 			$13, $14, $15, $16,
 			$17, $18, $19, $20,
 			$21, $22, $23, $24, $25,
-			1
+			$26
 		)
 		RETURNING id`
 
@@ -127,13 +212,19 @@ func (r *AirportRepository) SyncAirport(ctx context.Context, tx *sql.Tx, airport
 		airport.Unicom,
 		airport.CTAF,
 		airport.EffectiveDate,
+		airport.MagVariation,
 	)
 
 	var id string
-	row.Scan(&id)
+	if err := row.Scan(&id); err != nil {
+		r.logger.Errorf("Failed to sync airport: %v", err)
+		return model.Airport{}, err
+	}
 
 	result, err := r.FindByID(ctx, tx, id)
-	util.PanicIfError(err)
+	if err != nil {
+		return model.Airport{}, err
+	}
 
 	r.logger.Debugf("Inserted airport with ID: %s", id)
 	return result, nil
@@ -143,7 +234,7 @@ func (r *AirportRepository) FindByID(ctx context.Context, tx *sql.Tx, id string)
 	SQL := `
 SELECT id, site_number, icao_id, faa_id, iata_id, name, type, status,
 	country, state, state_full, county, city, ownership, "use",
-	manager, manager_phone, latitude, latitude_sec, longitude, longitude_sec, elevation,
+	manager, manager_phone, latitude, latitude_sec, longitude, longitude_sec, elevation, magnetic_variation,
 	control_tower, unicom, ctaf, effective_date, created_at, updated_at
 FROM airports 
 WHERE id = $1 
@@ -157,6 +248,9 @@ LIMIT 1
 	rows, err := tx.QueryContext(ctx, SQL, airportId)
 	if err == sql.ErrNoRows {
 		return model.Airport{}, util.ErrNotFound
+	} else if err != nil {
+		r.logger.Errorf("Failed to find airport by ID %s: %v", id, err)
+		return model.Airport{}, err
 	}
 	defer rows.Close()
 
@@ -185,6 +279,7 @@ LIMIT 1
 			&airport.Longitude,
 			&airport.LongitudeSec,
 			&airport.Elevation,
+			&airport.MagVariation,
 			&airport.ControlTower,
 			&airport.Unicom,
 			&airport.CTAF,
@@ -192,7 +287,10 @@ LIMIT 1
 			&airport.CreatedAt,
 			&airport.UpdatedAt,
 		)
-		util.PanicIfError(err)
+		if err != nil {
+			r.logger.Errorf("Failed to scan airport: %v", err)
+			return model.Airport{}, err
+		}
 		return airport, nil
 	} else {
 		return model.Airport{}, util.ErrNotFound
@@ -210,7 +308,10 @@ func (r *AirportRepository) FindAll(ctx context.Context, tx *sql.Tx, args map[st
 	OFFSET $2`
 
 	rows, err := tx.QueryContext(ctx, strings.TrimSpace(SQL), limit, offset)
-	util.PanicIfError(err)
+	if err != nil {
+		r.logger.Errorf("Failed to find airports: %v", err)
+		return nil, 0, err
+	}
 	defer rows.Close()
 
 	var airports []model.Airport
@@ -228,15 +329,20 @@ func (r *AirportRepository) FindAll(ctx context.Context, tx *sql.Tx, args map[st
 			&airport.CreatedAt,
 			&airport.UpdatedAt,
 		)
-		util.PanicIfError(err)
+		if err != nil {
+			r.logger.Errorf("Failed to scan airport: %v", err)
+			return nil, 0, err
+		}
 		airports = append(airports, airport)
 	}
 
 	var total int
 	TotalSQL := `SELECT COUNT(*) FROM airports`
 	row := tx.QueryRowContext(ctx, TotalSQL)
-	err = row.Scan(&total)
-	util.PanicIfError(err)
+	if err := row.Scan(&total); err != nil {
+		r.logger.Errorf("Failed to count airports: %v", err)
+		return nil, 0, err
+	}
 
 	return airports, total, nil
 }
@@ -249,7 +355,7 @@ func (r *AirportRepository) FindBySearchName(ctx context.Context, tx *sql.Tx, na
 
 	SQL := `SELECT id, site_number, icao_id, faa_id, iata_id, name, type, status,
 			country, state, state_full, county, city, ownership, "use",
-			manager, manager_phone, latitude, latitude_sec, longitude, longitude_sec, elevation,
+			manager, manager_phone, latitude, latitude_sec, longitude, longitude_sec, elevation, magnetic_variation,
 			control_tower, unicom, ctaf, effective_date, created_at, updated_at
 		FROM airports 
 		WHERE LOWER(name) LIKE LOWER($3)
@@ -258,7 +364,10 @@ func (r *AirportRepository) FindBySearchName(ctx context.Context, tx *sql.Tx, na
 		OFFSET $2`
 
 	rows, err := tx.QueryContext(ctx, strings.TrimSpace(SQL), limit, offset, searchName)
-	util.PanicIfError(err)
+	if err != nil {
+		r.logger.Errorf("Failed to find airports by name %s: %v", name, err)
+		return nil, 0, err
+	}
 	defer rows.Close()
 
 	var airports []model.Airport
@@ -287,6 +396,7 @@ func (r *AirportRepository) FindBySearchName(ctx context.Context, tx *sql.Tx, na
 			&airport.Longitude,
 			&airport.LongitudeSec,
 			&airport.Elevation,
+			&airport.MagVariation,
 			&airport.ControlTower,
 			&airport.Unicom,
 			&airport.CTAF,
@@ -294,7 +404,10 @@ func (r *AirportRepository) FindBySearchName(ctx context.Context, tx *sql.Tx, na
 			&airport.CreatedAt,
 			&airport.UpdatedAt,
 		)
-		util.PanicIfError(err)
+		if err != nil {
+			r.logger.Errorf("Failed to scan airport: %v", err)
+			return nil, 0, err
+		}
 		airports = append(airports, airport)
 	}
 
@@ -302,8 +415,10 @@ func (r *AirportRepository) FindBySearchName(ctx context.Context, tx *sql.Tx, na
 	TotalSQL := `SELECT COUNT(*) FROM airports WHERE name ILIKE $1`
 
 	row := tx.QueryRowContext(ctx, TotalSQL, searchName)
-	err = row.Scan(&total)
-	util.PanicIfError(err)
+	if err := row.Scan(&total); err != nil {
+		r.logger.Errorf("Failed to count airports by name %s: %v", name, err)
+		return nil, 0, err
+	}
 
 	return airports, total, nil
 }
@@ -319,7 +434,7 @@ func (r *AirportRepository) FindExistsByICAOID(ctx context.Context, tx *sql.Tx,
 		return false, nil
 	} else if err != nil {
 		r.logger.Errorf("Error checking existence of ICAO ID %s: %v", icaoId, err)
-		panic(err)
+		return false, err
 	}
 
 	if exists != 1 {
@@ -332,7 +447,7 @@ func (r *AirportRepository) FindExistsByICAOID(ctx context.Context, tx *sql.Tx,
 func (r *AirportRepository) FindByICAOID(ctx context.Context, tx *sql.Tx, icaoId string) (model.Airport, error) {
 	SQL := `SELECT id, site_number, icao_id, faa_id, iata_id, name, type, status,
 			country, state, state_full, county, city, ownership, "use",
-			manager, manager_phone, latitude, latitude_sec, longitude, longitude_sec, elevation,
+			manager, manager_phone, latitude, latitude_sec, longitude, longitude_sec, elevation, magnetic_variation,
 			control_tower, unicom, ctaf, effective_date, created_at, updated_at
 		FROM airports 
 		WHERE icao_id = $1 
@@ -341,8 +456,11 @@ func (r *AirportRepository) FindByICAOID(ctx context.Context, tx *sql.Tx, icaoId
 	rows, err := tx.QueryContext(ctx, strings.TrimSpace(SQL), icaoId)
 	if err == sql.ErrNoRows {
 		return model.Airport{}, util.ErrNotFound
+	} else if err != nil {
+		r.logger.Errorf("Failed to find airport by ICAO ID %s: %v", icaoId, err)
+		return model.Airport{}, err
 	}
-	util.PanicIfError(err)
+	defer rows.Close()
 
 	airport := model.Airport{}
 	if rows.Next() {
@@ -369,6 +487,7 @@ func (r *AirportRepository) FindByICAOID(ctx context.Context, tx *sql.Tx, icaoId
 			&airport.Longitude,
 			&airport.LongitudeSec,
 			&airport.Elevation,
+			&airport.MagVariation,
 			&airport.ControlTower,
 			&airport.Unicom,
 			&airport.CTAF,
@@ -376,13 +495,94 @@ func (r *AirportRepository) FindByICAOID(ctx context.Context, tx *sql.Tx, icaoId
 			&airport.CreatedAt,
 			&airport.UpdatedAt,
 		)
-		util.PanicIfError(err)
+		if err != nil {
+			r.logger.Errorf("Failed to scan airport: %v", err)
+			return model.Airport{}, err
+		}
 		return airport, nil
 	} else {
 		return model.Airport{}, util.ErrNotFound
 	}
 }
 
+// FindFieldSources returns the import source recorded for each field of the
+// airport with the ICAO code, keyed by column name.
+func (r *AirportRepository) FindFieldSources(ctx context.Context, tx *sql.Tx, icaoId string) (map[string]string, error) {
+	SQL := `SELECT field_sources FROM airports WHERE icao_id = $1`
+
+	var raw []byte
+	err := tx.QueryRowContext(ctx, SQL, icaoId).Scan(&raw)
+	if err == sql.ErrNoRows {
+		return nil, util.ErrNotFound
+	} else if err != nil {
+		r.logger.Errorf("Failed to find field sources of airport %s: %v", icaoId, err)
+		return nil, err
+	}
+
+	sources := map[string]string{}
+	if err := json.Unmarshal(raw, &sources); err != nil {
+		r.logger.Errorf("Failed to decode field sources of airport %s: %v", icaoId, err)
+		return nil, err
+	}
+	return sources, nil
+}
+
+// UpdateFieldSources records sources as the source of the fields they name,
+// merged over the ones already recorded for the airport with the ICAO code.
+func (r *AirportRepository) UpdateFieldSources(ctx context.Context, tx *sql.Tx, icaoId string, sources map[string]string) error {
+	SQL := `UPDATE airports SET field_sources = field_sources || $2::jsonb WHERE icao_id = $1`
+
+	fieldSources, err := json.Marshal(sources)
+	if err != nil {
+		return err
+	}
+
+	result, err := tx.ExecContext(ctx, SQL, icaoId, string(fieldSources))
+	if err != nil {
+		r.logger.Errorf("Failed to update field sources of airport %s: %v", icaoId, err)
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return util.ErrNotFound
+	}
+
+	return nil
+}
+
+// FindAllICAOIDs lists the ICAO code of every stored airport, in code order.
+func (r *AirportRepository) FindAllICAOIDs(ctx context.Context, tx *sql.Tx) ([]string, error) {
+	SQL := `SELECT icao_id FROM airports WHERE icao_id IS NOT NULL ORDER BY icao_id`
+
+	rows, err := tx.QueryContext(ctx, SQL)
+	if err != nil {
+		r.logger.Errorf("Failed to find airport ICAO codes: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	codes := []string{}
+	for rows.Next() {
+		var code string
+		if err := rows.Scan(&code); err != nil {
+			r.logger.Errorf("Failed to scan airport ICAO code: %v", err)
+			return nil, err
+		}
+		codes = append(codes, code)
+	}
+	if err := rows.Err(); err != nil {
+		r.logger.Errorf("Failed to read airport ICAO codes: %v", err)
+		return nil, err
+	}
+
+	return codes, nil
+}
+
 func (r *AirportRepository) Update(ctx context.Context, tx *sql.Tx, id string, airport model.Airport) (model.Airport, error) {
 	SQL := `
 		UPDATE airports SET
@@ -410,8 +610,9 @@ func (r *AirportRepository) Update(ctx context.Context, tx *sql.Tx, id string, a
 			unicom = $22,
 			ctaf = $23,
 			effective_date = $24,
+			magnetic_variation = $25,
 			updated_at = NOW()
-		WHERE id = $25
+		WHERE id = $26
 		RETURNING id
 	`
 
@@ -447,6 +648,7 @@ func (r *AirportRepository) Update(ctx context.Context, tx *sql.Tx, id string, a
 		airport.Unicom,
 		airport.CTAF,
 		airport.EffectiveDate,
+		airport.MagVariation,
 		airportId,
 	)
 
@@ -454,11 +656,15 @@ func (r *AirportRepository) Update(ctx context.Context, tx *sql.Tx, id string, a
 	err = row.Scan(&updatedID)
 	if err == sql.ErrNoRows {
 		return model.Airport{}, util.ErrNotFound
+	} else if err != nil {
+		r.logger.Errorf("Failed to update airport %s: %v", id, err)
+		return model.Airport{}, err
 	}
-	util.PanicIfError(err)
 
 	updatedAirport, err := r.FindByID(ctx, tx, updatedID)
-	util.PanicIfError(err)
+	if err != nil {
+		return model.Airport{}, err
+	}
 
 	return updatedAirport, nil
 }
@@ -472,10 +678,121 @@ func (r *AirportRepository) Delete(ctx context.Context, tx *sql.Tx, id string) e
 	}
 
 	result, err := tx.ExecContext(ctx, SQL, airportId)
-	util.PanicIfError(err)
+	if err != nil {
+		r.logger.Errorf("Failed to delete airport %s: %v", id, err)
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return util.ErrNotFound
+	}
+
+	return nil
+}
+
+// ClaimForSync marks up to limit airports SYNC_ON_PROCESS for the sync
+// worker and returns them. It claims new airports, failed ones with attempts
+// left whose last attempt was before retryBefore, and those left on process
+// since staleBefore by a worker that is presumed gone. Stale airports that
+// have used up their attempts are moved to SYNC_ERROR instead, so one that
+// keeps crashing the worker is not claimed forever. Rows locked by another
+// worker's claim are skipped.
+func (r *AirportRepository) ClaimForSync(ctx context.Context, tx *sql.Tx, limit int, maxAttempts int, retryBefore time.Time, staleBefore time.Time) ([]model.Airport, error) {
+	giveUpSQL := `
+		UPDATE airports SET
+			sync_status = $1,
+			sync_message = $2
+		WHERE sync_status = $3 AND sync_attempts >= $4 AND updated_at < $5`
+
+	_, err := tx.ExecContext(
+		ctx,
+		strings.TrimSpace(giveUpSQL),
+		enum.SYNC_ERROR.Int(),
+		fmt.Sprintf("Giving up after %d attempts. The sync did not finish", maxAttempts),
+		enum.SYNC_ON_PROCESS.Int(),
+		maxAttempts,
+		staleBefore,
+	)
+	if err != nil {
+		r.logger.Errorf("Failed to give up on stale airport syncs: %v", err)
+		return nil, err
+	}
+
+	SQL := `
+		UPDATE airports SET
+			sync_status = $1,
+			sync_message = $2,
+			sync_attempts = sync_attempts + 1
+		WHERE id IN (
+			SELECT id FROM airports
+			WHERE sync_status = $3
+				OR (sync_status = $4 AND sync_attempts < $5 AND updated_at < $6)
+				OR (sync_status = $1 AND sync_attempts < $5 AND updated_at < $7)
+			ORDER BY updated_at
+			LIMIT $8
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, icao_id, sync_status, sync_message, sync_attempts`
+
+	rows, err := tx.QueryContext(
+		ctx,
+		strings.TrimSpace(SQL),
+		enum.SYNC_ON_PROCESS.Int(),
+		enum.SYNC_ON_PROCESS.String(),
+		enum.SYNC_NEW.Int(),
+		enum.SYNC_ERROR.Int(),
+		maxAttempts,
+		retryBefore,
+		staleBefore,
+		limit,
+	)
+	if err != nil {
+		r.logger.Errorf("Failed to claim airports for sync: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	airports := []model.Airport{}
+	for rows.Next() {
+		airport := model.Airport{}
+		err := rows.Scan(&airport.ID, &airport.ICAOID, &airport.SyncStatus, &airport.SyncMessage, &airport.SyncAttempts)
+		if err != nil {
+			r.logger.Errorf("Failed to scan claimed airport: %v", err)
+			return nil, err
+		}
+		airports = append(airports, airport)
+	}
+	if err := rows.Err(); err != nil {
+		r.logger.Errorf("Failed to read claimed airports: %v", err)
+		return nil, err
+	}
+
+	return airports, nil
+}
+
+func (r *AirportRepository) UpdateSyncStatus(ctx context.Context, tx *sql.Tx, id string, status enum.SyncStatusEnum, message string) error {
+	SQL := `UPDATE airports SET sync_status = $2, sync_message = $3 WHERE id = $1`
+
+	airportId, err := uuid.Parse(id)
+	if err != nil {
+		return util.ErrNotFound
+	}
+
+	result, err := tx.ExecContext(ctx, SQL, airportId, status.Int(), message)
+	if err != nil {
+		r.logger.Errorf("Failed to update sync status of airport %s: %v", id, err)
+		return err
+	}
 
 	rowsAffected, err := result.RowsAffected()
-	util.PanicIfError(err)
+	if err != nil {
+		return err
+	}
 
 	if rowsAffected == 0 {
 		return util.ErrNotFound
@@ -3,7 +3,10 @@ package repository_airport
 import (
 	"context"
 	"database/sql"
+	"flight-api/internal/enum"
 	"flight-api/internal/model"
+	"flight-api/util"
+	"time"
 
 	"github.com/stretchr/testify/mock"
 )
@@ -13,7 +16,7 @@ type AirportRepositoryMock struct {
 }
 
 func (r *AirportRepositoryMock) Insert(ctx context.Context, tx *sql.Tx, airport model.Airport) (model.Airport, error) {
-	args := r.Mock.Called(ctx, tx, airport)
+	args := r.Mock.Called(ctx, util.MockTx{Tx: tx}, airport)
 	var out model.Airport
 	if v, ok := args.Get(0).(model.Airport); ok {
 		out = v
@@ -22,7 +25,7 @@ func (r *AirportRepositoryMock) Insert(ctx context.Context, tx *sql.Tx, airport
 }
 
 func (r *AirportRepositoryMock) SyncAirport(ctx context.Context, tx *sql.Tx, airport model.Airport) (model.Airport, error) {
-	args := r.Mock.Called(ctx, tx, airport)
+	args := r.Mock.Called(ctx, util.MockTx{Tx: tx}, airport)
 	var out model.Airport
 	if v, ok := args.Get(0).(model.Airport); ok {
 		out = v
@@ -31,7 +34,7 @@ func (r *AirportRepositoryMock) SyncAirport(ctx context.Context, tx *sql.Tx, air
 }
 
 func (r *AirportRepositoryMock) FindAll(ctx context.Context, tx *sql.Tx, args map[string]interface{}) ([]model.Airport, int, error) {
-	call := r.Mock.Called(ctx, tx, args)
+	call := r.Mock.Called(ctx, util.MockTx{Tx: tx}, args)
 
 	var list []model.Airport
 	if v, ok := call.Get(0).([]model.Airport); ok {
@@ -45,7 +48,7 @@ func (r *AirportRepositoryMock) FindAll(ctx context.Context, tx *sql.Tx, args ma
 }
 
 func (r *AirportRepositoryMock) FindBySearchName(ctx context.Context, tx *sql.Tx, name string, args map[string]interface{}) ([]model.Airport, int, error) {
-	call := r.Mock.Called(ctx, tx, name, args)
+	call := r.Mock.Called(ctx, util.MockTx{Tx: tx}, name, args)
 
 	var list []model.Airport
 	if v, ok := call.Get(0).([]model.Airport); ok {
@@ -61,7 +64,7 @@ func (r *AirportRepositoryMock) FindBySearchName(ctx context.Context, tx *sql.Tx
 }
 
 func (r *AirportRepositoryMock) FindByID(ctx context.Context, tx *sql.Tx, id string) (model.Airport, error) {
-	call := r.Mock.Called(ctx, tx, id)
+	call := r.Mock.Called(ctx, util.MockTx{Tx: tx}, id)
 	var out model.Airport
 	if v, ok := call.Get(0).(model.Airport); ok {
 		out = v
@@ -70,7 +73,7 @@ func (r *AirportRepositoryMock) FindByID(ctx context.Context, tx *sql.Tx, id str
 }
 
 func (r *AirportRepositoryMock) FindExistsByICAOID(ctx context.Context, tx *sql.Tx, icaoId string) (bool, error) {
-	args := r.Mock.Called(ctx, tx, icaoId)
+	args := r.Mock.Called(ctx, util.MockTx{Tx: tx}, icaoId)
 	var exists bool
 	if v, ok := args.Get(0).(bool); ok {
 		exists = v
@@ -79,7 +82,7 @@ func (r *AirportRepositoryMock) FindExistsByICAOID(ctx context.Context, tx *sql.
 }
 
 func (r *AirportRepositoryMock) FindByICAOID(ctx context.Context, tx *sql.Tx, icaoId string) (model.Airport, error) {
-	call := r.Mock.Called(ctx, tx, icaoId)
+	call := r.Mock.Called(ctx, util.MockTx{Tx: tx}, icaoId)
 	var out model.Airport
 	if v, ok := call.Get(0).(model.Airport); ok {
 		out = v
@@ -87,8 +90,40 @@ func (r *AirportRepositoryMock) FindByICAOID(ctx context.Context, tx *sql.Tx, ic
 	return out, call.Error(1)
 }
 
+func (r *AirportRepositoryMock) Upsert(ctx context.Context, tx *sql.Tx, airport model.Airport, sources map[string]string) (model.Airport, bool, error) {
+	call := r.Mock.Called(ctx, util.MockTx{Tx: tx}, airport, sources)
+	var out model.Airport
+	if v, ok := call.Get(0).(model.Airport); ok {
+		out = v
+	}
+	return out, call.Bool(1), call.Error(2)
+}
+
+func (r *AirportRepositoryMock) FindFieldSources(ctx context.Context, tx *sql.Tx, icaoId string) (map[string]string, error) {
+	call := r.Mock.Called(ctx, util.MockTx{Tx: tx}, icaoId)
+	var out map[string]string
+	if v, ok := call.Get(0).(map[string]string); ok {
+		out = v
+	}
+	return out, call.Error(1)
+}
+
+func (r *AirportRepositoryMock) UpdateFieldSources(ctx context.Context, tx *sql.Tx, icaoId string, sources map[string]string) error {
+	call := r.Mock.Called(ctx, util.MockTx{Tx: tx}, icaoId, sources)
+	return call.Error(0)
+}
+
+func (r *AirportRepositoryMock) FindAllICAOIDs(ctx context.Context, tx *sql.Tx) ([]string, error) {
+	call := r.Mock.Called(ctx, util.MockTx{Tx: tx})
+	var out []string
+	if v, ok := call.Get(0).([]string); ok {
+		out = v
+	}
+	return out, call.Error(1)
+}
+
 func (r *AirportRepositoryMock) Update(ctx context.Context, tx *sql.Tx, id string, airport model.Airport) (model.Airport, error) {
-	call := r.Mock.Called(ctx, tx, id, airport)
+	call := r.Mock.Called(ctx, util.MockTx{Tx: tx}, id, airport)
 	var out model.Airport
 	if v, ok := call.Get(0).(model.Airport); ok {
 		out = v
@@ -97,6 +132,20 @@ func (r *AirportRepositoryMock) Update(ctx context.Context, tx *sql.Tx, id strin
 }
 
 func (r *AirportRepositoryMock) Delete(ctx context.Context, tx *sql.Tx, id string) error {
-	call := r.Mock.Called(ctx, tx, id)
+	call := r.Mock.Called(ctx, util.MockTx{Tx: tx}, id)
+	return call.Error(0)
+}
+
+func (r *AirportRepositoryMock) ClaimForSync(ctx context.Context, tx *sql.Tx, limit int, maxAttempts int, retryBefore time.Time, staleBefore time.Time) ([]model.Airport, error) {
+	call := r.Mock.Called(ctx, util.MockTx{Tx: tx}, limit, maxAttempts, retryBefore, staleBefore)
+	var out []model.Airport
+	if v, ok := call.Get(0).([]model.Airport); ok {
+		out = v
+	}
+	return out, call.Error(1)
+}
+
+func (r *AirportRepositoryMock) UpdateSyncStatus(ctx context.Context, tx *sql.Tx, id string, status enum.SyncStatusEnum, message string) error {
+	call := r.Mock.Called(ctx, util.MockTx{Tx: tx}, id, status, message)
 	return call.Error(0)
 }
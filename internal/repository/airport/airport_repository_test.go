@@ -10,7 +10,6 @@ import (
 	"flight-api/internal/model"
 	"flight-api/pkg/logger"
 	"flight-api/util"
-	"fmt"
 	"regexp"
 	"sort"
 	"strings"
@@ -27,7 +26,7 @@ var log = logger.NewLogger(logger.DEBUG_LEVEL)
 // ---------- HELPER FUNCTIONS ----------
 var selectAirportQuery string = `SELECT id, site_number, icao_id, faa_id, iata_id, name, type, status,
 	country, state, state_full, county, city, ownership, "use",
-	manager, manager_phone, latitude, latitude_sec, longitude, longitude_sec, elevation,
+	manager, manager_phone, latitude, latitude_sec, longitude, longitude_sec, elevation, magnetic_variation,
 	control_tower, unicom, ctaf, effective_date, created_at, updated_at
 FROM airports 
 WHERE id = $1 
@@ -40,7 +39,7 @@ func newCols() []string {
 		"type", "status", "country", "state", "state_full", "county", "city",
 		"ownership", "use", "manager", "manager_phone",
 		"latitude", "latitude_sec", "longitude", "longitude_sec",
-		"elevation", "control_tower", "unicom", "ctaf",
+		"elevation", "magnetic_variation", "control_tower", "unicom", "ctaf",
 		"effective_date", "created_at", "updated_at",
 	}
 }
@@ -64,6 +63,7 @@ func successRow(
 	use enum.UseTypeEnum,
 	manager, managerPhone, latitude, latitudeSec, longitude, longitudeSec *string,
 	elevation *int64,
+	magVariation *string,
 	controlTower *bool,
 	unicom, ctaf *string,
 	effectiveDate *time.Time,
@@ -79,6 +79,7 @@ func successRow(
 		use,
 		manager, managerPhone, latitude, latitudeSec, longitude, longitudeSec,
 		elevation,
+		magVariation,
 		controlTower,
 		unicom, ctaf,
 		effectiveDate,
@@ -143,7 +144,7 @@ func buildRowsByName(nameLike string, limit, offset int) (*sqlmock.Rows, int) {
 		rows.AddRow(
 			a.ID, a.SiteNumber, a.ICAOID, a.FAAID, a.IATAID, a.Name, a.Type, a.Status,
 			a.Country, a.State, a.StateFull, a.County, a.City, a.Ownership, a.Use,
-			a.Manager, a.ManagerPhone, a.Latitude, a.LatitudeSec, a.Longitude, a.LongitudeSec, a.Elevation,
+			a.Manager, a.ManagerPhone, a.Latitude, a.LatitudeSec, a.Longitude, a.LongitudeSec, a.Elevation, a.MagVariation,
 			a.ControlTower, a.Unicom, a.CTAF, a.EffectiveDate, a.CreatedAt, a.UpdatedAt,
 		)
 	}
@@ -321,6 +322,7 @@ func TestAirportRepository_Insert(t *testing.T) {
 		Longitude:     util.Ptr("73.7781 W"),
 		LongitudeSec:  nil, // keep it nil to test
 		Elevation:     util.Ptr(int64(13)),
+		MagVariation:  util.Ptr("13W"),
 		ControlTower:  util.Ptr(true),
 		Unicom:        util.Ptr("123.45"),
 		CTAF:          util.Ptr("123.45"),
@@ -328,23 +330,13 @@ func TestAirportRepository_Insert(t *testing.T) {
 	}
 	modelInput := airport_dto.AirportRequestToAirport(req)
 
-	// ---------- expect INSERT ----------
+	// ---------- expect INSERT ... RETURNING ----------
 	insertRe := regexp.MustCompile(`(?s)INSERT\s+INTO\s+airports\s*\(.*?\)\s*VALUES\s*\(.*?\)\s*RETURNING\s+id`)
 	newID := uuid.New().String()
-
-	mock.ExpectQuery(insertRe.String()).
-		WithArgs(
-			sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
-			sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
-			sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
-			sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
-			sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
-		).
-		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(newID))
-
-	// ---------- expect SELECT (FindByID) ----------
 	now := time.Now()
-	rows := sqlmock.NewRows(newCols()).AddRow(
+
+	cols := append(newCols()[:27:27], "sync_status", "sync_message", "created_at", "updated_at")
+	rows := sqlmock.NewRows(cols).AddRow(
 		newID,
 		modelInput.SiteNumber,
 		modelInput.ICAOID,
@@ -367,15 +359,26 @@ func TestAirportRepository_Insert(t *testing.T) {
 		modelInput.Longitude,
 		modelInput.LongitudeSec,
 		modelInput.Elevation,
+		modelInput.MagVariation,
 		modelInput.ControlTower,
 		modelInput.Unicom,
 		modelInput.CTAF,
 		modelInput.EffectiveDate,
+		int64(20),
+		"synced",
 		now,
 		now,
 	)
-	mock.ExpectQuery(regexp.QuoteMeta(selectAirportQuery)).
-		WithArgs(newID).
+
+	mock.ExpectQuery(insertRe.String()).
+		WithArgs(
+			sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
+			sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
+			sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
+			sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
+			sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
+			sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
+		).
 		WillReturnRows(rows)
 
 	// Expect commit
@@ -410,6 +413,7 @@ func TestAirportRepository_Insert(t *testing.T) {
 	assert.Equal(t, modelInput.Longitude, out.Longitude)
 	assert.Equal(t, modelInput.LongitudeSec, out.LongitudeSec)
 	assert.Equal(t, modelInput.Elevation, out.Elevation)
+	assert.Equal(t, modelInput.MagVariation, out.MagVariation)
 	assert.Equal(t, modelInput.ControlTower, out.ControlTower)
 	assert.Equal(t, modelInput.Unicom, out.Unicom)
 	assert.Equal(t, modelInput.CTAF, out.CTAF)
@@ -457,6 +461,7 @@ func TestAirportRepository_SyncAirport(t *testing.T) {
 		Longitude:     util.Ptr("73.7781 W"),
 		LongitudeSec:  nil, // keep it nil to test
 		Elevation:     util.Ptr(int64(13)),
+		MagVariation:  util.Ptr("13W"),
 		ControlTower:  util.Ptr(true),
 		Unicom:        util.Ptr("123.45"),
 		CTAF:          util.Ptr("123.45"),
@@ -475,6 +480,7 @@ func TestAirportRepository_SyncAirport(t *testing.T) {
 			sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
 			sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
 			sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
+			sqlmock.AnyArg(),
 		).
 		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(newID))
 
@@ -508,6 +514,7 @@ func TestAirportRepository_SyncAirport(t *testing.T) {
 				modelInput.Longitude,
 				modelInput.LongitudeSec,
 				modelInput.Elevation,
+				modelInput.MagVariation,
 				modelInput.ControlTower,
 				modelInput.Unicom,
 				modelInput.CTAF,
@@ -549,6 +556,7 @@ func TestAirportRepository_SyncAirport(t *testing.T) {
 	assert.Equal(t, modelInput.Longitude, out.Longitude)
 	assert.Equal(t, modelInput.LongitudeSec, out.LongitudeSec)
 	assert.Equal(t, modelInput.Elevation, out.Elevation)
+	assert.Equal(t, modelInput.MagVariation, out.MagVariation)
 	assert.Equal(t, modelInput.ControlTower, out.ControlTower)
 	assert.Equal(t, modelInput.Unicom, out.Unicom)
 	assert.Equal(t, modelInput.CTAF, out.CTAF)
@@ -594,6 +602,7 @@ func TestAirportRepository_FindByID_Success(t *testing.T) {
 					row.Use,
 					row.Manager, row.ManagerPhone, row.Latitude, row.LatitudeSec, row.Longitude, row.LongitudeSec,
 					row.Elevation,
+					row.MagVariation,
 					row.ControlTower,
 					row.Unicom, row.CTAF,
 					row.EffectiveDate,
@@ -791,7 +800,7 @@ func TestAirportRepository_FindBySearchName(t *testing.T) {
 	selectSQL := `
 SELECT id, site_number, icao_id, faa_id, iata_id, name, type, status,
         country, state, state_full, county, city, ownership, "use",
-        manager, manager_phone, latitude, latitude_sec, longitude, longitude_sec, elevation,
+        manager, manager_phone, latitude, latitude_sec, longitude, longitude_sec, elevation, magnetic_variation,
         control_tower, unicom, ctaf, effective_date, created_at, updated_at
 FROM airports 
 WHERE LOWER(name) LIKE LOWER($3)
@@ -863,12 +872,13 @@ OFFSET $2`
 func TestAirportRepository_FindByICAOID(t *testing.T) {
 	query := `SELECT id, site_number, icao_id, faa_id, iata_id, name, type, status,
 				country, state, state_full, county, city, ownership, "use",
-				manager, manager_phone, latitude, latitude_sec, longitude, longitude_sec, elevation,
+				manager, manager_phone, latitude, latitude_sec, longitude, longitude_sec, elevation, magnetic_variation,
 				control_tower, unicom, ctaf, effective_date, created_at, updated_at
 		FROM airports 
 		WHERE icao_id = $1 
 		LIMIT 1`
 	q := regexp.QuoteMeta(strings.TrimSpace(query))
+	errBoom := errors.New("boom")
 
 	cases := []struct {
 		name        string
@@ -886,7 +896,7 @@ func TestAirportRepository_FindByICAOID(t *testing.T) {
 				rows := sqlmock.NewRows(newCols()).AddRow(
 					data.ID, data.SiteNumber, data.ICAOID, data.FAAID, data.IATAID, data.Name, data.Type, data.Status,
 					data.Country, data.State, data.StateFull, data.County, data.City, data.Ownership, data.Use,
-					data.Manager, data.ManagerPhone, data.Latitude, data.LatitudeSec, data.Longitude, data.LongitudeSec, data.Elevation,
+					data.Manager, data.ManagerPhone, data.Latitude, data.LatitudeSec, data.Longitude, data.LongitudeSec, data.Elevation, data.MagVariation,
 					data.ControlTower, data.Unicom, data.CTAF, data.EffectiveDate, data.CreatedAt, data.UpdatedAt,
 				)
 				m.ExpectQuery(q).
@@ -909,15 +919,15 @@ func TestAirportRepository_FindByICAOID(t *testing.T) {
 			expectOK:  false,
 		},
 		{
-			name: "db error -> panic",
+			name: "db error -> error",
 			icao: "KERR",
 			setupMock: func(m sqlmock.Sqlmock) {
 				m.ExpectQuery(q).
 					WithArgs("KERR").
-					WillReturnError(fmt.Errorf("boom"))
+					WillReturnError(errBoom)
 			},
-			expectErr: nil,   // kita assert panic, bukan error return
-			expectOK:  false, // tidak dipakai
+			expectErr: errBoom,
+			expectOK:  false,
 		},
 		{
 			name: "query returns sql.ErrNoRows",
@@ -951,14 +961,6 @@ func TestAirportRepository_FindByICAOID(t *testing.T) {
 
 			repo := NewAirportRepository(log)
 
-			if tc.name == "db error -> panic" {
-				assert.Panics(t, func() {
-					_, _ = repo.FindByICAOID(context.Background(), tx, tc.icao)
-				})
-				assert.NoError(t, tx.Commit())
-				return
-			}
-
 			if tc.expectPanic {
 				assert.Panics(t, func() {
 					_, _ = repo.FindByICAOID(context.Background(), tx, tc.icao)
@@ -1028,7 +1030,7 @@ func TestAirportRepository_FindExistsByICAOID(t *testing.T) {
 			expectErr: false,
 		},
 		{
-			name: "db error -> panic",
+			name: "db error -> error",
 			icao: "KERR",
 			setupMock: func(m sqlmock.Sqlmock) {
 				m.ExpectQuery(checkExistsQuery).
@@ -1036,7 +1038,7 @@ func TestAirportRepository_FindExistsByICAOID(t *testing.T) {
 					WillReturnError(errors.New("boom"))
 			},
 			expectOK:  false,
-			expectErr: true, // kita expect panic
+			expectErr: true, // error dikembalikan, tidak panic
 		},
 	}
 
@@ -1059,9 +1061,8 @@ func TestAirportRepository_FindExistsByICAOID(t *testing.T) {
 			repo := NewAirportRepository(log)
 
 			if tc.expectErr {
-				assert.Panics(t, func() {
-					_, _ = repo.FindExistsByICAOID(context.Background(), tx, tc.icao)
-				})
+				_, err := repo.FindExistsByICAOID(context.Background(), tx, tc.icao)
+				assert.Error(t, err)
 			} else {
 				ok, err := repo.FindExistsByICAOID(context.Background(), tx, tc.icao)
 				assert.NoError(t, err)
@@ -1126,8 +1127,9 @@ func TestAirportRepository_Update(t *testing.T) {
 			unicom = $22,
 			ctaf = $23,
 			effective_date = $24,
+			magnetic_variation = $25,
 			updated_at = NOW()
-		WHERE id = $25
+		WHERE id = $26
 		RETURNING id
 	`
 	updateQ := regexp.QuoteMeta(strings.TrimSpace(updateSQL))
@@ -1145,8 +1147,8 @@ func TestAirportRepository_Update(t *testing.T) {
 			payload:   updatedAirport,
 			expectErr: nil,
 			setupMock: func(m sqlmock.Sqlmock) {
-				args := make([]driver.Value, 0, 25)
-				for i := 0; i < 24; i++ {
+				args := make([]driver.Value, 0, 26)
+				for i := 0; i < 25; i++ {
 					args = append(args, sqlmock.AnyArg())
 				}
 				args = append(args, existingID)
@@ -1180,6 +1182,7 @@ func TestAirportRepository_Update(t *testing.T) {
 					updatedAirport.Longitude,
 					updatedAirport.LongitudeSec,
 					updatedAirport.Elevation,
+					updatedAirport.MagVariation,
 					updatedAirport.ControlTower,
 					updatedAirport.Unicom,
 					updatedAirport.CTAF,
@@ -1198,9 +1201,9 @@ func TestAirportRepository_Update(t *testing.T) {
 			payload:   updatedAirport,
 			expectErr: util.ErrNotFound,
 			setupMock: func(m sqlmock.Sqlmock) {
-				// susun args: 24 field + uuid di posisi $25
-				args := make([]driver.Value, 0, 25)
-				for i := 0; i < 24; i++ {
+				// susun args: 25 field + uuid di posisi $26
+				args := make([]driver.Value, 0, 26)
+				for i := 0; i < 25; i++ {
 					args = append(args, sqlmock.AnyArg())
 				}
 				args = append(args, notFoundID)
@@ -1320,3 +1323,251 @@ func TestAirportRepository_Delete(t *testing.T) {
 		})
 	}
 }
+
+func TestAirportRepository_ClaimForSync(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer func() {
+		assert.NoError(t, mock.ExpectationsWereMet())
+		_ = db.Close()
+	}()
+
+	mock.ExpectBegin()
+	tx, err := db.Begin()
+	assert.NoError(t, err)
+
+	retryBefore := time.Now().Add(-time.Minute)
+	staleBefore := time.Now().Add(-5 * time.Minute)
+	id := uuid.New()
+
+	mock.ExpectExec(`(?s)UPDATE\s+airports\s+SET.*WHERE sync_status = \$3 AND sync_attempts >= \$4 AND updated_at < \$5`).
+		WithArgs(50, "Giving up after 3 attempts. The sync did not finish", 10, 3, staleBefore).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`(?s)UPDATE\s+airports\s+SET.*sync_attempts = sync_attempts \+ 1.*OR \(sync_status = \$1 AND sync_attempts < \$5 AND updated_at < \$7\).*FOR UPDATE SKIP LOCKED`).
+		WithArgs(10, "on_process", 1, 50, 3, retryBefore, staleBefore, 25).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "icao_id", "sync_status", "sync_message", "sync_attempts"}).
+			AddRow(id, "KJFK", 10, "on_process", 1))
+	mock.ExpectCommit()
+
+	repo := NewAirportRepository(log)
+	airports, err := repo.ClaimForSync(context.Background(), tx, 25, 3, retryBefore, staleBefore)
+	assert.NoError(t, err)
+	assert.Len(t, airports, 1)
+	assert.Equal(t, id, *airports[0].ID)
+	assert.Equal(t, "KJFK", *airports[0].ICAOID)
+	assert.Equal(t, int64(1), *airports[0].SyncAttempts)
+
+	assert.NoError(t, tx.Commit())
+}
+
+func TestAirportRepository_ClaimForSync_GivesUpOnCappedStale(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer func() {
+		assert.NoError(t, mock.ExpectationsWereMet())
+		_ = db.Close()
+	}()
+
+	mock.ExpectBegin()
+	tx, err := db.Begin()
+	assert.NoError(t, err)
+
+	retryBefore := time.Now().Add(-time.Minute)
+	staleBefore := time.Now().Add(-5 * time.Minute)
+
+	// One airport left on process has used up its attempts: it is moved to
+	// error and not claimed again.
+	mock.ExpectExec(`(?s)UPDATE\s+airports\s+SET.*WHERE sync_status = \$3 AND sync_attempts >= \$4 AND updated_at < \$5`).
+		WithArgs(50, "Giving up after 3 attempts. The sync did not finish", 10, 3, staleBefore).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(`(?s)UPDATE\s+airports\s+SET.*OR \(sync_status = \$1 AND sync_attempts < \$5 AND updated_at < \$7\)`).
+		WithArgs(10, "on_process", 1, 50, 3, retryBefore, staleBefore, 25).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "icao_id", "sync_status", "sync_message", "sync_attempts"}))
+	mock.ExpectCommit()
+
+	repo := NewAirportRepository(log)
+	airports, err := repo.ClaimForSync(context.Background(), tx, 25, 3, retryBefore, staleBefore)
+	assert.NoError(t, err)
+	assert.Empty(t, airports)
+
+	assert.NoError(t, tx.Commit())
+}
+
+func TestAirportRepository_ClaimForSync_GiveUpError(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer func() {
+		assert.NoError(t, mock.ExpectationsWereMet())
+		_ = db.Close()
+	}()
+
+	mock.ExpectBegin()
+	tx, err := db.Begin()
+	assert.NoError(t, err)
+
+	mock.ExpectExec(`(?s)UPDATE\s+airports\s+SET`).WillReturnError(errors.New("db down"))
+	mock.ExpectRollback()
+
+	repo := NewAirportRepository(log)
+	_, err = repo.ClaimForSync(context.Background(), tx, 25, 3, time.Now(), time.Now())
+	assert.EqualError(t, err, "db down")
+
+	assert.NoError(t, tx.Rollback())
+}
+
+func TestAirportRepository_UpdateSyncStatus(t *testing.T) {
+	id := uuid.New()
+	updateRe := regexp.QuoteMeta(`UPDATE airports SET sync_status = $2, sync_message = $3 WHERE id = $1`)
+
+	cases := []struct {
+		name        string
+		id          string
+		affected    int64
+		expectedErr error
+	}{
+		{"existing ID", id.String(), 1, nil},
+		{"non-existing ID", id.String(), 0, util.ErrNotFound},
+		{"invalid UUID", "invalid-uuid", 0, util.ErrNotFound},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			assert.NoError(t, err)
+			defer func() {
+				assert.NoError(t, mock.ExpectationsWereMet())
+				_ = db.Close()
+			}()
+
+			mock.ExpectBegin()
+			tx, err := db.Begin()
+			assert.NoError(t, err)
+
+			if _, err := uuid.Parse(tc.id); err == nil {
+				mock.ExpectExec(updateRe).
+					WithArgs(id, 40, "No data found from Aviation API").
+					WillReturnResult(sqlmock.NewResult(0, tc.affected))
+			}
+			mock.ExpectCommit()
+
+			repo := NewAirportRepository(log)
+			err = repo.UpdateSyncStatus(context.Background(), tx, tc.id, enum.SYNC_NOT_FOUND, "No data found from Aviation API")
+			assert.ErrorIs(t, err, tc.expectedErr)
+
+			assert.NoError(t, tx.Commit())
+		})
+	}
+}
+
+func TestAirportRepository_FindAllICAOIDs(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer func() {
+		assert.NoError(t, mock.ExpectationsWereMet())
+		_ = db.Close()
+	}()
+
+	mock.ExpectBegin()
+	tx, err := db.Begin()
+	assert.NoError(t, err)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT icao_id FROM airports WHERE icao_id IS NOT NULL ORDER BY icao_id`)).
+		WillReturnRows(sqlmock.NewRows([]string{"icao_id"}).AddRow("KJFK").AddRow("KSEA"))
+	mock.ExpectCommit()
+
+	repo := NewAirportRepository(log)
+	codes, err := repo.FindAllICAOIDs(context.Background(), tx)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"KJFK", "KSEA"}, codes)
+
+	assert.NoError(t, tx.Commit())
+}
+
+func TestAirportRepository_Upsert(t *testing.T) {
+	for _, inserted := range []bool{true, false} {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+
+		mock.ExpectBegin()
+		tx, err := db.Begin()
+		assert.NoError(t, err)
+
+		id := uuid.New()
+		airport := model.Airport{ICAOID: util.Ptr("KJFK"), SiteNumber: util.Ptr("15793.*A"), Name: util.Ptr("JOHN F KENNEDY INTL")}
+		mock.ExpectQuery(`(?s)INSERT INTO airports.*ON CONFLICT \(icao_id\) DO UPDATE SET.*name = COALESCE\(EXCLUDED\.name, airports\.name\).*field_sources = airports\.field_sources \|\| EXCLUDED\.field_sources.*RETURNING id, icao_id, \(xmax = 0\) AS inserted`).
+			WithArgs(
+				airport.SiteNumber, airport.ICAOID, nil, nil, airport.Name,
+				nil, nil, nil, nil, nil,
+				nil, nil, nil, nil, nil,
+				nil, nil, nil, nil, nil,
+				nil, nil, nil, nil, nil,
+				nil, enum.SYNC_SYNCED.Int(), enum.SYNC_SYNCED.String(), `{"name":"nasr"}`,
+			).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "icao_id", "inserted"}).AddRow(id, "KJFK", inserted))
+
+		out, ok, err := NewAirportRepository(log).Upsert(context.Background(), tx, airport, map[string]string{"name": "nasr"})
+		assert.NoError(t, err)
+		assert.Equal(t, inserted, ok)
+		assert.Equal(t, id, *out.ID)
+		assert.Equal(t, "KJFK", *out.ICAOID)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+		_ = db.Close()
+	}
+}
+
+func TestAirportRepository_FindFieldSources(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer func() {
+		assert.NoError(t, mock.ExpectationsWereMet())
+		_ = db.Close()
+	}()
+
+	mock.ExpectBegin()
+	tx, err := db.Begin()
+	assert.NoError(t, err)
+
+	q := regexp.QuoteMeta(`SELECT field_sources FROM airports WHERE icao_id = $1`)
+	mock.ExpectQuery(q).WithArgs("KJFK").
+		WillReturnRows(sqlmock.NewRows([]string{"field_sources"}).AddRow([]byte(`{"name":"nasr","country":"ourairports"}`)))
+	mock.ExpectQuery(q).WithArgs("ZZZZ").WillReturnError(sql.ErrNoRows)
+	mock.ExpectCommit()
+
+	repo := NewAirportRepository(log)
+	sources, err := repo.FindFieldSources(context.Background(), tx, "KJFK")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"name": "nasr", "country": "ourairports"}, sources)
+
+	_, err = repo.FindFieldSources(context.Background(), tx, "ZZZZ")
+	assert.ErrorIs(t, err, util.ErrNotFound)
+
+	assert.NoError(t, tx.Commit())
+}
+
+func TestAirportRepository_UpdateFieldSources(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer func() {
+		assert.NoError(t, mock.ExpectationsWereMet())
+		_ = db.Close()
+	}()
+
+	mock.ExpectBegin()
+	tx, err := db.Begin()
+	assert.NoError(t, err)
+
+	q := regexp.QuoteMeta(`UPDATE airports SET field_sources = field_sources || $2::jsonb WHERE icao_id = $1`)
+	mock.ExpectExec(q).WithArgs("KJFK", `{"name":"aviationapi"}`).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(q).WithArgs("ZZZZ", `{"name":"aviationapi"}`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	repo := NewAirportRepository(log)
+	err = repo.UpdateFieldSources(context.Background(), tx, "KJFK", map[string]string{"name": "aviationapi"})
+	assert.NoError(t, err)
+
+	err = repo.UpdateFieldSources(context.Background(), tx, "ZZZZ", map[string]string{"name": "aviationapi"})
+	assert.ErrorIs(t, err, util.ErrNotFound)
+
+	assert.NoError(t, tx.Commit())
+}
@@ -0,0 +1,12 @@
+package repository_runway
+
+import (
+	"context"
+	"database/sql"
+	"flight-api/internal/model"
+)
+
+type IRunwayRepository interface {
+	FindByAirportID(ctx context.Context, tx *sql.Tx, airportID string) ([]model.Runway, error)
+	ReplaceByAirportID(ctx context.Context, tx *sql.Tx, airportID string, runways []model.Runway) error
+}
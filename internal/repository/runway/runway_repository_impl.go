@@ -0,0 +1,109 @@
+package repository_runway
+
+import (
+	"context"
+	"database/sql"
+	"flight-api/internal/model"
+	"flight-api/pkg/logger"
+	"flight-api/util"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+type RunwayRepository struct {
+	logger *logger.Logger
+}
+
+func NewRunwayRepository(l *logger.Logger) IRunwayRepository {
+	return &RunwayRepository{
+		logger: l,
+	}
+}
+
+// FindByAirportID returns the runway ends of an airport ordered by ident.
+// An airport without runway data yields an empty slice, not an error.
+func (r *RunwayRepository) FindByAirportID(ctx context.Context, tx *sql.Tx, airportID string) ([]model.Runway, error) {
+	SQL := `
+		SELECT id, airport_id, ident, magnetic_heading, true_heading,
+			length_ft, width_ft, surface, created_at, updated_at
+		FROM runways
+		WHERE airport_id = $1
+		ORDER BY ident`
+
+	id, err := uuid.Parse(airportID)
+	if err != nil {
+		return nil, util.ErrNotFound
+	}
+
+	rows, err := tx.QueryContext(ctx, strings.TrimSpace(SQL), id)
+	if err != nil {
+		r.logger.Errorf("Failed to find runways for airport %s: %v", airportID, err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	runways := []model.Runway{}
+	for rows.Next() {
+		runway := model.Runway{}
+		err := rows.Scan(
+			&runway.ID,
+			&runway.AirportID,
+			&runway.Ident,
+			&runway.MagneticHeading,
+			&runway.TrueHeading,
+			&runway.LengthFt,
+			&runway.WidthFt,
+			&runway.Surface,
+			&runway.CreatedAt,
+			&runway.UpdatedAt,
+		)
+		if err != nil {
+			r.logger.Errorf("Failed to scan runway: %v", err)
+			return nil, err
+		}
+		runways = append(runways, runway)
+	}
+
+	if err := rows.Err(); err != nil {
+		r.logger.Errorf("Failed to read runways for airport %s: %v", airportID, err)
+		return nil, err
+	}
+
+	return runways, nil
+}
+
+// ReplaceByAirportID replaces the runway ends stored for an airport with the
+// given ones. Runway ends must have distinct idents.
+func (r *RunwayRepository) ReplaceByAirportID(ctx context.Context, tx *sql.Tx, airportID string, runways []model.Runway) error {
+	id, err := uuid.Parse(airportID)
+	if err != nil {
+		return util.ErrNotFound
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM runways WHERE airport_id = $1`, id); err != nil {
+		r.logger.Errorf("Failed to delete runways for airport %s: %v", airportID, err)
+		return err
+	}
+	if len(runways) == 0 {
+		return nil
+	}
+
+	values := make([]string, 0, len(runways))
+	args := make([]interface{}, 0, len(runways)*7)
+	for i, runway := range runways {
+		n := i * 7
+		values = append(values, fmt.Sprintf("($%d, $%d, $%d, $%d, $%d, $%d, $%d)", n+1, n+2, n+3, n+4, n+5, n+6, n+7))
+		args = append(args, id, runway.Ident, runway.MagneticHeading, runway.TrueHeading, runway.LengthFt, runway.WidthFt, runway.Surface)
+	}
+
+	SQL := `INSERT INTO runways (airport_id, ident, magnetic_heading, true_heading, length_ft, width_ft, surface) VALUES ` +
+		strings.Join(values, ", ")
+	if _, err := tx.ExecContext(ctx, SQL, args...); err != nil {
+		r.logger.Errorf("Failed to insert runways for airport %s: %v", airportID, err)
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,28 @@
+package repository_runway
+
+import (
+	"context"
+	"database/sql"
+	"flight-api/internal/model"
+	"flight-api/util"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type RunwayRepositoryMock struct {
+	Mock mock.Mock
+}
+
+func (r *RunwayRepositoryMock) FindByAirportID(ctx context.Context, tx *sql.Tx, airportID string) ([]model.Runway, error) {
+	args := r.Mock.Called(ctx, util.MockTx{Tx: tx}, airportID)
+	var out []model.Runway
+	if v, ok := args.Get(0).([]model.Runway); ok {
+		out = v
+	}
+	return out, args.Error(1)
+}
+
+func (r *RunwayRepositoryMock) ReplaceByAirportID(ctx context.Context, tx *sql.Tx, airportID string, runways []model.Runway) error {
+	args := r.Mock.Called(ctx, util.MockTx{Tx: tx}, airportID, runways)
+	return args.Error(0)
+}
@@ -0,0 +1,175 @@
+package repository_runway
+
+import (
+	"context"
+	"errors"
+	"flight-api/internal/model"
+	"flight-api/pkg/logger"
+	"flight-api/util"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+var log = logger.NewLogger(logger.DEBUG_LEVEL)
+
+var selectRunwaysQuery = `SELECT id, airport_id, ident, magnetic_heading, true_heading,
+			length_ft, width_ft, surface, created_at, updated_at
+		FROM runways
+		WHERE airport_id = $1
+		ORDER BY ident`
+
+func newCols() []string {
+	return []string{
+		"id", "airport_id", "ident", "magnetic_heading", "true_heading",
+		"length_ft", "width_ft", "surface", "created_at", "updated_at",
+	}
+}
+
+func TestRunwayRepository_FindByAirportID(t *testing.T) {
+	airportID := uuid.New()
+	now := time.Now()
+	q := regexp.QuoteMeta(strings.TrimSpace(selectRunwaysQuery))
+	errBoom := errors.New("boom")
+
+	cases := []struct {
+		name      string
+		airportID string
+		setupMock func(sqlmock.Sqlmock)
+		expectLen int
+		expectErr error
+	}{
+		{
+			name:      "success",
+			airportID: airportID.String(),
+			setupMock: func(m sqlmock.Sqlmock) {
+				rows := sqlmock.NewRows(newCols()).
+					AddRow(uuid.New(), airportID, "04L", 40, 27, 12079, 200, "ASPH-CONC", now, now).
+					AddRow(uuid.New(), airportID, "22R", 220, 207, 12079, 200, "ASPH-CONC", now, now)
+				m.ExpectQuery(q).WithArgs(airportID).WillReturnRows(rows)
+			},
+			expectLen: 2,
+		},
+		{
+			name:      "no runway data",
+			airportID: airportID.String(),
+			setupMock: func(m sqlmock.Sqlmock) {
+				m.ExpectQuery(q).WithArgs(airportID).WillReturnRows(sqlmock.NewRows(newCols()))
+			},
+			expectLen: 0,
+		},
+		{
+			name:      "invalid uuid",
+			airportID: "invalid-uuid",
+			setupMock: func(m sqlmock.Sqlmock) {},
+			expectErr: util.ErrNotFound,
+		},
+		{
+			name:      "db error",
+			airportID: airportID.String(),
+			setupMock: func(m sqlmock.Sqlmock) {
+				m.ExpectQuery(q).WithArgs(airportID).WillReturnError(errBoom)
+			},
+			expectErr: errBoom,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			assert.NoError(t, err)
+			defer func() {
+				assert.NoError(t, mock.ExpectationsWereMet())
+				_ = db.Close()
+			}()
+
+			mock.ExpectBegin()
+			tx, err := db.Begin()
+			assert.NoError(t, err)
+
+			tc.setupMock(mock)
+			mock.ExpectCommit()
+
+			repo := NewRunwayRepository(log)
+			got, err := repo.FindByAirportID(context.Background(), tx, tc.airportID)
+			assert.ErrorIs(t, err, tc.expectErr)
+			if tc.expectErr == nil {
+				assert.Len(t, got, tc.expectLen)
+				assert.NotNil(t, got)
+			}
+
+			assert.NoError(t, tx.Commit())
+		})
+	}
+
+	t.Run("maps columns", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		tx, err := db.Begin()
+		assert.NoError(t, err)
+
+		rows := sqlmock.NewRows(newCols()).
+			AddRow(uuid.New(), airportID, "13R", 134, nil, 10000, 150, "ASPH", now, now)
+		mock.ExpectQuery(q).WithArgs(airportID).WillReturnRows(rows)
+		mock.ExpectRollback()
+
+		got, err := NewRunwayRepository(log).FindByAirportID(context.Background(), tx, airportID.String())
+		assert.NoError(t, err)
+		assert.Len(t, got, 1)
+		assert.Equal(t, "13R", *got[0].Ident)
+		assert.Equal(t, int64(134), *got[0].MagneticHeading)
+		assert.Nil(t, got[0].TrueHeading)
+		assert.Equal(t, airportID, *got[0].AirportID)
+
+		assert.NoError(t, tx.Rollback())
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestRunwayRepository_ReplaceByAirportID(t *testing.T) {
+	airportID := uuid.New()
+
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer func() {
+		assert.NoError(t, mock.ExpectationsWereMet())
+		_ = db.Close()
+	}()
+
+	mock.ExpectBegin()
+	tx, err := db.Begin()
+	assert.NoError(t, err)
+
+	runways := []model.Runway{
+		{Ident: util.Ptr("04L"), TrueHeading: util.Ptr(int64(31)), LengthFt: util.Ptr(int64(12079)), WidthFt: util.Ptr(int64(200)), Surface: util.Ptr("ASPH-CONC")},
+		{Ident: util.Ptr("22R"), TrueHeading: util.Ptr(int64(211)), LengthFt: util.Ptr(int64(12079)), WidthFt: util.Ptr(int64(200)), Surface: util.Ptr("ASPH-CONC")},
+	}
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM runways WHERE airport_id = $1`)).
+		WithArgs(airportID).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO runways (airport_id, ident, magnetic_heading, true_heading, length_ft, width_ft, surface) VALUES ($1, $2, $3, $4, $5, $6, $7), ($8, $9, $10, $11, $12, $13, $14)`)).
+		WithArgs(
+			airportID, "04L", nil, int64(31), int64(12079), int64(200), "ASPH-CONC",
+			airportID, "22R", nil, int64(211), int64(12079), int64(200), "ASPH-CONC",
+		).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	repo := NewRunwayRepository(log)
+	assert.NoError(t, repo.ReplaceByAirportID(context.Background(), tx, airportID.String(), runways))
+
+	// No runways only clears the stored ones
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM runways WHERE airport_id = $1`)).
+		WithArgs(airportID).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	assert.NoError(t, repo.ReplaceByAirportID(context.Background(), tx, airportID.String(), nil))
+
+	assert.ErrorIs(t, repo.ReplaceByAirportID(context.Background(), tx, "invalid-uuid", runways), util.ErrNotFound)
+}
@@ -0,0 +1,20 @@
+package repository_sync_job
+
+import (
+	"context"
+	"database/sql"
+	"flight-api/internal/model"
+	"time"
+)
+
+type ISyncJobRepository interface {
+	Insert(ctx context.Context, tx *sql.Tx, job model.SyncJob) (model.SyncJob, error)
+	FindByID(ctx context.Context, tx *sql.Tx, id string) (model.SyncJob, error)
+	FindAll(ctx context.Context, tx *sql.Tx, args map[string]interface{}) ([]model.SyncJob, int, error)
+	MarkRunning(ctx context.Context, tx *sql.Tx, id string) (bool, error)
+	UpdateProgress(ctx context.Context, tx *sql.Tx, id string, processed int, results []byte) (bool, error)
+	Finish(ctx context.Context, tx *sql.Tx, id string, status string, errMessage *string) (bool, error)
+	Cancel(ctx context.Context, tx *sql.Tx, id string) (bool, error)
+	Requeue(ctx context.Context, tx *sql.Tx, id string) (bool, error)
+	ClaimResumable(ctx context.Context, tx *sql.Tx, staleBefore time.Time) ([]model.SyncJob, error)
+}
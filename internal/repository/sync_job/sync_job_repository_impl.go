@@ -0,0 +1,270 @@
+package repository_sync_job
+
+import (
+	"context"
+	"database/sql"
+	"flight-api/internal/enum"
+	"flight-api/internal/model"
+	"flight-api/pkg/logger"
+	"flight-api/util"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+const syncJobColumns = `id, status, icao_codes, mode, fields, atomic, trigger, total, processed, results, error,
+			created_at, started_at, finished_at, updated_at`
+
+type SyncJobRepository struct {
+	logger *logger.Logger
+}
+
+func NewSyncJobRepository(l *logger.Logger) ISyncJobRepository {
+	return &SyncJobRepository{
+		logger: l,
+	}
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanSyncJob(row rowScanner) (model.SyncJob, error) {
+	job := model.SyncJob{}
+	err := row.Scan(
+		&job.ID,
+		&job.Status,
+		pq.Array(&job.ICAOCodes),
+		&job.Mode,
+		pq.Array(&job.Fields),
+		&job.Atomic,
+		&job.Trigger,
+		&job.Total,
+		&job.Processed,
+		&job.Results,
+		&job.Error,
+		&job.CreatedAt,
+		&job.StartedAt,
+		&job.FinishedAt,
+		&job.UpdatedAt,
+	)
+	return job, err
+}
+
+func (r *SyncJobRepository) Insert(ctx context.Context, tx *sql.Tx, job model.SyncJob) (model.SyncJob, error) {
+	SQL := `
+		INSERT INTO sync_jobs (status, icao_codes, mode, fields, atomic, trigger, total)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING ` + syncJobColumns
+
+	mode := enum.SYNC_MODE_INSERT.String()
+	if job.Mode != nil && *job.Mode != "" {
+		mode = *job.Mode
+	}
+	fields := job.Fields
+	if fields == nil {
+		fields = []string{}
+	}
+	atomic := job.Atomic != nil && *job.Atomic
+	trigger := enum.SYNC_RUN_TRIGGER_JOB.String()
+	if job.Trigger != nil && *job.Trigger != "" {
+		trigger = *job.Trigger
+	}
+
+	row := tx.QueryRowContext(ctx, strings.TrimSpace(SQL), enum.SYNC_JOB_QUEUED.String(), pq.Array(job.ICAOCodes), mode, pq.Array(fields), atomic, trigger, len(job.ICAOCodes))
+	result, err := scanSyncJob(row)
+	if err != nil {
+		r.logger.Errorf("Failed to insert sync job: %v", err)
+		return model.SyncJob{}, err
+	}
+
+	r.logger.Debugf("Inserted sync job with ID: %s", result.ID.String())
+	return result, nil
+}
+
+func (r *SyncJobRepository) FindByID(ctx context.Context, tx *sql.Tx, id string) (model.SyncJob, error) {
+	SQL := `SELECT ` + syncJobColumns + `
+		FROM sync_jobs
+		WHERE id = $1`
+
+	jobID, err := uuid.Parse(id)
+	if err != nil {
+		return model.SyncJob{}, util.ErrNotFound
+	}
+
+	job, err := scanSyncJob(tx.QueryRowContext(ctx, strings.TrimSpace(SQL), jobID))
+	if err == sql.ErrNoRows {
+		return model.SyncJob{}, util.ErrNotFound
+	} else if err != nil {
+		r.logger.Errorf("Failed to find sync job by ID %s: %v", id, err)
+		return model.SyncJob{}, err
+	}
+
+	return job, nil
+}
+
+// FindAll lists jobs newest first. args holds the pagination and an
+// optional "status" filter.
+func (r *SyncJobRepository) FindAll(ctx context.Context, tx *sql.Tx, args map[string]interface{}) ([]model.SyncJob, int, error) {
+	limit, offset := util.ParsePagination(args)
+	status, _ := args["status"].(string)
+
+	SQL := `SELECT ` + syncJobColumns + `
+		FROM sync_jobs
+		WHERE ($3 = '' OR status = $3)
+		ORDER BY created_at DESC
+		LIMIT $1
+		OFFSET $2`
+
+	rows, err := tx.QueryContext(ctx, strings.TrimSpace(SQL), limit, offset, status)
+	if err != nil {
+		r.logger.Errorf("Failed to find sync jobs: %v", err)
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	jobs := []model.SyncJob{}
+	for rows.Next() {
+		job, err := scanSyncJob(rows)
+		if err != nil {
+			r.logger.Errorf("Failed to scan sync job: %v", err)
+			return nil, 0, err
+		}
+		jobs = append(jobs, job)
+	}
+	if err := rows.Err(); err != nil {
+		r.logger.Errorf("Failed to read sync jobs: %v", err)
+		return nil, 0, err
+	}
+
+	var total int
+	TotalSQL := `SELECT COUNT(*) FROM sync_jobs WHERE ($1 = '' OR status = $1)`
+	if err := tx.QueryRowContext(ctx, TotalSQL, status).Scan(&total); err != nil {
+		r.logger.Errorf("Failed to count sync jobs: %v", err)
+		return nil, 0, err
+	}
+
+	return jobs, total, nil
+}
+
+// MarkRunning starts a queued job. It reports false when the job is no
+// longer queued, e.g. it was canceled first.
+func (r *SyncJobRepository) MarkRunning(ctx context.Context, tx *sql.Tx, id string) (bool, error) {
+	SQL := `
+		UPDATE sync_jobs SET
+			status = $2,
+			started_at = COALESCE(started_at, NOW())
+		WHERE id = $1 AND status IN ($2, $3)`
+
+	return r.exec(ctx, tx, "MarkRunning", SQL, id, enum.SYNC_JOB_RUNNING.String(), enum.SYNC_JOB_QUEUED.String())
+}
+
+// UpdateProgress stores the results processed so far. It reports false
+// when the job is no longer running, which is how a worker learns that the
+// job was canceled elsewhere.
+func (r *SyncJobRepository) UpdateProgress(ctx context.Context, tx *sql.Tx, id string, processed int, results []byte) (bool, error) {
+	SQL := `
+		UPDATE sync_jobs SET
+			processed = $2,
+			results = $3
+		WHERE id = $1 AND status = $4`
+
+	return r.exec(ctx, tx, "UpdateProgress", SQL, id, processed, results, enum.SYNC_JOB_RUNNING.String())
+}
+
+// Finish moves a running job to a terminal status.
+func (r *SyncJobRepository) Finish(ctx context.Context, tx *sql.Tx, id string, status string, errMessage *string) (bool, error) {
+	SQL := `
+		UPDATE sync_jobs SET
+			status = $2,
+			error = $3,
+			finished_at = NOW()
+		WHERE id = $1 AND status = $4`
+
+	return r.exec(ctx, tx, "Finish", SQL, id, status, errMessage, enum.SYNC_JOB_RUNNING.String())
+}
+
+// Cancel cancels an unfinished job. It reports false when the job has
+// already finished.
+func (r *SyncJobRepository) Cancel(ctx context.Context, tx *sql.Tx, id string) (bool, error) {
+	SQL := `
+		UPDATE sync_jobs SET
+			status = $2,
+			finished_at = NOW()
+		WHERE id = $1 AND status IN ($3, $4)`
+
+	return r.exec(ctx, tx, "Cancel", SQL, id, enum.SYNC_JOB_CANCELED.String(), enum.SYNC_JOB_QUEUED.String(), enum.SYNC_JOB_RUNNING.String())
+}
+
+// Requeue hands a running job back to the queue, for a worker that stops
+// before finishing it.
+func (r *SyncJobRepository) Requeue(ctx context.Context, tx *sql.Tx, id string) (bool, error) {
+	SQL := `
+		UPDATE sync_jobs SET
+			status = $2
+		WHERE id = $1 AND status = $3`
+
+	return r.exec(ctx, tx, "Requeue", SQL, id, enum.SYNC_JOB_QUEUED.String(), enum.SYNC_JOB_RUNNING.String())
+}
+
+// ClaimResumable marks as running and returns the queued jobs, and the
+// running ones not updated since staleBefore, whose worker is presumed
+// gone. Rows locked by another replica's claim are skipped.
+func (r *SyncJobRepository) ClaimResumable(ctx context.Context, tx *sql.Tx, staleBefore time.Time) ([]model.SyncJob, error) {
+	SQL := `
+		UPDATE sync_jobs SET
+			status = $1,
+			started_at = COALESCE(started_at, NOW())
+		WHERE id IN (
+			SELECT id FROM sync_jobs
+			WHERE status = $2 OR (status = $1 AND updated_at < $3)
+			ORDER BY created_at
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + syncJobColumns
+
+	rows, err := tx.QueryContext(ctx, strings.TrimSpace(SQL), enum.SYNC_JOB_RUNNING.String(), enum.SYNC_JOB_QUEUED.String(), staleBefore)
+	if err != nil {
+		r.logger.Errorf("Failed to claim sync jobs: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	jobs := []model.SyncJob{}
+	for rows.Next() {
+		job, err := scanSyncJob(rows)
+		if err != nil {
+			r.logger.Errorf("Failed to scan sync job: %v", err)
+			return nil, err
+		}
+		jobs = append(jobs, job)
+	}
+	if err := rows.Err(); err != nil {
+		r.logger.Errorf("Failed to read claimed sync jobs: %v", err)
+		return nil, err
+	}
+
+	return jobs, nil
+}
+
+// exec runs a single-row update and reports whether it matched the row.
+func (r *SyncJobRepository) exec(ctx context.Context, tx *sql.Tx, op string, SQL string, id string, args ...interface{}) (bool, error) {
+	jobID, err := uuid.Parse(id)
+	if err != nil {
+		return false, util.ErrNotFound
+	}
+
+	result, err := tx.ExecContext(ctx, strings.TrimSpace(SQL), append([]interface{}{jobID}, args...)...)
+	if err != nil {
+		r.logger.Errorf("[%s] Failed to update sync job %s: %v", op, id, err)
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
@@ -0,0 +1,80 @@
+package repository_sync_job
+
+import (
+	"context"
+	"database/sql"
+	"flight-api/internal/model"
+	"flight-api/util"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type SyncJobRepositoryMock struct {
+	Mock mock.Mock
+}
+
+func (r *SyncJobRepositoryMock) Insert(ctx context.Context, tx *sql.Tx, job model.SyncJob) (model.SyncJob, error) {
+	args := r.Mock.Called(ctx, util.MockTx{Tx: tx}, job)
+	var out model.SyncJob
+	if v, ok := args.Get(0).(model.SyncJob); ok {
+		out = v
+	}
+	return out, args.Error(1)
+}
+
+func (r *SyncJobRepositoryMock) FindByID(ctx context.Context, tx *sql.Tx, id string) (model.SyncJob, error) {
+	args := r.Mock.Called(ctx, util.MockTx{Tx: tx}, id)
+	var out model.SyncJob
+	if v, ok := args.Get(0).(model.SyncJob); ok {
+		out = v
+	}
+	return out, args.Error(1)
+}
+
+func (r *SyncJobRepositoryMock) FindAll(ctx context.Context, tx *sql.Tx, args map[string]interface{}) ([]model.SyncJob, int, error) {
+	call := r.Mock.Called(ctx, util.MockTx{Tx: tx}, args)
+	var list []model.SyncJob
+	if v, ok := call.Get(0).([]model.SyncJob); ok {
+		list = v
+	}
+	total := 0
+	if v, ok := call.Get(1).(int); ok {
+		total = v
+	}
+	return list, total, call.Error(2)
+}
+
+func (r *SyncJobRepositoryMock) MarkRunning(ctx context.Context, tx *sql.Tx, id string) (bool, error) {
+	args := r.Mock.Called(ctx, util.MockTx{Tx: tx}, id)
+	return args.Bool(0), args.Error(1)
+}
+
+func (r *SyncJobRepositoryMock) UpdateProgress(ctx context.Context, tx *sql.Tx, id string, processed int, results []byte) (bool, error) {
+	args := r.Mock.Called(ctx, util.MockTx{Tx: tx}, id, processed, results)
+	return args.Bool(0), args.Error(1)
+}
+
+func (r *SyncJobRepositoryMock) Finish(ctx context.Context, tx *sql.Tx, id string, status string, errMessage *string) (bool, error) {
+	args := r.Mock.Called(ctx, util.MockTx{Tx: tx}, id, status, errMessage)
+	return args.Bool(0), args.Error(1)
+}
+
+func (r *SyncJobRepositoryMock) Cancel(ctx context.Context, tx *sql.Tx, id string) (bool, error) {
+	args := r.Mock.Called(ctx, util.MockTx{Tx: tx}, id)
+	return args.Bool(0), args.Error(1)
+}
+
+func (r *SyncJobRepositoryMock) Requeue(ctx context.Context, tx *sql.Tx, id string) (bool, error) {
+	args := r.Mock.Called(ctx, util.MockTx{Tx: tx}, id)
+	return args.Bool(0), args.Error(1)
+}
+
+func (r *SyncJobRepositoryMock) ClaimResumable(ctx context.Context, tx *sql.Tx, staleBefore time.Time) ([]model.SyncJob, error) {
+	args := r.Mock.Called(ctx, util.MockTx{Tx: tx}, staleBefore)
+	var out []model.SyncJob
+	if v, ok := args.Get(0).([]model.SyncJob); ok {
+		out = v
+	}
+	return out, args.Error(1)
+}
@@ -0,0 +1,222 @@
+package repository_sync_job
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"flight-api/internal/model"
+	"flight-api/pkg/logger"
+	"flight-api/util"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var log = logger.NewLogger(logger.DEBUG_LEVEL)
+
+func newCols() []string {
+	return []string{
+		"id", "status", "icao_codes", "mode", "fields", "atomic", "trigger", "total", "processed", "results", "error",
+		"created_at", "started_at", "finished_at", "updated_at",
+	}
+}
+
+func newTx(t *testing.T) (*sql.Tx, sqlmock.Sqlmock) {
+	t.Helper()
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		assert.NoError(t, mock.ExpectationsWereMet())
+		_ = db.Close()
+	})
+
+	mock.ExpectBegin()
+	tx, err := db.Begin()
+	require.NoError(t, err)
+	return tx, mock
+}
+
+func TestSyncJobRepository_Insert(t *testing.T) {
+	tx, mock := newTx(t)
+	id := uuid.New()
+	now := time.Now()
+
+	mock.ExpectQuery(`(?s)INSERT\s+INTO\s+sync_jobs\s*\(status, icao_codes, mode, fields, atomic, trigger, total\).*RETURNING`).
+		WithArgs("queued", `{"KJFK","KSEA"}`, "refresh", `{"name"}`, false, "schedule", 2).
+		WillReturnRows(sqlmock.NewRows(newCols()).AddRow(id, "queued", `{KJFK,KSEA}`, "refresh", `{name}`, false, "schedule", 2, 0, []byte(`[]`), nil, now, nil, nil, now))
+
+	out, err := NewSyncJobRepository(log).Insert(context.Background(), tx, model.SyncJob{
+		ICAOCodes: []string{"KJFK", "KSEA"},
+		Mode:      util.Ptr("refresh"),
+		Fields:    []string{"name"},
+		Trigger:   util.Ptr("schedule"),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, id, *out.ID)
+	assert.Equal(t, "queued", *out.Status)
+	assert.Equal(t, []string{"KJFK", "KSEA"}, out.ICAOCodes)
+	assert.Equal(t, "refresh", *out.Mode)
+	assert.Equal(t, []string{"name"}, out.Fields)
+	assert.Equal(t, "schedule", *out.Trigger)
+	assert.Equal(t, int64(2), *out.Total)
+	assert.Equal(t, []byte(`[]`), out.Results)
+	assert.Nil(t, out.StartedAt)
+}
+
+func TestSyncJobRepository_FindByID(t *testing.T) {
+	id := uuid.New()
+	now := time.Now()
+	q := regexp.QuoteMeta(`FROM sync_jobs
+		WHERE id = $1`)
+
+	t.Run("found", func(t *testing.T) {
+		tx, mock := newTx(t)
+		mock.ExpectQuery(q).WithArgs(id).
+			WillReturnRows(sqlmock.NewRows(newCols()).AddRow(id, "running", `{KJFK}`, "insert", `{}`, false, "job", 1, 0, []byte(`[]`), nil, now, now, nil, now))
+
+		out, err := NewSyncJobRepository(log).FindByID(context.Background(), tx, id.String())
+		require.NoError(t, err)
+		assert.Equal(t, "running", *out.Status)
+		assert.NotNil(t, out.StartedAt)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		tx, mock := newTx(t)
+		mock.ExpectQuery(q).WithArgs(id).WillReturnRows(sqlmock.NewRows(newCols()))
+
+		_, err := NewSyncJobRepository(log).FindByID(context.Background(), tx, id.String())
+		assert.ErrorIs(t, err, util.ErrNotFound)
+	})
+
+	t.Run("invalid uuid", func(t *testing.T) {
+		tx, _ := newTx(t)
+		_, err := NewSyncJobRepository(log).FindByID(context.Background(), tx, "invalid-uuid")
+		assert.ErrorIs(t, err, util.ErrNotFound)
+	})
+}
+
+func TestSyncJobRepository_FindAll(t *testing.T) {
+	tx, mock := newTx(t)
+	now := time.Now()
+
+	mock.ExpectQuery(regexp.QuoteMeta(`WHERE ($3 = '' OR status = $3)`)).
+		WithArgs(10, 0, "failed").
+		WillReturnRows(sqlmock.NewRows(newCols()).
+			AddRow(uuid.New(), "failed", `{KJFK}`, "insert", `{}`, false, "job", 1, 1, []byte(`[]`), "boom", now, now, now, now))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT COUNT(*) FROM sync_jobs WHERE ($1 = '' OR status = $1)`)).
+		WithArgs("failed").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(7))
+
+	args := map[string]interface{}{"limit": 10, "offset": 0, "status": "failed"}
+	jobs, total, err := NewSyncJobRepository(log).FindAll(context.Background(), tx, args)
+	require.NoError(t, err)
+	assert.Len(t, jobs, 1)
+	assert.Equal(t, 7, total)
+	assert.Equal(t, "boom", *jobs[0].Error)
+}
+
+func TestSyncJobRepository_Updates(t *testing.T) {
+	id := uuid.New()
+	errBoom := errors.New("boom")
+
+	cases := []struct {
+		name     string
+		pattern  string
+		args     []interface{}
+		affected int64
+		err      error
+		call     func(r ISyncJobRepository, tx *sql.Tx) (bool, error)
+	}{
+		{
+			name:     "mark running",
+			pattern:  `status = \$2,\s+started_at = COALESCE`,
+			args:     []interface{}{id, "running", "queued"},
+			affected: 1,
+			call: func(r ISyncJobRepository, tx *sql.Tx) (bool, error) {
+				return r.MarkRunning(context.Background(), tx, id.String())
+			},
+		},
+		{
+			name:    "update progress on a canceled job",
+			pattern: `processed = \$2,\s+results = \$3\s+WHERE id = \$1 AND status = \$4`,
+			args:    []interface{}{id, 3, []byte(`[]`), "running"},
+			call: func(r ISyncJobRepository, tx *sql.Tx) (bool, error) {
+				return r.UpdateProgress(context.Background(), tx, id.String(), 3, []byte(`[]`))
+			},
+		},
+		{
+			name:     "finish",
+			pattern:  `error = \$3,\s+finished_at = NOW\(\)`,
+			args:     []interface{}{id, "failed", util.Ptr("boom"), "running"},
+			affected: 1,
+			call: func(r ISyncJobRepository, tx *sql.Tx) (bool, error) {
+				return r.Finish(context.Background(), tx, id.String(), "failed", util.Ptr("boom"))
+			},
+		},
+		{
+			name:     "cancel",
+			pattern:  `WHERE id = \$1 AND status IN \(\$3, \$4\)`,
+			args:     []interface{}{id, "canceled", "queued", "running"},
+			affected: 1,
+			call: func(r ISyncJobRepository, tx *sql.Tx) (bool, error) {
+				return r.Cancel(context.Background(), tx, id.String())
+			},
+		},
+		{
+			name:    "requeue with db error",
+			pattern: `SET\s+status = \$2\s+WHERE id = \$1 AND status = \$3`,
+			args:    []interface{}{id, "queued", "running"},
+			err:     errBoom,
+			call: func(r ISyncJobRepository, tx *sql.Tx) (bool, error) {
+				return r.Requeue(context.Background(), tx, id.String())
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			tx, mock := newTx(t)
+
+			exp := mock.ExpectExec(tc.pattern).WithArgs(driverValues(tc.args)...)
+			if tc.err != nil {
+				exp.WillReturnError(tc.err)
+			} else {
+				exp.WillReturnResult(sqlmock.NewResult(0, tc.affected))
+			}
+
+			ok, err := tc.call(NewSyncJobRepository(log), tx)
+			assert.ErrorIs(t, err, tc.err)
+			assert.Equal(t, tc.affected > 0, ok)
+		})
+	}
+}
+
+func TestSyncJobRepository_ClaimResumable(t *testing.T) {
+	tx, mock := newTx(t)
+	staleBefore := time.Now().Add(-5 * time.Minute)
+	now := time.Now()
+
+	mock.ExpectQuery(`(?s)WHERE status = \$2 OR \(status = \$1 AND updated_at < \$3\).*FOR UPDATE SKIP LOCKED`).
+		WithArgs("running", "queued", staleBefore).
+		WillReturnRows(sqlmock.NewRows(newCols()).
+			AddRow(uuid.New(), "running", `{KJFK,KSEA}`, "insert", `{}`, false, "job", 2, 1, []byte(`[{"icao_code":"KJFK"}]`), nil, now, now, nil, now))
+
+	jobs, err := NewSyncJobRepository(log).ClaimResumable(context.Background(), tx, staleBefore)
+	require.NoError(t, err)
+	require.Len(t, jobs, 1)
+	assert.Equal(t, int64(1), *jobs[0].Processed)
+}
+
+func driverValues(in []interface{}) []driver.Value {
+	out := make([]driver.Value, len(in))
+	for i, v := range in {
+		out[i] = v
+	}
+	return out
+}
@@ -0,0 +1,14 @@
+package repository_sync_run
+
+import (
+	"context"
+	"database/sql"
+	"flight-api/internal/model"
+)
+
+type ISyncRunRepository interface {
+	Insert(ctx context.Context, tx *sql.Tx, run model.SyncRun, items []model.SyncRunItem) (model.SyncRun, error)
+	FindByID(ctx context.Context, tx *sql.Tx, id string) (model.SyncRun, error)
+	FindAll(ctx context.Context, tx *sql.Tx, args map[string]interface{}) ([]model.SyncRun, int, error)
+	FindItems(ctx context.Context, tx *sql.Tx, runID string, outcomes []string) ([]model.SyncRunItem, error)
+}
@@ -0,0 +1,235 @@
+package repository_sync_run
+
+import (
+	"context"
+	"database/sql"
+	"flight-api/internal/model"
+	"flight-api/pkg/logger"
+	"flight-api/util"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+const syncRunColumns = `id, trigger, job_id, icao_codes, mode, fields, dry_run, atomic, total, failed, counts,
+			started_at, finished_at, created_at`
+
+// itemsPerInsert keeps a multi-row insert of run items well below the
+// Postgres limit of 65535 parameters.
+const itemsPerInsert = 1000
+
+type SyncRunRepository struct {
+	logger *logger.Logger
+}
+
+func NewSyncRunRepository(l *logger.Logger) ISyncRunRepository {
+	return &SyncRunRepository{
+		logger: l,
+	}
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanSyncRun(row rowScanner) (model.SyncRun, error) {
+	run := model.SyncRun{}
+	err := row.Scan(
+		&run.ID,
+		&run.Trigger,
+		&run.JobID,
+		pq.Array(&run.ICAOCodes),
+		&run.Mode,
+		pq.Array(&run.Fields),
+		&run.DryRun,
+		&run.Atomic,
+		&run.Total,
+		&run.Failed,
+		&run.Counts,
+		&run.StartedAt,
+		&run.FinishedAt,
+		&run.CreatedAt,
+	)
+	return run, err
+}
+
+// Insert stores a run and its items, numbered in the order given.
+func (r *SyncRunRepository) Insert(ctx context.Context, tx *sql.Tx, run model.SyncRun, items []model.SyncRunItem) (model.SyncRun, error) {
+	SQL := `
+		INSERT INTO sync_runs (trigger, job_id, icao_codes, mode, fields, dry_run, atomic, total, failed, counts, started_at, finished_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		RETURNING ` + syncRunColumns
+
+	fields := run.Fields
+	if fields == nil {
+		fields = []string{}
+	}
+	counts := run.Counts
+	if len(counts) == 0 {
+		counts = []byte(`{}`)
+	}
+
+	row := tx.QueryRowContext(ctx, strings.TrimSpace(SQL),
+		run.Trigger, run.JobID, pq.Array(run.ICAOCodes), run.Mode, pq.Array(fields), run.DryRun, run.Atomic,
+		run.Total, run.Failed, string(counts), run.StartedAt, run.FinishedAt,
+	)
+	result, err := scanSyncRun(row)
+	if err != nil {
+		r.logger.Errorf("Failed to insert sync run: %v", err)
+		return model.SyncRun{}, err
+	}
+
+	position := 0
+	for chunk := range slices.Chunk(items, itemsPerInsert) {
+		values := make([]string, 0, len(chunk))
+		args := make([]interface{}, 0, len(chunk)*7)
+		for i, item := range chunk {
+			n := i * 7
+			values = append(values, fmt.Sprintf("($%d, $%d, $%d, $%d, $%d, $%d, $%d)", n+1, n+2, n+3, n+4, n+5, n+6, n+7))
+			var changes interface{}
+			if len(item.Changes) > 0 {
+				changes = string(item.Changes)
+			}
+			args = append(args, result.ID, position, item.ICAOCode, item.Outcome, item.Status, item.Message, changes)
+			position++
+		}
+
+		SQL := `INSERT INTO sync_run_items (run_id, position, icao_code, outcome, status, message, changes) VALUES ` +
+			strings.Join(values, ", ")
+		if _, err := tx.ExecContext(ctx, SQL, args...); err != nil {
+			r.logger.Errorf("Failed to insert items of sync run %s: %v", result.ID, err)
+			return model.SyncRun{}, err
+		}
+	}
+
+	r.logger.Debugf("Inserted sync run with ID: %s", result.ID.String())
+	return result, nil
+}
+
+func (r *SyncRunRepository) FindByID(ctx context.Context, tx *sql.Tx, id string) (model.SyncRun, error) {
+	SQL := `SELECT ` + syncRunColumns + `
+		FROM sync_runs
+		WHERE id = $1`
+
+	runID, err := uuid.Parse(id)
+	if err != nil {
+		return model.SyncRun{}, util.ErrNotFound
+	}
+
+	run, err := scanSyncRun(tx.QueryRowContext(ctx, strings.TrimSpace(SQL), runID))
+	if err == sql.ErrNoRows {
+		return model.SyncRun{}, util.ErrNotFound
+	} else if err != nil {
+		r.logger.Errorf("Failed to find sync run by ID %s: %v", id, err)
+		return model.SyncRun{}, err
+	}
+
+	return run, nil
+}
+
+// FindAll lists runs newest first. args holds the pagination and the
+// optional filters: "outcomes", keeping the runs with a code in any of them,
+// "trigger" and "job_id", keeping the runs of one job.
+func (r *SyncRunRepository) FindAll(ctx context.Context, tx *sql.Tx, args map[string]interface{}) ([]model.SyncRun, int, error) {
+	limit, offset := util.ParsePagination(args)
+	outcomes, _ := args["outcomes"].([]string)
+	if outcomes == nil {
+		outcomes = []string{}
+	}
+	trigger, _ := args["trigger"].(string)
+
+	var jobID interface{}
+	if value, _ := args["job_id"].(string); value != "" {
+		id, err := uuid.Parse(value)
+		if err != nil {
+			return []model.SyncRun{}, 0, nil
+		}
+		jobID = id
+	}
+
+	SQL := `SELECT ` + syncRunColumns + `
+		FROM sync_runs
+		WHERE (cardinality($3::text[]) = 0 OR counts ?| $3::text[])
+			AND ($4 = '' OR trigger = $4)
+			AND ($5::uuid IS NULL OR job_id = $5::uuid)
+		ORDER BY started_at DESC
+		LIMIT $1
+		OFFSET $2`
+
+	rows, err := tx.QueryContext(ctx, strings.TrimSpace(SQL), limit, offset, pq.Array(outcomes), trigger, jobID)
+	if err != nil {
+		r.logger.Errorf("Failed to find sync runs: %v", err)
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	runs := []model.SyncRun{}
+	for rows.Next() {
+		run, err := scanSyncRun(rows)
+		if err != nil {
+			r.logger.Errorf("Failed to scan sync run: %v", err)
+			return nil, 0, err
+		}
+		runs = append(runs, run)
+	}
+	if err := rows.Err(); err != nil {
+		r.logger.Errorf("Failed to read sync runs: %v", err)
+		return nil, 0, err
+	}
+
+	var total int
+	TotalSQL := `SELECT COUNT(*) FROM sync_runs
+		WHERE (cardinality($1::text[]) = 0 OR counts ?| $1::text[])
+			AND ($2 = '' OR trigger = $2)
+			AND ($3::uuid IS NULL OR job_id = $3::uuid)`
+	if err := tx.QueryRowContext(ctx, strings.TrimSpace(TotalSQL), pq.Array(outcomes), trigger, jobID).Scan(&total); err != nil {
+		r.logger.Errorf("Failed to count sync runs: %v", err)
+		return nil, 0, err
+	}
+
+	return runs, total, nil
+}
+
+// FindItems lists the items of a run in order, only those with one of
+// outcomes when any are given.
+func (r *SyncRunRepository) FindItems(ctx context.Context, tx *sql.Tx, runID string, outcomes []string) ([]model.SyncRunItem, error) {
+	SQL := `SELECT id, run_id, position, icao_code, outcome, status, message, changes
+		FROM sync_run_items
+		WHERE run_id = $1 AND (cardinality($2::text[]) = 0 OR outcome = ANY($2::text[]))
+		ORDER BY position`
+
+	id, err := uuid.Parse(runID)
+	if err != nil {
+		return nil, util.ErrNotFound
+	}
+	if outcomes == nil {
+		outcomes = []string{}
+	}
+
+	rows, err := tx.QueryContext(ctx, strings.TrimSpace(SQL), id, pq.Array(outcomes))
+	if err != nil {
+		r.logger.Errorf("Failed to find items of sync run %s: %v", runID, err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []model.SyncRunItem{}
+	for rows.Next() {
+		item := model.SyncRunItem{}
+		err := rows.Scan(&item.ID, &item.RunID, &item.Position, &item.ICAOCode, &item.Outcome, &item.Status, &item.Message, &item.Changes)
+		if err != nil {
+			r.logger.Errorf("Failed to scan sync run item: %v", err)
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		r.logger.Errorf("Failed to read items of sync run %s: %v", runID, err)
+		return nil, err
+	}
+
+	return items, nil
+}
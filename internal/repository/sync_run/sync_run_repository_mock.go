@@ -0,0 +1,50 @@
+package repository_sync_run
+
+import (
+	"context"
+	"database/sql"
+	"flight-api/internal/model"
+	"flight-api/util"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type SyncRunRepositoryMock struct {
+	Mock mock.Mock
+}
+
+func (r *SyncRunRepositoryMock) Insert(ctx context.Context, tx *sql.Tx, run model.SyncRun, items []model.SyncRunItem) (model.SyncRun, error) {
+	args := r.Mock.Called(ctx, util.MockTx{Tx: tx}, run, items)
+	var out model.SyncRun
+	if v, ok := args.Get(0).(model.SyncRun); ok {
+		out = v
+	}
+	return out, args.Error(1)
+}
+
+func (r *SyncRunRepositoryMock) FindByID(ctx context.Context, tx *sql.Tx, id string) (model.SyncRun, error) {
+	args := r.Mock.Called(ctx, util.MockTx{Tx: tx}, id)
+	var out model.SyncRun
+	if v, ok := args.Get(0).(model.SyncRun); ok {
+		out = v
+	}
+	return out, args.Error(1)
+}
+
+func (r *SyncRunRepositoryMock) FindAll(ctx context.Context, tx *sql.Tx, args map[string]interface{}) ([]model.SyncRun, int, error) {
+	call := r.Mock.Called(ctx, util.MockTx{Tx: tx}, args)
+	var out []model.SyncRun
+	if v, ok := call.Get(0).([]model.SyncRun); ok {
+		out = v
+	}
+	return out, call.Int(1), call.Error(2)
+}
+
+func (r *SyncRunRepositoryMock) FindItems(ctx context.Context, tx *sql.Tx, runID string, outcomes []string) ([]model.SyncRunItem, error) {
+	args := r.Mock.Called(ctx, util.MockTx{Tx: tx}, runID, outcomes)
+	var out []model.SyncRunItem
+	if v, ok := args.Get(0).([]model.SyncRunItem); ok {
+		out = v
+	}
+	return out, args.Error(1)
+}
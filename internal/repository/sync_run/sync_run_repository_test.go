@@ -0,0 +1,204 @@
+package repository_sync_run
+
+import (
+	"context"
+	"database/sql"
+	"flight-api/internal/model"
+	"flight-api/pkg/logger"
+	"flight-api/util"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var log = logger.NewLogger(logger.DEBUG_LEVEL)
+
+func newCols() []string {
+	return []string{
+		"id", "trigger", "job_id", "icao_codes", "mode", "fields", "dry_run", "atomic", "total", "failed", "counts",
+		"started_at", "finished_at", "created_at",
+	}
+}
+
+func newTx(t *testing.T) (*sql.Tx, sqlmock.Sqlmock) {
+	t.Helper()
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		assert.NoError(t, mock.ExpectationsWereMet())
+		_ = db.Close()
+	})
+
+	mock.ExpectBegin()
+	tx, err := db.Begin()
+	require.NoError(t, err)
+	return tx, mock
+}
+
+func TestSyncRunRepository_Insert(t *testing.T) {
+	tx, mock := newTx(t)
+	id := uuid.New()
+	jobID := uuid.New()
+	now := time.Now()
+
+	run := model.SyncRun{
+		Trigger:    util.Ptr("job"),
+		JobID:      &jobID,
+		ICAOCodes:  []string{"KJFK", "KXXX"},
+		Mode:       util.Ptr("insert"),
+		DryRun:     util.Ptr(false),
+		Atomic:     util.Ptr(false),
+		Total:      util.Ptr(int64(2)),
+		Failed:     util.Ptr(int64(1)),
+		Counts:     []byte(`{"inserted":1,"not_found":1}`),
+		StartedAt:  &now,
+		FinishedAt: &now,
+	}
+	mock.ExpectQuery(`(?s)INSERT INTO sync_runs .*RETURNING id, trigger`).
+		WithArgs("job", jobID, pq.Array([]string{"KJFK", "KXXX"}), "insert", pq.Array([]string{}), false, false,
+			int64(2), int64(1), `{"inserted":1,"not_found":1}`, now, now).
+		WillReturnRows(sqlmock.NewRows(newCols()).AddRow(
+			id, "job", jobID, pq.Array([]string{"KJFK", "KXXX"}), "insert", pq.Array([]string{}), false, false,
+			2, 1, []byte(`{"inserted":1,"not_found":1}`), now, now, now,
+		))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO sync_run_items (run_id, position, icao_code, outcome, status, message, changes) VALUES ($1, $2, $3, $4, $5, $6, $7), ($8, $9, $10, $11, $12, $13, $14)`)).
+		WithArgs(
+			id, 0, "KJFK", "inserted", "Inserted", "Airport data successfully inserted", nil,
+			id, 1, "KXXX", "not_found", "Not Found", "No data found from Aviation API.", nil,
+		).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	items := []model.SyncRunItem{
+		{ICAOCode: util.Ptr("KJFK"), Outcome: util.Ptr("inserted"), Status: util.Ptr("Inserted"), Message: util.Ptr("Airport data successfully inserted")},
+		{ICAOCode: util.Ptr("KXXX"), Outcome: util.Ptr("not_found"), Status: util.Ptr("Not Found"), Message: util.Ptr("No data found from Aviation API.")},
+	}
+	out, err := NewSyncRunRepository(log).Insert(context.Background(), tx, run, items)
+	require.NoError(t, err)
+	assert.Equal(t, id, *out.ID)
+	assert.Equal(t, jobID, *out.JobID)
+	assert.Equal(t, []string{"KJFK", "KXXX"}, out.ICAOCodes)
+	assert.JSONEq(t, `{"inserted":1,"not_found":1}`, string(out.Counts))
+}
+
+func TestSyncRunRepository_FindByID(t *testing.T) {
+	tx, mock := newTx(t)
+	id := uuid.New()
+	now := time.Now()
+	q := regexp.QuoteMeta(`FROM sync_runs
+		WHERE id = $1`)
+
+	mock.ExpectQuery(q).WithArgs(id).
+		WillReturnRows(sqlmock.NewRows(newCols()).AddRow(
+			id, "api", nil, pq.Array([]string{"KJFK"}), "refresh", pq.Array([]string{"name"}), true, false,
+			1, 0, []byte(`{"would_update":1}`), now, now, now,
+		))
+	mock.ExpectQuery(q).WithArgs(sqlmock.AnyArg()).WillReturnError(sql.ErrNoRows)
+
+	repo := NewSyncRunRepository(log)
+	run, err := repo.FindByID(context.Background(), tx, id.String())
+	require.NoError(t, err)
+	assert.Equal(t, "api", *run.Trigger)
+	assert.Nil(t, run.JobID)
+	assert.Equal(t, []string{"name"}, run.Fields)
+	assert.True(t, *run.DryRun)
+
+	_, err = repo.FindByID(context.Background(), tx, uuid.NewString())
+	assert.ErrorIs(t, err, util.ErrNotFound)
+
+	_, err = repo.FindByID(context.Background(), tx, "not-a-uuid")
+	assert.ErrorIs(t, err, util.ErrNotFound)
+}
+
+func TestSyncRunRepository_FindAll(t *testing.T) {
+	tx, mock := newTx(t)
+	now := time.Now()
+	outcomes := pq.Array([]string{"error", "not_found"})
+
+	mock.ExpectQuery(regexp.QuoteMeta(`WHERE (cardinality($3::text[]) = 0 OR counts ?| $3::text[])
+			AND ($4 = '' OR trigger = $4)
+			AND ($5::uuid IS NULL OR job_id = $5::uuid)
+		ORDER BY started_at DESC`)).
+		WithArgs(10, 20, outcomes, "", nil).
+		WillReturnRows(sqlmock.NewRows(newCols()).AddRow(
+			uuid.New(), "job", uuid.New(), pq.Array([]string{"KXXX"}), "insert", pq.Array([]string{}), false, false,
+			1, 1, []byte(`{"not_found":1}`), now, now, now,
+		))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT COUNT(*) FROM sync_runs
+		WHERE (cardinality($1::text[]) = 0 OR counts ?| $1::text[])`)).
+		WithArgs(outcomes, "", nil).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(21))
+
+	runs, total, err := NewSyncRunRepository(log).FindAll(context.Background(), tx, map[string]interface{}{
+		"limit":    10,
+		"offset":   20,
+		"outcomes": []string{"error", "not_found"},
+	})
+	require.NoError(t, err)
+	require.Len(t, runs, 1)
+	assert.Equal(t, []string{"KXXX"}, runs[0].ICAOCodes)
+	assert.Equal(t, 21, total)
+}
+
+func TestSyncRunRepository_FindAll_ByTriggerAndJob(t *testing.T) {
+	tx, mock := newTx(t)
+	now := time.Now()
+	jobID := uuid.New()
+
+	mock.ExpectQuery(`(?s)FROM sync_runs.*ORDER BY started_at DESC`).
+		WithArgs(10, 0, pq.Array([]string{}), "schedule", jobID).
+		WillReturnRows(sqlmock.NewRows(newCols()).AddRow(
+			uuid.New(), "schedule", jobID, pq.Array([]string{"KJFK"}), "refresh", pq.Array([]string{}), false, false,
+			1, 0, []byte(`{"updated":1}`), now, now, now,
+		))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT COUNT(*) FROM sync_runs`)).
+		WithArgs(pq.Array([]string{}), "schedule", jobID).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+	repo := NewSyncRunRepository(log)
+	runs, total, err := repo.FindAll(context.Background(), tx, map[string]interface{}{
+		"limit":   10,
+		"offset":  0,
+		"trigger": "schedule",
+		"job_id":  jobID.String(),
+	})
+	require.NoError(t, err)
+	require.Len(t, runs, 1)
+	assert.Equal(t, "schedule", *runs[0].Trigger)
+	assert.Equal(t, jobID, *runs[0].JobID)
+	assert.Equal(t, 1, total)
+
+	// A job ID that is not a UUID matches no run.
+	runs, total, err = repo.FindAll(context.Background(), tx, map[string]interface{}{"job_id": "not-a-uuid"})
+	require.NoError(t, err)
+	assert.Empty(t, runs)
+	assert.Zero(t, total)
+}
+
+func TestSyncRunRepository_FindItems(t *testing.T) {
+	tx, mock := newTx(t)
+	runID := uuid.New()
+
+	mock.ExpectQuery(regexp.QuoteMeta(`WHERE run_id = $1 AND (cardinality($2::text[]) = 0 OR outcome = ANY($2::text[]))
+		ORDER BY position`)).
+		WithArgs(runID, pq.Array([]string{"updated"})).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "run_id", "position", "icao_code", "outcome", "status", "message", "changes"}).
+			AddRow(uuid.New(), runID, 3, "KJFK", "updated", "Updated", "Airport data refreshed, 1 fields changed",
+				[]byte(`[{"field":"name","old":"A","new":"B"}]`)))
+
+	repo := NewSyncRunRepository(log)
+	items, err := repo.FindItems(context.Background(), tx, runID.String(), []string{"updated"})
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	assert.Equal(t, int64(3), *items[0].Position)
+	assert.Equal(t, "KJFK", *items[0].ICAOCode)
+	assert.JSONEq(t, `[{"field":"name","old":"A","new":"B"}]`, string(items[0].Changes))
+
+	_, err = repo.FindItems(context.Background(), tx, "not-a-uuid", nil)
+	assert.ErrorIs(t, err, util.ErrNotFound)
+}
@@ -0,0 +1,14 @@
+package repository_sync_schedule
+
+import (
+	"context"
+	"database/sql"
+	"flight-api/internal/model"
+)
+
+type ISyncScheduleRepository interface {
+	TryLock(ctx context.Context, tx *sql.Tx, name string) (bool, error)
+	FindByName(ctx context.Context, tx *sql.Tx, name string) (model.SyncSchedule, error)
+	FindAll(ctx context.Context, tx *sql.Tx) ([]model.SyncSchedule, error)
+	Save(ctx context.Context, tx *sql.Tx, schedule model.SyncSchedule) (model.SyncSchedule, error)
+}
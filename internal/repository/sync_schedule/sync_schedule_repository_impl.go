@@ -0,0 +1,150 @@
+package repository_sync_schedule
+
+import (
+	"context"
+	"database/sql"
+	"flight-api/internal/model"
+	"flight-api/pkg/logger"
+	"flight-api/util"
+	"strings"
+)
+
+const syncScheduleColumns = `s.name, s.expression, s.last_run_at, s.next_run_at, s.last_outcome,
+			s.last_message, s.last_job_id, j.status, s.created_at, s.updated_at`
+
+type SyncScheduleRepository struct {
+	logger *logger.Logger
+}
+
+func NewSyncScheduleRepository(l *logger.Logger) ISyncScheduleRepository {
+	return &SyncScheduleRepository{
+		logger: l,
+	}
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanSyncSchedule(row rowScanner) (model.SyncSchedule, error) {
+	schedule := model.SyncSchedule{}
+	err := row.Scan(
+		&schedule.Name,
+		&schedule.Expression,
+		&schedule.LastRunAt,
+		&schedule.NextRunAt,
+		&schedule.LastOutcome,
+		&schedule.LastMessage,
+		&schedule.LastJobID,
+		&schedule.LastJobStatus,
+		&schedule.CreatedAt,
+		&schedule.UpdatedAt,
+	)
+	return schedule, err
+}
+
+// TryLock takes the advisory lock of the named schedule for the rest of tx
+// and reports whether it got it. Another replica holding the lock is running
+// the schedule right now.
+func (r *SyncScheduleRepository) TryLock(ctx context.Context, tx *sql.Tx, name string) (bool, error) {
+	SQL := `SELECT pg_try_advisory_xact_lock(hashtext('sync_schedule:' || $1))`
+
+	var locked bool
+	if err := tx.QueryRowContext(ctx, SQL, name).Scan(&locked); err != nil {
+		r.logger.Errorf("Failed to lock sync schedule %s: %v", name, err)
+		return false, err
+	}
+
+	return locked, nil
+}
+
+func (r *SyncScheduleRepository) FindByName(ctx context.Context, tx *sql.Tx, name string) (model.SyncSchedule, error) {
+	SQL := `SELECT ` + syncScheduleColumns + `
+		FROM sync_schedules s
+		LEFT JOIN sync_jobs j ON j.id = s.last_job_id
+		WHERE s.name = $1`
+
+	schedule, err := scanSyncSchedule(tx.QueryRowContext(ctx, strings.TrimSpace(SQL), name))
+	if err == sql.ErrNoRows {
+		return model.SyncSchedule{}, util.ErrNotFound
+	} else if err != nil {
+		r.logger.Errorf("Failed to find sync schedule %s: %v", name, err)
+		return model.SyncSchedule{}, err
+	}
+
+	return schedule, nil
+}
+
+func (r *SyncScheduleRepository) FindAll(ctx context.Context, tx *sql.Tx) ([]model.SyncSchedule, error) {
+	SQL := `SELECT ` + syncScheduleColumns + `
+		FROM sync_schedules s
+		LEFT JOIN sync_jobs j ON j.id = s.last_job_id
+		ORDER BY s.name`
+
+	rows, err := tx.QueryContext(ctx, strings.TrimSpace(SQL))
+	if err != nil {
+		r.logger.Errorf("Failed to find sync schedules: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	schedules := []model.SyncSchedule{}
+	for rows.Next() {
+		schedule, err := scanSyncSchedule(rows)
+		if err != nil {
+			r.logger.Errorf("Failed to scan sync schedule: %v", err)
+			return nil, err
+		}
+		schedules = append(schedules, schedule)
+	}
+	if err := rows.Err(); err != nil {
+		r.logger.Errorf("Failed to read sync schedules: %v", err)
+		return nil, err
+	}
+
+	return schedules, nil
+}
+
+// Save records a run of the schedule, creating its row on the first run.
+func (r *SyncScheduleRepository) Save(ctx context.Context, tx *sql.Tx, schedule model.SyncSchedule) (model.SyncSchedule, error) {
+	SQL := `
+		INSERT INTO sync_schedules (name, expression, last_run_at, next_run_at, last_outcome, last_message, last_job_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (name) DO UPDATE SET
+			expression = EXCLUDED.expression,
+			last_run_at = EXCLUDED.last_run_at,
+			next_run_at = EXCLUDED.next_run_at,
+			last_outcome = EXCLUDED.last_outcome,
+			last_message = EXCLUDED.last_message,
+			last_job_id = EXCLUDED.last_job_id
+		RETURNING name, expression, last_run_at, next_run_at, last_outcome, last_message, last_job_id, created_at, updated_at`
+
+	saved := model.SyncSchedule{}
+	err := tx.QueryRowContext(
+		ctx,
+		strings.TrimSpace(SQL),
+		schedule.Name,
+		schedule.Expression,
+		schedule.LastRunAt,
+		schedule.NextRunAt,
+		schedule.LastOutcome,
+		schedule.LastMessage,
+		schedule.LastJobID,
+	).Scan(
+		&saved.Name,
+		&saved.Expression,
+		&saved.LastRunAt,
+		&saved.NextRunAt,
+		&saved.LastOutcome,
+		&saved.LastMessage,
+		&saved.LastJobID,
+		&saved.CreatedAt,
+		&saved.UpdatedAt,
+	)
+	if err != nil {
+		r.logger.Errorf("Failed to save sync schedule %s: %v", util.DerefPtr(schedule.Name), err)
+		return model.SyncSchedule{}, err
+	}
+
+	return saved, nil
+}
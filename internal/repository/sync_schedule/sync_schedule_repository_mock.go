@@ -0,0 +1,47 @@
+package repository_sync_schedule
+
+import (
+	"context"
+	"database/sql"
+	"flight-api/internal/model"
+	"flight-api/util"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type SyncScheduleRepositoryMock struct {
+	Mock mock.Mock
+}
+
+func (r *SyncScheduleRepositoryMock) TryLock(ctx context.Context, tx *sql.Tx, name string) (bool, error) {
+	args := r.Mock.Called(ctx, util.MockTx{Tx: tx}, name)
+	locked, _ := args.Get(0).(bool)
+	return locked, args.Error(1)
+}
+
+func (r *SyncScheduleRepositoryMock) FindByName(ctx context.Context, tx *sql.Tx, name string) (model.SyncSchedule, error) {
+	args := r.Mock.Called(ctx, util.MockTx{Tx: tx}, name)
+	var out model.SyncSchedule
+	if v, ok := args.Get(0).(model.SyncSchedule); ok {
+		out = v
+	}
+	return out, args.Error(1)
+}
+
+func (r *SyncScheduleRepositoryMock) FindAll(ctx context.Context, tx *sql.Tx) ([]model.SyncSchedule, error) {
+	args := r.Mock.Called(ctx, util.MockTx{Tx: tx})
+	var out []model.SyncSchedule
+	if v, ok := args.Get(0).([]model.SyncSchedule); ok {
+		out = v
+	}
+	return out, args.Error(1)
+}
+
+func (r *SyncScheduleRepositoryMock) Save(ctx context.Context, tx *sql.Tx, schedule model.SyncSchedule) (model.SyncSchedule, error) {
+	args := r.Mock.Called(ctx, util.MockTx{Tx: tx}, schedule)
+	var out model.SyncSchedule
+	if v, ok := args.Get(0).(model.SyncSchedule); ok {
+		out = v
+	}
+	return out, args.Error(1)
+}
@@ -0,0 +1,125 @@
+package repository_sync_schedule
+
+import (
+	"context"
+	"database/sql"
+	"flight-api/internal/model"
+	"flight-api/pkg/logger"
+	"flight-api/util"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var log = logger.NewLogger(logger.DEBUG_LEVEL)
+
+func newCols() []string {
+	return []string{
+		"name", "expression", "last_run_at", "next_run_at", "last_outcome",
+		"last_message", "last_job_id", "status", "created_at", "updated_at",
+	}
+}
+
+func newTx(t *testing.T) (*sql.Tx, sqlmock.Sqlmock) {
+	t.Helper()
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		assert.NoError(t, mock.ExpectationsWereMet())
+		_ = db.Close()
+	})
+
+	mock.ExpectBegin()
+	tx, err := db.Begin()
+	require.NoError(t, err)
+	return tx, mock
+}
+
+func TestSyncScheduleRepository_TryLock(t *testing.T) {
+	for _, locked := range []bool{true, false} {
+		tx, mock := newTx(t)
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT pg_try_advisory_xact_lock(hashtext('sync_schedule:' || $1))`)).
+			WithArgs("airac").
+			WillReturnRows(sqlmock.NewRows([]string{"pg_try_advisory_xact_lock"}).AddRow(locked))
+
+		out, err := NewSyncScheduleRepository(log).TryLock(context.Background(), tx, "airac")
+		require.NoError(t, err)
+		assert.Equal(t, locked, out)
+	}
+}
+
+func TestSyncScheduleRepository_FindByName(t *testing.T) {
+	now := time.Now()
+	jobID := uuid.New()
+	q := regexp.QuoteMeta(`LEFT JOIN sync_jobs j ON j.id = s.last_job_id
+		WHERE s.name = $1`)
+
+	t.Run("found", func(t *testing.T) {
+		tx, mock := newTx(t)
+		mock.ExpectQuery(q).WithArgs("airac").
+			WillReturnRows(sqlmock.NewRows(newCols()).
+				AddRow("airac", "@airac", now, now.Add(time.Hour), "queued", "Refreshing 3 airports", jobID, "running", now, now))
+
+		out, err := NewSyncScheduleRepository(log).FindByName(context.Background(), tx, "airac")
+		require.NoError(t, err)
+		assert.Equal(t, "queued", *out.LastOutcome)
+		assert.Equal(t, jobID, *out.LastJobID)
+		assert.Equal(t, "running", *out.LastJobStatus)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		tx, mock := newTx(t)
+		mock.ExpectQuery(q).WithArgs("nightly").WillReturnRows(sqlmock.NewRows(newCols()))
+
+		_, err := NewSyncScheduleRepository(log).FindByName(context.Background(), tx, "nightly")
+		assert.ErrorIs(t, err, util.ErrNotFound)
+	})
+}
+
+func TestSyncScheduleRepository_FindAll(t *testing.T) {
+	tx, mock := newTx(t)
+	now := time.Now()
+
+	mock.ExpectQuery(regexp.QuoteMeta(`ORDER BY s.name`)).
+		WillReturnRows(sqlmock.NewRows(newCols()).
+			AddRow("airac", "@airac", now, now, "queued", "Refreshing 3 airports", uuid.New(), "completed", now, now).
+			AddRow("nightly", "0 3 * * *", now, now, "skipped", "No airports to refresh", nil, nil, now, now))
+
+	out, err := NewSyncScheduleRepository(log).FindAll(context.Background(), tx)
+	require.NoError(t, err)
+	require.Len(t, out, 2)
+	assert.Equal(t, "completed", *out[0].LastJobStatus)
+	assert.Nil(t, out[1].LastJobID)
+	assert.Nil(t, out[1].LastJobStatus)
+}
+
+func TestSyncScheduleRepository_Save(t *testing.T) {
+	tx, mock := newTx(t)
+	now := time.Now()
+	jobID := uuid.New()
+
+	schedule := model.SyncSchedule{
+		Name:        util.Ptr("airac"),
+		Expression:  util.Ptr("@airac"),
+		LastRunAt:   &now,
+		NextRunAt:   util.Ptr(now.Add(time.Hour)),
+		LastOutcome: util.Ptr("queued"),
+		LastMessage: util.Ptr("Refreshing 3 airports"),
+		LastJobID:   &jobID,
+	}
+	mock.ExpectQuery(`(?s)INSERT\s+INTO\s+sync_schedules.*ON CONFLICT \(name\) DO UPDATE`).
+		WithArgs(schedule.Name, schedule.Expression, schedule.LastRunAt, schedule.NextRunAt, schedule.LastOutcome, schedule.LastMessage, schedule.LastJobID).
+		WillReturnRows(sqlmock.NewRows([]string{
+			"name", "expression", "last_run_at", "next_run_at", "last_outcome", "last_message", "last_job_id", "created_at", "updated_at",
+		}).AddRow("airac", "@airac", now, now.Add(time.Hour), "queued", "Refreshing 3 airports", jobID, now, now))
+
+	out, err := NewSyncScheduleRepository(log).Save(context.Background(), tx, schedule)
+	require.NoError(t, err)
+	assert.Equal(t, "airac", *out.Name)
+	assert.Equal(t, jobID, *out.LastJobID)
+}
@@ -5,6 +5,7 @@ import (
 	airport_dto "flight-api/internal/dto/airport"
 	pagination_dto "flight-api/internal/dto/pagination"
 	queryparams "flight-api/internal/dto/query_params"
+	"time"
 )
 
 type IAirportService interface {
@@ -14,5 +15,9 @@ type IAirportService interface {
 	FindByID(ctx context.Context, id string) (airport_dto.AirportDto, error)
 	Update(ctx context.Context, id string, u airport_dto.AirportUpdateDto) (airport_dto.AirportDto, error)
 	Delete(ctx context.Context, id string) error
-	GetWeatherCondition(ctx context.Context, code string, name string, query queryparams.QueryParams) (*pagination_dto.PaginationDto, error)
+	GetWeatherCondition(ctx context.Context, code string, name string, query queryparams.QueryParams, categories []string) (*pagination_dto.PaginationDto, error)
+	GetAirportWeather(ctx context.Context, id string) (airport_dto.AirportWeatherDto, error)
+	GetAirportForecast(ctx context.Context, id string, days int, at *time.Time) (airport_dto.AirportForecastDto, error)
+	GetPerformanceConditions(ctx context.Context, id string) (airport_dto.AirportPerformanceDto, error)
+	GetWindComponents(ctx context.Context, id string, headings []int) (airport_dto.WindComponentsDto, error)
 }
@@ -0,0 +1,189 @@
+package service_airport
+
+import (
+	"context"
+	"errors"
+	"flight-api/config"
+	"flight-api/internal/cache"
+	airport_dto "flight-api/internal/dto/airport"
+	queryparams "flight-api/internal/dto/query_params"
+	"flight-api/internal/model"
+	repository_airport "flight-api/internal/repository/airport"
+	service_weather "flight-api/internal/service/weather"
+	"flight-api/pkg/logger"
+	"flight-api/util"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newCachedDeps(t *testing.T) (sqlmock.Sqlmock, *repository_airport.AirportRepositoryMock, *service_weather.WeatherServiceMock, IAirportService) {
+	t.Helper()
+
+	log := logger.NewLogger(logger.INFO_DEBUG_LEVEL)
+	db, dbmock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	cfg := &config.Config{ServiceName: "flight-api-test"}
+	airportCache := cache.NewAirportCache(log, cfg, cache.NewMemoryCache(log, cfg))
+
+	repoMock := &repository_airport.AirportRepositoryMock{}
+	wMock := &service_weather.WeatherServiceMock{}
+	svc := NewAirportService(log, cfg, util.NewValidator(), db, repoMock, wMock, airportCache, nil, nil)
+
+	return dbmock, repoMock, wMock, svc
+}
+
+func newCachedAirport(icao, name string) model.Airport {
+	id := uuid.New()
+	return model.Airport{
+		ID:     &id,
+		ICAOID: util.Ptr(icao),
+		Name:   util.Ptr(name),
+		City:   util.Ptr("New York"),
+
+		CreatedAt: &timeNow,
+		UpdatedAt: &timeNow,
+	}
+}
+
+var anyTx = mock.AnythingOfType("util.MockTx")
+
+func TestAirportService_FindByID_ServedFromCache(t *testing.T) {
+	dbmock, repoMock, _, svc := newCachedDeps(t)
+	airport := newCachedAirport("KJFK", "John F. Kennedy Intl")
+	id := airport.ID.String()
+
+	// Only the first read touches the database.
+	dbmock.ExpectBegin()
+	dbmock.ExpectCommit()
+	repoMock.Mock.On("FindByID", mock.Anything, anyTx, id).Return(airport, nil).Once()
+
+	first, err := svc.FindByID(context.Background(), id)
+	require.NoError(t, err)
+	second, err := svc.FindByID(context.Background(), id)
+	require.NoError(t, err)
+
+	require.Equal(t, first.ID, second.ID)
+	require.Equal(t, *first.Name, *second.Name)
+	require.True(t, first.UpdatedAt.Equal(second.UpdatedAt))
+	require.NoError(t, dbmock.ExpectationsWereMet())
+	repoMock.Mock.AssertExpectations(t)
+}
+
+func TestAirportService_GetWeatherConditionByCode_ServedFromCache(t *testing.T) {
+	dbmock, repoMock, wMock, svc := newCachedDeps(t)
+	airport := newCachedAirport("KJFK", "John F. Kennedy Intl")
+
+	dbmock.ExpectBegin()
+	dbmock.ExpectCommit()
+	repoMock.Mock.On("FindByICAOID", mock.Anything, anyTx, "KJFK").Return(airport, nil).Once()
+	wMock.Mock.On("GetWeatherCondition", mock.Anything, mock.Anything).Return(nil, util.ErrNotFound)
+
+	for i := 0; i < 3; i++ {
+		_, err := svc.GetWeatherCondition(context.Background(), "KJFK", "", queryparams.QueryParams{Limit: 10, Page: 1}, nil)
+		require.NoError(t, err)
+	}
+
+	// A lookup by ID is also warm after the ICAO read.
+	_, err := svc.FindByID(context.Background(), airport.ID.String())
+	require.NoError(t, err)
+
+	require.NoError(t, dbmock.ExpectationsWereMet())
+	repoMock.Mock.AssertExpectations(t)
+}
+
+func TestAirportService_Update_InvalidatesAfterCommit(t *testing.T) {
+	dbmock, repoMock, _, svc := newCachedDeps(t)
+	airport := newCachedAirport("KJFK", "John F. Kennedy Intl")
+	id := airport.ID.String()
+
+	renamed := airport
+	renamed.Name = util.Ptr("JFK Renamed")
+
+	// Warm the cache.
+	dbmock.ExpectBegin()
+	dbmock.ExpectCommit()
+	repoMock.Mock.On("FindByID", mock.Anything, anyTx, id).Return(airport, nil).Once()
+	_, err := svc.FindByID(context.Background(), id)
+	require.NoError(t, err)
+
+	// Update commits.
+	dbmock.ExpectBegin()
+	dbmock.ExpectCommit()
+	repoMock.Mock.On("FindByID", mock.Anything, anyTx, id).Return(airport, nil).Once()
+	repoMock.Mock.On("Update", mock.Anything, anyTx, id, mock.AnythingOfType("model.Airport")).Return(renamed, nil).Once()
+	_, err = svc.Update(context.Background(), id, airport_dto.AirportUpdateDto{Name: renamed.Name})
+	require.NoError(t, err)
+
+	// The next read goes back to the database and sees the new name.
+	dbmock.ExpectBegin()
+	dbmock.ExpectCommit()
+	repoMock.Mock.On("FindByID", mock.Anything, anyTx, id).Return(renamed, nil).Once()
+	got, err := svc.FindByID(context.Background(), id)
+	require.NoError(t, err)
+	require.Equal(t, "JFK Renamed", *got.Name)
+
+	require.NoError(t, dbmock.ExpectationsWereMet())
+	repoMock.Mock.AssertExpectations(t)
+}
+
+func TestAirportService_Update_CommitFailureKeepsCommittedData(t *testing.T) {
+	dbmock, repoMock, _, svc := newCachedDeps(t)
+	airport := newCachedAirport("KJFK", "John F. Kennedy Intl")
+	id := airport.ID.String()
+
+	renamed := airport
+	renamed.Name = util.Ptr("JFK Renamed")
+
+	dbmock.ExpectBegin()
+	dbmock.ExpectCommit()
+	repoMock.Mock.On("FindByID", mock.Anything, anyTx, id).Return(airport, nil).Once()
+	_, err := svc.FindByID(context.Background(), id)
+	require.NoError(t, err)
+
+	dbmock.ExpectBegin()
+	dbmock.ExpectCommit().WillReturnError(errors.New("serialization failure"))
+	repoMock.Mock.On("FindByID", mock.Anything, anyTx, id).Return(airport, nil).Once()
+	repoMock.Mock.On("Update", mock.Anything, anyTx, id, mock.AnythingOfType("model.Airport")).Return(renamed, nil).Once()
+	_, err = svc.Update(context.Background(), id, airport_dto.AirportUpdateDto{Name: renamed.Name})
+	require.ErrorIs(t, err, util.ErrInternalServer)
+
+	// Served from the cache, still showing the committed name.
+	got, err := svc.FindByID(context.Background(), id)
+	require.NoError(t, err)
+	require.Equal(t, "John F. Kennedy Intl", *got.Name)
+
+	require.NoError(t, dbmock.ExpectationsWereMet())
+}
+
+func TestAirportService_Delete_InvalidatesAfterCommit(t *testing.T) {
+	dbmock, repoMock, _, svc := newCachedDeps(t)
+	airport := newCachedAirport("KLAX", "Los Angeles Intl")
+	id := airport.ID.String()
+
+	dbmock.ExpectBegin()
+	dbmock.ExpectCommit()
+	repoMock.Mock.On("FindByID", mock.Anything, anyTx, id).Return(airport, nil).Once()
+	_, err := svc.FindByID(context.Background(), id)
+	require.NoError(t, err)
+
+	dbmock.ExpectBegin()
+	dbmock.ExpectCommit()
+	repoMock.Mock.On("FindByID", mock.Anything, anyTx, id).Return(airport, nil).Once()
+	repoMock.Mock.On("Delete", mock.Anything, anyTx, id).Return(nil).Once()
+	require.NoError(t, svc.Delete(context.Background(), id))
+
+	dbmock.ExpectBegin()
+	dbmock.ExpectRollback()
+	repoMock.Mock.On("FindByID", mock.Anything, anyTx, id).Return(model.Airport{}, util.ErrNotFound).Once()
+	_, err = svc.FindByID(context.Background(), id)
+	require.ErrorIs(t, err, util.ErrNotFound)
+
+	require.NoError(t, dbmock.ExpectationsWereMet())
+	repoMock.Mock.AssertExpectations(t)
+}
@@ -3,40 +3,83 @@ package service_airport
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"flight-api/config"
+	"flight-api/internal/cache"
 	airport_dto "flight-api/internal/dto/airport"
+	location_dto "flight-api/internal/dto/location"
 	pagination_dto "flight-api/internal/dto/pagination"
 	queryparams "flight-api/internal/dto/query_params"
 	weather_dto "flight-api/internal/dto/weather"
+	"flight-api/internal/model"
 	repository_airport "flight-api/internal/repository/airport"
+	repository_runway "flight-api/internal/repository/runway"
+	service_metar "flight-api/internal/service/metar"
 	service_weather "flight-api/internal/service/weather"
 
 	"flight-api/pkg/logger"
+	"flight-api/pkg/metar"
 	"flight-api/util"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/go-playground/validator"
 )
 
+const (
+	defaultWeatherFanoutWorkers = 8
+	defaultWeatherFanoutTimeout = 10 * time.Second
+
+	// categoryScanBatch is how many airports a category search reads from
+	// the database at a time.
+	categoryScanBatch = 100
+
+	// defaultCategoryScanLimit is how many airports a category search reads
+	// at most before returning what it found.
+	defaultCategoryScanLimit = 500
+)
+
+const kphPerKnot = 1.852
+
 type AirportService struct {
 	logger            *logger.Logger
+	cfg               *config.Config
 	validate          *validator.Validate
 	db                *sql.DB
 	airportRepository repository_airport.IAirportRepository
 	weatherService    service_weather.IWeatherService
+	airportCache      *cache.AirportCache
+	metarService      service_metar.IMetarService
+	runwayRepository  repository_runway.IRunwayRepository
+	now               func() time.Time
 }
 
 func NewAirportService(
 	logger *logger.Logger,
+	cfg *config.Config,
 	validate *validator.Validate,
 	db *sql.DB,
 	airportRepository repository_airport.IAirportRepository,
 	weatherService service_weather.IWeatherService,
+	airportCache *cache.AirportCache,
+	metarService service_metar.IMetarService,
+	runwayRepository repository_runway.IRunwayRepository,
 ) IAirportService {
 	return &AirportService{
 		logger:            logger,
+		cfg:               cfg,
 		validate:          validate,
 		db:                db,
 		airportRepository: airportRepository,
 		weatherService:    weatherService,
+		airportCache:      airportCache,
+		metarService:      metarService,
+		runwayRepository:  runwayRepository,
+		now:               time.Now,
 	}
 }
 
@@ -46,52 +89,52 @@ func (s *AirportService) Seeding(ctx context.Context, reqs []string) ([]airport_
 	return nil, nil
 }
 
-func (s *AirportService) Create(ctx context.Context, r airport_dto.AirportRequestDto) (airport_dto.AirportDto, error) {
+func (s *AirportService) Create(ctx context.Context, r airport_dto.AirportRequestDto) (_ airport_dto.AirportDto, err error) {
 	s.logger.Debug("[Create] Creating new airport...")
 
-	err := s.validate.Struct(r)
+	err = s.validate.Struct(r)
 	if err != nil {
-		return airport_dto.AirportDto{}, nil
+		return airport_dto.AirportDto{}, err
 	}
 
 	tx, err := s.db.Begin()
 	if err != nil {
 		s.logger.Errorf("[Create] Failed to begin transaction: %v", err)
-		return airport_dto.AirportDto{}, util.ErrInternalServer
+		return airport_dto.AirportDto{}, util.NewAppError(util.ErrInternalServer, "Failed to begin transaction", err)
 	}
-	defer util.CommitOrRollback(tx)
+	defer util.CommitOrRollbackErr(tx, &err)
 
 	isExists, err := s.airportRepository.FindExistsByICAOID(ctx, tx, *r.ICAOID)
 	if err != nil {
 		s.logger.Errorf("[Create] Failed to check existing airport: %v", err)
-		return airport_dto.AirportDto{}, util.ErrInternalServer
+		return airport_dto.AirportDto{}, util.NewAppError(util.ErrInternalServer, "Failed to check existing airport", err)
 	}
 
 	if isExists {
 		s.logger.Warnf("[Create] Airport with ICAO ID %s already exists", *r.ICAOID)
-		return airport_dto.AirportDto{}, util.ErrConflict
+		return airport_dto.AirportDto{}, util.NewAppError(util.ErrConflict, fmt.Sprintf("Airport with ICAO ID %s already exists", *r.ICAOID), nil)
 	}
 
 	airport := airport_dto.AirportRequestToAirport(r)
 	airport, err = s.airportRepository.Insert(ctx, tx, airport)
 	if err != nil {
 		s.logger.Errorf("[Create] Failed to insert airport: %v", err)
-		return airport_dto.AirportDto{}, err
+		return airport_dto.AirportDto{}, util.NewAppError(util.ErrInternalServer, "Failed to insert airport", err)
 	}
 
 	data := airport_dto.ToAirportDto(airport)
 	return data, nil
 }
 
-func (s *AirportService) FindAll(ctx context.Context, query queryparams.QueryParams) (pagination_dto.PaginationDto, error) {
+func (s *AirportService) FindAll(ctx context.Context, query queryparams.QueryParams) (_ pagination_dto.PaginationDto, err error) {
 	s.logger.Debug("[FindAll] Fetching all airports...")
 
 	tx, err := s.db.Begin()
 	if err != nil {
 		s.logger.Errorf("[FindAll] Failed to begin transaction: %v", err)
-		return pagination_dto.PaginationDto{}, util.ErrInternalServer
+		return pagination_dto.PaginationDto{}, util.NewAppError(util.ErrInternalServer, "Failed to begin transaction", err)
 	}
-	defer util.CommitOrRollback(tx)
+	defer util.CommitOrRollbackErr(tx, &err)
 
 	args := map[string]interface{}{
 		"limit":  query.Limit,
@@ -100,7 +143,7 @@ func (s *AirportService) FindAll(ctx context.Context, query queryparams.QueryPar
 	airports, total, err := s.airportRepository.FindAll(ctx, tx, args)
 	if err != nil {
 		s.logger.Errorf("[FindAll] Failed to fetch airports: %v", err)
-		return pagination_dto.PaginationDto{}, util.ErrInternalServer
+		return pagination_dto.PaginationDto{}, util.NewAppError(util.ErrInternalServer, "Failed to fetch airports", err)
 	}
 
 	airportRecords := airport_dto.ToAirportRecordDtos(airports)
@@ -124,79 +167,144 @@ func (s *AirportService) FindAll(ctx context.Context, query queryparams.QueryPar
 func (s *AirportService) FindByID(ctx context.Context, id string) (airport_dto.AirportDto, error) {
 	s.logger.Debug("[FindByID] Fetching airport by ID...")
 
-	tx, err := s.db.Begin()
-	util.PanicIfError(err)
-	defer util.CommitOrRollback(tx)
+	airport, err := s.findByID(ctx, id)
+	if err != nil {
+		return airport_dto.AirportDto{}, err
+	}
 
-	airport, err := s.airportRepository.FindByID(ctx, tx, id)
+	return airport_dto.ToAirportDto(airport), nil
+}
+
+// findByID reads an airport through the cache. On a miss the row is read in
+// its own transaction and cached once that transaction has committed.
+func (s *AirportService) findByID(ctx context.Context, id string) (airport model.Airport, err error) {
+	if cached, ok := s.airportCache.FindByID(ctx, id); ok {
+		return cached, nil
+	}
 
+	tx, err := s.db.Begin()
 	if err != nil {
-		return airport_dto.AirportDto{}, util.ErrNotFound
+		return model.Airport{}, util.NewAppError(util.ErrInternalServer, "Failed to begin transaction", err)
 	}
 
-	return airport_dto.ToAirportDto(airport), nil
+	// Registered before the commit so it only runs once the read is committed.
+	defer func() {
+		if err == nil {
+			s.airportCache.Store(ctx, airport)
+		}
+	}()
+	defer util.CommitOrRollbackErr(tx, &err)
+
+	airport, err = s.airportRepository.FindByID(ctx, tx, id)
+
+	if errors.Is(err, util.ErrNotFound) {
+		return model.Airport{}, util.NewAppError(util.ErrNotFound, fmt.Sprintf("Airport with ID %s not found", id), nil)
+	} else if err != nil {
+		return model.Airport{}, util.NewAppError(util.ErrInternalServer, "Failed to fetch airport", err)
+	}
+
+	return airport, nil
 }
 
-func (s *AirportService) Update(ctx context.Context, id string, u airport_dto.AirportUpdateDto) (airport_dto.AirportDto, error) {
+func (s *AirportService) Update(ctx context.Context, id string, u airport_dto.AirportUpdateDto) (_ airport_dto.AirportDto, err error) {
 	s.logger.Debug("[Update] Updating airport...")
 
-	err := s.validate.Struct(u)
+	err = s.validate.Struct(u)
 	if err != nil {
 		util.LogPanicError(err)
-		return airport_dto.AirportDto{}, util.ErrBadRequest
+		return airport_dto.AirportDto{}, err
 	}
 
 	tx, err := s.db.Begin()
-	util.PanicIfError(err)
-	defer util.CommitOrRollback(tx)
+	if err != nil {
+		return airport_dto.AirportDto{}, util.NewAppError(util.ErrInternalServer, "Failed to begin transaction", err)
+	}
+
+	var previous, updatedAirport model.Airport
+	// Invalidate after commit, covering the old ICAO code if it changed.
+	defer func() {
+		if err == nil {
+			s.airportCache.Invalidate(ctx, previous, updatedAirport)
+		}
+	}()
+	defer util.CommitOrRollbackErr(tx, &err)
 
 	airport, err := s.airportRepository.FindByID(ctx, tx, id)
 
-	if err == util.ErrNotFound {
-		return airport_dto.AirportDto{}, util.ErrNotFound
+	if errors.Is(err, util.ErrNotFound) {
+		return airport_dto.AirportDto{}, util.NewAppError(util.ErrNotFound, fmt.Sprintf("Airport with ID %s not found", id), nil)
 	} else if err != nil {
-		util.PanicIfError(err)
+		return airport_dto.AirportDto{}, util.NewAppError(util.ErrInternalServer, "Failed to fetch airport", err)
 	}
+	previous = airport
 
 	util.FillUpdatableFields(&airport, u)
-	updatedAirport, err := s.airportRepository.Update(ctx, tx, id, airport)
-	util.PanicIfError(err)
+	updatedAirport, err = s.airportRepository.Update(ctx, tx, id, airport)
+	if err != nil {
+		s.logger.Errorf("[Update] Failed to update airport: %v", err)
+		return airport_dto.AirportDto{}, util.NewAppError(util.ErrInternalServer, "Failed to update airport", err)
+	}
 
 	s.logger.Debugf("[Update] Airport updated: %+v", updatedAirport)
 	return airport_dto.ToAirportDto(updatedAirport), nil
 }
 
-func (s *AirportService) Delete(ctx context.Context, id string) error {
+func (s *AirportService) Delete(ctx context.Context, id string) (err error) {
 	s.logger.Debug("[Delete] Deleting airport...")
 
 	tx, err := s.db.Begin()
-	util.PanicIfError(err)
-	defer util.CommitOrRollback(tx)
+	if err != nil {
+		return util.NewAppError(util.ErrInternalServer, "Failed to begin transaction", err)
+	}
+
+	var airport model.Airport
+	defer func() {
+		if err == nil {
+			s.airportCache.Invalidate(ctx, airport)
+		}
+	}()
+	defer util.CommitOrRollbackErr(tx, &err)
 
-	_, err = s.airportRepository.FindByID(ctx, tx, id)
+	airport, err = s.airportRepository.FindByID(ctx, tx, id)
 
-	if err == util.ErrNotFound {
-		return util.ErrNotFound
+	if errors.Is(err, util.ErrNotFound) {
+		return util.NewAppError(util.ErrNotFound, fmt.Sprintf("Airport with ID %s not found", id), nil)
+	} else if err != nil {
+		return util.NewAppError(util.ErrInternalServer, "Failed to fetch airport", err)
 	}
 
 	err = s.airportRepository.Delete(ctx, tx, id)
-	util.PanicIfError(err)
+	if err != nil {
+		s.logger.Errorf("[Delete] Failed to delete airport: %v", err)
+		return util.NewAppError(util.ErrInternalServer, "Failed to delete airport", err)
+	}
 
 	return nil
 }
 
-func (s *AirportService) GetWeatherCondition(ctx context.Context, code string, name string, query queryparams.QueryParams) (*pagination_dto.PaginationDto, error) {
+// GetWeatherCondition returns the weather at the airport with ICAO code, or
+// at one page of airports whose name matches name. When categories is not
+// empty only records in one of those flight categories are returned. The
+// airports matching name are then scanned up to WEATHER_CATEGORY_SCAN_LIMIT
+// and within one WEATHER_FANOUT_TIMEOUT, pages are cut from the records left,
+// and the total counts those records; Meta.Truncated marks a scan that
+// stopped early.
+func (s *AirportService) GetWeatherCondition(ctx context.Context, code string, name string, query queryparams.QueryParams, categories []string) (*pagination_dto.PaginationDto, error) {
 	s.logger.Debugf("[GetWeatherCondition] Fetching weather data from Weather APIs...")
 
+	categories, err := normalizeCategories(categories)
+	if err != nil {
+		return nil, err
+	}
+
 	var response *pagination_dto.PaginationDto
-	var err error
 
 	if code != "" {
-		response, err = s.getWeatherConditionByCode(ctx, code)
+		response, err = s.getWeatherConditionByCode(ctx, code, categories)
 	} else if name != "" {
-		response, err = s.getWeatherConditionBySearchName(ctx, name, query)
+		response, err = s.getWeatherConditionBySearchName(ctx, name, query, categories)
 	} else {
-		return nil, util.ErrBadRequest
+		return nil, util.NewAppError(util.ErrBadRequest, "Either 'code' or 'name' query parameter is required", nil)
 	}
 
 	if err != nil {
@@ -206,33 +314,271 @@ func (s *AirportService) GetWeatherCondition(ctx context.Context, code string, n
 	return response, nil
 }
 
-func (s *AirportService) getWeatherConditionByCode(ctx context.Context, code string) (*pagination_dto.PaginationDto, error) {
-	s.logger.Debugf("[getWeatherConditionByCode] Fetching weather data from Weather APIs...")
+// GetAirportWeather returns the current weather at one airport. Unlike the
+// list endpoints, a failed lookup fails the request with the upstream error.
+func (s *AirportService) GetAirportWeather(ctx context.Context, id string) (airport_dto.AirportWeatherDto, error) {
+	s.logger.Debugf("[GetAirportWeather] Fetching weather for airport %s...", id)
+
+	airport, err := s.findByID(ctx, id)
+	if err != nil {
+		return airport_dto.AirportWeatherDto{}, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.weatherTimeout())
+	defer cancel()
+
+	record := newAirportWeatherRecord(airport)
+	if err := s.fetchAirportWeather(ctx, &record, airport); err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return airport_dto.AirportWeatherDto{}, util.NewAppError(util.ErrGatewayTimeout, "Timed out waiting for weather data", err)
+		}
+		return airport_dto.AirportWeatherDto{}, err
+	}
+
+	return record, nil
+}
+
+// GetAirportForecast returns the forecast at one airport for days days. When
+// at is set the forecast is extended to reach it and the hourly period
+// covering it is reported separately, so dispatch gets the conditions
+// expected on arrival. A zero days means the default length.
+func (s *AirportService) GetAirportForecast(ctx context.Context, id string, days int, at *time.Time) (airport_dto.AirportForecastDto, error) {
+	s.logger.Debugf("[GetAirportForecast] Fetching forecast for airport %s...", id)
+
+	days, err := forecastDaysFor(s.now(), days, at)
+	if err != nil {
+		return airport_dto.AirportForecastDto{}, err
+	}
+
+	airport, err := s.findByID(ctx, id)
+	if err != nil {
+		return airport_dto.AirportForecastDto{}, err
+	}
+
+	lookups := weatherLookups(airport)
+	if len(lookups) == 0 {
+		return airport_dto.AirportForecastDto{}, noWeatherLookupError()
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.weatherTimeout())
+	defer cancel()
+
+	forecast, by, err := lookupWeather(lookups, func(query string) (*weather_dto.ForecastDto, error) {
+		return s.weatherService.GetWeatherForecast(ctx, util.Ptr(query), days)
+	})
+	if errors.Is(err, context.DeadlineExceeded) {
+		return airport_dto.AirportForecastDto{}, util.NewAppError(util.ErrGatewayTimeout, "Timed out waiting for forecast data", err)
+	}
+	if err != nil {
+		return airport_dto.AirportForecastDto{}, err
+	}
+	if forecast == nil || forecast.Forecast == nil {
+		return airport_dto.AirportForecastDto{}, util.NewAppError(util.ErrNotFound, "No forecast reported for the airport", nil)
+	}
+
+	record := airport_dto.AirportForecastDto{
+		Object:      "airport_forecast",
+		Code:        airport.ICAOID,
+		Airport:     util.Ptr(airport_dto.ToAirportDto(airport)),
+		LookupBy:    by,
+		Source:      forecast.Source,
+		FetchedAt:   forecast.FetchedAt,
+		Observation: observationPoint(airport, forecast.Location),
+		Forecast:    forecast.Forecast.ForecastDay,
+	}
+
+	if at != nil {
+		record.AtTime = forecast.HourAt(*at)
+		if record.AtTime == nil {
+			detail := fmt.Sprintf("The forecast does not cover %s", at.UTC().Format(time.RFC3339))
+			return airport_dto.AirportForecastDto{}, util.NewAppError(util.ErrBadRequest, detail, nil)
+		}
+	}
+
+	return record, nil
+}
+
+// forecastDaysFor returns the forecast length to request: days, or the
+// default when zero, extended so that the forecast reaches at.
+func forecastDaysFor(now time.Time, days int, at *time.Time) (int, error) {
+	if days == 0 {
+		days = service_weather.DefaultForecastDays
+	}
+	if at == nil {
+		return days, nil
+	}
+
+	if at.Before(now.Add(-time.Hour)) {
+		return 0, util.NewAppError(util.ErrBadRequest, "'at' must not be in the past", nil)
+	}
+
+	// Forecast days start at local midnight, which may be up to a day before
+	// now in UTC terms, hence the extra day.
+	needed := int(at.Sub(now)/(24*time.Hour)) + 2
+	if needed > days {
+		days = min(needed, service_weather.MaxForecastDays)
+	}
+	return days, nil
+}
+
+// GetPerformanceConditions returns the pressure altitude, density altitude
+// and standard-atmosphere deviation at one airport from its current weather.
+func (s *AirportService) GetPerformanceConditions(ctx context.Context, id string) (airport_dto.AirportPerformanceDto, error) {
+	s.logger.Debugf("[GetPerformanceConditions] Computing performance conditions for airport %s...", id)
+
+	airport, err := s.findByID(ctx, id)
+	if err != nil {
+		return airport_dto.AirportPerformanceDto{}, err
+	}
+	if airport.Elevation == nil {
+		detail := fmt.Sprintf("Airport %s has no elevation", util.DerefPtr(airport.ICAOID))
+		return airport_dto.AirportPerformanceDto{}, util.NewAppError(util.ErrNotFound, detail, nil)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.weatherTimeout())
+	defer cancel()
+
+	record := newAirportWeatherRecord(airport)
+	if err := s.fetchAirportWeather(ctx, &record, airport); err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return airport_dto.AirportPerformanceDto{}, util.NewAppError(util.ErrGatewayTimeout, "Timed out waiting for weather data", err)
+		}
+		return airport_dto.AirportPerformanceDto{}, err
+	}
+	if record.Performance == nil {
+		return airport_dto.AirportPerformanceDto{}, util.NewAppError(util.ErrNotFound, "No temperature or pressure reported for the airport", nil)
+	}
+
+	return airport_dto.AirportPerformanceDto{
+		Object:        "performance_conditions",
+		Code:          airport.ICAOID,
+		Airport:       record.Airport,
+		WeatherStatus: record.WeatherStatus,
+		Source:        record.Source,
+		FetchedAt:     record.FetchedAt,
+		Performance:   record.Performance,
+	}, nil
+}
+
+// GetWindComponents resolves the current wind at an airport along runways.
+// headings are magnetic runway headings in degrees; when none are given the
+// runways stored for the airport are used. Wind directions are true, so the
+// airport's magnetic variation, when known, converts between the two.
+func (s *AirportService) GetWindComponents(ctx context.Context, id string, headings []int) (airport_dto.WindComponentsDto, error) {
+	s.logger.Debugf("[GetWindComponents] Computing wind components for airport %s...", id)
+
+	for _, heading := range headings {
+		if heading < 1 || heading > 360 {
+			detail := fmt.Sprintf("'runway_heading' must be between 1 and 360, got %d", heading)
+			return airport_dto.WindComponentsDto{}, util.NewAppError(util.ErrBadRequest, detail, nil)
+		}
+	}
+
+	airport, err := s.findByID(ctx, id)
+	if err != nil {
+		return airport_dto.WindComponentsDto{}, err
+	}
+
+	variation, hasVariation := magneticVariation(airport)
+	runways := make([]runwayHeading, 0, len(headings))
+	runwaySource := airport_dto.RunwaySourceRequest
+	for _, heading := range headings {
+		runways = append(runways, runwayHeading{
+			ident:       runwayIdent(heading),
+			magneticDeg: float64(heading),
+			trueDeg:     util.MagneticToTrue(float64(heading), variation),
+		})
+	}
+
+	if len(runways) == 0 {
+		runwaySource = airport_dto.RunwaySourceStored
+		runways, err = s.storedRunways(ctx, airport, variation)
+		if err != nil {
+			return airport_dto.WindComponentsDto{}, err
+		}
+	}
+
+	weatherCtx, cancel := context.WithTimeout(ctx, s.weatherTimeout())
+	defer cancel()
+
+	record := newAirportWeatherRecord(airport)
+	if err := s.fetchAirportWeather(weatherCtx, &record, airport); err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return airport_dto.WindComponentsDto{}, util.NewAppError(util.ErrGatewayTimeout, "Timed out waiting for weather data", err)
+		}
+		return airport_dto.WindComponentsDto{}, err
+	}
+
+	wind, ok := currentWind(record.Weather, record.Metar)
+	if !ok {
+		return airport_dto.WindComponentsDto{}, util.NewAppError(util.ErrNotFound, "No wind reported for the airport", nil)
+	}
+
+	result := airport_dto.WindComponentsDto{
+		Object:        "wind_components",
+		Code:          airport.ICAOID,
+		Airport:       record.Airport,
+		Wind:          wind,
+		RunwaySource:  runwaySource,
+		Runways:       make([]airport_dto.RunwayWindDto, 0, len(runways)),
+		WeatherStatus: record.WeatherStatus,
+		Source:        record.Source,
+		FetchedAt:     record.FetchedAt,
+	}
+	if hasVariation {
+		result.MagneticVariation = util.Ptr(variation)
+		if wind.DirectionTrue != nil {
+			result.Wind.DirectionMagnetic = util.Ptr(util.TrueToMagnetic(*wind.DirectionTrue, variation))
+		}
+	}
+
+	for _, runway := range runways {
+		result.Runways = append(result.Runways, runwayWind(runway, wind))
+	}
+	result.Recommended = recommendRunway(result.Runways, wind)
+
+	return result, nil
+}
+
+// storedRunways loads the runway ends stored for airport. It fails with not
+// found when there are none, since the caller gave no heading either.
+func (s *AirportService) storedRunways(ctx context.Context, airport model.Airport, variation float64) (_ []runwayHeading, err error) {
+	if s.runwayRepository == nil || airport.ID == nil {
+		return nil, noRunwayDataError(airport)
+	}
 
 	tx, err := s.db.Begin()
-	util.PanicIfError(err)
-	defer util.CommitOrRollback(tx)
+	if err != nil {
+		return nil, util.NewAppError(util.ErrInternalServer, "Failed to begin transaction", err)
+	}
+	defer util.CommitOrRollbackErr(tx, &err)
 
-	// Find Airport By ICAO ID
-	airport, err := s.airportRepository.FindByICAOID(ctx, tx, code)
+	stored, err := s.runwayRepository.FindByAirportID(ctx, tx, airport.ID.String())
+	if err != nil {
+		return nil, util.NewAppError(util.ErrInternalServer, "Failed to fetch runways", err)
+	}
 
-	if err == util.ErrNotFound {
-		return nil, util.ErrNotFound
-	} else if err != nil {
-		return nil, util.ErrInternalServer
+	runways := make([]runwayHeading, 0, len(stored))
+	for _, runway := range stored {
+		if heading, ok := toRunwayHeading(runway, variation); ok {
+			runways = append(runways, heading)
+		}
+	}
+	if len(runways) == 0 {
+		return nil, noRunwayDataError(airport)
 	}
+	return runways, nil
+}
 
-	// Get Airport Weather Condition
-	weather, _ := s.weatherService.GetWeatherCondition(ctx, airport.City)
+func (s *AirportService) getWeatherConditionByCode(ctx context.Context, code string, categories []string) (*pagination_dto.PaginationDto, error) {
+	s.logger.Debugf("[getWeatherConditionByCode] Fetching weather data from Weather APIs...")
 
-	data := []airport_dto.AirportWeatherDto{}
-	airportWeather := airport_dto.AirportWeatherDto{
-		Object:  "airport_weather",
-		Code:    airport.ICAOID,
-		Airport: util.Ptr(airport_dto.ToAirportDto(airport)),
-		Weather: weather.Current,
+	airport, err := s.findByICAOID(ctx, code)
+	if err != nil {
+		return nil, err
 	}
-	data = append(data, airportWeather)
+
+	data := filterByCategory(s.fetchAirportWeathers(ctx, []model.Airport{airport}), categories)
 
 	response := pagination_dto.PaginationDto{
 		Object:  "pagination",
@@ -244,46 +590,49 @@ func (s *AirportService) getWeatherConditionByCode(ctx context.Context, code str
 	return &response, nil
 }
 
-func (s *AirportService) getWeatherConditionBySearchName(ctx context.Context, name string, query queryparams.QueryParams) (*pagination_dto.PaginationDto, error) {
-	s.logger.Debugf("[getWeatherConditionBySearchName] Fetching weather data from Weather APIs...")
+// findByICAOID reads an airport through the cache. On a miss the row is read
+// in its own transaction and cached once that transaction has committed.
+func (s *AirportService) findByICAOID(ctx context.Context, code string) (airport model.Airport, err error) {
+	if cached, ok := s.airportCache.FindByICAOID(ctx, code); ok {
+		return cached, nil
+	}
 
 	tx, err := s.db.Begin()
-	util.PanicIfError(err)
-	defer util.CommitOrRollback(tx)
-
-	args := map[string]interface{}{
-		"limit":  query.Limit,
-		"offset": query.Offset,
+	if err != nil {
+		return model.Airport{}, util.NewAppError(util.ErrInternalServer, "Failed to begin transaction", err)
 	}
+	defer func() {
+		if err == nil {
+			s.airportCache.Store(ctx, airport)
+		}
+	}()
+	defer util.CommitOrRollbackErr(tx, &err)
 
-	// Get Airport By Search Name
-	airports, total, err := s.airportRepository.FindBySearchName(ctx, tx, name, args)
-	if err != nil {
-		return nil, err
+	airport, err = s.airportRepository.FindByICAOID(ctx, tx, code)
+
+	if errors.Is(err, util.ErrNotFound) {
+		return model.Airport{}, util.NewAppError(util.ErrNotFound, fmt.Sprintf("Airport with ICAO code %s not found", code), nil)
+	} else if err != nil {
+		return model.Airport{}, util.NewAppError(util.ErrInternalServer, "Failed to fetch airport", err)
 	}
 
-	records := []airport_dto.AirportWeatherDto{}
-	for _, airport := range airports {
-		// Get Airport Weather Condition
-		weather, _ := s.weatherService.GetWeatherCondition(ctx, airport.City)
+	return airport, nil
+}
 
-		var current *weather_dto.CurrentWeatherDto
-		if weather == nil {
-			current = nil
-		} else {
-			current = weather.Current
-		}
+func (s *AirportService) getWeatherConditionBySearchName(ctx context.Context, name string, query queryparams.QueryParams, categories []string) (*pagination_dto.PaginationDto, error) {
+	s.logger.Debugf("[getWeatherConditionBySearchName] Fetching weather data from Weather APIs...")
 
-		res := airport_dto.AirportWeatherDto{
-			Object:  "airport_weather",
-			Code:    airport.ICAOID,
-			Airport: util.Ptr(airport_dto.ToAirportDto(airport)),
-			Weather: current,
-		}
+	if len(categories) > 0 {
+		return s.getWeatherConditionByCategory(ctx, name, query, categories)
+	}
 
-		records = append(records, res)
+	airports, total, err := s.searchAirports(ctx, name, query)
+	if err != nil {
+		return nil, err
 	}
 
+	records := s.fetchAirportWeathers(ctx, airports)
+
 	result := pagination_dto.PaginationDto{
 		Object:  "pagination",
 		Records: util.ToInterfaces(records),
@@ -297,3 +646,530 @@ func (s *AirportService) getWeatherConditionBySearchName(ctx context.Context, na
 
 	return &result, nil
 }
+
+// getWeatherConditionByCategory filters on the flight category, which only
+// the weather knows, so it reads the airports matching name in batches and
+// pages over the records left. The scan stops at the scan limit or when the
+// fan-out deadline passes, whichever comes first; Total then only counts the
+// records found so far and Meta.Truncated is set.
+func (s *AirportService) getWeatherConditionByCategory(ctx context.Context, name string, query queryparams.QueryParams, categories []string) (*pagination_dto.PaginationDto, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.weatherTimeout())
+	defer cancel()
+
+	limit := s.categoryScanLimit()
+
+	var matched []airport_dto.AirportWeatherDto
+	truncated := false
+
+	batch := queryparams.QueryParams{Limit: min(categoryScanBatch, limit)}
+	for {
+		airports, total, err := s.searchAirports(ctx, name, batch)
+		if err != nil {
+			return nil, err
+		}
+
+		matched = append(matched, filterByCategory(s.fetchAirportWeathers(ctx, airports), categories)...)
+
+		batch.Offset += len(airports)
+		if len(airports) == 0 || batch.Offset >= total {
+			truncated = ctx.Err() != nil
+			break
+		}
+		if batch.Offset >= limit || ctx.Err() != nil {
+			s.logger.Warnf("[getWeatherConditionByCategory] Stopped the category scan after %d of %d airports", batch.Offset, total)
+			truncated = true
+			break
+		}
+		batch.Limit = min(categoryScanBatch, limit-batch.Offset)
+	}
+
+	start := min(query.Offset, len(matched))
+	end := min(start+query.Limit, len(matched))
+
+	result := pagination_dto.PaginationDto{
+		Object:  "pagination",
+		Records: util.ToInterfaces(matched[start:end]),
+		Total:   len(matched),
+		Meta: &pagination_dto.PaginationMetaDto{
+			Limit:     query.Limit,
+			Page:      query.Page,
+			Next:      end < len(matched),
+			Truncated: truncated,
+		},
+	}
+
+	return &result, nil
+}
+
+// searchAirports reads one page of airports matching name. The transaction is
+// closed before returning so no connection is held during upstream calls.
+func (s *AirportService) searchAirports(ctx context.Context, name string, query queryparams.QueryParams) (_ []model.Airport, _ int, err error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, 0, util.NewAppError(util.ErrInternalServer, "Failed to begin transaction", err)
+	}
+	defer util.CommitOrRollbackErr(tx, &err)
+
+	args := map[string]interface{}{
+		"limit":  query.Limit,
+		"offset": query.Offset,
+	}
+
+	airports, total, err := s.airportRepository.FindBySearchName(ctx, tx, name, args)
+	if err != nil {
+		return nil, 0, util.NewAppError(util.ErrInternalServer, "Failed to search airports", err)
+	}
+
+	return airports, total, nil
+}
+
+// fetchAirportWeathers looks up the weather of every airport concurrently,
+// bounded by WEATHER_FANOUT_WORKERS and WEATHER_FANOUT_TIMEOUT. Failures do not
+// fail the whole response; each record carries its own weather status.
+func (s *AirportService) fetchAirportWeathers(ctx context.Context, airports []model.Airport) []airport_dto.AirportWeatherDto {
+	workers := s.cfg.WeatherFanoutWorkers
+	if workers <= 0 {
+		workers = defaultWeatherFanoutWorkers
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.weatherTimeout())
+	defer cancel()
+
+	records := make([]airport_dto.AirportWeatherDto, len(airports))
+	sem := make(chan struct{}, workers)
+	var wg sync.WaitGroup
+
+	for i, airport := range airports {
+		records[i] = newAirportWeatherRecord(airport)
+
+		wg.Add(1)
+		go func(record *airport_dto.AirportWeatherDto, airport model.Airport) {
+			defer wg.Done()
+
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				markWeatherFailure(record, ctx.Err())
+				return
+			}
+
+			if err := s.fetchAirportWeather(ctx, record, airport); err != nil {
+				s.logger.Warnf("[fetchAirportWeathers] Failed to fetch weather for %s: %v", util.DerefPtr(record.Code), err)
+			}
+		}(&records[i], airport)
+	}
+
+	wg.Wait()
+	return records
+}
+
+func (s *AirportService) categoryScanLimit() int {
+	if s.cfg.WeatherCategoryScan <= 0 {
+		return defaultCategoryScanLimit
+	}
+	return s.cfg.WeatherCategoryScan
+}
+
+func (s *AirportService) weatherTimeout() time.Duration {
+	if s.cfg.WeatherFanoutTimeout <= 0 {
+		return defaultWeatherFanoutTimeout
+	}
+	return s.cfg.WeatherFanoutTimeout
+}
+
+func newAirportWeatherRecord(airport model.Airport) airport_dto.AirportWeatherDto {
+	return airport_dto.AirportWeatherDto{
+		Object:  "airport_weather",
+		Code:    airport.ICAOID,
+		Airport: util.Ptr(airport_dto.ToAirportDto(airport)),
+	}
+}
+
+// fetchAirportWeather fills record with the weather at airport. A fresh
+// ingested METAR for the airport's ICAO code is preferred; otherwise the
+// lookups from weatherLookups are tried in order, moving on only when a
+// location is not found. The returned error is already reflected in the
+// record.
+func (s *AirportService) fetchAirportWeather(ctx context.Context, record *airport_dto.AirportWeatherDto, airport model.Airport) error {
+	if m := s.latestMetar(ctx, airport); m != nil {
+		record.WeatherStatus = airport_dto.WeatherStatusOK
+		record.Weather = weather_dto.ToCurrentWeatherDto(m)
+		record.Metar = m
+		record.LookupBy = airport_dto.WeatherLookupICAO
+		record.Source = util.Ptr(airport_dto.WeatherSourceMetar)
+		record.FetchedAt = util.Ptr(m.Time)
+		record.Category = airport_dto.ToFlightCategoryDto(m, nil)
+		record.Performance = s.performanceConditions(airport, record.Weather)
+		return nil
+	}
+
+	lookups := weatherLookups(airport)
+	if len(lookups) == 0 {
+		err := noWeatherLookupError()
+		record.WeatherStatus = airport_dto.WeatherStatusUnavailable
+		record.WeatherError = util.Ptr(err.Detail)
+		return err
+	}
+
+	weather, by, err := lookupWeather(lookups, func(query string) (*weather_dto.WeatherDto, error) {
+		return s.weatherService.GetWeatherCondition(ctx, util.Ptr(query))
+	})
+	record.LookupBy = by
+
+	switch {
+	case err != nil:
+		markWeatherFailure(record, err)
+		return err
+	case weather == nil || weather.Current == nil:
+		appErr := util.NewAppError(util.ErrNotFound, "No current weather reported for the airport", nil)
+		record.WeatherStatus = airport_dto.WeatherStatusUnavailable
+		record.WeatherError = util.Ptr(appErr.Detail)
+		return appErr
+	case weather.Stale:
+		record.WeatherStatus = airport_dto.WeatherStatusStale
+	default:
+		record.WeatherStatus = airport_dto.WeatherStatusOK
+	}
+
+	record.Weather = weather.Current
+	record.Source = weather.Source
+	record.FetchedAt = weather.FetchedAt
+	record.Observation = observationPoint(airport, weather.Location)
+	record.Category = airport_dto.ToFlightCategoryDto(nil, weather.Current)
+	record.Performance = s.performanceConditions(airport, weather.Current)
+	return nil
+}
+
+// performanceConditions computes pressure and density altitude at airport
+// from current, flagged with the configured thresholds.
+func (s *AirportService) performanceConditions(airport model.Airport, current *weather_dto.CurrentWeatherDto) *airport_dto.PerformanceConditionsDto {
+	return airport_dto.ToPerformanceConditionsDto(airport.Elevation, current, airport_dto.PerformanceThresholds{
+		HighDensityAltitudeFt: s.cfg.DensityAltWarnFt,
+		AboveFieldFt:          s.cfg.DensityAltExcessFt,
+	})
+}
+
+// latestMetar returns the airport's ingested METAR, or nil when METAR
+// ingestion is off or has nothing recent for it.
+func (s *AirportService) latestMetar(ctx context.Context, airport model.Airport) *metar.Metar {
+	icao := strings.TrimSpace(util.DerefPtr(airport.ICAOID))
+	if s.metarService == nil || icao == "" {
+		return nil
+	}
+
+	m, err := s.metarService.Latest(ctx, icao)
+	if err != nil {
+		s.logger.Debugf("[latestMetar] No METAR for %s, using consumer weather: %v", icao, err)
+		return nil
+	}
+	return m
+}
+
+type weatherLookup struct {
+	by    string
+	query string
+}
+
+// weatherLookups lists the queries that identify an airport's weather, best
+// first. Coordinates are preferred: a city name resolves to the wrong place
+// for rural fields and is often empty. The ICAO code and city are only used
+// when the airport has no usable position.
+func weatherLookups(airport model.Airport) []weatherLookup {
+	if lat, lon, ok := airportPosition(airport); ok {
+		return []weatherLookup{{airport_dto.WeatherLookupCoordinates, fmt.Sprintf("%.4f,%.4f", lat, lon)}}
+	}
+
+	var lookups []weatherLookup
+	if icao := strings.TrimSpace(util.DerefPtr(airport.ICAOID)); icao != "" {
+		lookups = append(lookups, weatherLookup{airport_dto.WeatherLookupICAO, icao})
+	}
+	if city := strings.TrimSpace(util.DerefPtr(airport.City)); city != "" {
+		lookups = append(lookups, weatherLookup{airport_dto.WeatherLookupCity, city})
+	}
+	return lookups
+}
+
+// lookupWeather calls fetch with each lookup in order, moving on only when a
+// location is not found. It returns how the answer was looked up.
+func lookupWeather[T any](lookups []weatherLookup, fetch func(query string) (T, error)) (data T, by string, err error) {
+	for _, lookup := range lookups {
+		by = lookup.by
+		data, err = fetch(lookup.query)
+		if !errors.Is(err, util.ErrNotFound) {
+			break
+		}
+	}
+	return data, by, err
+}
+
+func noWeatherLookupError() *util.AppError {
+	return util.NewAppError(util.ErrNotFound, "Airport has no coordinates, ICAO code or city to look weather up by", nil)
+}
+
+// airportPosition returns the airport's position in decimal degrees, from
+// either the degrees-minutes-seconds or the arc-seconds columns.
+func airportPosition(airport model.Airport) (lat, lon float64, ok bool) {
+	pairs := [][2]*string{
+		{airport.Latitude, airport.Longitude},
+		{airport.LatitudeSec, airport.LongitudeSec},
+	}
+	for _, pair := range pairs {
+		if pair[0] == nil || pair[1] == nil {
+			continue
+		}
+		lat, errLat := util.ParseCoordinate(*pair[0])
+		lon, errLon := util.ParseCoordinate(*pair[1])
+		if errLat == nil && errLon == nil && lat >= -90 && lat <= 90 {
+			return lat, lon, true
+		}
+	}
+	return 0, 0, false
+}
+
+// observationPoint reports where the weather was observed and its distance
+// from the airport.
+func observationPoint(airport model.Airport, location *location_dto.LocationDto) *airport_dto.ObservationPointDto {
+	if location == nil {
+		return nil
+	}
+
+	point := &airport_dto.ObservationPointDto{
+		Name: location.Name,
+		Lat:  location.Lat,
+		Lon:  location.Lon,
+	}
+	if lat, lon, ok := airportPosition(airport); ok && point.Lat != nil && point.Lon != nil {
+		distance := math.Round(util.DistanceKm(lat, lon, *point.Lat, *point.Lon)*100) / 100
+		point.DistanceKm = &distance
+	}
+	return point
+}
+
+// normalizeCategories upper-cases the requested flight categories and
+// rejects unknown ones.
+func normalizeCategories(categories []string) ([]string, error) {
+	normalized := make([]string, 0, len(categories))
+	for _, category := range categories {
+		category = strings.ToUpper(strings.TrimSpace(category))
+		if category == "" {
+			continue
+		}
+		if !metar.IsFlightCategory(category) {
+			detail := fmt.Sprintf("Unknown flight category %q; use VFR, MVFR, IFR or LIFR", category)
+			return nil, util.NewAppError(util.ErrBadRequest, detail, nil)
+		}
+		normalized = append(normalized, category)
+	}
+	return normalized, nil
+}
+
+// filterByCategory keeps the records in one of categories. Records whose
+// category is unknown are dropped. An empty categories keeps everything.
+func filterByCategory(records []airport_dto.AirportWeatherDto, categories []string) []airport_dto.AirportWeatherDto {
+	if len(categories) == 0 {
+		return records
+	}
+
+	filtered := make([]airport_dto.AirportWeatherDto, 0, len(records))
+	for _, record := range records {
+		if record.Category != nil && slices.Contains(categories, record.Category.Category) {
+			filtered = append(filtered, record)
+		}
+	}
+	return filtered
+}
+
+func markWeatherFailure(record *airport_dto.AirportWeatherDto, err error) {
+	record.WeatherStatus = airport_dto.WeatherStatusError
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, util.ErrGatewayTimeout) {
+		record.WeatherStatus = airport_dto.WeatherStatusTimeout
+	}
+	record.WeatherError = util.Ptr(util.ToAppError(err).Detail)
+}
+
+// runwayHeading is a runway end with its heading in degrees magnetic and
+// true.
+type runwayHeading struct {
+	ident       string
+	magneticDeg float64
+	trueDeg     float64
+}
+
+// magneticVariation parses the airport's magnetic variation, east positive.
+// An airport without one is treated as having none.
+func magneticVariation(airport model.Airport) (float64, bool) {
+	if airport.MagVariation == nil {
+		return 0, false
+	}
+	variation, err := util.ParseMagneticVariation(*airport.MagVariation)
+	if err != nil {
+		return 0, false
+	}
+	return variation, true
+}
+
+// toRunwayHeading takes the heading of a stored runway end from its
+// magnetic heading, its true heading, or failing both its designator.
+func toRunwayHeading(runway model.Runway, variation float64) (runwayHeading, bool) {
+	heading := runwayHeading{ident: strings.TrimSpace(util.DerefPtr(runway.Ident))}
+
+	switch {
+	case runway.MagneticHeading != nil:
+		heading.magneticDeg = float64(*runway.MagneticHeading)
+		heading.trueDeg = util.MagneticToTrue(heading.magneticDeg, variation)
+		if runway.TrueHeading != nil {
+			heading.trueDeg = float64(*runway.TrueHeading)
+		}
+	case runway.TrueHeading != nil:
+		heading.trueDeg = float64(*runway.TrueHeading)
+		heading.magneticDeg = util.TrueToMagnetic(heading.trueDeg, variation)
+	default:
+		magnetic, ok := util.RunwayDesignatorHeading(heading.ident)
+		if !ok {
+			return runwayHeading{}, false
+		}
+		heading.magneticDeg = float64(magnetic)
+		heading.trueDeg = util.MagneticToTrue(heading.magneticDeg, variation)
+	}
+
+	heading.magneticDeg = util.NormalizeHeading(heading.magneticDeg)
+	heading.trueDeg = util.NormalizeHeading(heading.trueDeg)
+	return heading, true
+}
+
+// runwayIdent names a requested heading after the runway it would be, so
+// 043 becomes "04" and 004 becomes "36".
+func runwayIdent(heading int) string {
+	number := int(math.Round(float64(heading) / 10))
+	if number == 0 {
+		number = 36
+	}
+	return fmt.Sprintf("%02d", number)
+}
+
+// currentWind reads the wind from weather, preferring the knots of a METAR
+// over the rounded kph of the converted weather.
+func currentWind(weather *weather_dto.CurrentWeatherDto, m *metar.Metar) (airport_dto.WindDto, bool) {
+	if m != nil && m.Wind != nil {
+		wind := airport_dto.WindDto{
+			SpeedKt:  float64(m.Wind.SpeedKt),
+			SpeedKph: round1(float64(m.Wind.SpeedKt) * kphPerKnot),
+			Calm:     m.Wind.Calm,
+			Variable: m.Wind.Variable,
+		}
+		if m.Wind.DirectionDeg != nil && !m.Wind.Calm {
+			wind.DirectionTrue = util.Ptr(util.NormalizeHeading(float64(*m.Wind.DirectionDeg)))
+		}
+		if m.Wind.GustKt != nil {
+			wind.GustKt = util.Ptr(float64(*m.Wind.GustKt))
+			wind.GustKph = util.Ptr(round1(float64(*m.Wind.GustKt) * kphPerKnot))
+		}
+		return wind, true
+	}
+
+	if weather == nil || weather.WindKph == nil {
+		return airport_dto.WindDto{}, false
+	}
+
+	wind := airport_dto.WindDto{
+		SpeedKt:  round1(*weather.WindKph / kphPerKnot),
+		SpeedKph: *weather.WindKph,
+		Calm:     *weather.WindKph == 0,
+	}
+	if weather.WindDegree != nil && !wind.Calm {
+		wind.DirectionTrue = util.Ptr(util.NormalizeHeading(float64(*weather.WindDegree)))
+	}
+	if weather.GustKph != nil {
+		wind.GustKt = util.Ptr(round1(*weather.GustKph / kphPerKnot))
+		wind.GustKph = weather.GustKph
+	}
+	wind.Variable = wind.DirectionTrue == nil && !wind.Calm
+	return wind, true
+}
+
+// runwayWind resolves wind along runway. Gust components are only given
+// when the gusts exceed the steady wind.
+func runwayWind(runway runwayHeading, wind airport_dto.WindDto) airport_dto.RunwayWindDto {
+	result := airport_dto.RunwayWindDto{
+		Runway:          runway.ident,
+		HeadingMagnetic: round1(runway.magneticDeg),
+		HeadingTrue:     round1(runway.trueDeg),
+	}
+
+	switch {
+	case wind.Calm:
+		result.Steady = windComponent(0, 0, runway.trueDeg)
+		return result
+	case wind.DirectionTrue == nil:
+		return result
+	}
+
+	result.Steady = windComponent(*wind.DirectionTrue, wind.SpeedKt, runway.trueDeg)
+	if wind.GustKt != nil && *wind.GustKt > wind.SpeedKt {
+		result.Gust = windComponent(*wind.DirectionTrue, *wind.GustKt, runway.trueDeg)
+	}
+	return result
+}
+
+func windComponent(windFrom, speedKt, runwayTrue float64) *airport_dto.WindComponentDto {
+	headwind, crosswind := util.WindComponents(windFrom, speedKt, runwayTrue)
+	headwind, crosswind = round1(headwind), round1(crosswind)
+
+	component := &airport_dto.WindComponentDto{
+		HeadwindKt:   headwind,
+		HeadwindKph:  round1(headwind * kphPerKnot),
+		CrosswindKt:  math.Abs(crosswind),
+		CrosswindKph: round1(math.Abs(crosswind) * kphPerKnot),
+		Tailwind:     headwind < 0,
+	}
+	switch {
+	case crosswind > 0:
+		component.CrosswindFrom = airport_dto.CrosswindFromRight
+	case crosswind < 0:
+		component.CrosswindFrom = airport_dto.CrosswindFromLeft
+	}
+	return component
+}
+
+// recommendRunway picks the runway with the most headwind, breaking ties on
+// the smaller crosswind, gusts included. Calm or directionless wind favours
+// no runway.
+func recommendRunway(runways []airport_dto.RunwayWindDto, wind airport_dto.WindDto) *string {
+	if wind.Calm || wind.DirectionTrue == nil {
+		return nil
+	}
+
+	var best *airport_dto.RunwayWindDto
+	for i := range runways {
+		runway := &runways[i]
+		if runway.Steady == nil {
+			continue
+		}
+		if best == nil || runway.Steady.HeadwindKt > best.Steady.HeadwindKt ||
+			(runway.Steady.HeadwindKt == best.Steady.HeadwindKt && maxCrosswind(*runway) < maxCrosswind(*best)) {
+			best = runway
+		}
+	}
+	if best == nil {
+		return nil
+	}
+	return util.Ptr(best.Runway)
+}
+
+func maxCrosswind(runway airport_dto.RunwayWindDto) float64 {
+	crosswind := runway.Steady.CrosswindKt
+	if runway.Gust != nil {
+		crosswind = max(crosswind, runway.Gust.CrosswindKt)
+	}
+	return crosswind
+}
+
+func noRunwayDataError(airport model.Airport) *util.AppError {
+	detail := fmt.Sprintf("No runway data stored for %s; pass 'runway_heading'", util.DerefPtr(airport.ICAOID))
+	return util.NewAppError(util.ErrNotFound, detail, nil)
+}
+
+func round1(v float64) float64 {
+	return math.Round(v*10) / 10
+}
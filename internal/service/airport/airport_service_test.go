@@ -3,6 +3,7 @@ package service_airport
 import (
 	"context"
 	"database/sql"
+	"flight-api/config"
 	airport_dto "flight-api/internal/dto/airport"
 	dto "flight-api/internal/dto/airport"
 	location_dto "flight-api/internal/dto/location"
@@ -192,7 +193,7 @@ func newDeps(t *testing.T) (*logger.Logger, *validator.Validate, *sql.DB, sqlmoc
 	repoMock := &repository_airport.AirportRepositoryMock{}
 	wMock := &service_weather.WeatherServiceMock{}
 
-	svc := NewAirportService(log, val, db, repoMock, wMock)
+	svc := NewAirportService(log, &config.Config{}, val, db, repoMock, wMock, nil, nil, nil)
 
 	return log, val, db, dbmock, repoMock, wMock, svc
 }
@@ -220,7 +221,7 @@ func TestAirportService_Create_Success(t *testing.T) {
 	repoMock := &repository_airport.AirportRepositoryMock{Mock: mock.Mock{}}
 	weatherMock := &service_weather.WeatherServiceMock{Mock: mock.Mock{}}
 
-	svc := NewAirportService(log, val, db, repoMock, weatherMock)
+	svc := NewAirportService(log, &config.Config{}, val, db, repoMock, weatherMock, nil, nil, nil)
 
 	// Expect tx dari service
 	dbmock.ExpectBegin()
@@ -270,12 +271,22 @@ func TestAirportService_Create_Success(t *testing.T) {
 		UpdatedAt:     &timeNow,
 	}
 
+	// Expect: ICAO ID belum ada di database
+	repoMock.Mock.
+		On("FindExistsByICAOID",
+			mock.Anything,
+			mock.AnythingOfType("util.MockTx"),
+			*req.ICAOID,
+		).
+		Return(false, nil).
+		Once()
+
 	// Expect: repo.Insert dipanggil dengan ctx apapun, tx valid, dan airport model yang terbentuk dari request
 	repoMock.Mock.
 		On(
 			"Insert",
 			mock.Anything, // ctx
-			mock.MatchedBy(func(tx *sql.Tx) bool { return tx != nil }),
+			mock.AnythingOfType("util.MockTx"),
 			mock.AnythingOfType("model.Airport"),
 		).
 		Return(expectedModel, nil).
@@ -310,7 +321,7 @@ func TestAirportService_FindAll_Success_WithNextTrue(t *testing.T) {
 	repoMock := &repository_airport.AirportRepositoryMock{Mock: mock.Mock{}}
 	weatherMock := &service_weather.WeatherServiceMock{Mock: mock.Mock{}}
 
-	svc := NewAirportService(log, val, db, repoMock, weatherMock)
+	svc := NewAirportService(log, &config.Config{}, val, db, repoMock, weatherMock, nil, nil, nil)
 
 	// Query params: Limit kecil, total besar → Next = true
 	q := queryparams.QueryParams{
@@ -333,7 +344,7 @@ func TestAirportService_FindAll_Success_WithNextTrue(t *testing.T) {
 	repoMock.Mock.
 		On("FindAll",
 			mock.Anything, // ctx
-			mock.MatchedBy(func(tx *sql.Tx) bool { return tx != nil }),
+			mock.AnythingOfType("util.MockTx"),
 			mock.MatchedBy(func(m map[string]interface{}) bool {
 				lim, lok := m["limit"].(int)
 				off, ook := m["offset"].(int)
@@ -385,7 +396,7 @@ func TestAirportService_FindAll_Success_NoNext(t *testing.T) {
 	repoMock := &repository_airport.AirportRepositoryMock{Mock: mock.Mock{}}
 	weatherMock := &service_weather.WeatherServiceMock{Mock: mock.Mock{}}
 
-	svc := NewAirportService(log, val, db, repoMock, weatherMock)
+	svc := NewAirportService(log, &config.Config{}, val, db, repoMock, weatherMock, nil, nil, nil)
 
 	// (offset + limit) == total ⇒ Next: false
 	q := queryparams.QueryParams{
@@ -405,7 +416,7 @@ func TestAirportService_FindAll_Success_NoNext(t *testing.T) {
 	repoMock.Mock.
 		On("FindAll",
 			mock.Anything,
-			mock.MatchedBy(func(tx *sql.Tx) bool { return tx != nil }),
+			mock.AnythingOfType("util.MockTx"),
 			mock.MatchedBy(func(m map[string]interface{}) bool {
 				return m["limit"] == q.Limit && m["offset"] == q.Offset
 			}),
@@ -450,7 +461,7 @@ func TestAirportService_FindAll_RepoError_Rollback(t *testing.T) {
 	repoMock := &repository_airport.AirportRepositoryMock{Mock: mock.Mock{}}
 	weatherMock := &service_weather.WeatherServiceMock{Mock: mock.Mock{}}
 
-	svc := NewAirportService(log, val, db, repoMock, weatherMock)
+	svc := NewAirportService(log, &config.Config{}, val, db, repoMock, weatherMock, nil, nil, nil)
 
 	q := queryparams.QueryParams{Limit: 10, Offset: 0, Page: 1}
 
@@ -460,15 +471,14 @@ func TestAirportService_FindAll_RepoError_Rollback(t *testing.T) {
 	repoMock.Mock.
 		On("FindAll",
 			mock.Anything,
-			mock.MatchedBy(func(tx *sql.Tx) bool { return tx != nil }),
+			mock.AnythingOfType("util.MockTx"),
 			mock.AnythingOfType("map[string]interface {}"),
 		).
 		Return(nil, 0, assertErr("db failure")).
 		Once()
 
-	require.Panics(t, func() {
-		_, err = svc.FindAll(context.Background(), q)
-	})
+	_, err = svc.FindAll(context.Background(), q)
+	require.ErrorIs(t, err, util.ErrInternalServer)
 
 	require.NoError(t, dbmock.ExpectationsWereMet())
 	repoMock.Mock.AssertExpectations(t)
@@ -484,7 +494,7 @@ func TestAirportService_FindByID_Success(t *testing.T) {
 
 	repoMock := &repository_airport.AirportRepositoryMock{Mock: mock.Mock{}}
 	weatherMock := &service_weather.WeatherServiceMock{Mock: mock.Mock{}}
-	svc := NewAirportService(log, val, db, repoMock, weatherMock)
+	svc := NewAirportService(log, &config.Config{}, val, db, repoMock, weatherMock, nil, nil, nil)
 
 	targetID := sliceId["KJFK"]
 	expectedModel := dataDummy[0].row
@@ -498,7 +508,7 @@ func TestAirportService_FindByID_Success(t *testing.T) {
 	repoMock.Mock.
 		On("FindByID",
 			mock.Anything, // ctx
-			mock.MatchedBy(func(tx *sql.Tx) bool { return tx != nil }),
+			mock.AnythingOfType("util.MockTx"),
 			targetID.String(),
 		).
 		Return(expectedModel, nil).
@@ -533,18 +543,18 @@ func TestAirportService_FindByID_NotFound(t *testing.T) {
 
 	repoMock := &repository_airport.AirportRepositoryMock{Mock: mock.Mock{}}
 	weatherMock := &service_weather.WeatherServiceMock{Mock: mock.Mock{}}
-	svc := NewAirportService(log, val, db, repoMock, weatherMock)
+	svc := NewAirportService(log, &config.Config{}, val, db, repoMock, weatherMock, nil, nil, nil)
 
 	unknownID := uuid.New().String()
 
-	// transaksi tetap commit karena tidak ada panic
+	// transaksi di-rollback karena service mengembalikan error
 	dbmock.ExpectBegin()
-	dbmock.ExpectCommit()
+	dbmock.ExpectRollback() // error → rollback
 
 	repoMock.Mock.
 		On("FindByID",
 			mock.Anything,
-			mock.MatchedBy(func(tx *sql.Tx) bool { return tx != nil }),
+			mock.AnythingOfType("util.MockTx"),
 			unknownID,
 		).
 		Return(model.Airport{}, util.ErrNotFound).
@@ -555,7 +565,7 @@ func TestAirportService_FindByID_NotFound(t *testing.T) {
 
 	// assert
 	require.Error(t, err)
-	require.Equal(t, util.ErrNotFound, err)
+	require.ErrorIs(t, err, util.ErrNotFound)
 	require.Equal(t, dto.AirportDto{}, got) // kosong sesuai code
 
 	require.NoError(t, dbmock.ExpectationsWereMet())
@@ -573,7 +583,7 @@ func TestAirportService_Update_Success(t *testing.T) {
 
 	repoMock := &repository_airport.AirportRepositoryMock{Mock: mock.Mock{}}
 	weatherMock := &service_weather.WeatherServiceMock{Mock: mock.Mock{}}
-	svc := NewAirportService(log, val, db, repoMock, weatherMock)
+	svc := NewAirportService(log, &config.Config{}, val, db, repoMock, weatherMock, nil, nil, nil)
 
 	// Arrange
 	id := sliceId["KJFK"]
@@ -596,7 +606,7 @@ func TestAirportService_Update_Success(t *testing.T) {
 	repoMock.Mock.
 		On("FindByID",
 			mock.Anything,
-			mock.MatchedBy(func(tx *sql.Tx) bool { return tx != nil }),
+			mock.AnythingOfType("util.MockTx"),
 			id.String(),
 		).
 		Return(existing, nil).
@@ -612,7 +622,7 @@ func TestAirportService_Update_Success(t *testing.T) {
 	repoMock.Mock.
 		On("Update",
 			mock.Anything,
-			mock.MatchedBy(func(tx *sql.Tx) bool { return tx != nil }),
+			mock.AnythingOfType("util.MockTx"),
 			id.String(),
 			mock.AnythingOfType("model.Airport"),
 		).
@@ -647,17 +657,17 @@ func TestAirportService_Update_FindByID_NotFound(t *testing.T) {
 
 	repoMock := &repository_airport.AirportRepositoryMock{Mock: mock.Mock{}}
 	weatherMock := &service_weather.WeatherServiceMock{Mock: mock.Mock{}}
-	svc := NewAirportService(log, val, db, repoMock, weatherMock)
+	svc := NewAirportService(log, &config.Config{}, val, db, repoMock, weatherMock, nil, nil, nil)
 
 	id := uuid.New().String()
 
 	dbmock.ExpectBegin()
-	dbmock.ExpectCommit() // tidak panic → commit
+	dbmock.ExpectRollback() // error → rollback
 
 	repoMock.Mock.
 		On("FindByID",
 			mock.Anything,
-			mock.MatchedBy(func(tx *sql.Tx) bool { return tx != nil }),
+			mock.AnythingOfType("util.MockTx"),
 			id,
 		).
 		Return(model.Airport{}, util.ErrNotFound).
@@ -666,7 +676,7 @@ func TestAirportService_Update_FindByID_NotFound(t *testing.T) {
 	got, err := svc.Update(context.Background(), id, airport_dto.AirportUpdateDto{City: util.Ptr("X")})
 
 	require.Error(t, err)
-	require.Equal(t, util.ErrNotFound, err)
+	require.ErrorIs(t, err, util.ErrNotFound)
 	require.Equal(t, dto.AirportDto{}, got)
 
 	require.NoError(t, dbmock.ExpectationsWereMet())
@@ -683,7 +693,7 @@ func TestAirportService_Update_UpdateRepoError(t *testing.T) {
 
 	repoMock := &repository_airport.AirportRepositoryMock{Mock: mock.Mock{}}
 	weatherMock := &service_weather.WeatherServiceMock{Mock: mock.Mock{}}
-	svc := NewAirportService(log, val, db, repoMock, weatherMock)
+	svc := NewAirportService(log, &config.Config{}, val, db, repoMock, weatherMock, nil, nil, nil)
 
 	id := sliceId["KLAX"]
 	existing := dataDummy[1].row // KLAX
@@ -693,32 +703,31 @@ func TestAirportService_Update_UpdateRepoError(t *testing.T) {
 	}
 
 	dbmock.ExpectBegin()
-	dbmock.ExpectRollback() // karena PanicIfError pada Update
+	dbmock.ExpectRollback() // karena Update mengembalikan error
 
 	// FindByID OK
 	repoMock.Mock.
 		On("FindByID",
 			mock.Anything,
-			mock.MatchedBy(func(tx *sql.Tx) bool { return tx != nil }),
+			mock.AnythingOfType("util.MockTx"),
 			id.String(),
 		).
 		Return(existing, nil).
 		Once()
 
-	// Update error -> panic
+	// Update error -> internal server error
 	repoMock.Mock.
 		On("Update",
 			mock.Anything,
-			mock.MatchedBy(func(tx *sql.Tx) bool { return tx != nil }),
+			mock.AnythingOfType("util.MockTx"),
 			id.String(),
 			mock.AnythingOfType("model.Airport"),
 		).
 		Return(model.Airport{}, assertErr("update failed")).
 		Once()
 
-	require.Panics(t, func() {
-		_, _ = svc.Update(context.Background(), id.String(), airport_dto)
-	})
+	_, err = svc.Update(context.Background(), id.String(), airport_dto)
+	require.ErrorIs(t, err, util.ErrInternalServer)
 
 	require.NoError(t, dbmock.ExpectationsWereMet())
 	repoMock.Mock.AssertExpectations(t)
@@ -734,7 +743,7 @@ func TestAirportService_Delete_Success(t *testing.T) {
 
 	repo := &repository_airport.AirportRepositoryMock{Mock: mock.Mock{}}
 	weather := &service_weather.WeatherServiceMock{Mock: mock.Mock{}}
-	svc := NewAirportService(log, val, db, repo, weather)
+	svc := NewAirportService(log, &config.Config{}, val, db, repo, weather, nil, nil, nil)
 
 	existingID := sliceId["KSFO"]
 	existing := dataDummy[2].row // KSFO
@@ -748,7 +757,7 @@ func TestAirportService_Delete_Success(t *testing.T) {
 	repo.Mock.
 		On("FindByID",
 			mock.Anything,
-			mock.MatchedBy(func(tx *sql.Tx) bool { return tx != nil }),
+			mock.AnythingOfType("util.MockTx"),
 			id,
 		).
 		Return(existing, nil).
@@ -758,7 +767,7 @@ func TestAirportService_Delete_Success(t *testing.T) {
 	repo.Mock.
 		On("Delete",
 			mock.Anything,
-			mock.MatchedBy(func(tx *sql.Tx) bool { return tx != nil }),
+			mock.AnythingOfType("util.MockTx"),
 			id,
 		).
 		Return(nil).
@@ -782,18 +791,18 @@ func TestAirportService_Delete_NotFound(t *testing.T) {
 
 	repo := &repository_airport.AirportRepositoryMock{Mock: mock.Mock{}}
 	weather := &service_weather.WeatherServiceMock{Mock: mock.Mock{}}
-	svc := NewAirportService(log, val, db, repo, weather)
+	svc := NewAirportService(log, &config.Config{}, val, db, repo, weather, nil, nil, nil)
 
 	id := uuid.New().String()
 
-	// Begin + Commit (tidak panic)
+	// Begin + Rollback (service mengembalikan error)
 	dbmock.ExpectBegin()
-	dbmock.ExpectCommit()
+	dbmock.ExpectRollback() // error → rollback
 
 	repo.Mock.
 		On("FindByID",
 			mock.Anything,
-			mock.MatchedBy(func(tx *sql.Tx) bool { return tx != nil }),
+			mock.AnythingOfType("util.MockTx"),
 			id,
 		).
 		Return(model.Airport{}, util.ErrNotFound).
@@ -805,7 +814,7 @@ func TestAirportService_Delete_NotFound(t *testing.T) {
 
 	// assert
 	require.Error(t, err)
-	require.Equal(t, util.ErrNotFound, err)
+	require.ErrorIs(t, err, util.ErrNotFound)
 	repo.Mock.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything, mock.Anything)
 	require.NoError(t, dbmock.ExpectationsWereMet())
 	repo.Mock.AssertExpectations(t)
@@ -821,17 +830,17 @@ func TestGetWeatherCondition_EmptyCodeAndName(t *testing.T) {
 
 	repo := &repository_airport.AirportRepositoryMock{Mock: mock.Mock{}}
 	weather := &service_weather.WeatherServiceMock{Mock: mock.Mock{}}
-	svc := NewAirportService(log, val, db, repo, weather)
+	svc := NewAirportService(log, &config.Config{}, val, db, repo, weather, nil, nil, nil)
 
 	queryParam := queryparams.QueryParams{
 		Limit:  10,
 		Offset: 0,
 		Page:   1,
 	}
-	out, err := svc.GetWeatherCondition(context.Background(), "", "", queryParam)
+	out, err := svc.GetWeatherCondition(context.Background(), "", "", queryParam, nil)
 	require.Error(t, err)
 	require.Nil(t, out)
-	require.Equal(t, util.ErrBadRequest, err)
+	require.ErrorIs(t, err, util.ErrBadRequest)
 
 	// Pastikan tidak ada panggilan repo/weather
 	repo.Mock.AssertNotCalled(t, "FindByICAOID", mock.Anything, mock.Anything, mock.Anything)
@@ -847,7 +856,7 @@ func TestGetWeatherCondition_ByCode_Success(t *testing.T) {
 
 	repo := &repository_airport.AirportRepositoryMock{Mock: mock.Mock{}}
 	weather := &service_weather.WeatherServiceMock{Mock: mock.Mock{}}
-	svc := NewAirportService(log, val, db, repo, weather)
+	svc := NewAirportService(log, &config.Config{}, val, db, repo, weather, nil, nil, nil)
 
 	code := "KJFK"
 	airport := dataDummy[0].row // KJFK
@@ -860,7 +869,7 @@ func TestGetWeatherCondition_ByCode_Success(t *testing.T) {
 	repo.Mock.
 		On("FindByICAOID",
 			mock.Anything, // ctx
-			mock.MatchedBy(func(tx *sql.Tx) bool { return tx != nil }),
+			mock.AnythingOfType("util.MockTx"),
 			code,
 		).
 		Return(airport, nil).
@@ -872,7 +881,7 @@ func TestGetWeatherCondition_ByCode_Success(t *testing.T) {
 	weather.Mock.
 		On("GetWeatherCondition",
 			mock.Anything,
-			airport.City, // *string
+			util.Ptr("37.3639,-121.9289"), // looked up by the airport's coordinates
 		).
 		Return(weatherResp, nil).
 		Once()
@@ -883,7 +892,7 @@ func TestGetWeatherCondition_ByCode_Success(t *testing.T) {
 		Offset: 0,
 		Page:   1,
 	}
-	out, err := svc.GetWeatherCondition(context.Background(), code, "", queryParam)
+	out, err := svc.GetWeatherCondition(context.Background(), code, "", queryParam, nil)
 	require.NoError(t, err)
 	require.NotNil(t, out)
 
@@ -912,18 +921,18 @@ func TestGetWeatherCondition_ByCode_NotFound(t *testing.T) {
 
 	repo := &repository_airport.AirportRepositoryMock{Mock: mock.Mock{}}
 	weather := &service_weather.WeatherServiceMock{Mock: mock.Mock{}}
-	svc := NewAirportService(log, val, db, repo, weather)
+	svc := NewAirportService(log, &config.Config{}, val, db, repo, weather, nil, nil, nil)
 
 	code := "XXXX"
 
-	// Begin + Commit (tidak ada panic)
+	// Begin + Rollback (service mengembalikan error)
 	dbmock.ExpectBegin()
-	dbmock.ExpectCommit()
+	dbmock.ExpectRollback() // error → rollback
 
 	repo.Mock.
 		On("FindByICAOID",
 			mock.Anything,
-			mock.MatchedBy(func(tx *sql.Tx) bool { return tx != nil }),
+			mock.AnythingOfType("util.MockTx"),
 			code,
 		).
 		Return(model.Airport{}, util.ErrNotFound).
@@ -934,10 +943,10 @@ func TestGetWeatherCondition_ByCode_NotFound(t *testing.T) {
 		Offset: 0,
 		Page:   1,
 	}
-	out, err := svc.GetWeatherCondition(context.Background(), code, "", queryParam)
+	out, err := svc.GetWeatherCondition(context.Background(), code, "", queryParam, nil)
 	require.Error(t, err)
 	require.Nil(t, out)
-	require.Equal(t, util.ErrNotFound, err)
+	require.ErrorIs(t, err, util.ErrNotFound)
 
 	require.NoError(t, dbmock.ExpectationsWereMet())
 	repo.Mock.AssertExpectations(t)
@@ -953,18 +962,18 @@ func TestGetWeatherCondition_ByCode_UnexpectedRepoError(t *testing.T) {
 
 	repo := &repository_airport.AirportRepositoryMock{Mock: mock.Mock{}}
 	weather := &service_weather.WeatherServiceMock{Mock: mock.Mock{}}
-	svc := NewAirportService(log, val, db, repo, weather)
+	svc := NewAirportService(log, &config.Config{}, val, db, repo, weather, nil, nil, nil)
 
 	code := "KERR"
 
-	// Begin + Commit (servicemu return error biasa, bukan panic)
+	// Begin + Rollback (service mengembalikan error)
 	dbmock.ExpectBegin()
-	dbmock.ExpectCommit()
+	dbmock.ExpectRollback() // error → rollback
 
 	repo.Mock.
 		On("FindByICAOID",
 			mock.Anything,
-			mock.MatchedBy(func(tx *sql.Tx) bool { return tx != nil }),
+			mock.AnythingOfType("util.MockTx"),
 			code,
 		).
 		Return(model.Airport{}, assertErr("db down")).
@@ -975,10 +984,10 @@ func TestGetWeatherCondition_ByCode_UnexpectedRepoError(t *testing.T) {
 		Offset: 0,
 		Page:   1,
 	}
-	out, err := svc.GetWeatherCondition(context.Background(), code, "", queryParam)
+	out, err := svc.GetWeatherCondition(context.Background(), code, "", queryParam, nil)
 	require.Error(t, err)
 	require.Nil(t, out)
-	require.Equal(t, util.ErrInternalServer, err)
+	require.ErrorIs(t, err, util.ErrInternalServer)
 
 	require.NoError(t, dbmock.ExpectationsWereMet())
 	repo.Mock.AssertExpectations(t)
@@ -994,7 +1003,7 @@ func TestGetWeatherCondition_BySearchName_Success(t *testing.T) {
 
 	repo := &repository_airport.AirportRepositoryMock{Mock: mock.Mock{}}
 	weather := &service_weather.WeatherServiceMock{Mock: mock.Mock{}}
-	svc := NewAirportService(log, val, db, repo, weather)
+	svc := NewAirportService(log, &config.Config{}, val, db, repo, weather, nil, nil, nil)
 
 	name := "International"
 	q := queryparams.QueryParams{Limit: 2, Offset: 0, Page: 1}
@@ -1013,7 +1022,7 @@ func TestGetWeatherCondition_BySearchName_Success(t *testing.T) {
 	repo.Mock.
 		On("FindBySearchName",
 			mock.Anything,
-			mock.MatchedBy(func(tx *sql.Tx) bool { return tx != nil }),
+			mock.AnythingOfType("util.MockTx"),
 			name,
 			mock.MatchedBy(func(m map[string]interface{}) bool {
 				lim, ok1 := m["limit"].(int)
@@ -1024,22 +1033,22 @@ func TestGetWeatherCondition_BySearchName_Success(t *testing.T) {
 		Return(list, total, nil).
 		Once()
 
-	// weather service dipanggil per-airport (city pointer)
+	// weather service dipanggil per-airport (coordinates)
 	weatherData := dataDummyWeather["new_york"]
 	var weather1 *weather_dto.WeatherDto = &weatherData
 	weather.Mock.
-		On("GetWeatherCondition", mock.Anything, a1.City).
+		On("GetWeatherCondition", mock.Anything, util.Ptr("37.3639,-121.9289")).
 		Return(weather1, nil).
 		Once()
 
 	var weather2 *weather_dto.WeatherDto = nil
 	weather.Mock.
-		On("GetWeatherCondition", mock.Anything, a2.City).
+		On("GetWeatherCondition", mock.Anything, util.Ptr("33.9416,-118.4085")).
 		Return(weather2, nil).
 		Once()
 
 	// act via public method yg rutenya ke getWeatherConditionBySearchName
-	out, err := svc.GetWeatherCondition(context.Background(), "", name, q)
+	out, err := svc.GetWeatherCondition(context.Background(), "", name, q, nil)
 
 	// assert
 	require.NoError(t, err)
@@ -1085,19 +1094,19 @@ func TestGetWeatherCondition_BySearchName_RepoError(t *testing.T) {
 
 	repo := &repository_airport.AirportRepositoryMock{Mock: mock.Mock{}}
 	weather := &service_weather.WeatherServiceMock{Mock: mock.Mock{}}
-	svc := NewAirportService(log, val, db, repo, weather)
+	svc := NewAirportService(log, &config.Config{}, val, db, repo, weather, nil, nil, nil)
 
 	name := "X"
 	q := queryparams.QueryParams{Limit: 5, Offset: 10, Page: 4}
 
-	// Begin + Commit (return error biasa, tidak panic)
+	// Begin + Rollback (service mengembalikan error)
 	dbmock.ExpectBegin()
-	dbmock.ExpectCommit()
+	dbmock.ExpectRollback() // error → rollback
 
 	repo.Mock.
 		On("FindBySearchName",
 			mock.Anything,
-			mock.MatchedBy(func(tx *sql.Tx) bool { return tx != nil }),
+			mock.AnythingOfType("util.MockTx"),
 			name,
 			mock.MatchedBy(func(m map[string]interface{}) bool {
 				return m["limit"] == q.Limit && m["offset"] == q.Offset
@@ -1106,7 +1115,7 @@ func TestGetWeatherCondition_BySearchName_RepoError(t *testing.T) {
 		Return(nil, 0, assertErr("db error")).
 		Once()
 
-	out, err := svc.GetWeatherCondition(context.Background(), "", name, q)
+	out, err := svc.GetWeatherCondition(context.Background(), "", name, q, nil)
 	require.Error(t, err)
 	require.Nil(t, out)
 
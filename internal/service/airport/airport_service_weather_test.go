@@ -0,0 +1,558 @@
+package service_airport
+
+import (
+	"context"
+	"flight-api/config"
+	airport_dto "flight-api/internal/dto/airport"
+	location_dto "flight-api/internal/dto/location"
+	pagination_dto "flight-api/internal/dto/pagination"
+	queryparams "flight-api/internal/dto/query_params"
+	weather_dto "flight-api/internal/dto/weather"
+	"flight-api/internal/model"
+	repository_airport "flight-api/internal/repository/airport"
+	service_metar "flight-api/internal/service/metar"
+	service_weather "flight-api/internal/service/weather"
+	"flight-api/pkg/logger"
+	"flight-api/pkg/metar"
+	"flight-api/util"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newFanoutDeps(t *testing.T, cfg *config.Config) (sqlmock.Sqlmock, *repository_airport.AirportRepositoryMock, *service_weather.WeatherServiceMock, IAirportService) {
+	t.Helper()
+
+	db, dbmock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	repoMock := &repository_airport.AirportRepositoryMock{}
+	wMock := &service_weather.WeatherServiceMock{}
+	svc := NewAirportService(logger.NewLogger(logger.INFO_DEBUG_LEVEL), cfg, util.NewValidator(), db, repoMock, wMock, nil, nil, nil)
+
+	return dbmock, repoMock, wMock, svc
+}
+
+func fanoutAirports(n int) []model.Airport {
+	airports := make([]model.Airport, n)
+	for i := range airports {
+		id := uuid.New()
+		airports[i] = model.Airport{
+			ID:        &id,
+			ICAOID:    util.Ptr(fmt.Sprintf("K%03d", i)),
+			City:      util.Ptr(fmt.Sprintf("City %d", i)),
+			CreatedAt: &timeNow,
+			UpdatedAt: &timeNow,
+		}
+	}
+	return airports
+}
+
+func TestGetWeatherCondition_BySearchName_BoundedConcurrency(t *testing.T) {
+	dbmock, repoMock, wMock, svc := newFanoutDeps(t, &config.Config{WeatherFanoutWorkers: 2, WeatherFanoutTimeout: time.Second})
+	airports := fanoutAirports(6)
+	q := queryparams.QueryParams{Limit: 6, Page: 1}
+
+	dbmock.ExpectBegin()
+	dbmock.ExpectCommit()
+	repoMock.Mock.On("FindBySearchName", mock.Anything, anyTx, "City", mock.Anything).Return(airports, len(airports), nil).Once()
+
+	var active, peak atomic.Int32
+	weather := &weather_dto.WeatherDto{Current: &weather_dto.CurrentWeatherDto{TempC: util.Ptr(20.0)}}
+	wMock.Mock.On("GetWeatherCondition", mock.Anything, mock.Anything).
+		Run(func(mock.Arguments) {
+			// The page must already be committed before any upstream call.
+			require.NoError(t, dbmock.ExpectationsWereMet())
+
+			n := active.Add(1)
+			for {
+				p := peak.Load()
+				if n <= p || peak.CompareAndSwap(p, n) {
+					break
+				}
+			}
+			time.Sleep(20 * time.Millisecond)
+			active.Add(-1)
+		}).
+		Return(weather, nil)
+
+	out, err := svc.GetWeatherCondition(context.Background(), "", "City", q, nil)
+	require.NoError(t, err)
+	require.Len(t, out.Records, 6)
+	require.LessOrEqual(t, peak.Load(), int32(2))
+
+	for i, r := range out.Records {
+		rec := r.(airport_dto.AirportWeatherDto)
+		require.Equal(t, *airports[i].ICAOID, *rec.Code, "records keep page order")
+		require.Equal(t, airport_dto.WeatherStatusOK, rec.WeatherStatus)
+		require.NotNil(t, rec.Weather)
+	}
+	wMock.Mock.AssertNumberOfCalls(t, "GetWeatherCondition", 6)
+}
+
+func TestGetWeatherCondition_BySearchName_PartialResults(t *testing.T) {
+	dbmock, repoMock, wMock, svc := newFanoutDeps(t, &config.Config{WeatherFanoutWorkers: 4, WeatherFanoutTimeout: 50 * time.Millisecond})
+	airports := fanoutAirports(3)
+	q := queryparams.QueryParams{Limit: 3, Page: 1}
+
+	dbmock.ExpectBegin()
+	dbmock.ExpectCommit()
+	repoMock.Mock.On("FindBySearchName", mock.Anything, anyTx, "City", mock.Anything).Return(airports, len(airports), nil).Once()
+
+	weather := &weather_dto.WeatherDto{Current: &weather_dto.CurrentWeatherDto{TempC: util.Ptr(20.0)}}
+	// Without coordinates the ICAO code is tried first, then the city.
+	notFound := util.NewAppError(util.ErrNotFound, "No matching location found", nil)
+	wMock.Mock.On("GetWeatherCondition", mock.Anything, airports[0].ICAOID).Return(weather, nil).Once()
+	wMock.Mock.On("GetWeatherCondition", mock.Anything, airports[1].ICAOID).Return(nil, notFound).Once()
+	wMock.Mock.On("GetWeatherCondition", mock.Anything, airports[1].City).Return(nil, notFound).Once()
+	wMock.Mock.On("GetWeatherCondition", mock.Anything, airports[2].ICAOID).
+		Run(func(args mock.Arguments) {
+			<-args.Get(0).(context.Context).Done()
+		}).
+		Return(nil, util.ErrGatewayTimeout).Once()
+
+	start := time.Now()
+	out, err := svc.GetWeatherCondition(context.Background(), "", "City", q, nil)
+	require.NoError(t, err)
+	require.Less(t, time.Since(start), time.Second, "fan-out must honour its deadline")
+
+	ok := out.Records[0].(airport_dto.AirportWeatherDto)
+	require.Equal(t, airport_dto.WeatherStatusOK, ok.WeatherStatus)
+	require.Nil(t, ok.WeatherError)
+
+	failed := out.Records[1].(airport_dto.AirportWeatherDto)
+	require.Equal(t, airport_dto.WeatherStatusError, failed.WeatherStatus)
+	require.Equal(t, airport_dto.WeatherLookupCity, failed.LookupBy)
+	require.Nil(t, failed.Weather)
+	require.Equal(t, "No matching location found", *failed.WeatherError)
+
+	timedOut := out.Records[2].(airport_dto.AirportWeatherDto)
+	require.Equal(t, airport_dto.WeatherStatusTimeout, timedOut.WeatherStatus)
+	require.NotNil(t, timedOut.WeatherError)
+}
+
+func TestGetAirportWeather_ByCoordinates(t *testing.T) {
+	dbmock, repoMock, wMock, svc := newFanoutDeps(t, &config.Config{})
+	airport := fanoutAirports(1)[0]
+	airport.Latitude = util.Ptr("40-38-23.7400N")
+	airport.Longitude = util.Ptr("073-46-43.2930W")
+	id := airport.ID.String()
+
+	dbmock.ExpectBegin()
+	dbmock.ExpectCommit()
+	repoMock.Mock.On("FindByID", mock.Anything, anyTx, id).Return(airport, nil).Once()
+
+	weather := &weather_dto.WeatherDto{
+		Location: &location_dto.LocationDto{Name: util.Ptr("Jamaica"), Lat: util.Ptr(40.69), Lon: util.Ptr(-73.8)},
+		Current:  &weather_dto.CurrentWeatherDto{TempC: util.Ptr(18.0)},
+		Source:   util.Ptr("weatherapi"),
+	}
+	wMock.Mock.On("GetWeatherCondition", mock.Anything, util.Ptr("40.6399,-73.7787")).Return(weather, nil).Once()
+
+	out, err := svc.GetAirportWeather(context.Background(), id)
+	require.NoError(t, err)
+
+	require.Equal(t, airport_dto.WeatherStatusOK, out.WeatherStatus)
+	require.Equal(t, airport_dto.WeatherLookupCoordinates, out.LookupBy)
+	require.Equal(t, "weatherapi", *out.Source)
+	require.Equal(t, 18.0, *out.Weather.TempC)
+	require.NotNil(t, out.Observation)
+	require.Equal(t, "Jamaica", *out.Observation.Name)
+	require.InDelta(t, 5.9, *out.Observation.DistanceKm, 0.1)
+}
+
+func TestGetAirportWeather_FallsBackToCity(t *testing.T) {
+	dbmock, repoMock, wMock, svc := newFanoutDeps(t, &config.Config{})
+	airport := fanoutAirports(1)[0]
+	airport.ICAOID = nil
+	id := airport.ID.String()
+
+	dbmock.ExpectBegin()
+	dbmock.ExpectCommit()
+	repoMock.Mock.On("FindByID", mock.Anything, anyTx, id).Return(airport, nil).Once()
+
+	weather := &weather_dto.WeatherDto{Current: &weather_dto.CurrentWeatherDto{TempC: util.Ptr(18.0)}}
+	wMock.Mock.On("GetWeatherCondition", mock.Anything, util.Ptr("City 0")).Return(weather, nil).Once()
+
+	out, err := svc.GetAirportWeather(context.Background(), id)
+	require.NoError(t, err)
+	require.Equal(t, airport_dto.WeatherLookupCity, out.LookupBy)
+	require.Nil(t, out.Observation, "no observation point without a location")
+}
+
+func TestGetAirportWeather_NoLookupKey(t *testing.T) {
+	dbmock, repoMock, wMock, svc := newFanoutDeps(t, &config.Config{})
+	airport := fanoutAirports(1)[0]
+	airport.ICAOID = nil
+	airport.City = nil
+	id := airport.ID.String()
+
+	dbmock.ExpectBegin()
+	dbmock.ExpectCommit()
+	repoMock.Mock.On("FindByID", mock.Anything, anyTx, id).Return(airport, nil).Once()
+
+	_, err := svc.GetAirportWeather(context.Background(), id)
+	require.ErrorIs(t, err, util.ErrNotFound)
+	wMock.Mock.AssertNotCalled(t, "GetWeatherCondition", mock.Anything, mock.Anything)
+}
+
+func TestGetAirportWeather_UpstreamFailure(t *testing.T) {
+	dbmock, repoMock, wMock, svc := newFanoutDeps(t, &config.Config{})
+	airport := fanoutAirports(1)[0]
+	id := airport.ID.String()
+
+	dbmock.ExpectBegin()
+	dbmock.ExpectCommit()
+	repoMock.Mock.On("FindByID", mock.Anything, anyTx, id).Return(airport, nil).Once()
+	wMock.Mock.On("GetWeatherCondition", mock.Anything, airport.ICAOID).
+		Return(nil, util.NewAppError(util.ErrServiceUnavailable, "Weather provider is temporarily unavailable", nil)).Once()
+
+	_, err := svc.GetAirportWeather(context.Background(), id)
+	require.ErrorIs(t, err, util.ErrServiceUnavailable)
+}
+
+func TestGetWeatherCondition_BySearchName_CategoryFilter(t *testing.T) {
+	dbmock, repoMock, wMock, svc := newFanoutDeps(t, &config.Config{})
+	airports := fanoutAirports(3)
+	q := queryparams.QueryParams{Limit: 3, Page: 1}
+
+	dbmock.ExpectBegin()
+	dbmock.ExpectCommit()
+	repoMock.Mock.On("FindBySearchName", mock.Anything, anyTx, "City", mock.Anything).Return(airports, 3, nil).Once()
+
+	visibility := []float64{10, 2, 0.5}
+	for i, airport := range airports {
+		weather := &weather_dto.WeatherDto{Current: &weather_dto.CurrentWeatherDto{VisMiles: util.Ptr(visibility[i]), Cloud: util.Ptr(0)}}
+		wMock.Mock.On("GetWeatherCondition", mock.Anything, airport.ICAOID).Return(weather, nil).Once()
+	}
+
+	out, err := svc.GetWeatherCondition(context.Background(), "", "City", q, []string{"ifr", " LIFR"})
+	require.NoError(t, err)
+
+	require.Len(t, out.Records, 2)
+	require.Equal(t, 2, out.Total)
+	require.Equal(t, metar.CategoryIFR, out.Records[0].(airport_dto.AirportWeatherDto).Category.Category)
+	require.Equal(t, metar.CategoryLIFR, out.Records[1].(airport_dto.AirportWeatherDto).Category.Category)
+}
+
+func TestGetWeatherCondition_BySearchName_CategoryFilterPages(t *testing.T) {
+	dbmock, repoMock, wMock, svc := newFanoutDeps(t, &config.Config{})
+	airports := fanoutAirports(5)
+
+	// The database hands the matches over in two batches; the filter drops
+	// the VFR airports from the middle of the first one.
+	visibility := []float64{0.5, 10, 10, 2, 0.5}
+	for i, airport := range airports {
+		weather := &weather_dto.WeatherDto{Current: &weather_dto.CurrentWeatherDto{VisMiles: util.Ptr(visibility[i]), Cloud: util.Ptr(0)}}
+		wMock.Mock.On("GetWeatherCondition", mock.Anything, airport.ICAOID).Return(weather, nil)
+	}
+
+	search := func(q queryparams.QueryParams) *pagination_dto.PaginationDto {
+		dbmock.ExpectBegin()
+		dbmock.ExpectCommit()
+		repoMock.Mock.On("FindBySearchName", mock.Anything, anyTx, "City", map[string]interface{}{"limit": categoryScanBatch, "offset": 0}).
+			Return(airports[:3], 5, nil).Once()
+		dbmock.ExpectBegin()
+		dbmock.ExpectCommit()
+		repoMock.Mock.On("FindBySearchName", mock.Anything, anyTx, "City", map[string]interface{}{"limit": categoryScanBatch, "offset": 3}).
+			Return(airports[3:], 5, nil).Once()
+
+		out, err := svc.GetWeatherCondition(context.Background(), "", "City", q, []string{"IFR", "LIFR"})
+		require.NoError(t, err)
+		return out
+	}
+
+	codes := func(out *pagination_dto.PaginationDto) []string {
+		var codes []string
+		for _, record := range out.Records {
+			codes = append(codes, *record.(airport_dto.AirportWeatherDto).Code)
+		}
+		return codes
+	}
+
+	first := search(queryparams.QueryParams{Limit: 2, Page: 1, Offset: 0})
+	require.Equal(t, []string{"K000", "K003"}, codes(first))
+	require.Equal(t, 3, first.Total)
+	require.True(t, first.Meta.Next)
+
+	second := search(queryparams.QueryParams{Limit: 2, Page: 2, Offset: 2})
+	require.Equal(t, []string{"K004"}, codes(second))
+	require.Equal(t, 3, second.Total)
+	require.False(t, second.Meta.Next)
+
+	require.NoError(t, dbmock.ExpectationsWereMet())
+}
+
+func TestGetWeatherCondition_BySearchName_CategoryScanLimit(t *testing.T) {
+	dbmock, repoMock, wMock, svc := newFanoutDeps(t, &config.Config{WeatherCategoryScan: 3})
+	airports := fanoutAirports(3)
+
+	dbmock.ExpectBegin()
+	dbmock.ExpectCommit()
+	repoMock.Mock.On("FindBySearchName", mock.Anything, anyTx, "City", map[string]interface{}{"limit": 3, "offset": 0}).
+		Return(airports, 250, nil).Once()
+
+	weather := &weather_dto.WeatherDto{Current: &weather_dto.CurrentWeatherDto{VisMiles: util.Ptr(0.5), Cloud: util.Ptr(0)}}
+	wMock.Mock.On("GetWeatherCondition", mock.Anything, mock.Anything).Return(weather, nil)
+
+	out, err := svc.GetWeatherCondition(context.Background(), "", "City", queryparams.QueryParams{Limit: 2, Page: 1}, []string{"LIFR"})
+	require.NoError(t, err)
+
+	require.Len(t, out.Records, 2)
+	require.Equal(t, 3, out.Total)
+	require.True(t, out.Meta.Next)
+	require.True(t, out.Meta.Truncated)
+	repoMock.Mock.AssertNumberOfCalls(t, "FindBySearchName", 1)
+	require.NoError(t, dbmock.ExpectationsWereMet())
+}
+
+func TestGetWeatherCondition_BySearchName_CategoryScanDeadline(t *testing.T) {
+	dbmock, repoMock, wMock, svc := newFanoutDeps(t, &config.Config{WeatherFanoutTimeout: 50 * time.Millisecond})
+	airports := fanoutAirports(2)
+
+	dbmock.ExpectBegin()
+	dbmock.ExpectCommit()
+	repoMock.Mock.On("FindBySearchName", mock.Anything, anyTx, "City", map[string]interface{}{"limit": categoryScanBatch, "offset": 0}).
+		Return(airports, 5000, nil).Once()
+
+	weather := &weather_dto.WeatherDto{Current: &weather_dto.CurrentWeatherDto{VisMiles: util.Ptr(0.5), Cloud: util.Ptr(0)}}
+	wMock.Mock.On("GetWeatherCondition", mock.Anything, airports[0].ICAOID).Return(weather, nil).Once()
+	wMock.Mock.On("GetWeatherCondition", mock.Anything, airports[1].ICAOID).
+		Run(func(args mock.Arguments) { <-args.Get(0).(context.Context).Done() }).
+		Return(nil, util.ErrGatewayTimeout).Once()
+
+	out, err := svc.GetWeatherCondition(context.Background(), "", "City", queryparams.QueryParams{Limit: 10, Page: 1}, []string{"LIFR"})
+	require.NoError(t, err)
+
+	require.Equal(t, 1, out.Total)
+	require.True(t, out.Meta.Truncated)
+	repoMock.Mock.AssertNumberOfCalls(t, "FindBySearchName", 1)
+}
+
+func TestGetWeatherCondition_UnknownCategory(t *testing.T) {
+	_, _, _, svc := newFanoutDeps(t, &config.Config{})
+
+	_, err := svc.GetWeatherCondition(context.Background(), "KJFK", "", queryparams.QueryParams{}, []string{"SVFR"})
+	require.ErrorIs(t, err, util.ErrBadRequest)
+}
+
+func newMetarDeps(t *testing.T) (sqlmock.Sqlmock, *repository_airport.AirportRepositoryMock, *service_weather.WeatherServiceMock, *service_metar.MetarServiceMock, IAirportService) {
+	t.Helper()
+
+	db, dbmock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	repoMock := &repository_airport.AirportRepositoryMock{}
+	wMock := &service_weather.WeatherServiceMock{}
+	mMock := &service_metar.MetarServiceMock{}
+	svc := NewAirportService(logger.NewLogger(logger.INFO_DEBUG_LEVEL), &config.Config{}, util.NewValidator(), db, repoMock, wMock, nil, mMock, nil)
+
+	return dbmock, repoMock, wMock, mMock, svc
+}
+
+func TestGetAirportWeather_PrefersMetar(t *testing.T) {
+	dbmock, repoMock, wMock, mMock, svc := newMetarDeps(t)
+	airport := fanoutAirports(1)[0]
+	airport.Latitude = util.Ptr("40-38-23.7400N")
+	airport.Longitude = util.Ptr("073-46-43.2930W")
+	id := airport.ID.String()
+
+	dbmock.ExpectBegin()
+	dbmock.ExpectCommit()
+	repoMock.Mock.On("FindByID", mock.Anything, anyTx, id).Return(airport, nil).Once()
+
+	observed, err := metar.ParseAt("K000 061751Z 18012G22KT 3SM -RA BKN015 OVC030 17/11 A2992 RMK AO2 T01720106", time.Date(2025, 10, 6, 18, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	mMock.Mock.On("Latest", mock.Anything, "K000").Return(observed, nil).Once()
+
+	out, err := svc.GetAirportWeather(context.Background(), id)
+	require.NoError(t, err)
+
+	require.Equal(t, airport_dto.WeatherStatusOK, out.WeatherStatus)
+	require.Equal(t, airport_dto.WeatherLookupICAO, out.LookupBy)
+	require.Equal(t, airport_dto.WeatherSourceMetar, *out.Source)
+	require.Equal(t, observed.Time, *out.FetchedAt)
+	require.Same(t, observed, out.Metar)
+	require.Equal(t, 17.2, *out.Weather.TempC)
+	require.Equal(t, 180, *out.Weather.WindDegree)
+	require.Equal(t, "S", *out.Weather.WindDir)
+	require.Equal(t, 22.2, *out.Weather.WindKph)
+	require.Equal(t, 1013.2, *out.Weather.PressureMb)
+	require.Equal(t, 3.0, *out.Weather.VisMiles)
+	require.Equal(t, 100, *out.Weather.Cloud)
+	require.Equal(t, "Light rain", *out.Weather.Condition.Text)
+	require.Equal(t, metar.CategoryMVFR, out.Category.Category)
+	require.Equal(t, 1500, *out.Category.CeilingFt)
+	require.Nil(t, out.Performance, "no performance conditions without an elevation")
+	wMock.Mock.AssertNotCalled(t, "GetWeatherCondition", mock.Anything, mock.Anything)
+}
+
+func TestGetAirportWeather_NoMetarFallsBackToConsumerWeather(t *testing.T) {
+	dbmock, repoMock, wMock, mMock, svc := newMetarDeps(t)
+	airport := fanoutAirports(1)[0]
+	id := airport.ID.String()
+
+	dbmock.ExpectBegin()
+	dbmock.ExpectCommit()
+	repoMock.Mock.On("FindByID", mock.Anything, anyTx, id).Return(airport, nil).Once()
+	mMock.Mock.On("Latest", mock.Anything, "K000").Return(nil, util.NewAppError(util.ErrNotFound, "No METAR received for K000", nil)).Once()
+
+	weather := &weather_dto.WeatherDto{Current: &weather_dto.CurrentWeatherDto{TempC: util.Ptr(18.0)}, Source: util.Ptr("weatherapi")}
+	wMock.Mock.On("GetWeatherCondition", mock.Anything, airport.ICAOID).Return(weather, nil).Once()
+
+	out, err := svc.GetAirportWeather(context.Background(), id)
+	require.NoError(t, err)
+	require.Equal(t, "weatherapi", *out.Source)
+	require.Nil(t, out.Metar)
+}
+
+func hourlyForecast(start time.Time, hours int) *weather_dto.ForecastDto {
+	day := weather_dto.ForecastDayDto{Date: util.Ptr(start.Format("2006-01-02"))}
+	for h := 0; h < hours; h++ {
+		day.Hour = append(day.Hour, weather_dto.HourlyWeatherDto{
+			TimeEpoch: util.Ptr(int(start.Add(time.Duration(h) * time.Hour).Unix())),
+			TempC:     util.Ptr(float64(20 + h)),
+		})
+	}
+	return &weather_dto.ForecastDto{
+		Location: &location_dto.LocationDto{Name: util.Ptr("San Jose"), Lat: util.Ptr(37.36), Lon: util.Ptr(-121.93)},
+		Forecast: &weather_dto.ForecastPeriodsDto{ForecastDay: []weather_dto.ForecastDayDto{day}},
+		Source:   util.Ptr("weatherapi"),
+	}
+}
+
+func TestGetAirportForecast_ConditionsAtArrival(t *testing.T) {
+	dbmock, repoMock, wMock, svc := newFanoutDeps(t, &config.Config{})
+	now := time.Date(2025, 10, 6, 6, 0, 0, 0, time.UTC)
+	svc.(*AirportService).now = func() time.Time { return now }
+
+	airport := fanoutAirports(1)[0]
+	airport.Latitude = util.Ptr("37-21-50.0000N")
+	airport.Longitude = util.Ptr("121-55-44.0000W")
+	id := airport.ID.String()
+
+	dbmock.ExpectBegin()
+	dbmock.ExpectCommit()
+	repoMock.Mock.On("FindByID", mock.Anything, anyTx, id).Return(airport, nil).Once()
+
+	// Arrival two days out needs four forecast days, not the default three.
+	arrival := now.Add(2*24*time.Hour + 3*time.Hour + 20*time.Minute)
+	forecast := hourlyForecast(arrival.Truncate(24*time.Hour), 24)
+	wMock.Mock.On("GetWeatherForecast", mock.Anything, util.Ptr("37.3639,-121.9289"), 4).Return(forecast, nil).Once()
+
+	out, err := svc.GetAirportForecast(context.Background(), id, 0, &arrival)
+	require.NoError(t, err)
+
+	require.Equal(t, "airport_forecast", out.Object)
+	require.Equal(t, airport_dto.WeatherLookupCoordinates, out.LookupBy)
+	require.Equal(t, "weatherapi", *out.Source)
+	require.Len(t, out.Forecast, 1)
+	require.NotNil(t, out.AtTime)
+	require.Equal(t, 29.0, *out.AtTime.TempC)
+	require.NotNil(t, out.Observation.DistanceKm)
+}
+
+func TestGetAirportForecast_DefaultDays(t *testing.T) {
+	dbmock, repoMock, wMock, svc := newFanoutDeps(t, &config.Config{})
+	airport := fanoutAirports(1)[0]
+	id := airport.ID.String()
+
+	dbmock.ExpectBegin()
+	dbmock.ExpectCommit()
+	repoMock.Mock.On("FindByID", mock.Anything, anyTx, id).Return(airport, nil).Once()
+	wMock.Mock.On("GetWeatherForecast", mock.Anything, airport.ICAOID, service_weather.DefaultForecastDays).
+		Return(hourlyForecast(time.Now().Truncate(24*time.Hour), 24), nil).Once()
+
+	out, err := svc.GetAirportForecast(context.Background(), id, 0, nil)
+	require.NoError(t, err)
+	require.Equal(t, airport_dto.WeatherLookupICAO, out.LookupBy)
+	require.Nil(t, out.AtTime)
+	wMock.Mock.AssertExpectations(t)
+}
+
+func TestGetAirportForecast_AtOutsideForecast(t *testing.T) {
+	dbmock, repoMock, wMock, svc := newFanoutDeps(t, &config.Config{})
+	now := time.Date(2025, 10, 6, 6, 0, 0, 0, time.UTC)
+	svc.(*AirportService).now = func() time.Time { return now }
+
+	past := now.Add(-3 * time.Hour)
+	_, err := svc.GetAirportForecast(context.Background(), uuid.NewString(), 0, &past)
+	require.ErrorIs(t, err, util.ErrBadRequest)
+
+	airport := fanoutAirports(1)[0]
+	id := airport.ID.String()
+	dbmock.ExpectBegin()
+	dbmock.ExpectCommit()
+	repoMock.Mock.On("FindByID", mock.Anything, anyTx, id).Return(airport, nil).Once()
+
+	// Beyond the longest forecast available.
+	late := now.AddDate(0, 0, 30)
+	wMock.Mock.On("GetWeatherForecast", mock.Anything, airport.ICAOID, service_weather.MaxForecastDays).
+		Return(hourlyForecast(now.Truncate(24*time.Hour), 24), nil).Once()
+
+	_, err = svc.GetAirportForecast(context.Background(), id, 0, &late)
+	require.ErrorIs(t, err, util.ErrBadRequest)
+}
+
+func TestGetPerformanceConditions(t *testing.T) {
+	dbmock, repoMock, wMock, svc := newFanoutDeps(t, &config.Config{DensityAltWarnFt: 5000, DensityAltExcessFt: 2000})
+	airport := fanoutAirports(1)[0]
+	airport.Elevation = util.Ptr(int64(5000))
+	id := airport.ID.String()
+
+	dbmock.ExpectBegin()
+	dbmock.ExpectCommit()
+	repoMock.Mock.On("FindByID", mock.Anything, anyTx, id).Return(airport, nil).Once()
+
+	current := &weather_dto.CurrentWeatherDto{TempC: util.Ptr(32.0), DewpointC: util.Ptr(10.0), PressureMb: util.Ptr(1008.0)}
+	weather := &weather_dto.WeatherDto{Current: current, Source: util.Ptr("weatherapi")}
+	wMock.Mock.On("GetWeatherCondition", mock.Anything, airport.ICAOID).Return(weather, nil).Once()
+
+	out, err := svc.GetPerformanceConditions(context.Background(), id)
+	require.NoError(t, err)
+
+	require.Equal(t, "performance_conditions", out.Object)
+	require.Equal(t, "weatherapi", *out.Source)
+	require.InDelta(t, 5144, out.Performance.PressureAltitudeFt, 5)
+	require.Equal(t, 27.2, out.Performance.IsaDeviationC)
+	require.Equal(t, []string{airport_dto.PerformanceFlagHighDensityAltitude, airport_dto.PerformanceFlagDensityAboveField}, out.Performance.Flags)
+}
+
+func TestGetPerformanceConditions_NoElevation(t *testing.T) {
+	dbmock, repoMock, wMock, svc := newFanoutDeps(t, &config.Config{})
+	airport := fanoutAirports(1)[0]
+	id := airport.ID.String()
+
+	dbmock.ExpectBegin()
+	dbmock.ExpectCommit()
+	repoMock.Mock.On("FindByID", mock.Anything, anyTx, id).Return(airport, nil).Once()
+
+	_, err := svc.GetPerformanceConditions(context.Background(), id)
+	require.ErrorIs(t, err, util.ErrNotFound)
+	wMock.Mock.AssertNotCalled(t, "GetWeatherCondition", mock.Anything, mock.Anything)
+}
+
+func TestGetPerformanceConditions_NoPressure(t *testing.T) {
+	dbmock, repoMock, wMock, svc := newFanoutDeps(t, &config.Config{})
+	airport := fanoutAirports(1)[0]
+	airport.Elevation = util.Ptr(int64(13))
+	id := airport.ID.String()
+
+	dbmock.ExpectBegin()
+	dbmock.ExpectCommit()
+	repoMock.Mock.On("FindByID", mock.Anything, anyTx, id).Return(airport, nil).Once()
+
+	weather := &weather_dto.WeatherDto{Current: &weather_dto.CurrentWeatherDto{TempC: util.Ptr(18.0)}}
+	wMock.Mock.On("GetWeatherCondition", mock.Anything, airport.ICAOID).Return(weather, nil).Once()
+
+	_, err := svc.GetPerformanceConditions(context.Background(), id)
+	require.ErrorIs(t, err, util.ErrNotFound)
+}
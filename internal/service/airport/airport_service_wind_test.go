@@ -0,0 +1,184 @@
+package service_airport
+
+import (
+	"context"
+	"flight-api/config"
+	airport_dto "flight-api/internal/dto/airport"
+	weather_dto "flight-api/internal/dto/weather"
+	"flight-api/internal/model"
+	repository_airport "flight-api/internal/repository/airport"
+	repository_runway "flight-api/internal/repository/runway"
+	service_metar "flight-api/internal/service/metar"
+	service_weather "flight-api/internal/service/weather"
+	"flight-api/pkg/logger"
+	"flight-api/pkg/metar"
+	"flight-api/util"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type windDeps struct {
+	db      sqlmock.Sqlmock
+	repo    *repository_airport.AirportRepositoryMock
+	runways *repository_runway.RunwayRepositoryMock
+	weather *service_weather.WeatherServiceMock
+	metar   *service_metar.MetarServiceMock
+	svc     IAirportService
+}
+
+func newWindDeps(t *testing.T) windDeps {
+	t.Helper()
+
+	db, dbmock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	deps := windDeps{
+		db:      dbmock,
+		repo:    &repository_airport.AirportRepositoryMock{},
+		runways: &repository_runway.RunwayRepositoryMock{},
+		weather: &service_weather.WeatherServiceMock{},
+		metar:   &service_metar.MetarServiceMock{},
+	}
+	deps.svc = NewAirportService(logger.NewLogger(logger.INFO_DEBUG_LEVEL), &config.Config{}, util.NewValidator(), db,
+		deps.repo, deps.weather, nil, deps.metar, deps.runways)
+	return deps
+}
+
+func (d windDeps) noMetar(icao string) {
+	d.metar.Mock.On("Latest", mock.Anything, icao).Return(nil, util.NewAppError(util.ErrNotFound, "No METAR received for "+icao, nil)).Once()
+}
+
+func (d windDeps) withMetar(t *testing.T, raw string) {
+	t.Helper()
+	observed, err := metar.ParseAt(raw, time.Date(2025, 10, 6, 18, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	d.metar.Mock.On("Latest", mock.Anything, observed.Station).Return(observed, nil).Once()
+}
+
+func TestGetWindComponents_RequestedHeadings(t *testing.T) {
+	deps := newWindDeps(t)
+	airport := fanoutAirports(1)[0]
+	airport.MagVariation = util.Ptr("13W")
+	id := airport.ID.String()
+
+	deps.db.ExpectBegin()
+	deps.db.ExpectCommit()
+	deps.repo.Mock.On("FindByID", mock.Anything, anyTx, id).Return(airport, nil).Once()
+	deps.noMetar("K000")
+
+	weather := &weather_dto.WeatherDto{
+		Current: &weather_dto.CurrentWeatherDto{WindDegree: util.Ptr(250), WindKph: util.Ptr(37.04), GustKph: util.Ptr(55.56)},
+		Source:  util.Ptr("weatherapi"),
+	}
+	deps.weather.Mock.On("GetWeatherCondition", mock.Anything, airport.ICAOID).Return(weather, nil).Once()
+
+	out, err := deps.svc.GetWindComponents(context.Background(), id, []int{40, 220})
+	require.NoError(t, err)
+
+	require.Equal(t, airport_dto.RunwaySourceRequest, out.RunwaySource)
+	require.Equal(t, -13.0, *out.MagneticVariation)
+	require.Equal(t, 20.0, out.Wind.SpeedKt)
+	require.Equal(t, 30.0, *out.Wind.GustKt)
+	require.Equal(t, 263.0, *out.Wind.DirectionMagnetic)
+	require.Equal(t, "weatherapi", *out.Source)
+
+	require.Len(t, out.Runways, 2)
+	r04, r22 := out.Runways[0], out.Runways[1]
+	require.Equal(t, "04", r04.Runway)
+	require.Equal(t, 27.0, r04.HeadingTrue)
+	require.True(t, r04.Steady.Tailwind)
+	require.Equal(t, -14.6, r04.Steady.HeadwindKt)
+	require.Equal(t, airport_dto.CrosswindFromLeft, r04.Steady.CrosswindFrom)
+
+	require.Equal(t, "22", r22.Runway)
+	require.Equal(t, 207.0, r22.HeadingTrue)
+	require.Equal(t, 14.6, r22.Steady.HeadwindKt)
+	require.Equal(t, 13.6, r22.Steady.CrosswindKt)
+	require.Equal(t, 25.2, r22.Steady.CrosswindKph)
+	require.Equal(t, airport_dto.CrosswindFromRight, r22.Steady.CrosswindFrom)
+	require.NotNil(t, r22.Gust)
+	require.Equal(t, 20.5, r22.Gust.CrosswindKt)
+
+	require.Equal(t, "22", *out.Recommended)
+}
+
+func TestGetWindComponents_StoredRunwaysAndMetar(t *testing.T) {
+	deps := newWindDeps(t)
+	airport := fanoutAirports(1)[0]
+	id := airport.ID.String()
+
+	deps.db.ExpectBegin()
+	deps.db.ExpectCommit()
+	deps.repo.Mock.On("FindByID", mock.Anything, anyTx, id).Return(airport, nil).Once()
+
+	deps.db.ExpectBegin()
+	deps.db.ExpectCommit()
+	deps.runways.Mock.On("FindByAirportID", mock.Anything, anyTx, id).Return([]model.Runway{
+		{Ident: util.Ptr("13"), MagneticHeading: util.Ptr(int64(134))},
+		{Ident: util.Ptr("31"), TrueHeading: util.Ptr(int64(314))},
+		{Ident: util.Ptr("H1")},
+	}, nil).Once()
+	deps.withMetar(t, "K000 061751Z 30015KT 10SM CLR 17/11 A2992")
+
+	out, err := deps.svc.GetWindComponents(context.Background(), id, nil)
+	require.NoError(t, err)
+
+	require.Equal(t, airport_dto.RunwaySourceStored, out.RunwaySource)
+	require.Nil(t, out.MagneticVariation)
+	require.Equal(t, airport_dto.WeatherSourceMetar, *out.Source)
+	require.Equal(t, 15.0, out.Wind.SpeedKt)
+	require.Len(t, out.Runways, 2, "a runway without heading or designator is skipped")
+	require.Nil(t, out.Runways[1].Gust)
+	require.Equal(t, 14.6, out.Runways[1].Steady.HeadwindKt)
+	require.Equal(t, "31", *out.Recommended)
+	deps.weather.Mock.AssertNotCalled(t, "GetWeatherCondition", mock.Anything, mock.Anything)
+}
+
+func TestGetWindComponents_VariableWind(t *testing.T) {
+	deps := newWindDeps(t)
+	airport := fanoutAirports(1)[0]
+	id := airport.ID.String()
+
+	deps.db.ExpectBegin()
+	deps.db.ExpectCommit()
+	deps.repo.Mock.On("FindByID", mock.Anything, anyTx, id).Return(airport, nil).Once()
+	deps.withMetar(t, "K000 061751Z VRB03KT 10SM CLR 17/11 A2992")
+
+	out, err := deps.svc.GetWindComponents(context.Background(), id, []int{90})
+	require.NoError(t, err)
+	require.True(t, out.Wind.Variable)
+	require.Nil(t, out.Runways[0].Steady)
+	require.Nil(t, out.Recommended)
+}
+
+func TestGetWindComponents_NoRunwayData(t *testing.T) {
+	deps := newWindDeps(t)
+	airport := fanoutAirports(1)[0]
+	id := airport.ID.String()
+
+	deps.db.ExpectBegin()
+	deps.db.ExpectCommit()
+	deps.repo.Mock.On("FindByID", mock.Anything, anyTx, id).Return(airport, nil).Once()
+	deps.db.ExpectBegin()
+	deps.db.ExpectCommit()
+	deps.runways.Mock.On("FindByAirportID", mock.Anything, anyTx, id).Return([]model.Runway{}, nil).Once()
+
+	_, err := deps.svc.GetWindComponents(context.Background(), id, nil)
+	require.ErrorIs(t, err, util.ErrNotFound)
+	deps.weather.Mock.AssertNotCalled(t, "GetWeatherCondition", mock.Anything, mock.Anything)
+}
+
+func TestGetWindComponents_InvalidHeading(t *testing.T) {
+	deps := newWindDeps(t)
+
+	_, err := deps.svc.GetWindComponents(context.Background(), "any", []int{0})
+	require.ErrorIs(t, err, util.ErrBadRequest)
+
+	_, err = deps.svc.GetWindComponents(context.Background(), "any", []int{361})
+	require.ErrorIs(t, err, util.ErrBadRequest)
+}
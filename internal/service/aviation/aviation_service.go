@@ -7,4 +7,5 @@ import (
 
 type IAviationService interface {
 	FetchAirportData(ctx context.Context, icaoCodes []string) (map[string]airport_dto.AirportRequestDto, error)
+	FetchAirportDataBatched(ctx context.Context, icaoCodes []string) (map[string]airport_dto.AirportRequestDto, map[string]error)
 }
@@ -6,28 +6,51 @@ import (
 	"flight-api/config"
 	airport_dto "flight-api/internal/dto/airport"
 	aviation_dto "flight-api/internal/dto/aviation"
+	"flight-api/pkg/httpclient"
 	"flight-api/pkg/logger"
 	"flight-api/util"
+	"fmt"
 	"io"
 	"net"
 	"net/http"
+	"slices"
 	"strings"
-	"time"
+	"sync"
+)
+
+const (
+	defaultAviationBatchSize   = 25
+	defaultAviationConcurrency = 4
 )
 
 type AviationService struct {
 	logger *logger.Logger
 	cfg    *config.Config
-	client *http.Client
+	client *httpclient.Client
+}
+
+// NewAviationClient builds the HTTP client used to call the Aviation API, with the
+// timeout, retry, circuit breaker and rate limit settings from cfg.
+func NewAviationClient(logger *logger.Logger, cfg *config.Config) *httpclient.Client {
+	return httpclient.NewClient(logger, httpclient.Options{
+		Name:                  "aviation",
+		Timeout:               cfg.AviationTimeout,
+		MaxRetries:            cfg.HTTPMaxRetries,
+		RetryBaseDelay:        cfg.HTTPRetryBase,
+		RetryMaxDelay:         cfg.HTTPRetryMax,
+		BreakerThreshold:      cfg.BreakerThreshold,
+		BreakerOpenTimeout:    cfg.BreakerOpenTimeout,
+		BreakerHalfOpenProbes: cfg.BreakerHalfOpenProbes,
+		RateLimit:             cfg.AviationRateLimit,
+		RateBurst:             cfg.AviationRateBurst,
+	})
 }
 
-func NewAviationService(logger *logger.Logger, cfg *config.Config) IAviationService {
+func NewAviationService(logger *logger.Logger, cfg *config.Config, client *httpclient.Client) IAviationService {
 	return &AviationService{
 		logger: logger,
 		cfg:    cfg,
-		client: &http.Client{
-			Timeout: 60 * time.Second,
-		},
+		client: client,
 	}
 }
 
@@ -43,12 +66,16 @@ func (s *AviationService) FetchAirportData(ctx context.Context, icaoCodes []stri
 	s.logger.Debugf("Request URL: %s", URL)
 
 	s.logger.Debugf("[FetchiAirportData] ICAO code: %s", apt)
-	resp, err := s.client.Get(URL)
+	resp, err := s.client.Get(ctx, URL)
 	if err != nil {
 		s.logger.Errorf("[FetchiAirportData] Error fetching data for ICAO code %s: %v", apt, err)
 
+		if errors.Is(err, httpclient.ErrCircuitOpen) {
+			return nil, util.NewAppError(util.ErrServiceUnavailable, "Aviation provider is temporarily unavailable", err)
+		}
+
 		var ne net.Error
-		if errors.As(err, &ne) && ne.Timeout() {
+		if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &ne) && ne.Timeout()) {
 			return nil, util.ErrGatewayTimeout
 		}
 
@@ -60,7 +87,7 @@ func (s *AviationService) FetchAirportData(ctx context.Context, icaoCodes []stri
 
 	if resp.StatusCode != http.StatusOK {
 		s.logger.Errorf("[FetchiAirportData] Failed to fetch data for ICAO code %s: %s", apt, resp.Status)
-		return nil, util.ErrBadRequest
+		return nil, aviationAPIStatusError(resp.StatusCode)
 	}
 	s.logger.Debugf("[FetchiAirportData] Successfully fetched data for ICAO code %s", apt)
 
@@ -96,3 +123,91 @@ func (s *AviationService) FetchAirportData(ctx context.Context, icaoCodes []stri
 	s.logger.Debug("Finished fetching airport data.")
 	return airportsData, nil
 }
+
+// aviationAPIStatusError maps a non-200 Aviation API response from its HTTP
+// status. Only a 400 blames the request; rate limiting and server-side
+// failures are the provider's problem.
+func aviationAPIStatusError(status int) error {
+	switch {
+	case status == http.StatusBadRequest:
+		return util.ErrBadRequest
+	case status == http.StatusTooManyRequests:
+		return util.NewAppError(util.ErrServiceUnavailable, "Aviation API rate limit exceeded", nil)
+	default:
+		return util.NewAppError(util.ErrBadGateway, fmt.Sprintf("Aviation API responded with status %d", status), nil)
+	}
+}
+
+// FetchAirportDataBatched fetches any number of ICAO codes in batches of
+// AVIATION_API_BATCH_SIZE, running up to AVIATION_API_CONCURRENCY batches at
+// once. A batch that fails fails each of its codes, reported in the second
+// map. A batch the Aviation API rejects with a 400 is split in half
+// and retried, so one bad code does not fail the codes batched with it.
+func (s *AviationService) FetchAirportDataBatched(ctx context.Context, icaoCodes []string) (map[string]airport_dto.AirportRequestDto, map[string]error) {
+	fetched := make(map[string]airport_dto.AirportRequestDto, len(icaoCodes))
+	failed := make(map[string]error)
+	var mu sync.Mutex
+
+	record := func(batch []string, data map[string]airport_dto.AirportRequestDto, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		for _, code := range batch {
+			if err != nil {
+				failed[code] = err
+				continue
+			}
+			fetched[code] = data[code]
+		}
+	}
+
+	var fetch func(batch []string)
+	fetch = func(batch []string) {
+		data, err := s.FetchAirportData(ctx, batch)
+		if errors.Is(err, util.ErrBadRequest) && len(batch) > 1 && ctx.Err() == nil {
+			s.logger.Warnf("[FetchAirportDataBatched] Batch of %d ICAO codes rejected, splitting it", len(batch))
+			half := len(batch) / 2
+			fetch(batch[:half])
+			fetch(batch[half:])
+			return
+		}
+		record(batch, data, err)
+	}
+
+	sem := make(chan struct{}, s.concurrency())
+	var wg sync.WaitGroup
+
+	for batch := range slices.Chunk(icaoCodes, s.batchSize()) {
+		wg.Add(1)
+		go func(batch []string) {
+			defer wg.Done()
+
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				record(batch, nil, util.NewAppError(util.ErrGatewayTimeout, "Aviation API request was abandoned", ctx.Err()))
+				return
+			}
+
+			fetch(batch)
+		}(batch)
+	}
+
+	wg.Wait()
+	s.logger.Debugf("[FetchAirportDataBatched] Fetched %d ICAO codes, %d failed", len(fetched), len(failed))
+	return fetched, failed
+}
+
+func (s *AviationService) batchSize() int {
+	if s.cfg.AviationBatchSize > 0 {
+		return s.cfg.AviationBatchSize
+	}
+	return defaultAviationBatchSize
+}
+
+func (s *AviationService) concurrency() int {
+	if s.cfg.AviationConcurrency > 0 {
+		return s.cfg.AviationConcurrency
+	}
+	return defaultAviationConcurrency
+}
@@ -21,3 +21,18 @@ func (m *AviationServiceMock) FetchAirportData(ctx context.Context, icaoCodes []
 	}
 	return out, args.Error(1)
 }
+
+// FetchAirportDataBatched(ctx, icaoCodes) (map[string]AirportRequestDto, map[string]error)
+func (m *AviationServiceMock) FetchAirportDataBatched(ctx context.Context, icaoCodes []string) (map[string]airport_dto.AirportRequestDto, map[string]error) {
+	args := m.Mock.Called(ctx, icaoCodes)
+
+	var out map[string]airport_dto.AirportRequestDto
+	if v, ok := args.Get(0).(map[string]airport_dto.AirportRequestDto); ok {
+		out = v
+	}
+	var failed map[string]error
+	if v, ok := args.Get(1).(map[string]error); ok {
+		failed = v
+	}
+	return out, failed
+}
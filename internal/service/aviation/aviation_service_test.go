@@ -7,6 +7,7 @@ import (
 	"flight-api/config"
 	airport_dto "flight-api/internal/dto/airport"
 	aviation_dto "flight-api/internal/dto/aviation"
+	"flight-api/pkg/httpclient"
 	"flight-api/pkg/logger"
 	"flight-api/util"
 	"io"
@@ -14,6 +15,7 @@ import (
 	"net/http/httptest"
 	"reflect"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -220,7 +222,8 @@ func TestNewAviationService_InitializesFields(t *testing.T) {
 	cfg := &config.Config{AviationURL: "http://example.com"}
 
 	// Act
-	svc := NewAviationService(log, cfg)
+	client := NewAviationClient(log, cfg)
+	svc := NewAviationService(log, cfg, client)
 
 	// Assert 1: implement IAviationService (compile-time + runtime)
 	var _ IAviationService = svc // compile-time assertion
@@ -232,6 +235,8 @@ func TestNewAviationService_InitializesFields(t *testing.T) {
 	require.NotNil(t, impl)
 	require.Equal(t, log, impl.logger) // kalau ada getter; atau langsung impl.logger kalau di paket sama
 	require.Equal(t, cfg, impl.cfg)    // kalau ada getter/field exported; sesuaikan aksesnya
+	require.Same(t, client, impl.client)
+	require.Equal(t, "aviation", impl.client.Name())
 }
 
 // ---- Test FetchAirportData ----
@@ -244,9 +249,7 @@ func TestFetchAirportData_Success(t *testing.T) {
 			AviationURL: srv.URL,
 		},
 		logger: logger.NewLogger(logger.INFO_DEBUG_LEVEL),
-		client: &http.Client{
-			Timeout: 60 * time.Second,
-		},
+		client: httpclient.NewClient(logger.NewLogger(logger.INFO_DEBUG_LEVEL), httpclient.Options{Timeout: 60 * time.Second}),
 	}
 
 	tests := []struct {
@@ -434,15 +437,43 @@ func TestFetchAirportData_BadRequest(t *testing.T) {
 			AviationURL: srv.URL,
 		},
 		logger: logger.NewLogger(logger.DEBUG_LEVEL),
-		client: &http.Client{
-			Timeout: 60 * time.Second,
-		},
+		client: httpclient.NewClient(logger.NewLogger(logger.INFO_DEBUG_LEVEL), httpclient.Options{Timeout: 60 * time.Second}),
 	}
 
 	_, err := svc.FetchAirportData(context.Background(), []string{"BADREQUEST"})
 	assert.ErrorIs(t, err, util.ErrBadRequest)
 }
 
+func TestFetchAirportData_Error_ProviderSideStatus(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		want   error
+	}{
+		{name: "rate limited", status: http.StatusTooManyRequests, want: util.ErrServiceUnavailable},
+		{name: "server error", status: http.StatusInternalServerError, want: util.ErrBadGateway},
+		{name: "service unavailable", status: http.StatusServiceUnavailable, want: util.ErrBadGateway},
+		{name: "not found", status: http.StatusNotFound, want: util.ErrBadGateway},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rt := roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+				return &http.Response{StatusCode: tt.status, Body: io.NopCloser(strings.NewReader("")), Header: make(http.Header), Request: r}, nil
+			})
+			svc := &AviationService{
+				cfg:    &config.Config{AviationURL: "http://mock"},
+				client: httpclient.NewClient(logger.NewLogger(logger.INFO_DEBUG_LEVEL), httpclient.Options{Transport: rt}),
+				logger: logger.NewLogger(logger.INFO_DEBUG_LEVEL),
+			}
+
+			_, err := svc.FetchAirportData(context.Background(), []string{"KJFK"})
+			assert.ErrorIs(t, err, tt.want)
+			assert.NotErrorIs(t, err, util.ErrBadRequest)
+		})
+	}
+}
+
 func TestFetchAirportData_InternalServerError(t *testing.T) {
 	srv := newAviationMockServer(map[string][]map[string]string{
 		"KKKK": {},
@@ -454,9 +485,7 @@ func TestFetchAirportData_InternalServerError(t *testing.T) {
 			AviationURL: srv.URL,
 		},
 		logger: logger.NewLogger(logger.DEBUG_LEVEL),
-		client: &http.Client{
-			Timeout: 60 * time.Second,
-		},
+		client: httpclient.NewClient(logger.NewLogger(logger.INFO_DEBUG_LEVEL), httpclient.Options{Timeout: 60 * time.Second}),
 	}
 
 	_, err := svc.FetchAirportData(context.Background(), []string{"KKKK"})
@@ -472,9 +501,7 @@ func TestFetchAirportData_Error_Timeout(t *testing.T) {
 			AviationURL: srv.URL,
 		},
 		logger: logger.NewLogger(logger.DEBUG_LEVEL),
-		client: &http.Client{
-			Timeout: 50 * time.Millisecond,
-		},
+		client: httpclient.NewClient(logger.NewLogger(logger.INFO_DEBUG_LEVEL), httpclient.Options{Timeout: 50 * time.Millisecond}),
 	}
 
 	_, err := svc.FetchAirportData(context.Background(), []string{"KJFK"})
@@ -486,7 +513,7 @@ func TestFetchAirportData_Error_InternalServer(t *testing.T) {
 	rt := roundTripperFunc(func(r *http.Request) (*http.Response, error) {
 		return nil, errors.New("weird client error")
 	})
-	client := &http.Client{Transport: rt}
+	client := httpclient.NewClient(logger.NewLogger(logger.INFO_DEBUG_LEVEL), httpclient.Options{Transport: rt})
 
 	svc := &AviationService{
 		cfg:    &config.Config{AviationURL: "http://mock"},
@@ -509,7 +536,7 @@ func TestFetchAirportData_ReadBodyError(t *testing.T) {
 		}, nil
 	})
 
-	client := &http.Client{Transport: rt}
+	client := httpclient.NewClient(logger.NewLogger(logger.INFO_DEBUG_LEVEL), httpclient.Options{Transport: rt})
 
 	svc := &AviationService{
 		cfg:    &config.Config{AviationURL: "http://mock"},
@@ -521,3 +548,104 @@ func TestFetchAirportData_ReadBodyError(t *testing.T) {
 	assert.NotNil(t, err, "expected error")
 	assert.ErrorIs(t, err, util.ErrInternalServer, "expected ErrInternalServer")
 }
+
+// ---- Test FetchAirportDataBatched ----
+func TestFetchAirportDataBatched_SplitsRejectedBatches(t *testing.T) {
+	var mu sync.Mutex
+	var requests []string
+
+	// Answers like the mock server, but rejects any batch holding BADREQUEST
+	// and fails any batch holding DOWN.
+	rt := roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		apt := r.URL.Query().Get("apt")
+		mu.Lock()
+		requests = append(requests, apt)
+		mu.Unlock()
+
+		status, body := http.StatusOK, map[string][]map[string]string{}
+		for _, code := range strings.Split(apt, ",") {
+			switch code {
+			case "BADREQUEST":
+				status = http.StatusBadRequest
+			case "DOWN":
+				return nil, errors.New("connection reset")
+			}
+			body[code] = DummyData[code]
+		}
+		raw, _ := json.Marshal(body)
+		return &http.Response{StatusCode: status, Body: io.NopCloser(strings.NewReader(string(raw))), Header: make(http.Header), Request: r}, nil
+	})
+
+	svc := &AviationService{
+		cfg:    &config.Config{AviationURL: "http://mock", AviationBatchSize: 4, AviationConcurrency: 2},
+		client: httpclient.NewClient(logger.NewLogger(logger.INFO_DEBUG_LEVEL), httpclient.Options{Transport: rt}),
+		logger: logger.NewLogger(logger.INFO_DEBUG_LEVEL),
+	}
+
+	fetched, failed := svc.FetchAirportDataBatched(context.Background(), []string{"KJFK", "WIII", "BADREQUEST", "KATL", "DOWN", "WAHI"})
+
+	require.Len(t, fetched, 3)
+	assert.Equal(t, "KJFK", *fetched["KJFK"].ICAOID)
+	assert.Equal(t, "KATL", *fetched["KATL"].ICAOID)
+	assert.Equal(t, airport_dto.AirportRequestDto{}, fetched["WIII"])
+
+	require.Len(t, failed, 3)
+	assert.ErrorIs(t, failed["BADREQUEST"], util.ErrBadRequest)
+	// A batch that fails for another reason fails as a whole.
+	assert.ErrorIs(t, failed["DOWN"], util.ErrInternalServer)
+	assert.ErrorIs(t, failed["WAHI"], util.ErrInternalServer)
+
+	// The first batch is split down to the rejected code.
+	assert.ElementsMatch(t, []string{
+		"KJFK,WIII,BADREQUEST,KATL",
+		"KJFK,WIII",
+		"BADREQUEST,KATL",
+		"BADREQUEST",
+		"KATL",
+		"DOWN,WAHI",
+	}, requests)
+}
+
+func TestFetchAirportDataBatched_DoesNotSplitProviderFailures(t *testing.T) {
+	var mu sync.Mutex
+	var requests []string
+
+	rt := roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		mu.Lock()
+		requests = append(requests, r.URL.Query().Get("apt"))
+		mu.Unlock()
+		return &http.Response{StatusCode: http.StatusServiceUnavailable, Body: io.NopCloser(strings.NewReader("")), Header: make(http.Header), Request: r}, nil
+	})
+	svc := &AviationService{
+		cfg:    &config.Config{AviationURL: "http://mock", AviationBatchSize: 4, AviationConcurrency: 1},
+		client: httpclient.NewClient(logger.NewLogger(logger.INFO_DEBUG_LEVEL), httpclient.Options{Transport: rt}),
+		logger: logger.NewLogger(logger.INFO_DEBUG_LEVEL),
+	}
+
+	fetched, failed := svc.FetchAirportDataBatched(context.Background(), []string{"KJFK", "WIII", "KATL", "WAHI"})
+
+	assert.Empty(t, fetched)
+	require.Len(t, failed, 4)
+	for code, err := range failed {
+		assert.ErrorIs(t, err, util.ErrBadGateway, code)
+	}
+	assert.Equal(t, []string{"KJFK,WIII,KATL,WAHI"}, requests)
+}
+
+func TestFetchAirportDataBatched_CanceledContext(t *testing.T) {
+	rt := roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		return nil, r.Context().Err()
+	})
+	svc := &AviationService{
+		cfg:    &config.Config{AviationURL: "http://mock", AviationBatchSize: 1, AviationConcurrency: 1},
+		client: httpclient.NewClient(logger.NewLogger(logger.INFO_DEBUG_LEVEL), httpclient.Options{Transport: rt}),
+		logger: logger.NewLogger(logger.INFO_DEBUG_LEVEL),
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	fetched, failed := svc.FetchAirportDataBatched(ctx, []string{"KJFK", "KATL", "WIII"})
+	assert.Empty(t, fetched)
+	assert.Len(t, failed, 3)
+}
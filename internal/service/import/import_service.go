@@ -0,0 +1,19 @@
+package service_import
+
+import "context"
+
+// IImportService loads airport data published as files into the database,
+// giving a complete dataset without the live Aviation API.
+type IImportService interface {
+	ImportNASR(ctx context.Context, path string) (ImportSummary, error)
+	ImportOurAirports(ctx context.Context, dir string) (ImportSummary, error)
+}
+
+// ImportSummary counts what an import did with the facilities it read.
+type ImportSummary struct {
+	Facilities int
+	Inserted   int
+	Updated    int
+	Failed     int
+	Runways    int
+}
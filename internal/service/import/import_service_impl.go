@@ -0,0 +1,309 @@
+package service_import
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"flight-api/config"
+	"flight-api/internal/cache"
+	airport_dto "flight-api/internal/dto/airport"
+	aviation_dto "flight-api/internal/dto/aviation"
+	ourairports_dto "flight-api/internal/dto/ourairports"
+	"flight-api/internal/enum"
+	"flight-api/internal/model"
+	repo_airport "flight-api/internal/repository/airport"
+	repo_runway "flight-api/internal/repository/runway"
+	"flight-api/pkg/logger"
+	"flight-api/pkg/nasr"
+	"flight-api/pkg/ourairports"
+	"flight-api/util"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// importProgressEvery is how many facilities go by between progress logs.
+const importProgressEvery = 1000
+
+type ImportService struct {
+	logger            *logger.Logger
+	db                *sql.DB
+	airportRepository repo_airport.IAirportRepository
+	runwayRepository  repo_runway.IRunwayRepository
+	airportCache      *cache.AirportCache
+	priority          SourcePriority
+}
+
+func NewImportService(
+	logger *logger.Logger,
+	cfg *config.Config,
+	db *sql.DB,
+	airportRepository repo_airport.IAirportRepository,
+	runwayRepository repo_runway.IRunwayRepository,
+	airportCache *cache.AirportCache,
+) (IImportService, error) {
+	priority, err := ParseSourcePriority(cfg.ImportSourcePriority)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ImportService{
+		logger:            logger,
+		db:                db,
+		airportRepository: airportRepository,
+		runwayRepository:  runwayRepository,
+		airportCache:      airportCache,
+		priority:          priority,
+	}, nil
+}
+
+// facility is one airport read from an import source, ready to store.
+// Runways is nil when the source files carry no runway data.
+type facility struct {
+	airport model.Airport
+	runways []model.Runway
+	// logged with a failure to store the facility
+	fields logrus.Fields
+}
+
+// ImportNASR imports every facility in the NASR APT files at path, keyed by
+// ICAO code, with its runways when the files carry runway data.
+func (s *ImportService) ImportNASR(ctx context.Context, path string) (ImportSummary, error) {
+	airports, err := nasr.Load(path)
+	if err != nil {
+		s.logger.Errorf("[ImportNASR] Failed to read NASR files at %s: %v", path, err)
+		return ImportSummary{}, util.NewAppError(util.ErrBadRequest, "Failed to read NASR files", err)
+	}
+	s.logger.Infof("[ImportNASR] Importing %d facilities from %s", len(airports), path)
+
+	facilities := make([]facility, 0, len(airports))
+	for _, source := range airports {
+		airport := airport_dto.AirportRequestToAirport(aviation_dto.ToAirportRequestDto(aviation_dto.FromNASRAirport(source)))
+		omitBlank(&airport)
+
+		var runways []model.Runway
+		if source.Runways != nil {
+			runways = toRunways(source.Runways)
+		}
+		facilities = append(facilities, facility{
+			airport: airport,
+			runways: runways,
+			fields:  logrus.Fields{"site_number": source.SiteNumber, "icao_code": util.DerefPtr(airport.ICAOID)},
+		})
+	}
+
+	return s.importFacilities(ctx, enum.AIRPORT_SOURCE_NASR, facilities)
+}
+
+// ImportOurAirports imports every airport in the OurAirports files in dir,
+// keyed by ICAO code, with its open runways when runways.csv is there, and
+// merges them with the airports other sources stored.
+func (s *ImportService) ImportOurAirports(ctx context.Context, dir string) (ImportSummary, error) {
+	airports, err := ourairports.Load(dir)
+	if err != nil {
+		s.logger.Errorf("[ImportOurAirports] Failed to read OurAirports files in %s: %v", dir, err)
+		return ImportSummary{}, util.NewAppError(util.ErrBadRequest, "Failed to read OurAirports files", err)
+	}
+	s.logger.Infof("[ImportOurAirports] Importing %d airports from %s", len(airports), dir)
+
+	facilities := make([]facility, 0, len(airports))
+	for _, source := range airports {
+		var runways []model.Runway
+		if source.Runways != nil {
+			runways = ourAirportsRunways(source.Runways)
+		}
+		facilities = append(facilities, facility{
+			airport: airport_dto.AirportRequestToAirport(ourairports_dto.ToAirportRequestDto(source)),
+			runways: runways,
+			fields:  logrus.Fields{"ident": source.Ident, "icao_code": ourairports_dto.ICAOIdentifier(source)},
+		})
+	}
+
+	return s.importFacilities(ctx, enum.AIRPORT_SOURCE_OURAIRPORTS, facilities)
+}
+
+// importFacilities stores the facilities read from source, each in its own
+// transaction, so one that fails is counted and logged without holding back
+// the others. Fields the source leaves nil keep the stored value, and the
+// source priority decides which fields it overwrites.
+func (s *ImportService) importFacilities(ctx context.Context, source enum.AirportSourceEnum, facilities []facility) (ImportSummary, error) {
+	summary := ImportSummary{Facilities: len(facilities)}
+	for i, f := range facilities {
+		if err := ctx.Err(); err != nil {
+			return summary, err
+		}
+
+		stored, inserted, runways, err := s.store(ctx, source, f)
+		if err != nil {
+			summary.Failed++
+			fields := logrus.Fields{"source": source.String(), "error": err}
+			for k, v := range f.fields {
+				fields[k] = v
+			}
+			s.logger.Errorw(fields, "[importFacilities] Failed to import facility")
+			continue
+		}
+		s.airportCache.Invalidate(ctx, stored)
+
+		if inserted {
+			summary.Inserted++
+		} else {
+			summary.Updated++
+		}
+		summary.Runways += runways
+
+		if (i+1)%importProgressEvery == 0 {
+			s.logger.Infof("[importFacilities] Imported %d of %d %s facilities", i+1, len(facilities), source)
+		}
+	}
+
+	return summary, nil
+}
+
+// store merges a facility over the stored airport with its ICAO code and
+// upserts it, recording source as the owner of the fields it wrote, and
+// replaces its runways when source wins them, in one transaction. It
+// reports the number of runways stored.
+func (s *ImportService) store(ctx context.Context, source enum.AirportSourceEnum, f facility) (_ model.Airport, _ bool, _ int, err error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return model.Airport{}, false, 0, err
+	}
+	defer util.CommitOrRollbackErr(tx, &err)
+
+	var stored *model.Airport
+	owners := map[string]string{}
+	existing, err := s.airportRepository.FindByICAOID(ctx, tx, util.DerefPtr(f.airport.ICAOID))
+	if err == nil {
+		stored = &existing
+		if owners, err = s.airportRepository.FindFieldSources(ctx, tx, *existing.ICAOID); err != nil {
+			return model.Airport{}, false, 0, err
+		}
+	} else if !errors.Is(err, util.ErrNotFound) {
+		return model.Airport{}, false, 0, err
+	}
+
+	airport, claimed := MergeBySource(f.airport, stored, owners, source, s.priority)
+
+	replaceRunways := false
+	if f.runways != nil {
+		if replaceRunways, err = s.winsRunways(ctx, tx, source, stored, owners); err != nil {
+			return model.Airport{}, false, 0, err
+		}
+	}
+	if replaceRunways {
+		claimed[runwaysColumn] = source.String()
+	}
+
+	result, inserted, err := s.airportRepository.Upsert(ctx, tx, airport, claimed)
+	if err != nil {
+		return model.Airport{}, false, 0, err
+	}
+	if !replaceRunways {
+		return result, inserted, 0, nil
+	}
+	if err := s.runwayRepository.ReplaceByAirportID(ctx, tx, result.ID.String(), f.runways); err != nil {
+		return model.Airport{}, false, 0, err
+	}
+
+	return result, inserted, len(f.runways), nil
+}
+
+// winsRunways reports whether source may replace the runways of the stored
+// airport. Runways stored without a recorded owner rank as the Aviation
+// API's, and an airport without runways takes them from any source.
+func (s *ImportService) winsRunways(ctx context.Context, tx *sql.Tx, source enum.AirportSourceEnum, stored *model.Airport, owners map[string]string) (bool, error) {
+	if stored == nil {
+		return true, nil
+	}
+	if owner, ok := enum.ToAirportSource(owners[runwaysColumn]); ok {
+		return s.priority.wins(runwaysColumn, source, owner), nil
+	}
+	if s.priority.wins(runwaysColumn, source, enum.AIRPORT_SOURCE_AVIATION_API) {
+		return true, nil
+	}
+
+	runways, err := s.runwayRepository.FindByAirportID(ctx, tx, stored.ID.String())
+	if err != nil {
+		return false, err
+	}
+	return len(runways) == 0, nil
+}
+
+// omitBlank drops the text fields NASR leaves blank, so the upsert keeps
+// what is stored for them.
+func omitBlank(airport *model.Airport) {
+	fields := []**string{
+		&airport.SiteNumber, &airport.ICAOID, &airport.FAAID, &airport.IATAID, &airport.Name,
+		&airport.Country, &airport.State, &airport.StateFull, &airport.County, &airport.City,
+		&airport.Manager, &airport.ManagerPhone, &airport.Latitude, &airport.LatitudeSec,
+		&airport.Longitude, &airport.LongitudeSec, &airport.MagVariation, &airport.Unicom, &airport.CTAF,
+	}
+	for _, field := range fields {
+		if *field != nil && strings.TrimSpace(**field) == "" {
+			*field = nil
+		}
+	}
+}
+
+// toRunways turns runway strips into the runway ends stored for an airport.
+// An end listed twice keeps its first strip.
+func toRunways(strips []nasr.Runway) []model.Runway {
+	runways := []model.Runway{}
+	seen := map[string]bool{}
+	for _, strip := range strips {
+		for _, end := range strip.Ends {
+			if end.Ident == "" || seen[end.Ident] {
+				continue
+			}
+			seen[end.Ident] = true
+
+			runway := model.Runway{
+				Ident:       util.Ptr(end.Ident),
+				TrueHeading: util.ParseInt64Ptr(end.TrueHeading),
+				LengthFt:    util.ParseInt64Ptr(strip.LengthFt),
+				WidthFt:     util.ParseInt64Ptr(strip.WidthFt),
+			}
+			if strip.Surface != "" {
+				runway.Surface = util.Ptr(strip.Surface)
+			}
+			runways = append(runways, runway)
+		}
+	}
+	return runways
+}
+
+// ourAirportsRunways turns the open OurAirports runway strips into the
+// runway ends stored for an airport. An end listed twice keeps its first
+// strip.
+func ourAirportsRunways(strips []ourairports.Runway) []model.Runway {
+	runways := []model.Runway{}
+	seen := map[string]bool{}
+	for _, strip := range strips {
+		if strip.Closed {
+			continue
+		}
+		for _, end := range strip.Ends {
+			if seen[end.Ident] {
+				continue
+			}
+			seen[end.Ident] = true
+
+			runway := model.Runway{
+				Ident:    util.Ptr(end.Ident),
+				LengthFt: util.ParseInt64Ptr(strip.LengthFt),
+				WidthFt:  util.ParseInt64Ptr(strip.WidthFt),
+			}
+			if heading, err := strconv.ParseFloat(end.HeadingDegT, 64); err == nil {
+				runway.TrueHeading = util.Ptr(int64(math.Round(heading)))
+			}
+			if strip.Surface != "" {
+				runway.Surface = util.Ptr(strip.Surface)
+			}
+			runways = append(runways, runway)
+		}
+	}
+	return runways
+}
@@ -0,0 +1,29 @@
+package service_import
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type ImportServiceMock struct {
+	Mock mock.Mock
+}
+
+func (m *ImportServiceMock) ImportNASR(ctx context.Context, path string) (ImportSummary, error) {
+	args := m.Mock.Called(ctx, path)
+	var out ImportSummary
+	if v, ok := args.Get(0).(ImportSummary); ok {
+		out = v
+	}
+	return out, args.Error(1)
+}
+
+func (m *ImportServiceMock) ImportOurAirports(ctx context.Context, dir string) (ImportSummary, error) {
+	args := m.Mock.Called(ctx, dir)
+	var out ImportSummary
+	if v, ok := args.Get(0).(ImportSummary); ok {
+		out = v
+	}
+	return out, args.Error(1)
+}
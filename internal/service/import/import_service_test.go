@@ -0,0 +1,287 @@
+package service_import
+
+import (
+	"context"
+	"errors"
+	"flight-api/config"
+	"flight-api/internal/enum"
+	"flight-api/internal/model"
+	repo_airport "flight-api/internal/repository/airport"
+	repo_runway "flight-api/internal/repository/runway"
+	"flight-api/pkg/logger"
+	"flight-api/util"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var anyTx = mock.AnythingOfType("util.MockTx")
+
+const aptBase = `"EFF_DATE","SITE_NO","SITE_TYPE_CODE","STATE_CODE","ARPT_ID","ARPT_NAME","OWNERSHIP_TYPE_CODE","FACILITY_USE_CODE","ELEV","ARPT_STATUS","TWR_TYPE_CODE","ICAO_ID"
+"2025/10/02","15793.*A","A","NY","JFK","JOHN F KENNEDY INTL","PU","PU","13.4","O","ATCT","KJFK"
+"2025/10/02","16517.1*H","H","NY","6N5","EAST 34TH STREET","PU","PR","10","O","NON-ATCT",""
+`
+
+const aptRunways = `"SITE_NO","RWY_ID","RWY_LEN","RWY_WIDTH","SURFACE_TYPE_CODE"
+"15793.*A","04L/22R","12079","200","ASPH-CONC"
+"16517.1*H","H1","40","40",""
+`
+
+const aptRunwayEnds = `"SITE_NO","RWY_ID","RWY_END_ID","TRUE_ALIGNMENT"
+"15793.*A","04L/22R","04L","31"
+"15793.*A","04L/22R","22R","211"
+`
+
+func writeNASR(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644))
+	}
+	return dir
+}
+
+type importDeps struct {
+	dbmock  sqlmock.Sqlmock
+	airport *repo_airport.AirportRepositoryMock
+	runway  *repo_runway.RunwayRepositoryMock
+	svc     IImportService
+}
+
+func newImportDeps(t *testing.T) *importDeps {
+	t.Helper()
+	db, dbmock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	d := &importDeps{
+		dbmock:  dbmock,
+		airport: &repo_airport.AirportRepositoryMock{Mock: mock.Mock{}},
+		runway:  &repo_runway.RunwayRepositoryMock{Mock: mock.Mock{}},
+	}
+	cfg := &config.Config{ImportSourcePriority: "nasr,aviationapi,ourairports;iata_id=ourairports,nasr,aviationapi"}
+	d.svc, err = NewImportService(logger.NewLogger(logger.INFO_DEBUG_LEVEL), cfg, db, d.airport, d.runway, nil)
+	require.NoError(t, err)
+	return d
+}
+
+func byICAO(code string) interface{} {
+	return mock.MatchedBy(func(a model.Airport) bool { return util.DerefPtr(a.ICAOID) == code })
+}
+
+func TestImportService_ImportNASR(t *testing.T) {
+	d := newImportDeps(t)
+	dir := writeNASR(t, map[string]string{
+		"APT_BASE.csv":    aptBase,
+		"APT_RWY.csv":     aptRunways,
+		"APT_RWY_END.csv": aptRunwayEnds,
+	})
+	jfkID := uuid.New()
+
+	d.dbmock.ExpectBegin()
+	d.airport.Mock.On("FindByICAOID", mock.Anything, anyTx, "KJFK").Return(nil, util.ErrNotFound).Once()
+	d.airport.Mock.On("Upsert", mock.Anything, anyTx, mock.MatchedBy(func(a model.Airport) bool {
+		return *a.ICAOID == "KJFK" &&
+			*a.SiteNumber == "15793.*A" &&
+			*a.Type == "airport" &&
+			*a.Elevation == 13 &&
+			*a.ControlTower &&
+			// Blank in the CSV, so the stored values stay
+			a.Manager == nil && a.CTAF == nil
+	}), mock.MatchedBy(func(sources map[string]string) bool {
+		_, manager := sources["manager"]
+		return sources["name"] == "nasr" && sources["runways"] == "nasr" && !manager
+	})).Return(model.Airport{ID: &jfkID, ICAOID: util.Ptr("KJFK")}, true, nil).Once()
+	d.runway.Mock.On("ReplaceByAirportID", mock.Anything, anyTx, jfkID.String(), []model.Runway{
+		{Ident: util.Ptr("04L"), TrueHeading: util.Ptr(int64(31)), LengthFt: util.Ptr(int64(12079)), WidthFt: util.Ptr(int64(200)), Surface: util.Ptr("ASPH-CONC")},
+		{Ident: util.Ptr("22R"), TrueHeading: util.Ptr(int64(211)), LengthFt: util.Ptr(int64(12079)), WidthFt: util.Ptr(int64(200)), Surface: util.Ptr("ASPH-CONC")},
+	}).Return(nil).Once()
+	d.dbmock.ExpectCommit()
+
+	// The heliport has no ICAO code and is keyed by its FAA identifier; its
+	// failure does not undo the airport before it
+	d.dbmock.ExpectBegin()
+	d.airport.Mock.On("FindByICAOID", mock.Anything, anyTx, "6N5").Return(nil, util.ErrNotFound).Once()
+	d.airport.Mock.On("Upsert", mock.Anything, anyTx, byICAO("6N5"), mock.Anything).
+		Return(nil, false, errors.New("duplicate site_number")).Once()
+	d.dbmock.ExpectRollback()
+
+	summary, err := d.svc.ImportNASR(context.Background(), dir)
+	require.NoError(t, err)
+	assert.Equal(t, ImportSummary{Facilities: 2, Inserted: 1, Failed: 1, Runways: 2}, summary)
+
+	d.airport.Mock.AssertExpectations(t)
+	d.runway.Mock.AssertExpectations(t)
+	require.NoError(t, d.dbmock.ExpectationsWereMet())
+}
+
+func TestImportService_ImportNASR_KeepsRunwaysWithoutRunwayFiles(t *testing.T) {
+	d := newImportDeps(t)
+	dir := writeNASR(t, map[string]string{"APT_BASE.csv": aptBase})
+
+	for _, code := range []string{"KJFK", "6N5"} {
+		stored := model.Airport{ID: util.Ptr(uuid.New()), ICAOID: util.Ptr(code)}
+		d.dbmock.ExpectBegin()
+		d.airport.Mock.On("FindByICAOID", mock.Anything, anyTx, code).Return(stored, nil).Once()
+		d.airport.Mock.On("FindFieldSources", mock.Anything, anyTx, code).Return(map[string]string{}, nil).Once()
+		d.airport.Mock.On("Upsert", mock.Anything, anyTx, byICAO(code), mock.MatchedBy(func(sources map[string]string) bool {
+			_, runways := sources["runways"]
+			return sources["name"] == "nasr" && !runways
+		})).Return(stored, false, nil).Once()
+		d.dbmock.ExpectCommit()
+	}
+
+	summary, err := d.svc.ImportNASR(context.Background(), dir)
+	require.NoError(t, err)
+	assert.Equal(t, ImportSummary{Facilities: 2, Updated: 2}, summary)
+
+	d.runway.Mock.AssertNotCalled(t, "ReplaceByAirportID", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	require.NoError(t, d.dbmock.ExpectationsWereMet())
+}
+
+func TestImportService_ImportNASR_MissingFiles(t *testing.T) {
+	d := newImportDeps(t)
+
+	_, err := d.svc.ImportNASR(context.Background(), t.TempDir())
+
+	var appErr *util.AppError
+	require.ErrorAs(t, err, &appErr)
+	assert.ErrorIs(t, err, util.ErrBadRequest)
+}
+
+const ourAirports = `"id","ident","type","name","latitude_deg","longitude_deg","elevation_ft","continent","iso_country","iso_region","municipality","scheduled_service","icao_code","iata_code","gps_code","local_code"
+3622,"KJFK","large_airport","John F Kennedy International Airport",40.639447,-73.779317,13,"NA","US","US-NY","New York","yes","KJFK","JFK","KJFK","JFK"
+2434,"EGLL","large_airport","London Heathrow Airport",51.4706,-0.461941,83,"EU","GB","GB-ENG","London","yes","EGLL","LHR","EGLL",""
+6523,"00A","heliport","Total RF Heliport",40.070985,-74.933689,11,"NA","US","US-PA","Bensalem","no","","","K00A","00A"
+`
+
+const ourAirportsRunwayRows = `"id","airport_ref","airport_ident","length_ft","width_ft","surface","lighted","closed","le_ident","le_heading_degT","he_ident","he_heading_degT"
+240898,3622,"KJFK",12079,200,"ASP",1,0,"04L",31,"22R",211
+232317,2434,"EGLL",12799,164,"ASP",1,0,"09L",89.6,"27R",269.6
+999999,2434,"EGLL",1000,50,"GRS",0,1,"05",,"23",
+45200,6523,"00A",80,80,"ASPH-G",1,0,"H1",,,
+`
+
+const ourAirportsCountries = `"id","code","name","continent"
+302755,"GB","United Kingdom","EU"
+302618,"US","United States","NA"
+`
+
+func TestImportService_ImportOurAirports(t *testing.T) {
+	d := newImportDeps(t)
+	dir := writeNASR(t, map[string]string{
+		"airports.csv":  ourAirports,
+		"runways.csv":   ourAirportsRunwayRows,
+		"countries.csv": ourAirportsCountries,
+	})
+
+	// KJFK came from NASR, which outranks OurAirports except for the IATA
+	// code; the city has no recorded source and ranks as the Aviation API
+	jfk := model.Airport{
+		ID:        util.Ptr(uuid.New()),
+		ICAOID:    util.Ptr("KJFK"),
+		FAAID:     util.Ptr("JFK"),
+		IATAID:    util.Ptr("JFK"),
+		Name:      util.Ptr("JOHN F KENNEDY INTL"),
+		Type:      util.Ptr("airport"),
+		Status:    util.Ptr(true),
+		State:     util.Ptr("NY"),
+		City:      util.Ptr("NEW YORK"),
+		Latitude:  util.Ptr("40-38-23.7400N"),
+		Elevation: util.Ptr(int64(13)),
+	}
+	nasrOwned := map[string]string{"runways": "nasr"}
+	for _, column := range []string{"faa_id", "iata_id", "name", "type", "status", "state", "latitude", "elevation"} {
+		nasrOwned[column] = "nasr"
+	}
+	d.dbmock.ExpectBegin()
+	d.airport.Mock.On("FindByICAOID", mock.Anything, anyTx, "KJFK").Return(jfk, nil).Once()
+	d.airport.Mock.On("FindFieldSources", mock.Anything, anyTx, "KJFK").Return(nasrOwned, nil).Once()
+	d.airport.Mock.On("Upsert", mock.Anything, anyTx, model.Airport{
+		ICAOID:       util.Ptr("KJFK"),
+		IATAID:       util.Ptr("JFK"),
+		Country:      util.Ptr("United States"),
+		LatitudeSec:  util.Ptr("146302.0092N"),
+		Longitude:    util.Ptr("073-46-45.5412W"),
+		LongitudeSec: util.Ptr("265605.5412W"),
+	}, map[string]string{
+		"iata_id":       "ourairports",
+		"country":       "ourairports",
+		"latitude_sec":  "ourairports",
+		"longitude":     "ourairports",
+		"longitude_sec": "ourairports",
+	}).Return(jfk, false, nil).Once()
+	d.dbmock.ExpectCommit()
+
+	// A new airport takes every field and its open runways
+	heathrowID := uuid.New()
+	d.dbmock.ExpectBegin()
+	d.airport.Mock.On("FindByICAOID", mock.Anything, anyTx, "EGLL").Return(nil, util.ErrNotFound).Once()
+	d.airport.Mock.On("Upsert", mock.Anything, anyTx, mock.MatchedBy(func(a model.Airport) bool {
+		return *a.ICAOID == "EGLL" && *a.IATAID == "LHR" && *a.Country == "United Kingdom" &&
+			*a.State == "ENG" && *a.Type == "airport" && *a.Elevation == 83 && a.FAAID == nil
+	}), mock.MatchedBy(func(sources map[string]string) bool {
+		return sources["name"] == "ourairports" && sources["runways"] == "ourairports"
+	})).Return(model.Airport{ID: &heathrowID, ICAOID: util.Ptr("EGLL")}, true, nil).Once()
+	d.runway.Mock.On("ReplaceByAirportID", mock.Anything, anyTx, heathrowID.String(), []model.Runway{
+		{Ident: util.Ptr("09L"), TrueHeading: util.Ptr(int64(90)), LengthFt: util.Ptr(int64(12799)), WidthFt: util.Ptr(int64(164)), Surface: util.Ptr("ASP")},
+		{Ident: util.Ptr("27R"), TrueHeading: util.Ptr(int64(270)), LengthFt: util.Ptr(int64(12799)), WidthFt: util.Ptr(int64(164)), Surface: util.Ptr("ASP")},
+	}).Return(nil).Once()
+	d.dbmock.ExpectCommit()
+
+	// The heliport is keyed by its FAA identifier, as NASR stores it, and
+	// takes runways because it has none
+	heliport := model.Airport{ID: util.Ptr(uuid.New()), ICAOID: util.Ptr("00A")}
+	d.dbmock.ExpectBegin()
+	d.airport.Mock.On("FindByICAOID", mock.Anything, anyTx, "00A").Return(heliport, nil).Once()
+	d.airport.Mock.On("FindFieldSources", mock.Anything, anyTx, "00A").Return(map[string]string{}, nil).Once()
+	d.runway.Mock.On("FindByAirportID", mock.Anything, anyTx, heliport.ID.String()).Return([]model.Runway{}, nil).Once()
+	d.airport.Mock.On("Upsert", mock.Anything, anyTx, byICAO("00A"), mock.Anything).Return(heliport, false, nil).Once()
+	d.runway.Mock.On("ReplaceByAirportID", mock.Anything, anyTx, heliport.ID.String(), mock.Anything).Return(nil).Once()
+	d.dbmock.ExpectCommit()
+
+	summary, err := d.svc.ImportOurAirports(context.Background(), dir)
+	require.NoError(t, err)
+	assert.Equal(t, ImportSummary{Facilities: 3, Inserted: 1, Updated: 2, Runways: 3}, summary)
+
+	d.airport.Mock.AssertExpectations(t)
+	d.runway.Mock.AssertExpectations(t)
+	require.NoError(t, d.dbmock.ExpectationsWereMet())
+}
+
+func TestImportService_ImportOurAirports_MissingFiles(t *testing.T) {
+	d := newImportDeps(t)
+
+	_, err := d.svc.ImportOurAirports(context.Background(), t.TempDir())
+	assert.ErrorIs(t, err, util.ErrBadRequest)
+}
+
+func TestParseSourcePriority(t *testing.T) {
+	priority, err := ParseSourcePriority("nasr, aviationapi ; country=ourairports,nasr")
+	require.NoError(t, err)
+	assert.True(t, priority.wins("name", enum.AIRPORT_SOURCE_NASR, enum.AIRPORT_SOURCE_AVIATION_API))
+	assert.False(t, priority.wins("name", enum.AIRPORT_SOURCE_AVIATION_API, enum.AIRPORT_SOURCE_NASR))
+	assert.True(t, priority.wins("name", enum.AIRPORT_SOURCE_AVIATION_API, enum.AIRPORT_SOURCE_AVIATION_API))
+	// Left out of the default order, so it ranks last
+	assert.False(t, priority.wins("name", enum.AIRPORT_SOURCE_OURAIRPORTS, enum.AIRPORT_SOURCE_AVIATION_API))
+	assert.True(t, priority.wins("country", enum.AIRPORT_SOURCE_OURAIRPORTS, enum.AIRPORT_SOURCE_NASR))
+
+	for _, spec := range []string{
+		"",
+		"nasr,faa",
+		"nasr;country",
+		"nasr;ident=ourairports",
+		"nasr;sync_status=ourairports",
+		"nasr;country=ourairports;country=nasr",
+		"country=ourairports;nasr",
+	} {
+		_, err := ParseSourcePriority(spec)
+		assert.Error(t, err, spec)
+	}
+}
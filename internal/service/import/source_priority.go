@@ -0,0 +1,150 @@
+package service_import
+
+import (
+	"flight-api/internal/enum"
+	"flight-api/internal/model"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// runwaysColumn is the key the source of an airport's runways is recorded
+// and prioritised under, next to its columns.
+const runwaysColumn = "runways"
+
+// unsourcedColumns are the airport columns no import source owns: keys and
+// sync bookkeeping.
+var unsourcedColumns = map[string]bool{
+	"id":            true,
+	"icao_id":       true,
+	"sync_status":   true,
+	"sync_message":  true,
+	"sync_attempts": true,
+	"created_at":    true,
+	"updated_at":    true,
+}
+
+// SourcePriority ranks the airport data sources, the import files and the
+// Aviation API sync, for every field or for one column: the source listed
+// first wins. A source left out ranks last. The zero value lets every
+// source overwrite every field.
+type SourcePriority struct {
+	order   []enum.AirportSourceEnum
+	columns map[string][]enum.AirportSourceEnum
+}
+
+// ParseSourcePriority reads IMPORT_SOURCE_PRIORITY: the default order as a
+// comma separated list of sources, followed by column=order overrides, all
+// separated by semicolons, e.g. "nasr,aviationapi,ourairports;iata_id=ourairports,nasr".
+func ParseSourcePriority(spec string) (SourcePriority, error) {
+	priority := SourcePriority{columns: map[string][]enum.AirportSourceEnum{}}
+	for i, entry := range strings.Split(spec, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		column, list, override := strings.Cut(entry, "=")
+		if !override {
+			if i > 0 || priority.order != nil {
+				return SourcePriority{}, fmt.Errorf("source priority %q: expected column=sources after the default order", entry)
+			}
+			list = entry
+		}
+
+		column = strings.TrimSpace(column)
+		if override && !isSourcedColumn(column) {
+			return SourcePriority{}, fmt.Errorf("source priority %q: unknown column %q", entry, column)
+		}
+		if override && priority.columns[column] != nil {
+			return SourcePriority{}, fmt.Errorf("source priority for column %q configured twice", column)
+		}
+
+		order := []enum.AirportSourceEnum{}
+		for _, name := range strings.Split(list, ",") {
+			source, ok := enum.ToAirportSource(strings.TrimSpace(name))
+			if !ok {
+				return SourcePriority{}, fmt.Errorf("source priority %q: unknown source %q", entry, strings.TrimSpace(name))
+			}
+			order = append(order, source)
+		}
+
+		if override {
+			priority.columns[column] = order
+		} else {
+			priority.order = order
+		}
+	}
+	if priority.order == nil {
+		return SourcePriority{}, fmt.Errorf("source priority %q: missing the default order", spec)
+	}
+	return priority, nil
+}
+
+// rank is the position of source in the order for column; lower wins.
+func (p SourcePriority) rank(column string, source enum.AirportSourceEnum) int {
+	order, ok := p.columns[column]
+	if !ok {
+		order = p.order
+	}
+	for i, s := range order {
+		if s == source {
+			return i
+		}
+	}
+	return len(order)
+}
+
+// wins reports whether challenger may overwrite a value of column written
+// by owner. A source always overwrites its own values.
+func (p SourcePriority) wins(column string, challenger, owner enum.AirportSourceEnum) bool {
+	return p.rank(column, challenger) <= p.rank(column, owner)
+}
+
+func isSourcedColumn(column string) bool {
+	if column == runwaysColumn {
+		return true
+	}
+	t := reflect.TypeOf(model.Airport{})
+	for i := 0; i < t.NumField(); i++ {
+		if t.Field(i).Tag.Get("db") == column {
+			return !unsourcedColumns[column]
+		}
+	}
+	return false
+}
+
+// MergeBySource keeps the fields of incoming that source may write over
+// the stored airport, and clears the others so the upsert keeps their
+// stored value. A field with no stored value is always written. A stored
+// value without a recorded owner came from the Aviation API sync or the
+// airport API, and ranks as the Aviation API. It returns the fields written
+// with source as their owner.
+func MergeBySource(incoming model.Airport, stored *model.Airport, owners map[string]string, source enum.AirportSourceEnum, priority SourcePriority) (model.Airport, map[string]string) {
+	merged := incoming
+	claimed := map[string]string{}
+
+	out := reflect.ValueOf(&merged).Elem()
+	t := out.Type()
+	for i := 0; i < t.NumField(); i++ {
+		column := t.Field(i).Tag.Get("db")
+		field := out.Field(i)
+		if column == "" || unsourcedColumns[column] || field.Kind() != reflect.Ptr || field.IsNil() {
+			continue
+		}
+
+		if stored != nil && !reflect.ValueOf(*stored).Field(i).IsNil() {
+			owner := enum.AIRPORT_SOURCE_AVIATION_API
+			if recorded, ok := enum.ToAirportSource(owners[column]); ok {
+				owner = recorded
+			}
+			if !priority.wins(column, source, owner) {
+				field.Set(reflect.Zero(field.Type()))
+				continue
+			}
+		}
+		claimed[column] = source.String()
+	}
+
+	return merged, claimed
+}
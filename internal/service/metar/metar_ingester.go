@@ -0,0 +1,70 @@
+package service_metar
+
+import (
+	"context"
+	"flight-api/config"
+	"flight-api/pkg/logger"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const defaultPollInterval = 5 * time.Minute
+
+// MetarIngester polls the configured sources every METAR_POLL_INTERVAL and
+// feeds what they return to the METAR service.
+type MetarIngester struct {
+	logger  *logger.Logger
+	cfg     *config.Config
+	service IMetarService
+	sources []IMetarSource
+}
+
+func NewMetarIngester(logger *logger.Logger, cfg *config.Config, service IMetarService, sources ...IMetarSource) *MetarIngester {
+	return &MetarIngester{
+		logger:  logger,
+		cfg:     cfg,
+		service: service,
+		sources: sources,
+	}
+}
+
+// Run polls until ctx is done, starting immediately. It returns at once when
+// no source is configured.
+func (i *MetarIngester) Run(ctx context.Context) {
+	if len(i.sources) == 0 {
+		i.logger.Info("[Run] No METAR source configured, ingestion disabled")
+		return
+	}
+
+	interval := i.cfg.MetarPollInterval
+	if interval <= 0 {
+		interval = defaultPollInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		i.RunOnce(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce fetches from every source once. A failing source is logged and
+// does not stop the others.
+func (i *MetarIngester) RunOnce(ctx context.Context) {
+	for _, source := range i.sources {
+		batches, err := source.Fetch(ctx)
+		if err != nil {
+			i.logger.Warnw(logrus.Fields{"source": source.Name(), "error": err}, "[RunOnce] Failed to fetch METAR")
+		}
+		for _, batch := range batches {
+			i.service.Ingest(ctx, batch.Source, batch.Text)
+		}
+	}
+}
@@ -0,0 +1,13 @@
+package service_metar
+
+import (
+	"context"
+	weather_dto "flight-api/internal/dto/weather"
+	"flight-api/pkg/metar"
+)
+
+type IMetarService interface {
+	Decode(ctx context.Context, raw string) (*weather_dto.MetarDecodeDto, error)
+	Ingest(ctx context.Context, source string, text string) *weather_dto.MetarIngestDto
+	Latest(ctx context.Context, station string) (*metar.Metar, error)
+}
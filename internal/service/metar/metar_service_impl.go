@@ -0,0 +1,189 @@
+package service_metar
+
+import (
+	"context"
+	"errors"
+	"flight-api/config"
+	weather_dto "flight-api/internal/dto/weather"
+	"flight-api/pkg/logger"
+	"flight-api/pkg/metar"
+	"flight-api/util"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const defaultMaxAge = 3 * time.Hour
+
+// noaaDateLineRe matches the timestamp line NOAA cycle files put before each
+// report, e.g. "2025/10/06 17:51".
+var noaaDateLineRe = regexp.MustCompile(`^\d{4}/\d{2}/\d{2} \d{2}:\d{2}$`)
+
+// MetarService decodes reports and keeps the newest METAR of every station
+// in memory. Observations are short-lived, so they are not persisted; after
+// a restart airports fall back to consumer weather until the next ingest.
+type MetarService struct {
+	logger *logger.Logger
+	cfg    *config.Config
+	now    func() time.Time
+
+	mu     sync.RWMutex
+	latest map[string]*metar.Metar
+}
+
+func NewMetarService(logger *logger.Logger, cfg *config.Config) IMetarService {
+	return &MetarService{
+		logger: logger,
+		cfg:    cfg,
+		now:    time.Now,
+		latest: make(map[string]*metar.Metar),
+	}
+}
+
+// Decode parses a single METAR, SPECI or TAF. Nothing is stored.
+func (s *MetarService) Decode(ctx context.Context, raw string) (*weather_dto.MetarDecodeDto, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return nil, util.NewAppError(util.ErrBadRequest, "'raw' must contain a METAR or TAF report", nil)
+	}
+
+	if isTAF(raw) {
+		taf, err := metar.ParseTAFAt(raw, s.now())
+		if err != nil {
+			return nil, decodeError(err)
+		}
+		return &weather_dto.MetarDecodeDto{Object: "taf", TAF: taf}, nil
+	}
+
+	m, err := metar.ParseAt(raw, s.now())
+	if err != nil {
+		return nil, decodeError(err)
+	}
+	return &weather_dto.MetarDecodeDto{Object: "metar", Metar: m}, nil
+}
+
+// Ingest decodes every METAR in text and stores the ones newer than what is
+// already held for their station. Reports are separated by newlines, or by
+// "=" terminators when the text uses them. Bad reports are rejected one by
+// one without failing the batch.
+func (s *MetarService) Ingest(ctx context.Context, source string, text string) *weather_dto.MetarIngestDto {
+	result := &weather_dto.MetarIngestDto{
+		Object:   "metar_ingest",
+		Source:   source,
+		Stations: []string{},
+		Rejected: []weather_dto.MetarRejectedLineDto{},
+	}
+
+	updated := make(map[string]bool)
+	for _, raw := range splitReports(text) {
+		if isTAF(raw) {
+			result.Rejected = append(result.Rejected, weather_dto.MetarRejectedLineDto{Raw: raw, Error: "TAF reports are not ingested"})
+			continue
+		}
+
+		m, err := metar.ParseAt(raw, s.now())
+		if err != nil {
+			result.Rejected = append(result.Rejected, weather_dto.MetarRejectedLineDto{Raw: raw, Error: err.Error()})
+			continue
+		}
+
+		result.Accepted++
+		if s.store(m) {
+			updated[m.Station] = true
+		}
+	}
+
+	for station := range updated {
+		result.Stations = append(result.Stations, station)
+	}
+	sort.Strings(result.Stations)
+
+	s.logger.Infow(logrus.Fields{
+		"source":   source,
+		"accepted": result.Accepted,
+		"rejected": len(result.Rejected),
+		"stations": len(result.Stations),
+	}, "[Ingest] METAR batch ingested")
+
+	return result
+}
+
+// Latest returns the newest METAR for station, or a not found error when
+// none was ingested within METAR_MAX_AGE.
+func (s *MetarService) Latest(ctx context.Context, station string) (*metar.Metar, error) {
+	station = strings.ToUpper(strings.TrimSpace(station))
+
+	s.mu.RLock()
+	m, ok := s.latest[station]
+	s.mu.RUnlock()
+
+	if !ok {
+		return nil, util.NewAppError(util.ErrNotFound, fmt.Sprintf("No METAR received for %s", station), nil)
+	}
+	if age := s.now().Sub(m.Time); age > s.maxAge() {
+		detail := fmt.Sprintf("Latest METAR for %s is older than %s", station, s.maxAge())
+		return nil, util.NewAppError(util.ErrNotFound, detail, nil)
+	}
+	return m, nil
+}
+
+// store keeps m unless a report at least as recent is already held. It
+// reports whether m was stored.
+func (s *MetarService) store(m *metar.Metar) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if current, ok := s.latest[m.Station]; ok && !m.Time.After(current.Time) {
+		return false
+	}
+	s.latest[m.Station] = m
+	return true
+}
+
+func (s *MetarService) maxAge() time.Duration {
+	if s.cfg.MetarMaxAge <= 0 {
+		return defaultMaxAge
+	}
+	return s.cfg.MetarMaxAge
+}
+
+// splitReports breaks text into single reports, dropping blank lines and
+// NOAA timestamp lines.
+func splitReports(text string) []string {
+	var lines []string
+	for _, line := range strings.Split(text, "\n") {
+		if !noaaDateLineRe.MatchString(strings.TrimSpace(line)) {
+			lines = append(lines, line)
+		}
+	}
+	text = strings.Join(lines, "\n")
+
+	separator := "\n"
+	if strings.Contains(text, "=") {
+		separator = "="
+	}
+
+	var reports []string
+	for _, part := range strings.Split(text, separator) {
+		if report := strings.Join(strings.Fields(part), " "); report != "" {
+			reports = append(reports, report)
+		}
+	}
+	return reports
+}
+
+func isTAF(raw string) bool {
+	return strings.HasPrefix(strings.ToUpper(strings.TrimSpace(raw)), "TAF")
+}
+
+func decodeError(err error) error {
+	if errors.Is(err, metar.ErrEmptyReport) || errors.Is(err, metar.ErrInvalidReport) || errors.Is(err, metar.ErrNilReport) {
+		return util.NewAppError(util.ErrBadRequest, "Could not decode report: "+err.Error(), err)
+	}
+	return util.NewAppError(util.ErrInternalServer, "Could not decode report", err)
+}
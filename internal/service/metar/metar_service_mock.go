@@ -0,0 +1,40 @@
+package service_metar
+
+import (
+	"context"
+	weather_dto "flight-api/internal/dto/weather"
+	"flight-api/pkg/metar"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MetarServiceMock struct {
+	Mock mock.Mock
+}
+
+func (m *MetarServiceMock) Decode(ctx context.Context, raw string) (*weather_dto.MetarDecodeDto, error) {
+	args := m.Mock.Called(ctx, raw)
+	var out *weather_dto.MetarDecodeDto
+	if v, ok := args.Get(0).(*weather_dto.MetarDecodeDto); ok {
+		out = v
+	}
+	return out, args.Error(1)
+}
+
+func (m *MetarServiceMock) Ingest(ctx context.Context, source string, text string) *weather_dto.MetarIngestDto {
+	args := m.Mock.Called(ctx, source, text)
+	var out *weather_dto.MetarIngestDto
+	if v, ok := args.Get(0).(*weather_dto.MetarIngestDto); ok {
+		out = v
+	}
+	return out
+}
+
+func (m *MetarServiceMock) Latest(ctx context.Context, station string) (*metar.Metar, error) {
+	args := m.Mock.Called(ctx, station)
+	var out *metar.Metar
+	if v, ok := args.Get(0).(*metar.Metar); ok {
+		out = v
+	}
+	return out, args.Error(1)
+}
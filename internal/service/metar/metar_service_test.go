@@ -0,0 +1,110 @@
+package service_metar
+
+import (
+	"context"
+	"flight-api/config"
+	"flight-api/pkg/logger"
+	"flight-api/util"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var log = logger.NewLogger(logger.DEBUG_LEVEL)
+
+func newTestService(now time.Time) *MetarService {
+	s := NewMetarService(log, &config.Config{MetarMaxAge: 2 * time.Hour}).(*MetarService)
+	s.now = func() time.Time { return now }
+	return s
+}
+
+var testNow = time.Date(2025, 10, 6, 18, 5, 0, 0, time.UTC)
+
+func TestDecode_Metar(t *testing.T) {
+	s := newTestService(testNow)
+
+	data, err := s.Decode(context.Background(), " KJFK 061751Z 18012G22KT 10SM -RA BKN015 17/11 A2992 ")
+	require.NoError(t, err)
+
+	assert.Equal(t, "metar", data.Object)
+	assert.Nil(t, data.TAF)
+	require.NotNil(t, data.Metar)
+	assert.Equal(t, "KJFK", data.Metar.Station)
+	assert.Equal(t, 22, *data.Metar.Wind.GustKt)
+}
+
+func TestDecode_TAF(t *testing.T) {
+	s := newTestService(testNow)
+
+	data, err := s.Decode(context.Background(), "TAF KJFK 061720Z 0618/0724 20012KT P6SM FEW050 FM062200 19010KT P6SM BKN060")
+	require.NoError(t, err)
+
+	assert.Equal(t, "taf", data.Object)
+	assert.Nil(t, data.Metar)
+	require.NotNil(t, data.TAF)
+	assert.Len(t, data.TAF.Groups, 2)
+}
+
+func TestDecode_InvalidReport(t *testing.T) {
+	s := newTestService(testNow)
+
+	for _, raw := range []string{"", "not a report", "KJFK 061751Z NIL", "TAF KJFK 061720Z"} {
+		_, err := s.Decode(context.Background(), raw)
+		assert.ErrorIs(t, err, util.ErrBadRequest, raw)
+	}
+}
+
+func TestIngest_KeepsNewestPerStation(t *testing.T) {
+	s := newTestService(testNow)
+	ctx := context.Background()
+
+	text := "2025/10/06 17:51\n" +
+		"KJFK 061751Z 18012KT 10SM FEW050 17/11 A2992\n" +
+		"\n" +
+		"2025/10/06 16:51\n" +
+		"KJFK 061651Z 18010KT 10SM FEW050 16/11 A2993\n" +
+		"KLGA 061751Z 19015KT 10SM BKN060 17/12 A2991\n" +
+		"TAF KJFK 061720Z 0618/0724 20012KT P6SM FEW050\n" +
+		"garbage\n"
+
+	result := s.Ingest(ctx, "push", text)
+
+	assert.Equal(t, "push", result.Source)
+	assert.Equal(t, 3, result.Accepted)
+	assert.Equal(t, []string{"KJFK", "KLGA"}, result.Stations)
+	require.Len(t, result.Rejected, 2)
+	assert.Equal(t, "garbage", result.Rejected[1].Raw)
+
+	m, err := s.Latest(ctx, "kjfk")
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2025, 10, 6, 17, 51, 0, 0, time.UTC), m.Time)
+}
+
+func TestIngest_SplitsOnTerminators(t *testing.T) {
+	s := newTestService(testNow)
+
+	text := "EGLL 061750Z 22015G25KT 9999 -RA FEW012\n BKN025 14/11 Q1008 NOSIG=\nLFPG 061800Z 24012KT CAVOK 16/08 Q1015 NOSIG="
+
+	result := s.Ingest(context.Background(), "push", text)
+
+	assert.Equal(t, 2, result.Accepted)
+	assert.Empty(t, result.Rejected)
+
+	m, err := s.Latest(context.Background(), "EGLL")
+	require.NoError(t, err)
+	assert.Len(t, m.Clouds, 2)
+}
+
+func TestLatest_NotFoundAndExpired(t *testing.T) {
+	s := newTestService(testNow)
+	ctx := context.Background()
+
+	_, err := s.Latest(ctx, "KJFK")
+	assert.ErrorIs(t, err, util.ErrNotFound)
+
+	s.Ingest(ctx, "push", "KJFK 061551Z 18012KT 10SM FEW050 17/11 A2992")
+	_, err = s.Latest(ctx, "KJFK")
+	assert.ErrorIs(t, err, util.ErrNotFound, "a report older than METAR_MAX_AGE is not served")
+}
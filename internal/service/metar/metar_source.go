@@ -0,0 +1,169 @@
+package service_metar
+
+import (
+	"context"
+	"errors"
+	"flight-api/config"
+	"flight-api/pkg/httpclient"
+	"flight-api/pkg/logger"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// doneSuffix is appended to drop files once they have been read, so they
+// are not ingested twice.
+const doneSuffix = ".done"
+
+// maxSourceBytes bounds a single download or drop file.
+const maxSourceBytes = 16 << 20
+
+// MetarBatch is raw report text read from one source.
+type MetarBatch struct {
+	Source string
+	Text   string
+}
+
+// IMetarSource is somewhere raw METAR text is pulled from.
+type IMetarSource interface {
+	Name() string
+	Fetch(ctx context.Context) ([]MetarBatch, error)
+}
+
+// NewMetarClient builds the HTTP client used to download METAR text, with the
+// retry and circuit breaker settings from cfg.
+func NewMetarClient(logger *logger.Logger, cfg *config.Config) *httpclient.Client {
+	return httpclient.NewClient(logger, httpclient.Options{
+		Name:                  "metar",
+		Timeout:               cfg.WeatherTimeout,
+		MaxRetries:            cfg.HTTPMaxRetries,
+		RetryBaseDelay:        cfg.HTTPRetryBase,
+		RetryMaxDelay:         cfg.HTTPRetryMax,
+		BreakerThreshold:      cfg.BreakerThreshold,
+		BreakerOpenTimeout:    cfg.BreakerOpenTimeout,
+		BreakerHalfOpenProbes: cfg.BreakerHalfOpenProbes,
+	})
+}
+
+// NewMetarSources returns the sources enabled by METAR_DROP_DIR and
+// METAR_SOURCE_URL. It returns none when neither is set.
+func NewMetarSources(logger *logger.Logger, cfg *config.Config, client *httpclient.Client) []IMetarSource {
+	var sources []IMetarSource
+	if cfg.MetarDropDir != "" {
+		sources = append(sources, NewFileMetarSource(logger, cfg.MetarDropDir))
+	}
+	if cfg.MetarSourceURL != "" {
+		sources = append(sources, NewHTTPMetarSource(logger, cfg.MetarSourceURL, client))
+	}
+	return sources
+}
+
+// FileMetarSource reads every file dropped into a directory, e.g. NOAA cycle
+// files synced by another job, and renames each one with a ".done" suffix
+// once read.
+type FileMetarSource struct {
+	logger *logger.Logger
+	dir    string
+}
+
+func NewFileMetarSource(logger *logger.Logger, dir string) *FileMetarSource {
+	return &FileMetarSource{logger: logger, dir: dir}
+}
+
+func (f *FileMetarSource) Name() string {
+	return "file"
+}
+
+// Fetch returns one batch per new file, oldest name first. A file that cannot
+// be read is skipped and retried on the next poll.
+func (f *FileMetarSource) Fetch(ctx context.Context) ([]MetarBatch, error) {
+	entries, err := os.ReadDir(f.dir)
+	if err != nil {
+		return nil, fmt.Errorf("read drop directory: %w", err)
+	}
+
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.Type().IsRegular() && !strings.HasSuffix(name, doneSuffix) && !strings.HasPrefix(name, ".") {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var batches []MetarBatch
+	for _, name := range names {
+		if err := ctx.Err(); err != nil {
+			return batches, err
+		}
+
+		path := filepath.Join(f.dir, name)
+		text, err := readFile(path)
+		if err != nil {
+			f.logger.Warnf("[Fetch] Failed to read METAR file %s: %v", path, err)
+			continue
+		}
+		if err := os.Rename(path, path+doneSuffix); err != nil {
+			f.logger.Warnf("[Fetch] Failed to mark METAR file %s as done: %v", path, err)
+			continue
+		}
+
+		batches = append(batches, MetarBatch{Source: "file:" + name, Text: text})
+	}
+	return batches, nil
+}
+
+func readFile(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	body, err := io.ReadAll(io.LimitReader(file, maxSourceBytes))
+	if err != nil {
+		return "", err
+	}
+	return string(body), nil
+}
+
+// HTTPMetarSource downloads plain-text reports, one per line, from a URL such
+// as the aviationweather.gov data API with format=raw.
+type HTTPMetarSource struct {
+	logger *logger.Logger
+	url    string
+	client *httpclient.Client
+}
+
+func NewHTTPMetarSource(logger *logger.Logger, url string, client *httpclient.Client) *HTTPMetarSource {
+	return &HTTPMetarSource{logger: logger, url: url, client: client}
+}
+
+func (h *HTTPMetarSource) Name() string {
+	return "http"
+}
+
+func (h *HTTPMetarSource) Fetch(ctx context.Context) ([]MetarBatch, error) {
+	resp, err := h.client.Get(ctx, h.url)
+	if err != nil {
+		if errors.Is(err, httpclient.ErrCircuitOpen) {
+			return nil, fmt.Errorf("METAR source is temporarily unavailable: %w", err)
+		}
+		return nil, fmt.Errorf("download METAR: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("download METAR: unexpected status %s", resp.Status)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxSourceBytes))
+	if err != nil {
+		return nil, fmt.Errorf("read METAR response: %w", err)
+	}
+	return []MetarBatch{{Source: h.Name(), Text: string(body)}}, nil
+}
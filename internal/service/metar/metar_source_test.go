@@ -0,0 +1,90 @@
+package service_metar
+
+import (
+	"context"
+	"flight-api/config"
+	"flight-api/pkg/httpclient"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileMetarSource_ReadsAndMarksFilesDone(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "18Z.TXT"), []byte("KJFK 061751Z 18012KT 10SM FEW050 17/11 A2992\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "17Z.TXT.done"), []byte("old"), 0o644))
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "archive"), 0o755))
+
+	source := NewFileMetarSource(log, dir)
+
+	batches, err := source.Fetch(context.Background())
+	require.NoError(t, err)
+	require.Len(t, batches, 1)
+	assert.Equal(t, "file:18Z.TXT", batches[0].Source)
+	assert.Contains(t, batches[0].Text, "KJFK")
+	assert.FileExists(t, filepath.Join(dir, "18Z.TXT.done"))
+
+	batches, err = source.Fetch(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, batches, "a file is only read once")
+}
+
+func TestFileMetarSource_MissingDirectory(t *testing.T) {
+	_, err := NewFileMetarSource(log, filepath.Join(t.TempDir(), "missing")).Fetch(context.Background())
+	assert.Error(t, err)
+}
+
+func TestHTTPMetarSource_Fetch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("KJFK 061751Z 18012KT 10SM FEW050 17/11 A2992\n"))
+	}))
+	defer server.Close()
+
+	source := NewHTTPMetarSource(log, server.URL, NewMetarClient(log, &config.Config{WeatherTimeout: time.Second}))
+
+	batches, err := source.Fetch(context.Background())
+	require.NoError(t, err)
+	require.Len(t, batches, 1)
+	assert.Equal(t, "http", batches[0].Source)
+}
+
+func TestHTTPMetarSource_UpstreamError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	client := httpclient.NewClient(log, httpclient.Options{Name: "metar", Timeout: time.Second})
+	_, err := NewHTTPMetarSource(log, server.URL, client).Fetch(context.Background())
+	assert.ErrorContains(t, err, "404")
+}
+
+func TestMetarIngester_RunOnce(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "a.txt"), []byte("KJFK 061751Z 18012KT 10SM FEW050 17/11 A2992"), 0o644))
+
+	service := newTestService(testNow)
+	failing := NewFileMetarSource(log, filepath.Join(dir, "missing"))
+	ingester := NewMetarIngester(log, &config.Config{}, service, failing, NewFileMetarSource(log, dir))
+
+	ingester.RunOnce(context.Background())
+
+	m, err := service.Latest(context.Background(), "KJFK")
+	require.NoError(t, err)
+	assert.Equal(t, "KJFK", m.Station)
+}
+
+func TestNewMetarSources(t *testing.T) {
+	assert.Empty(t, NewMetarSources(log, &config.Config{}, nil))
+
+	sources := NewMetarSources(log, &config.Config{MetarDropDir: "/tmp", MetarSourceURL: "http://example.com"}, nil)
+	require.Len(t, sources, 2)
+	assert.Equal(t, "file", sources[0].Name())
+	assert.Equal(t, "http", sources[1].Name())
+}
@@ -0,0 +1,18 @@
+package service_status
+
+import (
+	"context"
+	"flight-api/internal/cache"
+	status_dto "flight-api/internal/dto/status"
+)
+
+type IStatusService interface {
+	GetStatus(ctx context.Context) *status_dto.StatusDto
+}
+
+// ICacheStats is a cache consumer whose counters are reported on the status
+// endpoint.
+type ICacheStats interface {
+	Name() string
+	Stats() cache.Stats
+}
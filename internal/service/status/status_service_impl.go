@@ -0,0 +1,118 @@
+package service_status
+
+import (
+	"context"
+	"database/sql"
+	status_dto "flight-api/internal/dto/status"
+	"flight-api/pkg/httpclient"
+	"flight-api/pkg/logger"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// pingTimeout bounds the database check so a hung pool cannot stall the
+// status endpoint.
+const pingTimeout = 2 * time.Second
+
+type StatusService struct {
+	logger    *logger.Logger
+	db        *sql.DB
+	caches    []ICacheStats
+	upstreams []*httpclient.Client
+	now       func() time.Time
+
+	mu            sync.Mutex
+	dbFailures    int
+	dbLastError   string
+	dbLastErrorAt time.Time
+	dbLastOK      time.Time
+}
+
+func NewStatusService(logger *logger.Logger, db *sql.DB, caches []ICacheStats, upstreams ...*httpclient.Client) IStatusService {
+	return &StatusService{
+		logger:    logger,
+		db:        db,
+		caches:    caches,
+		upstreams: upstreams,
+		now:       time.Now,
+	}
+}
+
+// GetStatus pings the database and reports the circuit breaker state of
+// every upstream. Upstreams are not called; their health comes from real
+// traffic, and the cache counters are reported alongside. The overall status is down when the database is down and degraded
+// when any other dependency is not up.
+func (s *StatusService) GetStatus(ctx context.Context) *status_dto.StatusDto {
+	status := &status_dto.StatusDto{
+		Object:       "status",
+		Status:       status_dto.StatusUp,
+		CheckedAt:    s.now().UTC(),
+		Dependencies: make([]status_dto.DependencyDto, 0, len(s.upstreams)+1),
+		Caches:       make([]status_dto.CacheDto, 0, len(s.caches)),
+	}
+
+	if s.db != nil {
+		database := s.checkDatabase(ctx)
+		if database.Status != status_dto.StatusUp {
+			status.Status = status_dto.StatusDown
+		}
+		status.Dependencies = append(status.Dependencies, database)
+	}
+
+	for _, upstream := range s.upstreams {
+		dependency := status_dto.ToUpstreamDependencyDto(upstream.Health(), upstream.Stats())
+		if dependency.Status != status_dto.StatusUp && status.Status == status_dto.StatusUp {
+			status.Status = status_dto.StatusDegraded
+		}
+		status.Dependencies = append(status.Dependencies, dependency)
+	}
+
+	for _, c := range s.caches {
+		status.Caches = append(status.Caches, status_dto.ToCacheDto(c.Name(), c.Stats()))
+	}
+
+	return status
+}
+
+func (s *StatusService) checkDatabase(ctx context.Context) status_dto.DependencyDto {
+	pingCtx, cancel := context.WithTimeout(ctx, pingTimeout)
+	defer cancel()
+
+	err := s.db.PingContext(pingCtx)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err != nil {
+		s.logger.Errorw(logrus.Fields{"error": err}, "[checkDatabase] Database ping failed")
+		s.dbFailures++
+		s.dbLastError = err.Error()
+		s.dbLastErrorAt = s.now().UTC()
+	} else {
+		s.dbFailures = 0
+		s.dbLastOK = s.now().UTC()
+	}
+
+	dependency := status_dto.DependencyDto{
+		Name:                "postgres",
+		Kind:                status_dto.KindDatabase,
+		Status:              status_dto.StatusUp,
+		ConsecutiveFailures: s.dbFailures,
+	}
+	if err != nil {
+		dependency.Status = status_dto.StatusDown
+	}
+	if !s.dbLastErrorAt.IsZero() {
+		lastError, at := s.dbLastError, s.dbLastErrorAt
+		dependency.LastError = &lastError
+		dependency.LastErrorAt = &at
+	}
+	if !s.dbLastOK.IsZero() {
+		at := s.dbLastOK
+		dependency.LastSuccessAt = &at
+	}
+
+	return dependency
+}
@@ -0,0 +1,121 @@
+package service_status
+
+import (
+	"context"
+	"errors"
+	"flight-api/internal/cache"
+	status_dto "flight-api/internal/dto/status"
+	"flight-api/pkg/httpclient"
+	"flight-api/pkg/logger"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var log = logger.NewLogger(logger.DEBUG_LEVEL)
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+func newFailingClient(name string) *httpclient.Client {
+	rt := roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		return nil, errors.New("connection refused")
+	})
+	return httpclient.NewClient(log, httpclient.Options{Name: name, Transport: rt, BreakerThreshold: 1, BreakerOpenTimeout: time.Hour})
+}
+
+type cacheStatsStub struct {
+	name  string
+	stats cache.Stats
+}
+
+func (c cacheStatsStub) Name() string       { return c.name }
+func (c cacheStatsStub) Stats() cache.Stats { return c.stats }
+
+func TestGetStatus_AllUp(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.MonitorPingsOption(true))
+	require.NoError(t, err)
+	defer db.Close()
+	mock.ExpectPing()
+
+	weather := httpclient.NewClient(log, httpclient.Options{Name: "weather"})
+	svc := NewStatusService(log, db, nil, weather)
+
+	status := svc.GetStatus(context.Background())
+
+	assert.Equal(t, status_dto.StatusUp, status.Status)
+	require.Len(t, status.Dependencies, 2)
+
+	database := status.Dependencies[0]
+	assert.Equal(t, "postgres", database.Name)
+	assert.Equal(t, status_dto.KindDatabase, database.Kind)
+	assert.Equal(t, status_dto.StatusUp, database.Status)
+	assert.NotNil(t, database.LastSuccessAt)
+	assert.Nil(t, database.LastError)
+
+	upstream := status.Dependencies[1]
+	assert.Equal(t, "weather", upstream.Name)
+	assert.Equal(t, status_dto.KindUpstream, upstream.Kind)
+	assert.Equal(t, status_dto.StatusUp, upstream.Status)
+	require.NotNil(t, upstream.BreakerState)
+	assert.Equal(t, "closed", *upstream.BreakerState)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetStatus_OpenBreakerIsDegraded(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.MonitorPingsOption(true))
+	require.NoError(t, err)
+	defer db.Close()
+	mock.ExpectPing()
+
+	aviation := newFailingClient("aviation")
+	_, err = aviation.Get(context.Background(), "http://upstream/airports")
+	require.Error(t, err)
+
+	status := NewStatusService(log, db, nil, aviation).GetStatus(context.Background())
+
+	assert.Equal(t, status_dto.StatusDegraded, status.Status)
+	upstream := status.Dependencies[1]
+	assert.Equal(t, status_dto.StatusDown, upstream.Status)
+	assert.Equal(t, "open", *upstream.BreakerState)
+	require.NotNil(t, upstream.LastError)
+	assert.Contains(t, *upstream.LastError, "connection refused")
+	assert.NotNil(t, upstream.LastErrorAt)
+	assert.NotNil(t, upstream.OpenUntil)
+}
+
+func TestGetStatus_DatabaseDown(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.MonitorPingsOption(true))
+	require.NoError(t, err)
+	defer db.Close()
+	mock.ExpectPing()
+	mock.ExpectPing().WillReturnError(errors.New("connection reset"))
+
+	svc := NewStatusService(log, db, nil)
+	svc.GetStatus(context.Background())
+	status := svc.GetStatus(context.Background())
+
+	assert.Equal(t, status_dto.StatusDown, status.Status)
+	database := status.Dependencies[0]
+	assert.Equal(t, status_dto.StatusDown, database.Status)
+	assert.Equal(t, 1, database.ConsecutiveFailures)
+	require.NotNil(t, database.LastError)
+	assert.Equal(t, "connection reset", *database.LastError)
+	assert.NotNil(t, database.LastSuccessAt, "the earlier successful ping is kept")
+}
+
+func TestGetStatus_ReportsCacheStats(t *testing.T) {
+	weather := cacheStatsStub{name: "weather", stats: cache.Stats{Hits: 7, Misses: 3, Errors: 1}}
+
+	status := NewStatusService(log, nil, []ICacheStats{weather}).GetStatus(context.Background())
+
+	assert.Equal(t, status_dto.StatusUp, status.Status)
+	assert.Equal(t, []status_dto.CacheDto{{Name: "weather", Hits: 7, Misses: 3, Errors: 1}}, status.Caches)
+}
@@ -0,0 +1,133 @@
+package service_sync
+
+import (
+	sync_dto "flight-api/internal/dto/sync"
+	"flight-api/internal/enum"
+	"flight-api/internal/model"
+	service_import "flight-api/internal/service/import"
+	"flight-api/util"
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// unsyncedColumns are the airport columns a refresh never copies from the
+// fetched data: the identity, and the bookkeeping the sync sets itself.
+var unsyncedColumns = map[string]bool{
+	"id":             true,
+	"icao_id":        true,
+	"effective_date": true,
+	"sync_status":    true,
+	"sync_message":   true,
+	"sync_attempts":  true,
+	"created_at":     true,
+	"updated_at":     true,
+}
+
+// syncFields are the refreshable airport fields, named after their columns.
+var syncFields = func() map[string]bool {
+	fields := map[string]bool{}
+	t := reflect.TypeOf(model.Airport{})
+	for i := 0; i < t.NumField(); i++ {
+		column := t.Field(i).Tag.Get("db")
+		if column != "" && !unsyncedColumns[column] {
+			fields[column] = true
+		}
+	}
+	return fields
+}()
+
+// ValidateSyncFields rejects field names a refresh cannot update.
+func ValidateSyncFields(fields []string) error {
+	var unknown []string
+	for _, field := range fields {
+		if !syncFields[field] {
+			unknown = append(unknown, field)
+		}
+	}
+	if len(unknown) == 0 {
+		return nil
+	}
+
+	allowed := make([]string, 0, len(syncFields))
+	for field := range syncFields {
+		allowed = append(allowed, field)
+	}
+	sort.Strings(allowed)
+
+	detail := fmt.Sprintf("Unknown sync fields %s, expected any of %s", strings.Join(unknown, ", "), strings.Join(allowed, ", "))
+	return util.NewAppError(util.ErrBadRequest, detail, nil)
+}
+
+// mergeAirport copies the fetched values that differ from the stored ones
+// onto a copy of stored, limited to fields when it is not empty, and lists
+// the changes in field order. A value missing from the fetched data never
+// clears a stored one.
+func mergeAirport(stored model.Airport, fetched model.Airport, fields []string) (model.Airport, []sync_dto.FieldChangeDto) {
+	allowed := func(column string) bool {
+		return syncFields[column]
+	}
+	if len(fields) > 0 {
+		allowed = func(column string) bool {
+			return slices.Contains(fields, column)
+		}
+	}
+	return mergeColumns(stored, fetched, allowed)
+}
+
+// mergeFetched merges data fetched from the Aviation API over stored like
+// mergeAirport, leaving out the fields whose recorded source in owners
+// outranks the Aviation API under priority, as an import does. It returns the
+// fields it changed, with the Aviation API as their source.
+func mergeFetched(stored model.Airport, owners map[string]string, fetched model.Airport, fields []string, priority service_import.SourcePriority) (model.Airport, []sync_dto.FieldChangeDto, map[string]string) {
+	kept, _ := service_import.MergeBySource(fetched, &stored, owners, enum.AIRPORT_SOURCE_AVIATION_API, priority)
+	merged, changes := mergeAirport(stored, kept, fields)
+
+	claimed := make(map[string]string, len(changes))
+	for _, change := range changes {
+		claimed[change.Field] = enum.AIRPORT_SOURCE_AVIATION_API.String()
+	}
+	return merged, changes, claimed
+}
+
+// insertChanges lists every value of an airport about to be inserted as a
+// change from nothing, leaving out what the database fills in.
+func insertChanges(airport model.Airport) []sync_dto.FieldChangeDto {
+	_, changes := mergeColumns(model.Airport{}, airport, func(column string) bool {
+		return column == "icao_id" || column == "effective_date" || !unsyncedColumns[column]
+	})
+	return changes
+}
+
+func mergeColumns(dst model.Airport, src model.Airport, allowed func(column string) bool) (model.Airport, []sync_dto.FieldChangeDto) {
+	merged := dst
+	to := reflect.ValueOf(&merged).Elem()
+	from := reflect.ValueOf(src)
+
+	changes := []sync_dto.FieldChangeDto{}
+	for i := 0; i < to.NumField(); i++ {
+		column := to.Type().Field(i).Tag.Get("db")
+		if column == "" || !allowed(column) {
+			continue
+		}
+
+		newValue, oldValue := from.Field(i), to.Field(i)
+		if newValue.IsNil() {
+			continue
+		}
+		if !oldValue.IsNil() && reflect.DeepEqual(oldValue.Elem().Interface(), newValue.Elem().Interface()) {
+			continue
+		}
+
+		change := sync_dto.FieldChangeDto{Field: column, New: newValue.Elem().Interface()}
+		if !oldValue.IsNil() {
+			change.Old = oldValue.Elem().Interface()
+		}
+		changes = append(changes, change)
+		oldValue.Set(newValue)
+	}
+
+	return merged, changes
+}
@@ -0,0 +1,73 @@
+package service_sync
+
+import (
+	"flight-api/internal/model"
+	"flight-api/util"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergeAirport(t *testing.T) {
+	id := uuid.New()
+	stored := model.Airport{
+		ID:        &id,
+		ICAOID:    util.Ptr("KJFK"),
+		Name:      util.Ptr("John F Kennedy Intl"),
+		City:      util.Ptr("New York"),
+		Manager:   util.Ptr("Local Curator"),
+		Elevation: util.Ptr(int64(13)),
+	}
+	fetched := model.Airport{
+		ICAOID:    util.Ptr("KJFK"),
+		Name:      util.Ptr("John F Kennedy International"),
+		City:      util.Ptr("New York"),
+		Manager:   util.Ptr("Port Authority"),
+		Elevation: util.Ptr(int64(13)),
+		Unicom:    util.Ptr("122.950"),
+	}
+
+	t.Run("every field", func(t *testing.T) {
+		merged, changes := mergeAirport(stored, fetched, nil)
+
+		require.Equal(t, id, *merged.ID)
+		require.Equal(t, "John F Kennedy International", *merged.Name)
+		require.Equal(t, "Port Authority", *merged.Manager)
+		require.Equal(t, "122.950", *merged.Unicom)
+		require.Len(t, changes, 3)
+		require.Equal(t, "name", changes[0].Field)
+		require.Equal(t, "John F Kennedy Intl", changes[0].Old)
+		require.Equal(t, "John F Kennedy International", changes[0].New)
+		require.Equal(t, "manager", changes[1].Field)
+		require.Equal(t, "unicom", changes[2].Field)
+		require.Nil(t, changes[2].Old)
+
+		// The stored airport is left untouched
+		require.Equal(t, "John F Kennedy Intl", *stored.Name)
+	})
+
+	t.Run("allowlist protects curated fields", func(t *testing.T) {
+		merged, changes := mergeAirport(stored, fetched, []string{"name", "city"})
+
+		require.Equal(t, "John F Kennedy International", *merged.Name)
+		require.Equal(t, "Local Curator", *merged.Manager)
+		require.Nil(t, merged.Unicom)
+		require.Len(t, changes, 1)
+		require.Equal(t, "name", changes[0].Field)
+	})
+
+	t.Run("missing values keep stored ones", func(t *testing.T) {
+		merged, changes := mergeAirport(stored, model.Airport{}, nil)
+
+		require.Equal(t, stored, merged)
+		require.Empty(t, changes)
+	})
+}
+
+func TestValidateSyncFields(t *testing.T) {
+	require.NoError(t, ValidateSyncFields(nil))
+	require.NoError(t, ValidateSyncFields([]string{"name", "manager_phone", "magnetic_variation"}))
+	require.ErrorIs(t, ValidateSyncFields([]string{"name", "icao_id", "nope"}), util.ErrBadRequest)
+	require.ErrorContains(t, ValidateSyncFields([]string{"nope"}), "nope")
+}
@@ -0,0 +1,22 @@
+package service_sync
+
+import (
+	"context"
+	"database/sql"
+	pagination_dto "flight-api/internal/dto/pagination"
+	queryparams "flight-api/internal/dto/query_params"
+	sync_dto "flight-api/internal/dto/sync"
+)
+
+// ISyncJobService runs airport syncs in the background as jobs persisted in
+// the database, so they can be polled, canceled and survive a restart.
+type ISyncJobService interface {
+	Start(ctx context.Context, req sync_dto.SyncAirportRequest) (sync_dto.SyncJobDto, error)
+	Queue(ctx context.Context, tx *sql.Tx, req sync_dto.SyncAirportRequest) (sync_dto.SyncJobDto, error)
+	Launch(ctx context.Context, id string) error
+	FindByID(ctx context.Context, id string) (sync_dto.SyncJobDto, error)
+	FindAll(ctx context.Context, query queryparams.QueryParams, status string) (pagination_dto.PaginationDto, error)
+	Cancel(ctx context.Context, id string) (sync_dto.SyncJobDto, error)
+	Resume(ctx context.Context)
+	Shutdown(ctx context.Context) error
+}
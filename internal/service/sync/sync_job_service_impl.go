@@ -0,0 +1,517 @@
+package service_sync
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"flight-api/config"
+	pagination_dto "flight-api/internal/dto/pagination"
+	queryparams "flight-api/internal/dto/query_params"
+	sync_dto "flight-api/internal/dto/sync"
+	"flight-api/internal/enum"
+	"flight-api/internal/model"
+	repo_sync_job "flight-api/internal/repository/sync_job"
+	"flight-api/pkg/logger"
+	"flight-api/util"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/go-playground/validator"
+)
+
+const (
+	defaultSyncJobStaleAfter = 5 * time.Minute
+	defaultSyncJobChunkSize  = 25
+)
+
+var (
+	// errJobCanceled stops a job canceled through the API.
+	errJobCanceled = errors.New("sync job canceled")
+	// errShuttingDown stops every job when the server shuts down; the jobs
+	// go back to the queue and are resumed on the next start.
+	errShuttingDown = errors.New("server shutting down")
+)
+
+type SyncJobService struct {
+	logger            *logger.Logger
+	cfg               *config.Config
+	validate          *validator.Validate
+	db                *sql.DB
+	syncService       ISyncService
+	syncJobRepository repo_sync_job.ISyncJobRepository
+
+	// Jobs run detached from the request that started them, under baseCtx.
+	baseCtx context.Context
+	stop    context.CancelCauseFunc
+	mu      sync.Mutex
+	cancels map[string]context.CancelCauseFunc
+	wg      sync.WaitGroup
+}
+
+func NewSyncJobService(
+	logger *logger.Logger,
+	cfg *config.Config,
+	validate *validator.Validate,
+	db *sql.DB,
+	syncService ISyncService,
+	syncJobRepository repo_sync_job.ISyncJobRepository,
+) ISyncJobService {
+	baseCtx, stop := context.WithCancelCause(context.Background())
+	return &SyncJobService{
+		logger:            logger,
+		cfg:               cfg,
+		validate:          validate,
+		db:                db,
+		syncService:       syncService,
+		syncJobRepository: syncJobRepository,
+		baseCtx:           baseCtx,
+		stop:              stop,
+		cancels:           map[string]context.CancelCauseFunc{},
+	}
+}
+
+// Start queues a job for the requested ICAO codes and starts running it.
+func (s *SyncJobService) Start(ctx context.Context, req sync_dto.SyncAirportRequest) (sync_dto.SyncJobDto, error) {
+	if err := s.validateRequest("[Start]", req); err != nil {
+		return sync_dto.SyncJobDto{}, err
+	}
+
+	var job model.SyncJob
+	err := s.withTx(func(tx *sql.Tx) (err error) {
+		job, err = s.insert(ctx, tx, req)
+		return err
+	})
+	if err != nil {
+		s.logger.Errorf("[Start] Failed to create sync job: %v", err)
+		return sync_dto.SyncJobDto{}, util.NewAppError(util.ErrInternalServer, "Failed to create sync job", err)
+	}
+
+	s.logger.Debugf("[Start] Sync job %s queued for %d ICAO codes", job.ID, len(job.ICAOCodes))
+	s.launch(job)
+	return sync_dto.ToSyncJobDto(job), nil
+}
+
+// Queue creates a queued job in the caller's transaction, so it is only
+// stored together with the caller's own writes. It does not run until
+// Launch is called once that transaction has committed.
+func (s *SyncJobService) Queue(ctx context.Context, tx *sql.Tx, req sync_dto.SyncAirportRequest) (sync_dto.SyncJobDto, error) {
+	if err := s.validateRequest("[Queue]", req); err != nil {
+		return sync_dto.SyncJobDto{}, err
+	}
+
+	job, err := s.insert(ctx, tx, req)
+	if err != nil {
+		s.logger.Errorf("[Queue] Failed to create sync job: %v", err)
+		return sync_dto.SyncJobDto{}, util.NewAppError(util.ErrInternalServer, "Failed to create sync job", err)
+	}
+
+	s.logger.Debugf("[Queue] Sync job %s queued for %d ICAO codes", job.ID, len(job.ICAOCodes))
+	return sync_dto.ToSyncJobDto(job), nil
+}
+
+// Launch starts running a job created by Queue. A job that is no longer
+// queued is left as it is.
+func (s *SyncJobService) Launch(ctx context.Context, id string) error {
+	var job model.SyncJob
+	err := s.withTx(func(tx *sql.Tx) (err error) {
+		job, err = s.findByID(ctx, tx, id)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	s.launch(job)
+	return nil
+}
+
+func (s *SyncJobService) validateRequest(logPrefix string, req sync_dto.SyncAirportRequest) error {
+	if err := s.validate.Struct(req); err != nil {
+		s.logger.Errorf("%s request validation failed %s", logPrefix, err)
+		return err
+	}
+
+	return ValidateSyncFields(req.Fields)
+}
+
+// insert stores a queued job. A request without a trigger comes from the
+// API and is stored as a job trigger.
+func (s *SyncJobService) insert(ctx context.Context, tx *sql.Tx, req sync_dto.SyncAirportRequest) (model.SyncJob, error) {
+	job := model.SyncJob{
+		ICAOCodes: util.RemoveDuplicate(req.ICAOCodes),
+		Mode:      &req.Mode,
+		Fields:    req.Fields,
+		Atomic:    &req.Atomic,
+	}
+	if req.Trigger != "" {
+		job.Trigger = &req.Trigger
+	}
+	return s.syncJobRepository.Insert(ctx, tx, job)
+}
+
+func (s *SyncJobService) FindByID(ctx context.Context, id string) (_ sync_dto.SyncJobDto, err error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		s.logger.Errorf("[FindByID] Failed to begin transaction: %v", err)
+		return sync_dto.SyncJobDto{}, util.NewAppError(util.ErrInternalServer, "Failed to begin transaction", err)
+	}
+	defer util.CommitOrRollbackErr(tx, &err)
+
+	job, err := s.findByID(ctx, tx, id)
+	if err != nil {
+		return sync_dto.SyncJobDto{}, err
+	}
+
+	return sync_dto.ToSyncJobDto(job), nil
+}
+
+// FindAll lists jobs newest first, optionally only those with status.
+func (s *SyncJobService) FindAll(ctx context.Context, query queryparams.QueryParams, status string) (_ pagination_dto.PaginationDto, err error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		s.logger.Errorf("[FindAll] Failed to begin transaction: %v", err)
+		return pagination_dto.PaginationDto{}, util.NewAppError(util.ErrInternalServer, "Failed to begin transaction", err)
+	}
+	defer util.CommitOrRollbackErr(tx, &err)
+
+	args := map[string]interface{}{
+		"limit":  query.Limit,
+		"offset": query.Offset,
+		"status": status,
+	}
+	jobs, total, err := s.syncJobRepository.FindAll(ctx, tx, args)
+	if err != nil {
+		s.logger.Errorf("[FindAll] Failed to fetch sync jobs: %v", err)
+		return pagination_dto.PaginationDto{}, util.NewAppError(util.ErrInternalServer, "Failed to fetch sync jobs", err)
+	}
+
+	hasNext := (query.Offset + query.Limit) < total
+
+	response := pagination_dto.PaginationDto{
+		Object:  "pagination",
+		Records: util.ToInterfaces(sync_dto.ToSyncJobDtos(jobs)),
+		Total:   total,
+		Meta: &pagination_dto.PaginationMetaDto{
+			Limit: query.Limit,
+			Page:  query.Page,
+			Next:  hasNext,
+		},
+	}
+
+	return response, nil
+}
+
+// Cancel cancels a queued or running job. The results processed so far are
+// kept. A job that has already finished is a conflict.
+func (s *SyncJobService) Cancel(ctx context.Context, id string) (_ sync_dto.SyncJobDto, err error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		s.logger.Errorf("[Cancel] Failed to begin transaction: %v", err)
+		return sync_dto.SyncJobDto{}, util.NewAppError(util.ErrInternalServer, "Failed to begin transaction", err)
+	}
+	defer util.CommitOrRollbackErr(tx, &err)
+
+	if _, err = s.findByID(ctx, tx, id); err != nil {
+		return sync_dto.SyncJobDto{}, err
+	}
+
+	canceled, err := s.syncJobRepository.Cancel(ctx, tx, id)
+	if err != nil {
+		s.logger.Errorf("[Cancel] Failed to cancel sync job %s: %v", id, err)
+		return sync_dto.SyncJobDto{}, util.NewAppError(util.ErrInternalServer, "Failed to cancel sync job", err)
+	}
+	if !canceled {
+		return sync_dto.SyncJobDto{}, util.NewAppError(util.ErrConflict, fmt.Sprintf("Sync job with ID %s has already finished", id), nil)
+	}
+
+	job, err := s.findByID(ctx, tx, id)
+	if err != nil {
+		return sync_dto.SyncJobDto{}, err
+	}
+
+	// A job running on another instance notices on its next progress update.
+	s.mu.Lock()
+	if cancel, ok := s.cancels[id]; ok {
+		cancel(errJobCanceled)
+	}
+	s.mu.Unlock()
+
+	s.logger.Debugf("[Cancel] Sync job %s canceled", id)
+	return sync_dto.ToSyncJobDto(job), nil
+}
+
+// Resume picks up the queued jobs and the running ones whose worker stopped
+// updating them SYNC_JOB_STALE_AFTER ago, e.g. because the server crashed.
+func (s *SyncJobService) Resume(ctx context.Context) {
+	staleAfter := s.cfg.SyncJobStaleAfter
+	if staleAfter <= 0 {
+		staleAfter = defaultSyncJobStaleAfter
+	}
+
+	var jobs []model.SyncJob
+	err := s.withTx(func(tx *sql.Tx) (err error) {
+		jobs, err = s.syncJobRepository.ClaimResumable(ctx, tx, time.Now().Add(-staleAfter))
+		return err
+	})
+	if err != nil {
+		s.logger.Errorf("[Resume] Failed to claim sync jobs: %v", err)
+		return
+	}
+
+	for _, job := range jobs {
+		s.logger.Infof("[Resume] Resuming sync job %s", job.ID)
+		s.launch(job)
+	}
+}
+
+// Shutdown stops the running jobs, which go back to the queue, and waits
+// for them until ctx is done.
+func (s *SyncJobService) Shutdown(ctx context.Context) error {
+	s.stop(errShuttingDown)
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (s *SyncJobService) findByID(ctx context.Context, tx *sql.Tx, id string) (model.SyncJob, error) {
+	job, err := s.syncJobRepository.FindByID(ctx, tx, id)
+	if errors.Is(err, util.ErrNotFound) {
+		return model.SyncJob{}, util.NewAppError(util.ErrNotFound, fmt.Sprintf("Sync job with ID %s not found", id), nil)
+	} else if err != nil {
+		s.logger.Errorf("[FindByID] Failed to fetch sync job %s: %v", id, err)
+		return model.SyncJob{}, util.NewAppError(util.ErrInternalServer, "Failed to fetch sync job", err)
+	}
+	return job, nil
+}
+
+// launch runs job in the background until it finishes, is canceled or the
+// service shuts down.
+func (s *SyncJobService) launch(job model.SyncJob) {
+	id := job.ID.String()
+	ctx, cancel := context.WithCancelCause(s.baseCtx)
+
+	s.mu.Lock()
+	s.cancels[id] = cancel
+	s.mu.Unlock()
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		defer func() {
+			s.mu.Lock()
+			delete(s.cancels, id)
+			s.mu.Unlock()
+			cancel(nil)
+		}()
+
+		s.run(ctx, job)
+	}()
+}
+
+// run syncs the job's codes a chunk at a time, skipping those a previous
+// run already recorded, and stores the results after each chunk. A job that
+// completes or is canceled is recorded as one sync run with the results of
+// all of its chunks; a requeued job is recorded once it is resumed and ends.
+func (s *SyncJobService) run(ctx context.Context, job model.SyncJob) {
+	id := job.ID.String()
+	// Bookkeeping must outlive a cancellation so the job can be requeued.
+	bookCtx := context.WithoutCancel(ctx)
+
+	var started bool
+	err := s.withTx(func(tx *sql.Tx) (err error) {
+		started, err = s.syncJobRepository.MarkRunning(bookCtx, tx, id)
+		return err
+	})
+	if err != nil {
+		s.logger.Errorf("[run] Failed to start sync job %s: %v", id, err)
+		return
+	}
+	if !started {
+		s.logger.Debugf("[run] Sync job %s is no longer queued", id)
+		return
+	}
+
+	// A resumed job's run covers the whole job, from when it first started.
+	startedAt := time.Now()
+	if job.StartedAt != nil {
+		startedAt = *job.StartedAt
+	}
+
+	// Codes are synced a chunk at a time, one Aviation API batch each, with
+	// the job's mode and fields. Progress is recorded after every chunk. An
+	// atomic job syncs all of its codes as one chunk, in one transaction.
+	mode := enum.SYNC_MODE_INSERT.String()
+	if job.Mode != nil {
+		mode = *job.Mode
+	}
+	atomic := job.Atomic != nil && *job.Atomic
+	trigger := enum.SYNC_RUN_TRIGGER_JOB.String()
+	if job.Trigger != nil {
+		trigger = *job.Trigger
+	}
+	req := func(codes []string) sync_dto.SyncAirportRequest {
+		return sync_dto.SyncAirportRequest{
+			ICAOCodes: codes,
+			Mode:      mode,
+			Fields:    job.Fields,
+			Atomic:    atomic,
+			Trigger:   trigger,
+			JobID:     job.ID,
+		}
+	}
+
+	results := sync_dto.DecodeSyncJobResults(job.Results)
+	remaining := pendingCodes(job.ICAOCodes, results)
+	chunkSize := s.chunkSize()
+	if atomic {
+		chunkSize = max(len(remaining), 1)
+	}
+	for codes := range slices.Chunk(remaining, chunkSize) {
+		if ctx.Err() != nil {
+			break
+		}
+
+		out, err := s.syncService.SyncAirports(ctx, req(codes))
+		interrupted := ctx.Err() != nil
+		if interrupted {
+			// Interrupted mid-way: the codes already synced are kept, the
+			// rest of the chunk is synced again on resume.
+			out = settledResults(out)
+			if len(out) == 0 {
+				break
+			}
+		} else if err != nil {
+			s.logger.Errorf("[run] Sync job %s failed to sync ICAO codes %v: %v", id, codes, err)
+			out = make([]sync_dto.SyncAirportResponse, 0, len(codes))
+			for _, code := range codes {
+				out = append(out, sync_dto.SyncAirportResponse{ICAOCode: code, Status: "Error", Message: err.Error()})
+			}
+		}
+		results = append(results, out...)
+
+		raw, err := json.Marshal(results)
+		if err != nil {
+			s.fail(bookCtx, id, err)
+			return
+		}
+
+		var updated bool
+		err = s.withTx(func(tx *sql.Tx) (err error) {
+			updated, err = s.syncJobRepository.UpdateProgress(bookCtx, tx, id, len(results), raw)
+			return err
+		})
+		if err != nil {
+			s.fail(bookCtx, id, err)
+			return
+		}
+		if !updated {
+			s.logger.Infof("[run] Sync job %s was canceled", id)
+			s.syncService.RecordRun(bookCtx, req(job.ICAOCodes), results, startedAt)
+			return
+		}
+		if interrupted {
+			break
+		}
+	}
+
+	switch cause := context.Cause(ctx); {
+	case errors.Is(cause, errJobCanceled):
+		s.logger.Infof("[run] Sync job %s was canceled", id)
+		s.syncService.RecordRun(bookCtx, req(job.ICAOCodes), results, startedAt)
+	case errors.Is(cause, errShuttingDown):
+		err := s.withTx(func(tx *sql.Tx) (err error) {
+			_, err = s.syncJobRepository.Requeue(bookCtx, tx, id)
+			return err
+		})
+		if err != nil {
+			s.logger.Errorf("[run] Failed to requeue sync job %s: %v", id, err)
+			return
+		}
+		s.logger.Infof("[run] Sync job %s requeued", id)
+	default:
+		s.syncService.RecordRun(bookCtx, req(job.ICAOCodes), results, startedAt)
+		err := s.withTx(func(tx *sql.Tx) (err error) {
+			_, err = s.syncJobRepository.Finish(bookCtx, tx, id, enum.SYNC_JOB_COMPLETED.String(), nil)
+			return err
+		})
+		if err != nil {
+			s.logger.Errorf("[run] Failed to complete sync job %s: %v", id, err)
+			return
+		}
+		s.logger.Infof("[run] Sync job %s completed", id)
+	}
+}
+
+// pendingCodes returns the codes of the job that have no result yet, in the
+// job's order.
+func pendingCodes(codes []string, results []sync_dto.SyncAirportResponse) []string {
+	done := make(map[string]bool, len(results))
+	for _, res := range results {
+		done[res.ICAOCode] = true
+	}
+
+	pending := make([]string, 0, len(codes))
+	for _, code := range codes {
+		if !done[code] {
+			pending = append(pending, code)
+		}
+	}
+	return pending
+}
+
+// settledResults keeps the results of an interrupted sync that stand on
+// their own. A failure may have been caused by the interruption, so those
+// codes are left to be synced again.
+func settledResults(out []sync_dto.SyncAirportResponse) []sync_dto.SyncAirportResponse {
+	settled := make([]sync_dto.SyncAirportResponse, 0, len(out))
+	for _, res := range out {
+		if !enum.ToSyncRunOutcome(res.Status).IsFailure() {
+			settled = append(settled, res)
+		}
+	}
+	return settled
+}
+
+// fail marks the job failed after its progress could not be stored.
+func (s *SyncJobService) fail(ctx context.Context, id string, cause error) {
+	s.logger.Errorf("[run] Sync job %s failed: %v", id, cause)
+
+	message := cause.Error()
+	err := s.withTx(func(tx *sql.Tx) (err error) {
+		_, err = s.syncJobRepository.Finish(ctx, tx, id, enum.SYNC_JOB_FAILED.String(), &message)
+		return err
+	})
+	if err != nil {
+		s.logger.Errorf("[run] Failed to mark sync job %s failed: %v", id, err)
+	}
+}
+
+func (s *SyncJobService) withTx(fn func(tx *sql.Tx) error) (err error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer util.CommitOrRollbackErr(tx, &err)
+
+	return fn(tx)
+}
+
+func (s *SyncJobService) chunkSize() int {
+	if s.cfg.AviationBatchSize > 0 {
+		return s.cfg.AviationBatchSize
+	}
+	return defaultSyncJobChunkSize
+}
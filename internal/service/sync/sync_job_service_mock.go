@@ -0,0 +1,75 @@
+package service_sync
+
+import (
+	"context"
+	"database/sql"
+	pagination_dto "flight-api/internal/dto/pagination"
+	queryparams "flight-api/internal/dto/query_params"
+	sync_dto "flight-api/internal/dto/sync"
+	"flight-api/util"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type SyncJobServiceMock struct {
+	Mock mock.Mock
+}
+
+func (m *SyncJobServiceMock) Start(ctx context.Context, req sync_dto.SyncAirportRequest) (sync_dto.SyncJobDto, error) {
+	args := m.Mock.Called(ctx, req)
+	var out sync_dto.SyncJobDto
+	if v, ok := args.Get(0).(sync_dto.SyncJobDto); ok {
+		out = v
+	}
+	return out, args.Error(1)
+}
+
+func (m *SyncJobServiceMock) Queue(ctx context.Context, tx *sql.Tx, req sync_dto.SyncAirportRequest) (sync_dto.SyncJobDto, error) {
+	args := m.Mock.Called(ctx, util.MockTx{Tx: tx}, req)
+	var out sync_dto.SyncJobDto
+	if v, ok := args.Get(0).(sync_dto.SyncJobDto); ok {
+		out = v
+	}
+	return out, args.Error(1)
+}
+
+func (m *SyncJobServiceMock) Launch(ctx context.Context, id string) error {
+	args := m.Mock.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *SyncJobServiceMock) FindByID(ctx context.Context, id string) (sync_dto.SyncJobDto, error) {
+	args := m.Mock.Called(ctx, id)
+	var out sync_dto.SyncJobDto
+	if v, ok := args.Get(0).(sync_dto.SyncJobDto); ok {
+		out = v
+	}
+	return out, args.Error(1)
+}
+
+func (m *SyncJobServiceMock) FindAll(ctx context.Context, query queryparams.QueryParams, status string) (pagination_dto.PaginationDto, error) {
+	args := m.Mock.Called(ctx, query, status)
+	var out pagination_dto.PaginationDto
+	if v, ok := args.Get(0).(pagination_dto.PaginationDto); ok {
+		out = v
+	}
+	return out, args.Error(1)
+}
+
+func (m *SyncJobServiceMock) Cancel(ctx context.Context, id string) (sync_dto.SyncJobDto, error) {
+	args := m.Mock.Called(ctx, id)
+	var out sync_dto.SyncJobDto
+	if v, ok := args.Get(0).(sync_dto.SyncJobDto); ok {
+		out = v
+	}
+	return out, args.Error(1)
+}
+
+func (m *SyncJobServiceMock) Resume(ctx context.Context) {
+	m.Mock.Called(ctx)
+}
+
+func (m *SyncJobServiceMock) Shutdown(ctx context.Context) error {
+	args := m.Mock.Called(ctx)
+	return args.Error(0)
+}
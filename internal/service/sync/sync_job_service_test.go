@@ -0,0 +1,366 @@
+package service_sync
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"flight-api/config"
+	sync_dto "flight-api/internal/dto/sync"
+	"flight-api/internal/model"
+	repo_sync_job "flight-api/internal/repository/sync_job"
+	"flight-api/pkg/logger"
+	"flight-api/util"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var anyTx = mock.AnythingOfType("util.MockTx")
+
+type jobDeps struct {
+	cfg    *config.Config
+	db     *sql.DB
+	dbmock sqlmock.Sqlmock
+	repo   *repo_sync_job.SyncJobRepositoryMock
+	sync   *SyncServiceMock
+	svc    ISyncJobService
+}
+
+func newJobDeps(t *testing.T) *jobDeps {
+	t.Helper()
+	db, dbmock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+	// The runner's transactions interleave with the test's own calls.
+	dbmock.MatchExpectationsInOrder(false)
+
+	d := &jobDeps{
+		// One code per chunk unless a test says otherwise.
+		cfg:    &config.Config{AviationBatchSize: 1},
+		db:     db,
+		dbmock: dbmock,
+		repo:   &repo_sync_job.SyncJobRepositoryMock{Mock: mock.Mock{}},
+		sync:   &SyncServiceMock{Mock: mock.Mock{}},
+	}
+	d.svc = NewSyncJobService(logger.NewLogger(logger.INFO_DEBUG_LEVEL), d.cfg, util.NewValidator(), db, d.sync, d.repo)
+	return d
+}
+
+// expectTxs expects n committed transactions.
+func (d *jobDeps) expectTxs(n int) {
+	for i := 0; i < n; i++ {
+		d.dbmock.ExpectBegin()
+		d.dbmock.ExpectCommit()
+	}
+}
+
+func newJob(codes []string, results []sync_dto.SyncAirportResponse) model.SyncJob {
+	id := uuid.New()
+	raw, _ := json.Marshal(results)
+	return model.SyncJob{
+		ID:        &id,
+		Status:    util.Ptr("queued"),
+		ICAOCodes: codes,
+		Mode:      util.Ptr("insert"),
+		Total:     util.Ptr(int64(len(codes))),
+		Processed: util.Ptr(int64(len(results))),
+		Results:   raw,
+	}
+}
+
+func insertReq(code string) sync_dto.SyncAirportRequest {
+	return sync_dto.SyncAirportRequest{ICAOCodes: []string{code}, Mode: "insert"}
+}
+
+// jobReq is the request a job syncs its codes with, recorded as its run.
+func jobReq(job model.SyncJob, req sync_dto.SyncAirportRequest) sync_dto.SyncAirportRequest {
+	req.Trigger = "job"
+	req.JobID = job.ID
+	return req
+}
+
+func syncOne(code string, status string) []sync_dto.SyncAirportResponse {
+	return []sync_dto.SyncAirportResponse{{ICAOCode: code, Status: status}}
+}
+
+func waitFor(t *testing.T, done <-chan struct{}) {
+	t.Helper()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("sync job did not finish")
+	}
+}
+
+func TestSyncJobService_Start_RunsToCompletion(t *testing.T) {
+	d := newJobDeps(t)
+	d.cfg.AviationBatchSize = 2
+	job := newJob([]string{"KJFK", "KSEA", "KLAX"}, nil)
+	id := job.ID.String()
+	d.expectTxs(5)
+
+	d.repo.Mock.On("Insert", mock.Anything, anyTx, mock.MatchedBy(func(m model.SyncJob) bool {
+		return len(m.ICAOCodes) == 3
+	})).Return(job, nil).Once()
+	d.repo.Mock.On("MarkRunning", mock.Anything, anyTx, id).Return(true, nil).Once()
+	// The codes are synced in chunks of the Aviation API batch size.
+	d.sync.Mock.On("SyncAirports", mock.Anything, jobReq(job, sync_dto.SyncAirportRequest{ICAOCodes: []string{"KJFK", "KSEA"}, Mode: "insert"})).
+		Return(append(syncOne("KJFK", "Conflict"), syncOne("KSEA", "Inserted")...), nil).Once()
+	d.sync.Mock.On("SyncAirports", mock.Anything, jobReq(job, insertReq("KLAX"))).
+		Return(nil, errors.New("db down")).Once()
+	d.repo.Mock.On("UpdateProgress", mock.Anything, anyTx, id, 2, mock.Anything).Return(true, nil).Once()
+	d.repo.Mock.On("UpdateProgress", mock.Anything, anyTx, id, 3, mock.MatchedBy(func(raw []byte) bool {
+		results := sync_dto.DecodeSyncJobResults(raw)
+		return len(results) == 3 && results[2].ICAOCode == "KLAX" && results[2].Status == "Error"
+	})).Return(true, nil).Once()
+
+	// The job is recorded as one run with the results of both chunks.
+	d.sync.Mock.On("RecordRun", mock.Anything, jobReq(job, sync_dto.SyncAirportRequest{ICAOCodes: []string{"KJFK", "KSEA", "KLAX"}, Mode: "insert"}),
+		mock.MatchedBy(func(results []sync_dto.SyncAirportResponse) bool {
+			return len(results) == 3 && results[0].ICAOCode == "KJFK" && results[2].ICAOCode == "KLAX"
+		}), mock.Anything).Once()
+
+	done := make(chan struct{})
+	d.repo.Mock.On("Finish", mock.Anything, anyTx, id, "completed", (*string)(nil)).
+		Return(true, nil).Once().Run(func(mock.Arguments) { close(done) })
+
+	out, err := d.svc.Start(context.Background(), sync_dto.SyncAirportRequest{ICAOCodes: []string{"KJFK", "KSEA", "KJFK", "KLAX"}})
+	require.NoError(t, err)
+	require.Equal(t, id, out.ID)
+	require.Equal(t, "queued", out.Status)
+
+	waitFor(t, done)
+	require.NoError(t, d.svc.Shutdown(context.Background()))
+	d.repo.Mock.AssertExpectations(t)
+	d.sync.Mock.AssertExpectations(t)
+	require.NoError(t, d.dbmock.ExpectationsWereMet())
+}
+
+func TestSyncJobService_Start_InvalidRequest(t *testing.T) {
+	d := newJobDeps(t)
+
+	_, err := d.svc.Start(context.Background(), sync_dto.SyncAirportRequest{})
+	require.Error(t, err)
+
+	_, err = d.svc.Start(context.Background(), sync_dto.SyncAirportRequest{ICAOCodes: []string{"KJFK"}, Mode: "upsert"})
+	require.Error(t, err)
+
+	_, err = d.svc.Start(context.Background(), sync_dto.SyncAirportRequest{ICAOCodes: []string{"KJFK"}, Mode: "refresh", Fields: []string{"id"}})
+	require.ErrorIs(t, err, util.ErrBadRequest)
+	d.repo.Mock.AssertNotCalled(t, "Insert", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestSyncJobService_QueueThenLaunch(t *testing.T) {
+	d := newJobDeps(t)
+	job := newJob([]string{"KJFK"}, nil)
+	job.Mode = util.Ptr("refresh")
+	job.Trigger = util.Ptr("schedule")
+	id := job.ID.String()
+
+	// Queue writes in the caller's transaction and runs nothing.
+	d.dbmock.ExpectBegin()
+	d.dbmock.ExpectCommit()
+	d.repo.Mock.On("Insert", mock.Anything, anyTx, mock.MatchedBy(func(m model.SyncJob) bool {
+		return *m.Trigger == "schedule"
+	})).Return(job, nil).Once()
+
+	tx, err := d.db.Begin()
+	require.NoError(t, err)
+	out, err := d.svc.Queue(context.Background(), tx, sync_dto.SyncAirportRequest{ICAOCodes: []string{"KJFK"}, Mode: "refresh", Trigger: "schedule"})
+	require.NoError(t, err)
+	require.Equal(t, id, out.ID)
+	require.NoError(t, tx.Commit())
+	d.repo.Mock.AssertNotCalled(t, "MarkRunning", mock.Anything, mock.Anything, mock.Anything)
+
+	// Launch runs it once the caller has committed, its runs recorded as
+	// triggered by the schedule.
+	d.expectTxs(4)
+	d.repo.Mock.On("FindByID", mock.Anything, anyTx, id).Return(job, nil).Once()
+	d.repo.Mock.On("MarkRunning", mock.Anything, anyTx, id).Return(true, nil).Once()
+	d.sync.Mock.On("SyncAirports", mock.Anything, sync_dto.SyncAirportRequest{ICAOCodes: []string{"KJFK"}, Mode: "refresh", Trigger: "schedule", JobID: job.ID}).
+		Return(syncOne("KJFK", "Updated"), nil).Once()
+	d.repo.Mock.On("UpdateProgress", mock.Anything, anyTx, id, 1, mock.Anything).Return(true, nil).Once()
+	d.sync.Mock.On("RecordRun", mock.Anything, sync_dto.SyncAirportRequest{ICAOCodes: []string{"KJFK"}, Mode: "refresh", Trigger: "schedule", JobID: job.ID},
+		syncOne("KJFK", "Updated"), mock.Anything).Once()
+	done := make(chan struct{})
+	d.repo.Mock.On("Finish", mock.Anything, anyTx, id, "completed", (*string)(nil)).
+		Return(true, nil).Once().Run(func(mock.Arguments) { close(done) })
+
+	require.NoError(t, d.svc.Launch(context.Background(), id))
+
+	waitFor(t, done)
+	require.NoError(t, d.svc.Shutdown(context.Background()))
+	d.repo.Mock.AssertExpectations(t)
+	require.NoError(t, d.dbmock.ExpectationsWereMet())
+}
+
+func TestSyncJobService_Queue_InvalidRequest(t *testing.T) {
+	d := newJobDeps(t)
+
+	_, err := d.svc.Queue(context.Background(), nil, sync_dto.SyncAirportRequest{})
+	require.Error(t, err)
+	d.repo.Mock.AssertNotCalled(t, "Insert", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestSyncJobService_Resume_SkipsRecordedResultsAndStopsWhenCanceledElsewhere(t *testing.T) {
+	d := newJobDeps(t)
+	job := newJob([]string{"KJFK", "KSEA", "KLAX"}, syncOne("KJFK", "Updated"))
+	job.Mode = util.Ptr("refresh")
+	job.Fields = []string{"name", "city"}
+	id := job.ID.String()
+	d.expectTxs(3)
+
+	d.repo.Mock.On("ClaimResumable", mock.Anything, anyTx, mock.Anything).Return([]model.SyncJob{job}, nil).Once()
+	d.repo.Mock.On("MarkRunning", mock.Anything, anyTx, id).Return(true, nil).Once()
+	d.sync.Mock.On("SyncAirports", mock.Anything, jobReq(job, sync_dto.SyncAirportRequest{
+		ICAOCodes: []string{"KSEA"},
+		Mode:      "refresh",
+		Fields:    []string{"name", "city"},
+	})).Return(syncOne("KSEA", "Updated"), nil).Once()
+
+	// The job was canceled through another instance; its run covers the
+	// code synced before the restart too.
+	d.repo.Mock.On("UpdateProgress", mock.Anything, anyTx, id, 2, mock.Anything).Return(false, nil).Once()
+	done := make(chan struct{})
+	d.sync.Mock.On("RecordRun", mock.Anything, jobReq(job, sync_dto.SyncAirportRequest{
+		ICAOCodes: []string{"KJFK", "KSEA", "KLAX"},
+		Mode:      "refresh",
+		Fields:    []string{"name", "city"},
+	}), append(syncOne("KJFK", "Updated"), syncOne("KSEA", "Updated")...), mock.Anything).
+		Once().Run(func(mock.Arguments) { close(done) })
+
+	d.svc.Resume(context.Background())
+	waitFor(t, done)
+	require.NoError(t, d.svc.Shutdown(context.Background()))
+
+	// KLAX is never synced and the job is left canceled.
+	d.sync.Mock.AssertNumberOfCalls(t, "SyncAirports", 1)
+	d.repo.Mock.AssertNumberOfCalls(t, "Finish", 0)
+	d.repo.Mock.AssertExpectations(t)
+	require.NoError(t, d.dbmock.ExpectationsWereMet())
+}
+
+func TestSyncJobService_Shutdown_RequeuesRunningJob(t *testing.T) {
+	d := newJobDeps(t)
+	job := newJob([]string{"KJFK"}, nil)
+	id := job.ID.String()
+	d.expectTxs(3)
+
+	started := make(chan struct{})
+	d.repo.Mock.On("Insert", mock.Anything, anyTx, mock.Anything).Return(job, nil).Once()
+	d.repo.Mock.On("MarkRunning", mock.Anything, anyTx, id).Return(true, nil).Once()
+	d.sync.Mock.On("SyncAirports", mock.Anything, mock.Anything).
+		Return(syncOne("KJFK", "Error"), nil).Once().
+		Run(func(args mock.Arguments) {
+			close(started)
+			<-args.Get(0).(context.Context).Done()
+		})
+	d.repo.Mock.On("Requeue", mock.Anything, anyTx, id).Return(true, nil).Once()
+
+	_, err := d.svc.Start(context.Background(), insertReq("KJFK"))
+	require.NoError(t, err)
+
+	waitFor(t, started)
+	require.NoError(t, d.svc.Shutdown(context.Background()))
+
+	// The interrupted code is not recorded, so it is synced again on resume.
+	d.repo.Mock.AssertNumberOfCalls(t, "UpdateProgress", 0)
+	d.repo.Mock.AssertExpectations(t)
+	require.NoError(t, d.dbmock.ExpectationsWereMet())
+}
+
+func TestSyncJobService_Shutdown_KeepsCodesSyncedBeforeInterrupt(t *testing.T) {
+	d := newJobDeps(t)
+	d.cfg.AviationBatchSize = 3
+	job := newJob([]string{"KJFK", "KSEA", "KLAX"}, nil)
+	id := job.ID.String()
+	d.expectTxs(4)
+
+	started := make(chan struct{})
+	d.repo.Mock.On("Insert", mock.Anything, anyTx, mock.Anything).Return(job, nil).Once()
+	d.repo.Mock.On("MarkRunning", mock.Anything, anyTx, id).Return(true, nil).Once()
+	d.sync.Mock.On("SyncAirports", mock.Anything, mock.Anything).
+		Return([]sync_dto.SyncAirportResponse{
+			{ICAOCode: "KSEA", Status: "Conflict"},
+			{ICAOCode: "KJFK", Status: "Inserted"},
+			{ICAOCode: "KLAX", Status: "Error", Message: "context canceled"},
+		}, nil).Once().
+		Run(func(args mock.Arguments) {
+			close(started)
+			<-args.Get(0).(context.Context).Done()
+		})
+
+	// Only the codes the sync got through are recorded.
+	var recorded []sync_dto.SyncAirportResponse
+	d.repo.Mock.On("UpdateProgress", mock.Anything, anyTx, id, 2, mock.Anything).
+		Return(true, nil).Once().
+		Run(func(args mock.Arguments) {
+			require.NoError(t, json.Unmarshal(args.Get(4).([]byte), &recorded))
+		})
+	d.repo.Mock.On("Requeue", mock.Anything, anyTx, id).Return(true, nil).Once()
+
+	_, err := d.svc.Start(context.Background(), insertReq("KJFK"))
+	require.NoError(t, err)
+
+	waitFor(t, started)
+	require.NoError(t, d.svc.Shutdown(context.Background()))
+
+	require.Equal(t, []sync_dto.SyncAirportResponse{
+		{ICAOCode: "KSEA", Status: "Conflict"},
+		{ICAOCode: "KJFK", Status: "Inserted"},
+	}, recorded)
+	require.Equal(t, []string{"KLAX"}, pendingCodes(job.ICAOCodes, recorded))
+	d.repo.Mock.AssertExpectations(t)
+	require.NoError(t, d.dbmock.ExpectationsWereMet())
+}
+
+func TestSyncJobService_Cancel(t *testing.T) {
+	job := newJob([]string{"KJFK"}, nil)
+	id := job.ID.String()
+
+	t.Run("queued job", func(t *testing.T) {
+		d := newJobDeps(t)
+		d.expectTxs(1)
+
+		canceled := job
+		canceled.Status = util.Ptr("canceled")
+		d.repo.Mock.On("FindByID", mock.Anything, anyTx, id).Return(job, nil).Once()
+		d.repo.Mock.On("Cancel", mock.Anything, anyTx, id).Return(true, nil).Once()
+		d.repo.Mock.On("FindByID", mock.Anything, anyTx, id).Return(canceled, nil).Once()
+
+		out, err := d.svc.Cancel(context.Background(), id)
+		require.NoError(t, err)
+		require.Equal(t, "canceled", out.Status)
+		require.NoError(t, d.dbmock.ExpectationsWereMet())
+	})
+
+	t.Run("finished job", func(t *testing.T) {
+		d := newJobDeps(t)
+		d.dbmock.ExpectBegin()
+		d.dbmock.ExpectRollback()
+
+		d.repo.Mock.On("FindByID", mock.Anything, anyTx, id).Return(job, nil).Once()
+		d.repo.Mock.On("Cancel", mock.Anything, anyTx, id).Return(false, nil).Once()
+
+		_, err := d.svc.Cancel(context.Background(), id)
+		require.ErrorIs(t, err, util.ErrConflict)
+		require.NoError(t, d.dbmock.ExpectationsWereMet())
+	})
+
+	t.Run("unknown job", func(t *testing.T) {
+		d := newJobDeps(t)
+		d.dbmock.ExpectBegin()
+		d.dbmock.ExpectRollback()
+
+		d.repo.Mock.On("FindByID", mock.Anything, anyTx, id).Return(model.SyncJob{}, util.ErrNotFound).Once()
+
+		_, err := d.svc.Cancel(context.Background(), id)
+		require.ErrorIs(t, err, util.ErrNotFound)
+		d.repo.Mock.AssertNotCalled(t, "Cancel", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
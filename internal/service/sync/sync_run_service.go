@@ -0,0 +1,15 @@
+package service_sync
+
+import (
+	"context"
+	pagination_dto "flight-api/internal/dto/pagination"
+	queryparams "flight-api/internal/dto/query_params"
+	sync_dto "flight-api/internal/dto/sync"
+)
+
+// ISyncRunService reports the history of airport sync runs, to audit what
+// each one changed and find the codes to sync again.
+type ISyncRunService interface {
+	FindAll(ctx context.Context, query queryparams.QueryParams, filter sync_dto.SyncRunFilter) (pagination_dto.PaginationDto, error)
+	FindByID(ctx context.Context, id string, outcome string) (sync_dto.SyncRunDto, error)
+}
@@ -0,0 +1,113 @@
+package service_sync
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	pagination_dto "flight-api/internal/dto/pagination"
+	queryparams "flight-api/internal/dto/query_params"
+	sync_dto "flight-api/internal/dto/sync"
+	"flight-api/internal/enum"
+	repo_sync_run "flight-api/internal/repository/sync_run"
+	"flight-api/pkg/logger"
+	"flight-api/util"
+	"fmt"
+)
+
+type SyncRunService struct {
+	logger            *logger.Logger
+	db                *sql.DB
+	syncRunRepository repo_sync_run.ISyncRunRepository
+}
+
+func NewSyncRunService(
+	logger *logger.Logger,
+	db *sql.DB,
+	syncRunRepository repo_sync_run.ISyncRunRepository,
+) ISyncRunService {
+	return &SyncRunService{
+		logger:            logger,
+		db:                db,
+		syncRunRepository: syncRunRepository,
+	}
+}
+
+// FindAll lists runs newest first, optionally narrowed by filter.
+func (s *SyncRunService) FindAll(ctx context.Context, query queryparams.QueryParams, filter sync_dto.SyncRunFilter) (_ pagination_dto.PaginationDto, err error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		s.logger.Errorf("[FindAll] Failed to begin transaction: %v", err)
+		return pagination_dto.PaginationDto{}, util.NewAppError(util.ErrInternalServer, "Failed to begin transaction", err)
+	}
+	defer util.CommitOrRollbackErr(tx, &err)
+
+	args := map[string]interface{}{
+		"limit":    query.Limit,
+		"offset":   query.Offset,
+		"outcomes": syncRunOutcomes(filter.Outcome),
+		"trigger":  filter.Trigger,
+		"job_id":   filter.JobID,
+	}
+	runs, total, err := s.syncRunRepository.FindAll(ctx, tx, args)
+	if err != nil {
+		s.logger.Errorf("[FindAll] Failed to fetch sync runs: %v", err)
+		return pagination_dto.PaginationDto{}, util.NewAppError(util.ErrInternalServer, "Failed to fetch sync runs", err)
+	}
+
+	hasNext := (query.Offset + query.Limit) < total
+
+	response := pagination_dto.PaginationDto{
+		Object:  "pagination",
+		Records: util.ToInterfaces(sync_dto.ToSyncRunDtos(runs)),
+		Total:   total,
+		Meta: &pagination_dto.PaginationMetaDto{
+			Limit: query.Limit,
+			Page:  query.Page,
+			Next:  hasNext,
+		},
+	}
+
+	return response, nil
+}
+
+// FindByID returns a run with the outcome of each of its codes, optionally
+// only the codes that ended in outcome.
+func (s *SyncRunService) FindByID(ctx context.Context, id string, outcome string) (_ sync_dto.SyncRunDto, err error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		s.logger.Errorf("[FindByID] Failed to begin transaction: %v", err)
+		return sync_dto.SyncRunDto{}, util.NewAppError(util.ErrInternalServer, "Failed to begin transaction", err)
+	}
+	defer util.CommitOrRollbackErr(tx, &err)
+
+	run, err := s.syncRunRepository.FindByID(ctx, tx, id)
+	if errors.Is(err, util.ErrNotFound) {
+		return sync_dto.SyncRunDto{}, util.NewAppError(util.ErrNotFound, fmt.Sprintf("Sync run with ID %s not found", id), nil)
+	} else if err != nil {
+		s.logger.Errorf("[FindByID] Failed to fetch sync run %s: %v", id, err)
+		return sync_dto.SyncRunDto{}, util.NewAppError(util.ErrInternalServer, "Failed to fetch sync run", err)
+	}
+
+	items, err := s.syncRunRepository.FindItems(ctx, tx, id, syncRunOutcomes(outcome))
+	if err != nil {
+		s.logger.Errorf("[FindByID] Failed to fetch items of sync run %s: %v", id, err)
+		return sync_dto.SyncRunDto{}, util.NewAppError(util.ErrInternalServer, "Failed to fetch sync run items", err)
+	}
+
+	dto := sync_dto.ToSyncRunDto(run)
+	dto.Items = sync_dto.ToSyncRunItemDtos(items)
+	return dto, nil
+}
+
+// syncRunOutcomes expands an outcome filter into the outcomes it keeps:
+// none for no filter, and every failure outcome for failed.
+func syncRunOutcomes(outcome string) []string {
+	switch {
+	case outcome == "":
+		return []string{}
+	case enum.SyncRunOutcomeEnum(outcome) == enum.SYNC_RUN_FAILED:
+		return enum.SyncRunFailureOutcomes()
+	default:
+		return []string{outcome}
+	}
+}
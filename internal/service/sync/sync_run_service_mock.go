@@ -0,0 +1,32 @@
+package service_sync
+
+import (
+	"context"
+	pagination_dto "flight-api/internal/dto/pagination"
+	queryparams "flight-api/internal/dto/query_params"
+	sync_dto "flight-api/internal/dto/sync"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type SyncRunServiceMock struct {
+	Mock mock.Mock
+}
+
+func (m *SyncRunServiceMock) FindAll(ctx context.Context, query queryparams.QueryParams, filter sync_dto.SyncRunFilter) (pagination_dto.PaginationDto, error) {
+	args := m.Mock.Called(ctx, query, filter)
+	var out pagination_dto.PaginationDto
+	if v, ok := args.Get(0).(pagination_dto.PaginationDto); ok {
+		out = v
+	}
+	return out, args.Error(1)
+}
+
+func (m *SyncRunServiceMock) FindByID(ctx context.Context, id string, outcome string) (sync_dto.SyncRunDto, error) {
+	args := m.Mock.Called(ctx, id, outcome)
+	var out sync_dto.SyncRunDto
+	if v, ok := args.Get(0).(sync_dto.SyncRunDto); ok {
+		out = v
+	}
+	return out, args.Error(1)
+}
@@ -0,0 +1,86 @@
+package service_sync
+
+import (
+	"context"
+	queryparams "flight-api/internal/dto/query_params"
+	sync_dto "flight-api/internal/dto/sync"
+	"flight-api/internal/model"
+	repo_sync_run "flight-api/internal/repository/sync_run"
+	"flight-api/pkg/logger"
+	"flight-api/util"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newRunService(t *testing.T) (ISyncRunService, *repo_sync_run.SyncRunRepositoryMock, sqlmock.Sqlmock) {
+	t.Helper()
+	db, dbmock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	repo := &repo_sync_run.SyncRunRepositoryMock{Mock: mock.Mock{}}
+	return NewSyncRunService(logger.NewLogger(logger.INFO_DEBUG_LEVEL), db, repo), repo, dbmock
+}
+
+func TestSyncRunService_FindAll_Failed(t *testing.T) {
+	svc, repo, dbmock := newRunService(t)
+	id := uuid.New()
+
+	dbmock.ExpectBegin()
+	repo.Mock.On("FindAll", mock.Anything, anyTx, map[string]interface{}{
+		"limit":    10,
+		"offset":   10,
+		"outcomes": []string{"error", "invalid", "not_found", "skipped", "rolled_back"},
+		"trigger":  "schedule",
+		"job_id":   id.String(),
+	}).Return([]model.SyncRun{{ID: &id, Counts: []byte(`{"error":2}`)}}, 11, nil).Once()
+	dbmock.ExpectCommit()
+
+	out, err := svc.FindAll(context.Background(), queryparams.QueryParams{Limit: 10, Offset: 10, Page: 2},
+		sync_dto.SyncRunFilter{Outcome: "failed", Trigger: "schedule", JobID: id.String()})
+	require.NoError(t, err)
+	assert.Equal(t, 11, out.Total)
+	assert.False(t, out.Meta.Next)
+	require.Len(t, out.Records, 1)
+	assert.Equal(t, map[string]int{"error": 2}, out.Records[0].(sync_dto.SyncRunDto).Counts)
+
+	require.NoError(t, dbmock.ExpectationsWereMet())
+}
+
+func TestSyncRunService_FindByID(t *testing.T) {
+	svc, repo, dbmock := newRunService(t)
+	id := uuid.New()
+
+	dbmock.ExpectBegin()
+	repo.Mock.On("FindByID", mock.Anything, anyTx, id.String()).
+		Return(model.SyncRun{ID: &id, ICAOCodes: []string{"KJFK", "KSEA"}, Counts: []byte(`{"updated":1,"error":1}`)}, nil).Once()
+	repo.Mock.On("FindItems", mock.Anything, anyTx, id.String(), []string{"updated"}).
+		Return([]model.SyncRunItem{{ICAOCode: util.Ptr("KJFK"), Outcome: util.Ptr("updated"), Status: util.Ptr("Updated")}}, nil).Once()
+	dbmock.ExpectCommit()
+
+	out, err := svc.FindByID(context.Background(), id.String(), "updated")
+	require.NoError(t, err)
+	assert.Equal(t, id.String(), out.ID)
+	assert.Equal(t, []sync_dto.SyncRunItemDto{{ICAOCode: "KJFK", Outcome: "updated", Status: "Updated"}}, out.Items)
+
+	require.NoError(t, dbmock.ExpectationsWereMet())
+}
+
+func TestSyncRunService_FindByID_NotFound(t *testing.T) {
+	svc, repo, dbmock := newRunService(t)
+
+	dbmock.ExpectBegin()
+	repo.Mock.On("FindByID", mock.Anything, anyTx, "missing").Return(nil, util.ErrNotFound).Once()
+	dbmock.ExpectRollback()
+
+	_, err := svc.FindByID(context.Background(), "missing", "")
+	var appErr *util.AppError
+	require.ErrorAs(t, err, &appErr)
+	assert.ErrorIs(t, err, util.ErrNotFound)
+	repo.Mock.AssertNotCalled(t, "FindItems", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,13 @@
+package service_sync
+
+import (
+	"context"
+	sync_dto "flight-api/internal/dto/sync"
+)
+
+// ISyncScheduleService starts a refresh of every stored airport as a sync
+// job whenever one of the schedules in SYNC_SCHEDULES comes due.
+type ISyncScheduleService interface {
+	FindAll(ctx context.Context) ([]sync_dto.SyncScheduleDto, error)
+	Run(ctx context.Context)
+}
@@ -0,0 +1,305 @@
+package service_sync
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"flight-api/config"
+	sync_dto "flight-api/internal/dto/sync"
+	"flight-api/internal/enum"
+	"flight-api/internal/model"
+	repo_airport "flight-api/internal/repository/airport"
+	repo_sync_schedule "flight-api/internal/repository/sync_schedule"
+	"flight-api/pkg/logger"
+	"flight-api/pkg/schedule"
+	"flight-api/util"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// maxSyncScheduleWait bounds how long the scheduler sleeps, so a schedule
+// another replica ran is picked up from the database in good time.
+const maxSyncScheduleWait = time.Minute
+
+type syncSchedule struct {
+	name       string
+	expression string
+	schedule   schedule.Schedule
+}
+
+// parseSyncSchedules reads SYNC_SCHEDULES: name=expression pairs separated
+// by semicolons, such as "airac=@airac;nightly=0 3 * * *". An empty value
+// configures no schedules.
+func parseSyncSchedules(spec string) ([]syncSchedule, error) {
+	schedules := []syncSchedule{}
+	seen := map[string]bool{}
+	for _, entry := range strings.Split(spec, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		name, expression, ok := strings.Cut(entry, "=")
+		name, expression = strings.TrimSpace(name), strings.TrimSpace(expression)
+		if !ok || name == "" || len(name) > 64 {
+			return nil, fmt.Errorf("sync schedule %q: expected name=expression with a name of at most 64 characters", entry)
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("sync schedule %q configured twice", name)
+		}
+		seen[name] = true
+
+		sched, err := schedule.Parse(expression)
+		if err != nil {
+			return nil, fmt.Errorf("sync schedule %q: %w", name, err)
+		}
+		schedules = append(schedules, syncSchedule{name: name, expression: expression, schedule: sched})
+	}
+	return schedules, nil
+}
+
+// SyncScheduleService runs the schedules in SYNC_SCHEDULES. Every replica
+// runs the scheduler; a Postgres advisory lock and the last run stored in
+// sync_schedules make sure each due run starts one job only.
+type SyncScheduleService struct {
+	logger                 *logger.Logger
+	db                     *sql.DB
+	syncScheduleRepository repo_sync_schedule.ISyncScheduleRepository
+	airportRepository      repo_airport.IAirportRepository
+	syncJobService         ISyncJobService
+	schedules              []syncSchedule
+
+	// A schedule that never ran is first due after startedAt.
+	startedAt time.Time
+	now       func() time.Time
+}
+
+func NewSyncScheduleService(
+	logger *logger.Logger,
+	cfg *config.Config,
+	db *sql.DB,
+	syncScheduleRepository repo_sync_schedule.ISyncScheduleRepository,
+	airportRepository repo_airport.IAirportRepository,
+	syncJobService ISyncJobService,
+) (ISyncScheduleService, error) {
+	schedules, err := parseSyncSchedules(cfg.SyncSchedules)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SyncScheduleService{
+		logger:                 logger,
+		db:                     db,
+		syncScheduleRepository: syncScheduleRepository,
+		airportRepository:      airportRepository,
+		syncJobService:         syncJobService,
+		schedules:              schedules,
+		startedAt:              time.Now(),
+		now:                    time.Now,
+	}, nil
+}
+
+// FindAll lists the configured schedules in configuration order with their
+// next run and the outcome of their last one.
+func (s *SyncScheduleService) FindAll(ctx context.Context) ([]sync_dto.SyncScheduleDto, error) {
+	states, err := s.states(ctx)
+	if err != nil {
+		s.logger.Errorf("[FindAll] Failed to find sync schedules: %v", err)
+		return nil, util.NewAppError(util.ErrInternalServer, "Failed to find sync schedules", err)
+	}
+
+	dtos := make([]sync_dto.SyncScheduleDto, 0, len(s.schedules))
+	for _, sched := range s.schedules {
+		dto := sync_dto.ToSyncScheduleDto(states[sched.name])
+		dto.Name = sched.name
+		dto.Expression = sched.expression
+		if due := s.due(sched, states[sched.name]); !due.IsZero() {
+			dto.NextRunAt = &due
+			if sched.expression == "@airac" {
+				cycle := schedule.AIRACCycleAt(due)
+				dto.AIRACCycle = &cycle.Ident
+			}
+		}
+		dtos = append(dtos, dto)
+	}
+	return dtos, nil
+}
+
+// Run starts the due schedules until ctx is done. It returns at once when
+// no schedules are configured.
+func (s *SyncScheduleService) Run(ctx context.Context) {
+	if len(s.schedules) == 0 {
+		s.logger.Info("[Run] No sync schedules configured")
+		return
+	}
+
+	for {
+		timer := time.NewTimer(s.RunDue(ctx))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+	}
+}
+
+// RunDue starts every schedule that is due and returns how long to wait
+// before the next one is.
+func (s *SyncScheduleService) RunDue(ctx context.Context) time.Duration {
+	states, err := s.states(ctx)
+	if err != nil {
+		s.logger.Errorf("[RunDue] Failed to find sync schedules: %v", err)
+		return maxSyncScheduleWait
+	}
+
+	wait := maxSyncScheduleWait
+	for _, sched := range s.schedules {
+		due := s.due(sched, states[sched.name])
+		if due.IsZero() {
+			continue
+		}
+
+		if !due.After(s.now()) {
+			s.trigger(ctx, sched, due)
+			continue
+		}
+		wait = min(wait, due.Sub(s.now()))
+	}
+	return wait
+}
+
+// due is when a schedule runs next: the first time after its last run, or
+// after the scheduler started for a schedule that never ran. A run missed
+// while no replica was up is due at once, and runs only once.
+func (s *SyncScheduleService) due(sched syncSchedule, state model.SyncSchedule) time.Time {
+	after := s.startedAt
+	if state.LastRunAt != nil {
+		after = *state.LastRunAt
+	}
+	return sched.schedule.Next(after)
+}
+
+func (s *SyncScheduleService) states(ctx context.Context) (_ map[string]model.SyncSchedule, err error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer util.CommitOrRollbackErr(tx, &err)
+
+	schedules, err := s.syncScheduleRepository.FindAll(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	states := make(map[string]model.SyncSchedule, len(schedules))
+	for _, state := range schedules {
+		states[util.DerefPtr(state.Name)] = state
+	}
+	return states, nil
+}
+
+// trigger runs a due schedule under its advisory lock. The schedule is
+// skipped when another replica holds the lock or has already run it. The
+// job is queued in the same transaction that records the run, so a run that
+// fails to be recorded queues nothing, and it is launched once both are
+// stored.
+func (s *SyncScheduleService) trigger(ctx context.Context, sched syncSchedule, due time.Time) {
+	var jobID string
+	err := func() (err error) {
+		tx, err := s.db.Begin()
+		if err != nil {
+			return err
+		}
+		defer util.CommitOrRollbackErr(tx, &err)
+
+		locked, err := s.syncScheduleRepository.TryLock(ctx, tx, sched.name)
+		if err != nil {
+			return err
+		}
+		if !locked {
+			s.logger.Debugf("[trigger] Sync schedule %s is running on another replica", sched.name)
+			return nil
+		}
+
+		state, err := s.syncScheduleRepository.FindByName(ctx, tx, sched.name)
+		if err != nil && !errors.Is(err, util.ErrNotFound) {
+			return err
+		}
+		if state.LastRunAt != nil && !state.LastRunAt.Before(due) {
+			s.logger.Debugf("[trigger] Sync schedule %s already ran on another replica", sched.name)
+			return nil
+		}
+
+		now := s.now()
+		run := model.SyncSchedule{
+			Name:       &sched.name,
+			Expression: &sched.expression,
+			LastRunAt:  &now,
+		}
+		if next := sched.schedule.Next(now); !next.IsZero() {
+			run.NextRunAt = &next
+		}
+		jobID = s.queue(ctx, tx, sched, &run)
+
+		_, err = s.syncScheduleRepository.Save(ctx, tx, run)
+		return err
+	}()
+	if err != nil {
+		s.logger.Errorf("[trigger] Failed to run sync schedule %s: %v", sched.name, err)
+		return
+	}
+	if jobID == "" {
+		return
+	}
+
+	// A job that fails to launch stays queued and is resumed on the next start.
+	if err := s.syncJobService.Launch(ctx, jobID); err != nil {
+		s.logger.Errorf("[trigger] Failed to launch sync job %s of sync schedule %s: %v", jobID, sched.name, err)
+	}
+}
+
+// queue queues a refresh of every stored airport, records the outcome on
+// run and returns the ID of the job it queued, if any.
+func (s *SyncScheduleService) queue(ctx context.Context, tx *sql.Tx, sched syncSchedule, run *model.SyncSchedule) string {
+	outcome := func(o enum.SyncScheduleOutcomeEnum, message string) {
+		run.LastOutcome = util.Ptr(o.String())
+		run.LastMessage = &message
+	}
+
+	codes, err := s.airportRepository.FindAllICAOIDs(ctx, tx)
+	if err != nil {
+		s.logger.Errorf("[queue] Failed to find airports for sync schedule %s: %v", sched.name, err)
+		outcome(enum.SYNC_SCHEDULE_FAILED, "Failed to find airports: "+err.Error())
+		return ""
+	}
+	if len(codes) == 0 {
+		outcome(enum.SYNC_SCHEDULE_SKIPPED, "No airports to refresh")
+		return ""
+	}
+
+	job, err := s.syncJobService.Queue(ctx, tx, sync_dto.SyncAirportRequest{
+		ICAOCodes: codes,
+		Mode:      enum.SYNC_MODE_REFRESH.String(),
+		Trigger:   enum.SYNC_RUN_TRIGGER_SCHEDULE.String(),
+	})
+	if err != nil {
+		s.logger.Errorf("[queue] Failed to queue sync job for sync schedule %s: %v", sched.name, err)
+		outcome(enum.SYNC_SCHEDULE_FAILED, "Failed to queue sync job: "+err.Error())
+		return ""
+	}
+
+	message := fmt.Sprintf("Refreshing %d airports", len(codes))
+	if sched.expression == "@airac" {
+		message += " for AIRAC cycle " + schedule.AIRACCycleAt(*run.LastRunAt).Ident
+	}
+	outcome(enum.SYNC_SCHEDULE_QUEUED, message)
+	if id, err := uuid.Parse(job.ID); err == nil {
+		run.LastJobID = &id
+	}
+	s.logger.Infof("[queue] Sync schedule %s queued sync job %s: %s", sched.name, job.ID, message)
+	return job.ID
+}
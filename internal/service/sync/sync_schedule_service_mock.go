@@ -0,0 +1,25 @@
+package service_sync
+
+import (
+	"context"
+	sync_dto "flight-api/internal/dto/sync"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type SyncScheduleServiceMock struct {
+	Mock mock.Mock
+}
+
+func (m *SyncScheduleServiceMock) FindAll(ctx context.Context) ([]sync_dto.SyncScheduleDto, error) {
+	args := m.Mock.Called(ctx)
+	var out []sync_dto.SyncScheduleDto
+	if v, ok := args.Get(0).([]sync_dto.SyncScheduleDto); ok {
+		out = v
+	}
+	return out, args.Error(1)
+}
+
+func (m *SyncScheduleServiceMock) Run(ctx context.Context) {
+	m.Mock.Called(ctx)
+}
@@ -0,0 +1,225 @@
+package service_sync
+
+import (
+	"context"
+	"errors"
+	"flight-api/config"
+	sync_dto "flight-api/internal/dto/sync"
+	"flight-api/internal/model"
+	repo_airport "flight-api/internal/repository/airport"
+	repo_sync_schedule "flight-api/internal/repository/sync_schedule"
+	"flight-api/pkg/logger"
+	"flight-api/util"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type scheduleDeps struct {
+	dbmock   sqlmock.Sqlmock
+	repo     *repo_sync_schedule.SyncScheduleRepositoryMock
+	airports *repo_airport.AirportRepositoryMock
+	jobs     *SyncJobServiceMock
+	svc      *SyncScheduleService
+	now      time.Time
+}
+
+// nightly is due at 0300Z on 2025-10-06, 30 seconds before now; airac is
+// not due until cycle 2511 takes effect at 0901Z on 2025-10-30.
+func newScheduleDeps(t *testing.T) *scheduleDeps {
+	t.Helper()
+	db, dbmock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	d := &scheduleDeps{
+		dbmock:   dbmock,
+		repo:     &repo_sync_schedule.SyncScheduleRepositoryMock{Mock: mock.Mock{}},
+		airports: &repo_airport.AirportRepositoryMock{Mock: mock.Mock{}},
+		jobs:     &SyncJobServiceMock{Mock: mock.Mock{}},
+		now:      time.Date(2025, time.October, 6, 3, 0, 30, 0, time.UTC),
+	}
+	cfg := &config.Config{SyncSchedules: "nightly=0 3 * * *; airac=@airac"}
+	svc, err := NewSyncScheduleService(logger.NewLogger(logger.INFO_DEBUG_LEVEL), cfg, db, d.repo, d.airports, d.jobs)
+	require.NoError(t, err)
+	d.svc = svc.(*SyncScheduleService)
+	d.svc.startedAt = time.Date(2025, time.October, 5, 12, 0, 0, 0, time.UTC)
+	d.svc.now = func() time.Time { return d.now }
+	return d
+}
+
+func (d *scheduleDeps) expectStates(states ...model.SyncSchedule) {
+	d.dbmock.ExpectBegin()
+	d.repo.Mock.On("FindAll", mock.Anything, anyTx).Return(states, nil).Once()
+	d.dbmock.ExpectCommit()
+}
+
+func TestParseSyncSchedules(t *testing.T) {
+	schedules, err := parseSyncSchedules(" airac=@airac ;nightly = 0 3 * * *;")
+	require.NoError(t, err)
+	require.Len(t, schedules, 2)
+	assert.Equal(t, "airac", schedules[0].name)
+	assert.Equal(t, "0 3 * * *", schedules[1].expression)
+
+	schedules, err = parseSyncSchedules("")
+	require.NoError(t, err)
+	assert.Empty(t, schedules)
+
+	for _, spec := range []string{"@airac", "=@airac", "a=@airac;a=@daily", "nightly=0 3 * *"} {
+		_, err := parseSyncSchedules(spec)
+		assert.Error(t, err, spec)
+	}
+}
+
+func TestSyncScheduleService_RunDue_StartsRefreshJob(t *testing.T) {
+	d := newScheduleDeps(t)
+	jobID := uuid.New()
+
+	d.expectStates()
+	d.dbmock.ExpectBegin()
+	d.repo.Mock.On("TryLock", mock.Anything, anyTx, "nightly").Return(true, nil).Once()
+	d.repo.Mock.On("FindByName", mock.Anything, anyTx, "nightly").Return(model.SyncSchedule{}, util.ErrNotFound).Once()
+	d.airports.Mock.On("FindAllICAOIDs", mock.Anything, anyTx).Return([]string{"KJFK", "KSEA"}, nil).Once()
+	d.jobs.Mock.On("Queue", mock.Anything, anyTx, sync_dto.SyncAirportRequest{ICAOCodes: []string{"KJFK", "KSEA"}, Mode: "refresh", Trigger: "schedule"}).
+		Return(sync_dto.SyncJobDto{ID: jobID.String()}, nil).Once()
+	d.repo.Mock.On("Save", mock.Anything, anyTx, mock.MatchedBy(func(s model.SyncSchedule) bool {
+		return *s.Name == "nightly" &&
+			s.LastRunAt.Equal(d.now) &&
+			s.NextRunAt.Equal(time.Date(2025, time.October, 7, 3, 0, 0, 0, time.UTC)) &&
+			*s.LastOutcome == "queued" &&
+			*s.LastMessage == "Refreshing 2 airports" &&
+			*s.LastJobID == jobID
+	})).Return(model.SyncSchedule{}, nil).Once()
+	d.dbmock.ExpectCommit()
+	d.jobs.Mock.On("Launch", mock.Anything, jobID.String()).Return(nil).Once()
+
+	// airac is next due at 0901Z on 2025-10-30
+	wait := d.svc.RunDue(context.Background())
+	assert.Equal(t, maxSyncScheduleWait, wait)
+
+	d.repo.Mock.AssertExpectations(t)
+	d.jobs.Mock.AssertExpectations(t)
+	require.NoError(t, d.dbmock.ExpectationsWereMet())
+}
+
+func TestSyncScheduleService_RunDue_DoesNotLaunchWhenRunNotSaved(t *testing.T) {
+	d := newScheduleDeps(t)
+	jobID := uuid.New()
+
+	d.expectStates()
+	d.dbmock.ExpectBegin()
+	d.repo.Mock.On("TryLock", mock.Anything, anyTx, "nightly").Return(true, nil).Once()
+	d.repo.Mock.On("FindByName", mock.Anything, anyTx, "nightly").Return(model.SyncSchedule{}, util.ErrNotFound).Once()
+	d.airports.Mock.On("FindAllICAOIDs", mock.Anything, anyTx).Return([]string{"KJFK"}, nil).Once()
+	d.jobs.Mock.On("Queue", mock.Anything, anyTx, mock.Anything).Return(sync_dto.SyncJobDto{ID: jobID.String()}, nil).Once()
+	d.repo.Mock.On("Save", mock.Anything, anyTx, mock.Anything).Return(nil, errors.New("db down")).Once()
+	// The queued job goes with the run, so the next tick queues it again.
+	d.dbmock.ExpectRollback()
+
+	d.svc.RunDue(context.Background())
+
+	d.jobs.Mock.AssertNotCalled(t, "Launch", mock.Anything, mock.Anything)
+	d.repo.Mock.AssertExpectations(t)
+	require.NoError(t, d.dbmock.ExpectationsWereMet())
+}
+
+func TestSyncScheduleService_RunDue_SkipsWhenLockedOrAlreadyRun(t *testing.T) {
+	d := newScheduleDeps(t)
+	ranAt := d.now.Add(-10 * time.Second)
+
+	// Another replica holds the lock
+	d.expectStates()
+	d.dbmock.ExpectBegin()
+	d.repo.Mock.On("TryLock", mock.Anything, anyTx, "nightly").Return(false, nil).Once()
+	d.dbmock.ExpectCommit()
+	d.svc.RunDue(context.Background())
+
+	// Another replica ran it after the states were read
+	d.expectStates()
+	d.dbmock.ExpectBegin()
+	d.repo.Mock.On("TryLock", mock.Anything, anyTx, "nightly").Return(true, nil).Once()
+	d.repo.Mock.On("FindByName", mock.Anything, anyTx, "nightly").
+		Return(model.SyncSchedule{Name: util.Ptr("nightly"), LastRunAt: &ranAt}, nil).Once()
+	d.dbmock.ExpectCommit()
+	d.svc.RunDue(context.Background())
+
+	// Once the run is stored, the next one is a day later
+	d.expectStates(model.SyncSchedule{Name: util.Ptr("nightly"), LastRunAt: &ranAt})
+	assert.Equal(t, maxSyncScheduleWait, d.svc.RunDue(context.Background()))
+
+	d.jobs.Mock.AssertNotCalled(t, "Queue", mock.Anything, mock.Anything, mock.Anything)
+	d.repo.Mock.AssertNotCalled(t, "Save", mock.Anything, mock.Anything, mock.Anything)
+	require.NoError(t, d.dbmock.ExpectationsWereMet())
+}
+
+func TestSyncScheduleService_RunDue_RecordsSkippedAndFailedRuns(t *testing.T) {
+	tests := []struct {
+		name    string
+		codes   []string
+		start   error
+		outcome string
+		message string
+	}{
+		{"no airports", []string{}, nil, "skipped", "No airports to refresh"},
+		{"job not queued", []string{"KJFK"}, errors.New("boom"), "failed", "Failed to queue sync job: boom"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := newScheduleDeps(t)
+
+			d.expectStates()
+			d.dbmock.ExpectBegin()
+			d.repo.Mock.On("TryLock", mock.Anything, anyTx, "nightly").Return(true, nil).Once()
+			d.repo.Mock.On("FindByName", mock.Anything, anyTx, "nightly").Return(model.SyncSchedule{}, util.ErrNotFound).Once()
+			d.airports.Mock.On("FindAllICAOIDs", mock.Anything, anyTx).Return(tt.codes, nil).Once()
+			d.jobs.Mock.On("Queue", mock.Anything, anyTx, mock.Anything).Return(nil, tt.start).Maybe()
+			d.repo.Mock.On("Save", mock.Anything, anyTx, mock.MatchedBy(func(s model.SyncSchedule) bool {
+				return *s.LastOutcome == tt.outcome && *s.LastMessage == tt.message && s.LastJobID == nil
+			})).Return(model.SyncSchedule{}, nil).Once()
+			d.dbmock.ExpectCommit()
+
+			d.svc.RunDue(context.Background())
+
+			d.jobs.Mock.AssertNotCalled(t, "Launch", mock.Anything, mock.Anything)
+			d.repo.Mock.AssertExpectations(t)
+			require.NoError(t, d.dbmock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestSyncScheduleService_FindAll(t *testing.T) {
+	d := newScheduleDeps(t)
+	ranAt := time.Date(2025, time.October, 6, 3, 0, 0, 0, time.UTC)
+	jobID := uuid.New()
+
+	d.expectStates(model.SyncSchedule{
+		Name:          util.Ptr("nightly"),
+		LastRunAt:     &ranAt,
+		LastOutcome:   util.Ptr("queued"),
+		LastMessage:   util.Ptr("Refreshing 2 airports"),
+		LastJobID:     &jobID,
+		LastJobStatus: util.Ptr("completed"),
+	})
+
+	out, err := d.svc.FindAll(context.Background())
+	require.NoError(t, err)
+	require.Len(t, out, 2)
+
+	assert.Equal(t, "nightly", out[0].Name)
+	assert.Equal(t, time.Date(2025, time.October, 7, 3, 0, 0, 0, time.UTC), *out[0].NextRunAt)
+	assert.Equal(t, jobID.String(), *out[0].LastJobID)
+	assert.Equal(t, "completed", *out[0].LastJobStatus)
+	assert.Nil(t, out[0].AIRACCycle)
+
+	assert.Equal(t, "airac", out[1].Name)
+	assert.Equal(t, time.Date(2025, time.October, 30, 9, 1, 0, 0, time.UTC), *out[1].NextRunAt)
+	assert.Equal(t, "2511", *out[1].AIRACCycle)
+	assert.Nil(t, out[1].LastRunAt)
+	require.NoError(t, d.dbmock.ExpectationsWereMet())
+}
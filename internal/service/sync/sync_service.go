@@ -3,8 +3,10 @@ package service_sync
 import (
 	"context"
 	sync_dto "flight-api/internal/dto/sync"
+	"time"
 )
 
 type ISyncService interface {
 	SyncAirports(ctx context.Context, req sync_dto.SyncAirportRequest) ([]sync_dto.SyncAirportResponse, error)
+	RecordRun(ctx context.Context, req sync_dto.SyncAirportRequest, responses []sync_dto.SyncAirportResponse, startedAt time.Time)
 }
@@ -3,12 +3,21 @@ package service_sync
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"flight-api/internal/cache"
 	airport_dto "flight-api/internal/dto/airport"
 	sync_dto "flight-api/internal/dto/sync"
+	"flight-api/internal/enum"
+	"flight-api/internal/model"
 	repo_airport "flight-api/internal/repository/airport"
+	repo_sync_run "flight-api/internal/repository/sync_run"
 	service_aviation "flight-api/internal/service/aviation"
+	service_import "flight-api/internal/service/import"
 	"flight-api/pkg/logger"
 	"flight-api/util"
+	"fmt"
+	"strings"
+	"time"
 
 	"github.com/go-playground/validator"
 )
@@ -18,7 +27,10 @@ type SyncService struct {
 	validate          *validator.Validate
 	db                *sql.DB
 	airportRepository repo_airport.IAirportRepository
+	syncRunRepository repo_sync_run.ISyncRunRepository
 	aviationService   service_aviation.IAviationService
+	airportCache      *cache.AirportCache
+	priority          service_import.SourcePriority
 }
 
 func NewSyncService(
@@ -26,44 +38,248 @@ func NewSyncService(
 	validate *validator.Validate,
 	db *sql.DB,
 	airportRepository repo_airport.IAirportRepository,
+	syncRunRepository repo_sync_run.ISyncRunRepository,
 	aviationService service_aviation.IAviationService,
+	airportCache *cache.AirportCache,
+	priority service_import.SourcePriority,
 ) ISyncService {
 	return &SyncService{
 		logger:            logger,
 		validate:          validate,
 		db:                db,
 		airportRepository: airportRepository,
+		syncRunRepository: syncRunRepository,
 		aviationService:   aviationService,
+		airportCache:      airportCache,
+		priority:          priority,
 	}
 }
 
+// errSyncRolledBack rolls back an airport's own transaction after a failed
+// or dry-run store; the outcome is already in its response.
+var errSyncRolledBack = errors.New("airport sync rolled back")
+
+// SyncAirports fetches the requested airports from the Aviation API and
+// stores them. By default every airport is stored in its own transaction,
+// so a failure only affects that airport's result and the others still
+// commit. An atomic request stores them all in one transaction, committed
+// only when every airport syncs. Every sync that gets to its results is
+// recorded as a sync run, except dry runs, which write nothing, and the
+// chunks of a sync job, which records one run for all of them.
 func (s *SyncService) SyncAirports(
 	ctx context.Context,
 	req sync_dto.SyncAirportRequest,
-) ([]sync_dto.SyncAirportResponse, error) {
-	err := s.validate.Struct(req)
+) (_ []sync_dto.SyncAirportResponse, err error) {
+	err = s.validate.Struct(req)
 	if err != nil {
 		s.logger.Errorf("[SyncAirports] request validation failed %s", err)
 		return nil, err
 	}
+	if err := ValidateSyncFields(req.Fields); err != nil {
+		return nil, err
+	}
 	s.logger.Debug("[SyncAirports] request validated")
 
+	startedAt := time.Now()
+
+	// Remove duplicate ICAO codes from the request
+	ICAOCodes := util.RemoveDuplicate(req.ICAOCodes)
+
+	var SyncAirportResponse []sync_dto.SyncAirportResponse
+	if req.Atomic {
+		SyncAirportResponse, err = s.syncAllOrNothing(ctx, req, ICAOCodes)
+	} else {
+		SyncAirportResponse, err = s.syncEach(ctx, req, ICAOCodes)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if !req.DryRun && req.JobID == nil {
+		s.RecordRun(ctx, req, SyncAirportResponse, startedAt)
+	}
+	return SyncAirportResponse, nil
+}
+
+// syncEach stores every airport in its own transaction.
+func (s *SyncService) syncEach(
+	ctx context.Context,
+	req sync_dto.SyncAirportRequest,
+	ICAOCodes []string,
+) (_ []sync_dto.SyncAirportResponse, err error) {
+	var icaoCodesToFetch []string
+	var existingCodes map[string]bool
+	var SyncAirportResponse []sync_dto.SyncAirportResponse
+	err = func() (err error) {
+		tx, err := s.db.Begin()
+		if err != nil {
+			return err
+		}
+		defer util.CommitOrRollbackErr(tx, &err)
+
+		icaoCodesToFetch, existingCodes, SyncAirportResponse = s.checkExisting(ctx, tx, ICAOCodes, req)
+		return nil
+	}()
+	if err != nil {
+		s.logger.Errorf("[syncEach] failed to check existing ICAO codes: %v", err)
+		return nil, util.NewAppError(util.ErrInternalServer, "Failed to check existing ICAO codes", err)
+	}
+
+	fetchedAirportData, fetchErrors := s.fetch(ctx, icaoCodesToFetch)
+
+	s.logger.Debug("[syncEach] Storing fetched airport data in the database...")
+	for _, code := range icaoCodesToFetch {
+		if res := s.checkFetched(code, fetchedAirportData[code], fetchErrors[code]); res != nil {
+			SyncAirportResponse = append(SyncAirportResponse, *res)
+			continue
+		}
+
+		res, airportModel := s.storeInOwnTx(ctx, code, fetchedAirportData[code], existingCodes[code], req)
+		SyncAirportResponse = append(SyncAirportResponse, res)
+		if airportModel != nil && !req.DryRun {
+			s.airportCache.Invalidate(ctx, *airportModel)
+		}
+	}
+
+	s.logger.Debugf("[syncEach] Successfully synced airport data")
+	return SyncAirportResponse, nil
+}
+
+// RecordRun stores the run history of a sync: the run and the outcome of
+// each code. It is bookkeeping, so failing to record a run is logged and
+// leaves the sync's results as they are.
+func (s *SyncService) RecordRun(
+	ctx context.Context,
+	req sync_dto.SyncAirportRequest,
+	responses []sync_dto.SyncAirportResponse,
+	startedAt time.Time,
+) {
+	run, items := sync_dto.ToSyncRun(req, responses, startedAt, time.Now())
+
+	// A sync cut short by a cancellation is recorded as far as it got.
+	ctx = context.WithoutCancel(ctx)
+	err := func() (err error) {
+		tx, err := s.db.Begin()
+		if err != nil {
+			return err
+		}
+		defer util.CommitOrRollbackErr(tx, &err)
+
+		_, err = s.syncRunRepository.Insert(ctx, tx, run, items)
+		return err
+	}()
+	if err != nil {
+		s.logger.Errorf("[RecordRun] failed to record the sync run of ICAO codes %v: %v", req.ICAOCodes, err)
+	}
+}
+
+// syncAllOrNothing stores every airport in one transaction. The first
+// failed store aborts the transaction, so the airports after it are
+// skipped. When any airport fails to sync the transaction is rolled back
+// and the airports stored before are reported as rolled back.
+func (s *SyncService) syncAllOrNothing(
+	ctx context.Context,
+	req sync_dto.SyncAirportRequest,
+	ICAOCodes []string,
+) (_ []sync_dto.SyncAirportResponse, err error) {
 	tx, err := s.db.Begin()
-	util.PanicIfError(err)
-	defer util.CommitOrRollback(tx)
+	if err != nil {
+		s.logger.Errorf("[syncAllOrNothing] failed to begin transaction: %v", err)
+		return nil, util.NewAppError(util.ErrInternalServer, "Failed to begin transaction", err)
+	}
+
+	// Synced airports are only evicted from the cache once the sync commits.
+	var syncedAirports []model.Airport
+	var failedCodes []string
+	defer func() {
+		if err == nil && !req.DryRun && len(failedCodes) == 0 {
+			s.airportCache.Invalidate(ctx, syncedAirports...)
+		}
+	}()
+	defer func() {
+		if p := recover(); p != nil {
+			_ = tx.Rollback()
+			panic(p)
+		}
+		if req.DryRun || len(failedCodes) > 0 {
+			// A dry run goes through every write and then discards them.
+			_ = tx.Rollback()
+			return
+		}
+		util.CommitOrRollbackErr(tx, &err)
+	}()
+
+	icaoCodesToFetch, existingCodes, SyncAirportResponse := s.checkExisting(ctx, tx, ICAOCodes, req)
+	fetchedAirportData, fetchErrors := s.fetch(ctx, icaoCodesToFetch)
+
+	var stored []int
+	aborted := false
+	for _, code := range icaoCodesToFetch {
+		if aborted {
+			SyncAirportResponse = append(SyncAirportResponse, sync_dto.SyncAirportResponse{
+				ICAOCode: code,
+				Status:   "Skipped",
+				Message:  "Not stored, an earlier airport failed to store",
+			})
+			continue
+		}
+		if res := s.checkFetched(code, fetchedAirportData[code], fetchErrors[code]); res != nil {
+			SyncAirportResponse = append(SyncAirportResponse, *res)
+			continue
+		}
+
+		res, airportModel := s.storeAirport(ctx, tx, code, fetchedAirportData[code], existingCodes[code], req)
+		if airportModel == nil {
+			// Postgres rejects every later statement in the transaction.
+			aborted = true
+		} else {
+			stored = append(stored, len(SyncAirportResponse))
+			syncedAirports = append(syncedAirports, *airportModel)
+		}
+		SyncAirportResponse = append(SyncAirportResponse, res)
+	}
+
+	for _, res := range SyncAirportResponse {
+		switch res.Status {
+		case "Error", "Invalid", "Not Found":
+			failedCodes = append(failedCodes, res.ICAOCode)
+		}
+	}
+	if len(failedCodes) == 0 {
+		s.logger.Debugf("[syncAllOrNothing] Successfully synced airport data")
+		return SyncAirportResponse, nil
+	}
+
+	s.logger.Warnf("[syncAllOrNothing] Rolling back the sync, failed ICAO codes: %v", failedCodes)
+	message := "Rolled back, the sync failed for " + strings.Join(failedCodes, ", ")
+	for _, i := range stored {
+		SyncAirportResponse[i].Airport = nil
+		SyncAirportResponse[i].Status = "Rolled Back"
+		SyncAirportResponse[i].Message = message
+	}
+	return SyncAirportResponse, nil
+}
+
+// checkExisting looks up which codes are already stored. It answers the
+// codes that need no fetch, a conflict outside refresh mode or a failed
+// lookup, and returns the rest to fetch.
+func (s *SyncService) checkExisting(
+	ctx context.Context,
+	tx *sql.Tx,
+	ICAOCodes []string,
+	req sync_dto.SyncAirportRequest,
+) ([]string, map[string]bool, []sync_dto.SyncAirportResponse) {
+	refresh := req.Mode == enum.SYNC_MODE_REFRESH.String()
 
-	// Prepare list of ICAO codes to fetch from Aviation API
 	var icaoCodesToFetch []string
+	existingCodes := make(map[string]bool)
 	SyncAirportResponse := make([]sync_dto.SyncAirportResponse, 0)
 
-	// Remove duplicate ICAO codes from the request
-	ICAOCodes := util.RemoveDuplicate(req.ICAOCodes)
-
-	s.logger.Debug("[SyncAirports] Checking existing ICAO codes in the database...")
+	s.logger.Debug("[checkExisting] Checking existing ICAO codes in the database...")
 	for _, code := range ICAOCodes {
 		exists, err := s.airportRepository.FindExistsByICAOID(ctx, tx, code)
 		if err != nil {
-			s.logger.Errorf("[SyncAirports] failed to check if ICAO code %s exists: %v", code, err)
+			s.logger.Errorf("[checkExisting] failed to check if ICAO code %s exists: %v", code, err)
 			res := sync_dto.SyncAirportResponse{
 				ICAOCode: code,
 				Airport:  nil,
@@ -74,6 +290,13 @@ func (s *SyncService) SyncAirports(
 			continue
 		}
 
+		if exists && refresh {
+			existingCodes[code] = true
+			icaoCodesToFetch = append(icaoCodesToFetch, code)
+			s.logger.Debugf("[checkExisting] ICAO code %s already exists in the database. Refreshing.", code)
+			continue
+		}
+
 		if exists {
 			res := sync_dto.SyncAirportResponse{
 				ICAOCode: code,
@@ -82,88 +305,213 @@ func (s *SyncService) SyncAirports(
 				Message:  "ICAO code already exists in the database. Skipping fetch.",
 			}
 			SyncAirportResponse = append(SyncAirportResponse, res)
-			s.logger.Debugf("[SyncAirports] ICAO code %s already exists in the database. Skipping fetch.", code)
+			s.logger.Debugf("[checkExisting] ICAO code %s already exists in the database. Skipping fetch.", code)
 			continue
 		}
 
 		icaoCodesToFetch = append(icaoCodesToFetch, code)
-		s.logger.Debugf("[SyncAirports] ICAO code %s does not exist in the database.", code)
+		s.logger.Debugf("[checkExisting] ICAO code %s does not exist in the database.", code)
 	}
 
-	// Fetch data from Aviation API.
-	s.logger.Debugf("[SyncAirports] Fetching data for ICAO codes: %v", icaoCodesToFetch)
-	var fetchedAirportData map[string]airport_dto.AirportRequestDto
+	return icaoCodesToFetch, existingCodes, SyncAirportResponse
+}
 
-	for _, code := range icaoCodesToFetch {
-		data, err := s.aviationService.FetchAirportData(ctx, []string{code})
-		if err != nil {
-			s.logger.Errorf("[SyncAirports] failed to fetch airport data for ICAO code %s from Aviation API: %v", code, err)
-			res := sync_dto.SyncAirportResponse{
-				ICAOCode: code,
-				Airport:  nil,
-				Status:   "Error",
-				Message:  "Failed to fetch airport data from Aviation API: " + err.Error(),
-			}
-			SyncAirportResponse = append(SyncAirportResponse, res)
-			continue
-		}
+// fetch gets the airport data from the Aviation API, in batches.
+func (s *SyncService) fetch(ctx context.Context, icaoCodes []string) (map[string]airport_dto.AirportRequestDto, map[string]error) {
+	if len(icaoCodes) == 0 {
+		return nil, nil
+	}
 
-		if len(data) == 0 {
-			s.logger.Warnf("[SyncAirports] No data found for ICAO code %s from Aviation API. Skipping...", code)
-			res := sync_dto.SyncAirportResponse{
-				ICAOCode: code,
-				Airport:  nil,
-				Status:   "Not Found",
-				Message:  "No data found from Aviation API.",
-			}
-			SyncAirportResponse = append(SyncAirportResponse, res)
-			continue
-		}
+	s.logger.Debugf("[fetch] Fetching data for ICAO codes: %v", icaoCodes)
+	fetched, failed := s.aviationService.FetchAirportDataBatched(ctx, icaoCodes)
+	s.logger.Debugf("[fetch] Fetched airport data from Aviation API, %d codes failed", len(failed))
+	return fetched, failed
+}
 
-		if fetchedAirportData == nil {
-			fetchedAirportData = make(map[string]airport_dto.AirportRequestDto)
+// checkFetched answers a code whose fetch failed or returned nothing
+// storable. It returns nil when the data can be stored.
+func (s *SyncService) checkFetched(code string, data airport_dto.AirportRequestDto, fetchErr error) *sync_dto.SyncAirportResponse {
+	if fetchErr != nil {
+		s.logger.Errorf("[checkFetched] failed to fetch airport data for ICAO code %s from Aviation API: %v", code, fetchErr)
+		return &sync_dto.SyncAirportResponse{
+			ICAOCode: code,
+			Airport:  nil,
+			Status:   "Error",
+			Message:  "Failed to fetch airport data from Aviation API: " + fetchErr.Error(),
 		}
+	}
+
+	s.logger.Debugf("[checkFetched] Fetched data for ICAO code %s: %+v", code, data)
 
-		for k, v := range data {
-			fetchedAirportData[k] = v
+	if data.ICAOID == nil {
+		s.logger.Warnf("[checkFetched] No data found for ICAO code %s from Aviation API. Skipping...", code)
+		return &sync_dto.SyncAirportResponse{
+			ICAOCode: code,
+			Airport:  nil,
+			Status:   "Not Found",
+			Message:  "No data found from Aviation API.",
 		}
 	}
-	s.logger.Debug("[SyncAirports] successfully fetched airport data from Aviation API")
-
-	// Store fetched data in the database.
-	s.logger.Debug("[SyncAirports] Storing fetched airport data in the database...")
 
-	for code, data := range fetchedAirportData {
-		s.logger.Debugf("[SyncAirports] Fetched data for ICAO code %s: %+v", code, data)
+	if err := s.validate.Struct(data); err != nil {
+		s.logger.Warnf("[checkFetched] Airport data for ICAO code %s failed validation: %v", code, err)
+		return &sync_dto.SyncAirportResponse{
+			ICAOCode: code,
+			Airport:  nil,
+			Status:   "Invalid",
+			Message:  "Airport data from Aviation API failed validation: " + err.Error(),
+		}
+	}
 
-		airportPayload := airport_dto.AirportRequestToAirport(data)
-		airportModel, err := s.airportRepository.Insert(ctx, tx, airportPayload)
+	return nil
+}
 
+// storeInOwnTx stores one airport in a transaction of its own, which a
+// dry run or a failed store rolls back. The airport is nil when nothing
+// was stored.
+func (s *SyncService) storeInOwnTx(
+	ctx context.Context,
+	code string,
+	data airport_dto.AirportRequestDto,
+	exists bool,
+	req sync_dto.SyncAirportRequest,
+) (res sync_dto.SyncAirportResponse, airportModel *model.Airport) {
+	err := func() (err error) {
+		tx, err := s.db.Begin()
 		if err != nil {
-			s.logger.Errorf("[SyncAirports] failed to insert airport data for ICAO code %s: %v", code, err)
-			result := sync_dto.SyncAirportResponse{
-				ICAOCode: code,
-				Airport:  nil,
-				Status:   "Error",
-				Message:  "Failed to insert airport data: " + err.Error(),
-			}
-			SyncAirportResponse = append(SyncAirportResponse, result)
-			continue
+			return err
 		}
+		defer util.CommitOrRollbackErr(tx, &err)
 
-		s.logger.Debugf("[SyncAirports] Successfully inserted airport data for ICAO code %s", code)
-		airportDto := airport_dto.ToAirportDto(airportModel)
-		res := sync_dto.SyncAirportResponse{
+		res, airportModel = s.storeAirport(ctx, tx, code, data, exists, req)
+		if airportModel == nil || req.DryRun {
+			return errSyncRolledBack
+		}
+		return nil
+	}()
+	if err != nil && !errors.Is(err, errSyncRolledBack) {
+		s.logger.Errorf("[storeInOwnTx] failed to store airport data for ICAO code %s: %v", code, err)
+		return sync_dto.SyncAirportResponse{
 			ICAOCode: code,
-			Airport:  &airportDto,
-			Status:   "Inserted",
-			Message:  "Airport data successfully inserted",
+			Airport:  nil,
+			Status:   "Error",
+			Message:  "Failed to store airport data: " + err.Error(),
+		}, nil
+	}
+	return res, airportModel
+}
+
+// storeAirport inserts a new airport or refreshes an existing one. The
+// airport is nil when the store failed.
+func (s *SyncService) storeAirport(
+	ctx context.Context,
+	tx *sql.Tx,
+	code string,
+	data airport_dto.AirportRequestDto,
+	exists bool,
+	req sync_dto.SyncAirportRequest,
+) (sync_dto.SyncAirportResponse, *model.Airport) {
+	if exists {
+		return s.refreshAirport(ctx, tx, code, data, req.Fields, req.DryRun)
+	}
+
+	airportPayload := airport_dto.AirportRequestToAirport(data)
+	airportModel, err := s.airportRepository.Insert(ctx, tx, airportPayload)
+	if err != nil {
+		s.logger.Errorf("[storeAirport] failed to insert airport data for ICAO code %s: %v", code, err)
+		return sync_dto.SyncAirportResponse{
+			ICAOCode: code,
+			Airport:  nil,
+			Status:   "Error",
+			Message:  "Failed to insert airport data: " + err.Error(),
+		}, nil
+	}
+
+	s.logger.Debugf("[storeAirport] Successfully inserted airport data for ICAO code %s", code)
+	airportDto := airport_dto.ToAirportDto(airportModel)
+	res := sync_dto.SyncAirportResponse{
+		ICAOCode: code,
+		Airport:  &airportDto,
+		Status:   "Inserted",
+		Message:  "Airport data successfully inserted",
+	}
+	if req.DryRun {
+		// Every field is new, so the diff is the whole airport
+		res.Changes = insertChanges(airportPayload)
+		res.Status = "Would Insert"
+		res.Message = "Airport data would be inserted"
+	}
+	return res, &airportModel
+}
+
+// refreshAirport updates a stored airport with the fetched data, keeping
+// its id, and reports the fields that changed, or would change on a dry run.
+// Fields an import source owns with a higher priority than the Aviation API
+// are left as stored. It also stamps the effective date, from the fetched
+// data or today, and the sync status. The airport is nil when the refresh
+// failed.
+func (s *SyncService) refreshAirport(
+	ctx context.Context,
+	tx *sql.Tx,
+	code string,
+	data airport_dto.AirportRequestDto,
+	fields []string,
+	dryRun bool,
+) (sync_dto.SyncAirportResponse, *model.Airport) {
+	failed := func(message string, err error) (sync_dto.SyncAirportResponse, *model.Airport) {
+		s.logger.Errorf("[refreshAirport] %s for ICAO code %s: %v", message, code, err)
+		return sync_dto.SyncAirportResponse{
+			ICAOCode: code,
+			Status:   "Error",
+			Message:  message + ": " + err.Error(),
+		}, nil
+	}
+
+	stored, err := s.airportRepository.FindByICAOID(ctx, tx, code)
+	if err != nil {
+		return failed("Failed to find stored airport data", err)
+	}
+
+	owners, err := s.airportRepository.FindFieldSources(ctx, tx, code)
+	if err != nil {
+		return failed("Failed to find field sources", err)
+	}
+
+	merged, changes, claimed := mergeFetched(stored, owners, airport_dto.AirportRequestToAirport(data), fields, s.priority)
+	merged.EffectiveDate = data.EffectiveDate
+	if merged.EffectiveDate == nil {
+		today := time.Now().UTC().Truncate(24 * time.Hour)
+		merged.EffectiveDate = &today
+	}
+
+	id := stored.ID.String()
+	updated, err := s.airportRepository.Update(ctx, tx, id, merged)
+	if err != nil {
+		return failed("Failed to update airport data", err)
+	}
+	if len(claimed) > 0 {
+		if err := s.airportRepository.UpdateFieldSources(ctx, tx, code, claimed); err != nil {
+			return failed("Failed to update field sources", err)
 		}
-		SyncAirportResponse = append(SyncAirportResponse, res)
+	}
 
-		s.logger.Debugf("[SyncAirports] Airport data for ICAO code %s Inserted", code)
+	status, message := "Unchanged", "Airport data is up to date"
+	if len(changes) > 0 && dryRun {
+		status, message = "Would Update", fmt.Sprintf("Airport data would be refreshed, %d fields would change", len(changes))
+	} else if len(changes) > 0 {
+		status, message = "Updated", fmt.Sprintf("Airport data refreshed, %d fields changed", len(changes))
+	}
+	if err := s.airportRepository.UpdateSyncStatus(ctx, tx, id, enum.SYNC_SYNCED, message); err != nil {
+		return failed("Failed to update sync status", err)
 	}
 
-	s.logger.Debugf("[SyncAirports] Successfully synced airport data")
-	return SyncAirportResponse, err
+	s.logger.Debugf("[refreshAirport] Airport data for ICAO code %s %s", code, strings.ToLower(status))
+	airportDto := airport_dto.ToAirportDto(updated)
+	return sync_dto.SyncAirportResponse{
+		ICAOCode: code,
+		Airport:  &airportDto,
+		Status:   status,
+		Message:  message,
+		Changes:  changes,
+	}, &updated
 }
@@ -0,0 +1,26 @@
+package service_sync
+
+import (
+	"context"
+	sync_dto "flight-api/internal/dto/sync"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type SyncServiceMock struct {
+	Mock mock.Mock
+}
+
+func (m *SyncServiceMock) SyncAirports(ctx context.Context, req sync_dto.SyncAirportRequest) ([]sync_dto.SyncAirportResponse, error) {
+	args := m.Mock.Called(ctx, req)
+	var out []sync_dto.SyncAirportResponse
+	if v, ok := args.Get(0).([]sync_dto.SyncAirportResponse); ok {
+		out = v
+	}
+	return out, args.Error(1)
+}
+
+func (m *SyncServiceMock) RecordRun(ctx context.Context, req sync_dto.SyncAirportRequest, responses []sync_dto.SyncAirportResponse, startedAt time.Time) {
+	m.Mock.Called(ctx, req, responses, startedAt)
+}
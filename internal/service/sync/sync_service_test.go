@@ -2,13 +2,15 @@ package service_sync
 
 import (
 	"context"
-	"database/sql"
 	"errors"
 	airport_dto "flight-api/internal/dto/airport"
 	sync_dto "flight-api/internal/dto/sync"
+	"flight-api/internal/enum"
 	"flight-api/internal/model"
 	repository_airport "flight-api/internal/repository/airport"
+	repo_sync_run "flight-api/internal/repository/sync_run"
 	service_aviation "flight-api/internal/service/aviation"
+	service_import "flight-api/internal/service/import"
 	"flight-api/pkg/logger"
 	"flight-api/util"
 	"testing"
@@ -20,6 +22,15 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
+// expectRunRecorded expects the sync run recorded after a sync, in a
+// transaction of its own.
+func expectRunRecorded(dbmock sqlmock.Sqlmock, runs *repo_sync_run.SyncRunRepositoryMock) {
+	dbmock.ExpectBegin()
+	runs.Mock.On("Insert", mock.Anything, mock.AnythingOfType("util.MockTx"), mock.Anything, mock.Anything).
+		Return(model.SyncRun{}, nil).Once()
+	dbmock.ExpectCommit()
+}
+
 type assertErr string
 
 func (e assertErr) Error() string { return string(e) }
@@ -34,7 +45,8 @@ func TestSyncAirports_Mixed_OneInserted_OneSkipped(t *testing.T) {
 	validate := util.NewValidator()
 	repo := &repository_airport.AirportRepositoryMock{Mock: mock.Mock{}}
 	avi := &service_aviation.AviationServiceMock{Mock: mock.Mock{}}
-	svc := NewSyncService(logger, validate, db, repo, avi)
+	runs := &repo_sync_run.SyncRunRepositoryMock{Mock: mock.Mock{}}
+	svc := NewSyncService(logger, validate, db, repo, runs, avi, nil, service_import.SourcePriority{})
 
 	req := sync_dto.SyncAirportRequest{ICAOCodes: []string{"KJFK", "KSEA"}}
 
@@ -42,8 +54,8 @@ func TestSyncAirports_Mixed_OneInserted_OneSkipped(t *testing.T) {
 	dbmock.ExpectCommit()
 
 	// JFK sudah ada, SEA belum
-	repo.Mock.On("FindExistsByICAOID", mock.Anything, mock.MatchedBy(func(tx *sql.Tx) bool { return tx != nil }), "KJFK").Return(true, nil).Once()
-	repo.Mock.On("FindExistsByICAOID", mock.Anything, mock.MatchedBy(func(tx *sql.Tx) bool { return tx != nil }), "KSEA").Return(false, nil).Once()
+	repo.Mock.On("FindExistsByICAOID", mock.Anything, mock.AnythingOfType("util.MockTx"), "KJFK").Return(true, nil).Once()
+	repo.Mock.On("FindExistsByICAOID", mock.Anything, mock.AnythingOfType("util.MockTx"), "KSEA").Return(false, nil).Once()
 
 	// aviation mengembalikan data untuk KSEA
 	seaReq := airport_dto.AirportRequestDto{
@@ -54,7 +66,7 @@ func TestSyncAirports_Mixed_OneInserted_OneSkipped(t *testing.T) {
 		Latitude:  util.Ptr("47.4502"),
 		Longitude: util.Ptr("-122.3088"),
 	}
-	avi.Mock.On("FetchAirportData", mock.Anything, []string{"KSEA"}).
+	avi.Mock.On("FetchAirportDataBatched", mock.Anything, []string{"KSEA"}).
 		Return(map[string]airport_dto.AirportRequestDto{"KSEA": seaReq}, nil).
 		Once()
 
@@ -75,12 +87,16 @@ func TestSyncAirports_Mixed_OneInserted_OneSkipped(t *testing.T) {
 
 	repo.Mock.On("Insert",
 		mock.Anything,
-		mock.MatchedBy(func(tx *sql.Tx) bool { return tx != nil }),
+		mock.AnythingOfType("util.MockTx"),
 		mock.MatchedBy(func(m model.Airport) bool {
 			return *m.ICAOID == "KSEA"
 		}),
 	).Return(seaModel, nil).Once()
+	// KSEA is stored in its own transaction
+	dbmock.ExpectBegin()
+	dbmock.ExpectCommit()
 
+	expectRunRecorded(dbmock, runs)
 	out, err := svc.SyncAirports(context.Background(), req)
 	require.NoError(t, err)
 	require.Len(t, out, 2)
@@ -110,7 +126,8 @@ func TestSyncAirports_NotFoundFromAviation(t *testing.T) {
 	validate := util.NewValidator()
 	repo := &repository_airport.AirportRepositoryMock{Mock: mock.Mock{}}
 	avi := &service_aviation.AviationServiceMock{Mock: mock.Mock{}}
-	svc := NewSyncService(logger, validate, db, repo, avi)
+	runs := &repo_sync_run.SyncRunRepositoryMock{Mock: mock.Mock{}}
+	svc := NewSyncService(logger, validate, db, repo, runs, avi, nil, service_import.SourcePriority{})
 
 	req := sync_dto.SyncAirportRequest{ICAOCodes: []string{"KXXX"}}
 
@@ -118,13 +135,14 @@ func TestSyncAirports_NotFoundFromAviation(t *testing.T) {
 	dbmock.ExpectCommit()
 
 	// Tidak ada di DB
-	repo.Mock.On("FindExistsByICAOID", mock.Anything, mock.MatchedBy(func(tx *sql.Tx) bool { return tx != nil }), "KXXX").Return(false, nil).Once()
+	repo.Mock.On("FindExistsByICAOID", mock.Anything, mock.AnythingOfType("util.MockTx"), "KXXX").Return(false, nil).Once()
 
 	// Aviation tidak mengembalikan data (key tidak ada)
-	avi.Mock.On("FetchAirportData", mock.Anything, []string{"KXXX"}).
-		Return(map[string]airport_dto.AirportRequestDto{}, nil).
+	avi.Mock.On("FetchAirportDataBatched", mock.Anything, []string{"KXXX"}).
+		Return(map[string]airport_dto.AirportRequestDto{"KXXX": {}}, nil).
 		Once()
 
+	expectRunRecorded(dbmock, runs)
 	out, err := svc.SyncAirports(context.Background(), req)
 	require.NoError(t, err)
 	require.Len(t, out, 1)
@@ -149,7 +167,8 @@ func TestSyncAirports_ErrorOnExistsCheck_ReturnsError(t *testing.T) {
 	validate := util.NewValidator()
 	repo := &repository_airport.AirportRepositoryMock{Mock: mock.Mock{}}
 	avi := &service_aviation.AviationServiceMock{Mock: mock.Mock{}}
-	svc := NewSyncService(logger, validate, db, repo, avi)
+	runs := &repo_sync_run.SyncRunRepositoryMock{Mock: mock.Mock{}}
+	svc := NewSyncService(logger, validate, db, repo, runs, avi, nil, service_import.SourcePriority{})
 
 	req := sync_dto.SyncAirportRequest{ICAOCodes: []string{"KJFK"}}
 
@@ -158,10 +177,11 @@ func TestSyncAirports_ErrorOnExistsCheck_ReturnsError(t *testing.T) {
 
 	repo.Mock.On("FindExistsByICAOID",
 		mock.Anything,
-		mock.MatchedBy(func(tx *sql.Tx) bool { return tx != nil }),
+		mock.AnythingOfType("util.MockTx"),
 		"KJFK",
 	).Return(false, errors.New("db failure")).Once()
 
+	expectRunRecorded(dbmock, runs)
 	out, _ := svc.SyncAirports(context.Background(), req)
 
 	require.Len(t, out, 1)
@@ -169,7 +189,7 @@ func TestSyncAirports_ErrorOnExistsCheck_ReturnsError(t *testing.T) {
 	require.Equal(t, "Error", out[0].Status)
 
 	// Aviation & Insert tidak terpanggil
-	avi.Mock.AssertNotCalled(t, "FetchAirportData", mock.Anything, mock.Anything)
+	avi.Mock.AssertNotCalled(t, "FetchAirportDataBatched", mock.Anything, mock.Anything)
 	repo.Mock.AssertNotCalled(t, "Insert", mock.Anything, mock.Anything, mock.Anything)
 
 	require.NoError(t, dbmock.ExpectationsWereMet())
@@ -186,20 +206,22 @@ func TestSyncAirports_ErrorOnAviationFetch_ReturnsError(t *testing.T) {
 	validate := util.NewValidator()
 	repo := &repository_airport.AirportRepositoryMock{Mock: mock.Mock{}}
 	avi := &service_aviation.AviationServiceMock{Mock: mock.Mock{}}
-	svc := NewSyncService(logger, validate, db, repo, avi)
+	runs := &repo_sync_run.SyncRunRepositoryMock{Mock: mock.Mock{}}
+	svc := NewSyncService(logger, validate, db, repo, runs, avi, nil, service_import.SourcePriority{})
 	req := sync_dto.SyncAirportRequest{ICAOCodes: []string{"KSEA", "KPDX"}}
 
 	dbmock.ExpectBegin()
 	dbmock.ExpectCommit()
 
 	// KSEA belum ada, KPDX sudah ada → only fetch KSEA
-	repo.Mock.On("FindExistsByICAOID", mock.Anything, mock.MatchedBy(func(tx *sql.Tx) bool { return tx != nil }), "KSEA").Return(false, nil).Once()
-	repo.Mock.On("FindExistsByICAOID", mock.Anything, mock.MatchedBy(func(tx *sql.Tx) bool { return tx != nil }), "KPDX").Return(true, nil).Once()
+	repo.Mock.On("FindExistsByICAOID", mock.Anything, mock.AnythingOfType("util.MockTx"), "KSEA").Return(false, nil).Once()
+	repo.Mock.On("FindExistsByICAOID", mock.Anything, mock.AnythingOfType("util.MockTx"), "KPDX").Return(true, nil).Once()
 
-	avi.Mock.On("FetchAirportData", mock.Anything, []string{"KSEA"}).
-		Return(nil, assertErr("aviation timeout")).
+	avi.Mock.On("FetchAirportDataBatched", mock.Anything, []string{"KSEA"}).
+		Return(nil, map[string]error{"KSEA": assertErr("aviation timeout")}).
 		Once()
 
+	expectRunRecorded(dbmock, runs)
 	out, err := svc.SyncAirports(context.Background(), req)
 	require.NoError(t, err)
 	require.Len(t, out, 2)
@@ -232,7 +254,8 @@ func TestSyncAirports_ErrorOnInsert_ReturnsError(t *testing.T) {
 	validate := util.NewValidator()
 	repo := &repository_airport.AirportRepositoryMock{Mock: mock.Mock{}}
 	avi := &service_aviation.AviationServiceMock{Mock: mock.Mock{}}
-	svc := NewSyncService(logger, validate, db, repo, avi)
+	runs := &repo_sync_run.SyncRunRepositoryMock{Mock: mock.Mock{}}
+	svc := NewSyncService(logger, validate, db, repo, runs, avi, nil, service_import.SourcePriority{})
 
 	req := sync_dto.SyncAirportRequest{ICAOCodes: []string{"KLAX"}}
 
@@ -240,7 +263,7 @@ func TestSyncAirports_ErrorOnInsert_ReturnsError(t *testing.T) {
 	dbmock.ExpectCommit()
 
 	// KLAX belum ada
-	repo.Mock.On("FindExistsByICAOID", mock.Anything, mock.MatchedBy(func(tx *sql.Tx) bool { return tx != nil }), "KLAX").Return(false, nil).Once()
+	repo.Mock.On("FindExistsByICAOID", mock.Anything, mock.AnythingOfType("util.MockTx"), "KLAX").Return(false, nil).Once()
 
 	// Aviation balikin data KLAX
 	laxReq := airport_dto.AirportRequestDto{
@@ -251,17 +274,20 @@ func TestSyncAirports_ErrorOnInsert_ReturnsError(t *testing.T) {
 		Latitude:  util.Ptr("33.9416"),
 		Longitude: util.Ptr("-118.4085"),
 	}
-	avi.Mock.On("FetchAirportData", mock.Anything, []string{"KLAX"}).
+	avi.Mock.On("FetchAirportDataBatched", mock.Anything, []string{"KLAX"}).
 		Return(map[string]airport_dto.AirportRequestDto{"KLAX": laxReq}, nil).
 		Once()
 
 	// Insert error
 	repo.Mock.On("Insert",
 		mock.Anything,
-		mock.MatchedBy(func(tx *sql.Tx) bool { return tx != nil }),
+		mock.AnythingOfType("util.MockTx"),
 		mock.MatchedBy(func(m model.Airport) bool { return *m.ICAOID == "KLAX" }),
 	).Return(model.Airport{}, assertErr("insert failed")).Once()
+	dbmock.ExpectBegin()
+	dbmock.ExpectRollback()
 
+	expectRunRecorded(dbmock, runs)
 	out, err := svc.SyncAirports(context.Background(), req)
 	require.NoError(t, err)
 	require.Len(t, out, 1)
@@ -274,3 +300,424 @@ func TestSyncAirports_ErrorOnInsert_ReturnsError(t *testing.T) {
 	repo.Mock.AssertExpectations(t)
 	avi.Mock.AssertExpectations(t)
 }
+
+func TestSyncAirports_Refresh_UpdatesExistingAirport(t *testing.T) {
+	logger := logger.NewLogger(logger.INFO_DEBUG_LEVEL)
+
+	db, dbmock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	validate := util.NewValidator()
+	repo := &repository_airport.AirportRepositoryMock{Mock: mock.Mock{}}
+	avi := &service_aviation.AviationServiceMock{Mock: mock.Mock{}}
+	runs := &repo_sync_run.SyncRunRepositoryMock{Mock: mock.Mock{}}
+	svc := NewSyncService(logger, validate, db, repo, runs, avi, nil, service_import.SourcePriority{})
+
+	// Only the name may be refreshed; the manager is curated locally
+	req := sync_dto.SyncAirportRequest{ICAOCodes: []string{"KJFK"}, Mode: "refresh", Fields: []string{"name"}}
+
+	dbmock.ExpectBegin()
+	dbmock.ExpectCommit()
+
+	anyTx := mock.AnythingOfType("util.MockTx")
+	repo.Mock.On("FindExistsByICAOID", mock.Anything, anyTx, "KJFK").Return(true, nil).Once()
+
+	effective := time.Date(2026, 9, 4, 0, 0, 0, 0, time.UTC)
+	avi.Mock.On("FetchAirportDataBatched", mock.Anything, []string{"KJFK"}).
+		Return(map[string]airport_dto.AirportRequestDto{"KJFK": {
+			ICAOID:        util.Ptr("KJFK"),
+			Name:          util.Ptr("John F Kennedy International Airport"),
+			Manager:       util.Ptr("Port Authority"),
+			EffectiveDate: &effective,
+		}}, nil).
+		Once()
+
+	id := uuid.New()
+	timeNow := time.Now()
+	stored := model.Airport{
+		ID:        &id,
+		ICAOID:    util.Ptr("KJFK"),
+		Name:      util.Ptr("John F Kennedy Intl"),
+		Manager:   util.Ptr("Local Curator"),
+		CreatedAt: &timeNow,
+		UpdatedAt: &timeNow,
+	}
+	repo.Mock.On("FindByICAOID", mock.Anything, anyTx, "KJFK").Return(stored, nil).Once()
+	repo.Mock.On("FindFieldSources", mock.Anything, anyTx, "KJFK").Return(map[string]string{}, nil).Once()
+
+	updated := stored
+	updated.Name = util.Ptr("John F Kennedy International Airport")
+	updated.EffectiveDate = &effective
+	repo.Mock.On("Update", mock.Anything, anyTx, id.String(), mock.MatchedBy(func(m model.Airport) bool {
+		return *m.ID == id &&
+			*m.Name == "John F Kennedy International Airport" &&
+			*m.Manager == "Local Curator" &&
+			m.EffectiveDate.Equal(effective)
+	})).Return(updated, nil).Once()
+	repo.Mock.On("UpdateFieldSources", mock.Anything, anyTx, "KJFK", map[string]string{"name": "aviationapi"}).Return(nil).Once()
+	repo.Mock.On("UpdateSyncStatus", mock.Anything, anyTx, id.String(), enum.SYNC_SYNCED, "Airport data refreshed, 1 fields changed").
+		Return(nil).Once()
+	dbmock.ExpectBegin()
+	dbmock.ExpectCommit()
+
+	expectRunRecorded(dbmock, runs)
+	out, err := svc.SyncAirports(context.Background(), req)
+	require.NoError(t, err)
+	require.Len(t, out, 1)
+	require.Equal(t, "Updated", out[0].Status)
+	require.Equal(t, id, *out[0].Airport.ID)
+	require.Len(t, out[0].Changes, 1)
+	require.Equal(t, "name", out[0].Changes[0].Field)
+	require.Equal(t, "John F Kennedy Intl", out[0].Changes[0].Old)
+
+	repo.Mock.AssertNotCalled(t, "Insert", mock.Anything, mock.Anything, mock.Anything)
+	require.NoError(t, dbmock.ExpectationsWereMet())
+	repo.Mock.AssertExpectations(t)
+	avi.Mock.AssertExpectations(t)
+}
+
+func TestSyncAirports_Refresh_KeepsHigherPriorityFields(t *testing.T) {
+	logger := logger.NewLogger(logger.INFO_DEBUG_LEVEL)
+
+	db, dbmock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	priority, err := service_import.ParseSourcePriority("nasr,aviationapi")
+	require.NoError(t, err)
+
+	validate := util.NewValidator()
+	repo := &repository_airport.AirportRepositoryMock{Mock: mock.Mock{}}
+	avi := &service_aviation.AviationServiceMock{Mock: mock.Mock{}}
+	runs := &repo_sync_run.SyncRunRepositoryMock{Mock: mock.Mock{}}
+	svc := NewSyncService(logger, validate, db, repo, runs, avi, nil, priority)
+
+	req := sync_dto.SyncAirportRequest{ICAOCodes: []string{"KJFK"}, Mode: "refresh", Fields: []string{"name", "city"}}
+
+	dbmock.ExpectBegin()
+	dbmock.ExpectCommit()
+	repo.Mock.On("FindExistsByICAOID", mock.Anything, mock.Anything, "KJFK").Return(true, nil).Once()
+
+	avi.Mock.On("FetchAirportDataBatched", mock.Anything, []string{"KJFK"}).
+		Return(map[string]airport_dto.AirportRequestDto{"KJFK": {
+			ICAOID: util.Ptr("KJFK"),
+			Name:   util.Ptr("John F Kennedy Intl"),
+			City:   util.Ptr("Jamaica"),
+		}}, nil).
+		Once()
+
+	id := uuid.New()
+	timeNow := time.Now()
+	stored := model.Airport{
+		ID:        &id,
+		ICAOID:    util.Ptr("KJFK"),
+		Name:      util.Ptr("John F Kennedy International"),
+		City:      util.Ptr("New York"),
+		CreatedAt: &timeNow,
+		UpdatedAt: &timeNow,
+	}
+
+	// NASR owns the name and outranks the Aviation API; the city has no
+	// recorded source, so the Aviation API may refresh it
+	dbmock.ExpectBegin()
+	repo.Mock.On("FindByICAOID", mock.Anything, mock.Anything, "KJFK").Return(stored, nil).Once()
+	repo.Mock.On("FindFieldSources", mock.Anything, mock.Anything, "KJFK").Return(map[string]string{"name": "nasr"}, nil).Once()
+	repo.Mock.On("Update", mock.Anything, mock.Anything, id.String(), mock.MatchedBy(func(m model.Airport) bool {
+		return *m.Name == "John F Kennedy International" && *m.City == "Jamaica"
+	})).Return(stored, nil).Once()
+	repo.Mock.On("UpdateFieldSources", mock.Anything, mock.Anything, "KJFK", map[string]string{"city": "aviationapi"}).Return(nil).Once()
+	repo.Mock.On("UpdateSyncStatus", mock.Anything, mock.Anything, id.String(), enum.SYNC_SYNCED, "Airport data refreshed, 1 fields changed").
+		Return(nil).Once()
+	dbmock.ExpectCommit()
+
+	expectRunRecorded(dbmock, runs)
+	out, err := svc.SyncAirports(context.Background(), req)
+	require.NoError(t, err)
+	require.Len(t, out, 1)
+	require.Equal(t, "Updated", out[0].Status)
+	require.Equal(t, []sync_dto.FieldChangeDto{{Field: "city", Old: "New York", New: "Jamaica"}}, out[0].Changes)
+
+	repo.Mock.AssertExpectations(t)
+	require.NoError(t, dbmock.ExpectationsWereMet())
+}
+
+func TestSyncAirports_DryRun_ReportsDiffsAndRollsBack(t *testing.T) {
+	logger := logger.NewLogger(logger.INFO_DEBUG_LEVEL)
+
+	db, dbmock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	validate := util.NewValidator()
+	repo := &repository_airport.AirportRepositoryMock{Mock: mock.Mock{}}
+	avi := &service_aviation.AviationServiceMock{Mock: mock.Mock{}}
+	runs := &repo_sync_run.SyncRunRepositoryMock{Mock: mock.Mock{}}
+	svc := NewSyncService(logger, validate, db, repo, runs, avi, nil, service_import.SourcePriority{})
+
+	req := sync_dto.SyncAirportRequest{ICAOCodes: []string{"KJFK", "KSEA", "KBAD"}, Mode: "refresh", DryRun: true}
+
+	// Only the existence checks commit, every airport's writes are rolled back
+	dbmock.ExpectBegin()
+	dbmock.ExpectCommit()
+	dbmock.ExpectBegin()
+	dbmock.ExpectRollback()
+	dbmock.ExpectBegin()
+	dbmock.ExpectRollback()
+
+	anyTx := mock.AnythingOfType("util.MockTx")
+	repo.Mock.On("FindExistsByICAOID", mock.Anything, anyTx, "KJFK").Return(true, nil).Once()
+	repo.Mock.On("FindExistsByICAOID", mock.Anything, anyTx, "KSEA").Return(false, nil).Once()
+	repo.Mock.On("FindExistsByICAOID", mock.Anything, anyTx, "KBAD").Return(false, nil).Once()
+
+	// One batched call covers every code
+	avi.Mock.On("FetchAirportDataBatched", mock.Anything, []string{"KJFK", "KSEA", "KBAD"}).
+		Return(map[string]airport_dto.AirportRequestDto{
+			"KJFK": {ICAOID: util.Ptr("KJFK"), Name: util.Ptr("John F Kennedy International Airport")},
+			"KSEA": {ICAOID: util.Ptr("KSEA"), Name: util.Ptr("Seattle-Tacoma Intl"), City: util.Ptr("Seattle")},
+			"KBAD": {ICAOID: util.Ptr("KBAD"), Type: util.Ptr("seaplane base")},
+		}, nil).Once()
+
+	timeNow := time.Now()
+	jfkID, seaID := uuid.New(), uuid.New()
+	jfk := model.Airport{ID: &jfkID, ICAOID: util.Ptr("KJFK"), Name: util.Ptr("John F Kennedy Intl"), CreatedAt: &timeNow, UpdatedAt: &timeNow}
+	repo.Mock.On("FindByICAOID", mock.Anything, anyTx, "KJFK").Return(jfk, nil).Once()
+	repo.Mock.On("FindFieldSources", mock.Anything, anyTx, "KJFK").Return(map[string]string{}, nil).Once()
+	repo.Mock.On("Update", mock.Anything, anyTx, jfkID.String(), mock.Anything).Return(jfk, nil).Once()
+	repo.Mock.On("UpdateFieldSources", mock.Anything, anyTx, "KJFK", map[string]string{"name": "aviationapi"}).Return(nil).Once()
+	repo.Mock.On("UpdateSyncStatus", mock.Anything, anyTx, jfkID.String(), enum.SYNC_SYNCED, mock.Anything).Return(nil).Once()
+
+	sea := model.Airport{ID: &seaID, ICAOID: util.Ptr("KSEA"), CreatedAt: &timeNow, UpdatedAt: &timeNow}
+	repo.Mock.On("Insert", mock.Anything, anyTx, mock.Anything).Return(sea, nil).Once()
+
+	out, err := svc.SyncAirports(context.Background(), req)
+	require.NoError(t, err)
+	require.Len(t, out, 3)
+
+	byCode := map[string]sync_dto.SyncAirportResponse{}
+	for _, res := range out {
+		byCode[res.ICAOCode] = res
+	}
+
+	require.Equal(t, "Would Update", byCode["KJFK"].Status)
+	require.Equal(t, []sync_dto.FieldChangeDto{
+		{Field: "name", Old: "John F Kennedy Intl", New: "John F Kennedy International Airport"},
+	}, byCode["KJFK"].Changes)
+
+	require.Equal(t, "Would Insert", byCode["KSEA"].Status)
+	require.Equal(t, []sync_dto.FieldChangeDto{
+		{Field: "icao_id", Old: nil, New: "KSEA"},
+		{Field: "name", Old: nil, New: "Seattle-Tacoma Intl"},
+		{Field: "city", Old: nil, New: "Seattle"},
+	}, byCode["KSEA"].Changes)
+
+	// Mapped data the API would reject is reported, not written
+	require.Equal(t, "Invalid", byCode["KBAD"].Status)
+
+	require.NoError(t, dbmock.ExpectationsWereMet())
+	repo.Mock.AssertExpectations(t)
+	avi.Mock.AssertExpectations(t)
+}
+
+func TestSyncAirports_DryRun_NotRecorded(t *testing.T) {
+	logger := logger.NewLogger(logger.INFO_DEBUG_LEVEL)
+
+	db, dbmock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	validate := util.NewValidator()
+	repo := &repository_airport.AirportRepositoryMock{Mock: mock.Mock{}}
+	runs := &repo_sync_run.SyncRunRepositoryMock{Mock: mock.Mock{}}
+	avi := &service_aviation.AviationServiceMock{Mock: mock.Mock{}}
+	svc := NewSyncService(logger, validate, db, repo, runs, avi, nil, service_import.SourcePriority{})
+
+	req := sync_dto.SyncAirportRequest{ICAOCodes: []string{"KJFK"}, DryRun: true}
+
+	// Only the existence check runs; no transaction follows to record a run
+	dbmock.ExpectBegin()
+	dbmock.ExpectCommit()
+	repo.Mock.On("FindExistsByICAOID", mock.Anything, mock.Anything, "KJFK").Return(true, nil).Once()
+
+	out, err := svc.SyncAirports(context.Background(), req)
+	require.NoError(t, err)
+	require.Len(t, out, 1)
+	require.Equal(t, "Conflict", out[0].Status)
+
+	runs.Mock.AssertNotCalled(t, "Insert", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	require.NoError(t, dbmock.ExpectationsWereMet())
+}
+
+func TestSyncAirports_JobChunkNotRecorded(t *testing.T) {
+	logger := logger.NewLogger(logger.INFO_DEBUG_LEVEL)
+
+	db, dbmock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	validate := util.NewValidator()
+	repo := &repository_airport.AirportRepositoryMock{Mock: mock.Mock{}}
+	runs := &repo_sync_run.SyncRunRepositoryMock{Mock: mock.Mock{}}
+	avi := &service_aviation.AviationServiceMock{Mock: mock.Mock{}}
+	svc := NewSyncService(logger, validate, db, repo, runs, avi, nil, service_import.SourcePriority{})
+
+	jobID := uuid.New()
+	req := sync_dto.SyncAirportRequest{ICAOCodes: []string{"KJFK"}, Trigger: "job", JobID: &jobID}
+
+	// The job records one run once all of its chunks are synced
+	dbmock.ExpectBegin()
+	dbmock.ExpectCommit()
+	repo.Mock.On("FindExistsByICAOID", mock.Anything, mock.Anything, "KJFK").Return(true, nil).Once()
+
+	out, err := svc.SyncAirports(context.Background(), req)
+	require.NoError(t, err)
+	require.Len(t, out, 1)
+
+	runs.Mock.AssertNotCalled(t, "Insert", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	require.NoError(t, dbmock.ExpectationsWereMet())
+}
+
+func TestSyncAirports_FailedAirportDoesNotRollBackOthers(t *testing.T) {
+	logger := logger.NewLogger(logger.INFO_DEBUG_LEVEL)
+
+	db, dbmock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	validate := util.NewValidator()
+	repo := &repository_airport.AirportRepositoryMock{Mock: mock.Mock{}}
+	avi := &service_aviation.AviationServiceMock{Mock: mock.Mock{}}
+	runs := &repo_sync_run.SyncRunRepositoryMock{Mock: mock.Mock{}}
+	svc := NewSyncService(logger, validate, db, repo, runs, avi, nil, service_import.SourcePriority{})
+
+	req := sync_dto.SyncAirportRequest{ICAOCodes: []string{"KJFK", "KSEA"}}
+
+	anyTx := mock.AnythingOfType("util.MockTx")
+	dbmock.ExpectBegin()
+	dbmock.ExpectCommit()
+	repo.Mock.On("FindExistsByICAOID", mock.Anything, anyTx, "KJFK").Return(false, nil).Once()
+	repo.Mock.On("FindExistsByICAOID", mock.Anything, anyTx, "KSEA").Return(false, nil).Once()
+
+	avi.Mock.On("FetchAirportDataBatched", mock.Anything, []string{"KJFK", "KSEA"}).
+		Return(map[string]airport_dto.AirportRequestDto{
+			"KJFK": {ICAOID: util.Ptr("KJFK")},
+			"KSEA": {ICAOID: util.Ptr("KSEA")},
+		}, nil).Once()
+
+	// KJFK fails and only its own transaction is rolled back
+	dbmock.ExpectBegin()
+	dbmock.ExpectRollback()
+	repo.Mock.On("Insert", mock.Anything, anyTx, mock.MatchedBy(func(m model.Airport) bool { return *m.ICAOID == "KJFK" })).
+		Return(model.Airport{}, assertErr("duplicate key value")).Once()
+
+	// KSEA still commits
+	timeNow := time.Now()
+	seaID := uuid.New()
+	dbmock.ExpectBegin()
+	dbmock.ExpectCommit()
+	repo.Mock.On("Insert", mock.Anything, anyTx, mock.MatchedBy(func(m model.Airport) bool { return *m.ICAOID == "KSEA" })).
+		Return(model.Airport{ID: &seaID, ICAOID: util.Ptr("KSEA"), CreatedAt: &timeNow, UpdatedAt: &timeNow}, nil).Once()
+
+	expectRunRecorded(dbmock, runs)
+	out, err := svc.SyncAirports(context.Background(), req)
+	require.NoError(t, err)
+	require.Len(t, out, 2)
+	require.Equal(t, "Error", out[0].Status)
+	require.Equal(t, "Inserted", out[1].Status)
+
+	require.NoError(t, dbmock.ExpectationsWereMet())
+	repo.Mock.AssertExpectations(t)
+}
+
+func TestSyncAirports_Atomic_RollsBackEveryAirportOnFailure(t *testing.T) {
+	logger := logger.NewLogger(logger.INFO_DEBUG_LEVEL)
+
+	db, dbmock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	validate := util.NewValidator()
+	repo := &repository_airport.AirportRepositoryMock{Mock: mock.Mock{}}
+	avi := &service_aviation.AviationServiceMock{Mock: mock.Mock{}}
+	runs := &repo_sync_run.SyncRunRepositoryMock{Mock: mock.Mock{}}
+	svc := NewSyncService(logger, validate, db, repo, runs, avi, nil, service_import.SourcePriority{})
+
+	req := sync_dto.SyncAirportRequest{ICAOCodes: []string{"KJFK", "KSEA", "KLAX"}, Atomic: true}
+
+	// One transaction for the whole sync, rolled back
+	dbmock.ExpectBegin()
+	dbmock.ExpectRollback()
+
+	anyTx := mock.AnythingOfType("util.MockTx")
+	for _, code := range req.ICAOCodes {
+		repo.Mock.On("FindExistsByICAOID", mock.Anything, anyTx, code).Return(false, nil).Once()
+	}
+	avi.Mock.On("FetchAirportDataBatched", mock.Anything, []string{"KJFK", "KSEA", "KLAX"}).
+		Return(map[string]airport_dto.AirportRequestDto{
+			"KJFK": {ICAOID: util.Ptr("KJFK")},
+			"KSEA": {ICAOID: util.Ptr("KSEA")},
+			"KLAX": {ICAOID: util.Ptr("KLAX")},
+		}, nil).Once()
+
+	timeNow := time.Now()
+	jfkID := uuid.New()
+	repo.Mock.On("Insert", mock.Anything, anyTx, mock.MatchedBy(func(m model.Airport) bool { return *m.ICAOID == "KJFK" })).
+		Return(model.Airport{ID: &jfkID, ICAOID: util.Ptr("KJFK"), CreatedAt: &timeNow, UpdatedAt: &timeNow}, nil).Once()
+	repo.Mock.On("Insert", mock.Anything, anyTx, mock.MatchedBy(func(m model.Airport) bool { return *m.ICAOID == "KSEA" })).
+		Return(model.Airport{}, assertErr("duplicate key value")).Once()
+
+	expectRunRecorded(dbmock, runs)
+	out, err := svc.SyncAirports(context.Background(), req)
+	require.NoError(t, err)
+	require.Len(t, out, 3)
+
+	require.Equal(t, "Rolled Back", out[0].Status)
+	require.Nil(t, out[0].Airport)
+	require.Contains(t, out[0].Message, "KSEA")
+	require.Equal(t, "Error", out[1].Status)
+	// The aborted transaction would reject KLAX, so it is not attempted
+	require.Equal(t, "Skipped", out[2].Status)
+
+	repo.Mock.AssertNumberOfCalls(t, "Insert", 2)
+	require.NoError(t, dbmock.ExpectationsWereMet())
+	repo.Mock.AssertExpectations(t)
+}
+
+func TestSyncAirports_RecordsRun(t *testing.T) {
+	logger := logger.NewLogger(logger.INFO_DEBUG_LEVEL)
+
+	db, dbmock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	validate := util.NewValidator()
+	repo := &repository_airport.AirportRepositoryMock{Mock: mock.Mock{}}
+	runs := &repo_sync_run.SyncRunRepositoryMock{Mock: mock.Mock{}}
+	avi := &service_aviation.AviationServiceMock{Mock: mock.Mock{}}
+	svc := NewSyncService(logger, validate, db, repo, runs, avi, nil, service_import.SourcePriority{})
+
+	req := sync_dto.SyncAirportRequest{ICAOCodes: []string{"KJFK", "KJFK"}}
+
+	dbmock.ExpectBegin()
+	dbmock.ExpectCommit()
+	repo.Mock.On("FindExistsByICAOID", mock.Anything, mock.Anything, "KJFK").Return(true, nil).Once()
+
+	dbmock.ExpectBegin()
+	runs.Mock.On("Insert", mock.Anything, mock.Anything, mock.MatchedBy(func(run model.SyncRun) bool {
+		return *run.Trigger == "api" && run.JobID == nil && *run.Mode == "insert" &&
+			*run.Total == 1 && *run.Failed == 0 && string(run.Counts) == `{"conflict":1}` &&
+			!run.FinishedAt.Before(*run.StartedAt)
+	}), mock.MatchedBy(func(items []model.SyncRunItem) bool {
+		return len(items) == 1 && *items[0].ICAOCode == "KJFK" && *items[0].Outcome == "conflict" && *items[0].Status == "Conflict"
+	})).Return(nil, errors.New("sync_runs missing")).Once()
+	dbmock.ExpectRollback()
+
+	// Failing to record the run leaves the results as they are
+	out, err := svc.SyncAirports(context.Background(), req)
+	require.NoError(t, err)
+	require.Len(t, out, 1)
+	require.Equal(t, "Conflict", out[0].Status)
+
+	runs.Mock.AssertExpectations(t)
+	require.NoError(t, dbmock.ExpectationsWereMet())
+}
@@ -0,0 +1,243 @@
+package service_sync
+
+import (
+	"context"
+	"database/sql"
+	"flight-api/config"
+	"flight-api/internal/cache"
+	airport_dto "flight-api/internal/dto/airport"
+	"flight-api/internal/enum"
+	"flight-api/internal/model"
+	repo_airport "flight-api/internal/repository/airport"
+	service_aviation "flight-api/internal/service/aviation"
+	service_import "flight-api/internal/service/import"
+	"flight-api/pkg/logger"
+	"flight-api/util"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	defaultSyncWorkerBatchSize   = 25
+	defaultSyncWorkerMaxAttempts = 3
+	defaultSyncWorkerStaleAfter  = 10 * time.Minute
+)
+
+// SyncWorker moves airports through the sync_status state machine. Every
+// SYNC_WORKER_INTERVAL it claims new and retryable failed airports, fetches
+// them from the Aviation API and fills in their rows, recording a terminal
+// status: synced, not found, or error once SYNC_WORKER_MAX_ATTEMPTS is
+// reached. Fields an import source outranks the Aviation API on are kept.
+type SyncWorker struct {
+	logger            *logger.Logger
+	cfg               *config.Config
+	db                *sql.DB
+	airportRepository repo_airport.IAirportRepository
+	aviationService   service_aviation.IAviationService
+	airportCache      *cache.AirportCache
+	priority          service_import.SourcePriority
+	now               func() time.Time
+}
+
+func NewSyncWorker(
+	logger *logger.Logger,
+	cfg *config.Config,
+	db *sql.DB,
+	airportRepository repo_airport.IAirportRepository,
+	aviationService service_aviation.IAviationService,
+	airportCache *cache.AirportCache,
+	priority service_import.SourcePriority,
+) *SyncWorker {
+	return &SyncWorker{
+		logger:            logger,
+		cfg:               cfg,
+		db:                db,
+		airportRepository: airportRepository,
+		aviationService:   aviationService,
+		airportCache:      airportCache,
+		priority:          priority,
+		now:               time.Now,
+	}
+}
+
+// Run works until ctx is done, starting immediately. A full batch is
+// followed by the next one without waiting. It returns at once when
+// SYNC_WORKER_INTERVAL is zero.
+func (w *SyncWorker) Run(ctx context.Context) {
+	if w.cfg.SyncWorkerInterval <= 0 {
+		w.logger.Info("[Run] Sync worker disabled")
+		return
+	}
+
+	ticker := time.NewTicker(w.cfg.SyncWorkerInterval)
+	defer ticker.Stop()
+
+	for {
+		if claimed := w.RunOnce(ctx); claimed == w.batchSize() {
+			if ctx.Err() != nil {
+				return
+			}
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce syncs one batch of claimed airports and reports how many it
+// claimed. Airports left on process by an interrupted run are claimed
+// again once SYNC_WORKER_STALE_AFTER has passed.
+func (w *SyncWorker) RunOnce(ctx context.Context) int {
+	airports, err := w.claim(ctx)
+	if err != nil {
+		w.logger.Errorf("[RunOnce] Failed to claim airports for sync: %v", err)
+		return 0
+	}
+	if len(airports) == 0 {
+		return 0
+	}
+
+	codes := make([]string, 0, len(airports))
+	for _, airport := range airports {
+		codes = append(codes, *airport.ICAOID)
+	}
+	w.logger.Debugf("[RunOnce] Syncing ICAO codes: %v", codes)
+
+	fetched, failed := w.aviationService.FetchAirportDataBatched(ctx, codes)
+	if ctx.Err() != nil {
+		return len(airports)
+	}
+
+	for _, airport := range airports {
+		if err, ok := failed[*airport.ICAOID]; ok {
+			w.logger.Errorf("[RunOnce] Failed to fetch airport data for ICAO code %s from Aviation API: %v", *airport.ICAOID, err)
+			w.fail(ctx, airport, "Failed to fetch airport data from Aviation API: "+err.Error())
+			continue
+		}
+
+		data, ok := fetched[*airport.ICAOID]
+		if !ok || data.ICAOID == nil {
+			w.finish(ctx, airport, enum.SYNC_NOT_FOUND, "No data found from Aviation API.")
+			continue
+		}
+		w.fill(ctx, airport, data)
+	}
+
+	return len(airports)
+}
+
+func (w *SyncWorker) claim(ctx context.Context) (_ []model.Airport, err error) {
+	tx, err := w.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer util.CommitOrRollbackErr(tx, &err)
+
+	now := w.now()
+	staleAfter := w.cfg.SyncWorkerStaleAfter
+	if staleAfter <= 0 {
+		staleAfter = defaultSyncWorkerStaleAfter
+	}
+
+	return w.airportRepository.ClaimForSync(ctx, tx, w.batchSize(), w.maxAttempts(), now.Add(-w.cfg.SyncWorkerRetryDelay), now.Add(-staleAfter))
+}
+
+// fill merges the fetched data over the claimed row and marks it synced.
+// Values missing from the fetched data keep the stored ones, and so do the
+// fields another source owns with a higher priority.
+func (w *SyncWorker) fill(ctx context.Context, airport model.Airport, data airport_dto.AirportRequestDto) {
+	id := airport.ID.String()
+	code := *airport.ICAOID
+
+	updated, err := func() (_ model.Airport, err error) {
+		tx, err := w.db.Begin()
+		if err != nil {
+			return model.Airport{}, err
+		}
+		defer util.CommitOrRollbackErr(tx, &err)
+
+		stored, err := w.airportRepository.FindByICAOID(ctx, tx, code)
+		if err != nil {
+			return model.Airport{}, err
+		}
+		owners, err := w.airportRepository.FindFieldSources(ctx, tx, code)
+		if err != nil {
+			return model.Airport{}, err
+		}
+
+		merged, _, claimed := mergeFetched(stored, owners, airport_dto.AirportRequestToAirport(data), nil, w.priority)
+		if data.EffectiveDate != nil {
+			merged.EffectiveDate = data.EffectiveDate
+		}
+
+		updated, err := w.airportRepository.Update(ctx, tx, id, merged)
+		if err != nil {
+			return model.Airport{}, err
+		}
+		if len(claimed) > 0 {
+			if err = w.airportRepository.UpdateFieldSources(ctx, tx, code, claimed); err != nil {
+				return model.Airport{}, err
+			}
+		}
+		err = w.airportRepository.UpdateSyncStatus(ctx, tx, id, enum.SYNC_SYNCED, enum.SYNC_SYNCED.String())
+		return updated, err
+	}()
+	if err != nil {
+		w.logger.Errorf("[fill] Failed to store airport data for ICAO code %s: %v", *airport.ICAOID, err)
+		w.fail(ctx, airport, "Failed to store airport data: "+err.Error())
+		return
+	}
+
+	w.airportCache.Invalidate(ctx, updated)
+	w.logger.Debugf("[fill] Airport data for ICAO code %s synced", *airport.ICAOID)
+}
+
+// fail records a failed attempt. The airport is retried after
+// SYNC_WORKER_RETRY_DELAY until it has used up its attempts.
+func (w *SyncWorker) fail(ctx context.Context, airport model.Airport, message string) {
+	var attempts int64
+	if airport.SyncAttempts != nil {
+		attempts = *airport.SyncAttempts
+	}
+	if attempts >= int64(w.maxAttempts()) {
+		message = fmt.Sprintf("Giving up after %d attempts. %s", attempts, message)
+	}
+	w.finish(ctx, airport, enum.SYNC_ERROR, message)
+}
+
+func (w *SyncWorker) finish(ctx context.Context, airport model.Airport, status enum.SyncStatusEnum, message string) {
+	err := func() (err error) {
+		tx, err := w.db.Begin()
+		if err != nil {
+			return err
+		}
+		defer util.CommitOrRollbackErr(tx, &err)
+
+		return w.airportRepository.UpdateSyncStatus(ctx, tx, airport.ID.String(), status, message)
+	}()
+	if err != nil {
+		w.logger.Errorw(logrus.Fields{"icao_code": *airport.ICAOID, "error": err}, "[finish] Failed to record sync status")
+		return
+	}
+	w.logger.Debugf("[finish] ICAO code %s is %s: %s", *airport.ICAOID, status.String(), message)
+}
+
+func (w *SyncWorker) batchSize() int {
+	if w.cfg.SyncWorkerBatchSize > 0 {
+		return w.cfg.SyncWorkerBatchSize
+	}
+	return defaultSyncWorkerBatchSize
+}
+
+func (w *SyncWorker) maxAttempts() int {
+	if w.cfg.SyncWorkerMaxAttempts > 0 {
+		return w.cfg.SyncWorkerMaxAttempts
+	}
+	return defaultSyncWorkerMaxAttempts
+}
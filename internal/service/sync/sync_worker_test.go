@@ -0,0 +1,194 @@
+package service_sync
+
+import (
+	"context"
+	"errors"
+	"flight-api/config"
+	airport_dto "flight-api/internal/dto/airport"
+	"flight-api/internal/enum"
+	"flight-api/internal/model"
+	repo_airport "flight-api/internal/repository/airport"
+	service_aviation "flight-api/internal/service/aviation"
+	service_import "flight-api/internal/service/import"
+	"flight-api/pkg/logger"
+	"flight-api/util"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type workerDeps struct {
+	dbmock sqlmock.Sqlmock
+	repo   *repo_airport.AirportRepositoryMock
+	avi    *service_aviation.AviationServiceMock
+	worker *SyncWorker
+	now    time.Time
+}
+
+func newWorkerDeps(t *testing.T) *workerDeps {
+	t.Helper()
+	db, dbmock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	cfg := &config.Config{
+		SyncWorkerBatchSize:   10,
+		SyncWorkerMaxAttempts: 3,
+		SyncWorkerRetryDelay:  time.Minute,
+		SyncWorkerStaleAfter:  time.Hour,
+	}
+	d := &workerDeps{
+		dbmock: dbmock,
+		repo:   &repo_airport.AirportRepositoryMock{Mock: mock.Mock{}},
+		avi:    &service_aviation.AviationServiceMock{Mock: mock.Mock{}},
+		now:    time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC),
+	}
+	d.worker = NewSyncWorker(logger.NewLogger(logger.INFO_DEBUG_LEVEL), cfg, db, d.repo, d.avi, nil, service_import.SourcePriority{})
+	d.worker.now = func() time.Time { return d.now }
+	return d
+}
+
+func (d *workerDeps) expectTx() {
+	d.dbmock.ExpectBegin()
+	d.dbmock.ExpectCommit()
+}
+
+func (d *workerDeps) expectClaim(airports ...model.Airport) {
+	d.expectTx()
+	d.repo.Mock.On("ClaimForSync", mock.Anything, anyTx, 10, 3, d.now.Add(-time.Minute), d.now.Add(-time.Hour)).
+		Return(airports, nil).Once()
+}
+
+func claimedAirport(code string, attempts int64) model.Airport {
+	id := uuid.New()
+	return model.Airport{
+		ID:           &id,
+		ICAOID:       util.Ptr(code),
+		SyncStatus:   util.Ptr(int64(enum.SYNC_ON_PROCESS)),
+		SyncAttempts: util.Ptr(attempts),
+	}
+}
+
+func TestSyncWorker_RunOnce_FillsSeededRowsAndRecordsStatus(t *testing.T) {
+	d := newWorkerDeps(t)
+	jfk := claimedAirport("KJFK", 1)
+	lga := claimedAirport("KLGA", 1)
+	xxx := claimedAirport("KXXX", 1)
+
+	d.expectClaim(jfk, lga, xxx)
+	d.avi.Mock.On("FetchAirportDataBatched", mock.Anything, []string{"KJFK", "KLGA", "KXXX"}).
+		Return(map[string]airport_dto.AirportRequestDto{
+			"KJFK": {ICAOID: util.Ptr("KJFK"), Name: util.Ptr("John F Kennedy Intl")},
+			"KLGA": {ICAOID: util.Ptr("KLGA"), Name: util.Ptr("La Guardia")},
+			"KXXX": {},
+		}, nil).Once()
+
+	// KJFK: the seeded row is filled in, not inserted again
+	d.expectTx()
+	d.repo.Mock.On("FindByICAOID", mock.Anything, anyTx, "KJFK").Return(jfk, nil).Once()
+	d.repo.Mock.On("FindFieldSources", mock.Anything, anyTx, "KJFK").Return(map[string]string{}, nil).Once()
+	d.repo.Mock.On("Update", mock.Anything, anyTx, jfk.ID.String(), mock.MatchedBy(func(m model.Airport) bool {
+		return *m.Name == "John F Kennedy Intl"
+	})).Return(jfk, nil).Once()
+	d.repo.Mock.On("UpdateFieldSources", mock.Anything, anyTx, "KJFK", map[string]string{"name": "aviationapi"}).Return(nil).Once()
+	d.repo.Mock.On("UpdateSyncStatus", mock.Anything, anyTx, jfk.ID.String(), enum.SYNC_SYNCED, "synced").Return(nil).Once()
+
+	// KLGA: storing fails, so it is left for a retry
+	d.dbmock.ExpectBegin()
+	d.dbmock.ExpectRollback()
+	d.repo.Mock.On("FindByICAOID", mock.Anything, anyTx, "KLGA").Return(lga, nil).Once()
+	d.repo.Mock.On("FindFieldSources", mock.Anything, anyTx, "KLGA").Return(map[string]string{}, nil).Once()
+	d.repo.Mock.On("Update", mock.Anything, anyTx, lga.ID.String(), mock.Anything).
+		Return(model.Airport{}, errors.New("constraint violation")).Once()
+	d.expectTx()
+	d.repo.Mock.On("UpdateSyncStatus", mock.Anything, anyTx, lga.ID.String(), enum.SYNC_ERROR, "Failed to store airport data: constraint violation").
+		Return(nil).Once()
+
+	// KXXX: unknown to the Aviation API
+	d.expectTx()
+	d.repo.Mock.On("UpdateSyncStatus", mock.Anything, anyTx, xxx.ID.String(), enum.SYNC_NOT_FOUND, "No data found from Aviation API.").
+		Return(nil).Once()
+
+	require.Equal(t, 3, d.worker.RunOnce(context.Background()))
+
+	d.repo.Mock.AssertNotCalled(t, "Insert", mock.Anything, mock.Anything, mock.Anything)
+	d.repo.Mock.AssertExpectations(t)
+	d.avi.Mock.AssertExpectations(t)
+	require.NoError(t, d.dbmock.ExpectationsWereMet())
+}
+
+func TestSyncWorker_RunOnce_KeepsStoredAndHigherPriorityFields(t *testing.T) {
+	d := newWorkerDeps(t)
+	priority, err := service_import.ParseSourcePriority("nasr,aviationapi")
+	require.NoError(t, err)
+	d.worker.priority = priority
+
+	jfk := claimedAirport("KJFK", 1)
+	stored := jfk
+	stored.Name = util.Ptr("John F Kennedy International")
+	stored.City = util.Ptr("New York")
+	stored.State = util.Ptr("NY")
+
+	d.expectClaim(jfk)
+	d.avi.Mock.On("FetchAirportDataBatched", mock.Anything, []string{"KJFK"}).
+		Return(map[string]airport_dto.AirportRequestDto{
+			"KJFK": {ICAOID: util.Ptr("KJFK"), Name: util.Ptr("John F Kennedy Intl"), State: util.Ptr("New York"), Country: util.Ptr("US")},
+		}, nil).Once()
+
+	// The name came from NASR, which outranks the Aviation API; the city is
+	// missing from the fetched data and keeps its stored value.
+	d.expectTx()
+	d.repo.Mock.On("FindByICAOID", mock.Anything, anyTx, "KJFK").Return(stored, nil).Once()
+	d.repo.Mock.On("FindFieldSources", mock.Anything, anyTx, "KJFK").Return(map[string]string{"name": "nasr"}, nil).Once()
+	d.repo.Mock.On("Update", mock.Anything, anyTx, jfk.ID.String(), mock.MatchedBy(func(m model.Airport) bool {
+		return *m.Name == "John F Kennedy International" && *m.City == "New York" && *m.State == "New York" && *m.Country == "US"
+	})).Return(stored, nil).Once()
+	d.repo.Mock.On("UpdateFieldSources", mock.Anything, anyTx, "KJFK", map[string]string{"state": "aviationapi", "country": "aviationapi"}).Return(nil).Once()
+	d.repo.Mock.On("UpdateSyncStatus", mock.Anything, anyTx, jfk.ID.String(), enum.SYNC_SYNCED, "synced").Return(nil).Once()
+
+	require.Equal(t, 1, d.worker.RunOnce(context.Background()))
+
+	d.repo.Mock.AssertExpectations(t)
+	require.NoError(t, d.dbmock.ExpectationsWereMet())
+}
+
+func TestSyncWorker_RunOnce_FetchErrorGivesUpAfterMaxAttempts(t *testing.T) {
+	d := newWorkerDeps(t)
+	retry := claimedAirport("KJFK", 1)
+	last := claimedAirport("KLGA", 3)
+
+	d.expectClaim(retry, last)
+	d.avi.Mock.On("FetchAirportDataBatched", mock.Anything, []string{"KJFK", "KLGA"}).
+		Return(nil, map[string]error{"KJFK": util.ErrGatewayTimeout, "KLGA": util.ErrGatewayTimeout}).Once()
+
+	d.expectTx()
+	d.repo.Mock.On("UpdateSyncStatus", mock.Anything, anyTx, retry.ID.String(), enum.SYNC_ERROR,
+		mock.MatchedBy(func(message string) bool {
+			return strings.HasPrefix(message, "Failed to fetch airport data")
+		})).Return(nil).Once()
+	d.expectTx()
+	d.repo.Mock.On("UpdateSyncStatus", mock.Anything, anyTx, last.ID.String(), enum.SYNC_ERROR,
+		mock.MatchedBy(func(message string) bool {
+			return strings.HasPrefix(message, "Giving up after 3 attempts. Failed to fetch airport data")
+		})).Return(nil).Once()
+
+	require.Equal(t, 2, d.worker.RunOnce(context.Background()))
+
+	d.repo.Mock.AssertExpectations(t)
+	require.NoError(t, d.dbmock.ExpectationsWereMet())
+}
+
+func TestSyncWorker_RunOnce_NothingToClaim(t *testing.T) {
+	d := newWorkerDeps(t)
+	d.expectClaim()
+
+	require.Equal(t, 0, d.worker.RunOnce(context.Background()))
+
+	d.avi.Mock.AssertNotCalled(t, "FetchAirportDataBatched", mock.Anything, mock.Anything)
+	require.NoError(t, d.dbmock.ExpectationsWereMet())
+}
@@ -0,0 +1,105 @@
+package service_weather
+
+import (
+	"context"
+	"errors"
+	"flight-api/config"
+	"flight-api/pkg/httpclient"
+	"flight-api/pkg/logger"
+	"flight-api/util"
+	"fmt"
+	"net"
+	"strings"
+)
+
+// Provider names accepted in WEATHER_PROVIDERS. They are also reported as
+// the `source` of every weather response.
+const (
+	ProviderWeatherAPI = "weatherapi"
+	ProviderOpenMeteo  = "openmeteo"
+	ProviderFake       = "fake"
+)
+
+// IWeatherProvider is one upstream source of current conditions. Providers
+// normalize their payload into weather_dto.WeatherDto and report failures as
+// AppErrors, so the fallback chain can tell a missing location from an
+// unavailable provider.
+type IWeatherProvider interface {
+	IWeatherService
+	Name() string
+}
+
+// upstreamProvider is implemented by providers that call an HTTP upstream.
+type upstreamProvider interface {
+	Client() *httpclient.Client
+}
+
+// NewWeatherProviders builds the providers named in WEATHER_PROVIDERS, in
+// order. An empty setting means WeatherAPI only.
+func NewWeatherProviders(logger *logger.Logger, cfg *config.Config) ([]IWeatherProvider, error) {
+	names := ParseProviderNames(cfg.WeatherProviders)
+	if len(names) == 0 {
+		names = []string{ProviderWeatherAPI}
+	}
+
+	providers := make([]IWeatherProvider, 0, len(names))
+	for _, name := range names {
+		switch name {
+		case ProviderWeatherAPI:
+			providers = append(providers, NewWeatherAPIProvider(logger, cfg, NewWeatherAPIClient(logger, cfg)))
+		case ProviderOpenMeteo:
+			providers = append(providers, NewOpenMeteoProvider(logger, cfg, NewOpenMeteoClient(logger, cfg)))
+		case ProviderFake:
+			providers = append(providers, NewFakeWeatherProvider(ProviderFake))
+		default:
+			return nil, fmt.Errorf("unknown weather provider %q", name)
+		}
+	}
+
+	return providers, nil
+}
+
+// ParseProviderNames splits a comma separated provider list, dropping blanks
+// and duplicates while keeping the order.
+func ParseProviderNames(value string) []string {
+	var names []string
+	seen := make(map[string]struct{})
+	for _, name := range strings.Split(value, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		if _, ok := seen[name]; ok {
+			continue
+		}
+		seen[name] = struct{}{}
+		names = append(names, name)
+	}
+	return names
+}
+
+// ProviderClients returns the HTTP clients behind providers, for reporting
+// their circuit breakers on the status endpoint.
+func ProviderClients(providers []IWeatherProvider) []*httpclient.Client {
+	var clients []*httpclient.Client
+	for _, p := range providers {
+		if u, ok := p.(upstreamProvider); ok {
+			clients = append(clients, u.Client())
+		}
+	}
+	return clients
+}
+
+// requestError maps a failed upstream call to an AppError.
+func requestError(err error) error {
+	if errors.Is(err, httpclient.ErrCircuitOpen) {
+		return util.NewAppError(util.ErrServiceUnavailable, "Weather provider is temporarily unavailable", err)
+	}
+
+	var ne net.Error
+	if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &ne) && ne.Timeout()) {
+		return util.ErrGatewayTimeout
+	}
+
+	return util.ErrInternalServer
+}
@@ -0,0 +1,235 @@
+package service_weather
+
+import (
+	"context"
+	location_dto "flight-api/internal/dto/location"
+	weather_dto "flight-api/internal/dto/weather"
+	"flight-api/util"
+	"hash/fnv"
+	"math"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FakeWeatherProvider serves weather without any network access, for tests
+// and offline development (WEATHER_PROVIDERS=fake). Locations registered with
+// Set return that payload; Fail makes every call return an error; any other
+// location gets plausible conditions derived from its name, so the same
+// location always reports the same weather.
+type FakeWeatherProvider struct {
+	name string
+	now  func() time.Time
+
+	mu      sync.Mutex
+	weather map[string]*weather_dto.WeatherDto
+	err     error
+	calls   int
+}
+
+func NewFakeWeatherProvider(name string) *FakeWeatherProvider {
+	return &FakeWeatherProvider{
+		name:    name,
+		now:     time.Now,
+		weather: make(map[string]*weather_dto.WeatherDto),
+	}
+}
+
+func (p *FakeWeatherProvider) Name() string {
+	return p.name
+}
+
+// Set registers the current conditions returned for loc. Forecasts are
+// always generated.
+func (p *FakeWeatherProvider) Set(loc string, data *weather_dto.WeatherDto) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.weather[fakeKey(loc)] = data
+}
+
+// Fail makes every following call return err; nil restores normal answers.
+func (p *FakeWeatherProvider) Fail(err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.err = err
+}
+
+// Calls returns how many lookups the provider has served.
+func (p *FakeWeatherProvider) Calls() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.calls
+}
+
+func (p *FakeWeatherProvider) GetWeatherCondition(ctx context.Context, loc *string) (*weather_dto.WeatherDto, error) {
+	if loc == nil || strings.TrimSpace(*loc) == "" {
+		return nil, util.NewAppError(util.ErrBadRequest, "Weather location query is missing", nil)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.calls++
+	if p.err != nil {
+		return nil, p.err
+	}
+	if data, ok := p.weather[fakeKey(*loc)]; ok {
+		// Hand out a copy so callers stamping provenance do not race.
+		out := *data
+		return &out, nil
+	}
+
+	return p.generate(*loc), nil
+}
+
+func (p *FakeWeatherProvider) GetWeatherForecast(ctx context.Context, loc *string, days int) (*weather_dto.ForecastDto, error) {
+	if loc == nil || strings.TrimSpace(*loc) == "" {
+		return nil, util.NewAppError(util.ErrBadRequest, "Weather location query is missing", nil)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.calls++
+	if p.err != nil {
+		return nil, p.err
+	}
+
+	return p.generateForecast(*loc, days), nil
+}
+
+// generateForecast derives hourly periods for days days from midnight UTC:
+// the generated current conditions with a diurnal temperature swing.
+func (p *FakeWeatherProvider) generateForecast(loc string, days int) *weather_dto.ForecastDto {
+	weather := p.generate(loc)
+	base := weather.Current
+	midnight := p.now().UTC().Truncate(24 * time.Hour)
+
+	forecastDays := make([]weather_dto.ForecastDayDto, days)
+	for d := range forecastDays {
+		date := midnight.AddDate(0, 0, d)
+		hours := make([]weather_dto.HourlyWeatherDto, 24)
+		minTemp, maxTemp, sumTemp := math.Inf(1), math.Inf(-1), 0.0
+		for h := range hours {
+			start := date.Add(time.Duration(h) * time.Hour)
+			tempC := round1(*base.TempC + 4*math.Sin(float64(h-9)*math.Pi/12) + 0.5*float64(d))
+			isDay := uint8(0)
+			if h >= 6 && h < 18 {
+				isDay = 1
+			}
+
+			hours[h] = weather_dto.HourlyWeatherDto{
+				TimeEpoch:  util.Ptr(int(start.Unix())),
+				Time:       util.Ptr(start.Format("2006-01-02 15:04")),
+				TempC:      util.Ptr(tempC),
+				TempF:      util.Ptr(celsiusToFahrenheit(tempC)),
+				IsDay:      &isDay,
+				Condition:  base.Condition,
+				WindKph:    base.WindKph,
+				WindMph:    base.WindMph,
+				WindDegree: base.WindDegree,
+				WindDir:    base.WindDir,
+				PressureMb: base.PressureMb,
+				PressureIn: base.PressureIn,
+				PrecipMm:   base.PrecipMm,
+				PrecipIn:   base.PrecipIn,
+				Humidity:   base.Humidity,
+				Cloud:      base.Cloud,
+				VisKm:      base.VisKm,
+				VisMiles:   base.VisMiles,
+			}
+
+			minTemp, maxTemp, sumTemp = math.Min(minTemp, tempC), math.Max(maxTemp, tempC), sumTemp+tempC
+		}
+
+		avgTemp := round1(sumTemp / float64(len(hours)))
+		forecastDays[d] = weather_dto.ForecastDayDto{
+			Date:      util.Ptr(date.Format("2006-01-02")),
+			DateEpoch: util.Ptr(int(date.Unix())),
+			Day: &weather_dto.DayWeatherDto{
+				MaxtempC:      util.Ptr(maxTemp),
+				MaxtempF:      util.Ptr(celsiusToFahrenheit(maxTemp)),
+				MintempC:      util.Ptr(minTemp),
+				MintempF:      util.Ptr(celsiusToFahrenheit(minTemp)),
+				AvgtempC:      util.Ptr(avgTemp),
+				AvgtempF:      util.Ptr(celsiusToFahrenheit(avgTemp)),
+				MaxwindKph:    base.WindKph,
+				MaxwindMph:    base.WindMph,
+				TotalprecipMm: util.Ptr(0.0),
+				TotalprecipIn: util.Ptr(0.0),
+				AvgvisKm:      base.VisKm,
+				AvgvisMiles:   base.VisMiles,
+				Avghumidity:   base.Humidity,
+				Condition:     base.Condition,
+			},
+			Hour: hours,
+		}
+	}
+
+	return &weather_dto.ForecastDto{
+		Object:   util.Ptr("forecast"),
+		Location: weather.Location,
+		Current:  base,
+		Forecast: &weather_dto.ForecastPeriodsDto{ForecastDay: forecastDays},
+	}
+}
+
+// generate derives stable, plausible conditions from loc.
+func (p *FakeWeatherProvider) generate(loc string) *weather_dto.WeatherDto {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(fakeKey(loc)))
+	seed := int(h.Sum32())
+
+	now := p.now().UTC()
+	tempC := float64(seed%400)/10 - 5 // -5.0 .. 34.9
+	windKph := float64(seed/7%400) / 10
+	windDegree := seed / 11 % 360
+	pressureMb := float64(990 + seed/13%40)
+	humidity := 20 + seed/17%80
+	cloud := seed / 19 % 101
+	visKm := float64(1 + seed/23%10)
+	isDay := uint8(0)
+	if hour := now.Hour(); hour >= 6 && hour < 18 {
+		isDay = 1
+	}
+
+	return &weather_dto.WeatherDto{
+		Object: util.Ptr("weather"),
+		Location: &location_dto.LocationDto{
+			Name:           util.Ptr(strings.TrimSpace(loc)),
+			TzId:           util.Ptr("UTC"),
+			LocaltimeEpoch: util.Ptr(int(now.Unix())),
+			Localtime:      util.Ptr(now.Format("2006-01-02 15:04")),
+		},
+		Current: &weather_dto.CurrentWeatherDto{
+			LastUpdatedEpoch: util.Ptr(int(now.Unix())),
+			LastUpdated:      util.Ptr(now.Format("2006-01-02 15:04")),
+			TempC:            util.Ptr(tempC),
+			TempF:            util.Ptr(celsiusToFahrenheit(tempC)),
+			IsDay:            &isDay,
+			Condition: &weather_dto.ConditionDto{
+				Text: util.Ptr(wmoCondition(cloud / 34)),
+				Code: util.Ptr(cloud / 34),
+			},
+			WindKph:    util.Ptr(windKph),
+			WindMph:    util.Ptr(kphToMph(windKph)),
+			WindDegree: util.Ptr(windDegree),
+			WindDir:    util.Ptr(compassPoint(windDegree)),
+			PressureMb: util.Ptr(pressureMb),
+			PressureIn: util.Ptr(mbToInHg(pressureMb)),
+			PrecipMm:   util.Ptr(0.0),
+			PrecipIn:   util.Ptr(0.0),
+			Humidity:   util.Ptr(humidity),
+			Cloud:      util.Ptr(cloud),
+			VisKm:      util.Ptr(visKm),
+			VisMiles:   util.Ptr(round1(visKm / 1.609344)),
+		},
+	}
+}
+
+func fakeKey(loc string) string {
+	return strings.ToUpper(strings.TrimSpace(loc))
+}
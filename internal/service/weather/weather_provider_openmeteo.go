@@ -0,0 +1,624 @@
+package service_weather
+
+import (
+	"context"
+	"flight-api/config"
+	location_dto "flight-api/internal/dto/location"
+	weather_dto "flight-api/internal/dto/weather"
+	"flight-api/pkg/httpclient"
+	"flight-api/pkg/logger"
+	"flight-api/util"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Variables requested from /v1/forecast for current conditions and for the
+// hourly and daily forecast periods.
+const (
+	openMeteoCurrent = "temperature_2m,relative_humidity_2m,apparent_temperature,is_day,precipitation," +
+		"weather_code,cloud_cover,pressure_msl,wind_speed_10m,wind_direction_10m,wind_gusts_10m,dew_point_2m,visibility"
+	openMeteoHourly = "temperature_2m,relative_humidity_2m,apparent_temperature,dew_point_2m,precipitation_probability," +
+		"precipitation,snowfall,weather_code,pressure_msl,cloud_cover,visibility,wind_speed_10m,wind_direction_10m," +
+		"wind_gusts_10m,is_day,uv_index"
+	openMeteoDaily = "weather_code,temperature_2m_max,temperature_2m_min,precipitation_sum,snowfall_sum," +
+		"precipitation_probability_max,wind_speed_10m_max,uv_index_max"
+)
+
+// rainLikelyPercent is the precipitation probability from which a period is
+// reported as will_it_rain, as WeatherAPI does.
+const rainLikelyPercent = 50
+
+// OpenMeteoProvider reads current conditions and forecasts from Open-Meteo. It needs
+// coordinates, so a location that is not a "lat,lon" pair is resolved with
+// the Open-Meteo geocoding API first. No API key is required.
+type OpenMeteoProvider struct {
+	logger *logger.Logger
+	cfg    *config.Config
+	client *httpclient.Client
+}
+
+// NewOpenMeteoClient builds the HTTP client used to call Open-Meteo, with the
+// timeout, retry and circuit breaker settings from cfg.
+func NewOpenMeteoClient(logger *logger.Logger, cfg *config.Config) *httpclient.Client {
+	return httpclient.NewClient(logger, httpclient.Options{
+		Name:                  ProviderOpenMeteo,
+		Timeout:               cfg.WeatherTimeout,
+		MaxRetries:            cfg.HTTPMaxRetries,
+		RetryBaseDelay:        cfg.HTTPRetryBase,
+		RetryMaxDelay:         cfg.HTTPRetryMax,
+		BreakerThreshold:      cfg.BreakerThreshold,
+		BreakerOpenTimeout:    cfg.BreakerOpenTimeout,
+		BreakerHalfOpenProbes: cfg.BreakerHalfOpenProbes,
+	})
+}
+
+func NewOpenMeteoProvider(logger *logger.Logger, cfg *config.Config, client *httpclient.Client) IWeatherProvider {
+	return &OpenMeteoProvider{
+		logger: logger,
+		cfg:    cfg,
+		client: client,
+	}
+}
+
+func (s *OpenMeteoProvider) Name() string {
+	return ProviderOpenMeteo
+}
+
+func (s *OpenMeteoProvider) Client() *httpclient.Client {
+	return s.client
+}
+
+type openMeteoPlace struct {
+	Name      string  `json:"name"`
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+	Country   string  `json:"country"`
+	Admin1    string  `json:"admin1"`
+	Timezone  string  `json:"timezone"`
+}
+
+type openMeteoForecast struct {
+	Latitude         float64                `json:"latitude"`
+	Longitude        float64                `json:"longitude"`
+	Timezone         string                 `json:"timezone"`
+	UTCOffsetSeconds int                    `json:"utc_offset_seconds"`
+	Hourly           *openMeteoHourlySeries `json:"hourly"`
+	Daily            *openMeteoDailySeries  `json:"daily"`
+	Current          *struct {
+		Time                int64    `json:"time"`
+		Temperature2m       *float64 `json:"temperature_2m"`
+		RelativeHumidity2m  *int     `json:"relative_humidity_2m"`
+		ApparentTemperature *float64 `json:"apparent_temperature"`
+		IsDay               *uint8   `json:"is_day"`
+		Precipitation       *float64 `json:"precipitation"`
+		WeatherCode         *int     `json:"weather_code"`
+		CloudCover          *int     `json:"cloud_cover"`
+		PressureMsl         *float64 `json:"pressure_msl"`
+		WindSpeed10m        *float64 `json:"wind_speed_10m"`
+		WindDirection10m    *int     `json:"wind_direction_10m"`
+		WindGusts10m        *float64 `json:"wind_gusts_10m"`
+		DewPoint2m          *float64 `json:"dew_point_2m"`
+		Visibility          *float64 `json:"visibility"`
+	} `json:"current"`
+}
+
+// openMeteoHourlySeries holds one array per variable, indexed like Time.
+// Open-Meteo reports missing values as null.
+type openMeteoHourlySeries struct {
+	Time                     []int64    `json:"time"`
+	Temperature2m            []*float64 `json:"temperature_2m"`
+	RelativeHumidity2m       []*int     `json:"relative_humidity_2m"`
+	ApparentTemperature      []*float64 `json:"apparent_temperature"`
+	DewPoint2m               []*float64 `json:"dew_point_2m"`
+	PrecipitationProbability []*int     `json:"precipitation_probability"`
+	Precipitation            []*float64 `json:"precipitation"`
+	Snowfall                 []*float64 `json:"snowfall"`
+	WeatherCode              []*int     `json:"weather_code"`
+	PressureMsl              []*float64 `json:"pressure_msl"`
+	CloudCover               []*int     `json:"cloud_cover"`
+	Visibility               []*float64 `json:"visibility"`
+	WindSpeed10m             []*float64 `json:"wind_speed_10m"`
+	WindDirection10m         []*int     `json:"wind_direction_10m"`
+	WindGusts10m             []*float64 `json:"wind_gusts_10m"`
+	IsDay                    []*uint8   `json:"is_day"`
+	UvIndex                  []*float64 `json:"uv_index"`
+}
+
+type openMeteoDailySeries struct {
+	Time                        []int64    `json:"time"`
+	WeatherCode                 []*int     `json:"weather_code"`
+	Temperature2mMax            []*float64 `json:"temperature_2m_max"`
+	Temperature2mMin            []*float64 `json:"temperature_2m_min"`
+	PrecipitationSum            []*float64 `json:"precipitation_sum"`
+	SnowfallSum                 []*float64 `json:"snowfall_sum"`
+	PrecipitationProbabilityMax []*int     `json:"precipitation_probability_max"`
+	WindSpeed10mMax             []*float64 `json:"wind_speed_10m_max"`
+	UvIndexMax                  []*float64 `json:"uv_index_max"`
+}
+
+func (s *OpenMeteoProvider) GetWeatherCondition(ctx context.Context, loc *string) (*weather_dto.WeatherDto, error) {
+	s.logger.Debug("[GetWeatherCondition] Fetching weather data from Open-Meteo...")
+
+	if loc == nil || strings.TrimSpace(*loc) == "" {
+		s.logger.Errorf("[GetWeatherCondition] Failed to fetch weather data: %v", util.ErrBadRequest)
+		return nil, util.NewAppError(util.ErrBadRequest, "Weather location query is missing", nil)
+	}
+
+	place, err := s.resolve(ctx, *loc)
+	if err != nil {
+		return nil, err
+	}
+
+	query := url.Values{}
+	query.Set("current", openMeteoCurrent)
+
+	forecast, err := s.forecast(ctx, place, query)
+	if err != nil {
+		return nil, err
+	}
+	if forecast.Current == nil {
+		s.logger.Error("[GetWeatherCondition] Open-Meteo response has no current conditions")
+		return nil, util.NewAppError(util.ErrBadGateway, "Weather provider returned no current conditions", nil)
+	}
+
+	s.logger.Debug("[GetWeatherCondition] Finished fetching weather data from Open-Meteo.")
+	return toOpenMeteoWeatherDto(place, forecast), nil
+}
+
+func (s *OpenMeteoProvider) GetWeatherForecast(ctx context.Context, loc *string, days int) (*weather_dto.ForecastDto, error) {
+	s.logger.Debug("[GetWeatherForecast] Fetching forecast data from Open-Meteo...")
+
+	if loc == nil || strings.TrimSpace(*loc) == "" {
+		s.logger.Errorf("[GetWeatherForecast] Failed to fetch forecast data: %v", util.ErrBadRequest)
+		return nil, util.NewAppError(util.ErrBadRequest, "Weather location query is missing", nil)
+	}
+
+	place, err := s.resolve(ctx, *loc)
+	if err != nil {
+		return nil, err
+	}
+
+	query := url.Values{}
+	query.Set("current", openMeteoCurrent)
+	query.Set("hourly", openMeteoHourly)
+	query.Set("daily", openMeteoDaily)
+	query.Set("forecast_days", strconv.Itoa(days))
+
+	forecast, err := s.forecast(ctx, place, query)
+	if err != nil {
+		return nil, err
+	}
+	if forecast.Hourly == nil || forecast.Daily == nil {
+		s.logger.Error("[GetWeatherForecast] Open-Meteo response has no forecast periods")
+		return nil, util.NewAppError(util.ErrBadGateway, "Weather provider returned no forecast", nil)
+	}
+
+	s.logger.Debug("[GetWeatherForecast] Finished fetching forecast data from Open-Meteo.")
+	return toOpenMeteoForecastDto(place, forecast), nil
+}
+
+// resolve returns the coordinates of loc, geocoding it unless it already is
+// a "lat,lon" pair.
+func (s *OpenMeteoProvider) resolve(ctx context.Context, loc string) (openMeteoPlace, error) {
+	if place, ok := parseLatLon(loc); ok {
+		return place, nil
+	}
+	return s.geocode(ctx, loc)
+}
+
+// forecast calls /v1/forecast for place with the variables in query.
+func (s *OpenMeteoProvider) forecast(ctx context.Context, place openMeteoPlace, query url.Values) (openMeteoForecast, error) {
+	query.Set("latitude", strconv.FormatFloat(place.Latitude, 'f', -1, 64))
+	query.Set("longitude", strconv.FormatFloat(place.Longitude, 'f', -1, 64))
+	query.Set("wind_speed_unit", "kmh")
+	query.Set("timeformat", "unixtime")
+	query.Set("timezone", "auto")
+
+	var forecast openMeteoForecast
+	err := s.getJSON(ctx, s.cfg.OpenMeteoURL+"/v1/forecast?"+query.Encode(), &forecast)
+	return forecast, err
+}
+
+// geocode resolves a place name to coordinates with the first match.
+func (s *OpenMeteoProvider) geocode(ctx context.Context, loc string) (openMeteoPlace, error) {
+	query := url.Values{}
+	query.Set("name", strings.TrimSpace(loc))
+	query.Set("count", "1")
+	query.Set("language", "en")
+	query.Set("format", "json")
+
+	var result struct {
+		Results []openMeteoPlace `json:"results"`
+	}
+	if err := s.getJSON(ctx, s.cfg.OpenMeteoGeocodingURL+"/v1/search?"+query.Encode(), &result); err != nil {
+		return openMeteoPlace{}, err
+	}
+	if len(result.Results) == 0 {
+		s.logger.Debugf("[geocode] No Open-Meteo match for %s", loc)
+		return openMeteoPlace{}, util.NewAppError(util.ErrNotFound, "No matching location found", nil)
+	}
+
+	return result.Results[0], nil
+}
+
+func (s *OpenMeteoProvider) getJSON(ctx context.Context, URL string, out interface{}) error {
+	resp, err := s.client.Get(ctx, URL)
+	if err != nil {
+		s.logger.Errorf("[getJSON] Failed to call Open-Meteo: %v", err)
+		return requestError(err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		s.logger.Errorf("[getJSON] Failed to read response body: %v", err)
+		return util.ErrInternalServer
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		s.logger.Errorf("[getJSON] Open-Meteo returned %s", resp.Status)
+
+		// Open-Meteo reports bad parameters as {"error": true, "reason": "..."}
+		var errData struct {
+			Reason string `json:"reason"`
+		}
+		_ = util.ParseJSON(body, &errData)
+
+		if resp.StatusCode == http.StatusBadRequest {
+			return util.NewAppError(util.ErrBadRequest, "Weather provider rejected the request: "+errData.Reason, nil)
+		}
+		return util.NewAppError(util.ErrBadGateway, "Weather provider returned "+resp.Status, nil)
+	}
+
+	if err := util.ParseJSON(body, out); err != nil {
+		s.logger.Errorf("[getJSON] Failed to unmarshal response body: %v", err)
+		return util.ErrInternalServer
+	}
+
+	return nil
+}
+
+// parseLatLon accepts "lat,lon" in decimal degrees.
+func parseLatLon(loc string) (openMeteoPlace, bool) {
+	latStr, lonStr, ok := strings.Cut(loc, ",")
+	if !ok {
+		return openMeteoPlace{}, false
+	}
+
+	lat, err := strconv.ParseFloat(strings.TrimSpace(latStr), 64)
+	if err != nil || lat < -90 || lat > 90 {
+		return openMeteoPlace{}, false
+	}
+	lon, err := strconv.ParseFloat(strings.TrimSpace(lonStr), 64)
+	if err != nil || lon < -180 || lon > 180 {
+		return openMeteoPlace{}, false
+	}
+
+	return openMeteoPlace{Latitude: lat, Longitude: lon}, true
+}
+
+func toOpenMeteoWeatherDto(place openMeteoPlace, forecast openMeteoForecast) *weather_dto.WeatherDto {
+	return &weather_dto.WeatherDto{
+		Object:   util.Ptr("weather"),
+		Location: toOpenMeteoLocation(place, forecast),
+		Current:  toOpenMeteoCurrent(forecast),
+	}
+}
+
+func toOpenMeteoForecastDto(place openMeteoPlace, forecast openMeteoForecast) *weather_dto.ForecastDto {
+	zone := openMeteoZone(forecast)
+	daily := forecast.Daily
+	hourly := forecast.Hourly
+
+	days := make([]weather_dto.ForecastDayDto, len(daily.Time))
+	dayIndex := make(map[string]int, len(daily.Time))
+	for i, epoch := range daily.Time {
+		date := time.Unix(epoch, 0).In(zone).Format("2006-01-02")
+		dayIndex[date] = i
+		days[i] = weather_dto.ForecastDayDto{
+			Date:      util.Ptr(date),
+			DateEpoch: util.Ptr(int(epoch)),
+			Day:       toOpenMeteoDay(daily, i),
+		}
+	}
+
+	for i, epoch := range hourly.Time {
+		start := time.Unix(epoch, 0).In(zone)
+		d, ok := dayIndex[start.Format("2006-01-02")]
+		if !ok {
+			continue
+		}
+		days[d].Hour = append(days[d].Hour, toOpenMeteoHour(hourly, i, start))
+	}
+
+	for i := range days {
+		summarizeHours(days[i].Day, days[i].Hour)
+	}
+
+	dto := &weather_dto.ForecastDto{
+		Object:   util.Ptr("forecast"),
+		Location: toOpenMeteoLocation(place, forecast),
+		Forecast: &weather_dto.ForecastPeriodsDto{ForecastDay: days},
+	}
+	if forecast.Current != nil {
+		dto.Current = toOpenMeteoCurrent(forecast)
+	}
+	return dto
+}
+
+func openMeteoZone(forecast openMeteoForecast) *time.Location {
+	return time.FixedZone(forecast.Timezone, forecast.UTCOffsetSeconds)
+}
+
+func toOpenMeteoLocation(place openMeteoPlace, forecast openMeteoForecast) *location_dto.LocationDto {
+	localNow := time.Now().In(openMeteoZone(forecast))
+
+	return &location_dto.LocationDto{
+		Lat:            util.Ptr(forecast.Latitude),
+		Lon:            util.Ptr(forecast.Longitude),
+		TzId:           nonEmpty(forecast.Timezone),
+		LocaltimeEpoch: util.Ptr(int(localNow.Unix())),
+		Localtime:      util.Ptr(localNow.Format("2006-01-02 15:04")),
+		Name:           nonEmpty(place.Name),
+		Region:         nonEmpty(place.Admin1),
+		Country:        nonEmpty(place.Country),
+	}
+}
+
+func toOpenMeteoCurrent(forecast openMeteoForecast) *weather_dto.CurrentWeatherDto {
+	current := forecast.Current
+	observed := time.Unix(current.Time, 0).In(openMeteoZone(forecast))
+
+	return &weather_dto.CurrentWeatherDto{
+		LastUpdatedEpoch: util.Ptr(int(current.Time)),
+		LastUpdated:      util.Ptr(observed.Format("2006-01-02 15:04")),
+		TempC:            current.Temperature2m,
+		TempF:            mapFloat(current.Temperature2m, celsiusToFahrenheit),
+		IsDay:            current.IsDay,
+		WindKph:          current.WindSpeed10m,
+		WindMph:          mapFloat(current.WindSpeed10m, kphToMph),
+		WindDegree:       current.WindDirection10m,
+		WindDir:          compassPointPtr(current.WindDirection10m),
+		PressureMb:       current.PressureMsl,
+		PressureIn:       mapFloat(current.PressureMsl, mbToInHg),
+		PrecipMm:         current.Precipitation,
+		PrecipIn:         mapFloat(current.Precipitation, mmToIn),
+		Humidity:         current.RelativeHumidity2m,
+		Cloud:            current.CloudCover,
+		FeelslikeC:       current.ApparentTemperature,
+		FeelslikeF:       mapFloat(current.ApparentTemperature, celsiusToFahrenheit),
+		DewpointC:        current.DewPoint2m,
+		DewpointF:        mapFloat(current.DewPoint2m, celsiusToFahrenheit),
+		VisKm:            mapFloat(current.Visibility, metresToKm),
+		VisMiles:         mapFloat(current.Visibility, metresToMiles),
+		GustKph:          current.WindGusts10m,
+		GustMph:          mapFloat(current.WindGusts10m, kphToMph),
+		Condition:        wmoConditionDto(current.WeatherCode),
+	}
+}
+
+func toOpenMeteoHour(hourly *openMeteoHourlySeries, i int, start time.Time) weather_dto.HourlyWeatherDto {
+	temp := valueAt(hourly.Temperature2m, i)
+	wind := valueAt(hourly.WindSpeed10m, i)
+	windDegree := valueAt(hourly.WindDirection10m, i)
+	pressure := valueAt(hourly.PressureMsl, i)
+	precip := valueAt(hourly.Precipitation, i)
+	feelsLike := valueAt(hourly.ApparentTemperature, i)
+	dewPoint := valueAt(hourly.DewPoint2m, i)
+	visibility := valueAt(hourly.Visibility, i)
+	gust := valueAt(hourly.WindGusts10m, i)
+	chanceOfRain := valueAt(hourly.PrecipitationProbability, i)
+	snowfall := valueAt(hourly.Snowfall, i)
+
+	return weather_dto.HourlyWeatherDto{
+		TimeEpoch:    util.Ptr(int(start.Unix())),
+		Time:         util.Ptr(start.Format("2006-01-02 15:04")),
+		TempC:        temp,
+		TempF:        mapFloat(temp, celsiusToFahrenheit),
+		IsDay:        valueAt(hourly.IsDay, i),
+		Condition:    wmoConditionDto(valueAt(hourly.WeatherCode, i)),
+		WindKph:      wind,
+		WindMph:      mapFloat(wind, kphToMph),
+		WindDegree:   windDegree,
+		WindDir:      compassPointPtr(windDegree),
+		PressureMb:   pressure,
+		PressureIn:   mapFloat(pressure, mbToInHg),
+		PrecipMm:     precip,
+		PrecipIn:     mapFloat(precip, mmToIn),
+		SnowCm:       snowfall,
+		Humidity:     valueAt(hourly.RelativeHumidity2m, i),
+		Cloud:        valueAt(hourly.CloudCover, i),
+		FeelslikeC:   feelsLike,
+		FeelslikeF:   mapFloat(feelsLike, celsiusToFahrenheit),
+		DewpointC:    dewPoint,
+		DewpointF:    mapFloat(dewPoint, celsiusToFahrenheit),
+		WillItRain:   likely(chanceOfRain),
+		ChanceOfRain: chanceOfRain,
+		WillItSnow:   positive(snowfall),
+		VisKm:        mapFloat(visibility, metresToKm),
+		VisMiles:     mapFloat(visibility, metresToMiles),
+		GustKph:      gust,
+		GustMph:      mapFloat(gust, kphToMph),
+		Uv:           valueAt(hourly.UvIndex, i),
+	}
+}
+
+func toOpenMeteoDay(daily *openMeteoDailySeries, i int) *weather_dto.DayWeatherDto {
+	maxTemp := valueAt(daily.Temperature2mMax, i)
+	minTemp := valueAt(daily.Temperature2mMin, i)
+	maxWind := valueAt(daily.WindSpeed10mMax, i)
+	precip := valueAt(daily.PrecipitationSum, i)
+	snowfall := valueAt(daily.SnowfallSum, i)
+	chanceOfRain := valueAt(daily.PrecipitationProbabilityMax, i)
+
+	return &weather_dto.DayWeatherDto{
+		MaxtempC:          maxTemp,
+		MaxtempF:          mapFloat(maxTemp, celsiusToFahrenheit),
+		MintempC:          minTemp,
+		MintempF:          mapFloat(minTemp, celsiusToFahrenheit),
+		MaxwindKph:        maxWind,
+		MaxwindMph:        mapFloat(maxWind, kphToMph),
+		TotalprecipMm:     precip,
+		TotalprecipIn:     mapFloat(precip, mmToIn),
+		TotalsnowCm:       snowfall,
+		DailyWillItRain:   likely(chanceOfRain),
+		DailyChanceOfRain: chanceOfRain,
+		DailyWillItSnow:   positive(snowfall),
+		Condition:         wmoConditionDto(valueAt(daily.WeatherCode, i)),
+		Uv:                valueAt(daily.UvIndexMax, i),
+	}
+}
+
+// summarizeHours fills the daily averages Open-Meteo does not report from the
+// day's hourly periods.
+func summarizeHours(day *weather_dto.DayWeatherDto, hours []weather_dto.HourlyWeatherDto) {
+	var temp, humidity, vis float64
+	var nTemp, nHumidity, nVis int
+	for _, h := range hours {
+		if h.TempC != nil {
+			temp += *h.TempC
+			nTemp++
+		}
+		if h.Humidity != nil {
+			humidity += float64(*h.Humidity)
+			nHumidity++
+		}
+		if h.VisKm != nil {
+			vis += *h.VisKm
+			nVis++
+		}
+	}
+
+	if nTemp > 0 {
+		day.AvgtempC = util.Ptr(round1(temp / float64(nTemp)))
+		day.AvgtempF = mapFloat(day.AvgtempC, celsiusToFahrenheit)
+	}
+	if nHumidity > 0 {
+		day.Avghumidity = util.Ptr(int(math.Round(humidity / float64(nHumidity))))
+	}
+	if nVis > 0 {
+		day.AvgvisKm = util.Ptr(round1(vis / float64(nVis)))
+		day.AvgvisMiles = util.Ptr(round1(*day.AvgvisKm / 1.609344))
+	}
+}
+
+// wmoConditions are the WMO weather interpretation codes used by Open-Meteo.
+var wmoConditions = map[int]string{
+	0:  "Clear sky",
+	1:  "Mainly clear",
+	2:  "Partly cloudy",
+	3:  "Overcast",
+	45: "Fog",
+	48: "Depositing rime fog",
+	51: "Light drizzle",
+	53: "Moderate drizzle",
+	55: "Dense drizzle",
+	56: "Light freezing drizzle",
+	57: "Dense freezing drizzle",
+	61: "Slight rain",
+	63: "Moderate rain",
+	65: "Heavy rain",
+	66: "Light freezing rain",
+	67: "Heavy freezing rain",
+	71: "Slight snow fall",
+	73: "Moderate snow fall",
+	75: "Heavy snow fall",
+	77: "Snow grains",
+	80: "Slight rain showers",
+	81: "Moderate rain showers",
+	82: "Violent rain showers",
+	85: "Slight snow showers",
+	86: "Heavy snow showers",
+	95: "Thunderstorm",
+	96: "Thunderstorm with slight hail",
+	99: "Thunderstorm with heavy hail",
+}
+
+func wmoCondition(code int) string {
+	if text, ok := wmoConditions[code]; ok {
+		return text
+	}
+	return "Unknown"
+}
+
+func wmoConditionDto(code *int) *weather_dto.ConditionDto {
+	if code == nil {
+		return nil
+	}
+	return &weather_dto.ConditionDto{
+		Text: util.Ptr(wmoCondition(*code)),
+		Code: code,
+	}
+}
+
+var compassPoints = [...]string{"N", "NNE", "NE", "ENE", "E", "ESE", "SE", "SSE", "S", "SSW", "SW", "WSW", "W", "WNW", "NW", "NNW"}
+
+// compassPoint converts a bearing in degrees to a 16-point compass name.
+func compassPoint(degrees int) string {
+	index := int(math.Round(float64(((degrees%360)+360)%360)/22.5)) % len(compassPoints)
+	return compassPoints[index]
+}
+
+func compassPointPtr(degrees *int) *string {
+	if degrees == nil {
+		return nil
+	}
+	return util.Ptr(compassPoint(*degrees))
+}
+
+func celsiusToFahrenheit(c float64) float64 { return round1(c*9/5 + 32) }
+func kphToMph(kph float64) float64          { return round1(kph / 1.609344) }
+func mbToInHg(mb float64) float64           { return math.Round(mb*0.02953*100) / 100 }
+func mmToIn(mm float64) float64             { return math.Round(mm/25.4*100) / 100 }
+func metresToKm(m float64) float64          { return round1(m / 1000) }
+func metresToMiles(m float64) float64       { return round1(m / 1609.344) }
+
+func round1(v float64) float64 { return math.Round(v*10) / 10 }
+
+func mapFloat(v *float64, f func(float64) float64) *float64 {
+	if v == nil {
+		return nil
+	}
+	return util.Ptr(f(*v))
+}
+
+func nonEmpty(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+// valueAt returns values[i], or nil when the series is shorter.
+func valueAt[T any](values []*T, i int) *T {
+	if i >= len(values) {
+		return nil
+	}
+	return values[i]
+}
+
+// likely reports 1 when a precipitation probability reaches rainLikelyPercent.
+func likely(chance *int) *int {
+	if chance == nil {
+		return nil
+	}
+	if *chance >= rainLikelyPercent {
+		return util.Ptr(1)
+	}
+	return util.Ptr(0)
+}
+
+func positive(amount *float64) *int {
+	if amount == nil {
+		return nil
+	}
+	if *amount > 0 {
+		return util.Ptr(1)
+	}
+	return util.Ptr(0)
+}
@@ -0,0 +1,185 @@
+package service_weather
+
+import (
+	"context"
+	"flight-api/config"
+	"flight-api/pkg/httpclient"
+	"flight-api/util"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const openMeteoForecastBody = `{
+	"latitude": -6.125, "longitude": 106.625,
+	"timezone": "Asia/Jakarta", "utc_offset_seconds": 25200,
+	"current": {
+		"time": 1759730400, "interval": 900,
+		"temperature_2m": 31.4, "relative_humidity_2m": 62, "apparent_temperature": 36.2,
+		"is_day": 1, "precipitation": 0.0, "weather_code": 2, "cloud_cover": 40,
+		"pressure_msl": 1009.8, "wind_speed_10m": 14.8, "wind_direction_10m": 338,
+		"wind_gusts_10m": 27.4, "dew_point_2m": 23.1, "visibility": 24140.0
+	}
+}`
+
+const openMeteoPeriodsBody = `{
+	"latitude": -6.125, "longitude": 106.625,
+	"timezone": "Asia/Jakarta", "utc_offset_seconds": 25200,
+	"hourly": {
+		"time": [1759683600, 1759687200, 1759770000],
+		"temperature_2m": [24.0, 26.0, 30.0],
+		"relative_humidity_2m": [80, 70, 60],
+		"precipitation_probability": [10, 60, null],
+		"weather_code": [3, 61, 1],
+		"wind_speed_10m": [9.0, 12.0, 5.0],
+		"wind_direction_10m": [90, 180, 270],
+		"visibility": [24140.0, 20000.0, null]
+	},
+	"daily": {
+		"time": [1759683600],
+		"weather_code": [61],
+		"temperature_2m_max": [33.2],
+		"temperature_2m_min": [24.0],
+		"precipitation_sum": [4.2],
+		"snowfall_sum": [0.0],
+		"precipitation_probability_max": [60]
+	}
+}`
+
+func newOpenMeteoServer(t *testing.T, geocode string, forecastStatus int) (*httptest.Server, *[]string) {
+	t.Helper()
+
+	var paths []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/v1/search":
+			_, _ = w.Write([]byte(geocode))
+		case "/v1/forecast":
+			w.WriteHeader(forecastStatus)
+			if forecastStatus == http.StatusBadRequest {
+				_, _ = w.Write([]byte(`{"error": true, "reason": "Latitude must be in range of -90 to 90°."}`))
+				return
+			}
+			if r.URL.Query().Get("hourly") != "" {
+				_, _ = w.Write([]byte(openMeteoPeriodsBody))
+				return
+			}
+			_, _ = w.Write([]byte(openMeteoForecastBody))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &paths
+}
+
+func newTestOpenMeteo(srv *httptest.Server) IWeatherProvider {
+	cfg := &config.Config{OpenMeteoURL: srv.URL, OpenMeteoGeocodingURL: srv.URL}
+	return NewOpenMeteoProvider(log, cfg, httpclient.NewClient(log, httpclient.Options{Name: ProviderOpenMeteo}))
+}
+
+func TestOpenMeteo_GeocodesAndNormalizes(t *testing.T) {
+	srv, paths := newOpenMeteoServer(t, `{"results":[{"name":"Jakarta","latitude":-6.21,"longitude":106.85,"country":"Indonesia","admin1":"Jakarta"}]}`, http.StatusOK)
+
+	loc := "Jakarta"
+	data, err := newTestOpenMeteo(srv).GetWeatherCondition(context.Background(), &loc)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"/v1/search", "/v1/forecast"}, *paths)
+	assert.Equal(t, "Jakarta", *data.Location.Name)
+	assert.Equal(t, "Indonesia", *data.Location.Country)
+	assert.Equal(t, "Asia/Jakarta", *data.Location.TzId)
+
+	current := data.Current
+	assert.Equal(t, 1759730400, *current.LastUpdatedEpoch)
+	assert.Equal(t, "2025-10-06 13:00", *current.LastUpdated)
+	assert.Equal(t, 31.4, *current.TempC)
+	assert.Equal(t, 88.5, *current.TempF)
+	assert.Equal(t, "Partly cloudy", *current.Condition.Text)
+	assert.Equal(t, 2, *current.Condition.Code)
+	assert.Equal(t, 9.2, *current.WindMph)
+	assert.Equal(t, "NNW", *current.WindDir)
+	assert.Equal(t, 29.82, *current.PressureIn)
+	assert.Equal(t, 24.1, *current.VisKm)
+	assert.Equal(t, 62, *current.Humidity)
+}
+
+func TestOpenMeteo_LatLonSkipsGeocoding(t *testing.T) {
+	srv, paths := newOpenMeteoServer(t, `{}`, http.StatusOK)
+
+	loc := "-6.125, 106.656"
+	data, err := newTestOpenMeteo(srv).GetWeatherCondition(context.Background(), &loc)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"/v1/forecast"}, *paths)
+	assert.Nil(t, data.Location.Name)
+}
+
+func TestOpenMeteo_UnknownLocation(t *testing.T) {
+	srv, _ := newOpenMeteoServer(t, `{"generationtime_ms": 0.5}`, http.StatusOK)
+
+	loc := "Atlantis"
+	_, err := newTestOpenMeteo(srv).GetWeatherCondition(context.Background(), &loc)
+	assert.ErrorIs(t, err, util.ErrNotFound)
+}
+
+func TestOpenMeteo_Errors(t *testing.T) {
+	tests := map[int]error{
+		http.StatusBadRequest:          util.ErrBadRequest,
+		http.StatusInternalServerError: util.ErrBadGateway,
+	}
+
+	for status, expected := range tests {
+		srv, _ := newOpenMeteoServer(t, `{}`, status)
+
+		loc := "1,2"
+		_, err := newTestOpenMeteo(srv).GetWeatherCondition(context.Background(), &loc)
+		assert.ErrorIs(t, err, expected, "status %d", status)
+	}
+}
+
+func TestOpenMeteo_Forecast(t *testing.T) {
+	srv, paths := newOpenMeteoServer(t, `{}`, http.StatusOK)
+
+	loc := "-6.125,106.656"
+	data, err := newTestOpenMeteo(srv).GetWeatherForecast(context.Background(), &loc, 1)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"/v1/forecast"}, *paths)
+	assert.Equal(t, "forecast", *data.Object)
+	assert.Nil(t, data.Current)
+	require.Len(t, data.Forecast.ForecastDay, 1)
+
+	day := data.Forecast.ForecastDay[0]
+	assert.Equal(t, "2025-10-06", *day.Date)
+	assert.Equal(t, 33.2, *day.Day.MaxtempC)
+	assert.Equal(t, 91.8, *day.Day.MaxtempF)
+	assert.Equal(t, 25.0, *day.Day.AvgtempC)
+	assert.Equal(t, 75, *day.Day.Avghumidity)
+	assert.Equal(t, 22.1, *day.Day.AvgvisKm)
+	assert.Equal(t, 1, *day.Day.DailyWillItRain)
+	assert.Equal(t, 0, *day.Day.DailyWillItSnow)
+	assert.Equal(t, "Slight rain", *day.Day.Condition.Text)
+
+	require.Len(t, day.Hour, 2, "hours past the last forecast day are dropped")
+	hour := day.Hour[1]
+	assert.Equal(t, "2025-10-06 01:00", *hour.Time)
+	assert.Equal(t, 26.0, *hour.TempC)
+	assert.Equal(t, 60, *hour.ChanceOfRain)
+	assert.Equal(t, 1, *hour.WillItRain)
+	assert.Equal(t, "S", *hour.WindDir)
+	assert.Equal(t, 7.5, *hour.WindMph)
+	assert.Nil(t, hour.Uv)
+}
+
+func TestCompassPoint(t *testing.T) {
+	tests := map[int]string{0: "N", 11: "N", 12: "NNE", 90: "E", 202: "SSW", 349: "N", 360: "N", -90: "W"}
+	for degrees, expected := range tests {
+		assert.Equal(t, expected, compassPoint(degrees), "%d°", degrees)
+	}
+}
@@ -0,0 +1,183 @@
+package service_weather
+
+import (
+	"context"
+	"flight-api/config"
+	weather_dto "flight-api/internal/dto/weather"
+	"flight-api/pkg/httpclient"
+	"flight-api/pkg/logger"
+	"flight-api/util"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// WeatherAPIProvider reads current conditions and forecasts from
+// WeatherAPI.com's /v1/current.json and /v1/forecast.json, whose payloads are
+// already shaped like WeatherDto and ForecastDto.
+type WeatherAPIProvider struct {
+	logger *logger.Logger
+	cfg    *config.Config
+	client *httpclient.Client
+}
+
+// NewWeatherAPIClient builds the HTTP client used to call WeatherAPI, with the
+// timeout, retry and circuit breaker settings from cfg.
+func NewWeatherAPIClient(logger *logger.Logger, cfg *config.Config) *httpclient.Client {
+	return httpclient.NewClient(logger, httpclient.Options{
+		Name:                  ProviderWeatherAPI,
+		Timeout:               cfg.WeatherTimeout,
+		MaxRetries:            cfg.HTTPMaxRetries,
+		RetryBaseDelay:        cfg.HTTPRetryBase,
+		RetryMaxDelay:         cfg.HTTPRetryMax,
+		BreakerThreshold:      cfg.BreakerThreshold,
+		BreakerOpenTimeout:    cfg.BreakerOpenTimeout,
+		BreakerHalfOpenProbes: cfg.BreakerHalfOpenProbes,
+		Secrets:               []string{cfg.WeatherAPIKey},
+	})
+}
+
+func NewWeatherAPIProvider(logger *logger.Logger, cfg *config.Config, client *httpclient.Client) IWeatherProvider {
+	return &WeatherAPIProvider{
+		logger: logger,
+		cfg:    cfg,
+		client: client,
+	}
+}
+
+func (s *WeatherAPIProvider) Name() string {
+	return ProviderWeatherAPI
+}
+
+func (s *WeatherAPIProvider) Client() *httpclient.Client {
+	return s.client
+}
+
+func (s *WeatherAPIProvider) GetWeatherCondition(ctx context.Context, loc *string) (*weather_dto.WeatherDto, error) {
+	// Call WeatherAPIs
+	s.logger.Debug("[GetWeatherCondition] Fetching weather data from Weather APIs...")
+
+	if loc == nil {
+		s.logger.Errorf("[GetWeatherCondition] Failed to fetch weather data: %v", util.ErrBadRequest)
+		return nil, util.ErrBadRequest
+	}
+
+	s.logger.Debugf("[GetWeatherCondition] Location: %s", *loc)
+	data := weather_dto.WeatherDto{
+		Object:   util.Ptr("weather"),
+		Location: nil,
+		Current:  nil,
+	}
+
+	if err := s.fetch(ctx, "/v1/current.json", *loc, nil, &data); err != nil {
+		return nil, err
+	}
+
+	s.logger.Debug("[GetWeatherCondition] Finished fetching weather data.")
+	return &data, nil
+}
+
+func (s *WeatherAPIProvider) GetWeatherForecast(ctx context.Context, loc *string, days int) (*weather_dto.ForecastDto, error) {
+	s.logger.Debug("[GetWeatherForecast] Fetching forecast data from Weather APIs...")
+
+	if loc == nil {
+		s.logger.Errorf("[GetWeatherForecast] Failed to fetch forecast data: %v", util.ErrBadRequest)
+		return nil, util.ErrBadRequest
+	}
+
+	s.logger.Debugf("[GetWeatherForecast] Location: %s, days: %d", *loc, days)
+	params := url.Values{}
+	params.Set("days", strconv.Itoa(days))
+	params.Set("aqi", "no")
+	params.Set("alerts", "no")
+
+	data := weather_dto.ForecastDto{Object: util.Ptr("forecast")}
+	if err := s.fetch(ctx, "/v1/forecast.json", *loc, params, &data); err != nil {
+		return nil, err
+	}
+
+	s.logger.Debug("[GetWeatherForecast] Finished fetching forecast data.")
+	return &data, nil
+}
+
+// fetch calls a WeatherAPI endpoint for loc and decodes the response into
+// out. WeatherAPI error codes are mapped to AppErrors.
+func (s *WeatherAPIProvider) fetch(ctx context.Context, endpoint, loc string, params url.Values, out interface{}) error {
+	location := url.QueryEscape(strings.ToUpper(loc))
+	URL := s.cfg.WeatherURL + endpoint + "?key=" + s.cfg.WeatherAPIKey + "&q=" + location
+	if len(params) > 0 {
+		URL += "&" + params.Encode()
+	}
+
+	resp, err := s.client.Get(ctx, URL)
+	if err != nil {
+		s.logger.Errorf("[fetch] Failed to fetch weather data: %v", err)
+		return requestError(err)
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		s.logger.Errorf("[fetch] Failed to fetch weather data: %s", resp.Status)
+
+		errBody, _ := io.ReadAll(resp.Body)
+		var errData map[string]map[string]interface{}
+		err = util.ParseJSON(errBody, &errData)
+		if err != nil {
+			s.logger.Errorf("[fetch] Failed to unmarshal error response body: %v", err)
+			return util.ErrInternalServer
+		}
+
+		errorCode, ok := errData["error"]["code"].(float64)
+		if !ok {
+			s.logger.Errorf("[fetch] Failed to assert error code to float64: %v", errData["error"]["code"])
+			return util.ErrInternalServer
+		}
+
+		switch errorCode {
+		case 1006:
+			s.logger.Error("[fetch] Error code: ", errorCode)
+			return util.NewAppError(util.ErrNotFound, "No matching location found", nil)
+		case 1003:
+			s.logger.Error("[fetch] Error code: ", errorCode)
+			return util.NewAppError(util.ErrBadRequest, "Weather location query is missing", nil)
+		case 1002:
+			s.logger.Error("[fetch] Error code: ", errorCode)
+			return util.NewAppError(util.ErrUnauthorized, "Weather API key is invalid or not provided", nil)
+		default:
+			s.logger.Error("[fetch] Error code: ", errorCode)
+			return weatherAPIStatusError(resp.StatusCode)
+		}
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		s.logger.Errorf("[fetch] Failed to read response body: %v", err)
+		return util.ErrInternalServer
+	}
+
+	err = util.ParseJSON(body, out)
+	if err != nil {
+		s.logger.Debugf("[fetch] Failed to unmarshal response body: %v", err)
+		return util.ErrInternalServer
+	}
+
+	return nil
+}
+
+// weatherAPIStatusError maps a WeatherAPI failure without a known error code
+// from its HTTP status. Quota, disabled key and server-side failures are the
+// provider's problem, not the request's, so none of them is a bad request.
+func weatherAPIStatusError(status int) error {
+	switch {
+	case status == http.StatusTooManyRequests:
+		return util.NewAppError(util.ErrServiceUnavailable, "Weather API rate limit exceeded", nil)
+	case status == http.StatusUnauthorized || status == http.StatusForbidden:
+		return util.NewAppError(util.ErrServiceUnavailable, "Weather API key is disabled or over its quota", nil)
+	default:
+		return util.NewAppError(util.ErrBadGateway, fmt.Sprintf("Weather API responded with status %d", status), nil)
+	}
+}
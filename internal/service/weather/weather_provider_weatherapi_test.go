@@ -0,0 +1,810 @@
+package service_weather
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flight-api/config"
+	location_dto "flight-api/internal/dto/location"
+	weather_dto "flight-api/internal/dto/weather"
+	"flight-api/pkg/httpclient"
+	"flight-api/pkg/logger"
+	"flight-api/util"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var log = logger.NewLogger(logger.DEBUG_LEVEL)
+
+// Dummy Data Weather API
+var DummyData = map[string]map[string]map[string]interface{}{
+	"jakarta": {
+		"location": {
+			"name":            "Jakarta",
+			"region":          "Jakarta Raya",
+			"country":         "Indonesia",
+			"lat":             -6.2146,
+			"lon":             106.8451,
+			"tz_id":           "Asia/Jakarta",
+			"localtime_epoch": 1759705426,
+			"localtime":       "2025-10-06 06:03",
+		},
+		"current": {
+			"last_updated_epoch": 1759705200,
+			"last_updated":       "2025-10-06 06:00",
+			"temp_c":             25.2,
+			"temp_f":             77.4,
+			"is_day":             1,
+			"condition": map[string]interface{}{
+				"text": "Mist",
+				"icon": "//cdn.weatherapi.com/weather/64x64/day/143.png",
+				"code": 1030,
+			},
+			"wind_mph":    3.8,
+			"wind_kph":    6.1,
+			"wind_degree": 133,
+			"wind_dir":    "SE",
+			"pressure_mb": 1011.0,
+			"pressure_in": 29.85,
+			"precip_mm":   0.0,
+			"precip_in":   0.0,
+			"humidity":    83,
+			"cloud":       25,
+			"feelslike_c": 27.1,
+			"feelslike_f": 80.7,
+			"windchill_c": 27.0,
+			"windchill_f": 80.5,
+			"heatindex_c": 29.7,
+			"heatindex_f": 85.4,
+			"dewpoint_c":  22.2,
+			"dewpoint_f":  72.0,
+			"vis_km":      4.0,
+			"vis_miles":   2.0,
+			"uv":          0.0,
+			"gust_mph":    5.2,
+			"gust_kph":    8.3,
+			"short_rad":   1.34,
+			"diff_rad":    0.67,
+			"dni":         0.0,
+			"gti":         0.67,
+		},
+	},
+	"tokyo": {
+		"location": {
+			"name":            "Tokyo",
+			"region":          "Tokyo",
+			"country":         "Japan",
+			"lat":             35.6895,
+			"lon":             139.6917,
+			"tz_id":           "Asia/Tokyo",
+			"localtime_epoch": 1759705568,
+			"localtime":       "2025-10-06 08:06",
+		},
+		"current": {
+			"last_updated_epoch": 1759705200,
+			"last_updated":       "2025-10-06 08:00",
+			"temp_c":             24.3,
+			"temp_f":             75.7,
+			"is_day":             1,
+			"condition": map[string]interface{}{
+				"text": "Partly cloudy",
+				"icon": "//cdn.weatherapi.com/weather/64x64/day/116.png",
+				"code": 1003,
+			},
+			"wind_mph":    8.3,
+			"wind_kph":    13.3,
+			"wind_degree": 353,
+			"wind_dir":    "N",
+			"pressure_mb": 1010.0,
+			"pressure_in": 29.83,
+			"precip_mm":   0.0,
+			"precip_in":   0.0,
+			"humidity":    89,
+			"cloud":       75,
+			"feelslike_c": 26.0,
+			"feelslike_f": 78.8,
+			"windchill_c": 25.6,
+			"windchill_f": 78.0,
+			"heatindex_c": 27.4,
+			"heatindex_f": 81.3,
+			"dewpoint_c":  20.3,
+			"dewpoint_f":  68.5,
+			"vis_km":      10.0,
+			"vis_miles":   6.0,
+			"uv":          1.3,
+			"gust_mph":    10.6,
+			"gust_kph":    17.1,
+			"short_rad":   58.85,
+			"diff_rad":    30.72,
+			"dni":         0.0,
+			"gti":         28.94,
+		},
+	},
+}
+
+// Mock Weather API
+func newWeatherMockServer(log *logger.Logger, routes map[string]map[string]map[string]interface{}, mode string, delay time.Duration) *httptest.Server {
+	log.Info("Mock Weather API server started")
+
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		log.Debug("Mock Weather API: Received request: ", r.URL.String())
+
+		// Handle error simulation
+		switch mode {
+		case "timeout":
+			time.Sleep(delay * time.Second)
+			w.Header().Set("Content-Type", "application/json")
+			return
+		case "success-invalid-json":
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(`{invalid json`))
+			return
+		case "invalid-json":
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{invalid json`))
+			return
+		case "invalid-error-code-type":
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(
+				map[string]map[string]interface{}{
+					"error": {
+						"code":    "not-a-number",
+						"message": "An error occurred.",
+					},
+				},
+			)
+			return
+		case "unknown-error-code":
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(
+				map[string]map[string]interface{}{
+					"error": {
+						"code":    9999,
+						"message": "Unknown error occurred.",
+					},
+				},
+			)
+			return
+		}
+
+		// Handle success
+		key := r.URL.Query().Get("key")
+		q := r.URL.Query().Get("q")
+
+		if key == "" {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(
+				map[string]map[string]interface{}{
+					"error": {
+						"code":    1002,
+						"message": "API key is invalid or not provided.",
+					},
+				},
+			)
+			return
+		}
+
+		if q == "" {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(
+				map[string]map[string]interface{}{
+					"error": {
+						"code":    1003,
+						"message": "Parameter q is missing.",
+					},
+				},
+			)
+			return
+		}
+
+		data, exists := routes[strings.ToLower(q)]
+		if !exists {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			_ = json.NewEncoder(w).Encode(
+				map[string]map[string]interface{}{
+					"error": {
+						"code":    1006,
+						"message": "No matching location found.",
+					},
+				},
+			)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(data)
+		log.Infof("Mock Weather API: Served data for location %s", q)
+	})
+
+	return httptest.NewServer(h)
+}
+
+// Mock Tripper
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+type errReadCloser struct{ err error }
+
+func (e errReadCloser) Read(p []byte) (int, error) { return 0, e.err }
+func (e errReadCloser) Close() error               { return nil }
+
+// -------------------- Unit Test -------------------
+
+func TestNewWeatherAPIProvider(t *testing.T) {
+	cfg := &config.Config{
+		WeatherURL:     "http://example.com",
+		WeatherAPIKey:  "test_api_key",
+		WeatherTimeout: 7 * time.Second,
+	}
+	svc := NewWeatherAPIProvider(log, cfg, NewWeatherAPIClient(log, cfg))
+	assert.NotNil(t, svc, "WeatherAPIProvider should not be nil")
+	assert.Equal(t, ProviderWeatherAPI, svc.Name())
+	assert.Equal(t, log, svc.(*WeatherAPIProvider).logger, "Logger should match")
+	assert.Equal(t, cfg, svc.(*WeatherAPIProvider).cfg, "Config should match")
+	assert.NotNil(t, svc.(*WeatherAPIProvider).client, "HTTP client should not be nil")
+	assert.Equal(t, ProviderWeatherAPI, svc.(*WeatherAPIProvider).client.Name())
+	assert.Equal(t, 7*time.Second, svc.(*WeatherAPIProvider).client.Timeout(), "HTTP client timeout should come from WEATHER_API_TIMEOUT")
+}
+
+func TestGetWeatherCondition_Success(t *testing.T) {
+	srv := newWeatherMockServer(log, DummyData, "", 2)
+	defer srv.Close()
+
+	svc := &WeatherAPIProvider{
+		cfg: &config.Config{
+			WeatherURL:    srv.URL,
+			WeatherAPIKey: "test_api_key",
+		},
+		logger: log,
+		client: httpclient.NewClient(log, httpclient.Options{Timeout: 60 * time.Second}),
+	}
+
+	tests := []struct {
+		name     string
+		input    string
+		expected *weather_dto.WeatherDto
+	}{
+		{
+			name:  "Valid Location - Jakarta",
+			input: "jakarta",
+			expected: &weather_dto.WeatherDto{
+				Object: util.Ptr("weather"),
+				Location: &location_dto.LocationDto{
+					Name:           util.Ptr("Jakarta"),
+					Country:        util.Ptr("Indonesia"),
+					Lat:            util.Ptr(-6.2146),
+					Lon:            util.Ptr(106.8451),
+					TzId:           util.Ptr("Asia/Jakarta"),
+					LocaltimeEpoch: util.Ptr(1759705426),
+					Localtime:      util.Ptr("2025-10-06 06:03"),
+				},
+				Current: &weather_dto.CurrentWeatherDto{
+					LastUpdatedEpoch: util.Ptr(1759705200),
+					LastUpdated:      util.Ptr("2025-10-06 06:00"),
+					TempC:            util.Ptr(25.2),
+					TempF:            util.Ptr(77.4),
+					IsDay:            util.Ptr(uint8(1)),
+					Condition: &weather_dto.ConditionDto{
+						Text: util.Ptr("Mist"),
+						Icon: util.Ptr("//cdn.weatherapi.com/weather/64x64/day/143.png"),
+						Code: util.Ptr(1030),
+					},
+					WindMph:    util.Ptr(3.8),
+					WindKph:    util.Ptr(6.1),
+					WindDegree: util.Ptr(133),
+					WindDir:    util.Ptr("SE"),
+					PressureMb: util.Ptr(1011.0),
+					PressureIn: util.Ptr(29.85),
+					PrecipMm:   util.Ptr(0.0),
+					PrecipIn:   util.Ptr(0.0),
+					Humidity:   util.Ptr(83),
+					Cloud:      util.Ptr(25),
+					FeelslikeC: util.Ptr(27.1),
+					FeelslikeF: util.Ptr(80.7),
+					WindchillC: util.Ptr(27.0),
+					WindchillF: util.Ptr(80.5),
+					HeatindexC: util.Ptr(29.7),
+					HeatindexF: util.Ptr(85.4),
+					DewpointC:  util.Ptr(22.2),
+					DewpointF:  util.Ptr(72.0),
+					VisKm:      util.Ptr(4.0),
+					VisMiles:   util.Ptr(2.0),
+					Uv:         util.Ptr(0.0),
+					GustMph:    util.Ptr(5.2),
+					GustKph:    util.Ptr(8.3),
+					ShortRad:   util.Ptr(1.34),
+					DiffRad:    util.Ptr(0.67),
+					DNI:        util.Ptr(0.0),
+					GTI:        util.Ptr(0.67),
+				},
+			},
+		},
+		{
+			name:  "Valid Location - Tokyo",
+			input: "tokyo",
+			expected: &weather_dto.WeatherDto{
+				Object: util.Ptr("weather"),
+				Location: &location_dto.LocationDto{
+					Name:           util.Ptr("Tokyo"),
+					Country:        util.Ptr("Japan"),
+					Lat:            util.Ptr(35.6895),
+					Lon:            util.Ptr(139.6917),
+					TzId:           util.Ptr("Asia/Tokyo"),
+					LocaltimeEpoch: util.Ptr(1759705568),
+					Localtime:      util.Ptr("2025-10-06 08:06"),
+				},
+				Current: &weather_dto.CurrentWeatherDto{
+					LastUpdatedEpoch: util.Ptr(1759705200),
+					LastUpdated:      util.Ptr("2025-10-06 08:00"),
+					TempC:            util.Ptr(24.3),
+					TempF:            util.Ptr(75.7),
+					IsDay:            util.Ptr(uint8(1)),
+					Condition: &weather_dto.ConditionDto{
+						Text: util.Ptr("Partly cloudy"),
+						Icon: util.Ptr("//cdn.weatherapi.com/weather/64x64/day/116.png"),
+						Code: util.Ptr(1003),
+					},
+					WindMph:    util.Ptr(8.3),
+					WindKph:    util.Ptr(13.3),
+					WindDegree: util.Ptr(353),
+					WindDir:    util.Ptr("N"),
+					PressureMb: util.Ptr(1010.0),
+					PressureIn: util.Ptr(29.83),
+					PrecipMm:   util.Ptr(0.0),
+					PrecipIn:   util.Ptr(0.0),
+					Humidity:   util.Ptr(89),
+					Cloud:      util.Ptr(75),
+					FeelslikeC: util.Ptr(26.0),
+					FeelslikeF: util.Ptr(78.8),
+					WindchillC: util.Ptr(25.6),
+					WindchillF: util.Ptr(78.0),
+					HeatindexC: util.Ptr(27.4),
+					HeatindexF: util.Ptr(81.3),
+					DewpointC:  util.Ptr(20.3),
+					DewpointF:  util.Ptr(68.5),
+					VisKm:      util.Ptr(10.0),
+					VisMiles:   util.Ptr(6.0),
+					Uv:         util.Ptr(1.3),
+					GustMph:    util.Ptr(10.6),
+					GustKph:    util.Ptr(17.1),
+					ShortRad:   util.Ptr(58.85),
+					DiffRad:    util.Ptr(30.72),
+					DNI:        util.Ptr(0.0),
+					GTI:        util.Ptr(28.94),
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := svc.GetWeatherCondition(context.Background(), &tt.input)
+			assert.NoError(t, err)
+
+			// Check object type
+			assert.Equal(t, "weather", *result.Object)
+
+			// Check location
+			assert.Equal(t, tt.expected.Location.Name, result.Location.Name, "Location field Name does not match")
+			assert.Equal(t, tt.expected.Location.Country, result.Location.Country, "Location field Country does not match")
+			assert.Equal(t, tt.expected.Location.Lat, result.Location.Lat, "Location field Lat does not match")
+			assert.Equal(t, tt.expected.Location.Lon, result.Location.Lon, "Location field Lon does not match")
+			assert.Equal(t, tt.expected.Location.TzId, result.Location.TzId, "Location field TzId does not match")
+			assert.Equal(t, tt.expected.Location.LocaltimeEpoch, result.Location.LocaltimeEpoch, "Location field LocaltimeEpoch does not match")
+			assert.Equal(t, tt.expected.Location.Localtime, result.Location.Localtime, "Location field Localtime does not match")
+
+			// Check current weather
+			assert.Equal(t, tt.expected.Current.LastUpdatedEpoch, result.Current.LastUpdatedEpoch, "Current field LastUpdatedEpoch does not match")
+			assert.Equal(t, tt.expected.Current.LastUpdated, result.Current.LastUpdated, "Current field LastUpdated does not match")
+			assert.Equal(t, tt.expected.Current.TempC, result.Current.TempC, "Current field TempC does not match")
+			assert.Equal(t, tt.expected.Current.TempF, result.Current.TempF, "Current field TempF does not match")
+			assert.Equal(t, tt.expected.Current.IsDay, result.Current.IsDay, "Current field IsDay does not match")
+
+			// Check condition
+			assert.Equal(t, tt.expected.Current.Condition.Text, result.Current.Condition.Text, "Condition field Text does not match")
+			assert.Equal(t, tt.expected.Current.Condition.Icon, result.Current.Condition.Icon, "Condition field Icon does not match")
+			assert.Equal(t, tt.expected.Current.Condition.Code, result.Current.Condition.Code, "Condition field Code does not match")
+		})
+	}
+}
+
+func TestGetWeatherCondition_Success_InvalidJSON(t *testing.T) {
+	srv := newWeatherMockServer(log, DummyData, "success-invalid-json", 2)
+	defer srv.Close()
+
+	svc := &WeatherAPIProvider{
+		cfg: &config.Config{
+			WeatherURL:    srv.URL,
+			WeatherAPIKey: "test_api_key",
+		},
+		logger: log,
+		client: httpclient.NewClient(log, httpclient.Options{Timeout: 60 * time.Second}),
+	}
+
+	loc := "xxx"
+	_, err := svc.GetWeatherCondition(context.Background(), &loc)
+	assert.ErrorIs(t, err, util.ErrInternalServer)
+}
+
+func TestGetWeatherCondition_Error(t *testing.T) {
+	srv := newWeatherMockServer(log, DummyData, "", 2)
+	defer srv.Close()
+
+	svc := &WeatherAPIProvider{
+		cfg: &config.Config{
+			WeatherURL:    srv.URL,
+			WeatherAPIKey: "test_api_key",
+		},
+		logger: log,
+		client: httpclient.NewClient(log, httpclient.Options{}),
+	}
+
+	tests := []struct {
+		name     string
+		input    string
+		expected error
+	}{
+		{
+			name:     "Invalid Location",
+			input:    "invalid_location",
+			expected: util.ErrNotFound,
+		},
+		{
+			name:     "Empty Location",
+			input:    "",
+			expected: util.ErrBadRequest,
+		},
+		{
+			name:     "Whitespace Location",
+			input:    "   ",
+			expected: util.ErrNotFound,
+		},
+		{
+			name:     "Special Characters",
+			input:    "@#$%^&*()!",
+			expected: util.ErrNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := svc.GetWeatherCondition(context.Background(), &tt.input)
+			assert.ErrorIs(t, err, tt.expected)
+		})
+	}
+
+}
+
+func TestGetWeatherCondition_Error_NilLocation(t *testing.T) {
+	srv := newWeatherMockServer(log, DummyData, "", 2)
+	defer srv.Close()
+
+	svc := &WeatherAPIProvider{
+		cfg: &config.Config{
+			WeatherURL:    srv.URL,
+			WeatherAPIKey: "test_api_key",
+		},
+		logger: log,
+		client: httpclient.NewClient(log, httpclient.Options{}),
+	}
+
+	_, err := svc.GetWeatherCondition(context.Background(), nil)
+	assert.ErrorIs(t, err, util.ErrBadRequest)
+}
+
+func TestGetWeatherCondition_Error_Unauthorized(t *testing.T) {
+	srv := newWeatherMockServer(log, DummyData, "", 2)
+	defer srv.Close()
+
+	svc := &WeatherAPIProvider{
+		cfg: &config.Config{
+			WeatherURL: srv.URL,
+			// Missing API Key
+		},
+		logger: log,
+		client: httpclient.NewClient(log, httpclient.Options{}),
+	}
+
+	loc := "jakarta"
+	_, err := svc.GetWeatherCondition(context.Background(), &loc)
+	assert.ErrorIs(t, err, util.ErrUnauthorized)
+}
+
+func TestGetWeatherCondition_Error_Request(t *testing.T) {
+	// Transport that returns error on any request
+	rt := roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		return nil, errors.New("weird client error")
+	})
+	client := httpclient.NewClient(log, httpclient.Options{Transport: rt})
+
+	svc := &WeatherAPIProvider{
+		cfg: &config.Config{
+			WeatherURL:    "http://invalid-url", // Invalid URL to trigger error
+			WeatherAPIKey: "test_api_key",
+		},
+		logger: log,
+		client: client,
+	}
+
+	loc := "jakarta"
+	_, err := svc.GetWeatherCondition(context.Background(), &loc)
+	assert.NotNil(t, err, "expected error")
+	assert.ErrorIs(t, err, util.ErrInternalServer, "expected ErrInternalServer")
+}
+
+func TestGetWeatherCondition_Error_CircuitOpen(t *testing.T) {
+	calls := 0
+	rt := roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		calls++
+		return nil, errors.New("connection refused")
+	})
+	client := httpclient.NewClient(log, httpclient.Options{Transport: rt, BreakerThreshold: 1, BreakerOpenTimeout: time.Hour})
+
+	svc := &WeatherAPIProvider{
+		cfg:    &config.Config{WeatherURL: "http://upstream", WeatherAPIKey: "test_api_key"},
+		logger: log,
+		client: client,
+	}
+
+	loc := "jakarta"
+	_, err := svc.GetWeatherCondition(context.Background(), &loc)
+	assert.ErrorIs(t, err, util.ErrInternalServer)
+
+	// The breaker is now open, so the next call fails fast
+	_, err = svc.GetWeatherCondition(context.Background(), &loc)
+	assert.ErrorIs(t, err, util.ErrServiceUnavailable)
+	assert.ErrorIs(t, err, httpclient.ErrCircuitOpen)
+	assert.Equal(t, 1, calls, "open breaker must not reach the upstream")
+}
+
+func TestGetWeatherCondition_Error_Timeout(t *testing.T) {
+	srv := newWeatherMockServer(log, DummyData, "timeout", 2)
+	defer srv.Close()
+
+	svc := &WeatherAPIProvider{
+		cfg: &config.Config{
+			WeatherURL: srv.URL,
+		},
+		logger: log,
+		client: httpclient.NewClient(log, httpclient.Options{Timeout: 5 * time.Millisecond}),
+	}
+
+	loc := "jakarta"
+	_, err := svc.GetWeatherCondition(context.Background(), &loc)
+	assert.ErrorIs(t, err, util.ErrGatewayTimeout)
+}
+
+func TestGetWeatherCondition_Error_InvalidJSON(t *testing.T) {
+	srv := newWeatherMockServer(log, DummyData, "invalid-json", 2)
+	defer srv.Close()
+
+	svc := &WeatherAPIProvider{
+		cfg: &config.Config{
+			WeatherURL:    srv.URL,
+			WeatherAPIKey: "test_api_key",
+		},
+		logger: log,
+		client: httpclient.NewClient(log, httpclient.Options{}),
+	}
+
+	loc := "jakarta"
+	_, err := svc.GetWeatherCondition(context.Background(), &loc)
+	assert.ErrorIs(t, err, util.ErrInternalServer)
+}
+
+func TestGetWeatherCondition_Error_InvalidErrorCodeType(t *testing.T) {
+	srv := newWeatherMockServer(log, DummyData, "invalid-error-code-type", 2)
+	defer srv.Close()
+
+	svc := &WeatherAPIProvider{
+		cfg: &config.Config{
+			WeatherURL:    srv.URL,
+			WeatherAPIKey: "test_api_key",
+		},
+		logger: log,
+		client: httpclient.NewClient(log, httpclient.Options{}),
+	}
+
+	loc := "jakarta"
+	_, err := svc.GetWeatherCondition(context.Background(), &loc)
+	assert.NotNil(t, err, "expected error")
+	assert.ErrorIs(t, err, util.ErrInternalServer, "expected ErrInternalServer")
+}
+
+func TestGetWeatherCondition_Error_UnknownErrorCode(t *testing.T) {
+	srv := newWeatherMockServer(log, DummyData, "unknown-error-code", 2)
+	defer srv.Close()
+
+	svc := &WeatherAPIProvider{
+		cfg: &config.Config{
+			WeatherURL:    srv.URL,
+			WeatherAPIKey: "test_api_key",
+		},
+		logger: log,
+		client: httpclient.NewClient(log, httpclient.Options{}),
+	}
+
+	loc := "jakarta"
+	_, err := svc.GetWeatherCondition(context.Background(), &loc)
+	assert.ErrorIs(t, err, util.ErrBadGateway)
+}
+
+func TestGetWeatherCondition_Error_ProviderSideStatus(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		code   int
+		want   error
+	}{
+		{"key disabled", http.StatusUnauthorized, 2006, util.ErrServiceUnavailable},
+		{"quota exceeded", http.StatusForbidden, 2007, util.ErrServiceUnavailable},
+		{"rate limited", http.StatusTooManyRequests, 9999, util.ErrServiceUnavailable},
+		{"internal error", http.StatusInternalServerError, 9999, util.ErrBadGateway},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := newWeatherAPIErrorServer(t, tt.status, tt.code)
+			svc := &WeatherAPIProvider{
+				cfg:    &config.Config{WeatherURL: srv.URL, WeatherAPIKey: "test_api_key"},
+				logger: log,
+				client: httpclient.NewClient(log, httpclient.Options{}),
+			}
+
+			loc := "jakarta"
+			_, err := svc.GetWeatherCondition(context.Background(), &loc)
+			assert.ErrorIs(t, err, tt.want)
+		})
+	}
+}
+
+func TestGetWeatherCondition_Error_QuotaFallsBackToOpenMeteo(t *testing.T) {
+	weatherAPI := NewWeatherAPIProvider(log, &config.Config{
+		WeatherURL:    newWeatherAPIErrorServer(t, http.StatusForbidden, 2007).URL,
+		WeatherAPIKey: "test_api_key",
+	}, httpclient.NewClient(log, httpclient.Options{Name: ProviderWeatherAPI}))
+	openMeteo, _ := newOpenMeteoServer(t, `{"results":[{"name":"Jakarta","latitude":-6.21,"longitude":106.85,"country":"Indonesia","admin1":"Jakarta"}]}`, http.StatusOK)
+
+	loc := "Jakarta"
+	data, err := newTestChain(weatherAPI, newTestOpenMeteo(openMeteo)).GetWeatherCondition(context.Background(), &loc)
+
+	require.NoError(t, err)
+	require.NotNil(t, data.Source)
+	assert.Equal(t, ProviderOpenMeteo, *data.Source)
+}
+
+func newWeatherAPIErrorServer(t *testing.T, status, code int) *httptest.Server {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_ = json.NewEncoder(w).Encode(map[string]map[string]interface{}{
+			"error": {"code": code, "message": "Upstream error."},
+		})
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestGetWeatherCondition_Error_Request_InvalidURL(t *testing.T) {
+	svc := &WeatherAPIProvider{
+		cfg: &config.Config{
+			WeatherURL:    "http://invalid-url", // Invalid URL to trigger error
+			WeatherAPIKey: "test_api_key",
+		},
+		logger: log,
+		client: httpclient.NewClient(log, httpclient.Options{}),
+	}
+
+	loc := "jakarta"
+	_, err := svc.GetWeatherCondition(context.Background(), &loc)
+	assert.ErrorIs(t, err, util.ErrInternalServer, "expected ErrInternalServer")
+}
+
+func TestGetWeatherCondition_Error_ReadBody(t *testing.T) {
+	// Transport that returns a response with a body that errors on Read
+	rt := roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       errReadCloser{err: io.ErrUnexpectedEOF},
+			Header:     make(http.Header),
+		}, nil
+	})
+
+	client := httpclient.NewClient(log, httpclient.Options{Transport: rt})
+
+	svc := &WeatherAPIProvider{
+		cfg: &config.Config{
+			WeatherURL:    "http://valid-url", // Valid URL but body will error
+			WeatherAPIKey: "test_api_key",
+		},
+		logger: log,
+		client: client,
+	}
+
+	loc := "jakarta"
+	_, err := svc.GetWeatherCondition(context.Background(), &loc)
+	assert.NotNil(t, err, "expected error")
+	assert.ErrorIs(t, err, util.ErrInternalServer, "expected ErrInternalServer")
+}
+
+func TestGetWeatherForecast_Success(t *testing.T) {
+	var query url.Values
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/forecast.json" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		query = r.URL.Query()
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"location": {"name": "Jakarta", "lat": -6.21, "lon": 106.85},
+			"current": {"temp_c": 27.0},
+			"forecast": {"forecastday": [{
+				"date": "2025-10-06", "date_epoch": 1759708800,
+				"day": {"maxtemp_c": 33.1, "mintemp_c": 24.9, "avghumidity": 71, "daily_chance_of_rain": 87,
+					"condition": {"text": "Patchy rain nearby", "code": 1063}},
+				"hour": [{"time_epoch": 1759683600, "time": "2025-10-06 00:00", "temp_c": 25.4, "chance_of_rain": 0, "vis_km": 10.0}]
+			}]}
+		}`))
+	}))
+	defer srv.Close()
+
+	cfg := &config.Config{WeatherURL: srv.URL, WeatherAPIKey: "test_api_key"}
+	svc := NewWeatherAPIProvider(log, cfg, httpclient.NewClient(log, httpclient.Options{}))
+
+	loc := "jakarta"
+	data, err := svc.GetWeatherForecast(context.Background(), &loc, 2)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "2", query.Get("days"))
+	assert.Equal(t, "JAKARTA", query.Get("q"))
+	assert.Equal(t, "forecast", *data.Object)
+	assert.Equal(t, 27.0, *data.Current.TempC)
+	if assert.Len(t, data.Forecast.ForecastDay, 1) {
+		day := data.Forecast.ForecastDay[0]
+		assert.Equal(t, "2025-10-06", *day.Date)
+		assert.Equal(t, 33.1, *day.Day.MaxtempC)
+		assert.Equal(t, 87, *day.Day.DailyChanceOfRain)
+		assert.Equal(t, "Patchy rain nearby", *day.Day.Condition.Text)
+		assert.Equal(t, 25.4, *day.Hour[0].TempC)
+	}
+}
+
+func TestGetWeatherForecast_Error_NotFound(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"error": {"code": 1006, "message": "No matching location found."}}`))
+	}))
+	defer srv.Close()
+
+	cfg := &config.Config{WeatherURL: srv.URL, WeatherAPIKey: "test_api_key"}
+	svc := NewWeatherAPIProvider(log, cfg, httpclient.NewClient(log, httpclient.Options{}))
+
+	loc := "atlantis"
+	_, err := svc.GetWeatherForecast(context.Background(), &loc, 3)
+	assert.ErrorIs(t, err, util.ErrNotFound)
+
+	_, err = svc.GetWeatherForecast(context.Background(), nil, 3)
+	assert.ErrorIs(t, err, util.ErrBadRequest)
+}
@@ -5,6 +5,14 @@ import (
 	weather_dto "flight-api/internal/dto/weather"
 )
 
+// Forecast lengths, in days, accepted by GetWeatherForecast.
+const (
+	MinForecastDays     = 1
+	MaxForecastDays     = 10
+	DefaultForecastDays = 3
+)
+
 type IWeatherService interface {
 	GetWeatherCondition(ctx context.Context, loc *string) (*weather_dto.WeatherDto, error)
+	GetWeatherForecast(ctx context.Context, loc *string, days int) (*weather_dto.ForecastDto, error)
 }
@@ -0,0 +1,195 @@
+package service_weather
+
+import (
+	"context"
+	"errors"
+	"flight-api/config"
+	"flight-api/internal/cache"
+	weather_dto "flight-api/internal/dto/weather"
+	"flight-api/pkg/logger"
+	"flight-api/util"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	defaultWeatherCacheTTL    = 15 * time.Minute
+	defaultWeatherCacheMinTTL = 30 * time.Second
+	defaultForecastCacheTTL   = time.Hour
+)
+
+// CachedWeatherService is a read-through cache in front of another
+// IWeatherService. Cache failures are logged and counted, never returned, so
+// an unavailable cache only costs an upstream call.
+type CachedWeatherService struct {
+	logger *logger.Logger
+	cfg    *config.Config
+	next   IWeatherService
+	cache  cache.ICache
+	now    func() time.Time
+
+	hits   atomic.Uint64
+	misses atomic.Uint64
+	errors atomic.Uint64
+}
+
+// NewCachedWeatherService wraps next with a read-through cache. When c is nil
+// the cache is disabled and next is returned unchanged.
+func NewCachedWeatherService(logger *logger.Logger, cfg *config.Config, next IWeatherService, c cache.ICache) IWeatherService {
+	if c == nil {
+		return next
+	}
+
+	return &CachedWeatherService{
+		logger: logger,
+		cfg:    cfg,
+		next:   next,
+		cache:  c,
+		now:    time.Now,
+	}
+}
+
+func (s *CachedWeatherService) GetWeatherCondition(ctx context.Context, loc *string) (*weather_dto.WeatherDto, error) {
+	if loc == nil || cache.NormalizeKey(*loc) == "" {
+		return s.next.GetWeatherCondition(ctx, loc)
+	}
+
+	cached, err := s.cache.FindWeatherByLocation(ctx, *loc)
+	switch {
+	case err == nil && cached != nil:
+		s.hits.Add(1)
+		s.logger.Debugf("[GetWeatherCondition] Cache hit for location %s", *loc)
+		return cached, nil
+	case errors.Is(err, cache.ErrCachedNotFound):
+		s.hits.Add(1)
+		s.logger.Debugf("[GetWeatherCondition] Negative cache hit for location %s", *loc)
+		return nil, util.NewAppError(util.ErrNotFound, "No matching location found", nil)
+	case err == nil || errors.Is(err, cache.ErrCacheMiss):
+		s.misses.Add(1)
+	default:
+		s.errors.Add(1)
+		s.logger.Warnf("[GetWeatherCondition] Weather cache unavailable, bypassing: %v", err)
+	}
+
+	return s.fetch(ctx, loc)
+}
+
+// RefreshWeatherCondition skips the cache lookup and replaces the cached
+// entry with a new upstream observation. It is used when the cached entry is
+// still within its TTL but no longer fresh enough to serve.
+func (s *CachedWeatherService) RefreshWeatherCondition(ctx context.Context, loc *string) (*weather_dto.WeatherDto, error) {
+	if loc == nil || cache.NormalizeKey(*loc) == "" {
+		return s.next.GetWeatherCondition(ctx, loc)
+	}
+
+	return s.fetch(ctx, loc)
+}
+
+// fetch calls the upstream and writes the answer, or its absence, through to
+// the cache.
+func (s *CachedWeatherService) fetch(ctx context.Context, loc *string) (*weather_dto.WeatherDto, error) {
+	data, err := s.next.GetWeatherCondition(ctx, loc)
+	if errors.Is(err, util.ErrNotFound) && s.cfg.CacheNegativeTTL > 0 {
+		if cacheErr := s.cache.CacheWeatherNotFound(ctx, *loc, s.cfg.CacheNegativeTTL); cacheErr != nil {
+			s.errors.Add(1)
+			s.logger.Warnf("[fetch] Failed to cache missing location: %v", cacheErr)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.cache.CacheWeather(ctx, *loc, data, s.ttlFor(data)); err != nil {
+		s.errors.Add(1)
+		s.logger.Warnf("[fetch] Failed to cache weather data: %v", err)
+	}
+
+	return data, nil
+}
+
+// GetWeatherForecast caches forecasts for ForecastCacheTTL. Forecast models
+// are re-run hourly at best, so forecasts can be kept much longer than
+// current conditions.
+func (s *CachedWeatherService) GetWeatherForecast(ctx context.Context, loc *string, days int) (*weather_dto.ForecastDto, error) {
+	if loc == nil || cache.NormalizeKey(*loc) == "" {
+		return s.next.GetWeatherForecast(ctx, loc, days)
+	}
+
+	cached, err := s.cache.FindForecast(ctx, *loc, days)
+	switch {
+	case err == nil && cached != nil:
+		s.hits.Add(1)
+		s.logger.Debugf("[GetWeatherForecast] Cache hit for location %s", *loc)
+		return cached, nil
+	case err == nil || errors.Is(err, cache.ErrCacheMiss):
+		s.misses.Add(1)
+	default:
+		s.errors.Add(1)
+		s.logger.Warnf("[GetWeatherForecast] Forecast cache unavailable, bypassing: %v", err)
+	}
+
+	data, err := s.next.GetWeatherForecast(ctx, loc, days)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.cache.CacheForecast(ctx, *loc, days, data, s.forecastTTL()); err != nil {
+		s.errors.Add(1)
+		s.logger.Warnf("[GetWeatherForecast] Failed to cache forecast data: %v", err)
+	}
+
+	return data, nil
+}
+
+// Name identifies the cache on the status endpoint.
+func (s *CachedWeatherService) Name() string {
+	return "weather"
+}
+
+// Stats returns the current hit, miss and error counters.
+func (s *CachedWeatherService) Stats() cache.Stats {
+	return cache.Stats{
+		Hits:   s.hits.Load(),
+		Misses: s.misses.Load(),
+		Errors: s.errors.Load(),
+	}
+}
+
+// ttlFor expires an entry when the upstream observation is expected to be
+// refreshed, i.e. WeatherCacheTTL after last_updated_epoch. The result is
+// clamped to [WeatherCacheMinTTL, WeatherCacheTTL].
+func (s *CachedWeatherService) ttlFor(data *weather_dto.WeatherDto) time.Duration {
+	maxTTL := s.cfg.WeatherCacheTTL
+	if maxTTL <= 0 {
+		maxTTL = defaultWeatherCacheTTL
+	}
+	minTTL := s.cfg.WeatherCacheMinTTL
+	if minTTL <= 0 {
+		minTTL = defaultWeatherCacheMinTTL
+	}
+	if minTTL > maxTTL {
+		minTTL = maxTTL
+	}
+
+	ttl := maxTTL
+	if data != nil && data.Current != nil && data.Current.LastUpdatedEpoch != nil {
+		lastUpdated := time.Unix(int64(*data.Current.LastUpdatedEpoch), 0)
+		ttl = lastUpdated.Add(maxTTL).Sub(s.now())
+	}
+
+	switch {
+	case ttl > maxTTL:
+		return maxTTL
+	case ttl < minTTL:
+		return minTTL
+	default:
+		return ttl
+	}
+}
+
+func (s *CachedWeatherService) forecastTTL() time.Duration {
+	if s.cfg.ForecastCacheTTL <= 0 {
+		return defaultForecastCacheTTL
+	}
+	return s.cfg.ForecastCacheTTL
+}
@@ -0,0 +1,177 @@
+package service_weather
+
+import (
+	"context"
+	"errors"
+	"flight-api/config"
+	"flight-api/internal/cache"
+	weather_dto "flight-api/internal/dto/weather"
+	"flight-api/util"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newCachedWeatherService(t *testing.T, now time.Time) (*CachedWeatherService, *WeatherServiceMock, *cache.CacheMock) {
+	t.Helper()
+
+	next := &WeatherServiceMock{}
+	c := &cache.CacheMock{}
+	cfg := &config.Config{
+		WeatherCacheTTL:    15 * time.Minute,
+		WeatherCacheMinTTL: 30 * time.Second,
+	}
+
+	svc := NewCachedWeatherService(log, cfg, next, c).(*CachedWeatherService)
+	svc.now = func() time.Time { return now }
+	return svc, next, c
+}
+
+func weatherUpdatedAt(ts time.Time) *weather_dto.WeatherDto {
+	return &weather_dto.WeatherDto{
+		Object:  util.Ptr("weather"),
+		Current: &weather_dto.CurrentWeatherDto{LastUpdatedEpoch: util.Ptr(int(ts.Unix()))},
+	}
+}
+
+func TestNewCachedWeatherService_NilCacheReturnsNext(t *testing.T) {
+	next := &WeatherServiceMock{}
+	svc := NewCachedWeatherService(log, &config.Config{}, next, nil)
+	assert.Same(t, next, svc)
+}
+
+func TestCachedWeatherService_Hit(t *testing.T) {
+	now := time.Unix(1759705200, 0)
+	svc, next, c := newCachedWeatherService(t, now)
+	want := weatherUpdatedAt(now)
+
+	c.Mock.On("FindWeatherByLocation", mock.Anything, "Jakarta").Return(want, nil).Once()
+
+	loc := "Jakarta"
+	got, err := svc.GetWeatherCondition(context.Background(), &loc)
+
+	assert.NoError(t, err)
+	assert.Same(t, want, got)
+	next.Mock.AssertNotCalled(t, "GetWeatherCondition", mock.Anything, mock.Anything)
+	assert.Equal(t, cache.Stats{Hits: 1}, svc.Stats())
+}
+
+func TestCachedWeatherService_MissStoresWithUpstreamTTL(t *testing.T) {
+	now := time.Unix(1759705200, 0)
+	svc, next, c := newCachedWeatherService(t, now)
+	want := weatherUpdatedAt(now.Add(-5 * time.Minute))
+
+	loc := "jakarta"
+	c.Mock.On("FindWeatherByLocation", mock.Anything, loc).Return(nil, cache.ErrCacheMiss).Once()
+	next.Mock.On("GetWeatherCondition", mock.Anything, &loc).Return(want, nil).Once()
+	c.Mock.On("CacheWeather", mock.Anything, loc, want, 10*time.Minute).Return(nil).Once()
+
+	got, err := svc.GetWeatherCondition(context.Background(), &loc)
+
+	assert.NoError(t, err)
+	assert.Same(t, want, got)
+	c.Mock.AssertExpectations(t)
+	assert.Equal(t, cache.Stats{Misses: 1}, svc.Stats())
+}
+
+func TestCachedWeatherService_CacheDownBypasses(t *testing.T) {
+	now := time.Unix(1759705200, 0)
+	svc, next, c := newCachedWeatherService(t, now)
+	want := weatherUpdatedAt(now)
+
+	loc := "tokyo"
+	c.Mock.On("FindWeatherByLocation", mock.Anything, loc).Return(nil, errors.New("dial tcp: connection refused")).Once()
+	next.Mock.On("GetWeatherCondition", mock.Anything, &loc).Return(want, nil).Once()
+	c.Mock.On("CacheWeather", mock.Anything, loc, want, mock.Anything).Return(errors.New("dial tcp: connection refused")).Once()
+
+	got, err := svc.GetWeatherCondition(context.Background(), &loc)
+
+	assert.NoError(t, err)
+	assert.Same(t, want, got)
+	assert.Equal(t, cache.Stats{Errors: 2}, svc.Stats())
+}
+
+func TestCachedWeatherService_UpstreamErrorNotCached(t *testing.T) {
+	svc, next, c := newCachedWeatherService(t, time.Now())
+
+	loc := "nowhere"
+	c.Mock.On("FindWeatherByLocation", mock.Anything, loc).Return(nil, cache.ErrCacheMiss).Once()
+	next.Mock.On("GetWeatherCondition", mock.Anything, &loc).Return(nil, util.ErrNotFound).Once()
+
+	_, err := svc.GetWeatherCondition(context.Background(), &loc)
+
+	assert.ErrorIs(t, err, util.ErrNotFound)
+	c.Mock.AssertNotCalled(t, "CacheWeather", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestCachedWeatherService_TTLFor(t *testing.T) {
+	now := time.Unix(1759705200, 0)
+	svc, _, _ := newCachedWeatherService(t, now)
+
+	tests := []struct {
+		name     string
+		data     *weather_dto.WeatherDto
+		expected time.Duration
+	}{
+		{name: "fresh observation", data: weatherUpdatedAt(now), expected: 15 * time.Minute},
+		{name: "partially aged observation", data: weatherUpdatedAt(now.Add(-12 * time.Minute)), expected: 3 * time.Minute},
+		{name: "overdue observation", data: weatherUpdatedAt(now.Add(-time.Hour)), expected: 30 * time.Second},
+		{name: "future timestamp", data: weatherUpdatedAt(now.Add(time.Hour)), expected: 15 * time.Minute},
+		{name: "missing timestamp", data: &weather_dto.WeatherDto{}, expected: 15 * time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, svc.ttlFor(tt.data))
+		})
+	}
+}
+
+func TestCachedWeatherService_NegativeCaching(t *testing.T) {
+	svc, next, c := newCachedWeatherService(t, time.Now())
+	svc.cfg.CacheNegativeTTL = time.Minute
+
+	loc := "atlantis"
+	c.Mock.On("FindWeatherByLocation", mock.Anything, loc).Return(nil, cache.ErrCacheMiss).Once()
+	next.Mock.On("GetWeatherCondition", mock.Anything, &loc).Return(nil, util.ErrNotFound).Once()
+	c.Mock.On("CacheWeatherNotFound", mock.Anything, loc, time.Minute).Return(nil).Once()
+
+	_, err := svc.GetWeatherCondition(context.Background(), &loc)
+	assert.ErrorIs(t, err, util.ErrNotFound)
+
+	c.Mock.On("FindWeatherByLocation", mock.Anything, loc).Return(nil, cache.ErrCachedNotFound).Once()
+
+	_, err = svc.GetWeatherCondition(context.Background(), &loc)
+	assert.ErrorIs(t, err, util.ErrNotFound)
+
+	next.Mock.AssertNumberOfCalls(t, "GetWeatherCondition", 1)
+	c.Mock.AssertExpectations(t)
+	assert.Equal(t, cache.Stats{Hits: 1, Misses: 1}, svc.Stats())
+}
+
+func TestCachedWeatherService_Forecast(t *testing.T) {
+	svc, next, c := newCachedWeatherService(t, time.Now())
+	svc.cfg.ForecastCacheTTL = 2 * time.Hour
+	want := &weather_dto.ForecastDto{Object: util.Ptr("forecast")}
+
+	loc := "jakarta"
+	c.Mock.On("FindForecast", mock.Anything, loc, 3).Return(nil, cache.ErrCacheMiss).Once()
+	next.Mock.On("GetWeatherForecast", mock.Anything, &loc, 3).Return(want, nil).Once()
+	c.Mock.On("CacheForecast", mock.Anything, loc, 3, want, 2*time.Hour).Return(nil).Once()
+
+	got, err := svc.GetWeatherForecast(context.Background(), &loc, 3)
+	assert.NoError(t, err)
+	assert.Same(t, want, got)
+
+	c.Mock.On("FindForecast", mock.Anything, loc, 3).Return(want, nil).Once()
+
+	got, err = svc.GetWeatherForecast(context.Background(), &loc, 3)
+	assert.NoError(t, err)
+	assert.Same(t, want, got)
+
+	next.Mock.AssertNumberOfCalls(t, "GetWeatherForecast", 1)
+	c.Mock.AssertExpectations(t)
+	assert.Equal(t, cache.Stats{Hits: 1, Misses: 1}, svc.Stats())
+}
@@ -0,0 +1,236 @@
+package service_weather
+
+import (
+	"context"
+	"flight-api/config"
+	"flight-api/internal/cache"
+	weather_dto "flight-api/internal/dto/weather"
+	"flight-api/pkg/logger"
+	"flight-api/util"
+	"net/http"
+	"strconv"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+const (
+	defaultWeatherFreshTTL     = time.Minute
+	defaultWeatherStaleGrace   = 5 * time.Minute
+	defaultWeatherStaleIfError = time.Hour
+
+	// weatherRefreshTimeout bounds a shared upstream call, which is detached
+	// from the request that happened to start it.
+	weatherRefreshTimeout = 30 * time.Second
+)
+
+// weatherRefresher is implemented by layers that can answer from a cache and
+// can also be asked to skip it.
+type weatherRefresher interface {
+	RefreshWeatherCondition(ctx context.Context, loc *string) (*weather_dto.WeatherDto, error)
+}
+
+type lastGoodWeather struct {
+	data      *weather_dto.WeatherDto
+	fetchedAt time.Time
+}
+
+// CoalescingWeatherService collapses concurrent identical lookups into a
+// single upstream call and remembers the last good observation per location,
+// aged from when it was fetched from the provider rather than from when it
+// reached this layer.
+//
+//   - younger than WeatherFreshTTL: served as is;
+//   - within the following WeatherStaleGrace: served stale while one
+//     background refresh runs;
+//   - when the upstream fails with a server-side error: the last good value is
+//     served stale for up to WeatherStaleIfError.
+type CoalescingWeatherService struct {
+	logger       *logger.Logger
+	next         IWeatherService
+	freshTTL     time.Duration
+	staleGrace   time.Duration
+	staleIfError time.Duration
+	now          func() time.Time
+
+	group     singleflight.Group
+	forecasts singleflight.Group
+	lastGood  cache.ICache
+}
+
+func NewCoalescingWeatherService(logger *logger.Logger, cfg *config.Config, next IWeatherService) IWeatherService {
+	s := &CoalescingWeatherService{
+		logger:       logger,
+		next:         next,
+		freshTTL:     cfg.WeatherFreshTTL,
+		staleGrace:   cfg.WeatherStaleGrace,
+		staleIfError: cfg.WeatherStaleIfError,
+		now:          time.Now,
+		lastGood:     cache.NewMemoryCache(logger, cfg),
+	}
+
+	if s.freshTTL <= 0 {
+		s.freshTTL = defaultWeatherFreshTTL
+	}
+	if s.staleGrace <= 0 {
+		s.staleGrace = defaultWeatherStaleGrace
+	}
+	if s.staleIfError <= 0 {
+		s.staleIfError = defaultWeatherStaleIfError
+	}
+
+	return s
+}
+
+func (s *CoalescingWeatherService) GetWeatherCondition(ctx context.Context, loc *string) (*weather_dto.WeatherDto, error) {
+	if loc == nil || cache.NormalizeKey(*loc) == "" {
+		return s.next.GetWeatherCondition(ctx, loc)
+	}
+
+	key := cache.NormalizeKey(*loc)
+	location := *loc
+
+	entry, found := s.load(ctx, key)
+	if found {
+		age := s.now().Sub(entry.fetchedAt)
+		switch {
+		case age < s.freshTTL:
+			return entry.data, nil
+		case age < s.freshTTL+s.staleGrace:
+			s.logger.Debugf("[GetWeatherCondition] Serving stale weather for %s while refreshing", location)
+			s.group.DoChan(key, func() (interface{}, error) {
+				return s.refresh(ctx, key, location)
+			})
+			return s.stale(entry), nil
+		}
+	}
+
+	ch := s.group.DoChan(key, func() (interface{}, error) {
+		return s.refresh(ctx, key, location)
+	})
+
+	select {
+	case res := <-ch:
+		if res.Err == nil {
+			return res.Val.(*weather_dto.WeatherDto), nil
+		}
+		if fallback, ok := s.fallback(key, res.Err); ok {
+			return fallback, nil
+		}
+		return nil, res.Err
+	case <-ctx.Done():
+		if fallback, ok := s.fallback(key, ctx.Err()); ok {
+			return fallback, nil
+		}
+		return nil, util.NewAppError(util.ErrGatewayTimeout, "Timed out waiting for weather data", ctx.Err())
+	}
+}
+
+// GetWeatherForecast only coalesces concurrent identical lookups; forecasts
+// are long-lived enough in the cache that serving them stale is not needed.
+func (s *CoalescingWeatherService) GetWeatherForecast(ctx context.Context, loc *string, days int) (*weather_dto.ForecastDto, error) {
+	if loc == nil || cache.NormalizeKey(*loc) == "" {
+		return s.next.GetWeatherForecast(ctx, loc, days)
+	}
+
+	key := strconv.Itoa(days) + ":" + cache.NormalizeKey(*loc)
+	location := *loc
+
+	ch := s.forecasts.DoChan(key, func() (interface{}, error) {
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), weatherRefreshTimeout)
+		defer cancel()
+
+		return s.next.GetWeatherForecast(ctx, &location, days)
+	})
+
+	select {
+	case res := <-ch:
+		if res.Err != nil {
+			return nil, res.Err
+		}
+		return res.Val.(*weather_dto.ForecastDto), nil
+	case <-ctx.Done():
+		return nil, util.NewAppError(util.ErrGatewayTimeout, "Timed out waiting for forecast data", ctx.Err())
+	}
+}
+
+// refresh performs the shared upstream call. It runs on a context detached
+// from the caller so that one cancelled request does not fail the others.
+// When the layer below answers from its cache with an observation that is no
+// longer fresh, the call is repeated past that cache so the refresh actually
+// reaches the provider.
+func (s *CoalescingWeatherService) refresh(ctx context.Context, key, location string) (*weather_dto.WeatherDto, error) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), weatherRefreshTimeout)
+	defer cancel()
+
+	data, err := s.next.GetWeatherCondition(ctx, &location)
+	if err != nil {
+		return nil, err
+	}
+
+	if refresher, ok := s.next.(weatherRefresher); ok && data.FetchedAt != nil && s.now().Sub(*data.FetchedAt) >= s.freshTTL {
+		refreshed, err := refresher.RefreshWeatherCondition(ctx, &location)
+		if err != nil {
+			s.logger.Warnf("[refresh] Failed to refresh cached weather for %s, serving it stale: %v", location, err)
+		} else {
+			data = refreshed
+		}
+	}
+
+	entry := s.store(ctx, key, data)
+	if s.now().Sub(entry.fetchedAt) >= s.freshTTL {
+		return s.stale(entry), nil
+	}
+	return entry.data, nil
+}
+
+// fallback returns the last good value when the upstream failed on its side
+// (5xx, timeout) and the value went stale no more than WeatherStaleIfError ago.
+func (s *CoalescingWeatherService) fallback(key string, err error) (*weather_dto.WeatherDto, bool) {
+	if util.ToAppError(err).Status() < http.StatusInternalServerError {
+		return nil, false
+	}
+
+	entry, found := s.load(context.Background(), key)
+	if !found || s.now().Sub(entry.fetchedAt) > s.freshTTL+s.staleIfError {
+		return nil, false
+	}
+
+	s.logger.Warnf("[GetWeatherCondition] Upstream failed, serving last good weather for %s: %v", key, err)
+	return s.stale(entry), true
+}
+
+func (s *CoalescingWeatherService) stale(entry lastGoodWeather) *weather_dto.WeatherDto {
+	data := *entry.data
+	data.Stale = true
+	data.AgeSeconds = util.Ptr(int64(s.now().Sub(entry.fetchedAt) / time.Second))
+	return &data
+}
+
+func (s *CoalescingWeatherService) load(ctx context.Context, key string) (lastGoodWeather, bool) {
+	data, err := s.lastGood.FindWeatherByLocation(ctx, key)
+	if err != nil || data.FetchedAt == nil {
+		return lastGoodWeather{}, false
+	}
+
+	return lastGoodWeather{data: data, fetchedAt: *data.FetchedAt}, true
+}
+
+// store keeps the observation under the time the provider answered, taken
+// from its FetchedAt, and only stamps it now when the layers below did not.
+func (s *CoalescingWeatherService) store(ctx context.Context, key string, data *weather_dto.WeatherDto) lastGoodWeather {
+	fetchedAt := s.now().UTC()
+	if data.FetchedAt != nil {
+		fetchedAt = *data.FetchedAt
+	}
+
+	stamped := *data
+	stamped.FetchedAt = &fetchedAt
+	stamped.Stale = false
+	stamped.AgeSeconds = nil
+
+	if err := s.lastGood.CacheWeather(ctx, key, &stamped, 0); err != nil {
+		s.logger.Warnf("[store] Failed to keep last good weather for %s: %v", key, err)
+	}
+	return lastGoodWeather{data: &stamped, fetchedAt: fetchedAt}
+}
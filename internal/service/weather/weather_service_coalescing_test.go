@@ -0,0 +1,272 @@
+package service_weather
+
+import (
+	"context"
+	"flight-api/config"
+	"flight-api/internal/cache"
+	weather_dto "flight-api/internal/dto/weather"
+	"flight-api/util"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func newCoalescingWeatherService(t *testing.T) (*CoalescingWeatherService, *WeatherServiceMock, *fakeClock) {
+	t.Helper()
+
+	next := &WeatherServiceMock{}
+	clock := &fakeClock{now: time.Unix(1759705200, 0)}
+	cfg := &config.Config{
+		WeatherFreshTTL:     time.Minute,
+		WeatherStaleGrace:   5 * time.Minute,
+		WeatherStaleIfError: time.Hour,
+	}
+
+	svc := NewCoalescingWeatherService(log, cfg, next).(*CoalescingWeatherService)
+	svc.now = clock.Now
+	return svc, next, clock
+}
+
+func weatherWithTemp(temp float64) *weather_dto.WeatherDto {
+	return &weather_dto.WeatherDto{
+		Object:  util.Ptr("weather"),
+		Current: &weather_dto.CurrentWeatherDto{TempC: util.Ptr(temp)},
+	}
+}
+
+func TestCoalescingWeatherService_CollapsesConcurrentLookups(t *testing.T) {
+	svc, next, _ := newCoalescingWeatherService(t)
+
+	release := make(chan struct{})
+	var calls atomic.Int32
+	next.Mock.On("GetWeatherCondition", mock.Anything, mock.Anything).
+		Run(func(mock.Arguments) {
+			calls.Add(1)
+			<-release
+		}).
+		Return(weatherWithTemp(21), nil)
+
+	var wg sync.WaitGroup
+	for _, loc := range []string{"NEW YORK", "new york", " New  York ", "new york", "NEW YORK"} {
+		wg.Add(1)
+		go func(loc string) {
+			defer wg.Done()
+			got, err := svc.GetWeatherCondition(context.Background(), &loc)
+			assert.NoError(t, err)
+			assert.Equal(t, 21.0, *got.Current.TempC)
+		}(loc)
+	}
+
+	// Give every caller a chance to join the in-flight call.
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestCoalescingWeatherService_FreshServedWithoutUpstream(t *testing.T) {
+	svc, next, clock := newCoalescingWeatherService(t)
+	loc := "jakarta"
+
+	next.Mock.On("GetWeatherCondition", mock.Anything, mock.Anything).Return(weatherWithTemp(25), nil).Once()
+
+	_, err := svc.GetWeatherCondition(context.Background(), &loc)
+	require.NoError(t, err)
+
+	clock.Advance(30 * time.Second)
+	got, err := svc.GetWeatherCondition(context.Background(), &loc)
+	require.NoError(t, err)
+	assert.False(t, got.Stale)
+	assert.Nil(t, got.AgeSeconds)
+
+	next.Mock.AssertNumberOfCalls(t, "GetWeatherCondition", 1)
+}
+
+func TestCoalescingWeatherService_StaleWhileRevalidate(t *testing.T) {
+	svc, next, clock := newCoalescingWeatherService(t)
+	loc := "jakarta"
+
+	next.Mock.On("GetWeatherCondition", mock.Anything, mock.Anything).Return(weatherWithTemp(25), nil).Once()
+	_, err := svc.GetWeatherCondition(context.Background(), &loc)
+	require.NoError(t, err)
+
+	clock.Advance(2 * time.Minute)
+
+	refreshed := make(chan struct{})
+	next.Mock.On("GetWeatherCondition", mock.Anything, mock.Anything).
+		Run(func(mock.Arguments) { close(refreshed) }).
+		Return(weatherWithTemp(27), nil).Once()
+
+	got, err := svc.GetWeatherCondition(context.Background(), &loc)
+	require.NoError(t, err)
+	assert.True(t, got.Stale)
+	assert.Equal(t, int64(120), *got.AgeSeconds)
+	assert.Equal(t, 25.0, *got.Current.TempC)
+
+	select {
+	case <-refreshed:
+	case <-time.After(time.Second):
+		t.Fatal("background refresh did not run")
+	}
+
+	require.Eventually(t, func() bool {
+		got, err := svc.GetWeatherCondition(context.Background(), &loc)
+		return err == nil && !got.Stale && *got.Current.TempC == 27.0
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestCoalescingWeatherService_FallbackOnUpstreamError(t *testing.T) {
+	svc, next, clock := newCoalescingWeatherService(t)
+	loc := "jakarta"
+
+	next.Mock.On("GetWeatherCondition", mock.Anything, mock.Anything).Return(weatherWithTemp(25), nil).Once()
+	_, err := svc.GetWeatherCondition(context.Background(), &loc)
+	require.NoError(t, err)
+
+	// Past the grace window the lookup is synchronous and the upstream is down.
+	clock.Advance(10 * time.Minute)
+	next.Mock.On("GetWeatherCondition", mock.Anything, mock.Anything).Return(nil, util.ErrGatewayTimeout).Once()
+
+	got, err := svc.GetWeatherCondition(context.Background(), &loc)
+	require.NoError(t, err)
+	assert.True(t, got.Stale)
+	assert.Equal(t, int64(600), *got.AgeSeconds)
+}
+
+func TestCoalescingWeatherService_NoFallbackOnClientError(t *testing.T) {
+	svc, next, clock := newCoalescingWeatherService(t)
+	loc := "jakarta"
+
+	next.Mock.On("GetWeatherCondition", mock.Anything, mock.Anything).Return(weatherWithTemp(25), nil).Once()
+	_, err := svc.GetWeatherCondition(context.Background(), &loc)
+	require.NoError(t, err)
+
+	clock.Advance(10 * time.Minute)
+	next.Mock.On("GetWeatherCondition", mock.Anything, mock.Anything).Return(nil, util.ErrUnauthorized).Once()
+
+	_, err = svc.GetWeatherCondition(context.Background(), &loc)
+	assert.ErrorIs(t, err, util.ErrUnauthorized)
+}
+
+func TestCoalescingWeatherService_NoFallbackWithoutLastGood(t *testing.T) {
+	svc, next, _ := newCoalescingWeatherService(t)
+	loc := "jakarta"
+
+	next.Mock.On("GetWeatherCondition", mock.Anything, mock.Anything).Return(nil, util.ErrBadGateway).Once()
+
+	_, err := svc.GetWeatherCondition(context.Background(), &loc)
+	assert.ErrorIs(t, err, util.ErrBadGateway)
+}
+
+func TestCoalescingWeatherService_AgesFromFetchedAt(t *testing.T) {
+	svc, next, clock := newCoalescingWeatherService(t)
+	loc := "jakarta"
+
+	// The layer below answered from its cache with an observation fetched
+	// three minutes ago.
+	cached := weatherWithTemp(25)
+	cached.FetchedAt = util.Ptr(clock.Now().Add(-3 * time.Minute))
+	next.Mock.On("GetWeatherCondition", mock.Anything, mock.Anything).Return(cached, nil).Once()
+
+	got, err := svc.GetWeatherCondition(context.Background(), &loc)
+	require.NoError(t, err)
+	assert.True(t, got.Stale)
+	assert.Equal(t, int64(180), *got.AgeSeconds)
+
+	entry, found := svc.load(context.Background(), "jakarta")
+	require.True(t, found)
+	assert.True(t, entry.fetchedAt.Equal(*cached.FetchedAt))
+}
+
+func TestCoalescingWeatherService_RefreshBypassesReadThroughCache(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(1759705200, 0)}
+	provider := &WeatherServiceMock{}
+	c := &cache.CacheMock{}
+	cfg := &config.Config{
+		WeatherCacheTTL:     15 * time.Minute,
+		WeatherCacheMinTTL:  30 * time.Second,
+		WeatherFreshTTL:     time.Minute,
+		WeatherStaleGrace:   5 * time.Minute,
+		WeatherStaleIfError: time.Hour,
+	}
+	cached := NewCachedWeatherService(log, cfg, provider, c).(*CachedWeatherService)
+	cached.now = clock.Now
+	svc := NewCoalescingWeatherService(log, cfg, cached).(*CoalescingWeatherService)
+	svc.now = clock.Now
+	loc := "jakarta"
+
+	// The shared cache still holds an observation fetched three minutes ago.
+	old := weatherWithTemp(25)
+	old.FetchedAt = util.Ptr(clock.Now().Add(-3 * time.Minute))
+	c.Mock.On("FindWeatherByLocation", mock.Anything, loc).Return(old, nil).Once()
+
+	fresh := weatherWithTemp(27)
+	fresh.FetchedAt = util.Ptr(clock.Now())
+	provider.Mock.On("GetWeatherCondition", mock.Anything, mock.Anything).Return(fresh, nil).Once()
+	c.Mock.On("CacheWeather", mock.Anything, loc, fresh, 15*time.Minute).Return(nil).Once()
+
+	got, err := svc.GetWeatherCondition(context.Background(), &loc)
+	require.NoError(t, err)
+	assert.False(t, got.Stale)
+	assert.Nil(t, got.AgeSeconds)
+	assert.Equal(t, 27.0, *got.Current.TempC)
+
+	provider.Mock.AssertExpectations(t)
+	c.Mock.AssertExpectations(t)
+}
+
+func TestCoalescingWeatherService_StampsMissingFetchedAt(t *testing.T) {
+	svc, next, clock := newCoalescingWeatherService(t)
+	loc := "jakarta"
+
+	next.Mock.On("GetWeatherCondition", mock.Anything, mock.Anything).Return(weatherWithTemp(25), nil).Once()
+
+	got, err := svc.GetWeatherCondition(context.Background(), &loc)
+	require.NoError(t, err)
+	assert.False(t, got.Stale)
+	require.NotNil(t, got.FetchedAt)
+	assert.True(t, got.FetchedAt.Equal(clock.Now()))
+}
+
+func TestCoalescingWeatherService_EvictsLeastRecentlyUsed(t *testing.T) {
+	next := &WeatherServiceMock{}
+	cfg := &config.Config{CacheMaxEntries: 2}
+	svc := NewCoalescingWeatherService(log, cfg, next).(*CoalescingWeatherService)
+
+	next.Mock.On("GetWeatherCondition", mock.Anything, mock.Anything).Return(weatherWithTemp(25), nil)
+
+	for _, loc := range []string{"jakarta", "bandung", "jakarta", "surabaya"} {
+		_, err := svc.GetWeatherCondition(context.Background(), &loc)
+		require.NoError(t, err)
+	}
+
+	_, found := svc.load(context.Background(), "bandung")
+	assert.False(t, found)
+	_, found = svc.load(context.Background(), "jakarta")
+	assert.True(t, found)
+	_, found = svc.load(context.Background(), "surabaya")
+	assert.True(t, found)
+}
@@ -3,113 +3,121 @@ package service_weather
 import (
 	"context"
 	"errors"
-	"flight-api/config"
 	weather_dto "flight-api/internal/dto/weather"
 	"flight-api/pkg/logger"
 	"flight-api/util"
-	"io"
-	"net"
+	"fmt"
 	"net/http"
-	"net/url"
-	"strings"
 	"time"
+
+	"github.com/sirupsen/logrus"
 )
 
+// WeatherService asks its providers in order and returns the first answer,
+// stamped with the provider name and fetch time. A provider that fails for
+// any reason other than a bad request is skipped in favour of the next one.
 type WeatherService struct {
-	logger *logger.Logger
-	cfg    *config.Config
-	client *http.Client
+	logger    *logger.Logger
+	providers []IWeatherProvider
+	now       func() time.Time
 }
 
-func NewWeatherService(logger *logger.Logger, cfg *config.Config) IWeatherService {
+func NewWeatherService(logger *logger.Logger, providers ...IWeatherProvider) IWeatherService {
 	return &WeatherService{
-		logger: logger,
-		cfg:    cfg,
-		client: &http.Client{
-			Timeout: 60 * time.Second,
-		},
+		logger:    logger,
+		providers: providers,
+		now:       time.Now,
 	}
 }
 
 func (s *WeatherService) GetWeatherCondition(ctx context.Context, loc *string) (*weather_dto.WeatherDto, error) {
-	// Call WeatherAPIs
-	s.logger.Debug("[GetWeatherCondition] Fetching weather data from Weather APIs...")
-
 	if loc == nil {
 		s.logger.Errorf("[GetWeatherCondition] Failed to fetch weather data: %v", util.ErrBadRequest)
 		return nil, util.ErrBadRequest
 	}
 
-	location := url.QueryEscape(strings.ToUpper(*loc))
-	currentWeatherUrl := s.cfg.WeatherURL + "/v1/current.json"
-	URL := currentWeatherUrl + "?key=" + s.cfg.WeatherAPIKey + "&q=" + location
-
-	s.logger.Debugf("[GetWeatherCondition] Location: %s", *loc)
-	resp, err := s.client.Get(URL)
+	data, source, err := askProviders(ctx, s, *loc, "[GetWeatherCondition]", func(p IWeatherProvider) (*weather_dto.WeatherDto, error) {
+		return p.GetWeatherCondition(ctx, loc)
+	})
 	if err != nil {
-		s.logger.Errorf("[GetWeatherCondition] Failed to fetch weather data: %v", err)
+		return nil, err
+	}
 
-		var ne net.Error
-		if errors.As(err, &ne) && ne.Timeout() {
-			return nil, util.ErrGatewayTimeout
-		}
+	fetchedAt := s.now().UTC()
+	data.Source = &source
+	data.FetchedAt = &fetchedAt
+	return data, nil
+}
+
+func (s *WeatherService) GetWeatherForecast(ctx context.Context, loc *string, days int) (*weather_dto.ForecastDto, error) {
+	if loc == nil {
+		s.logger.Errorf("[GetWeatherForecast] Failed to fetch forecast data: %v", util.ErrBadRequest)
+		return nil, util.ErrBadRequest
+	}
+	if days < MinForecastDays || days > MaxForecastDays {
+		return nil, util.NewAppError(util.ErrBadRequest, fmt.Sprintf("Forecast days must be between %d and %d", MinForecastDays, MaxForecastDays), nil)
+	}
 
-		return nil, util.ErrInternalServer
+	data, source, err := askProviders(ctx, s, *loc, "[GetWeatherForecast]", func(p IWeatherProvider) (*weather_dto.ForecastDto, error) {
+		return p.GetWeatherForecast(ctx, loc, days)
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	defer resp.Body.Close()
+	fetchedAt := s.now().UTC()
+	data.Source = &source
+	data.FetchedAt = &fetchedAt
+	return data, nil
+}
 
-	if resp.StatusCode != http.StatusOK {
-		s.logger.Errorf("[GetWeatherCondition] Failed to fetch weather data: %s", resp.Status)
+// askProviders calls fetch with each provider in turn and returns the first
+// answer together with the name of the provider that gave it.
+func askProviders[T any](ctx context.Context, s *WeatherService, loc, logPrefix string, fetch func(IWeatherProvider) (T, error)) (T, string, error) {
+	var zero T
+	if len(s.providers) == 0 {
+		return zero, "", util.NewAppError(util.ErrServiceUnavailable, "No weather provider is configured", nil)
+	}
 
-		errBody, _ := io.ReadAll(resp.Body)
-		var errData map[string]map[string]interface{}
-		err = util.ParseJSON(errBody, &errData)
-		if err != nil {
-			s.logger.Errorf("[GetWeatherCondition] Failed to unmarshal error response body: %v", err)
-			return nil, util.ErrInternalServer
+	var errs []error
+	for _, provider := range s.providers {
+		data, err := fetch(provider)
+		if err == nil {
+			return data, provider.Name(), nil
 		}
 
-		errorCode, ok := errData["error"]["code"].(float64)
-		if !ok {
-			s.logger.Errorf("[GetWeatherCondition] Failed to assert error code to float64: %v", errData["error"]["code"])
-			return nil, util.ErrInternalServer
+		errs = append(errs, err)
+		if !shouldFallback(ctx, err) {
+			return zero, "", err
 		}
 
-		switch errorCode {
-		case 1006:
-			s.logger.Error("[GetWeatherCondition] Error code: ", errorCode)
-			return nil, util.ErrNotFound
-		case 1003:
-			s.logger.Error("[GetWeatherCondition] Error code: ", errorCode)
-			return nil, util.ErrBadRequest
-		case 1002:
-			s.logger.Error("[GetWeatherCondition] Error code: ", errorCode)
-			return nil, util.ErrUnauthorized
-		default:
-			return nil, util.ErrBadRequest
-		}
+		s.logger.Warnw(logrus.Fields{
+			"provider": provider.Name(),
+			"location": loc,
+			"error":    err,
+		}, "%s Weather provider failed, trying the next one", logPrefix)
 	}
 
-	s.logger.Debug("[GetWeatherCondition] Successfully fetched weather data.")
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		s.logger.Errorf("[GetWeatherCondition] Failed to read response body: %v", err)
-		return nil, util.ErrInternalServer
-	}
+	return zero, "", chainError(errs)
+}
 
-	data := weather_dto.WeatherDto{
-		Object:   util.Ptr("weather"),
-		Location: nil,
-		Current:  nil,
+// shouldFallback reports whether another provider may succeed where this
+// one failed. Bad requests fail the same everywhere and a cancelled caller
+// is gone.
+func shouldFallback(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
 	}
+	return util.ToAppError(err).Status() != http.StatusBadRequest
+}
 
-	err = util.ParseJSON(body, &data)
-	if err != nil {
-		s.logger.Debugf("[GetWeatherCondition] Failed to unmarshal response body: %v", err)
-		return nil, util.ErrInternalServer
+// chainError picks the error to report once every provider failed: not found
+// only if all of them agreed, otherwise the first provider-side failure.
+func chainError(errs []error) error {
+	for _, err := range errs {
+		if !errors.Is(err, util.ErrNotFound) {
+			return err
+		}
 	}
-
-	s.logger.Debug("[GetWeatherCondition] Finished fetching weather data.")
-	return &data, nil
+	return errs[0]
 }
@@ -19,3 +19,12 @@ func (w *WeatherServiceMock) GetWeatherCondition(ctx context.Context, loc *strin
 	}
 	return out, args.Error(1)
 }
+
+func (w *WeatherServiceMock) GetWeatherForecast(ctx context.Context, loc *string, days int) (*weather_dto.ForecastDto, error) {
+	args := w.Mock.Called(ctx, loc, days)
+	var out *weather_dto.ForecastDto
+	if v, ok := args.Get(0).(*weather_dto.ForecastDto); ok {
+		out = v
+	}
+	return out, args.Error(1)
+}
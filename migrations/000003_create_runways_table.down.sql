@@ -0,0 +1,5 @@
+-- Drop trigger
+DROP TRIGGER IF EXISTS trg_runways_updated_at ON public.runways;
+
+-- Drop table
+DROP TABLE IF EXISTS public.runways;
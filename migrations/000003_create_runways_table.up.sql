@@ -0,0 +1,21 @@
+-- One row per runway end, e.g. "04L" and "22R" of the same strip.
+CREATE TABLE public.runways (
+    id                          UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    airport_id                  UUID NOT NULL REFERENCES public.airports(id) ON DELETE CASCADE,
+    ident                       VARCHAR(8) NOT NULL,                         -- "04L", "22", "H1"
+    magnetic_heading            INTEGER,                                     -- degrees magnetic
+    true_heading                INTEGER,                                     -- degrees true
+    length_ft                   INTEGER,
+    width_ft                    INTEGER,
+    surface                     VARCHAR(64),
+    created_at                  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at                  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE (airport_id, ident)
+);
+
+DROP TRIGGER IF EXISTS trg_runways_updated_at ON public.runways;
+
+CREATE TRIGGER trg_runways_updated_at
+BEFORE UPDATE ON public.runways
+FOR EACH ROW
+EXECUTE FUNCTION set_updated_at();
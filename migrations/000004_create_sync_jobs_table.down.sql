@@ -0,0 +1,5 @@
+-- Drop trigger
+DROP TRIGGER IF EXISTS trg_sync_jobs_updated_at ON public.sync_jobs;
+
+-- Drop table
+DROP TABLE IF EXISTS public.sync_jobs;
@@ -0,0 +1,25 @@
+-- Asynchronous airport sync jobs. Results hold the per-ICAO outcome as
+-- it is processed, so a restarted job only resumes the remaining codes.
+CREATE TABLE public.sync_jobs (
+    id                          UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    status                      VARCHAR(16) NOT NULL DEFAULT 'queued',       -- queued, running, completed, failed, canceled
+    icao_codes                  TEXT[] NOT NULL,
+    total                       INTEGER NOT NULL DEFAULT 0,
+    processed                   INTEGER NOT NULL DEFAULT 0,
+    results                     JSONB NOT NULL DEFAULT '[]',
+    error                       TEXT,
+    created_at                  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    started_at                  TIMESTAMPTZ,
+    finished_at                 TIMESTAMPTZ,
+    updated_at                  TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_sync_jobs_status ON public.sync_jobs (status, updated_at);
+CREATE INDEX idx_sync_jobs_created_at ON public.sync_jobs (created_at DESC);
+
+DROP TRIGGER IF EXISTS trg_sync_jobs_updated_at ON public.sync_jobs;
+
+CREATE TRIGGER trg_sync_jobs_updated_at
+BEFORE UPDATE ON public.sync_jobs
+FOR EACH ROW
+EXECUTE FUNCTION set_updated_at();
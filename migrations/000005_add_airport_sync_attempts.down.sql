@@ -0,0 +1,5 @@
+-- Drop index
+DROP INDEX IF EXISTS public.idx_airports_sync_status;
+
+-- Drop column
+ALTER TABLE public.airports DROP COLUMN IF EXISTS sync_attempts;
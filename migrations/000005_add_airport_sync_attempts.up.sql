@@ -0,0 +1,6 @@
+-- Attempts made by the sync worker; SYNC_ERROR rows are retried until it
+-- reaches SYNC_WORKER_MAX_ATTEMPTS.
+ALTER TABLE public.airports
+    ADD COLUMN IF NOT EXISTS sync_attempts INTEGER NOT NULL DEFAULT 0;
+
+CREATE INDEX IF NOT EXISTS idx_airports_sync_status ON public.airports (sync_status, updated_at);
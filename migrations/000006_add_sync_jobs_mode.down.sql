@@ -0,0 +1,4 @@
+-- Drop columns
+ALTER TABLE public.sync_jobs
+    DROP COLUMN IF EXISTS fields,
+    DROP COLUMN IF EXISTS mode;
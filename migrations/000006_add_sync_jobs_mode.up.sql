@@ -0,0 +1,5 @@
+-- Sync mode of the job ('insert' or 'refresh') and the fields a refresh
+-- may update; empty means every field.
+ALTER TABLE public.sync_jobs
+    ADD COLUMN IF NOT EXISTS mode VARCHAR(16) NOT NULL DEFAULT 'insert',
+    ADD COLUMN IF NOT EXISTS fields TEXT[] NOT NULL DEFAULT '{}';
@@ -0,0 +1,3 @@
+-- Drop column
+ALTER TABLE public.sync_jobs
+    DROP COLUMN IF EXISTS atomic;
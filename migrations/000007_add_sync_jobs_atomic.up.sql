@@ -0,0 +1,4 @@
+-- Whether the job stores all of its airports in one transaction, committed
+-- only when every airport syncs.
+ALTER TABLE public.sync_jobs
+    ADD COLUMN IF NOT EXISTS atomic BOOLEAN NOT NULL DEFAULT false;
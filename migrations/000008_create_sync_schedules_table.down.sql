@@ -0,0 +1,5 @@
+-- Drop trigger
+DROP TRIGGER IF EXISTS trg_sync_schedules_updated_at ON public.sync_schedules;
+
+-- Drop table
+DROP TABLE IF EXISTS public.sync_schedules;
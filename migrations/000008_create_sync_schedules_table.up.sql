@@ -0,0 +1,20 @@
+-- Last run of each configured sync schedule, shared by every replica so a
+-- schedule runs once per due time no matter how many instances are up.
+CREATE TABLE public.sync_schedules (
+    name                        VARCHAR(64) PRIMARY KEY,
+    expression                  TEXT NOT NULL,
+    last_run_at                 TIMESTAMPTZ,
+    next_run_at                 TIMESTAMPTZ,
+    last_outcome                VARCHAR(16),                                  -- queued, skipped, failed
+    last_message                TEXT,
+    last_job_id                 UUID REFERENCES public.sync_jobs (id) ON DELETE SET NULL,
+    created_at                  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at                  TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+DROP TRIGGER IF EXISTS trg_sync_schedules_updated_at ON public.sync_schedules;
+
+CREATE TRIGGER trg_sync_schedules_updated_at
+BEFORE UPDATE ON public.sync_schedules
+FOR EACH ROW
+EXECUTE FUNCTION set_updated_at();
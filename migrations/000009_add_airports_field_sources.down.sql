@@ -0,0 +1,3 @@
+-- Drop column
+ALTER TABLE public.airports
+    DROP COLUMN IF EXISTS field_sources;
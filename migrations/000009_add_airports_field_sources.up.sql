@@ -0,0 +1,4 @@
+-- Which import source wrote each field, keyed by column name, so a later
+-- import only overwrites the fields its source has priority for.
+ALTER TABLE public.airports
+    ADD COLUMN IF NOT EXISTS field_sources JSONB NOT NULL DEFAULT '{}'::jsonb;
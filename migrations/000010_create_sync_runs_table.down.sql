@@ -0,0 +1,3 @@
+-- Drop tables
+DROP TABLE IF EXISTS public.sync_run_items;
+DROP TABLE IF EXISTS public.sync_runs;
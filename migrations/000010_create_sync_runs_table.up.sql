@@ -0,0 +1,35 @@
+-- One row per airport sync run: what triggered it, the codes it was asked
+-- for and how many of them ended in each outcome.
+CREATE TABLE public.sync_runs (
+    id                          UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    trigger                     VARCHAR(16) NOT NULL,                        -- api, job, schedule
+    job_id                      UUID REFERENCES public.sync_jobs (id) ON DELETE SET NULL,
+    icao_codes                  TEXT[] NOT NULL,
+    mode                        VARCHAR(16) NOT NULL DEFAULT 'insert',
+    fields                      TEXT[] NOT NULL DEFAULT '{}',
+    dry_run                     BOOLEAN NOT NULL DEFAULT false,
+    atomic                      BOOLEAN NOT NULL DEFAULT false,
+    total                       INTEGER NOT NULL DEFAULT 0,
+    failed                      INTEGER NOT NULL DEFAULT 0,
+    counts                      JSONB NOT NULL DEFAULT '{}',                 -- outcome -> number of codes
+    started_at                  TIMESTAMPTZ NOT NULL,
+    finished_at                 TIMESTAMPTZ NOT NULL,
+    created_at                  TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_sync_runs_started_at ON public.sync_runs (started_at DESC);
+
+-- The outcome of each ICAO code in a run, in response order.
+CREATE TABLE public.sync_run_items (
+    id                          UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    run_id                      UUID NOT NULL REFERENCES public.sync_runs (id) ON DELETE CASCADE,
+    position                    INTEGER NOT NULL,
+    icao_code                   TEXT NOT NULL,
+    outcome                     VARCHAR(16) NOT NULL,                        -- inserted, updated, error, ...
+    status                      VARCHAR(32) NOT NULL,
+    message                     TEXT,
+    changes                     JSONB,
+    UNIQUE (run_id, position)
+);
+
+CREATE INDEX idx_sync_run_items_outcome ON public.sync_run_items (run_id, outcome);
@@ -0,0 +1,4 @@
+-- What started the job: a request to the API or a sync schedule. The runs
+-- of the job are recorded with it.
+ALTER TABLE public.sync_jobs
+    ADD COLUMN IF NOT EXISTS trigger VARCHAR(16) NOT NULL DEFAULT 'job';          -- job, schedule
@@ -0,0 +1,196 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned without contacting the upstream while its
+// circuit breaker is open.
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// State is the state of an upstream circuit breaker.
+type State string
+
+const (
+	StateClosed   State = "closed"
+	StateOpen     State = "open"
+	StateHalfOpen State = "half_open"
+)
+
+const defaultBreakerOpenTimeout = 30 * time.Second
+
+// Health is a snapshot of an upstream's breaker state and recent outcomes.
+type Health struct {
+	Name                string     `json:"name"`
+	State               State      `json:"state"`
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	LastError           *string    `json:"last_error"`
+	LastErrorAt         *time.Time `json:"last_error_at"`
+	LastSuccessAt       *time.Time `json:"last_success_at"`
+	OpenUntil           *time.Time `json:"open_until,omitempty"`
+}
+
+// outcome classifies a finished call for the breaker.
+type outcome int
+
+const (
+	outcomeSuccess outcome = iota
+	outcomeFailure
+	// outcomeIgnored is a call abandoned by the caller; it says nothing about
+	// the upstream.
+	outcomeIgnored
+)
+
+// breaker is a consecutive-failure circuit breaker. After threshold failed
+// calls in a row it opens and rejects calls for openTimeout, then lets up to
+// halfOpenProbes calls through; if they all succeed it closes again, and any
+// failure reopens it. A zero threshold disables tripping but health is still
+// tracked.
+type breaker struct {
+	mu             sync.Mutex
+	threshold      int
+	openTimeout    time.Duration
+	halfOpenProbes int
+	now            func() time.Time
+
+	state         State
+	failures      int
+	openedAt      time.Time
+	inFlight      int
+	probeSuccess  int
+	lastError     string
+	lastErrorAt   time.Time
+	lastSuccessAt time.Time
+}
+
+func newBreaker(threshold int, openTimeout time.Duration, halfOpenProbes int) *breaker {
+	if threshold < 0 {
+		threshold = 0
+	}
+	if openTimeout <= 0 {
+		openTimeout = defaultBreakerOpenTimeout
+	}
+	if halfOpenProbes <= 0 {
+		halfOpenProbes = 1
+	}
+
+	return &breaker{
+		threshold:      threshold,
+		openTimeout:    openTimeout,
+		halfOpenProbes: halfOpenProbes,
+		now:            time.Now,
+		state:          StateClosed,
+	}
+}
+
+// allow reports whether a call may proceed. Every allowed call must be
+// followed by exactly one done.
+func (b *breaker) allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case StateOpen:
+		if b.now().Sub(b.openedAt) < b.openTimeout {
+			return ErrCircuitOpen
+		}
+		b.state = StateHalfOpen
+		b.inFlight = 0
+		b.probeSuccess = 0
+		fallthrough
+	case StateHalfOpen:
+		if b.inFlight >= b.halfOpenProbes {
+			return ErrCircuitOpen
+		}
+	}
+
+	b.inFlight++
+	return nil
+}
+
+// done records the outcome of a call admitted by allow.
+func (b *breaker) done(result outcome, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.inFlight > 0 {
+		b.inFlight--
+	}
+
+	switch result {
+	case outcomeSuccess:
+		b.lastSuccessAt = b.now()
+		b.failures = 0
+		if b.state == StateHalfOpen {
+			b.probeSuccess++
+			if b.probeSuccess >= b.halfOpenProbes {
+				b.state = StateClosed
+			}
+		}
+	case outcomeFailure:
+		b.lastErrorAt = b.now()
+		if err != nil {
+			b.lastError = err.Error()
+		}
+		b.failures++
+		if b.state == StateHalfOpen || (b.threshold > 0 && b.failures >= b.threshold) {
+			b.trip()
+		}
+	}
+}
+
+// trip must be called with b.mu held.
+func (b *breaker) trip() {
+	b.state = StateOpen
+	b.openedAt = b.now()
+	b.inFlight = 0
+	b.probeSuccess = 0
+}
+
+func (b *breaker) snapshot(name string) Health {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	health := Health{
+		Name:                name,
+		State:               b.state,
+		ConsecutiveFailures: b.failures,
+	}
+	if b.state == StateOpen {
+		until := b.openedAt.Add(b.openTimeout)
+		health.OpenUntil = &until
+	}
+	if !b.lastErrorAt.IsZero() {
+		lastError, at := b.lastError, b.lastErrorAt
+		health.LastError = &lastError
+		health.LastErrorAt = &at
+	}
+	if !b.lastSuccessAt.IsZero() {
+		at := b.lastSuccessAt
+		health.LastSuccessAt = &at
+	}
+
+	return health
+}
+
+// classify decides whether a finished call counts against the upstream.
+// Server errors, 429s and transport failures do; client errors do not, since
+// the upstream answered. A call cut short by the caller's own context, whether
+// cancelled or past its deadline, is ignored; only the client's per-attempt
+// timeout counts as the upstream being slow.
+func classify(ctx context.Context, resp *http.Response, err error) outcome {
+	if err != nil {
+		if ctx.Err() != nil {
+			return outcomeIgnored
+		}
+		return outcomeFailure
+	}
+	if resp.StatusCode >= http.StatusInternalServerError || resp.StatusCode == http.StatusTooManyRequests {
+		return outcomeFailure
+	}
+	return outcomeSuccess
+}
@@ -0,0 +1,213 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeClock struct{ t time.Time }
+
+func (c *fakeClock) now() time.Time          { return c.t }
+func (c *fakeClock) advance(d time.Duration) { c.t = c.t.Add(d) }
+
+func newTestBreaker(threshold int, openTimeout time.Duration, probes int) (*breaker, *fakeClock) {
+	clock := &fakeClock{t: time.Date(2025, 10, 6, 6, 0, 0, 0, time.UTC)}
+	b := newBreaker(threshold, openTimeout, probes)
+	b.now = clock.now
+	return b, clock
+}
+
+func fail(t *testing.T, b *breaker) {
+	t.Helper()
+	require.NoError(t, b.allow())
+	b.done(outcomeFailure, errors.New("boom"))
+}
+
+func TestBreaker_OpensAfterThreshold(t *testing.T) {
+	b, _ := newTestBreaker(3, time.Minute, 1)
+
+	fail(t, b)
+	fail(t, b)
+	assert.Equal(t, StateClosed, b.snapshot("x").State)
+
+	fail(t, b)
+	health := b.snapshot("x")
+	assert.Equal(t, StateOpen, health.State)
+	assert.Equal(t, 3, health.ConsecutiveFailures)
+	require.NotNil(t, health.LastError)
+	assert.Equal(t, "boom", *health.LastError)
+	assert.NotNil(t, health.OpenUntil)
+	assert.ErrorIs(t, b.allow(), ErrCircuitOpen)
+}
+
+func TestBreaker_SuccessResetsFailures(t *testing.T) {
+	b, _ := newTestBreaker(2, time.Minute, 1)
+
+	fail(t, b)
+	require.NoError(t, b.allow())
+	b.done(outcomeSuccess, nil)
+	fail(t, b)
+
+	health := b.snapshot("x")
+	assert.Equal(t, StateClosed, health.State)
+	assert.Equal(t, 1, health.ConsecutiveFailures)
+	assert.NotNil(t, health.LastSuccessAt)
+}
+
+func TestBreaker_HalfOpenProbe(t *testing.T) {
+	b, clock := newTestBreaker(1, 30*time.Second, 1)
+	fail(t, b)
+
+	clock.advance(29 * time.Second)
+	assert.ErrorIs(t, b.allow(), ErrCircuitOpen)
+
+	// Only one probe is admitted while half-open.
+	clock.advance(time.Second)
+	require.NoError(t, b.allow())
+	assert.Equal(t, StateHalfOpen, b.snapshot("x").State)
+	assert.ErrorIs(t, b.allow(), ErrCircuitOpen)
+
+	b.done(outcomeSuccess, nil)
+	assert.Equal(t, StateClosed, b.snapshot("x").State)
+	assert.NoError(t, b.allow())
+}
+
+func TestBreaker_FailedProbeReopens(t *testing.T) {
+	b, clock := newTestBreaker(5, 30*time.Second, 1)
+	for range 5 {
+		fail(t, b)
+	}
+
+	clock.advance(30 * time.Second)
+	fail(t, b)
+
+	assert.Equal(t, StateOpen, b.snapshot("x").State)
+	assert.ErrorIs(t, b.allow(), ErrCircuitOpen)
+}
+
+func TestBreaker_IgnoredProbeFreesSlot(t *testing.T) {
+	b, clock := newTestBreaker(1, time.Second, 1)
+	fail(t, b)
+	clock.advance(time.Second)
+
+	require.NoError(t, b.allow())
+	b.done(outcomeIgnored, context.Canceled)
+
+	assert.Equal(t, StateHalfOpen, b.snapshot("x").State)
+	assert.NoError(t, b.allow())
+}
+
+func TestBreaker_ZeroThresholdNeverOpens(t *testing.T) {
+	b, _ := newTestBreaker(0, time.Second, 1)
+	for range 10 {
+		fail(t, b)
+	}
+
+	health := b.snapshot("x")
+	assert.Equal(t, StateClosed, health.State)
+	assert.Equal(t, 10, health.ConsecutiveFailures)
+}
+
+func TestClient_FailsFastWhenBreakerOpen(t *testing.T) {
+	var calls atomic.Int32
+	rt := roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		calls.Add(1)
+		return nil, errors.New("connection refused")
+	})
+	c, _ := newTestClient(Options{Name: "weather", Transport: rt, BreakerThreshold: 2, BreakerOpenTimeout: time.Hour})
+
+	for range 2 {
+		_, err := c.Get(context.Background(), "http://upstream/")
+		require.Error(t, err)
+		assert.NotErrorIs(t, err, ErrCircuitOpen)
+	}
+
+	_, err := c.Get(context.Background(), "http://upstream/")
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+	assert.Equal(t, int32(2), calls.Load())
+
+	health := c.Health()
+	assert.Equal(t, "weather", health.Name)
+	assert.Equal(t, StateOpen, health.State)
+	require.NotNil(t, health.LastError)
+	assert.Contains(t, *health.LastError, "connection refused")
+	assert.Equal(t, uint64(1), c.Stats().Rejected)
+}
+
+func TestClient_ServerErrorsCountAgainstBreaker(t *testing.T) {
+	srv, _ := statusServer(t, []int{http.StatusInternalServerError, http.StatusNotFound}, nil)
+	c, _ := newTestClient(Options{BreakerThreshold: 5})
+
+	resp, err := c.Get(context.Background(), srv.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	health := c.Health()
+	assert.Equal(t, 1, health.ConsecutiveFailures)
+	require.NotNil(t, health.LastError)
+	assert.Equal(t, "unexpected status 500 Internal Server Error", *health.LastError)
+
+	// A 404 means the upstream answered, so it resets the count.
+	resp, err = c.Get(context.Background(), srv.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	health = c.Health()
+	assert.Equal(t, 0, health.ConsecutiveFailures)
+	assert.NotNil(t, health.LastSuccessAt)
+}
+
+func TestClient_CallerCancellationIsIgnored(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	rt := roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		cancel()
+		return nil, r.Context().Err()
+	})
+	c, _ := newTestClient(Options{Transport: rt, BreakerThreshold: 1})
+
+	_, err := c.Get(ctx, "http://upstream/")
+	require.ErrorIs(t, err, context.Canceled)
+
+	health := c.Health()
+	assert.Equal(t, StateClosed, health.State)
+	assert.Nil(t, health.LastError)
+}
+
+func TestClient_CallerDeadlineIsIgnored(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	rt := roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		<-r.Context().Done()
+		return nil, r.Context().Err()
+	})
+	c, _ := newTestClient(Options{Transport: rt, BreakerThreshold: 1})
+
+	_, err := c.Get(ctx, "http://upstream/")
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	health := c.Health()
+	assert.Equal(t, StateClosed, health.State)
+	assert.Nil(t, health.LastError)
+}
+
+func TestClient_AttemptTimeoutCountsAgainstBreaker(t *testing.T) {
+	rt := roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		<-r.Context().Done()
+		return nil, r.Context().Err()
+	})
+	c, _ := newTestClient(Options{Transport: rt, Timeout: 20 * time.Millisecond, BreakerThreshold: 1})
+
+	_, err := c.Get(context.Background(), "http://upstream/")
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	health := c.Health()
+	assert.Equal(t, StateOpen, health.State)
+	require.NotNil(t, health.LastError)
+}
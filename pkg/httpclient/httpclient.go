@@ -0,0 +1,333 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"flight-api/pkg/logger"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	defaultTimeout        = 30 * time.Second
+	defaultRetryBaseDelay = 200 * time.Millisecond
+	defaultRetryMaxDelay  = 5 * time.Second
+)
+
+// Options configures a Client for a single upstream.
+type Options struct {
+	// Name identifies the upstream in logs and metrics, e.g. "weather".
+	Name string
+	// Timeout bounds each attempt, including reading the response body.
+	Timeout time.Duration
+	// MaxRetries is the number of extra attempts for idempotent requests.
+	// Zero disables retries.
+	MaxRetries     int
+	RetryBaseDelay time.Duration
+	RetryMaxDelay  time.Duration
+	// Secrets are literal values (API keys, tokens) scrubbed from logs and
+	// returned errors, in addition to well-known credential query params.
+	Secrets []string
+	// BreakerThreshold is the number of consecutive failed calls that opens
+	// the circuit breaker. Zero disables it.
+	BreakerThreshold int
+	// BreakerOpenTimeout is how long an open breaker rejects calls before
+	// letting probes through.
+	BreakerOpenTimeout time.Duration
+	// BreakerHalfOpenProbes is the number of successful probes that close a
+	// half-open breaker.
+	BreakerHalfOpenProbes int
+	// RateLimit is the number of attempts per second allowed to the
+	// upstream, shared by every caller of the client. Zero disables it.
+	RateLimit float64
+	// RateBurst is the number of attempts that may go out at once before
+	// RateLimit applies.
+	RateBurst int
+	// Transport overrides http.DefaultTransport, mostly for tests.
+	Transport http.RoundTripper
+}
+
+// Client is a context-aware HTTP client for one upstream. It retries
+// idempotent requests with jittered exponential backoff, honours
+// Retry-After, fails fast while the upstream's circuit breaker is open,
+// paces attempts to the upstream's rate limit, redacts secrets and records
+// per-upstream metrics.
+type Client struct {
+	logger         *logger.Logger
+	name           string
+	http           *http.Client
+	maxRetries     int
+	retryBaseDelay time.Duration
+	retryMaxDelay  time.Duration
+	secrets        []string
+	metrics        *metrics
+	breaker        *breaker
+	limiter        *limiter
+	sleep          func(ctx context.Context, d time.Duration) error
+}
+
+func NewClient(logger *logger.Logger, opts Options) *Client {
+	if opts.Timeout <= 0 {
+		opts.Timeout = defaultTimeout
+	}
+	if opts.RetryBaseDelay <= 0 {
+		opts.RetryBaseDelay = defaultRetryBaseDelay
+	}
+	if opts.RetryMaxDelay <= 0 {
+		opts.RetryMaxDelay = defaultRetryMaxDelay
+	}
+	if opts.MaxRetries < 0 {
+		opts.MaxRetries = 0
+	}
+
+	secrets := make([]string, 0, len(opts.Secrets))
+	for _, s := range opts.Secrets {
+		if s != "" {
+			secrets = append(secrets, s)
+		}
+	}
+
+	return &Client{
+		logger: logger,
+		name:   opts.Name,
+		http: &http.Client{
+			Timeout:   opts.Timeout,
+			Transport: opts.Transport,
+		},
+		maxRetries:     opts.MaxRetries,
+		retryBaseDelay: opts.RetryBaseDelay,
+		retryMaxDelay:  opts.RetryMaxDelay,
+		secrets:        secrets,
+		metrics:        newMetrics(opts.Name),
+		breaker:        newBreaker(opts.BreakerThreshold, opts.BreakerOpenTimeout, opts.BreakerHalfOpenProbes),
+		limiter:        newLimiter(opts.RateLimit, opts.RateBurst),
+		sleep:          sleepContext,
+	}
+}
+
+// Name returns the upstream name given in Options.
+func (c *Client) Name() string {
+	return c.name
+}
+
+// Timeout returns the per-attempt timeout.
+func (c *Client) Timeout() time.Duration {
+	return c.http.Timeout
+}
+
+// Get issues a GET request bound to ctx.
+func (c *Client) Get(ctx context.Context, rawURL string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, c.redactError(err)
+	}
+	return c.Do(req)
+}
+
+// Do sends req, retrying idempotent requests on transport errors and on
+// 429, 502, 503 and 504 responses. The request context bounds the whole
+// call, including the waits between attempts. Returned errors never contain
+// configured secrets. While the circuit breaker is open Do returns
+// ErrCircuitOpen without sending the request. With a rate limit every
+// attempt first waits for its turn, within the request context.
+func (c *Client) Do(req *http.Request) (resp *http.Response, err error) {
+	ctx := req.Context()
+	target := c.Redact(req.URL.String())
+
+	if err := c.breaker.allow(); err != nil {
+		c.metrics.reject()
+		c.logger.Warnw(logrus.Fields{
+			"upstream": c.name,
+			"method":   req.Method,
+			"url":      target,
+		}, "Upstream circuit breaker is open, failing fast")
+		return nil, err
+	}
+	defer func() {
+		c.breaker.done(classify(ctx, resp, err), callError(resp, err))
+	}()
+
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, c.redactError(err)
+			}
+			req.Body = body
+		}
+
+		if err := c.limiter.wait(ctx, c.sleep); err != nil {
+			return nil, c.redactError(err)
+		}
+
+		start := time.Now()
+		resp, err := c.http.Do(req)
+		latency := time.Since(start)
+		c.metrics.record(resp, err, latency, attempt > 0)
+
+		fields := logrus.Fields{
+			"upstream": c.name,
+			"method":   req.Method,
+			"url":      target,
+			"attempt":  attempt + 1,
+			"duration": latency,
+		}
+		if resp != nil {
+			fields["status"] = resp.StatusCode
+		}
+		if err != nil {
+			fields["error"] = c.Redact(err.Error())
+		}
+		c.logger.Debugw(fields, "Upstream request")
+
+		if attempt >= c.maxRetries || !c.shouldRetry(req, resp, err) {
+			return resp, c.redactError(err)
+		}
+
+		delay, ok := c.retryDelay(attempt, resp)
+		if !ok || !fitsDeadline(ctx, delay) {
+			return resp, c.redactError(err)
+		}
+
+		if resp != nil {
+			_, _ = io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		c.logger.Warnw(fields, "Retrying upstream request in %s", delay)
+		if err := c.sleep(ctx, delay); err != nil {
+			return nil, c.redactError(err)
+		}
+	}
+}
+
+// Stats returns a snapshot of the upstream metrics.
+func (c *Client) Stats() Stats {
+	return c.metrics.snapshot()
+}
+
+// Health returns the circuit breaker state and the last error and success
+// seen for the upstream.
+func (c *Client) Health() Health {
+	return c.breaker.snapshot(c.name)
+}
+
+func (c *Client) shouldRetry(req *http.Request, resp *http.Response, err error) bool {
+	if !isIdempotent(req.Method) || req.Context().Err() != nil {
+		return false
+	}
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return false
+	}
+	if err != nil {
+		return true
+	}
+
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+// retryDelay returns how long to wait before the next attempt. A Retry-After
+// header wins over the backoff; when it asks for more than RetryMaxDelay the
+// request is not retried and the response is handed back to the caller.
+func (c *Client) retryDelay(attempt int, resp *http.Response) (time.Duration, bool) {
+	if resp != nil {
+		if after, ok := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()); ok {
+			return after, after <= c.retryMaxDelay
+		}
+	}
+
+	backoff := c.retryBaseDelay << attempt
+	if backoff <= 0 || backoff > c.retryMaxDelay {
+		backoff = c.retryMaxDelay
+	}
+
+	// Equal jitter: wait between half and all of the backoff.
+	half := backoff / 2
+	return half + rand.N(half+1), true
+}
+
+func (c *Client) redactError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		urlErr.URL = c.Redact(urlErr.URL)
+	}
+	return &redactedError{msg: c.Redact(err.Error()), err: err}
+}
+
+// redactedError keeps the original chain for errors.Is/As while scrubbing
+// secrets from the message.
+type redactedError struct {
+	msg string
+	err error
+}
+
+func (e *redactedError) Error() string { return e.msg }
+func (e *redactedError) Unwrap() error { return e.err }
+
+// callError describes a failed call for Health; err is already redacted.
+func callError(resp *http.Response, err error) error {
+	if err != nil {
+		return err
+	}
+	if resp != nil {
+		return errors.New("unexpected status " + resp.Status)
+	}
+	return nil
+}
+
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
+
+func fitsDeadline(ctx context.Context, delay time.Duration) bool {
+	deadline, ok := ctx.Deadline()
+	return !ok || time.Until(deadline) > delay
+}
+
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		if d := at.Sub(now); d > 0 {
+			return d, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,203 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"flight-api/pkg/logger"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var log = logger.NewLogger(logger.DEBUG_LEVEL)
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+// newTestClient records the waits between attempts instead of sleeping.
+func newTestClient(opts Options) (*Client, *[]time.Duration) {
+	c := NewClient(log, opts)
+	var waits []time.Duration
+	c.sleep = func(ctx context.Context, d time.Duration) error {
+		waits = append(waits, d)
+		return ctx.Err()
+	}
+	return c, &waits
+}
+
+func statusServer(t *testing.T, statuses []int, header http.Header) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := int(calls.Add(1)) - 1
+		if n >= len(statuses) {
+			n = len(statuses) - 1
+		}
+		for k, v := range header {
+			w.Header()[k] = v
+		}
+		w.WriteHeader(statuses[n])
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &calls
+}
+
+func TestClient_RetriesTransientStatus(t *testing.T) {
+	srv, calls := statusServer(t, []int{http.StatusServiceUnavailable, http.StatusBadGateway, http.StatusOK}, nil)
+	c, waits := newTestClient(Options{Name: "test", MaxRetries: 3, RetryBaseDelay: 100 * time.Millisecond, RetryMaxDelay: time.Second})
+
+	resp, err := c.Get(context.Background(), srv.URL)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, int32(3), calls.Load())
+	require.Len(t, *waits, 2)
+	// Equal jitter keeps each wait between half and all of the backoff.
+	assert.GreaterOrEqual(t, (*waits)[0], 50*time.Millisecond)
+	assert.LessOrEqual(t, (*waits)[0], 100*time.Millisecond)
+	assert.GreaterOrEqual(t, (*waits)[1], 100*time.Millisecond)
+	assert.LessOrEqual(t, (*waits)[1], 200*time.Millisecond)
+
+	stats := c.Stats()
+	assert.Equal(t, "test", stats.Name)
+	assert.Equal(t, uint64(3), stats.Requests)
+	assert.Equal(t, uint64(2), stats.Retries)
+	assert.Equal(t, map[string]uint64{"503": 1, "502": 1, "200": 1}, stats.StatusCodes)
+}
+
+func TestClient_GivesUpAfterMaxRetries(t *testing.T) {
+	srv, calls := statusServer(t, []int{http.StatusServiceUnavailable}, nil)
+	c, _ := newTestClient(Options{MaxRetries: 2})
+
+	resp, err := c.Get(context.Background(), srv.URL)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	assert.Equal(t, int32(3), calls.Load())
+}
+
+func TestClient_DoesNotRetryClientErrors(t *testing.T) {
+	srv, calls := statusServer(t, []int{http.StatusBadRequest}, nil)
+	c, _ := newTestClient(Options{MaxRetries: 2})
+
+	resp, err := c.Get(context.Background(), srv.URL)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestClient_DoesNotRetryNonIdempotent(t *testing.T) {
+	srv, calls := statusServer(t, []int{http.StatusServiceUnavailable}, nil)
+	c, _ := newTestClient(Options{MaxRetries: 2})
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, srv.URL, strings.NewReader("{}"))
+	require.NoError(t, err)
+	resp, err := c.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestClient_HonoursRetryAfter(t *testing.T) {
+	srv, calls := statusServer(t, []int{http.StatusTooManyRequests, http.StatusOK}, http.Header{"Retry-After": {"2"}})
+	c, waits := newTestClient(Options{MaxRetries: 1, RetryMaxDelay: 5 * time.Second})
+
+	resp, err := c.Get(context.Background(), srv.URL)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, int32(2), calls.Load())
+	assert.Equal(t, []time.Duration{2 * time.Second}, *waits)
+}
+
+func TestClient_RetryAfterBeyondMaxDelayIsReturned(t *testing.T) {
+	srv, calls := statusServer(t, []int{http.StatusTooManyRequests}, http.Header{"Retry-After": {"120"}})
+	c, waits := newTestClient(Options{MaxRetries: 3, RetryMaxDelay: 5 * time.Second})
+
+	resp, err := c.Get(context.Background(), srv.URL)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+	assert.Equal(t, int32(1), calls.Load())
+	assert.Empty(t, *waits)
+}
+
+func TestClient_PropagatesContext(t *testing.T) {
+	started := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-r.Context().Done()
+	}))
+	defer srv.Close()
+
+	c, _ := newTestClient(Options{MaxRetries: 3})
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-started
+		cancel()
+	}()
+
+	_, err := c.Get(ctx, srv.URL)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestClient_RedactsSecretsFromErrors(t *testing.T) {
+	rt := roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		return nil, errors.New("connection reset")
+	})
+	c, _ := newTestClient(Options{Transport: rt, Secrets: []string{"s3cr3t-value"}})
+
+	_, err := c.Get(context.Background(), "http://upstream/v1/current.json?key=s3cr3t-value&q=JAKARTA")
+	require.Error(t, err)
+	assert.NotContains(t, err.Error(), "s3cr3t-value")
+	assert.Contains(t, err.Error(), "key=REDACTED")
+	assert.Contains(t, err.Error(), "q=JAKARTA")
+	assert.Equal(t, uint64(1), c.Stats().Errors)
+}
+
+func TestRedactURL(t *testing.T) {
+	tests := map[string]string{
+		"http://x/a?key=abc&q=NYC":                 "http://x/a?key=REDACTED&q=NYC",
+		"http://x/a?q=NYC&API_KEY=abc":             "http://x/a?q=NYC&API_KEY=REDACTED",
+		"http://x/a?q=NYC":                         "http://x/a?q=NYC",
+		"http://x/a":                               "http://x/a",
+		`Get "http://x/a?token=abc": EOF`:          `Get "http://x/a?token=REDACTED": EOF`,
+		"http://x/a?access_token=abc#frag":         "http://x/a?access_token=REDACTED#frag",
+		"http://x/a?keyboard=abc&password=hunter2": "http://x/a?keyboard=abc&password=REDACTED",
+	}
+
+	for input, expected := range tests {
+		assert.Equal(t, expected, RedactURL(input), input)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2025, 10, 6, 6, 0, 0, 0, time.UTC)
+
+	d, ok := parseRetryAfter("3", now)
+	assert.True(t, ok)
+	assert.Equal(t, 3*time.Second, d)
+
+	d, ok = parseRetryAfter(now.Add(10*time.Second).Format(http.TimeFormat), now)
+	assert.True(t, ok)
+	assert.Equal(t, 10*time.Second, d)
+
+	_, ok = parseRetryAfter("soon", now)
+	assert.False(t, ok)
+}
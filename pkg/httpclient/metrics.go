@@ -0,0 +1,90 @@
+package httpclient
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Stats is a snapshot of the requests made to one upstream.
+type Stats struct {
+	Name         string            `json:"name"`
+	Requests     uint64            `json:"requests"`
+	Retries      uint64            `json:"retries"`
+	Errors       uint64            `json:"errors"`
+	Rejected     uint64            `json:"rejected"`
+	StatusCodes  map[string]uint64 `json:"status_codes"`
+	LatencyAvgMs float64           `json:"latency_avg_ms"`
+	LatencyMaxMs float64           `json:"latency_max_ms"`
+}
+
+type metrics struct {
+	mu           sync.Mutex
+	name         string
+	requests     uint64
+	retries      uint64
+	errors       uint64
+	rejected     uint64
+	statusCodes  map[int]uint64
+	totalLatency time.Duration
+	maxLatency   time.Duration
+}
+
+func newMetrics(name string) *metrics {
+	return &metrics{
+		name:        name,
+		statusCodes: make(map[int]uint64),
+	}
+}
+
+func (m *metrics) record(resp *http.Response, err error, latency time.Duration, retry bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.requests++
+	if retry {
+		m.retries++
+	}
+	if err != nil {
+		m.errors++
+	} else if resp != nil {
+		m.statusCodes[resp.StatusCode]++
+	}
+
+	m.totalLatency += latency
+	if latency > m.maxLatency {
+		m.maxLatency = latency
+	}
+}
+
+// reject counts a call refused by the circuit breaker.
+func (m *metrics) reject() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.rejected++
+}
+
+func (m *metrics) snapshot() Stats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stats := Stats{
+		Name:         m.name,
+		Requests:     m.requests,
+		Retries:      m.retries,
+		Errors:       m.errors,
+		Rejected:     m.rejected,
+		StatusCodes:  make(map[string]uint64, len(m.statusCodes)),
+		LatencyMaxMs: float64(m.maxLatency) / float64(time.Millisecond),
+	}
+	for code, n := range m.statusCodes {
+		stats.StatusCodes[strconv.Itoa(code)] = n
+	}
+	if m.requests > 0 {
+		stats.LatencyAvgMs = float64(m.totalLatency) / float64(m.requests) / float64(time.Millisecond)
+	}
+
+	return stats
+}
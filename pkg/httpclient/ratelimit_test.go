@@ -0,0 +1,71 @@
+package httpclient
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestLimiter(rate float64, burst int) (*limiter, *fakeClock) {
+	clock := &fakeClock{t: time.Date(2025, 10, 6, 6, 0, 0, 0, time.UTC)}
+	l := newLimiter(rate, burst)
+	l.now = clock.now
+	return l, clock
+}
+
+func TestLimiter_BurstThenPaced(t *testing.T) {
+	l, clock := newTestLimiter(2, 2)
+
+	assert.Zero(t, l.reserve())
+	assert.Zero(t, l.reserve())
+	// Waiters queue up behind each other.
+	assert.Equal(t, 500*time.Millisecond, l.reserve())
+	assert.Equal(t, time.Second, l.reserve())
+
+	// Idle time refills the bucket, up to the burst.
+	clock.advance(time.Minute)
+	assert.Zero(t, l.reserve())
+	assert.Zero(t, l.reserve())
+	assert.Equal(t, 500*time.Millisecond, l.reserve())
+}
+
+func TestLimiter_AbandonedWaitReturnsToken(t *testing.T) {
+	l, _ := newTestLimiter(1, 1)
+	assert.Zero(t, l.reserve())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := l.wait(ctx, sleepContext)
+	require.ErrorIs(t, err, context.Canceled)
+
+	assert.Equal(t, time.Second, l.reserve())
+}
+
+func TestLimiter_ZeroRateDisables(t *testing.T) {
+	assert.Nil(t, newLimiter(0, 5))
+
+	var l *limiter
+	assert.NoError(t, l.wait(context.Background(), sleepContext))
+}
+
+func TestClient_WaitsForRateLimit(t *testing.T) {
+	srv, calls := statusServer(t, []int{http.StatusOK}, nil)
+	c, waits := newTestClient(Options{Name: "test", RateLimit: 4, RateBurst: 1})
+
+	for i := 0; i < 3; i++ {
+		resp, err := c.Get(context.Background(), srv.URL)
+		require.NoError(t, err)
+		resp.Body.Close()
+	}
+
+	assert.Equal(t, int32(3), calls.Load())
+	// The first call uses the burst. The recorded sleeps return at once, so
+	// the next calls queue a quarter second further out each.
+	require.Len(t, *waits, 2)
+	assert.InDelta(t, 250*time.Millisecond, (*waits)[0], float64(50*time.Millisecond))
+	assert.InDelta(t, 500*time.Millisecond, (*waits)[1], float64(50*time.Millisecond))
+}
@@ -0,0 +1,65 @@
+package httpclient
+
+import (
+	"net/url"
+	"strings"
+)
+
+const redacted = "REDACTED"
+
+// sensitiveParams are query parameters whose values are always scrubbed.
+var sensitiveParams = map[string]struct{}{
+	"key":          {},
+	"api_key":      {},
+	"apikey":       {},
+	"access_token": {},
+	"token":        {},
+	"password":     {},
+	"secret":       {},
+}
+
+// Redact scrubs configured secrets and credential query parameters from s,
+// which may be a URL or any message that embeds one.
+func (c *Client) Redact(s string) string {
+	for _, secret := range c.secrets {
+		s = strings.ReplaceAll(s, secret, redacted)
+		if escaped := url.QueryEscape(secret); escaped != secret {
+			s = strings.ReplaceAll(s, escaped, redacted)
+		}
+	}
+	return RedactURL(s)
+}
+
+// RedactURL replaces the values of credential query parameters in rawURL. It
+// also works on text that merely contains a URL.
+func RedactURL(rawURL string) string {
+	start := strings.IndexByte(rawURL, '?')
+	if start < 0 {
+		return rawURL
+	}
+
+	var b strings.Builder
+	b.WriteString(rawURL[:start+1])
+
+	rest := rawURL[start+1:]
+	end := strings.IndexAny(rest, " \"'#")
+	tail := ""
+	if end >= 0 {
+		rest, tail = rest[:end], rest[end:]
+	}
+
+	for i, pair := range strings.Split(rest, "&") {
+		if i > 0 {
+			b.WriteByte('&')
+		}
+		name, _, hasValue := strings.Cut(pair, "=")
+		if _, sensitive := sensitiveParams[strings.ToLower(name)]; sensitive && hasValue {
+			b.WriteString(name + "=" + redacted)
+			continue
+		}
+		b.WriteString(pair)
+	}
+
+	b.WriteString(tail)
+	return b.String()
+}
@@ -6,6 +6,7 @@ import (
 
 	"flight-api/pkg/logger"
 	mid "flight-api/pkg/middleware"
+	"flight-api/util"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
@@ -25,7 +26,7 @@ func NewRouter(handlers ...Handler) *chi.Mux {
 	r.Use(middleware.RequestID)
 	r.Use(middleware.RealIP)
 	r.Use(mid.HTTPLogger)
-	r.Use(middleware.Recoverer)
+	r.Use(mid.Recoverer)
 	r.Use(middleware.Timeout(60 * time.Second))
 
 	// CORS middleware
@@ -48,6 +49,14 @@ func NewRouter(handlers ...Handler) *chi.Mux {
 		}
 	})
 
+	// Unknown routes and methods are rendered as problems too
+	r.NotFound(func(w http.ResponseWriter, r *http.Request) {
+		util.ErrorHandler(w, r, util.NewAppError(util.ErrNotFound, "No route matches "+r.URL.Path, nil))
+	})
+	r.MethodNotAllowed(func(w http.ResponseWriter, r *http.Request) {
+		util.ErrorHandler(w, r, util.NewAppError(util.ErrMethodNotAllowed, "Method "+r.Method+" is not allowed on "+r.URL.Path, nil))
+	})
+
 	// Register all handlers
 	for _, h := range handlers {
 		h.RegisterRouter(r)
@@ -0,0 +1,93 @@
+package metar
+
+// FAA flight categories, from best to worst.
+const (
+	CategoryVFR  = "VFR"
+	CategoryMVFR = "MVFR"
+	CategoryIFR  = "IFR"
+	CategoryLIFR = "LIFR"
+)
+
+var categoryRank = map[string]int{
+	CategoryVFR:  0,
+	CategoryMVFR: 1,
+	CategoryIFR:  2,
+	CategoryLIFR: 3,
+}
+
+// IsFlightCategory reports whether category is one of the four FAA flight
+// categories.
+func IsFlightCategory(category string) bool {
+	_, ok := categoryRank[category]
+	return ok
+}
+
+// FlightCategory returns the FAA flight category for a ceiling in feet above
+// ground and a visibility in statute miles, whichever is worse. A nil
+// ceiling means there is none; a nil visibility is ignored. It returns ""
+// when visibility is unknown and there is no ceiling to judge by.
+//
+//	LIFR  ceiling below 500 ft or visibility below 1 mile
+//	IFR   ceiling 500 to below 1,000 ft or visibility 1 to below 3 miles
+//	MVFR  ceiling 1,000 to 3,000 ft or visibility 3 to 5 miles
+//	VFR   ceiling above 3,000 ft and visibility above 5 miles
+func FlightCategory(ceilingFt *int, visibilityMiles *float64) string {
+	if ceilingFt == nil && visibilityMiles == nil {
+		return ""
+	}
+
+	category := CategoryVFR
+	if ceilingFt != nil {
+		category = worse(category, ceilingCategory(*ceilingFt))
+	}
+	if visibilityMiles != nil {
+		category = worse(category, visibilityCategory(*visibilityMiles))
+	}
+	return category
+}
+
+// FlightCategory returns the report's flight category, or "" when it has
+// neither a visibility nor a ceiling. CAVOK counts as more than 6 miles.
+func (m *Metar) FlightCategory() string {
+	var visibility *float64
+	switch {
+	case m.CAVOK:
+		visibility = ptr(10.0)
+	case m.Visibility != nil:
+		visibility = ptr(m.Visibility.Miles())
+	}
+	return FlightCategory(m.CeilingFt(), visibility)
+}
+
+func ceilingCategory(ft int) string {
+	switch {
+	case ft < 500:
+		return CategoryLIFR
+	case ft < 1000:
+		return CategoryIFR
+	case ft <= 3000:
+		return CategoryMVFR
+	default:
+		return CategoryVFR
+	}
+}
+
+func visibilityCategory(miles float64) string {
+	switch {
+	case miles < 1:
+		return CategoryLIFR
+	case miles < 3:
+		return CategoryIFR
+	case miles <= 5:
+		return CategoryMVFR
+	default:
+		return CategoryVFR
+	}
+}
+
+func worse(a, b string) string {
+	if categoryRank[b] > categoryRank[a] {
+		return b
+	}
+	return a
+}
@@ -0,0 +1,65 @@
+package metar
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFlightCategory(t *testing.T) {
+	tests := []struct {
+		name       string
+		ceilingFt  *int
+		visibility *float64
+		want       string
+	}{
+		{"unknown", nil, nil, ""},
+		{"clear and unlimited", nil, ptr(10.0), CategoryVFR},
+		{"ceiling only", ptr(3500), nil, CategoryVFR},
+		{"ceiling at 3000 is marginal", ptr(3000), ptr(10.0), CategoryMVFR},
+		{"visibility at 5 is marginal", ptr(5000), ptr(5.0), CategoryMVFR},
+		{"ceiling at 1000 is marginal", ptr(1000), ptr(10.0), CategoryMVFR},
+		{"ceiling below 1000", ptr(900), ptr(10.0), CategoryIFR},
+		{"visibility below 3", nil, ptr(2.5), CategoryIFR},
+		{"ceiling at 500 is IFR", ptr(500), ptr(10.0), CategoryIFR},
+		{"ceiling below 500", ptr(400), ptr(10.0), CategoryLIFR},
+		{"visibility below 1", ptr(5000), ptr(0.75), CategoryLIFR},
+		{"worse of the two wins", ptr(2000), ptr(0.5), CategoryLIFR},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, FlightCategory(tt.ceilingFt, tt.visibility))
+		})
+	}
+}
+
+func TestMetar_FlightCategory(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want string
+	}{
+		{"KJFK 061751Z 20012KT 10SM FEW050 SCT250 24/14 A3002", CategoryVFR},
+		{"KJFK 061751Z 20012KT 10SM BKN025 24/14 A3002", CategoryMVFR},
+		{"KBOS 061754Z 05009KT 1 1/2SM -RA BR OVC008 13/12 A3010", CategoryIFR},
+		{"KBTV 061754Z 00000KT M1/4SM FG VV001 08/08 A3012", CategoryLIFR},
+		{"LFPG 061800Z 24012KT CAVOK 16/08 Q1015", CategoryVFR},
+		{"EGLL 061750Z 22015KT 6000 BR SCT008 14/11 Q1008", CategoryMVFR},
+		{"KXYZ 061751Z AUTO 20012KT //// 24/14 A3002", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			m, err := ParseAt(tt.raw, ref)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, m.FlightCategory())
+		})
+	}
+}
+
+func TestIsFlightCategory(t *testing.T) {
+	assert.True(t, IsFlightCategory("LIFR"))
+	assert.False(t, IsFlightCategory("lifr"))
+	assert.False(t, IsFlightCategory("SVFR"))
+}
@@ -0,0 +1,354 @@
+package metar
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Conditions are the groups shared by METAR bodies and TAF forecast groups.
+type Conditions struct {
+	Wind                 *Wind        `json:"wind,omitempty"`
+	Visibility           *Visibility  `json:"visibility,omitempty"`
+	CAVOK                bool         `json:"cavok,omitempty"`
+	Weather              []Weather    `json:"weather,omitempty"`
+	NoSignificantWeather bool         `json:"no_significant_weather,omitempty"`
+	Clouds               []CloudLayer `json:"clouds,omitempty"`
+	SkyClear             bool         `json:"sky_clear,omitempty"`
+	VerticalVisibilityFt *int         `json:"vertical_visibility_ft,omitempty"`
+}
+
+// Wind speeds are always reported in knots; MPS and KMH groups are converted.
+type Wind struct {
+	DirectionDeg    *int `json:"direction_deg"`
+	Variable        bool `json:"variable,omitempty"`
+	SpeedKt         int  `json:"speed_kt"`
+	GustKt          *int `json:"gust_kt,omitempty"`
+	VariableFromDeg *int `json:"variable_from_deg,omitempty"`
+	VariableToDeg   *int `json:"variable_to_deg,omitempty"`
+	Calm            bool `json:"calm,omitempty"`
+}
+
+// Visibility holds the prevailing visibility as reported: statute miles in
+// North America, metres elsewhere. LessThan and GreaterThan mark the M and P
+// prefixes, and 9999 metres (10 km or more).
+type Visibility struct {
+	StatuteMiles *float64 `json:"statute_miles,omitempty"`
+	Meters       *int     `json:"meters,omitempty"`
+	LessThan     bool     `json:"less_than,omitempty"`
+	GreaterThan  bool     `json:"greater_than,omitempty"`
+}
+
+// Miles returns the visibility in statute miles, whichever unit it was
+// reported in.
+func (v *Visibility) Miles() float64 {
+	if v.StatuteMiles != nil {
+		return *v.StatuteMiles
+	}
+	if v.Meters != nil {
+		return float64(*v.Meters) / metersPerStatuteMile
+	}
+	return 0
+}
+
+// Weather is one present weather group, e.g. "-SHRA" or "VCTS".
+type Weather struct {
+	Raw           string   `json:"raw"`
+	Intensity     string   `json:"intensity,omitempty"`
+	Descriptor    string   `json:"descriptor,omitempty"`
+	Precipitation []string `json:"precipitation,omitempty"`
+	Obscuration   string   `json:"obscuration,omitempty"`
+	Other         string   `json:"other,omitempty"`
+	Description   string   `json:"description"`
+}
+
+// CloudLayer is one sky condition group. BaseFt is nil when the base is
+// reported as "///".
+type CloudLayer struct {
+	Cover  string `json:"cover"`
+	BaseFt *int   `json:"base_ft,omitempty"`
+	Type   string `json:"type,omitempty"`
+}
+
+// Cloud cover codes.
+const (
+	CoverFew       = "FEW"
+	CoverScattered = "SCT"
+	CoverBroken    = "BKN"
+	CoverOvercast  = "OVC"
+)
+
+const (
+	metersPerStatuteMile = 1609.344
+	knotsPerMPS          = 1.943844
+	knotsPerKMH          = 0.539957
+)
+
+var (
+	windRe          = regexp.MustCompile(`^(\d{3}|VRB)(\d{2,3})(?:G(\d{2,3}))?(KT|MPS|KMH)$`)
+	missingWindRe   = regexp.MustCompile(`^/{5}(?:KT|MPS|KMH)$`)
+	windVariationRe = regexp.MustCompile(`^(\d{3})V(\d{3})$`)
+	visMilesRe      = regexp.MustCompile(`^([MP])?(?:(\d{1,2})|(\d{1,2} )?(\d)/(\d{1,2}))SM$`)
+	visMetersRe     = regexp.MustCompile(`^(\d{4})(?:NDV|[NSEW]{1,2})?$`)
+	wholeMilesRe    = regexp.MustCompile(`^\d{1,2}$`)
+	fractionMilesRe = regexp.MustCompile(`^\d/\d{1,2}SM$`)
+	weatherRe       = regexp.MustCompile(`^(-|\+|VC)?(MI|PR|BC|DR|BL|SH|TS|FZ)?((?:DZ|RA|SN|SG|IC|PL|GR|GS|UP)*)(BR|FG|FU|VA|DU|SA|HZ|PY)?(PO|SQ|FC|SS|DS)?$`)
+	cloudRe         = regexp.MustCompile(`^(FEW|SCT|BKN|OVC)(\d{3}|///)(CB|TCU|///)?$`)
+	verticalVisRe   = regexp.MustCompile(`^VV(\d{3}|///)$`)
+)
+
+// parseConditions consumes the condition group at tokens[i], if there is
+// one, and returns the index of the next unread token.
+func parseConditions(tokens []string, i int, c *Conditions) (int, bool) {
+	token := tokens[i]
+
+	switch {
+	case token == "CAVOK":
+		c.CAVOK = true
+		return i + 1, true
+	case token == "NSW":
+		c.NoSignificantWeather = true
+		return i + 1, true
+	case token == "SKC" || token == "CLR" || token == "NSC" || token == "NCD":
+		c.SkyClear = true
+		return i + 1, true
+	case missingWindRe.MatchString(token):
+		return i + 1, true
+	}
+
+	if wind, ok := parseWind(token); ok {
+		c.Wind = wind
+		return i + 1, true
+	}
+	if m := windVariationRe.FindStringSubmatch(token); m != nil && c.Wind != nil {
+		c.Wind.VariableFromDeg = ptr(atoi(m[1]))
+		c.Wind.VariableToDeg = ptr(atoi(m[2]))
+		return i + 1, true
+	}
+
+	// "1 1/2SM" is split over two tokens.
+	if wholeMilesRe.MatchString(token) && i+1 < len(tokens) && fractionMilesRe.MatchString(tokens[i+1]) {
+		if vis, ok := parseVisibility(token + " " + tokens[i+1]); ok {
+			c.Visibility = vis
+			return i + 2, true
+		}
+	}
+	if vis, ok := parseVisibility(token); ok {
+		c.Visibility = vis
+		return i + 1, true
+	}
+
+	if layer, ok := parseCloud(token); ok {
+		c.Clouds = append(c.Clouds, layer)
+		return i + 1, true
+	}
+	if m := verticalVisRe.FindStringSubmatch(token); m != nil {
+		if m[1] != "///" {
+			c.VerticalVisibilityFt = ptr(atoi(m[1]) * 100)
+		}
+		return i + 1, true
+	}
+
+	if weather, ok := parseWeather(token); ok {
+		c.Weather = append(c.Weather, weather)
+		return i + 1, true
+	}
+
+	return i, false
+}
+
+func parseWind(token string) (*Wind, bool) {
+	m := windRe.FindStringSubmatch(token)
+	if m == nil {
+		return nil, false
+	}
+
+	factor := 1.0
+	switch m[4] {
+	case "MPS":
+		factor = knotsPerMPS
+	case "KMH":
+		factor = knotsPerKMH
+	}
+	knots := func(value string) int {
+		return int(math.Round(float64(atoi(value)) * factor))
+	}
+
+	wind := &Wind{SpeedKt: knots(m[2])}
+	if m[1] == "VRB" {
+		wind.Variable = true
+	} else {
+		wind.DirectionDeg = ptr(atoi(m[1]))
+	}
+	if m[3] != "" {
+		wind.GustKt = ptr(knots(m[3]))
+	}
+	if wind.SpeedKt == 0 && !wind.Variable && *wind.DirectionDeg == 0 {
+		wind.Calm = true
+	}
+	return wind, true
+}
+
+func parseVisibility(token string) (*Visibility, bool) {
+	if m := visMilesRe.FindStringSubmatch(token); m != nil {
+		vis := &Visibility{LessThan: m[1] == "M", GreaterThan: m[1] == "P"}
+		var miles float64
+		if m[2] != "" {
+			miles, _ = strconv.ParseFloat(m[2], 64)
+		} else {
+			if whole := strings.TrimSpace(m[3]); whole != "" {
+				miles, _ = strconv.ParseFloat(whole, 64)
+			}
+			num, _ := strconv.ParseFloat(m[4], 64)
+			den, _ := strconv.ParseFloat(m[5], 64)
+			if den == 0 {
+				return nil, false
+			}
+			miles += num / den
+		}
+		vis.StatuteMiles = &miles
+		return vis, true
+	}
+
+	if m := visMetersRe.FindStringSubmatch(token); m != nil {
+		meters := atoi(m[1])
+		vis := &Visibility{Meters: &meters}
+		if meters == 9999 {
+			vis.Meters = ptr(10000)
+			vis.GreaterThan = true
+		}
+		return vis, true
+	}
+
+	return nil, false
+}
+
+func parseCloud(token string) (CloudLayer, bool) {
+	m := cloudRe.FindStringSubmatch(token)
+	if m == nil {
+		return CloudLayer{}, false
+	}
+
+	layer := CloudLayer{Cover: m[1]}
+	if m[2] != "///" {
+		layer.BaseFt = ptr(atoi(m[2]) * 100)
+	}
+	if m[3] != "///" {
+		layer.Type = m[3]
+	}
+	return layer, true
+}
+
+func parseWeather(token string) (Weather, bool) {
+	m := weatherRe.FindStringSubmatch(token)
+	if m == nil {
+		return Weather{}, false
+	}
+
+	intensity, descriptor, precip, obscuration, other := m[1], m[2], m[3], m[4], m[5]
+	if descriptor == "" && precip == "" && obscuration == "" && other == "" {
+		return Weather{}, false
+	}
+	// A descriptor needs a phenomenon, except thunderstorms and showers in
+	// the vicinity.
+	if precip == "" && obscuration == "" && other == "" && descriptor != "TS" && !(intensity == "VC" && descriptor == "SH") {
+		return Weather{}, false
+	}
+
+	weather := Weather{
+		Raw:         token,
+		Descriptor:  descriptor,
+		Obscuration: obscuration,
+		Other:       other,
+	}
+	switch intensity {
+	case "-":
+		weather.Intensity = "light"
+	case "+":
+		weather.Intensity = "heavy"
+	case "VC":
+		weather.Intensity = "vicinity"
+	}
+	for j := 0; j+2 <= len(precip); j += 2 {
+		weather.Precipitation = append(weather.Precipitation, precip[j:j+2])
+	}
+	weather.Description = describeWeather(weather)
+	return weather, true
+}
+
+var weatherCodes = map[string]string{
+	"MI": "shallow", "PR": "partial", "BC": "patches of", "DR": "low drifting", "BL": "blowing",
+	"SH": "showers", "TS": "thunderstorm", "FZ": "freezing",
+	"DZ": "drizzle", "RA": "rain", "SN": "snow", "SG": "snow grains", "IC": "ice crystals",
+	"PL": "ice pellets", "GR": "hail", "GS": "small hail", "UP": "unknown precipitation",
+	"BR": "mist", "FG": "fog", "FU": "smoke", "VA": "volcanic ash", "DU": "dust", "SA": "sand",
+	"HZ": "haze", "PY": "spray",
+	"PO": "dust whirls", "SQ": "squalls", "FC": "funnel cloud", "SS": "sandstorm", "DS": "duststorm",
+}
+
+// describeWeather renders a weather group in plain English, e.g. "light
+// showers of rain and snow".
+func describeWeather(w Weather) string {
+	var parts []string
+	if w.Intensity != "" && w.Intensity != "vicinity" {
+		parts = append(parts, w.Intensity)
+	}
+
+	precip := make([]string, 0, len(w.Precipitation))
+	for _, p := range w.Precipitation {
+		precip = append(precip, weatherCodes[p])
+	}
+	phenomena := strings.Join(precip, " and ")
+
+	switch {
+	case w.Descriptor == "SH" && phenomena != "":
+		parts = append(parts, "showers of "+phenomena)
+	case w.Descriptor == "TS" && phenomena != "":
+		parts = append(parts, "thunderstorm with "+phenomena)
+	case w.Descriptor != "":
+		parts = append(parts, weatherCodes[w.Descriptor])
+		if phenomena != "" {
+			parts = append(parts, phenomena)
+		}
+	case phenomena != "":
+		parts = append(parts, phenomena)
+	}
+	if w.Obscuration != "" {
+		parts = append(parts, weatherCodes[w.Obscuration])
+	}
+	if w.Other != "" {
+		parts = append(parts, weatherCodes[w.Other])
+	}
+	if w.Intensity == "vicinity" {
+		parts = append(parts, "in the vicinity")
+	}
+	return strings.Join(parts, " ")
+}
+
+// ceiling returns the height of the lowest broken or overcast layer, or the
+// vertical visibility into an obscured sky.
+func (c *Conditions) ceiling() *int {
+	var lowest *int
+	for _, layer := range c.Clouds {
+		if (layer.Cover == CoverBroken || layer.Cover == CoverOvercast) && layer.BaseFt != nil {
+			if lowest == nil || *layer.BaseFt < *lowest {
+				lowest = ptr(*layer.BaseFt)
+			}
+		}
+	}
+	if c.VerticalVisibilityFt != nil && (lowest == nil || *c.VerticalVisibilityFt < *lowest) {
+		lowest = ptr(*c.VerticalVisibilityFt)
+	}
+	return lowest
+}
+
+// atoi parses digits already matched by a regular expression.
+func atoi(s string) int {
+	v, _ := strconv.Atoi(s)
+	return v
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
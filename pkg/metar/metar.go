@@ -0,0 +1,340 @@
+// Package metar decodes raw METAR and TAF reports offline, without calling
+// any weather service.
+package metar
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrEmptyReport is returned for a blank report.
+	ErrEmptyReport = errors.New("empty report")
+	// ErrInvalidReport is returned when the station or time group is missing
+	// or malformed.
+	ErrInvalidReport = errors.New("invalid report")
+	// ErrNilReport is returned for a report sent as NIL (missing).
+	ErrNilReport = errors.New("nil report")
+)
+
+// Report types.
+const (
+	TypeMETAR = "METAR"
+	TypeSPECI = "SPECI"
+)
+
+// Metar is a decoded METAR or SPECI observation. Groups that were not
+// recognized are kept in Unparsed rather than failing the report.
+type Metar struct {
+	Raw       string    `json:"raw"`
+	Type      string    `json:"type"`
+	Station   string    `json:"station"`
+	Time      time.Time `json:"time"`
+	Auto      bool      `json:"auto,omitempty"`
+	Corrected bool      `json:"corrected,omitempty"`
+	Conditions
+	RVR          []RVR      `json:"rvr,omitempty"`
+	TemperatureC *float64   `json:"temperature_c,omitempty"`
+	DewpointC    *float64   `json:"dewpoint_c,omitempty"`
+	Altimeter    *Altimeter `json:"altimeter,omitempty"`
+	Trend        string     `json:"trend,omitempty"`
+	Remarks      *Remarks   `json:"remarks,omitempty"`
+	Unparsed     []string   `json:"unparsed,omitempty"`
+}
+
+// RVR is a runway visual range group, e.g. "R04R/1800V3000FT/U".
+type RVR struct {
+	Runway      string `json:"runway"`
+	Value       int    `json:"value"`
+	MaxValue    *int   `json:"max_value,omitempty"`
+	Unit        string `json:"unit"`
+	LessThan    bool   `json:"less_than,omitempty"`
+	GreaterThan bool   `json:"greater_than,omitempty"`
+	Trend       string `json:"trend,omitempty"`
+}
+
+// Altimeter is reported in either inches of mercury (A group) or hPa (Q
+// group); both fields are filled.
+type Altimeter struct {
+	InHg float64 `json:"in_hg"`
+	Hpa  float64 `json:"hpa"`
+}
+
+// Remarks holds the raw RMK section and the remarks decoded from it.
+type Remarks struct {
+	Raw                 string   `json:"raw"`
+	StationType         string   `json:"station_type,omitempty"`
+	SeaLevelPressureHpa *float64 `json:"sea_level_pressure_hpa,omitempty"`
+	TemperatureC        *float64 `json:"temperature_c,omitempty"`
+	DewpointC           *float64 `json:"dewpoint_c,omitempty"`
+	PeakWind            *Wind    `json:"peak_wind,omitempty"`
+	Maintenance         bool     `json:"maintenance,omitempty"`
+}
+
+const hpaPerInHg = 33.8639
+
+var (
+	stationRe     = regexp.MustCompile(`^[A-Z][A-Z0-9]{3}$`)
+	timeRe        = regexp.MustCompile(`^(\d{2})(\d{2})(\d{2})Z$`)
+	rvrRe         = regexp.MustCompile(`^R(\d{2}[LCR]?)/([MP])?(\d{4})(?:V([MP])?(\d{4}))?(FT)?/?([UDN])?$`)
+	temperatureRe = regexp.MustCompile(`^(M)?(\d{2})/(?:(M)?(\d{2}))?$`)
+	altimeterRe   = regexp.MustCompile(`^([AQ])(\d{4})$`)
+	slpRe         = regexp.MustCompile(`^SLP(\d{3})$`)
+	tempGroupRe   = regexp.MustCompile(`^T([01])(\d{3})(?:([01])(\d{3}))?$`)
+	peakWindRe    = regexp.MustCompile(`^(\d{3})(\d{2,3})/(\d{2})?(\d{2})$`)
+)
+
+// Parse decodes a METAR or SPECI, resolving its day-of-month time group
+// against the current time.
+func Parse(raw string) (*Metar, error) {
+	return ParseAt(raw, time.Now())
+}
+
+// ParseAt decodes a METAR or SPECI whose time group is resolved to the month
+// closest to ref.
+func ParseAt(raw string, ref time.Time) (*Metar, error) {
+	tokens := tokenize(raw)
+	if len(tokens) == 0 {
+		return nil, ErrEmptyReport
+	}
+
+	m := &Metar{Raw: strings.Join(tokens, " "), Type: TypeMETAR}
+	i := 0
+	if tokens[i] == TypeMETAR || tokens[i] == TypeSPECI {
+		m.Type = tokens[i]
+		i++
+	}
+	if i < len(tokens) && tokens[i] == "COR" {
+		m.Corrected = true
+		i++
+	}
+
+	if i >= len(tokens) || !stationRe.MatchString(tokens[i]) {
+		return nil, fmt.Errorf("%w: missing station identifier", ErrInvalidReport)
+	}
+	m.Station = tokens[i]
+	i++
+
+	if i >= len(tokens) {
+		return nil, fmt.Errorf("%w: missing observation time", ErrInvalidReport)
+	}
+	observed, err := parseTimeGroup(tokens[i], ref)
+	if err != nil {
+		return nil, err
+	}
+	m.Time = observed
+	i++
+
+	for ; i < len(tokens); i++ {
+		token := tokens[i]
+		switch {
+		case token == "NIL":
+			return nil, ErrNilReport
+		case token == "AUTO":
+			m.Auto = true
+		case token == "COR":
+			m.Corrected = true
+		case token == "RMK":
+			m.Remarks = parseRemarks(tokens[i+1:])
+			return m, nil
+		case token == "NOSIG" || token == "BECMG" || token == "TEMPO":
+			end := indexOf(tokens[i:], "RMK")
+			if end < 0 {
+				m.Trend = strings.Join(tokens[i:], " ")
+				return m, nil
+			}
+			m.Trend = strings.Join(tokens[i:i+end], " ")
+			i += end - 1
+		case strings.Trim(token, "/") == "":
+			// Sensor data missing, e.g. "////" or "//////".
+		default:
+			if next, ok := parseConditions(tokens, i, &m.Conditions); ok {
+				i = next - 1
+				continue
+			}
+			if !m.parseGroup(token) {
+				m.Unparsed = append(m.Unparsed, token)
+			}
+		}
+	}
+
+	return m, nil
+}
+
+// parseGroup decodes the METAR-only groups: RVR, temperature and altimeter.
+func (m *Metar) parseGroup(token string) bool {
+	if r := rvrRe.FindStringSubmatch(token); r != nil {
+		rvr := RVR{
+			Runway:      r[1],
+			Value:       atoi(r[3]),
+			Unit:        "M",
+			LessThan:    r[2] == "M",
+			GreaterThan: r[2] == "P" || r[4] == "P",
+			Trend:       r[7],
+		}
+		if r[5] != "" {
+			rvr.MaxValue = ptr(atoi(r[5]))
+		}
+		if r[6] == "FT" {
+			rvr.Unit = "FT"
+		}
+		m.RVR = append(m.RVR, rvr)
+		return true
+	}
+
+	if t := temperatureRe.FindStringSubmatch(token); t != nil {
+		m.TemperatureC = ptr(signed(t[1], t[2]))
+		if t[4] != "" {
+			m.DewpointC = ptr(signed(t[3], t[4]))
+		}
+		return true
+	}
+
+	if a := altimeterRe.FindStringSubmatch(token); a != nil {
+		value := float64(atoi(a[2]))
+		if a[1] == "A" {
+			inHg := value / 100
+			m.Altimeter = &Altimeter{InHg: inHg, Hpa: math.Round(inHg*hpaPerInHg*10) / 10}
+		} else {
+			m.Altimeter = &Altimeter{InHg: math.Round(value/hpaPerInHg*100) / 100, Hpa: value}
+		}
+		return true
+	}
+
+	return false
+}
+
+// CeilingFt returns the height above ground of the lowest broken or overcast
+// layer or of the vertical visibility, or nil when there is no ceiling.
+func (m *Metar) CeilingFt() *int {
+	return m.ceiling()
+}
+
+// Temperature returns the most precise temperature and dewpoint reported:
+// the remarks T group when present, otherwise the body group.
+func (m *Metar) Temperature() (temperatureC, dewpointC *float64) {
+	temperatureC, dewpointC = m.TemperatureC, m.DewpointC
+	if m.Remarks != nil {
+		if m.Remarks.TemperatureC != nil {
+			temperatureC = m.Remarks.TemperatureC
+		}
+		if m.Remarks.DewpointC != nil {
+			dewpointC = m.Remarks.DewpointC
+		}
+	}
+	return temperatureC, dewpointC
+}
+
+func parseRemarks(tokens []string) *Remarks {
+	r := &Remarks{Raw: strings.Join(tokens, " ")}
+
+	for i, token := range tokens {
+		switch {
+		case token == "AO1" || token == "AO2":
+			r.StationType = token
+		case token == "$":
+			r.Maintenance = true
+		case token == "PK" && i+2 < len(tokens) && tokens[i+1] == "WND":
+			if p := peakWindRe.FindStringSubmatch(tokens[i+2]); p != nil {
+				r.PeakWind = &Wind{DirectionDeg: ptr(atoi(p[1])), SpeedKt: atoi(p[2])}
+			}
+		}
+
+		if s := slpRe.FindStringSubmatch(token); s != nil {
+			// SLP gives the last three digits of the pressure in tenths.
+			tenths := float64(atoi(s[1])) / 10
+			hpa := 1000 + tenths
+			if tenths >= 50 {
+				hpa = 900 + tenths
+			}
+			r.SeaLevelPressureHpa = ptr(math.Round(hpa*10) / 10)
+		}
+		if t := tempGroupRe.FindStringSubmatch(token); t != nil {
+			r.TemperatureC = ptr(tenthsGroup(t[1], t[2]))
+			if t[4] != "" {
+				r.DewpointC = ptr(tenthsGroup(t[3], t[4]))
+			}
+		}
+	}
+
+	return r
+}
+
+// parseTimeGroup decodes a DDHHMMZ group.
+func parseTimeGroup(token string, ref time.Time) (time.Time, error) {
+	t := timeRe.FindStringSubmatch(token)
+	if t == nil {
+		return time.Time{}, fmt.Errorf("%w: malformed time group %q", ErrInvalidReport, token)
+	}
+
+	resolved, ok := resolveTime(ref, atoi(t[1]), atoi(t[2]), atoi(t[3]))
+	if !ok {
+		return time.Time{}, fmt.Errorf("%w: impossible time group %q", ErrInvalidReport, token)
+	}
+	return resolved, nil
+}
+
+// resolveTime places a day-of-month time in the month before, of, or after
+// ref, whichever is closest to ref. An hour of 24 means the end of the day.
+func resolveTime(ref time.Time, day, hour, minute int) (time.Time, bool) {
+	if day < 1 || day > 31 || hour > 24 || minute > 59 || (hour == 24 && minute != 0) {
+		return time.Time{}, false
+	}
+
+	ref = ref.UTC()
+	var best time.Time
+	for offset := -1; offset <= 1; offset++ {
+		midnight := time.Date(ref.Year(), ref.Month()+time.Month(offset), day, 0, 0, 0, 0, time.UTC)
+		if midnight.Day() != day {
+			continue // e.g. the 31st of a 30-day month
+		}
+		candidate := midnight.Add(time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute)
+		if best.IsZero() || absDuration(candidate.Sub(ref)) < absDuration(best.Sub(ref)) {
+			best = candidate
+		}
+	}
+	return best, !best.IsZero()
+}
+
+// tokenize splits a report on whitespace and drops the "=" terminator.
+func tokenize(raw string) []string {
+	raw = strings.ToUpper(strings.TrimSpace(raw))
+	raw = strings.TrimSuffix(raw, "=")
+	return strings.Fields(raw)
+}
+
+func signed(minus, digits string) float64 {
+	v := float64(atoi(digits))
+	if minus == "M" {
+		return -v
+	}
+	return v
+}
+
+func tenthsGroup(sign, digits string) float64 {
+	v := float64(atoi(digits)) / 10
+	if sign == "1" {
+		return -v
+	}
+	return v
+}
+
+func indexOf(tokens []string, value string) int {
+	for i, token := range tokens {
+		if token == value {
+			return i
+		}
+	}
+	return -1
+}
+
+func absDuration(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+	return d
+}
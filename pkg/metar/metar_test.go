@@ -0,0 +1,243 @@
+package metar
+
+import (
+	"bufio"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var ref = time.Date(2025, 10, 6, 18, 0, 0, 0, time.UTC)
+
+// readCorpus returns the reports in a testdata file, skipping blank lines
+// and comments.
+func readCorpus(t *testing.T, name string) []string {
+	t.Helper()
+	f, err := os.Open("testdata/" + name)
+	require.NoError(t, err)
+	defer f.Close()
+
+	var reports []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		reports = append(reports, line)
+	}
+	require.NoError(t, scanner.Err())
+	require.NotEmpty(t, reports)
+	return reports
+}
+
+func TestParse_Corpus(t *testing.T) {
+	for _, raw := range readCorpus(t, "metar.txt") {
+		t.Run(strings.Fields(raw)[0]+" "+strings.Fields(raw)[1], func(t *testing.T) {
+			m, err := ParseAt(raw, ref)
+			require.NoError(t, err)
+			assert.Empty(t, m.Unparsed)
+			assert.NotEmpty(t, m.Station)
+			assert.NotNil(t, m.Wind)
+			assert.NotNil(t, m.TemperatureC)
+			assert.NotNil(t, m.Altimeter)
+			assert.Equal(t, 2025, m.Time.Year())
+		})
+	}
+}
+
+func TestParse_FullReport(t *testing.T) {
+	m, err := ParseAt("KJFK 061751Z 18012G22KT 150V210 10SM -RA BKN015 OVC030 17/11 A2992 RMK AO2 PK WND 20032/1715 SLP132 T01720106 $", ref)
+	require.NoError(t, err)
+
+	assert.Equal(t, TypeMETAR, m.Type)
+	assert.Equal(t, "KJFK", m.Station)
+	assert.Equal(t, time.Date(2025, 10, 6, 17, 51, 0, 0, time.UTC), m.Time)
+
+	require.NotNil(t, m.Wind)
+	assert.Equal(t, 180, *m.Wind.DirectionDeg)
+	assert.Equal(t, 12, m.Wind.SpeedKt)
+	assert.Equal(t, 22, *m.Wind.GustKt)
+	assert.Equal(t, 150, *m.Wind.VariableFromDeg)
+	assert.Equal(t, 210, *m.Wind.VariableToDeg)
+
+	require.NotNil(t, m.Visibility)
+	assert.Equal(t, 10.0, *m.Visibility.StatuteMiles)
+
+	require.Len(t, m.Weather, 1)
+	assert.Equal(t, "light", m.Weather[0].Intensity)
+	assert.Equal(t, []string{"RA"}, m.Weather[0].Precipitation)
+	assert.Equal(t, "light rain", m.Weather[0].Description)
+
+	require.Len(t, m.Clouds, 2)
+	assert.Equal(t, CloudLayer{Cover: CoverBroken, BaseFt: ptr(1500)}, m.Clouds[0])
+	assert.Equal(t, 1500, *m.CeilingFt())
+
+	assert.Equal(t, 17.0, *m.TemperatureC)
+	assert.Equal(t, 11.0, *m.DewpointC)
+	assert.Equal(t, &Altimeter{InHg: 29.92, Hpa: 1013.2}, m.Altimeter)
+
+	require.NotNil(t, m.Remarks)
+	assert.Equal(t, "AO2", m.Remarks.StationType)
+	assert.Equal(t, 1013.2, *m.Remarks.SeaLevelPressureHpa)
+	assert.True(t, m.Remarks.Maintenance)
+	require.NotNil(t, m.Remarks.PeakWind)
+	assert.Equal(t, 32, m.Remarks.PeakWind.SpeedKt)
+
+	temp, dew := m.Temperature()
+	assert.Equal(t, 17.2, *temp)
+	assert.Equal(t, 10.6, *dew)
+}
+
+func TestParse_Visibility(t *testing.T) {
+	tests := []struct {
+		name  string
+		group string
+		want  Visibility
+		miles float64
+	}{
+		{"whole miles", "3SM", Visibility{StatuteMiles: ptr(3.0)}, 3},
+		{"fraction", "3/4SM", Visibility{StatuteMiles: ptr(0.75)}, 0.75},
+		{"mixed fraction", "1 1/2SM", Visibility{StatuteMiles: ptr(1.5)}, 1.5},
+		{"less than", "M1/4SM", Visibility{StatuteMiles: ptr(0.25), LessThan: true}, 0.25},
+		{"greater than", "P6SM", Visibility{StatuteMiles: ptr(6.0), GreaterThan: true}, 6},
+		{"meters", "1600", Visibility{Meters: ptr(1600)}, 1600 / metersPerStatuteMile},
+		{"10 km or more", "9999", Visibility{Meters: ptr(10000), GreaterThan: true}, 10000 / metersPerStatuteMile},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := ParseAt("KXYZ 061751Z 00000KT "+tt.group+" CLR 10/05 A3000", ref)
+			require.NoError(t, err)
+			assert.Empty(t, m.Unparsed)
+			require.NotNil(t, m.Visibility)
+			assert.Equal(t, tt.want, *m.Visibility)
+			assert.InDelta(t, tt.miles, m.Visibility.Miles(), 0.001)
+		})
+	}
+}
+
+func TestParse_Wind(t *testing.T) {
+	tests := []struct {
+		name  string
+		group string
+		want  Wind
+	}{
+		{"calm", "00000KT", Wind{DirectionDeg: ptr(0), Calm: true}},
+		{"variable", "VRB03KT", Wind{Variable: true, SpeedKt: 3}},
+		{"three digit speed", "270105G130KT", Wind{DirectionDeg: ptr(270), SpeedKt: 105, GustKt: ptr(130)}},
+		{"metres per second", "30005MPS", Wind{DirectionDeg: ptr(300), SpeedKt: 10}},
+		{"kilometres per hour", "12020KMH", Wind{DirectionDeg: ptr(120), SpeedKt: 11}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := ParseAt("KXYZ 061751Z "+tt.group+" 9999 10/05 Q1013", ref)
+			require.NoError(t, err)
+			require.NotNil(t, m.Wind)
+			assert.Equal(t, tt.want, *m.Wind)
+		})
+	}
+}
+
+func TestParse_WeatherAndClouds(t *testing.T) {
+	m, err := ParseAt("KBUF 061754Z 27022G31KT 1/2SM +SN FZFG VCTS VCSH -SHRAGS FEW008 BKN///TCU SCT020CB VV004 M02/M03 A2972", ref)
+	require.NoError(t, err)
+	assert.Empty(t, m.Unparsed)
+
+	descriptions := make([]string, 0, len(m.Weather))
+	for _, w := range m.Weather {
+		descriptions = append(descriptions, w.Description)
+	}
+	assert.Equal(t, []string{
+		"heavy snow",
+		"freezing fog",
+		"thunderstorm in the vicinity",
+		"showers in the vicinity",
+		"light showers of rain and small hail",
+	}, descriptions)
+
+	require.Len(t, m.Clouds, 3)
+	assert.Nil(t, m.Clouds[1].BaseFt)
+	assert.Equal(t, "TCU", m.Clouds[1].Type)
+	assert.Equal(t, "CB", m.Clouds[2].Type)
+	assert.Equal(t, 400, *m.VerticalVisibilityFt)
+	assert.Equal(t, 400, *m.CeilingFt())
+	assert.Equal(t, -2.0, *m.TemperatureC)
+	assert.Equal(t, -3.0, *m.DewpointC)
+}
+
+func TestParse_RVR(t *testing.T) {
+	m, err := ParseAt("EFHK 061750Z 20008KT 0400 R04L/0700U R04R/P2000N R22/1800V3000FT/D R15/M0050 FG VV002 06/06 Q1004", ref)
+	require.NoError(t, err)
+	assert.Empty(t, m.Unparsed)
+
+	require.Len(t, m.RVR, 4)
+	assert.Equal(t, RVR{Runway: "04L", Value: 700, Unit: "M", Trend: "U"}, m.RVR[0])
+	assert.Equal(t, RVR{Runway: "04R", Value: 2000, Unit: "M", GreaterThan: true, Trend: "N"}, m.RVR[1])
+	assert.Equal(t, RVR{Runway: "22", Value: 1800, MaxValue: ptr(3000), Unit: "FT", Trend: "D"}, m.RVR[2])
+	assert.Equal(t, RVR{Runway: "15", Value: 50, Unit: "M", LessThan: true}, m.RVR[3])
+	assert.Equal(t, &Altimeter{InHg: 29.65, Hpa: 1004}, m.Altimeter)
+}
+
+func TestParse_CAVOKAndTrend(t *testing.T) {
+	m, err := ParseAt("METAR LFPG 061800Z 24012KT CAVOK 16/08 Q1015 BECMG 9999 -RA RMK QFE1002", ref)
+	require.NoError(t, err)
+
+	assert.True(t, m.CAVOK)
+	assert.Nil(t, m.CeilingFt())
+	assert.Equal(t, "BECMG 9999 -RA", m.Trend)
+	assert.Equal(t, "QFE1002", m.Remarks.Raw)
+	assert.Empty(t, m.Weather)
+}
+
+func TestParse_Header(t *testing.T) {
+	m, err := ParseAt("speci kord 061812z cor auto 24018g28kt ////// 3/4sm 16/14 a2983=", ref)
+	require.NoError(t, err)
+
+	assert.Equal(t, TypeSPECI, m.Type)
+	assert.Equal(t, "KORD", m.Station)
+	assert.True(t, m.Corrected)
+	assert.True(t, m.Auto)
+	assert.Empty(t, m.Unparsed)
+	assert.Equal(t, "SPECI KORD 061812Z COR AUTO 24018G28KT ////// 3/4SM 16/14 A2983", m.Raw)
+}
+
+func TestParse_TimeAcrossMonthBoundary(t *testing.T) {
+	m, err := ParseAt("KJFK 302351Z 00000KT 10SM CLR 10/05 A3000", time.Date(2025, 10, 1, 0, 5, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2025, 9, 30, 23, 51, 0, 0, time.UTC), m.Time)
+}
+
+func TestParse_KeepsUnknownGroups(t *testing.T) {
+	m, err := ParseAt("KJFK 061751Z 18012KT 10SM XYZZY CLR 17/11 A2992", ref)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"XYZZY"}, m.Unparsed)
+}
+
+func TestParse_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+		want error
+	}{
+		{"empty", "   ", ErrEmptyReport},
+		{"no station", "METAR", ErrInvalidReport},
+		{"bad station", "K1 061751Z 18012KT", ErrInvalidReport},
+		{"no time", "KJFK", ErrInvalidReport},
+		{"bad time", "KJFK 0617Z 18012KT", ErrInvalidReport},
+		{"impossible time", "KJFK 322561Z 18012KT", ErrInvalidReport},
+		{"nil report", "KJFK 061751Z NIL", ErrNilReport},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseAt(tt.raw, ref)
+			assert.ErrorIs(t, err, tt.want)
+		})
+	}
+}
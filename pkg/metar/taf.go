@@ -0,0 +1,243 @@
+package metar
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Change group types. The first group of every TAF is the BASE forecast.
+const (
+	ChangeBase  = "BASE"
+	ChangeFM    = "FM"
+	ChangeBECMG = "BECMG"
+	ChangeTEMPO = "TEMPO"
+	ChangePROB  = "PROB"
+)
+
+// TAF is a decoded terminal aerodrome forecast.
+type TAF struct {
+	Raw          string           `json:"raw"`
+	Station      string           `json:"station"`
+	IssuedAt     time.Time        `json:"issued_at"`
+	ValidFrom    time.Time        `json:"valid_from"`
+	ValidTo      time.Time        `json:"valid_to"`
+	Amended      bool             `json:"amended,omitempty"`
+	Corrected    bool             `json:"corrected,omitempty"`
+	Groups       []ForecastGroup  `json:"groups"`
+	Temperatures []TAFTemperature `json:"temperatures,omitempty"`
+	Remarks      string           `json:"remarks,omitempty"`
+}
+
+// ForecastGroup is the base forecast or one change group. From and To bound
+// the period the group applies to; an FM group lasts until the next FM group
+// or the end of the TAF. Probability is set for PROB30/PROB40 groups, which
+// may be combined with TEMPO (Type TEMPO, Probability 30).
+type ForecastGroup struct {
+	Type        string    `json:"type"`
+	Probability *int      `json:"probability,omitempty"`
+	From        time.Time `json:"from"`
+	To          time.Time `json:"to"`
+	Conditions
+	WindShear *WindShear `json:"wind_shear,omitempty"`
+	Unparsed  []string   `json:"unparsed,omitempty"`
+}
+
+// WindShear is a low-level wind shear group, e.g. "WS020/27045KT".
+type WindShear struct {
+	HeightFt int  `json:"height_ft"`
+	Wind     Wind `json:"wind"`
+}
+
+// TAFTemperature is a forecast maximum (TX) or minimum (TN) temperature.
+type TAFTemperature struct {
+	Kind         string    `json:"kind"`
+	TemperatureC float64   `json:"temperature_c"`
+	At           time.Time `json:"at"`
+}
+
+var (
+	periodRe    = regexp.MustCompile(`^(\d{2})(\d{2})/(\d{2})(\d{2})$`)
+	fromRe      = regexp.MustCompile(`^FM(\d{2})(\d{2})(\d{2})$`)
+	probRe      = regexp.MustCompile(`^PROB(\d{2})$`)
+	windShearRe = regexp.MustCompile(`^WS(\d{3})/(.+)$`)
+	tafTempRe   = regexp.MustCompile(`^T([XN])(M)?(\d{2})/(\d{2})(\d{2})Z$`)
+)
+
+// ParseTAF decodes a TAF, resolving its time groups against the current time.
+func ParseTAF(raw string) (*TAF, error) {
+	return ParseTAFAt(raw, time.Now())
+}
+
+// ParseTAFAt decodes a TAF whose time groups are resolved to the month
+// closest to ref.
+func ParseTAFAt(raw string, ref time.Time) (*TAF, error) {
+	tokens := tokenize(raw)
+	if len(tokens) == 0 {
+		return nil, ErrEmptyReport
+	}
+
+	taf := &TAF{Raw: strings.Join(tokens, " ")}
+	i := 0
+	if tokens[i] == "TAF" {
+		i++
+	}
+	for ; i < len(tokens) && (tokens[i] == "AMD" || tokens[i] == "COR"); i++ {
+		taf.Amended = taf.Amended || tokens[i] == "AMD"
+		taf.Corrected = taf.Corrected || tokens[i] == "COR"
+	}
+
+	if i >= len(tokens) || !stationRe.MatchString(tokens[i]) {
+		return nil, fmt.Errorf("%w: missing station identifier", ErrInvalidReport)
+	}
+	taf.Station = tokens[i]
+	i++
+
+	if i >= len(tokens) {
+		return nil, fmt.Errorf("%w: missing issue time", ErrInvalidReport)
+	}
+	issued, err := parseTimeGroup(tokens[i], ref)
+	if err != nil {
+		return nil, err
+	}
+	taf.IssuedAt = issued
+	i++
+
+	if i < len(tokens) && tokens[i] == "NIL" {
+		return nil, ErrNilReport
+	}
+	if i >= len(tokens) {
+		return nil, fmt.Errorf("%w: missing validity period", ErrInvalidReport)
+	}
+	from, to, ok := parsePeriod(tokens[i], issued)
+	if !ok {
+		return nil, fmt.Errorf("%w: malformed validity period %q", ErrInvalidReport, tokens[i])
+	}
+	taf.ValidFrom, taf.ValidTo = from, to
+	i++
+
+	group := &ForecastGroup{Type: ChangeBase, From: from, To: to}
+	for ; i < len(tokens); i++ {
+		token := tokens[i]
+
+		if next, ok := taf.startGroup(tokens, i); ok {
+			taf.Groups = append(taf.Groups, *group)
+			group = next.group
+			i = next.index - 1
+			continue
+		}
+
+		switch {
+		case token == "NIL":
+			return nil, ErrNilReport
+		case token == "CNL":
+			taf.Remarks = strings.TrimSpace(taf.Remarks + " CNL")
+		case token == "RMK":
+			taf.Remarks = strings.Join(tokens[i+1:], " ")
+			i = len(tokens)
+		case tafTempRe.MatchString(token):
+			t := tafTempRe.FindStringSubmatch(token)
+			kind := "max"
+			if t[1] == "N" {
+				kind = "min"
+			}
+			at, _ := resolveTime(issued, atoi(t[4]), atoi(t[5]), 0)
+			taf.Temperatures = append(taf.Temperatures, TAFTemperature{Kind: kind, TemperatureC: signed(t[2], t[3]), At: at})
+		case windShearRe.MatchString(token):
+			ws := windShearRe.FindStringSubmatch(token)
+			if wind, ok := parseWind(ws[2]); ok {
+				group.WindShear = &WindShear{HeightFt: atoi(ws[1]) * 100, Wind: *wind}
+			} else {
+				group.Unparsed = append(group.Unparsed, token)
+			}
+		default:
+			if next, ok := parseConditions(tokens, i, &group.Conditions); ok {
+				i = next - 1
+				continue
+			}
+			group.Unparsed = append(group.Unparsed, token)
+		}
+	}
+	taf.Groups = append(taf.Groups, *group)
+
+	taf.closeFMGroups()
+	return taf, nil
+}
+
+type groupStart struct {
+	group *ForecastGroup
+	index int
+}
+
+// startGroup recognizes a change group header at tokens[i]: FMddhhmm,
+// BECMG/TEMPO followed by a period, or PROBnn [TEMPO] followed by a period.
+func (taf *TAF) startGroup(tokens []string, i int) (groupStart, bool) {
+	token := tokens[i]
+
+	if f := fromRe.FindStringSubmatch(token); f != nil {
+		from, ok := resolveTime(taf.IssuedAt, atoi(f[1]), atoi(f[2]), atoi(f[3]))
+		if !ok {
+			return groupStart{}, false
+		}
+		return groupStart{&ForecastGroup{Type: ChangeFM, From: from, To: taf.ValidTo}, i + 1}, true
+	}
+
+	group := &ForecastGroup{}
+	j := i
+	if p := probRe.FindStringSubmatch(token); p != nil {
+		group.Type = ChangePROB
+		group.Probability = ptr(atoi(p[1]))
+		j++
+		if j < len(tokens) && tokens[j] == ChangeTEMPO {
+			group.Type = ChangeTEMPO
+			j++
+		}
+	} else if token == ChangeBECMG || token == ChangeTEMPO {
+		group.Type = token
+		j++
+	} else {
+		return groupStart{}, false
+	}
+
+	if j >= len(tokens) {
+		return groupStart{}, false
+	}
+	from, to, ok := parsePeriod(tokens[j], taf.IssuedAt)
+	if !ok {
+		return groupStart{}, false
+	}
+	group.From, group.To = from, to
+	return groupStart{group, j + 1}, true
+}
+
+// closeFMGroups ends the base forecast and every FM group where the next FM
+// group takes over.
+func (taf *TAF) closeFMGroups() {
+	prevailing := 0
+	for i := 1; i < len(taf.Groups); i++ {
+		if taf.Groups[i].Type != ChangeFM {
+			continue
+		}
+		taf.Groups[prevailing].To = taf.Groups[i].From
+		prevailing = i
+	}
+}
+
+// parsePeriod decodes a DDHH/DDHH validity period.
+func parsePeriod(token string, ref time.Time) (time.Time, time.Time, bool) {
+	p := periodRe.FindStringSubmatch(token)
+	if p == nil {
+		return time.Time{}, time.Time{}, false
+	}
+
+	from, ok := resolveTime(ref, atoi(p[1]), atoi(p[2]), 0)
+	if !ok {
+		return time.Time{}, time.Time{}, false
+	}
+	to, ok := resolveTime(from, atoi(p[3]), atoi(p[4]), 0)
+	if !ok || to.Before(from) {
+		return time.Time{}, time.Time{}, false
+	}
+	return from, to, true
+}
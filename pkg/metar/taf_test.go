@@ -0,0 +1,134 @@
+package metar
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTAF_Corpus(t *testing.T) {
+	for _, raw := range readCorpus(t, "taf.txt") {
+		t.Run(strings.Fields(raw)[1], func(t *testing.T) {
+			taf, err := ParseTAFAt(raw, ref)
+			require.NoError(t, err)
+			require.NotEmpty(t, taf.Groups)
+			assert.Equal(t, ChangeBase, taf.Groups[0].Type)
+			assert.True(t, taf.ValidTo.After(taf.ValidFrom))
+			for _, g := range taf.Groups {
+				assert.Empty(t, g.Unparsed, "group %s from %s", g.Type, g.From)
+				assert.False(t, g.To.Before(g.From), "group %s from %s", g.Type, g.From)
+			}
+		})
+	}
+}
+
+func TestParseTAF_ChangeGroups(t *testing.T) {
+	raw := "TAF AMD KORD 061720Z 0618/0724 22014G24KT P6SM VCSH BKN045 TX27/0620Z TNM01/0711Z " +
+		"FM062000 24018G28KT 3SM TSRA BKN015CB WS020/27045KT " +
+		"PROB30 0620/0623 1SM +TSRA OVC008CB " +
+		"FM070300 30012KT P6SM SCT040 " +
+		"BECMG 0710/0712 VRB03KT " +
+		"PROB40 TEMPO 0714/0718 2SM BR " +
+		"TEMPO 0718/0722 5SM -SHRA RMK NXT FCST BY 06/21Z"
+
+	taf, err := ParseTAFAt(raw, ref)
+	require.NoError(t, err)
+
+	at := func(day, hour int) time.Time { return time.Date(2025, 10, day, hour, 0, 0, 0, time.UTC) }
+
+	assert.Equal(t, "KORD", taf.Station)
+	assert.True(t, taf.Amended)
+	assert.Equal(t, time.Date(2025, 10, 6, 17, 20, 0, 0, time.UTC), taf.IssuedAt)
+	assert.Equal(t, at(6, 18), taf.ValidFrom)
+	assert.Equal(t, at(8, 0), taf.ValidTo)
+	assert.Equal(t, "NXT FCST BY 06/21Z", taf.Remarks)
+	assert.Equal(t, []TAFTemperature{
+		{Kind: "max", TemperatureC: 27, At: at(6, 20)},
+		{Kind: "min", TemperatureC: -1, At: at(7, 11)},
+	}, taf.Temperatures)
+
+	require.Len(t, taf.Groups, 7)
+
+	base := taf.Groups[0]
+	assert.Equal(t, ChangeBase, base.Type)
+	assert.Equal(t, at(6, 18), base.From)
+	assert.Equal(t, at(6, 20), base.To, "the base forecast ends at the first FM group")
+	assert.Equal(t, 14, base.Wind.SpeedKt)
+	assert.Equal(t, "showers in the vicinity", base.Weather[0].Description)
+
+	fm := taf.Groups[1]
+	assert.Equal(t, ChangeFM, fm.Type)
+	assert.Equal(t, at(6, 20), fm.From)
+	assert.Equal(t, at(7, 3), fm.To, "an FM group ends at the next FM group")
+	assert.Equal(t, 1500, *fm.ceiling())
+	require.NotNil(t, fm.WindShear)
+	assert.Equal(t, 2000, fm.WindShear.HeightFt)
+	assert.Equal(t, 45, fm.WindShear.Wind.SpeedKt)
+
+	prob := taf.Groups[2]
+	assert.Equal(t, ChangePROB, prob.Type)
+	assert.Equal(t, 30, *prob.Probability)
+	assert.Equal(t, at(6, 20), prob.From)
+	assert.Equal(t, at(6, 23), prob.To)
+	assert.Equal(t, 1.0, prob.Visibility.Miles())
+
+	last := taf.Groups[3]
+	assert.Equal(t, ChangeFM, last.Type)
+	assert.Equal(t, at(8, 0), last.To, "the last FM group runs to the end of the TAF")
+
+	becmg := taf.Groups[4]
+	assert.Equal(t, ChangeBECMG, becmg.Type)
+	assert.True(t, becmg.Wind.Variable)
+
+	probTempo := taf.Groups[5]
+	assert.Equal(t, ChangeTEMPO, probTempo.Type)
+	assert.Equal(t, 40, *probTempo.Probability)
+	assert.Equal(t, at(7, 14), probTempo.From)
+
+	tempo := taf.Groups[6]
+	assert.Equal(t, ChangeTEMPO, tempo.Type)
+	assert.Nil(t, tempo.Probability)
+	assert.Equal(t, at(7, 22), tempo.To)
+}
+
+func TestParseTAF_PeriodAcrossMonthBoundary(t *testing.T) {
+	taf, err := ParseTAFAt("TAF KSEA 302320Z 0100/0206 18008KT P6SM BKN030 FM012000 20010KT P6SM OVC015", ref)
+	require.NoError(t, err)
+
+	assert.Equal(t, time.Date(2025, 9, 30, 23, 20, 0, 0, time.UTC), taf.IssuedAt)
+	assert.Equal(t, time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC), taf.ValidFrom)
+	assert.Equal(t, time.Date(2025, 10, 2, 6, 0, 0, 0, time.UTC), taf.ValidTo)
+	assert.Equal(t, time.Date(2025, 10, 1, 20, 0, 0, 0, time.UTC), taf.Groups[1].From)
+}
+
+func TestParseTAF_EndOfDay(t *testing.T) {
+	taf, err := ParseTAFAt("TAF EGLL 061700Z 0618/0624 22015KT 9999 FEW012", ref)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2025, 10, 7, 0, 0, 0, 0, time.UTC), taf.ValidTo)
+}
+
+func TestParseTAF_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+		want error
+	}{
+		{"empty", "", ErrEmptyReport},
+		{"no station", "TAF", ErrInvalidReport},
+		{"no issue time", "TAF KJFK", ErrInvalidReport},
+		{"no period", "TAF KJFK 061720Z", ErrInvalidReport},
+		{"bad period", "TAF KJFK 061720Z 0618 20012KT", ErrInvalidReport},
+		{"reversed period", "TAF KJFK 061720Z 0718/0618 20012KT", ErrInvalidReport},
+		{"nil", "TAF KJFK 061720Z NIL", ErrNilReport},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseTAFAt(tt.raw, ref)
+			assert.ErrorIs(t, err, tt.want)
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"flight-api/pkg/logger"
+	"flight-api/util"
+	"fmt"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Recoverer recovers from panics in downstream handlers and renders them as
+// an internal server error problem instead of dropping the connection.
+func Recoverer(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			rec := recover()
+			if rec == nil {
+				return
+			}
+			if rec == http.ErrAbortHandler {
+				// Let net/http abort the response as intended
+				panic(rec)
+			}
+
+			logger := logger.NewLogger(logger.INFO_DEBUG_LEVEL)
+			logger.Errorw(logrus.Fields{
+				"panic": rec,
+				"stack": string(debug.Stack()),
+			}, "[Recoverer] Recovered from panic")
+
+			err, ok := rec.(error)
+			if !ok {
+				err = fmt.Errorf("%v", rec)
+			}
+
+			util.ErrorHandler(w, r, util.NewAppError(util.ErrInternalServer, "An unexpected error occurred on the server", err))
+		}()
+
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,171 @@
+package nasr
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// field is a column of an APT.txt record: its 1-based start and length as
+// given in the record layout, apt_rf.txt.
+type field struct{ start, length int }
+
+func (f field) get(line string) string {
+	start := f.start - 1
+	if start >= len(line) {
+		return ""
+	}
+	end := min(start+f.length, len(line))
+	return strings.TrimSpace(line[start:end])
+}
+
+// APT record
+var (
+	aptSiteNumber     = field{4, 11}
+	aptType           = field{15, 13}
+	aptFAAID          = field{28, 4}
+	aptEffectiveDate  = field{32, 10}
+	aptRegion         = field{42, 3}
+	aptDistrictOffice = field{45, 4}
+	aptState          = field{49, 2}
+	aptStateFull      = field{51, 20}
+	aptCounty         = field{71, 21}
+	aptCity           = field{94, 40}
+	aptName           = field{134, 50}
+	aptOwnership      = field{184, 2}
+	aptUse            = field{186, 2}
+	aptManager        = field{356, 35}
+	aptManagerPhone   = field{508, 16}
+	aptLatitude       = field{524, 15}
+	aptLatitudeSec    = field{539, 12}
+	aptLongitude      = field{551, 15}
+	aptLongitudeSec   = field{566, 12}
+	aptElevation      = field{579, 7}
+	aptMagVariation   = field{587, 3}
+	aptTPA            = field{594, 4}
+	aptSectional      = field{598, 30}
+	aptNotamID        = field{829, 4}
+	aptStatus         = field{841, 2}
+	aptControlTower   = field{981, 1}
+	aptUnicom         = field{982, 7}
+	aptCTAF           = field{989, 7}
+	aptICAOID         = field{1211, 7}
+)
+
+// RWY record
+var (
+	rwySiteNumber        = field{4, 11}
+	rwyID                = field{17, 7}
+	rwyLength            = field{24, 5}
+	rwyWidth             = field{29, 4}
+	rwySurface           = field{33, 12}
+	rwyBaseEnd           = field{66, 3}
+	rwyBaseHeading       = field{69, 3}
+	rwyReciprocalEnd     = field{288, 3}
+	rwyReciprocalHeading = field{291, 3}
+)
+
+// ReadAPT reads airports and their runways from the fixed-width APT.txt.
+// Records other than APT and RWY are skipped.
+func ReadAPT(r io.Reader) ([]Airport, error) {
+	airports := []Airport{}
+	bySite := map[string]int{}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		text := scanner.Text()
+		switch {
+		case strings.HasPrefix(text, "APT"):
+			airport, err := aptAirport(text)
+			if err != nil {
+				return nil, fmt.Errorf("nasr: APT.txt line %d: %w", line, err)
+			}
+			bySite[airport.SiteNumber] = len(airports)
+			airports = append(airports, airport)
+		case strings.HasPrefix(text, "RWY"):
+			i, ok := bySite[rwySiteNumber.get(text)]
+			if !ok {
+				continue
+			}
+			airports[i].Runways = append(airports[i].Runways, aptRunway(text))
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("nasr: APT.txt: %w", err)
+	}
+
+	return airports, nil
+}
+
+func aptAirport(line string) (Airport, error) {
+	airport := Airport{
+		SiteNumber:     aptSiteNumber.get(line),
+		Type:           aptType.get(line),
+		FAAID:          aptFAAID.get(line),
+		ICAOID:         aptICAOID.get(line),
+		Region:         aptRegion.get(line),
+		DistrictOffice: aptDistrictOffice.get(line),
+		State:          aptState.get(line),
+		StateFull:      aptStateFull.get(line),
+		County:         aptCounty.get(line),
+		City:           aptCity.get(line),
+		Name:           aptName.get(line),
+		Ownership:      aptOwnership.get(line),
+		Use:            aptUse.get(line),
+		Manager:        aptManager.get(line),
+		ManagerPhone:   aptManagerPhone.get(line),
+		Latitude:       aptLatitude.get(line),
+		LatitudeSec:    aptLatitudeSec.get(line),
+		Longitude:      aptLongitude.get(line),
+		LongitudeSec:   aptLongitudeSec.get(line),
+		Elevation:      aptElevation.get(line),
+		MagVariation:   aptMagVariation.get(line),
+		TPA:            aptTPA.get(line),
+		Sectional:      aptSectional.get(line),
+		NotamID:        aptNotamID.get(line),
+		Status:         aptStatus.get(line),
+		ControlTower:   aptControlTower.get(line),
+		Unicom:         aptUnicom.get(line),
+		CTAF:           aptCTAF.get(line),
+		Runways:        []Runway{},
+	}
+	if airport.SiteNumber == "" {
+		return Airport{}, fmt.Errorf("missing site number")
+	}
+
+	if date := aptEffectiveDate.get(line); date != "" {
+		t, err := time.Parse("01/02/2006", date)
+		if err != nil {
+			return Airport{}, fmt.Errorf("effective date: %w", err)
+		}
+		airport.EffectiveDate = t
+	}
+
+	return airport, nil
+}
+
+func aptRunway(line string) Runway {
+	runway := Runway{
+		ID:       rwyID.get(line),
+		LengthFt: rwyLength.get(line),
+		WidthFt:  rwyWidth.get(line),
+		Surface:  rwySurface.get(line),
+	}
+
+	ends := []RunwayEnd{
+		{Ident: rwyBaseEnd.get(line), TrueHeading: rwyBaseHeading.get(line)},
+		{Ident: rwyReciprocalEnd.get(line), TrueHeading: rwyReciprocalHeading.get(line)},
+	}
+	for _, end := range ends {
+		if end.Ident != "" {
+			runway.Ends = append(runway.Ends, end)
+		}
+	}
+	if len(runway.Ends) == 0 {
+		runway.Ends = endsFromID(runway.ID)
+	}
+	return runway
+}
@@ -0,0 +1,226 @@
+package nasr
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// siteTypes names the SITE_TYPE_CODE values as APT.txt spells them.
+var siteTypes = map[string]string{
+	"A": "AIRPORT",
+	"B": "BALLOONPORT",
+	"C": "SEAPLANE BASE",
+	"G": "GLIDERPORT",
+	"H": "HELIPORT",
+	"U": "ULTRALIGHT",
+}
+
+// ReadCSV reads airports from APT_BASE.csv and attaches the runways in
+// APT_RWY.csv and the runway ends in APT_RWY_END.csv. Either runway reader
+// may be nil. A runway without rows in APT_RWY_END.csv gets its ends from
+// its ID, without headings.
+func ReadCSV(base, runways, runwayEnds io.Reader) ([]Airport, error) {
+	airports := []Airport{}
+	bySite := map[string]int{}
+	err := readCSV(base, "APT_BASE.csv", func(row csvRow) error {
+		airport, err := csvAirport(row)
+		if err != nil {
+			return err
+		}
+		bySite[airport.SiteNumber] = len(airports)
+		airports = append(airports, airport)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if runways == nil {
+		return airports, nil
+	}
+
+	for i := range airports {
+		airports[i].Runways = []Runway{}
+	}
+
+	type strip struct{ airport, runway int }
+	strips := map[string]strip{}
+	err = readCSV(runways, "APT_RWY.csv", func(row csvRow) error {
+		i, ok := bySite[row.get("SITE_NO")]
+		if !ok {
+			return nil
+		}
+		runway := Runway{
+			ID:       row.get("RWY_ID"),
+			LengthFt: row.get("RWY_LEN"),
+			WidthFt:  row.get("RWY_WIDTH"),
+			Surface:  row.get("SURFACE_TYPE_CODE"),
+		}
+		strips[row.get("SITE_NO")+"|"+runway.ID] = strip{i, len(airports[i].Runways)}
+		airports[i].Runways = append(airports[i].Runways, runway)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if runwayEnds != nil {
+		err = readCSV(runwayEnds, "APT_RWY_END.csv", func(row csvRow) error {
+			s, ok := strips[row.get("SITE_NO")+"|"+row.get("RWY_ID")]
+			if !ok {
+				return nil
+			}
+			runway := &airports[s.airport].Runways[s.runway]
+			runway.Ends = append(runway.Ends, RunwayEnd{
+				Ident:       row.get("RWY_END_ID"),
+				TrueHeading: row.get("TRUE_ALIGNMENT"),
+			})
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, s := range strips {
+		if runway := &airports[s.airport].Runways[s.runway]; len(runway.Ends) == 0 {
+			runway.Ends = endsFromID(runway.ID)
+		}
+	}
+	return airports, nil
+}
+
+func csvAirport(row csvRow) (Airport, error) {
+	airport := Airport{
+		SiteNumber:     row.get("SITE_NO"),
+		Type:           siteTypes[row.get("SITE_TYPE_CODE")],
+		FAAID:          row.get("ARPT_ID"),
+		ICAOID:         row.get("ICAO_ID"),
+		Region:         row.get("REGION_CODE"),
+		DistrictOffice: row.get("ADO_CODE"),
+		State:          row.get("STATE_CODE"),
+		StateFull:      row.get("STATE_NAME"),
+		County:         row.get("COUNTY_NAME"),
+		City:           row.get("CITY"),
+		Name:           row.get("ARPT_NAME"),
+		Ownership:      row.get("OWNERSHIP_TYPE_CODE"),
+		Use:            row.get("FACILITY_USE_CODE"),
+		Elevation:      row.get("ELEV"),
+		TPA:            row.get("TPA"),
+		Sectional:      row.get("CHART_NAME"),
+		NotamID:        row.get("NOTAM_ID"),
+		Status:         row.get("ARPT_STATUS"),
+		ControlTower:   "N",
+	}
+	if airport.SiteNumber == "" {
+		return Airport{}, fmt.Errorf("nasr: APT_BASE.csv line %d: missing SITE_NO", row.line)
+	}
+
+	if date := row.get("EFF_DATE"); date != "" {
+		t, err := time.Parse("2006/01/02", date)
+		if err != nil {
+			return Airport{}, fmt.Errorf("nasr: APT_BASE.csv line %d: EFF_DATE: %w", row.line, err)
+		}
+		airport.EffectiveDate = t
+	}
+
+	airport.Latitude, airport.LatitudeSec = coordinate(row, "LAT", 2)
+	airport.Longitude, airport.LongitudeSec = coordinate(row, "LONG", 3)
+
+	if variation := row.get("MAG_VARN"); variation != "" {
+		if n, err := strconv.Atoi(variation); err == nil {
+			airport.MagVariation = fmt.Sprintf("%02d%s", n, row.get("MAG_HEMIS"))
+		}
+	}
+	if strings.HasPrefix(row.get("TWR_TYPE_CODE"), "ATCT") {
+		airport.ControlTower = "Y"
+	}
+
+	return airport, nil
+}
+
+// coordinate formats the degree, minute and second columns of a latitude
+// or longitude the way APT.txt publishes it: 35-26-04.0000N, and in
+// seconds, 127564.0000N.
+func coordinate(row csvRow, prefix string, degreeDigits int) (string, string) {
+	deg, err1 := strconv.ParseFloat(row.get(prefix+"_DEG"), 64)
+	minutes, err2 := strconv.ParseFloat(row.get(prefix+"_MIN"), 64)
+	sec, err3 := strconv.ParseFloat(row.get(prefix+"_SEC"), 64)
+	hemisphere := row.get(prefix + "_HEMIS")
+	if err := errors.Join(err1, err2, err3); err != nil || hemisphere == "" {
+		return "", ""
+	}
+
+	formatted := fmt.Sprintf("%0*d-%02d-%07.4f%s", degreeDigits, int(deg), int(minutes), sec, hemisphere)
+	seconds := fmt.Sprintf("%.4f%s", math.Round((deg*3600+minutes*60+sec)*1e4)/1e4, hemisphere)
+	return formatted, seconds
+}
+
+// endsFromID splits a runway ID like 04L/22R into its ends.
+func endsFromID(id string) []RunwayEnd {
+	ends := []RunwayEnd{}
+	for _, ident := range strings.Split(id, "/") {
+		if ident = strings.TrimSpace(ident); ident != "" {
+			ends = append(ends, RunwayEnd{Ident: ident})
+		}
+	}
+	return ends
+}
+
+type csvRow struct {
+	line    int
+	columns map[string]int
+	record  []string
+}
+
+func (r csvRow) get(column string) string {
+	i, ok := r.columns[column]
+	if !ok || i >= len(r.record) {
+		return ""
+	}
+	return strings.TrimSpace(r.record[i])
+}
+
+// readCSV calls fn for every row after the header of a NASR CSV file.
+func readCSV(r io.Reader, name string, fn func(row csvRow) error) error {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	reader.ReuseRecord = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return fmt.Errorf("nasr: %s: reading header: %w", name, err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, column := range header {
+		columns[strings.TrimPrefix(strings.TrimSpace(column), "\ufeff")] = i
+	}
+
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return fmt.Errorf("nasr: %s: %w", name, err)
+		}
+		if err := fn(csvRow{line: line, columns: columns, record: record}); err != nil {
+			return err
+		}
+	}
+}
+
+// optional turns a missing file into a nil reader.
+func optional(f *os.File) io.Reader {
+	if f == nil {
+		return nil
+	}
+	return f
+}
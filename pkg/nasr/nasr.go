@@ -0,0 +1,122 @@
+// Package nasr reads the airport data of the FAA 28-day NASR subscription,
+// either the APT_BASE, APT_RWY and APT_RWY_END CSV files or the legacy
+// fixed-width APT.txt.
+package nasr
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Airport is one landing facility. Fields hold the values as NASR publishes
+// them, in the formats of the legacy APT.txt. Runways is nil when the files
+// read carry no runway data, and empty for a facility without runways.
+type Airport struct {
+	SiteNumber     string
+	Type           string // AIRPORT, HELIPORT, SEAPLANE BASE, ...
+	FAAID          string
+	ICAOID         string // empty for most small facilities
+	EffectiveDate  time.Time
+	Region         string
+	DistrictOffice string
+	State          string
+	StateFull      string
+	County         string
+	City           string
+	Name           string
+	Ownership      string // PU, PR, MA, MR, MN, CG
+	Use            string // PU, PR
+	Manager        string
+	ManagerPhone   string
+	Latitude       string // 35-26-04.0000N
+	LatitudeSec    string // 127564.0000N
+	Longitude      string // 082-32-33.8240W
+	LongitudeSec   string // 297153.8240W
+	Elevation      string // feet, to the tenth
+	MagVariation   string // 09W
+	TPA            string
+	Sectional      string
+	NotamID        string
+	Status         string // O, CI, CP
+	ControlTower   string // Y, N
+	Unicom         string
+	CTAF           string
+	Runways        []Runway
+}
+
+// Runway is a runway strip and its ends, such as 04L/22R.
+type Runway struct {
+	ID       string
+	LengthFt string
+	WidthFt  string
+	Surface  string
+	Ends     []RunwayEnd
+}
+
+type RunwayEnd struct {
+	Ident       string
+	TrueHeading string
+}
+
+// Load reads the airports at path: a directory holding APT_BASE.csv, with
+// APT_RWY.csv and APT_RWY_END.csv for runways when they are there, a
+// directory holding APT.txt, or an APT.txt file.
+func Load(path string) ([]Airport, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return loadAPT(path)
+	}
+
+	if exists(filepath.Join(path, "APT_BASE.csv")) {
+		return loadCSV(path)
+	}
+	if exists(filepath.Join(path, "APT.txt")) {
+		return loadAPT(filepath.Join(path, "APT.txt"))
+	}
+	return nil, fmt.Errorf("nasr: neither APT_BASE.csv nor APT.txt found in %s", path)
+}
+
+func loadCSV(dir string) ([]Airport, error) {
+	base, err := os.Open(filepath.Join(dir, "APT_BASE.csv"))
+	if err != nil {
+		return nil, err
+	}
+	defer base.Close()
+
+	// Runway files are optional; without them airports come without runways.
+	var files []*os.File
+	for _, name := range []string{"APT_RWY.csv", "APT_RWY_END.csv"} {
+		f, err := os.Open(filepath.Join(dir, name))
+		if errors.Is(err, os.ErrNotExist) {
+			files = append(files, nil)
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		files = append(files, f)
+	}
+
+	return ReadCSV(base, optional(files[0]), optional(files[1]))
+}
+
+func loadAPT(path string) ([]Airport, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return ReadAPT(f)
+}
+
+func exists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
@@ -0,0 +1,142 @@
+package nasr
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad_CSV(t *testing.T) {
+	airports, err := Load("testdata/csv")
+	require.NoError(t, err)
+	require.Len(t, airports, 2)
+
+	jfk := airports[0]
+	assert.Equal(t, "15793.*A", jfk.SiteNumber)
+	assert.Equal(t, "AIRPORT", jfk.Type)
+	assert.Equal(t, "JFK", jfk.FAAID)
+	assert.Equal(t, "KJFK", jfk.ICAOID)
+	assert.Equal(t, time.Date(2025, time.October, 2, 0, 0, 0, 0, time.UTC), jfk.EffectiveDate)
+	assert.Equal(t, "40-38-23.7400N", jfk.Latitude)
+	assert.Equal(t, "146303.7400N", jfk.LatitudeSec)
+	assert.Equal(t, "073-46-43.2900W", jfk.Longitude)
+	assert.Equal(t, "265603.2900W", jfk.LongitudeSec)
+	assert.Equal(t, "13.4", jfk.Elevation)
+	assert.Equal(t, "13W", jfk.MagVariation)
+	assert.Equal(t, "Y", jfk.ControlTower)
+	assert.Equal(t, "O", jfk.Status)
+
+	require.Len(t, jfk.Runways, 2)
+	assert.Equal(t, Runway{
+		ID:       "04L/22R",
+		LengthFt: "12079",
+		WidthFt:  "200",
+		Surface:  "ASPH-CONC",
+		Ends:     []RunwayEnd{{Ident: "04L", TrueHeading: "31"}, {Ident: "22R", TrueHeading: "211"}},
+	}, jfk.Runways[0])
+	// No APT_RWY_END rows, so the ends come from the runway ID
+	assert.Equal(t, []RunwayEnd{{Ident: "13R"}, {Ident: "31L"}}, jfk.Runways[1].Ends)
+
+	heliport := airports[1]
+	assert.Equal(t, "HELIPORT", heliport.Type)
+	assert.Empty(t, heliport.ICAOID)
+	assert.Equal(t, "N", heliport.ControlTower)
+	require.Len(t, heliport.Runways, 1)
+	assert.Equal(t, []RunwayEnd{{Ident: "H1"}}, heliport.Runways[0].Ends)
+}
+
+func TestLoad_CSVWithoutRunways(t *testing.T) {
+	dir := t.TempDir()
+	base, err := os.ReadFile("testdata/csv/APT_BASE.csv")
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "APT_BASE.csv"), base, 0o644))
+
+	airports, err := Load(dir)
+	require.NoError(t, err)
+	require.Len(t, airports, 2)
+	assert.Nil(t, airports[0].Runways)
+}
+
+// record lays values out at their APT.txt positions.
+func record(kind string, values map[field]string) string {
+	line := []byte(strings.Repeat(" ", 1300))
+	copy(line, kind)
+	for f, v := range values {
+		copy(line[f.start-1:f.start-1+f.length], v)
+	}
+	return strings.TrimRight(string(line), " ")
+}
+
+func TestLoad_APT(t *testing.T) {
+	lines := []string{
+		record("APT", map[field]string{
+			aptSiteNumber:    "15793.*A",
+			aptType:          "AIRPORT",
+			aptFAAID:         "JFK",
+			aptEffectiveDate: "10/02/2025",
+			aptState:         "NY",
+			aptStateFull:     "NEW YORK",
+			aptCity:          "NEW YORK",
+			aptName:          "JOHN F KENNEDY INTL",
+			aptOwnership:     "PU",
+			aptUse:           "PU",
+			aptManager:       "CHARLES EVERETT",
+			aptManagerPhone:  "718-244-3501",
+			aptLatitude:      "40-38-23.7400N",
+			aptElevation:     "13.4",
+			aptMagVariation:  "13W",
+			aptStatus:        "O",
+			aptControlTower:  "Y",
+			aptCTAF:          "119.1",
+			aptICAOID:        "KJFK",
+		}),
+		record("ATT", map[field]string{aptSiteNumber: "15793.*A"}),
+		record("RWY", map[field]string{
+			rwySiteNumber:        "15793.*A",
+			rwyID:                "04L/22R",
+			rwyLength:            "12079",
+			rwyWidth:             "200",
+			rwySurface:           "ASPH-CONC-G",
+			rwyBaseEnd:           "04L",
+			rwyBaseHeading:       "031",
+			rwyReciprocalEnd:     "22R",
+			rwyReciprocalHeading: "211",
+		}),
+		// A runway of an unknown facility is dropped
+		record("RWY", map[field]string{rwySiteNumber: "99999.*A", rwyID: "18/36"}),
+	}
+	path := filepath.Join(t.TempDir(), "APT.txt")
+	require.NoError(t, os.WriteFile(path, []byte(strings.Join(lines, "\r\n")+"\r\n"), 0o644))
+
+	airports, err := Load(path)
+	require.NoError(t, err)
+	require.Len(t, airports, 1)
+
+	jfk := airports[0]
+	assert.Equal(t, "15793.*A", jfk.SiteNumber)
+	assert.Equal(t, "KJFK", jfk.ICAOID)
+	assert.Equal(t, "JOHN F KENNEDY INTL", jfk.Name)
+	assert.Equal(t, "CHARLES EVERETT", jfk.Manager)
+	assert.Equal(t, "718-244-3501", jfk.ManagerPhone)
+	assert.Equal(t, time.Date(2025, time.October, 2, 0, 0, 0, 0, time.UTC), jfk.EffectiveDate)
+	assert.Equal(t, "13.4", jfk.Elevation)
+	assert.Equal(t, "Y", jfk.ControlTower)
+	assert.Equal(t, "119.1", jfk.CTAF)
+
+	require.Len(t, jfk.Runways, 1)
+	assert.Equal(t, "ASPH-CONC-G", jfk.Runways[0].Surface)
+	assert.Equal(t, []RunwayEnd{{Ident: "04L", TrueHeading: "031"}, {Ident: "22R", TrueHeading: "211"}}, jfk.Runways[0].Ends)
+}
+
+func TestLoad_MissingFiles(t *testing.T) {
+	_, err := Load(t.TempDir())
+	assert.ErrorContains(t, err, "neither APT_BASE.csv nor APT.txt")
+
+	_, err = Load(filepath.Join(t.TempDir(), "APT.txt"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,200 @@
+// Package ourairports reads the public domain OurAirports data dumps:
+// airports.csv, with runways.csv and countries.csv alongside it.
+package ourairports
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Airport is one row of airports.csv, with the values as published.
+// Coordinates are decimal degrees. Runways is nil when no runways.csv was
+// read, and empty for an airport without runways.
+type Airport struct {
+	ID           string // OurAirports row ID, referenced by runways.csv
+	Ident        string
+	Type         string // large_airport, medium_airport, small_airport, heliport, seaplane_base, balloonport, closed
+	Name         string
+	LatitudeDeg  string
+	LongitudeDeg string
+	ElevationFt  string
+	Country      string // ISO 3166-1 alpha-2 code
+	CountryName  string // from countries.csv; empty without it
+	Region       string // ISO 3166-2 code, such as US-NY
+	Municipality string
+	ICAOCode     string
+	IATACode     string
+	GPSCode      string
+	LocalCode    string // the FAA identifier in the US
+	Runways      []Runway
+}
+
+// Runway is a runway strip and its low and high ends.
+type Runway struct {
+	LengthFt string
+	WidthFt  string
+	Surface  string
+	Closed   bool
+	Ends     []RunwayEnd
+}
+
+type RunwayEnd struct {
+	Ident       string
+	HeadingDegT string // degrees true, with decimals
+}
+
+// Load reads airports.csv in dir, with runways.csv and countries.csv when
+// they are there.
+func Load(dir string) ([]Airport, error) {
+	airports, err := os.Open(filepath.Join(dir, "airports.csv"))
+	if err != nil {
+		return nil, err
+	}
+	defer airports.Close()
+
+	// The other files are optional; without them airports come without
+	// runways or country names.
+	var files []io.Reader
+	for _, name := range []string{"runways.csv", "countries.csv"} {
+		f, err := os.Open(filepath.Join(dir, name))
+		if errors.Is(err, os.ErrNotExist) {
+			files = append(files, nil)
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		files = append(files, f)
+	}
+
+	return ReadCSV(airports, files[0], files[1])
+}
+
+// ReadCSV reads airports from airports.csv, attaches the runways in
+// runways.csv and names their countries from countries.csv. Either of the
+// last two readers may be nil.
+func ReadCSV(airports, runways, countries io.Reader) ([]Airport, error) {
+	names := map[string]string{}
+	if countries != nil {
+		err := readCSV(countries, "countries.csv", func(row csvRow) error {
+			names[row.get("code")] = row.get("name")
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	result := []Airport{}
+	byID := map[string]int{}
+	err := readCSV(airports, "airports.csv", func(row csvRow) error {
+		airport := Airport{
+			ID:           row.get("id"),
+			Ident:        row.get("ident"),
+			Type:         row.get("type"),
+			Name:         row.get("name"),
+			LatitudeDeg:  row.get("latitude_deg"),
+			LongitudeDeg: row.get("longitude_deg"),
+			ElevationFt:  row.get("elevation_ft"),
+			Country:      row.get("iso_country"),
+			Region:       row.get("iso_region"),
+			Municipality: row.get("municipality"),
+			ICAOCode:     row.get("icao_code"),
+			IATACode:     row.get("iata_code"),
+			GPSCode:      row.get("gps_code"),
+			LocalCode:    row.get("local_code"),
+		}
+		if airport.Ident == "" {
+			return fmt.Errorf("ourairports: airports.csv line %d: missing ident", row.line)
+		}
+		airport.CountryName = names[airport.Country]
+
+		byID[airport.ID] = len(result)
+		result = append(result, airport)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if runways == nil {
+		return result, nil
+	}
+
+	for i := range result {
+		result[i].Runways = []Runway{}
+	}
+	err = readCSV(runways, "runways.csv", func(row csvRow) error {
+		i, ok := byID[row.get("airport_ref")]
+		if !ok {
+			return nil
+		}
+		runway := Runway{
+			LengthFt: row.get("length_ft"),
+			WidthFt:  row.get("width_ft"),
+			Surface:  row.get("surface"),
+			Closed:   row.get("closed") == "1",
+			Ends:     []RunwayEnd{},
+		}
+		for _, end := range []string{"le", "he"} {
+			if ident := row.get(end + "_ident"); ident != "" {
+				runway.Ends = append(runway.Ends, RunwayEnd{Ident: ident, HeadingDegT: row.get(end + "_heading_degT")})
+			}
+		}
+		result[i].Runways = append(result[i].Runways, runway)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+type csvRow struct {
+	line    int
+	columns map[string]int
+	record  []string
+}
+
+func (r csvRow) get(column string) string {
+	i, ok := r.columns[column]
+	if !ok || i >= len(r.record) {
+		return ""
+	}
+	return strings.TrimSpace(r.record[i])
+}
+
+// readCSV calls fn for every row after the header of an OurAirports file.
+func readCSV(r io.Reader, name string, fn func(row csvRow) error) error {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	reader.ReuseRecord = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return fmt.Errorf("ourairports: %s: reading header: %w", name, err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, column := range header {
+		columns[strings.TrimPrefix(strings.TrimSpace(column), "\ufeff")] = i
+	}
+
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return fmt.Errorf("ourairports: %s: %w", name, err)
+		}
+		if err := fn(csvRow{line: line, columns: columns, record: record}); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package ourairports
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad(t *testing.T) {
+	airports, err := Load("testdata")
+	require.NoError(t, err)
+	require.Len(t, airports, 3)
+
+	jfk := airports[0]
+	assert.Equal(t, "KJFK", jfk.Ident)
+	assert.Equal(t, "large_airport", jfk.Type)
+	assert.Equal(t, "40.639447", jfk.LatitudeDeg)
+	assert.Equal(t, "-73.779317", jfk.LongitudeDeg)
+	assert.Equal(t, "13", jfk.ElevationFt)
+	assert.Equal(t, "US", jfk.Country)
+	assert.Equal(t, "United States", jfk.CountryName)
+	assert.Equal(t, "US-NY", jfk.Region)
+	assert.Equal(t, "New York", jfk.Municipality)
+	assert.Equal(t, "KJFK", jfk.ICAOCode)
+	assert.Equal(t, "JFK", jfk.IATACode)
+	assert.Equal(t, "JFK", jfk.LocalCode)
+	assert.Equal(t, []Runway{{
+		LengthFt: "12079",
+		WidthFt:  "200",
+		Surface:  "ASP",
+		Ends:     []RunwayEnd{{Ident: "04L", HeadingDegT: "31"}, {Ident: "22R", HeadingDegT: "211"}},
+	}}, jfk.Runways)
+
+	heathrow := airports[1]
+	assert.Equal(t, "United Kingdom", heathrow.CountryName)
+	require.Len(t, heathrow.Runways, 2)
+	assert.Equal(t, "89.6", heathrow.Runways[0].Ends[0].HeadingDegT)
+	assert.True(t, heathrow.Runways[1].Closed)
+	assert.Equal(t, []RunwayEnd{{Ident: "05"}}, heathrow.Runways[1].Ends)
+
+	heliport := airports[2]
+	assert.Equal(t, "heliport", heliport.Type)
+	assert.Empty(t, heliport.ICAOCode)
+	assert.Equal(t, "K00A", heliport.GPSCode)
+}
+
+func TestLoad_AirportsOnly(t *testing.T) {
+	dir := t.TempDir()
+	data, err := os.ReadFile("testdata/airports.csv")
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "airports.csv"), data, 0o644))
+
+	airports, err := Load(dir)
+	require.NoError(t, err)
+	require.Len(t, airports, 3)
+	assert.Nil(t, airports[0].Runways)
+	assert.Empty(t, airports[0].CountryName)
+}
+
+func TestLoad_MissingAirports(t *testing.T) {
+	_, err := Load(t.TempDir())
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
@@ -0,0 +1,64 @@
+package schedule
+
+import (
+	"fmt"
+	"time"
+)
+
+// AIRACCycleLength is the length of an AIRAC cycle. The FAA publishes its
+// NASR airport data on the same 28-day cycle.
+const AIRACCycleLength = 28 * 24 * time.Hour
+
+// airacEpoch is the effective date of cycle 2001, which every other cycle is
+// counted from.
+var airacEpoch = time.Date(2020, time.January, 2, 0, 0, 0, 0, time.UTC)
+
+// AIRACCycle is an AIRAC cycle, identified as YYNN: the two-digit year of
+// its effective date and its number within that year, starting at 01.
+type AIRACCycle struct {
+	Ident     string
+	Effective time.Time
+}
+
+// Expires returns when the next cycle takes over.
+func (c AIRACCycle) Expires() time.Time {
+	return c.Effective.Add(AIRACCycleLength)
+}
+
+// AIRACCycleAt returns the cycle in effect at t.
+func AIRACCycleAt(t time.Time) AIRACCycle {
+	n := int64(t.UTC().Sub(airacEpoch) / AIRACCycleLength)
+	if t.UTC().Before(airacEpoch.Add(time.Duration(n) * AIRACCycleLength)) {
+		n--
+	}
+	return airacCycle(airacEpoch.Add(time.Duration(n) * AIRACCycleLength))
+}
+
+// NextAIRACCycle returns the first cycle that takes effect after t.
+func NextAIRACCycle(t time.Time) AIRACCycle {
+	return airacCycle(AIRACCycleAt(t).Expires())
+}
+
+func airacCycle(effective time.Time) AIRACCycle {
+	// Cycles in the same year are 28 days apart, so the number within the
+	// year follows from the day of the year.
+	number := (effective.YearDay()-1)/28 + 1
+	return AIRACCycle{
+		Ident:     fmt.Sprintf("%02d%02d", effective.Year()%100, number),
+		Effective: effective,
+	}
+}
+
+// airacSchedule fires once per AIRAC cycle, at offset past the effective
+// date.
+type airacSchedule struct {
+	offset time.Duration
+}
+
+func (s airacSchedule) Next(after time.Time) time.Time {
+	next := AIRACCycleAt(after).Effective.Add(s.offset)
+	for !next.After(after) {
+		next = next.Add(AIRACCycleLength)
+	}
+	return next
+}
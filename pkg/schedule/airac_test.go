@@ -0,0 +1,54 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestAIRACCycleAt(t *testing.T) {
+	tests := []struct {
+		at        time.Time
+		ident     string
+		effective time.Time
+	}{
+		{date(2020, time.January, 2), "2001", date(2020, time.January, 2)},
+		{date(2020, time.January, 29), "2001", date(2020, time.January, 2)},
+		{date(2020, time.December, 31), "2014", date(2020, time.December, 31)},
+		{date(2021, time.January, 27), "2014", date(2020, time.December, 31)},
+		{date(2021, time.January, 28), "2101", date(2021, time.January, 28)},
+		{date(2024, time.January, 25), "2401", date(2024, time.January, 25)},
+		{date(2025, time.October, 5), "2510", date(2025, time.October, 2)},
+		// Before the epoch
+		{date(2019, time.December, 31), "1913", date(2019, time.December, 5)},
+	}
+
+	for _, tt := range tests {
+		cycle := AIRACCycleAt(tt.at)
+		assert.Equal(t, tt.ident, cycle.Ident, tt.at.String())
+		assert.Equal(t, tt.effective, cycle.Effective, tt.at.String())
+	}
+}
+
+func TestNextAIRACCycle(t *testing.T) {
+	next := NextAIRACCycle(date(2025, time.October, 5))
+	assert.Equal(t, "2511", next.Ident)
+	assert.Equal(t, date(2025, time.October, 30), next.Effective)
+	assert.Equal(t, date(2025, time.November, 27), next.Expires())
+}
+
+func TestAIRACSchedule_Next(t *testing.T) {
+	s, err := Parse("@airac")
+	assert.NoError(t, err)
+
+	effective := date(2025, time.October, 2)
+	// Before 0901Z on the effective date the cycle's own run is next
+	assert.Equal(t, effective.Add(AIRACOffset), s.Next(effective))
+	assert.Equal(t, date(2025, time.October, 30).Add(AIRACOffset), s.Next(effective.Add(AIRACOffset)))
+	assert.Equal(t, date(2025, time.October, 30).Add(AIRACOffset), s.Next(date(2025, time.October, 20)))
+}
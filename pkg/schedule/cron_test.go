@@ -0,0 +1,64 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse_Next(t *testing.T) {
+	// A Sunday
+	from := time.Date(2025, time.October, 5, 10, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		expr string
+		next time.Time
+	}{
+		{"* * * * *", time.Date(2025, time.October, 5, 10, 31, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2025, time.October, 5, 10, 45, 0, 0, time.UTC)},
+		{"0 3 * * *", time.Date(2025, time.October, 6, 3, 0, 0, 0, time.UTC)},
+		{"30 10 * * *", time.Date(2025, time.October, 6, 10, 30, 0, 0, time.UTC)},
+		{"0 9-17/4 * * *", time.Date(2025, time.October, 5, 13, 0, 0, 0, time.UTC)},
+		{"0 0 * * 1-5", time.Date(2025, time.October, 6, 0, 0, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2025, time.October, 12, 0, 0, 0, 0, time.UTC)},
+		{"0 0 1,15 * *", time.Date(2025, time.October, 15, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2028, time.February, 29, 0, 0, 0, 0, time.UTC)},
+		// Either day field matches when both are restricted
+		{"0 0 20 * 3", time.Date(2025, time.October, 8, 0, 0, 0, 0, time.UTC)},
+		{"@daily", time.Date(2025, time.October, 6, 0, 0, 0, 0, time.UTC)},
+		{"@monthly", time.Date(2025, time.November, 1, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		s, err := Parse(tt.expr)
+		require.NoError(t, err, tt.expr)
+		assert.Equal(t, tt.next, s.Next(from), tt.expr)
+	}
+}
+
+func TestParse_NeverMatches(t *testing.T) {
+	s, err := Parse("0 0 31 2 *")
+	require.NoError(t, err)
+	assert.True(t, s.Next(time.Now()).IsZero())
+}
+
+func TestParse_Invalid(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"5-1 * * * *",
+		"*/0 * * * *",
+		"a * * * *",
+		"@fortnightly",
+	} {
+		_, err := Parse(expr)
+		assert.Error(t, err, expr)
+	}
+}
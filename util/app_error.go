@@ -0,0 +1,152 @@
+package util
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/go-playground/validator"
+)
+
+// ProblemTypeBaseURI is the prefix of every problem `type` URI emitted by the API.
+const ProblemTypeBaseURI = "/problems/"
+
+// problemKind describes how a sentinel error is rendered as a problem.
+type problemKind struct {
+	status int
+	slug   string
+	title  string
+	detail string
+}
+
+var problemKinds = map[error]problemKind{
+	ErrBadRequest:         {http.StatusBadRequest, "bad-request", "Bad Request", "The request could not be understood or was missing required parameters"},
+	ErrUnauthorized:       {http.StatusUnauthorized, "unauthorized", "Unauthorized", "Authentication is required and has failed or has not yet been provided"},
+	ErrPaymentRequired:    {http.StatusPaymentRequired, "payment-required", "Payment Required", "Payment is required to access the requested resource"},
+	ErrForbidden:          {http.StatusForbidden, "forbidden", "Forbidden", "You do not have permission to access the requested resource"},
+	ErrNotFound:           {http.StatusNotFound, "not-found", "Not Found", "The requested resource could not be found"},
+	ErrMethodNotAllowed:   {http.StatusMethodNotAllowed, "method-not-allowed", "Method Not Allowed", "The request method is not supported by the target resource"},
+	ErrConflict:           {http.StatusConflict, "conflict", "Conflict", "The request conflicts with the current state of the resource"},
+	ErrInternalServer:     {http.StatusInternalServerError, "internal-server-error", "Internal Server Error", "An unexpected error occurred on the server"},
+	ErrNotImplemented:     {http.StatusNotImplemented, "not-implemented", "Not Implemented", "The requested functionality is not implemented"},
+	ErrBadGateway:         {http.StatusBadGateway, "bad-gateway", "Bad Gateway", "Received an invalid response from the upstream server"},
+	ErrServiceUnavailable: {http.StatusServiceUnavailable, "service-unavailable", "Service Unavailable", "The server is currently unable to handle the request"},
+	ErrGatewayTimeout:     {http.StatusGatewayTimeout, "gateway-timeout", "Gateway Timeout", "The server did not receive a timely response from the upstream server"},
+}
+
+// problemKindOrder is the order ToAppError tries the kinds in, most specific
+// first, so an error wrapping several kinds always maps to the same one.
+var problemKindOrder = []error{
+	ErrNotFound,
+	ErrConflict,
+	ErrForbidden,
+	ErrUnauthorized,
+	ErrPaymentRequired,
+	ErrMethodNotAllowed,
+	ErrBadRequest,
+	ErrNotImplemented,
+	ErrGatewayTimeout,
+	ErrBadGateway,
+	ErrServiceUnavailable,
+	ErrInternalServer,
+}
+
+// AppError is an application error that knows how to render itself as an
+// RFC 7807 problem. Kind is one of the sentinel errors above and decides the
+// HTTP status; Cause keeps the underlying error for logging and errors.Is.
+type AppError struct {
+	Kind       error
+	Detail     string
+	Cause      error
+	Extensions map[string]interface{}
+}
+
+// NewAppError creates an AppError of the given kind.
+func NewAppError(kind error, detail string, cause error) *AppError {
+	if _, ok := problemKinds[kind]; !ok {
+		kind = ErrInternalServer
+	}
+
+	return &AppError{
+		Kind:   kind,
+		Detail: detail,
+		Cause:  cause,
+	}
+}
+
+// WithExtension attaches an extension member to the rendered problem.
+func (e *AppError) WithExtension(key string, value interface{}) *AppError {
+	if e.Extensions == nil {
+		e.Extensions = make(map[string]interface{})
+	}
+	e.Extensions[key] = value
+	return e
+}
+
+func (e *AppError) Error() string {
+	msg := e.Kind.Error()
+	if e.Detail != "" {
+		msg = fmt.Sprintf("%s: %s", msg, e.Detail)
+	}
+	if e.Cause != nil {
+		msg = fmt.Sprintf("%s: %v", msg, e.Cause)
+	}
+	return msg
+}
+
+// Unwrap exposes both the kind and the cause to errors.Is and errors.As.
+func (e *AppError) Unwrap() []error {
+	if e.Cause == nil {
+		return []error{e.Kind}
+	}
+	return []error{e.Kind, e.Cause}
+}
+
+// Status returns the HTTP status code of the error.
+func (e *AppError) Status() int {
+	return problemKinds[e.Kind].status
+}
+
+// Title returns the short, human-readable summary of the problem type.
+func (e *AppError) Title() string {
+	return problemKinds[e.Kind].title
+}
+
+// Type returns the stable problem type URI.
+func (e *AppError) Type() string {
+	return ProblemTypeBaseURI + problemKinds[e.Kind].slug
+}
+
+// ToAppError converts any error into an AppError. Bare sentinel errors keep
+// their kind, everything else becomes an internal server error.
+func ToAppError(err error) *AppError {
+	var appErr *AppError
+	if errors.As(err, &appErr) {
+		return appErr
+	}
+
+	var validationErrs validator.ValidationErrors
+	if errors.As(err, &validationErrs) {
+		fields := make([]map[string]string, 0, len(validationErrs))
+		for _, fe := range validationErrs {
+			fields = append(fields, map[string]string{"field": fe.Namespace(), "rule": fe.Tag()})
+		}
+		return NewAppError(ErrBadRequest, "Request validation failed", err).WithExtension("invalid_params", fields)
+	}
+
+	for _, kind := range problemKindOrder {
+		p := problemKinds[kind]
+		if err == kind {
+			return &AppError{Kind: kind, Detail: p.detail}
+		}
+		if errors.Is(err, kind) {
+			return &AppError{Kind: kind, Detail: p.detail, Cause: err}
+		}
+	}
+
+	return &AppError{
+		Kind:   ErrInternalServer,
+		Detail: problemKinds[ErrInternalServer].detail,
+		Cause:  err,
+	}
+}
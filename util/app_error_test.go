@@ -0,0 +1,110 @@
+package util_test
+
+import (
+	"encoding/json"
+	"errors"
+	"flight-api/util"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-playground/validator"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewAppError(t *testing.T) {
+	cause := errors.New("pq: connection refused")
+	err := util.NewAppError(util.ErrNotFound, "Airport with ID 1 not found", cause)
+
+	assert.ErrorIs(t, err, util.ErrNotFound)
+	assert.ErrorIs(t, err, cause)
+	assert.Equal(t, http.StatusNotFound, err.Status())
+	assert.Equal(t, "Not Found", err.Title())
+	assert.Equal(t, "/problems/not-found", err.Type())
+	assert.Contains(t, err.Error(), "Airport with ID 1 not found")
+	assert.Contains(t, err.Error(), "connection refused")
+}
+
+func TestNewAppError_UnknownKind(t *testing.T) {
+	err := util.NewAppError(errors.New("weird"), "detail", nil)
+
+	assert.ErrorIs(t, err, util.ErrInternalServer)
+	assert.Equal(t, http.StatusInternalServerError, err.Status())
+}
+
+func TestToAppError(t *testing.T) {
+	type payload struct {
+		Name string `validate:"required"`
+	}
+	validationErr := util.NewValidator().Struct(payload{})
+
+	tests := []struct {
+		name   string
+		input  error
+		status int
+	}{
+		{name: "sentinel", input: util.ErrConflict, status: http.StatusConflict},
+		{name: "wrapped sentinel", input: errors.Join(util.ErrGatewayTimeout, errors.New("dial tcp")), status: http.StatusGatewayTimeout},
+		{name: "several kinds", input: errors.Join(util.ErrInternalServer, util.ErrBadGateway, util.ErrNotFound), status: http.StatusNotFound},
+		{name: "several wrapped kinds", input: fmt.Errorf("%w: %w", util.ErrServiceUnavailable, util.ErrConflict), status: http.StatusConflict},
+		{name: "app error", input: util.NewAppError(util.ErrUnauthorized, "nope", nil), status: http.StatusUnauthorized},
+		{name: "validation error", input: validationErr, status: http.StatusBadRequest},
+		{name: "unknown error", input: errors.New("boom"), status: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.status, util.ToAppError(tt.input).Status())
+		})
+	}
+
+	var ve validator.ValidationErrors
+	assert.True(t, errors.As(validationErr, &ve))
+	assert.NotNil(t, util.ToAppError(validationErr).Extensions["invalid_params"])
+}
+
+func TestErrorHandler_WritesProblemJSON(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/v1/airports/123", nil)
+	rr := httptest.NewRecorder()
+
+	handler := middleware.RequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		util.ErrorHandler(w, r, util.NewAppError(util.ErrNotFound, "Airport with ID 123 not found", nil))
+	}))
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+	assert.Equal(t, "application/problem+json", rr.Header().Get("Content-Type"))
+
+	var got map[string]interface{}
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &got))
+	assert.Equal(t, "/problems/not-found", got["type"])
+	assert.Equal(t, "Not Found", got["title"])
+	assert.Equal(t, float64(http.StatusNotFound), got["status"])
+	assert.Equal(t, "Airport with ID 123 not found", got["detail"])
+	assert.Equal(t, "/v1/airports/123", got["instance"])
+	assert.NotEmpty(t, got["request_id"])
+}
+
+func TestErrorHandler_HidesInternalCause(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/v1/airports", nil)
+	rr := httptest.NewRecorder()
+
+	util.ErrorHandler(rr, req, errors.New("pq: password authentication failed"))
+
+	assert.Equal(t, http.StatusInternalServerError, rr.Code)
+	assert.NotContains(t, rr.Body.String(), "password")
+}
+
+func TestErrorHandler_FlattensExtensions(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/v1/airports", nil)
+	rr := httptest.NewRecorder()
+
+	err := util.NewAppError(util.ErrBadRequest, "Malformed JSON body", nil).WithExtension("line", 3)
+	util.ErrorHandler(rr, req, err)
+
+	var got map[string]interface{}
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &got))
+	assert.Equal(t, float64(3), got["line"])
+}
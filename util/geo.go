@@ -0,0 +1,77 @@
+package util
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// earthRadiusKm is the mean Earth radius used for great-circle distances.
+const earthRadiusKm = 6371.0088
+
+var ErrInvalidCoordinate = errors.New("invalid coordinate")
+
+// ParseCoordinate converts an airport coordinate to signed decimal degrees.
+// It accepts the FAA forms "40-38-23.7400N" (degrees-minutes-seconds) and
+// "146303.7400N" (total arc seconds), as well as plain decimal degrees such
+// as "-6.1256". S and W hemispheres are negative.
+func ParseCoordinate(value string) (float64, error) {
+	value = strings.ToUpper(strings.TrimSpace(value))
+	if value == "" {
+		return 0, ErrInvalidCoordinate
+	}
+
+	sign := 1.0
+	hemisphere := value[len(value)-1]
+	switch hemisphere {
+	case 'N', 'E':
+		value = value[:len(value)-1]
+	case 'S', 'W':
+		sign = -1
+		value = value[:len(value)-1]
+	default:
+		hemisphere = 0
+	}
+
+	var degrees float64
+	if parts := strings.Split(value, "-"); len(parts) == 3 && parts[0] != "" {
+		d, errD := strconv.ParseFloat(parts[0], 64)
+		m, errM := strconv.ParseFloat(parts[1], 64)
+		s, errS := strconv.ParseFloat(parts[2], 64)
+		if errD != nil || errM != nil || errS != nil || m >= 60 || s >= 60 {
+			return 0, ErrInvalidCoordinate
+		}
+		degrees = d + m/60 + s/3600
+	} else {
+		v, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return 0, ErrInvalidCoordinate
+		}
+		degrees = v
+		// A hemisphere suffix on a bare number means total arc seconds.
+		if hemisphere != 0 {
+			degrees = v / 3600
+		}
+	}
+
+	degrees *= sign
+	if math.IsNaN(degrees) || degrees < -180 || degrees > 180 {
+		return 0, ErrInvalidCoordinate
+	}
+
+	return degrees, nil
+}
+
+// DistanceKm returns the great-circle distance between two points given in
+// decimal degrees, using the haversine formula.
+func DistanceKm(lat1, lon1, lat2, lon2 float64) float64 {
+	toRad := func(deg float64) float64 { return deg * math.Pi / 180 }
+
+	dLat := toRad(lat2 - lat1)
+	dLon := toRad(lon2 - lon1)
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(toRad(lat1))*math.Cos(toRad(lat2))*math.Sin(dLon/2)*math.Sin(dLon/2)
+
+	return 2 * earthRadiusKm * math.Asin(math.Min(1, math.Sqrt(a)))
+}
@@ -0,0 +1,45 @@
+package util_test
+
+import (
+	"flight-api/util"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCoordinate(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected float64
+	}{
+		{"40-38-23.7400N", 40.639928},
+		{"073-46-43.2930W", -73.778693},
+		{"146303.7400N", 40.639928},
+		{"265603.2930W", -73.778693},
+		{"06-07-32.0000S", -6.125556},
+		{"-6.1256", -6.1256},
+		{" 106.6559 ", 106.6559},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := util.ParseCoordinate(tt.input)
+			require.NoError(t, err)
+			assert.InDelta(t, tt.expected, got, 1e-6)
+		})
+	}
+}
+
+func TestParseCoordinate_Invalid(t *testing.T) {
+	for _, input := range []string{"", "N", "abc", "40-75-00N", "40-38N", "900.5"} {
+		_, err := util.ParseCoordinate(input)
+		assert.ErrorIs(t, err, util.ErrInvalidCoordinate, input)
+	}
+}
+
+func TestDistanceKm(t *testing.T) {
+	// KJFK to KLGA
+	assert.InDelta(t, 17.2, util.DistanceKm(40.6399, -73.7787, 40.7772, -73.8726), 0.1)
+	assert.Equal(t, 0.0, util.DistanceKm(1, 2, 1, 2))
+}
@@ -1,7 +1,11 @@
 package util
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
 )
 
@@ -15,10 +19,66 @@ func ToJSON(data interface{}) ([]byte, error) {
 	return bytes, err
 }
 
-func ReadFromRequestBody(request *http.Request, result interface{}) {
-	decoder := json.NewDecoder(request.Body)
-	err := decoder.Decode(result)
-	PanicIfError(err)
+// ReadFromRequestBody decodes the JSON request body into result. Malformed
+// bodies are reported as a bad request carrying the parse position.
+func ReadFromRequestBody(request *http.Request, result interface{}) error {
+	body, err := io.ReadAll(request.Body)
+	if err != nil {
+		return NewAppError(ErrBadRequest, "Failed to read request body", err)
+	}
+
+	if len(bytes.TrimSpace(body)) == 0 {
+		return NewAppError(ErrBadRequest, "Request body must not be empty", nil)
+	}
+
+	err = json.Unmarshal(body, result)
+	if err == nil {
+		return nil
+	}
+
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+
+	switch {
+	case errors.As(err, &syntaxErr):
+		return malformedJSONError(body, syntaxErr.Offset, err)
+	case errors.As(err, &typeErr):
+		appErr := malformedJSONError(body, typeErr.Offset, err)
+		appErr.Detail = fmt.Sprintf("Field '%s' must be of type %s (line %d, column %d)",
+			typeErr.Field, typeErr.Type.String(), appErr.Extensions["line"], appErr.Extensions["column"])
+		return appErr.WithExtension("field", typeErr.Field)
+	default:
+		return NewAppError(ErrBadRequest, "Malformed JSON body: "+err.Error(), err)
+	}
+}
+
+// malformedJSONError builds a bad request error pointing at offset in body.
+func malformedJSONError(body []byte, offset int64, cause error) *AppError {
+	if offset > int64(len(body)) {
+		offset = int64(len(body))
+	}
+
+	// The decoder reports the offset just past the offending byte
+	pos := offset
+	if pos > 0 {
+		pos--
+	}
+
+	line, column := 1, 1
+	for _, b := range body[:pos] {
+		if b == '\n' {
+			line++
+			column = 1
+			continue
+		}
+		column++
+	}
+
+	detail := fmt.Sprintf("Malformed JSON body at line %d, column %d: %v", line, column, cause)
+	return NewAppError(ErrBadRequest, detail, cause).
+		WithExtension("offset", offset).
+		WithExtension("line", line).
+		WithExtension("column", column)
 }
 
 func WriteToResponseBody(writer http.ResponseWriter, status int, response any) {
@@ -185,25 +185,51 @@ func TestReadFromRequestBody(t *testing.T) {
 		req := httptest.NewRequest(http.MethodPost, "/x", bytes.NewReader(body))
 
 		var got payload
-		util.ReadFromRequestBody(req, &got)
+		err := util.ReadFromRequestBody(req, &got)
 
+		assert.NoError(t, err)
 		if got.Name != "John" || got.Age != 42 {
 			t.Fatalf("unexpected decode result: %+v", got)
 		}
 	})
 
-	t.Run("panics on invalid JSON", func(t *testing.T) {
-		body := []byte(`{"name":"John","age":}`)
+	t.Run("bad request with position on invalid JSON", func(t *testing.T) {
+		body := []byte("{\n  \"name\":\"John\",\n  \"age\":}")
 		req := httptest.NewRequest(http.MethodPost, "/x", bytes.NewReader(body))
 
-		defer func() {
-			if r := recover(); r == nil {
-				t.Fatalf("expected panic, got none")
-			}
-		}()
+		var got payload
+		err := util.ReadFromRequestBody(req, &got)
+
+		assert.ErrorIs(t, err, util.ErrBadRequest)
+
+		var appErr *util.AppError
+		assert.True(t, errors.As(err, &appErr))
+		assert.Equal(t, 3, appErr.Extensions["line"])
+		assert.Equal(t, 9, appErr.Extensions["column"])
+		assert.Contains(t, appErr.Detail, "line 3, column 9")
+	})
+
+	t.Run("bad request on wrong field type", func(t *testing.T) {
+		body := []byte(`{"name":"John","age":"old"}`)
+		req := httptest.NewRequest(http.MethodPost, "/x", bytes.NewReader(body))
 
 		var got payload
-		util.ReadFromRequestBody(req, &got)
+		err := util.ReadFromRequestBody(req, &got)
+
+		assert.ErrorIs(t, err, util.ErrBadRequest)
+
+		var appErr *util.AppError
+		assert.True(t, errors.As(err, &appErr))
+		assert.Equal(t, "age", appErr.Extensions["field"])
+	})
+
+	t.Run("bad request on empty body", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/x", bytes.NewReader(nil))
+
+		var got payload
+		err := util.ReadFromRequestBody(req, &got)
+
+		assert.ErrorIs(t, err, util.ErrBadRequest)
 	})
 }
 
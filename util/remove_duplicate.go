@@ -1,14 +1,15 @@
 package util
 
-// RemoveDuplicate removes duplicate values from a slice.
+// RemoveDuplicate removes duplicate values from a slice, keeping the first
+// occurrence of each in order.
 func RemoveDuplicate[T comparable](input []T) []T {
-	uniqueMap := make(map[T]struct{})
+	seen := make(map[T]struct{}, len(input))
+	uniqueSlice := make([]T, 0, len(input))
 	for _, item := range input {
-		uniqueMap[item] = struct{}{}
-	}
-
-	uniqueSlice := make([]T, 0, len(uniqueMap))
-	for item := range uniqueMap {
+		if _, ok := seen[item]; ok {
+			continue
+		}
+		seen[item] = struct{}{}
 		uniqueSlice = append(uniqueSlice, item)
 	}
 
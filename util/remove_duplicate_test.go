@@ -41,7 +41,7 @@ func TestRemoveDuplicate(t *testing.T) {
 			if len(result) != len(tt.expected) {
 				assert.Fail(t, "Length mismatch", "Expected length %d, got %d", len(tt.expected), len(result))
 			}
-			assert.ElementsMatch(t, tt.expected, result, "Expected %v, got %v", tt.expected, result)
+			assert.Equal(t, tt.expected, result, "Expected %v, got %v", tt.expected, result)
 
 			// uniqueness check
 			uniqueMap := make(map[interface{}]struct{})
@@ -13,3 +13,36 @@ func CommitOrRollback(tx *sql.Tx) {
 		PanicIfError(errorCommit)
 	}
 }
+
+// CommitOrRollbackErr finishes tx based on the error returned by the caller.
+// It is meant to be deferred with a pointer to a named error result: the
+// transaction is rolled back when *errp is set or a panic is in flight, and
+// committed otherwise. A failed commit is reported through *errp.
+func CommitOrRollbackErr(tx *sql.Tx, errp *error) {
+	if p := recover(); p != nil {
+		_ = tx.Rollback()
+		panic(p)
+	}
+
+	if *errp != nil {
+		if err := tx.Rollback(); err != nil && err != sql.ErrTxDone {
+			LogPanicError(err)
+		}
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		*errp = NewAppError(ErrInternalServer, "Failed to commit transaction", err)
+	}
+}
+
+// MockTx stands in for a *sql.Tx in mock call arguments. testify formats
+// every argument while matching a call, and reading a live *sql.Tx that way
+// races with the goroutine database/sql runs to watch its context.
+type MockTx struct {
+	Tx *sql.Tx
+}
+
+func (t MockTx) String() string {
+	return "*sql.Tx"
+}
@@ -0,0 +1,84 @@
+package util
+
+import (
+	"errors"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var ErrInvalidMagneticVariation = errors.New("invalid magnetic variation")
+
+// runwayDesignatorRe matches a runway end such as "4", "04L" or "36C".
+var runwayDesignatorRe = regexp.MustCompile(`^(0?[1-9]|[12][0-9]|3[0-6])[LCR]?$`)
+
+// ParseMagneticVariation converts a variation such as "13W", "05E" or
+// "-4.5" to signed degrees, east positive. FAA data may append the epoch
+// year ("13W 1985"), which is ignored.
+func ParseMagneticVariation(value string) (float64, error) {
+	fields := strings.Fields(strings.ToUpper(value))
+	if len(fields) == 0 {
+		return 0, ErrInvalidMagneticVariation
+	}
+	value = fields[0]
+
+	sign := 1.0
+	switch value[len(value)-1] {
+	case 'E':
+		value = value[:len(value)-1]
+	case 'W':
+		sign = -1
+		value = value[:len(value)-1]
+	}
+
+	degrees, err := strconv.ParseFloat(value, 64)
+	if err != nil || math.IsNaN(degrees) || math.Abs(degrees) > 180 {
+		return 0, ErrInvalidMagneticVariation
+	}
+
+	return sign * degrees, nil
+}
+
+// MagneticToTrue converts a magnetic heading to true using a variation in
+// signed degrees, east positive. The result is in (0, 360].
+func MagneticToTrue(magnetic, variation float64) float64 {
+	return NormalizeHeading(magnetic + variation)
+}
+
+// TrueToMagnetic is the inverse of MagneticToTrue.
+func TrueToMagnetic(trueHeading, variation float64) float64 {
+	return NormalizeHeading(trueHeading - variation)
+}
+
+// NormalizeHeading wraps degrees into (0, 360], so north is 360 as on a
+// runway designator.
+func NormalizeHeading(degrees float64) float64 {
+	degrees = math.Mod(degrees, 360)
+	if degrees <= 0 {
+		degrees += 360
+	}
+	return degrees
+}
+
+// RunwayDesignatorHeading returns the nominal magnetic heading of a runway
+// end, ten times its number: "04L" is 040 and "36" is 360.
+func RunwayDesignatorHeading(ident string) (int, bool) {
+	ident = strings.ToUpper(strings.TrimSpace(ident))
+	match := runwayDesignatorRe.FindStringSubmatch(ident)
+	if match == nil {
+		return 0, false
+	}
+	number, _ := strconv.Atoi(match[1])
+	return number * 10, true
+}
+
+// WindComponents splits a wind blowing from windFrom (degrees) at speed
+// into components along and across a runway heading, both in degrees
+// relative to the same north. A positive headwind blows down the runway
+// towards the aircraft, a negative one is a tailwind. A positive crosswind
+// comes from the right of the runway, a negative one from the left.
+func WindComponents(windFrom, speed, runwayHeading float64) (headwind, crosswind float64) {
+	angle := (windFrom - runwayHeading) * math.Pi / 180
+	return speed * math.Cos(angle), speed * math.Sin(angle)
+}
@@ -0,0 +1,83 @@
+package util_test
+
+import (
+	"flight-api/util"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMagneticVariation(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected float64
+	}{
+		{"13W", -13},
+		{"05E", 5},
+		{"13w 1985", -13},
+		{"-4.5", -4.5},
+		{" 0 ", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := util.ParseMagneticVariation(tt.input)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, got)
+		})
+	}
+}
+
+func TestParseMagneticVariation_Invalid(t *testing.T) {
+	for _, input := range []string{"", "W", "abc", "200E"} {
+		_, err := util.ParseMagneticVariation(input)
+		assert.ErrorIs(t, err, util.ErrInvalidMagneticVariation, input)
+	}
+}
+
+func TestMagneticTrueConversion(t *testing.T) {
+	assert.Equal(t, 27.0, util.MagneticToTrue(40, -13))
+	assert.Equal(t, 360.0, util.MagneticToTrue(355, 5))
+	assert.Equal(t, 5.0, util.MagneticToTrue(360, 5))
+	assert.Equal(t, 40.0, util.TrueToMagnetic(27, -13))
+	assert.Equal(t, 350.0, util.TrueToMagnetic(3, 13))
+}
+
+func TestRunwayDesignatorHeading(t *testing.T) {
+	for ident, expected := range map[string]int{"04L": 40, "4": 40, "22R": 220, "36": 360, " 09c ": 90} {
+		got, ok := util.RunwayDesignatorHeading(ident)
+		assert.True(t, ok, ident)
+		assert.Equal(t, expected, got, ident)
+	}
+
+	for _, ident := range []string{"", "00", "37", "H1", "04X", "040"} {
+		_, ok := util.RunwayDesignatorHeading(ident)
+		assert.False(t, ok, ident)
+	}
+}
+
+func TestWindComponents(t *testing.T) {
+	tests := []struct {
+		name                string
+		windFrom, speed     float64
+		runway              float64
+		headwind, crosswind float64
+	}{
+		{"straight down the runway", 220, 10, 220, 10, 0},
+		{"tailwind", 40, 10, 220, -10, 0},
+		{"from the right", 310, 10, 220, 0, 10},
+		{"from the left", 130, 10, 220, 0, -10},
+		{"thirty degrees off", 250, 20, 220, 17.32, 10},
+		{"across north", 10, 20, 340, 17.32, 10},
+		{"calm", 0, 0, 90, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			headwind, crosswind := util.WindComponents(tt.windFrom, tt.speed, tt.runway)
+			assert.InDelta(t, tt.headwind, headwind, 0.01)
+			assert.InDelta(t, tt.crosswind, crosswind, 0.01)
+		})
+	}
+}